@@ -0,0 +1,412 @@
+// Command fissionctl is an operator tool for a running relayer. It talks to
+// the admin API (ADMIN_API_KEY) and the WS stream (RELAYER_API_KEY).
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"relayer/pkg/client"
+)
+
+const usage = `usage: fissionctl [flags] <command> [args]
+
+commands:
+  orders                                       list live orders
+  order <orderHash>                            inspect an order, live or archived
+  search [filter=value...]                     search the stored orders by status, srcChain,
+                                               dstChain, token, maker, from, to, minAmount,
+                                               maxAmount, sort, order, cursor or limit
+  quote <quoteId>                              dump a cached quote
+  reverify <orderHash> <srcTx> <dstTx>         verify a fill again, bypassing the cache
+  release <orderHash> <idx> <srcTx> <dstTx>    mark a fill ready for its secret without verification
+  quarantine                                   list orders held back for their price
+  approve <orderHash>                          broadcast a quarantined order
+  reject <orderHash>                           drop a quarantined order
+  tail [orderHash...]                          print WS events until interrupted, only
+                                               those of the given orders if any
+  chains                                       check chain RPC connectivity
+  resolvers                                    rank resolvers by connection uptime and ping RTT
+  deposits                                     show safety deposits quoted per chain
+  keys                                         show requests and throttling per 1inch API key
+  config                                       show the runtime config
+  reload                                       reload the runtime config file
+
+flags:
+`
+
+func main() {
+	apiURL := flag.String("api", envOr("FISSION_API_URL", "http://localhost:8080"), "relayer API base URL")
+	wsURL := flag.String("ws", envOr("FISSION_WS_URL", "ws://localhost:8081/"), "relayer WS URL")
+	adminKey := flag.String("admin-key", os.Getenv("ADMIN_API_KEY"), "admin API key")
+	resolverKey := flag.String("resolver-key", os.Getenv("RELAYER_API_KEY"), "resolver API key for tail")
+	timeout := flag.Duration("timeout", time.Minute, "request timeout")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	api := client.New(*apiURL, nil)
+	api.AdminKey = *adminKey
+
+	cmd, args := flag.Arg(0), flag.Args()[1:]
+	var err error
+	if cmd == "tail" {
+		err = tail(ctx, *wsURL, *resolverKey, args)
+	} else {
+		reqCtx, cancel := context.WithTimeout(ctx, *timeout)
+		err = run(reqCtx, api, cmd, args)
+		cancel()
+	}
+
+	if errors.Is(err, errUsage) {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err != nil && !errors.Is(err, context.Canceled) {
+		fmt.Fprintf(os.Stderr, "fissionctl: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+var errUsage = errors.New("usage")
+
+func run(ctx context.Context, api *client.Client, cmd string, args []string) error {
+	switch cmd {
+	case "orders":
+		if len(args) != 0 {
+			return errUsage
+		}
+		orders, err := api.ListOrders(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "ORDER HASH\tSRC CHAIN\tTYPE\tSTATUS\tFILLS\tFILLED\tSUBMITTED")
+		for _, o := range orders {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\t%s\n", o.OrderHash, o.SrcChainID, o.OrderType, o.Status,
+				len(o.Fills), o.FilledMakingAmount, o.SubmittedAt.Format(time.RFC3339))
+		}
+		return w.Flush()
+
+	case "order":
+		if len(args) != 1 {
+			return errUsage
+		}
+		order, err := api.InspectOrder(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		return printJSON(order)
+
+	case "search":
+		search, err := parseSearch(args)
+		if err != nil {
+			return err
+		}
+		result, err := api.SearchOrders(ctx, search)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "ORDER HASH\tSTATUS\tSRC CHAIN\tDST CHAIN\tMAKER\tMAKING\tTAKING\tSUBMITTED")
+		for _, o := range result.Orders {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", o.OrderHash, o.Status, o.SrcChainID, o.DstChainID,
+				o.Maker, o.MakingAmount, o.TakingAmount, o.SubmittedAt.Format(time.RFC3339))
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		if result.NextCursor != "" {
+			fmt.Printf("more: cursor=%s\n", result.NextCursor)
+		}
+		return nil
+
+	case "quote":
+		if len(args) != 1 {
+			return errUsage
+		}
+		quote, err := api.InspectQuote(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		return printJSON(quote)
+
+	case "reverify":
+		if len(args) != 3 {
+			return errUsage
+		}
+		if err := api.Reverify(ctx, args[0], args[1], args[2]); err != nil {
+			return err
+		}
+		fmt.Println("fill verified")
+		return nil
+
+	case "release":
+		if len(args) != 4 {
+			return errUsage
+		}
+		idx, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid secret index %q", args[1])
+		}
+		fill := client.ReadyToAcceptSecretFill{Idx: idx, SrcEscrowDeployTxHash: args[2], DstEscrowDeployTxHash: args[3]}
+		if err := api.ReleaseFill(ctx, args[0], fill); err != nil {
+			return err
+		}
+		fmt.Println("fill released")
+		return nil
+
+	case "quarantine":
+		if len(args) != 0 {
+			return errUsage
+		}
+		orders, err := api.Quarantined(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "ORDER HASH\tSRC CHAIN\tDST CHAIN\tMAKING\tTAKING\tDEVIATION\tQUARANTINED")
+		for _, o := range orders {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%d bps\t%s\n", o.OrderHash, o.SrcChainID, o.DstChainID,
+				o.MakingAmount, o.TakingAmount, o.DeviationBps, o.QuarantinedAt.Format(time.RFC3339))
+		}
+		return w.Flush()
+
+	case "approve", "reject":
+		if len(args) != 1 {
+			return errUsage
+		}
+		if cmd == "approve" {
+			if err := api.ApproveQuarantined(ctx, args[0]); err != nil {
+				return err
+			}
+			fmt.Println("order broadcast")
+			return nil
+		}
+		if err := api.RejectQuarantined(ctx, args[0]); err != nil {
+			return err
+		}
+		fmt.Println("order dropped")
+		return nil
+
+	case "chains":
+		if len(args) != 0 {
+			return errUsage
+		}
+		health, err := api.ChainHealth(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "CHAIN\tOK\tHEAD\tLAG\tBEHIND\tLATENCY\tERROR")
+		failed := false
+		for _, h := range health {
+			fmt.Fprintf(w, "%s\t%t\t%d\t%s\t%t\t%s\t%s\n", h.Chain, h.OK, h.Head, h.Lag, h.Behind, h.Latency, h.Error)
+			failed = failed || !h.OK
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		if failed {
+			return errors.New("some chain endpoints are unreachable")
+		}
+		return nil
+
+	case "resolvers":
+		if len(args) != 0 {
+			return errUsage
+		}
+		stats, err := api.ResolverStats(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "RESOLVER\tCONNECTED\tUPTIME\tPING RTT\tPINGS\tFAILED\tSCORE")
+		for _, r := range stats {
+			fmt.Fprintf(w, "%s\t%t\t%.1f%%\t%s\t%d\t%d\t%.3f\n", r.ID, r.Connected, r.Uptime*100, r.PingRTT, r.Pings, r.PingFailures, r.Score)
+		}
+		return w.Flush()
+
+	case "deposits":
+		if len(args) != 0 {
+			return errUsage
+		}
+		deposits, err := api.SafetyDeposits(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "CHAIN\tGAS UNITS\tMULTIPLIER\tFLOOR\tGAS PRICE\tDEPOSIT\tERROR")
+		for _, d := range deposits {
+			fmt.Fprintf(w, "%s\t%d\t%d bps\t%s\t%s\t%s\t%s\n", d.Chain, d.GasUnits, d.MultiplierBps, d.Floor, d.GasPrice, d.Deposit, d.Error)
+		}
+		return w.Flush()
+
+	case "keys":
+		if len(args) != 0 {
+			return errUsage
+		}
+		keys, err := api.UpstreamKeys(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "KEY\tREQUESTS\tTHROTTLED\tCOOLING UNTIL")
+		for _, k := range keys {
+			until := ""
+			if k.CoolingUntil != nil {
+				until = k.CoolingUntil.Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "%s\t%d\t%d\t%s\n", k.Key, k.Requests, k.Throttled, until)
+		}
+		return w.Flush()
+
+	case "config", "reload":
+		if len(args) != 0 {
+			return errUsage
+		}
+		get := api.GetConfig
+		if cmd == "reload" {
+			get = api.ReloadConfig
+		}
+		cfg, err := get(ctx)
+		if err != nil {
+			return err
+		}
+		return printJSON(cfg)
+
+	default:
+		return errUsage
+	}
+}
+
+// tail prints every WS event, including retained ones, until ctx is done.
+// With orders it follows their rooms, which adds the maker-facing events.
+func tail(ctx context.Context, wsURL, apiKey string, orders []string) error {
+	stream := client.NewStream(wsURL, client.Handlers{
+		OnOrder: func(o *client.Order) {
+			printEvent("BROADC", o)
+		},
+		OnSecret: func(orderHash, secret string) {
+			printEvent("SECRET", map[string]string{"orderHash": orderHash, "secret": secret})
+		},
+		OnExpired: func(orderHash string) {
+			printEvent("EXPIRED", map[string]string{"orderHash": orderHash})
+		},
+		OnEscrowsVerified: func(orderHash string, hashIdx int, srcEscrow, dstEscrow string) {
+			printEvent("ESCROWS_VERIFIED", map[string]any{"orderHash": orderHash, "hashIdx": hashIdx, "srcEscrow": srcEscrow, "dstEscrow": dstEscrow})
+		},
+		OnFinalityWait: func(orderHash string, hashIdx int, until time.Time) {
+			printEvent("FINALITY_WAIT", map[string]any{"orderHash": orderHash, "hashIdx": hashIdx, "until": until})
+		},
+		OnSecretReleased: func(orderHash string) {
+			printEvent("SECRET_RELEASED", map[string]string{"orderHash": orderHash})
+		},
+		OnWithdrawn: func(orderHash, side, txHash string) {
+			printEvent("WITHDRAWN", map[string]string{"orderHash": orderHash, "side": side, "txHash": txHash})
+		},
+		OnStatus: func(orderHash, status string) {
+			printEvent("STATUS", map[string]string{"orderHash": orderHash, "status": status})
+		},
+		OnRejected: func(reason string) {
+			printEvent("ERROR", reason)
+		},
+		OnUnknown: func(raw string) {
+			printEvent("RAW", raw)
+		},
+		OnError: func(err error) {
+			fmt.Fprintf(os.Stderr, "fissionctl: %v\n", err)
+		},
+	})
+	stream.APIKey = apiKey
+	stream.Orders = orders
+
+	return stream.Subscribe(ctx)
+}
+
+func printEvent(kind string, payload any) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		data = []byte(fmt.Sprint(payload))
+	}
+	fmt.Printf("%s %s %s\n", time.Now().Format(time.RFC3339), kind, data)
+}
+
+// parseSearch reads the filter=value arguments of search.
+func parseSearch(args []string) (client.OrderSearch, error) {
+	var search client.OrderSearch
+	for _, arg := range args {
+		key, value, ok := strings.Cut(arg, "=")
+		if !ok {
+			return search, errUsage
+		}
+		var err error
+		switch key {
+		case "status":
+			search.Statuses = strings.Split(value, ",")
+		case "srcChain":
+			search.SrcChain = value
+		case "dstChain":
+			search.DstChain = value
+		case "token":
+			search.Token = value
+		case "maker":
+			search.Maker = value
+		case "from":
+			search.From, err = time.Parse(time.RFC3339, value)
+		case "to":
+			search.To, err = time.Parse(time.RFC3339, value)
+		case "minAmount":
+			search.MinAmount = value
+		case "maxAmount":
+			search.MaxAmount = value
+		case "sort":
+			search.Sort = value
+		case "order":
+			search.Ascending = value == "asc"
+		case "cursor":
+			search.Cursor = value
+		case "limit":
+			search.Limit, err = strconv.Atoi(value)
+		default:
+			return search, fmt.Errorf("unknown search filter %q", key)
+		}
+		if err != nil {
+			return search, fmt.Errorf("invalid %s: %w", key, err)
+		}
+	}
+	return search, nil
+}
+
+func printJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
@@ -2,13 +2,18 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
 	"relayer/internal/api"
+	"relayer/internal/chain/mock"
+	"relayer/internal/loadgen"
+	"relayer/internal/logging"
 	"relayer/internal/manager"
+	"relayer/internal/network"
 	"relayer/internal/ws"
 	"syscall"
 	"time"
@@ -45,12 +50,59 @@ func initServer(server *http.Server, done chan bool, logger *log.Logger) {
 	done <- true
 }
 
+// reloadOnHangup reloads the runtime config every time the process gets SIGHUP.
+func reloadOnHangup(manager *manager.Manager, logger *log.Logger) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	for range hup {
+		if _, err := manager.ReloadConfig(); err != nil {
+			logger.Printf("Config reload failed, keeping previous config: %v", err)
+		}
+	}
+}
+
+// newManager connects the manager to the configured chains or, in load-test
+// mode, to mock chains fed by a synthetic order generator.
+func newManager(logger *log.Logger, loadRate float64, fillDelay time.Duration) *manager.Manager {
+	if loadRate <= 0 {
+		return manager.NewManager(logger)
+	}
+
+	evm := mock.NewEVMClient()
+	m := manager.NewManagerWithClients(logger, evm, mock.NewSuiClient())
+
+	gen := loadgen.New(m, evm, loadgen.Config{Rate: loadRate, FillDelay: fillDelay}, logger)
+	go func() {
+		if err := gen.Run(context.Background()); err != nil {
+			logger.Printf("Load test failed: %v", err)
+		}
+	}()
+	return m
+}
+
 func main() {
-	// Initialize logger
-	logger := log.New(os.Stdout, "relayer: ", log.LstdFlags)
+	// Initialize logger, level is controlled by LOG_LEVEL (debug, info, warn, error)
+	logger := logging.New()
+
+	// Select the deployment target, NETWORK_PROFILE or -network (mainnet, testnet, devnet)
+	profile := os.Getenv("NETWORK_PROFILE")
+	if profile == "" {
+		profile = "mainnet"
+	}
+	flag.StringVar(&profile, "network", profile, "network profile: mainnet, testnet or devnet")
+	// Load-test mode runs on mock chains and generates its own traffic
+	loadRate := flag.Float64("loadtest", 0, "run on mock chains with a synthetic order generator submitting this many orders per second")
+	fillDelay := flag.Duration("loadtest-fill-delay", loadgen.DefaultFillDelay, "time between a synthetic order's broadcast and its fill")
+	flag.Parse()
+	if err := network.Select(profile); err != nil {
+		logger.Fatal(err)
+	}
+	logger.Printf("Using %s network profile", profile)
 
 	// Initialize the manager
-	manager := manager.NewManager(logger)
+	manager := newManager(logger, *loadRate, *fillDelay)
+	go reloadOnHangup(manager, logger)
 
 	// create the servers
 	apiServer := api.NewAPIServer(manager, logger)
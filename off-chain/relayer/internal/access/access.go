@@ -0,0 +1,187 @@
+// Package access authenticates callers of the admin and analytics APIs and
+// assigns them a role. Callers present an API key from the access file, the
+// legacy ADMIN_API_KEY, or an HS256 JWT whose claims carry the role.
+package access
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"relayer/internal/analytics"
+	"strings"
+	"time"
+)
+
+// Role is what a caller may do.
+type Role string
+
+const (
+	// RoleAdmin may do everything, including releasing unverified fills and
+	// reloading the config
+	RoleAdmin Role = "admin"
+	// RoleOperator may inspect relayer state and re-verify fills
+	RoleOperator Role = "operator"
+	// RoleAnalytics may read the analytics endpoints
+	RoleAnalytics Role = "analytics"
+	// RoleIntegrator may read its own integrator fees
+	RoleIntegrator Role = "integrator"
+)
+
+var (
+	ErrUnauthenticated = errors.New("unauthenticated")
+	ErrBadToken        = errors.New("invalid token")
+)
+
+func (r Role) valid() bool {
+	switch r {
+	case RoleAdmin, RoleOperator, RoleAnalytics, RoleIntegrator:
+		return true
+	}
+	return false
+}
+
+// Principal is an authenticated caller. Integrator is the integrator id the
+// caller's fees accrue to, for RoleIntegrator.
+type Principal struct {
+	ID         string `json:"id"`
+	Role       Role   `json:"role"`
+	Integrator string `json:"integrator,omitempty"`
+}
+
+// Key is an API key entry of the access file.
+type Key struct {
+	ID     string `json:"id"`
+	APIKey string `json:"apiKey"`
+	Role   Role   `json:"role"`
+}
+
+// Policy is the immutable set of credentials loaded at startup.
+type Policy struct {
+	keys      []Key
+	jwtSecret []byte
+}
+
+// NewPolicy validates keys and builds a policy. adminKey, if set, is an
+// extra admin key; jwtSecret, if set, enables JWT authentication.
+func NewPolicy(keys []Key, adminKey string, jwtSecret []byte) (*Policy, error) {
+	ids := make(map[string]bool, len(keys))
+	secrets := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		if k.ID == "" || k.APIKey == "" {
+			return nil, errors.New("access key id and apiKey are required")
+		}
+		if !k.Role.valid() {
+			return nil, fmt.Errorf("access key %s has unknown role %q", k.ID, k.Role)
+		}
+		if ids[k.ID] || secrets[k.APIKey] {
+			return nil, fmt.Errorf("access key %s is a duplicate", k.ID)
+		}
+		ids[k.ID] = true
+		secrets[k.APIKey] = true
+	}
+
+	if adminKey != "" {
+		keys = append(keys, Key{ID: "admin", APIKey: adminKey, Role: RoleAdmin})
+	}
+	return &Policy{keys: keys, jwtSecret: jwtSecret}, nil
+}
+
+// Load reads the keys from a JSON array at path, if any, and adds the admin
+// key and JWT secret.
+func Load(path, adminKey, jwtSecret string) (*Policy, error) {
+	var keys []Key
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &keys); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", path, err)
+		}
+	}
+	return NewPolicy(keys, adminKey, []byte(jwtSecret))
+}
+
+// Enabled reports whether any credentials are configured.
+func (p *Policy) Enabled() bool {
+	return len(p.keys) > 0 || len(p.jwtSecret) > 0
+}
+
+// Authenticate returns the caller presenting token, an API key or a JWT.
+func (p *Policy) Authenticate(token string, now time.Time) (Principal, error) {
+	if token == "" {
+		return Principal{}, ErrUnauthenticated
+	}
+
+	for _, k := range p.keys {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(k.APIKey)) == 1 {
+			principal := Principal{ID: k.ID, Role: k.Role}
+			if k.Role == RoleIntegrator {
+				// integrators authenticate with the key they quote with
+				principal.Integrator = analytics.IntegratorID(k.APIKey)
+			}
+			return principal, nil
+		}
+	}
+
+	if len(p.jwtSecret) > 0 && strings.Count(token, ".") == 2 {
+		return p.verifyJWT(token, now)
+	}
+	return Principal{}, ErrUnauthenticated
+}
+
+type jwtClaims struct {
+	Subject    string `json:"sub"`
+	Role       Role   `json:"role"`
+	Integrator string `json:"integrator"`
+	ExpiresAt  int64  `json:"exp"`
+}
+
+// verifyJWT checks an HS256 token signed with the policy's secret. Tokens
+// must expire; integrator tokens must name their integrator id.
+func (p *Policy) verifyJWT(token string, now time.Time) (Principal, error) {
+	parts := strings.Split(token, ".")
+
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil || header.Alg != "HS256" {
+		return Principal{}, ErrBadToken
+	}
+
+	mac := hmac.New(sha256.New, p.jwtSecret)
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(sig, mac.Sum(nil)) {
+		return Principal{}, ErrBadToken
+	}
+
+	var claims jwtClaims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return Principal{}, ErrBadToken
+	}
+	if claims.ExpiresAt == 0 || now.Unix() >= claims.ExpiresAt {
+		return Principal{}, fmt.Errorf("%w: expired", ErrBadToken)
+	}
+	if claims.Subject == "" || !claims.Role.valid() {
+		return Principal{}, fmt.Errorf("%w: sub and a known role are required", ErrBadToken)
+	}
+	if claims.Role == RoleIntegrator && claims.Integrator == "" {
+		return Principal{}, fmt.Errorf("%w: integrator tokens must name the integrator", ErrBadToken)
+	}
+
+	return Principal{ID: claims.Subject, Role: claims.Role, Integrator: claims.Integrator}, nil
+}
+
+func decodeSegment(segment string, v any) error {
+	raw, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
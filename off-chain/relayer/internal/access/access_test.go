@@ -0,0 +1,143 @@
+package access
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+var secret = []byte("jwt secret")
+
+// signJWT encodes claims as a token with header alg, signed with key.
+func signJWT(t *testing.T, alg string, claims map[string]any, key []byte) string {
+	t.Helper()
+	segment := func(v any) string {
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	unsigned := segment(map[string]string{"alg": alg, "typ": "JWT"}) + "." + segment(claims)
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(unsigned))
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestAuthenticateJWT(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	exp := now.Add(time.Hour).Unix()
+	p, err := NewPolicy(nil, "", secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		alg     string
+		claims  map[string]any
+		key     []byte
+		want    Principal
+		wantErr bool
+	}{
+		{
+			name:   "operator",
+			claims: map[string]any{"sub": "ops", "role": "operator", "exp": exp},
+			want:   Principal{ID: "ops", Role: RoleOperator},
+		},
+		{
+			name:   "integrator",
+			claims: map[string]any{"sub": "dex", "role": "integrator", "integrator": "dex-id", "exp": exp},
+			want:   Principal{ID: "dex", Role: RoleIntegrator, Integrator: "dex-id"},
+		},
+		{
+			name:    "integrator without its id",
+			claims:  map[string]any{"sub": "dex", "role": "integrator", "exp": exp},
+			wantErr: true,
+		},
+		{
+			name:    "expired",
+			claims:  map[string]any{"sub": "ops", "role": "operator", "exp": now.Unix()},
+			wantErr: true,
+		},
+		{
+			name:    "missing exp",
+			claims:  map[string]any{"sub": "ops", "role": "operator"},
+			wantErr: true,
+		},
+		{
+			name:    "missing sub",
+			claims:  map[string]any{"role": "operator", "exp": exp},
+			wantErr: true,
+		},
+		{
+			name:    "unknown role",
+			claims:  map[string]any{"sub": "ops", "role": "root", "exp": exp},
+			wantErr: true,
+		},
+		{
+			name:    "not HS256",
+			alg:     "none",
+			claims:  map[string]any{"sub": "ops", "role": "admin", "exp": exp},
+			wantErr: true,
+		},
+		{
+			name:    "HS512 header",
+			alg:     "HS512",
+			claims:  map[string]any{"sub": "ops", "role": "admin", "exp": exp},
+			wantErr: true,
+		},
+		{
+			name:    "wrong secret",
+			claims:  map[string]any{"sub": "ops", "role": "admin", "exp": exp},
+			key:     []byte("other secret"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alg, key := tt.alg, tt.key
+			if alg == "" {
+				alg = "HS256"
+			}
+			if key == nil {
+				key = secret
+			}
+
+			got, err := p.Authenticate(signJWT(t, alg, tt.claims, key), now)
+			if tt.wantErr {
+				if !errors.Is(err, ErrBadToken) {
+					t.Fatalf("got %+v, %v, want %v", got, err, ErrBadToken)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Fatalf("got %+v, %v, want %+v", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthenticateKey(t *testing.T) {
+	p, err := NewPolicy([]Key{{ID: "ops", APIKey: "ops-key", Role: RoleOperator}}, "admin-key", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+
+	if got, err := p.Authenticate("ops-key", now); err != nil || got != (Principal{ID: "ops", Role: RoleOperator}) {
+		t.Fatalf("access file key: got %+v, %v", got, err)
+	}
+	if got, err := p.Authenticate("admin-key", now); err != nil || got.Role != RoleAdmin {
+		t.Fatalf("admin key: got %+v, %v", got, err)
+	}
+	for _, token := range []string{"", "other-key", "a.b.c"} {
+		if _, err := p.Authenticate(token, now); !errors.Is(err, ErrUnauthenticated) {
+			t.Fatalf("token %q: got %v, want %v", token, err, ErrUnauthenticated)
+		}
+	}
+}
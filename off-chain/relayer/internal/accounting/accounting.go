@@ -0,0 +1,104 @@
+package accounting
+
+import (
+	"fmt"
+	"math/big"
+	"relayer/internal/common"
+	"sort"
+	"sync"
+)
+
+// MaxFeeBps caps the protocol fee at 10%.
+const MaxFeeBps = 1000
+
+var bpsDenominator = big.NewInt(10_000)
+
+// FeeAmount returns the fee charged on makingAmount (a decimal string) at bps.
+func FeeAmount(makingAmount string, bps uint64) (*big.Int, error) {
+	amount, err := common.ParseAmount(makingAmount)
+	if err != nil {
+		return nil, fmt.Errorf("making amount: %w", err)
+	}
+
+	fee := new(big.Int).Mul(amount, new(big.Int).SetUint64(bps))
+	return fee.Div(fee, bpsDenominator), nil
+}
+
+// ApplyFee returns amount reduced by bps, rounding down, as a decimal string.
+// It is used to scale quoted output amounts after the protocol fee is deducted.
+func ApplyFee(amount string, bps uint64) (string, error) {
+	value, err := common.ParseAmount(amount)
+	if err != nil {
+		return "", err
+	}
+
+	value.Mul(value, new(big.Int).SetUint64(10_000-bps))
+	return value.Div(value, bpsDenominator).String(), nil
+}
+
+// Entry is the fee revenue accrued for one token on one chain.
+type Entry struct {
+	ChainID string `json:"chainId"`
+	Token   string `json:"token"`
+	Orders  int    `json:"orders"`
+	Accrued string `json:"accrued"`
+}
+
+type key struct {
+	chainID string
+	token   string
+}
+
+// Ledger accumulates protocol fees per (chain, token).
+type Ledger struct {
+	mu      sync.Mutex
+	accrued map[key]*big.Int
+	orders  map[key]int
+}
+
+func NewLedger() *Ledger {
+	return &Ledger{
+		accrued: make(map[key]*big.Int),
+		orders:  make(map[key]int),
+	}
+}
+
+// Accrue records a fee accrued on an order's fill, counting the order when
+// newOrder, on its first accrual.
+func (l *Ledger) Accrue(chainID, token string, amount *big.Int, newOrder bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	k := key{chainID: chainID, token: token}
+	if _, ok := l.accrued[k]; !ok {
+		l.accrued[k] = new(big.Int)
+	}
+	l.accrued[k].Add(l.accrued[k], amount)
+	if newOrder {
+		l.orders[k]++
+	}
+}
+
+// Summary returns the accrued fees ordered by chain and token.
+func (l *Ledger) Summary() []Entry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	out := make([]Entry, 0, len(l.accrued))
+	for k, amount := range l.accrued {
+		out = append(out, Entry{
+			ChainID: k.chainID,
+			Token:   k.token,
+			Orders:  l.orders[k],
+			Accrued: amount.String(),
+		})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].ChainID != out[j].ChainID {
+			return out[i].ChainID < out[j].ChainID
+		}
+		return out[i].Token < out[j].Token
+	})
+
+	return out
+}
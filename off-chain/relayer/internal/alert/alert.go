@@ -0,0 +1,131 @@
+// Package alert delivers operator alerts to a generic JSON webhook and to
+// PagerDuty through its Events API v2. Targets come from the environment; a
+// Notifier without any target drops alerts.
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// PagerDutyEventsURL is the PagerDuty Events API v2 endpoint.
+const PagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+// SendTimeout bounds the delivery of one alert to all targets.
+const SendTimeout = time.Second * 10
+
+// Severity of an alert, named as PagerDuty expects it.
+type Severity string
+
+const (
+	Critical Severity = "critical"
+	Warning  Severity = "warning"
+)
+
+// Alert is one condition raised or cleared. Key identifies the condition so
+// that a resolving alert closes the incident its triggering alert opened.
+type Alert struct {
+	Key      string         `json:"key"`
+	Summary  string         `json:"summary"`
+	Severity Severity       `json:"severity"`
+	Resolved bool           `json:"resolved"`
+	Details  map[string]any `json:"details,omitempty"`
+	Time     time.Time      `json:"time"`
+}
+
+// Notifier sends alerts to the configured targets.
+type Notifier struct {
+	webhookURL   string
+	pagerDutyKey string
+	source       string
+	client       *http.Client
+}
+
+// New returns a Notifier posting to webhookURL and/or PagerDuty with
+// routingKey; either may be empty. source names the relayer instance in
+// PagerDuty incidents.
+func New(webhookURL, routingKey, source string) *Notifier {
+	return &Notifier{
+		webhookURL:   webhookURL,
+		pagerDutyKey: routingKey,
+		source:       source,
+		client:       &http.Client{Timeout: SendTimeout},
+	}
+}
+
+// Enabled reports whether alerts go anywhere.
+func (n *Notifier) Enabled() bool {
+	return n.webhookURL != "" || n.pagerDutyKey != ""
+}
+
+// Send delivers a to every target, returning the errors of those that failed.
+func (n *Notifier) Send(ctx context.Context, a Alert) error {
+	if a.Time.IsZero() {
+		a.Time = time.Now()
+	}
+
+	var errs []error
+	if n.webhookURL != "" {
+		if err := n.post(ctx, n.webhookURL, a); err != nil {
+			errs = append(errs, fmt.Errorf("webhook: %w", err))
+		}
+	}
+	if n.pagerDutyKey != "" {
+		if err := n.post(ctx, PagerDutyEventsURL, n.pagerDutyEvent(a)); err != nil {
+			errs = append(errs, fmt.Errorf("pagerduty: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// pagerDutyEvent maps a to a PagerDuty trigger or resolve event.
+func (n *Notifier) pagerDutyEvent(a Alert) map[string]any {
+	action := "trigger"
+	if a.Resolved {
+		action = "resolve"
+	}
+
+	return map[string]any{
+		"routing_key":  n.pagerDutyKey,
+		"event_action": action,
+		"dedup_key":    a.Key,
+		"payload": map[string]any{
+			"summary":        a.Summary,
+			"source":         n.source,
+			"severity":       a.Severity,
+			"timestamp":      a.Time.UTC().Format(time.RFC3339),
+			"custom_details": a.Details,
+		},
+	}
+}
+
+func (n *Notifier) post(ctx context.Context, url string, body any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
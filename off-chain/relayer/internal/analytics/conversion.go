@@ -0,0 +1,128 @@
+package analytics
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// PairConversion is how many quotes for one chain pair and token pair were
+// ordered against and how many expired unused.
+type PairConversion struct {
+	SrcChain  string `json:"srcChain"`
+	DstChain  string `json:"dstChain"`
+	SrcToken  string `json:"srcToken"`
+	DstToken  string `json:"dstToken"`
+	Quoted    int    `json:"quoted"`
+	Converted int    `json:"converted"`
+	Expired   int    `json:"expired"`
+	// converted share of the quotes that were ordered against or expired,
+	// quotes still valid are not counted yet
+	ConversionRate float64 `json:"conversionRate"`
+}
+
+// QuotePair is the chain and token pair of a quote.
+type QuotePair struct {
+	SrcChain string
+	DstChain string
+	SrcToken string
+	DstToken string
+}
+
+// Conversions counts quotes by pair and links the quotes ordered against to
+// their order until the quote expires.
+type Conversions struct {
+	mu     sync.Mutex
+	counts map[QuotePair]*PairConversion
+	orders map[string]string // quote id to order hash
+}
+
+func NewConversions() *Conversions {
+	return &Conversions{
+		counts: make(map[QuotePair]*PairConversion),
+		orders: make(map[string]string),
+	}
+}
+
+func (c *Conversions) pair(p QuotePair) *PairConversion {
+	p.SrcToken, p.DstToken = strings.ToLower(p.SrcToken), strings.ToLower(p.DstToken)
+	stats, ok := c.counts[p]
+	if !ok {
+		stats = &PairConversion{SrcChain: p.SrcChain, DstChain: p.DstChain, SrcToken: p.SrcToken, DstToken: p.DstToken}
+		c.counts[p] = stats
+	}
+	return stats
+}
+
+// Quoted records a quote handed out.
+func (c *Conversions) Quoted(p QuotePair) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.pair(p).Quoted++
+}
+
+// Converted links quoteID to the order placed against it. Only the first
+// order of a quote counts.
+func (c *Conversions) Converted(quoteID string, p QuotePair, orderHash string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.orders[quoteID]; ok {
+		return
+	}
+	c.orders[quoteID] = orderHash
+	c.pair(p).Converted++
+}
+
+// Expired records the end of a quote's validity, counting it as unused if
+// no order was placed against it.
+func (c *Conversions) Expired(quoteID string, p QuotePair) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.orders[quoteID]; ok {
+		delete(c.orders, quoteID)
+		return
+	}
+	c.pair(p).Expired++
+}
+
+// Order returns the hash of the order placed against a live quote.
+func (c *Conversions) Order(quoteID string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	orderHash, ok := c.orders[quoteID]
+	return orderHash, ok
+}
+
+// Stats returns the conversion of every pair ordered by chains and tokens.
+func (c *Conversions) Stats() []PairConversion {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	out := make([]PairConversion, 0, len(c.counts))
+	for _, stats := range c.counts {
+		s := *stats
+		if resolved := s.Converted + s.Expired; resolved > 0 {
+			s.ConversionRate = float64(s.Converted) / float64(resolved)
+		}
+		out = append(out, s)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if a.SrcChain != b.SrcChain {
+			return a.SrcChain < b.SrcChain
+		}
+		if a.DstChain != b.DstChain {
+			return a.DstChain < b.DstChain
+		}
+		if a.SrcToken != b.SrcToken {
+			return a.SrcToken < b.SrcToken
+		}
+		return a.DstToken < b.DstToken
+	})
+
+	return out
+}
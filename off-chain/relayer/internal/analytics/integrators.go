@@ -0,0 +1,99 @@
+package analytics
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"math/big"
+	"sort"
+	"sync"
+)
+
+// AnonymousIntegrator is the integrator of quotes requested without an API key.
+const AnonymousIntegrator = "anonymous"
+
+// IntegratorID identifies the integrator holding apiKey without exposing the
+// key: the first 8 bytes of its SHA-256, hex encoded.
+func IntegratorID(apiKey string) string {
+	if apiKey == "" {
+		return AnonymousIntegrator
+	}
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:8])
+}
+
+// IntegratorFee is the fee accrued by one integrator in one token on one chain.
+type IntegratorFee struct {
+	Integrator string `json:"integrator"`
+	ChainID    string `json:"chainId"`
+	Token      string `json:"token"`
+	Orders     int    `json:"orders"`
+	Accrued    string `json:"accrued"`
+}
+
+type integratorKey struct {
+	integrator string
+	chainID    string
+	token      string
+}
+
+// IntegratorFees accumulates integrator fees per (integrator, chain, token).
+type IntegratorFees struct {
+	mu      sync.Mutex
+	accrued map[integratorKey]*big.Int
+	orders  map[integratorKey]int
+}
+
+func NewIntegratorFees() *IntegratorFees {
+	return &IntegratorFees{
+		accrued: make(map[integratorKey]*big.Int),
+		orders:  make(map[integratorKey]int),
+	}
+}
+
+// Accrue records the integrator fee accrued on an order's fill, counting the
+// order when newOrder, on its first accrual.
+func (f *IntegratorFees) Accrue(integrator, chainID, token string, amount *big.Int, newOrder bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	k := integratorKey{integrator: integrator, chainID: chainID, token: token}
+	if _, ok := f.accrued[k]; !ok {
+		f.accrued[k] = new(big.Int)
+	}
+	f.accrued[k].Add(f.accrued[k], amount)
+	if newOrder {
+		f.orders[k]++
+	}
+}
+
+// Summary returns the accrued fees ordered by integrator, chain and token,
+// only those of integrator when it is not empty.
+func (f *IntegratorFees) Summary(integrator string) []IntegratorFee {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	out := make([]IntegratorFee, 0, len(f.accrued))
+	for k, amount := range f.accrued {
+		if integrator != "" && k.integrator != integrator {
+			continue
+		}
+		out = append(out, IntegratorFee{
+			Integrator: k.integrator,
+			ChainID:    k.chainID,
+			Token:      k.token,
+			Orders:     f.orders[k],
+			Accrued:    amount.String(),
+		})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Integrator != out[j].Integrator {
+			return out[i].Integrator < out[j].Integrator
+		}
+		if out[i].ChainID != out[j].ChainID {
+			return out[i].ChainID < out[j].ChainID
+		}
+		return out[i].Token < out[j].Token
+	})
+
+	return out
+}
@@ -0,0 +1,38 @@
+package analytics
+
+import (
+	"math"
+	"slices"
+	"time"
+)
+
+// StageLatency summarizes how long orders took between two lifecycle stages.
+type StageLatency struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Orders int    `json:"orders"`
+	P50Ms  int64  `json:"p50Ms"`
+	P95Ms  int64  `json:"p95Ms"`
+	MaxMs  int64  `json:"maxMs"`
+}
+
+// Latency computes the distribution of durations between from and to.
+func Latency(from, to string, durations []time.Duration) StageLatency {
+	stats := StageLatency{From: from, To: to, Orders: len(durations)}
+	if len(durations) == 0 {
+		return stats
+	}
+
+	sorted := slices.Clone(durations)
+	slices.Sort(sorted)
+	stats.P50Ms = percentile(sorted, 0.50).Milliseconds()
+	stats.P95Ms = percentile(sorted, 0.95).Milliseconds()
+	stats.MaxMs = sorted[len(sorted)-1].Milliseconds()
+	return stats
+}
+
+// percentile returns the nearest-rank percentile p of sorted durations.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	return sorted[max(rank, 0)]
+}
@@ -0,0 +1,152 @@
+package analytics
+
+import (
+	"math/big"
+	"relayer/internal/common"
+	"sort"
+	"sync"
+	"time"
+)
+
+// SurplusRecord is the difference between what an order settled for on the
+// destination chain and the quoted auction end amount.
+type SurplusRecord struct {
+	OrderHash  string    `json:"orderHash"`
+	SrcChain   string    `json:"srcChain"`
+	DstChain   string    `json:"dstChain"`
+	Token      string    `json:"token"`
+	Quoted     string    `json:"quoted"`
+	Settled    string    `json:"settled"`
+	Surplus    string    `json:"surplus"`
+	RecordedAt time.Time `json:"recordedAt"`
+}
+
+// TokenSurplus aggregates surplus for one destination token.
+type TokenSurplus struct {
+	DstChain          string  `json:"dstChain"`
+	Token             string  `json:"token"`
+	Orders            int     `json:"orders"`
+	OrdersWithSurplus int     `json:"ordersWithSurplus"`
+	TotalSurplus      string  `json:"totalSurplus"`
+	AvgSurplusBps     float64 `json:"avgSurplusBps"`
+}
+
+// MaxSurplusRecords bounds the orders Surplus keeps a record of, the oldest
+// ones being forgotten first.
+const MaxSurplusRecords = 10000
+
+// Surplus keeps one record per settled order, of the last MaxSurplusRecords.
+type Surplus struct {
+	mu      sync.Mutex
+	records map[string]SurplusRecord
+	// order hashes of the records, oldest first
+	order []string
+}
+
+func NewSurplus() *Surplus {
+	return &Surplus{records: make(map[string]SurplusRecord)}
+}
+
+// Record computes and stores the surplus of an order. Amounts are decimal
+// strings in destination token units; a settlement below the quoted amount is
+// recorded as zero surplus. The first record of an order wins.
+func (s *Surplus) Record(orderHash, srcChain, dstChain, token string, quoted, settled *big.Int) SurplusRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, ok := s.records[orderHash]; ok {
+		return existing
+	}
+
+	surplus := new(big.Int).Sub(settled, quoted)
+	if surplus.Sign() < 0 {
+		surplus.SetInt64(0)
+	}
+
+	record := SurplusRecord{
+		OrderHash:  orderHash,
+		SrcChain:   srcChain,
+		DstChain:   dstChain,
+		Token:      token,
+		Quoted:     quoted.String(),
+		Settled:    settled.String(),
+		Surplus:    surplus.String(),
+		RecordedAt: time.Now(),
+	}
+	s.records[orderHash] = record
+	s.order = append(s.order, orderHash)
+	if len(s.order) > MaxSurplusRecords {
+		delete(s.records, s.order[0])
+		s.order = s.order[1:]
+	}
+
+	return record
+}
+
+// Get returns the surplus record of an order.
+func (s *Surplus) Get(orderHash string) (SurplusRecord, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, ok := s.records[orderHash]
+	return record, ok
+}
+
+// Stats aggregates the kept records per destination chain and token.
+func (s *Surplus) Stats() []TokenSurplus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	type acc struct {
+		stats  TokenSurplus
+		total  *big.Int
+		bpsSum float64
+	}
+	groups := make(map[[2]string]*acc)
+
+	for _, r := range s.records {
+		surplus, err := common.ParseAmount(r.Surplus)
+		if err != nil {
+			continue
+		}
+		quoted, err := common.ParseAmount(r.Quoted)
+		if err != nil {
+			continue
+		}
+
+		k := [2]string{r.DstChain, r.Token}
+		g, ok := groups[k]
+		if !ok {
+			g = &acc{stats: TokenSurplus{DstChain: r.DstChain, Token: r.Token}, total: new(big.Int)}
+			groups[k] = g
+		}
+
+		g.stats.Orders++
+		if surplus.Sign() > 0 {
+			g.stats.OrdersWithSurplus++
+		}
+		g.total.Add(g.total, surplus)
+		if quoted.Sign() > 0 {
+			bps, _ := new(big.Float).Quo(
+				new(big.Float).SetInt(new(big.Int).Mul(surplus, big.NewInt(10_000))),
+				new(big.Float).SetInt(quoted),
+			).Float64()
+			g.bpsSum += bps
+		}
+	}
+
+	out := make([]TokenSurplus, 0, len(groups))
+	for _, g := range groups {
+		g.stats.TotalSurplus = g.total.String()
+		g.stats.AvgSurplusBps = g.bpsSum / float64(g.stats.Orders)
+		out = append(out, g.stats)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].DstChain != out[j].DstChain {
+			return out[i].DstChain < out[j].DstChain
+		}
+		return out[i].Token < out[j].Token
+	})
+
+	return out
+}
@@ -0,0 +1,25 @@
+package analytics
+
+import (
+	"math/big"
+	"strconv"
+	"testing"
+)
+
+func TestSurplusBound(t *testing.T) {
+	s := NewSurplus()
+	for i := range MaxSurplusRecords + 1 {
+		s.Record(strconv.Itoa(i), "1", "101", "0xtoken", big.NewInt(100), big.NewInt(101))
+	}
+
+	if _, ok := s.Get("0"); ok {
+		t.Error("oldest record kept past MaxSurplusRecords")
+	}
+	if _, ok := s.Get(strconv.Itoa(MaxSurplusRecords)); !ok {
+		t.Error("newest record missing")
+	}
+	stats := s.Stats()
+	if len(stats) != 1 || stats[0].Orders != MaxSurplusRecords || stats[0].TotalSurplus != strconv.Itoa(MaxSurplusRecords) {
+		t.Errorf("stats = %+v, want %d orders of surplus 1", stats, MaxSurplusRecords)
+	}
+}
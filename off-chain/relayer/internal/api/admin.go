@@ -0,0 +1,185 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"relayer/internal/access"
+	"relayer/internal/common"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// PrincipalKey is the gin context key of the authenticated caller.
+const PrincipalKey = "principal"
+
+// requireRole guards endpoints with the access policy: the bearer token must
+// authenticate a caller holding one of roles. Admins pass every check. The
+// guarded routes are disabled entirely when no credentials are configured.
+func (s *APIServer) requireRole(roles ...access.Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !s.access.Enabled() {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "Admin API is disabled"})
+			return
+		}
+
+		token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		principal, err := s.access.Authenticate(token, time.Now())
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+		if principal.Role != access.RoleAdmin && !slices.Contains(roles, principal.Role) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": fmt.Sprintf("Role %s may not access this endpoint", principal.Role)})
+			return
+		}
+
+		c.Set(PrincipalKey, principal)
+		c.Next()
+	}
+}
+
+// principal returns the caller authenticated by requireRole.
+func principal(c *gin.Context) access.Principal {
+	p, _ := c.MustGet(PrincipalKey).(access.Principal)
+	return p
+}
+
+// ListOrders returns a summary of every live order.
+func (s *APIServer) ListOrders(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"orders": s.manager.AdminOrders()})
+}
+
+// InspectOrder returns the full relayer state of an order, live or archived.
+func (s *APIServer) InspectOrder(c *gin.Context) {
+	order, err := s.manager.AdminOrder(c.Param("orderHash"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Order not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
+
+// InspectQuote returns a cached quote with the request it was issued for.
+func (s *APIServer) InspectQuote(c *gin.Context) {
+	quoteID, err := uuid.Parse(c.Param("quoteId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid quote id"})
+		return
+	}
+
+	quoteEntry, err := s.manager.GetQuote(quoteID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Quote not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, s.manager.AdminQuote(quoteEntry))
+}
+
+// ReverifyFill verifies a fill's escrow transactions again, bypassing the
+// verification cache.
+func (s *APIServer) ReverifyFill(c *gin.Context) {
+	var req common.ReverifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.SrcTxHash == "" || req.DstTxHash == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "srcTxHash and dstTxHash are required"})
+		return
+	}
+
+	orderHash := c.Param("orderHash")
+	s.logger.Printf("Admin re-verification of order %s: src %s, dst %s", orderHash, req.SrcTxHash, req.DstTxHash)
+	if err := s.manager.Reverify(orderHash, req.SrcTxHash, req.DstTxHash); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"verified": true})
+}
+
+// ReleaseFill marks a fill ready to accept its secret without verification.
+func (s *APIServer) ReleaseFill(c *gin.Context) {
+	var fill common.ReadyToAcceptSecretFill
+	if err := c.ShouldBindJSON(&fill); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid fill"})
+		return
+	}
+
+	if err := s.manager.ForceRelease(c.Param("orderHash"), fill); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"released": true})
+}
+
+// ListQuarantined returns the orders held back for their price, awaiting
+// approval.
+func (s *APIServer) ListQuarantined(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"orders": s.manager.Quarantined()})
+}
+
+// ApproveQuarantined broadcasts a quarantined order as if its price had been
+// in line.
+func (s *APIServer) ApproveQuarantined(c *gin.Context) {
+	orderEntry, err := s.manager.TakeQuarantined(c.Param("orderHash"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	s.logger.Printf("Quarantined order %s approved by %s", orderEntry.OrderHash.Hex(), principal(c).ID)
+	if status, body := s.dispatchOrder(orderEntry); body != nil {
+		c.JSON(status, body)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"approved": true})
+}
+
+// RejectQuarantined drops a quarantined order without broadcasting it.
+func (s *APIServer) RejectQuarantined(c *gin.Context) {
+	orderEntry, err := s.manager.TakeQuarantined(c.Param("orderHash"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	s.logger.Printf("Quarantined order %s rejected by %s", orderEntry.OrderHash.Hex(), principal(c).ID)
+	c.JSON(http.StatusOK, gin.H{"rejected": true})
+}
+
+// GetChainHealth reports connectivity of the chain RPC endpoints.
+func (s *APIServer) GetChainHealth(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"chains": s.manager.ChainHealth(c.Request.Context())})
+}
+
+// GetSafetyDeposits reports the safety deposit parameters of the configured
+// chains with the deposits their quotes currently get.
+func (s *APIServer) GetSafetyDeposits(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"safetyDeposits": s.manager.SafetyDeposits(c.Request.Context())})
+}
+
+// GetResolverStats ranks the authenticated resolvers by the score of their
+// connection uptime and ping round trips.
+func (s *APIServer) GetResolverStats(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"resolvers": s.manager.Liveness().Stats(time.Now())})
+}
+
+// GetConfig returns the active runtime config.
+func (s *APIServer) GetConfig(c *gin.Context) {
+	c.JSON(http.StatusOK, s.manager.Config())
+}
+
+// ReloadConfig re-reads CONFIG_FILE, same as sending SIGHUP to the relayer.
+func (s *APIServer) ReloadConfig(c *gin.Context) {
+	cfg, err := s.manager.ReloadConfig()
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, cfg)
+}
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"relayer/internal/access"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequireRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	policy, err := access.NewPolicy([]access.Key{
+		{ID: "operator", APIKey: "operator-key", Role: access.RoleOperator},
+		{ID: "analytics", APIKey: "analytics-key", Role: access.RoleAnalytics},
+		{ID: "integrator", APIKey: "integrator-key", Role: access.RoleIntegrator},
+	}, "admin-key", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &APIServer{access: policy}
+
+	// the guards of routes.go
+	guards := map[string]gin.HandlerFunc{
+		"admin":       s.requireRole(),
+		"operator":    s.requireRole(access.RoleOperator),
+		"analyst":     s.requireRole(access.RoleOperator, access.RoleAnalytics),
+		"integrators": s.requireRole(access.RoleOperator, access.RoleAnalytics, access.RoleIntegrator),
+	}
+	tests := []struct {
+		token string
+		want  map[string]int
+	}{
+		{
+			token: "admin-key",
+			want:  map[string]int{"admin": http.StatusOK, "operator": http.StatusOK, "analyst": http.StatusOK, "integrators": http.StatusOK},
+		},
+		{
+			token: "operator-key",
+			want:  map[string]int{"admin": http.StatusForbidden, "operator": http.StatusOK, "analyst": http.StatusOK, "integrators": http.StatusOK},
+		},
+		{
+			token: "analytics-key",
+			want:  map[string]int{"admin": http.StatusForbidden, "operator": http.StatusForbidden, "analyst": http.StatusOK, "integrators": http.StatusOK},
+		},
+		{
+			token: "integrator-key",
+			want:  map[string]int{"admin": http.StatusForbidden, "operator": http.StatusForbidden, "analyst": http.StatusForbidden, "integrators": http.StatusOK},
+		},
+		{
+			token: "unknown-key",
+			want:  map[string]int{"admin": http.StatusUnauthorized, "operator": http.StatusUnauthorized, "analyst": http.StatusUnauthorized, "integrators": http.StatusUnauthorized},
+		},
+		{
+			want: map[string]int{"admin": http.StatusUnauthorized, "operator": http.StatusUnauthorized, "analyst": http.StatusUnauthorized, "integrators": http.StatusUnauthorized},
+		},
+	}
+	for _, tt := range tests {
+		for guard, want := range tt.want {
+			t.Run(tt.token+"/"+guard, func(t *testing.T) {
+				if got := serveGuarded(guards[guard], tt.token); got != want {
+					t.Fatalf("got status %d, want %d", got, want)
+				}
+			})
+		}
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		disabled, err := access.NewPolicy(nil, "", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s := &APIServer{access: disabled}
+		if got := serveGuarded(s.requireRole(), "admin-key"); got != http.StatusNotFound {
+			t.Fatalf("got status %d, want %d", got, http.StatusNotFound)
+		}
+	})
+}
+
+// serveGuarded serves a request with a bearer token, if any, through guard.
+func serveGuarded(guard gin.HandlerFunc, token string) int {
+	router := gin.New()
+	router.GET("/", guard, func(c *gin.Context) {
+		if principal(c).Role == "" {
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec.Code
+}
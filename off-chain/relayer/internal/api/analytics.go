@@ -0,0 +1,66 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"relayer/internal/manager"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultLatencyWindow is how far back latency statistics look without ?since=.
+const DefaultLatencyWindow = time.Hour * 24
+
+// GetSurplus returns aggregated settlement surplus per destination token, or
+// the record of a single order with ?orderHash=.
+func (s *APIServer) GetSurplus(c *gin.Context) {
+	if orderHash := c.Query("orderHash"); orderHash != "" {
+		record, ok := s.manager.Surplus().Get(orderHash)
+		if !ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": "No surplus recorded for order"})
+			return
+		}
+
+		c.JSON(http.StatusOK, record)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"surplus": s.manager.Surplus().Stats()})
+}
+
+// GetLatency returns p50/p95 latencies between the lifecycle stages of orders
+// quoted in the window given by ?since=, a lookback such as "6h" or an
+// RFC 3339 time, computed from the stage timestamps in the persistent store.
+func (s *APIServer) GetLatency(c *gin.Context) {
+	since := time.Now().Add(-DefaultLatencyWindow)
+	if v := c.Query("since"); v != "" {
+		if lookback, err := time.ParseDuration(v); err == nil {
+			since = time.Now().Add(-lookback)
+		} else if at, err := time.Parse(time.RFC3339, v); err == nil {
+			since = at
+		} else {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "since must be a duration like 6h or an RFC 3339 time"})
+			return
+		}
+	}
+
+	stages, err := s.manager.Latency(c.Request.Context(), since)
+	if errors.Is(err, manager.ErrNoStore) {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Latency analytics require DATABASE_PATH"})
+		return
+	}
+	if err != nil {
+		s.logger.Printf("Error computing latency: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute latency"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"since": since.UTC().Format(time.RFC3339), "stages": stages})
+}
+
+// GetConversion returns, per chain pair and token pair, how many quotes were
+// ordered against and how many expired unused.
+func (s *APIServer) GetConversion(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"conversion": s.manager.Conversions().Stats()})
+}
@@ -0,0 +1,158 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"relayer/internal/common"
+	"relayer/internal/metrics"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultKeyCooldown is how long a 1inch API key answered with 429 is left
+// out of the rotation when the answer has no Retry-After.
+const DefaultKeyCooldown = 5 * time.Second
+
+// apiKeys rotates the 1inch API keys of 1INCH_API_KEY, a comma separated
+// list, round robin. A key answered with 429 cools down for the answer's
+// Retry-After and the call is retried with the next key.
+type apiKeys struct {
+	mu   sync.Mutex
+	keys []*apiKey
+	next int
+}
+
+// apiKey is one 1inch API key and its usage since the start.
+type apiKey struct {
+	secret    string
+	label     string // for metrics and the admin API, without the secret
+	requests  int64
+	throttled int64
+	coolUntil time.Time
+}
+
+func newAPIKeys(list string) *apiKeys {
+	k := &apiKeys{}
+	for _, secret := range strings.Split(list, ",") {
+		secret = strings.TrimSpace(secret)
+		if secret == "" {
+			continue
+		}
+		label := fmt.Sprintf("key%d", len(k.keys)+1)
+		if len(secret) > 8 {
+			label += "..." + secret[len(secret)-4:]
+		}
+		k.keys = append(k.keys, &apiKey{secret: secret, label: label})
+	}
+	return k
+}
+
+// pick returns the next key not cooling down and not in tried, or, with all
+// of them cooling down, the untried one ready soonest. It returns nil once
+// every key was tried.
+func (k *apiKeys) pick(tried map[*apiKey]bool, now time.Time) *apiKey {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	var soonest *apiKey
+	for i := range k.keys {
+		key := k.keys[(k.next+i)%len(k.keys)]
+		if tried[key] {
+			continue
+		}
+		if !key.coolUntil.After(now) {
+			k.next = (k.next + i + 1) % len(k.keys)
+			return key
+		}
+		if soonest == nil || key.coolUntil.Before(soonest.coolUntil) {
+			soonest = key
+		}
+	}
+	return soonest
+}
+
+// record counts a call made with key, cooling the key down when it was
+// throttled.
+func (k *apiKeys) record(key *apiKey, resp *http.Response, now time.Time) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	key.requests++
+	status := "error"
+	if resp != nil {
+		status = strconv.Itoa(resp.StatusCode)
+	}
+	metrics.UpstreamKeyRequests.Add(key.label+" "+status, 1)
+	if resp == nil || resp.StatusCode != http.StatusTooManyRequests {
+		return
+	}
+
+	key.throttled++
+	cooldown := DefaultKeyCooldown
+	if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs > 0 {
+		cooldown = time.Duration(secs) * time.Second
+	}
+	key.coolUntil = now.Add(cooldown)
+}
+
+// usage reports every key's usage, in configuration order.
+func (k *apiKeys) usage(now time.Time) []common.UpstreamKeyUsage {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	out := make([]common.UpstreamKeyUsage, 0, len(k.keys))
+	for _, key := range k.keys {
+		u := common.UpstreamKeyUsage{Key: key.label, Requests: key.requests, Throttled: key.throttled}
+		if key.coolUntil.After(now) {
+			until := key.coolUntil
+			u.CoolingUntil = &until
+		}
+		out = append(out, u)
+	}
+	return out
+}
+
+// callUpstream sends req to the 1inch API with the next API key, retrying
+// with the other keys while it is answered with 429. Requests with a body
+// must be built from a bytes reader so they can be resent.
+func (s *APIServer) callUpstream(req *http.Request) (*http.Response, error) {
+	if len(s.apiKeys.keys) == 0 {
+		return s.upstream.Do(req)
+	}
+
+	tried := make(map[*apiKey]bool)
+	for {
+		key := s.apiKeys.pick(tried, time.Now())
+		tried[key] = true
+
+		attempt := req.Clone(req.Context())
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			attempt.Body = body
+		}
+		attempt.Header.Set("Authorization", "Bearer "+key.secret)
+
+		resp, err := s.upstream.Do(attempt)
+		s.apiKeys.record(key, resp, time.Now())
+		if err != nil || resp.StatusCode != http.StatusTooManyRequests || len(tried) == len(s.apiKeys.keys) {
+			return resp, err
+		}
+
+		s.logger.Printf("1inch API key %s throttled, retrying with the next key", key.label)
+		io.Copy(io.Discard, io.LimitReader(resp.Body, upstreamBodyLimit))
+		resp.Body.Close()
+	}
+}
+
+// GetUpstreamKeys reports the requests and throttling of every 1inch API key.
+func (s *APIServer) GetUpstreamKeys(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"keys": s.apiKeys.usage(time.Now())})
+}
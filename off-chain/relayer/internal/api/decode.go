@@ -0,0 +1,88 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// DecodeError locates why a JSON payload could not be decoded.
+type DecodeError struct {
+	// 1-based position of the offending input
+	Line   int
+	Column int
+	// dotted path of the offending field, when known
+	Field string
+	Err   error
+}
+
+func (e *DecodeError) Error() string {
+	if e.Field != "" {
+		return fmt.Sprintf("line %d, column %d, field %s: %v", e.Line, e.Column, e.Field, e.Err)
+	}
+	return fmt.Sprintf("line %d, column %d: %v", e.Line, e.Column, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// decodeJSON decodes the single JSON value of r into v. Under strict, fields
+// v has no place for and trailing data are rejected. Errors are located in
+// the input as a *DecodeError.
+func decodeJSON(r io.Reader, v any, strict bool) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if strict {
+		dec.DisallowUnknownFields()
+	}
+	if err := dec.Decode(v); err != nil {
+		return locate(data, dec.InputOffset(), err)
+	}
+	if strict {
+		if _, err := dec.Token(); err != io.EOF {
+			return locate(data, dec.InputOffset(), errors.New("unexpected data after the JSON value"))
+		}
+	}
+	return nil
+}
+
+// locate wraps err in a DecodeError at the offset it carries, or at offset
+// where the decoder stopped.
+func locate(data []byte, offset int64, err error) error {
+	derr := &DecodeError{Err: err}
+
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		offset = syntaxErr.Offset
+	case errors.As(err, &typeErr):
+		offset = typeErr.Offset
+		derr.Field = typeErr.Field
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		offset = int64(len(data))
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		// the decoder reads the whole value before it complains, point at the
+		// key instead
+		derr.Field = strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)
+		if idx := bytes.Index(data, []byte(`"`+derr.Field+`"`)); idx >= 0 {
+			offset = int64(idx)
+		}
+	}
+
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	before := data[:offset]
+	derr.Line = bytes.Count(before, []byte("\n")) + 1
+	derr.Column = int(offset) - (bytes.LastIndexByte(before, '\n') + 1) + 1
+	return derr
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"relayer/internal/accounting"
+	"relayer/internal/common"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetEstimate previews the price of a quote request for UI price displays.
+// It quotes like GetQuote but stores nothing, so the estimate cannot be
+// signed against and leaves no throwaway quotes behind.
+func (s *APIServer) GetEstimate(c *gin.Context) {
+	queryParams, err := parseQuoteRequest(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	path, hub, err := s.manager.Routes().Path(queryParams.SrcChain, queryParams.DstChain)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	legParams := []common.QuoteRequestParams{queryParams}
+	if hub != nil {
+		legParams = hubLegParams(queryParams, hub)
+	}
+
+	var estimate common.QuoteEstimate
+	var last *common.Quote
+	for i, route := range path {
+		params := legParams[i]
+		if last != nil {
+			params.Amount = last.DstTokenAmount
+		}
+
+		quote, err := s.quoteRoute(params, route)
+		if err != nil {
+			c.JSON(quoteErrorStatus(err), gin.H{"error": fmt.Sprintf("leg %d: %s", i+1, err)})
+			return
+		}
+
+		fee, err := estimateFee(params, quote)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		estimate.Fees = append(estimate.Fees, fee)
+
+		if i == 0 {
+			estimate.SrcTokenAmount = quote.SrcTokenAmount
+			estimate.Prices.USD.SrcToken = quote.Prices.USD.SrcToken
+		}
+		last = quote
+	}
+
+	preset := last.Presets[last.RecommendedPreset]
+	estimate.DstTokenAmount = last.DstTokenAmount
+	estimate.AuctionStartAmount = preset.AuctionStartAmount
+	estimate.AuctionEndAmount = preset.AuctionEndAmount
+	estimate.RecommendedPreset = last.RecommendedPreset
+	estimate.Prices.USD.DstToken = last.Prices.USD.DstToken
+
+	c.JSON(http.StatusOK, estimate)
+}
+
+// estimateFee computes the fees a leg takes of the amount it is quoted for.
+func estimateFee(params common.QuoteRequestParams, quote *common.Quote) (common.FeeEstimate, error) {
+	fee := common.FeeEstimate{
+		ChainID:          params.SrcChain,
+		Token:            params.SrcTokenAddress,
+		ProtocolFeeBps:   quote.ProtocolFeeBps,
+		IntegratorFeeBps: quote.IntegratorFeeBps,
+	}
+
+	protocolFee, err := accounting.FeeAmount(params.Amount, quote.ProtocolFeeBps)
+	if err != nil {
+		return fee, err
+	}
+	fee.ProtocolFee = protocolFee.String()
+
+	if quote.IntegratorFeeBps > 0 {
+		integratorFee, err := accounting.FeeAmount(params.Amount, quote.IntegratorFeeBps)
+		if err != nil {
+			return fee, err
+		}
+		fee.IntegratorFee = integratorFee.String()
+	}
+	return fee, nil
+}
@@ -0,0 +1,135 @@
+package api
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"relayer/internal/access"
+	"relayer/internal/accounting"
+	"relayer/internal/analytics"
+	"relayer/internal/common"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// applyProtocolFee deducts the protocol fee from every output amount of the quote.
+func applyProtocolFee(quote *common.Quote, bps uint64) error {
+	if err := deductFee(quote, bps); err != nil {
+		return err
+	}
+	quote.ProtocolFeeBps = bps
+	return nil
+}
+
+// applyIntegratorFee deducts the integrator fee requested with the quote from
+// every output amount of the quote.
+func applyIntegratorFee(quote *common.Quote, params common.QuoteRequestParams) error {
+	if params.IntegratorFee == 0 {
+		return nil
+	}
+	if err := deductFee(quote, params.IntegratorFee); err != nil {
+		return err
+	}
+	quote.IntegratorFeeBps = params.IntegratorFee
+	quote.FeeReceiver = params.FeeReceiver
+	return nil
+}
+
+// deductFee scales every output amount of the quote down by bps.
+func deductFee(quote *common.Quote, bps uint64) error {
+	if bps == 0 {
+		return nil
+	}
+
+	dstAmount, err := accounting.ApplyFee(quote.DstTokenAmount, bps)
+	if err != nil {
+		return err
+	}
+	quote.DstTokenAmount = dstAmount
+
+	return updatePresets(quote, func(_ common.PresetEnum, preset common.PresetData) (common.PresetData, error) {
+		for _, amount := range []*string{&preset.AuctionStartAmount, &preset.StartAmount, &preset.AuctionEndAmount} {
+			reduced, err := accounting.ApplyFee(*amount, bps)
+			if err != nil {
+				return preset, err
+			}
+			*amount = reduced
+		}
+		return preset, nil
+	})
+}
+
+// IntegratorKeyHeader carries the API key integrators request quotes with;
+// their fees are accounted under its fingerprint.
+const IntegratorKeyHeader = "X-API-Key"
+
+// integratorID identifies the integrator requesting a quote.
+func integratorID(c *gin.Context) string {
+	return analytics.IntegratorID(c.GetHeader(IntegratorKeyHeader))
+}
+
+// parseIntegratorFee reads ?integratorFee=<bps>&feeReceiver=<address> into
+// the quote request. The receiver is an address on the source chain and is
+// required with a non-zero fee.
+func parseIntegratorFee(c *gin.Context, params *common.QuoteRequestParams) error {
+	if v := c.Query("integratorFee"); v != "" {
+		bps, err := strconv.ParseUint(v, 10, 64)
+		if err != nil || bps > accounting.MaxFeeBps {
+			return fmt.Errorf("integratorFee must be an integer between 0 and %d", accounting.MaxFeeBps)
+		}
+		params.IntegratorFee = bps
+	}
+
+	if params.IntegratorFee == 0 {
+		if params.FeeReceiver != "" {
+			return errors.New("feeReceiver requires an integratorFee")
+		}
+		return nil
+	}
+	if params.FeeReceiver == "" {
+		return errors.New("integratorFee requires a feeReceiver")
+	}
+	if err := common.ValidateAddress(params.SrcChain, params.FeeReceiver); err != nil {
+		return fmt.Errorf("invalid feeReceiver: %w", err)
+	}
+	return nil
+}
+
+// GetIntegratorFees returns the integrator fees accrued per API key
+// fingerprint, chain and token, or one integrator's with ?integrator=.
+func (s *APIServer) GetIntegratorFees(c *gin.Context) {
+	integrator := c.Query("integrator")
+	if p := principal(c); p.Role == access.RoleIntegrator {
+		if integrator != "" && integrator != p.Integrator {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Integrators may only read their own fees"})
+			return
+		}
+		integrator = p.Integrator
+	}
+
+	c.JSON(http.StatusOK, gin.H{"fees": s.manager.IntegratorFees().Summary(integrator)})
+}
+
+// GetFeeSummary exports the accrued protocol fees per chain and token,
+// as JSON or as CSV with ?format=csv.
+func (s *APIServer) GetFeeSummary(c *gin.Context) {
+	summary := s.manager.Fees().Summary()
+
+	if c.Query("format") != "csv" {
+		c.JSON(http.StatusOK, gin.H{"feeBps": s.feeBps, "fees": summary})
+		return
+	}
+
+	c.Header("Content-Type", "text/csv")
+	c.Header("Content-Disposition", `attachment; filename="protocol-fees.csv"`)
+	c.Status(http.StatusOK)
+
+	w := csv.NewWriter(c.Writer)
+	w.Write([]string{"chainId", "token", "orders", "accrued"})
+	for _, e := range summary {
+		w.Write([]string{e.ChainID, e.Token, strconv.Itoa(e.Orders), e.Accrued})
+	}
+	w.Flush()
+}
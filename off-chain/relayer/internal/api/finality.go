@@ -0,0 +1,56 @@
+package api
+
+import (
+	"relayer/internal/common"
+	"time"
+)
+
+// adjustForFinality fits a quote to chains whose escrow deployments take
+// longer to become final than its timelocks allow for. The relayer holds a
+// fill's secret until both deployments are final (finalityDelays), so the
+// withdrawal stages of each escrow are pushed back past its chain's
+// finality, keeping the length of every later stage and the dst escrow's
+// cancellation ahead of the src escrow's. The auctions are stretched by as
+// much as the src stages moved, since fills settle that much later.
+func adjustForFinality(quote *common.Quote, srcFinality, dstFinality time.Duration) {
+	locks := &quote.TimeLocks
+
+	dstShift := max(0, int64(dstFinality/time.Second)-locks.DstWithdrawal)
+	srcShift := max(0, int64(srcFinality/time.Second)-locks.SrcWithdrawal, dstShift)
+	if srcShift == 0 && dstShift == 0 {
+		return
+	}
+
+	for _, stage := range []*int64{&locks.SrcWithdrawal, &locks.SrcPublicWithdrawal, &locks.SrcCancellation, &locks.SrcPublicCancellation} {
+		*stage += srcShift
+	}
+	for _, stage := range []*int64{&locks.DstWithdrawal, &locks.DstPublicWithdrawal, &locks.DstCancellation} {
+		*stage += dstShift
+	}
+	if srcShift == 0 {
+		return
+	}
+
+	// stretching cannot fail
+	_ = updatePresets(quote, func(_ common.PresetEnum, preset common.PresetData) (common.PresetData, error) {
+		return stretchAuction(preset, srcShift), nil
+	})
+}
+
+// stretchAuction lengthens a preset's auction by extra seconds, scaling the
+// delays of its price points along so the curve keeps its shape.
+func stretchAuction(preset common.PresetData, extra int64) common.PresetData {
+	duration := preset.AuctionDuration + extra
+
+	points := make([]common.AuctionPoint, len(preset.Points))
+	for i, p := range preset.Points {
+		points[i] = p
+		if preset.AuctionDuration > 0 {
+			points[i].Delay = p.Delay * duration / preset.AuctionDuration
+		}
+	}
+
+	preset.AuctionDuration = duration
+	preset.Points = points
+	return preset
+}
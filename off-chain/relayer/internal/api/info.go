@@ -0,0 +1,85 @@
+package api
+
+import (
+	"net/http"
+	"relayer/internal/common"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetChains lists the served chains with the escrow factories or Move
+// packages of their routes and the chains those routes lead to.
+func (s *APIServer) GetChains(c *gin.Context) {
+	chains := make(map[string]*common.ChainInfo)
+	for _, id := range common.SupportedChains() {
+		info := &common.ChainInfo{ChainID: id, CAIP2: id.CAIP2(), VM: "evm", Destinations: []string{}}
+		if id.IsMove() {
+			info.VM = "move"
+		}
+		chains[id.String()] = info
+	}
+
+	addFactory := func(chain, factory string) {
+		info, ok := chains[chain]
+		if !ok || factory == "" {
+			return
+		}
+		factories := &info.EscrowFactories
+		if info.VM == "move" {
+			factories = &info.PackageIDs
+		}
+		for _, f := range *factories {
+			if strings.EqualFold(f, factory) {
+				return
+			}
+		}
+		*factories = append(*factories, factory)
+	}
+
+	for _, r := range s.manager.Routes().Routes() {
+		if !r.Enabled {
+			continue
+		}
+		addFactory(r.SrcChain, r.SrcEscrowFactory)
+		addFactory(r.DstChain, r.DstEscrowFactory)
+		if info, ok := chains[r.SrcChain]; ok {
+			info.Destinations = append(info.Destinations, r.DstChain)
+		}
+	}
+
+	out := make([]common.ChainInfo, 0, len(chains))
+	for _, info := range chains {
+		out = append(out, *info)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ChainID < out[j].ChainID })
+
+	c.JSON(http.StatusOK, gin.H{"chains": out})
+}
+
+// GetTokens lists the tokens quoted per chain, or those of ?chain=. Chains
+// without a list quote every token and are absent.
+func (s *APIServer) GetTokens(c *gin.Context) {
+	tokens := s.manager.Routes().Tokens()
+
+	if v := c.Query("chain"); v != "" {
+		chain, err := common.NormalizeChain(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		filtered := tokens[:0:0]
+		for _, tok := range tokens {
+			if tok.Chain == chain {
+				filtered = append(filtered, tok)
+			}
+		}
+		tokens = filtered
+	}
+	if tokens == nil {
+		tokens = []common.TokenInfo{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tokens": tokens})
+}
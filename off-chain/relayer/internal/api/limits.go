@@ -0,0 +1,43 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// DefaultReadHeaderTimeout, DefaultReadTimeout, DefaultWriteTimeout and
+	// DefaultIdleTimeout bound the phases of an API connection, overridable
+	// with the HTTP_*_TIMEOUT_SECONDS variables
+	DefaultReadHeaderTimeout = 5 * time.Second
+	DefaultReadTimeout       = 10 * time.Second
+	DefaultWriteTimeout      = 30 * time.Second
+	DefaultIdleTimeout       = time.Minute
+	// DefaultMaxHeaderBytes bounds the request headers, overridable with
+	// HTTP_MAX_HEADER_BYTES
+	DefaultMaxHeaderBytes = 64 << 10
+)
+
+// limitBody rejects requests declaring a body larger than the limit returned
+// by max, read at every request so config reloads apply, and stops reading
+// bodies past it. Reads past the limit fail with an *http.MaxBytesError.
+func limitBody(max func() int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit := max()
+		if c.Request.ContentLength > limit {
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Request body too large"})
+			return
+		}
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+		c.Next()
+	}
+}
+
+// tooLarge reports whether err is a body read past limitBody's limit.
+func tooLarge(err error) bool {
+	var maxErr *http.MaxBytesError
+	return errors.As(err, &maxErr)
+}
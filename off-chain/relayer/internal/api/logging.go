@@ -0,0 +1,126 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"relayer/internal/logging"
+	"relayer/internal/metrics"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxLoggedBody caps how much of a request or response body is logged.
+const maxLoggedBody = 2048
+
+// redactedFields are JSON keys whose string values never reach the logs in full.
+var redactedFields = map[string]bool{
+	"secret":           true,
+	"secrets":          true,
+	"signature":        true,
+	"relayersignature": true,
+	"apikey":           true,
+	"exporttoken":      true,
+}
+
+// bodyRecorder tees the response body so a sampled request can log it.
+type bodyRecorder struct {
+	gin.ResponseWriter
+	body *bytes.Buffer
+}
+
+func (w *bodyRecorder) Write(b []byte) (int, error) {
+	if room := maxLoggedBody - w.body.Len(); room > 0 {
+		w.body.Write(b[:min(len(b), room)])
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// requestLogger logs method, route, status and latency of every request and
+// records them in metrics. A config.BodySampleRate share of requests also has
+// its bodies logged, with secrets and signatures redacted.
+func (s *APIServer) requestLogger() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		sampled := rand.Float64() < s.manager.Config().BodySampleRate
+
+		var reqBody []byte
+		var recorder *bodyRecorder
+		if sampled {
+			if c.Request.Body != nil {
+				reqBody, _ = io.ReadAll(io.LimitReader(c.Request.Body, maxLoggedBody))
+				c.Request.Body = readCloser{io.MultiReader(bytes.NewReader(reqBody), c.Request.Body), c.Request.Body}
+			}
+			recorder = &bodyRecorder{ResponseWriter: c.Writer, body: new(bytes.Buffer)}
+			c.Writer = recorder
+		}
+
+		c.Next()
+
+		latency := time.Since(start)
+		status := c.Writer.Status()
+		metrics.ObserveHTTP(c.Request.Method, c.FullPath(), status, latency)
+
+		path := c.Request.URL.Path
+		if c.Request.URL.RawQuery != "" {
+			path += "?" + c.Request.URL.RawQuery
+		}
+		s.logger.Printf("%s %s %d %s from %s", c.Request.Method, path, status, latency, c.ClientIP())
+
+		if sampled {
+			s.logger.Printf("%s %s request body: %s", c.Request.Method, c.Request.URL.Path, redactBody(reqBody))
+			s.logger.Printf("%s %s response body: %s", c.Request.Method, c.Request.URL.Path, redactBody(recorder.body.Bytes()))
+		}
+	}
+}
+
+// readCloser replays the logged prefix of a request body before the rest.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// redactBody renders a body for the logs, shortening sensitive JSON values.
+// Bodies that are not JSON are reduced to their length.
+func redactBody(body []byte) string {
+	if len(body) == 0 {
+		return "<empty>"
+	}
+
+	var v any
+	if err := json.Unmarshal(body, &v); err != nil {
+		return fmt.Sprintf("<%d bytes, not JSON>", len(body))
+	}
+
+	out, err := json.Marshal(redactValue(v, false))
+	if err != nil {
+		return "<unloggable>"
+	}
+	return string(out)
+}
+
+func redactValue(v any, sensitive bool) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, field := range v {
+			v[k] = redactValue(field, redactedFields[strings.ToLower(k)])
+		}
+		return v
+	case []any:
+		for i, item := range v {
+			v[i] = redactValue(item, sensitive)
+		}
+		return v
+	case string:
+		if sensitive {
+			return logging.Redact(v)
+		}
+		return v
+	default:
+		return v
+	}
+}
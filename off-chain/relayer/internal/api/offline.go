@@ -0,0 +1,99 @@
+package api
+
+import (
+	"fmt"
+	"math/big"
+	"relayer/internal/auction"
+	"relayer/internal/common"
+	"relayer/internal/config"
+
+	"github.com/google/uuid"
+)
+
+// RateStartAuctionIn is the delay of the auctions of rate table quotes, in
+// seconds after the order's submission.
+const RateStartAuctionIn = 24
+
+// rateAuctionDurations are the auction durations of the rate table presets,
+// in seconds, those of the 1inch API.
+var rateAuctionDurations = map[common.PresetEnum]int64{
+	common.PresetFast:   180,
+	common.PresetMedium: 360,
+	common.PresetSlow:   600,
+}
+
+// rateTimeLocks are the timelocks of rate table quotes, in seconds after the
+// escrow deployment; on Sui corridors they are fitted to finality like those
+// of any quote.
+var rateTimeLocks = common.TimeLocksRaw{
+	SrcWithdrawal:         36,
+	SrcPublicWithdrawal:   336,
+	SrcCancellation:       492,
+	SrcPublicCancellation: 612,
+	DstWithdrawal:         36,
+	DstPublicWithdrawal:   300,
+	DstCancellation:       420,
+}
+
+// rateQuote quotes a token pair from its rate table: the amount is converted
+// at the table's rate less its spread, and every preset runs a linear auction
+// from auctionStartBps above to auctionEndBps below that.
+func rateQuote(params common.QuoteRequestParams, table config.RateTable) (*common.Quote, error) {
+	amount, ok := new(big.Int).SetString(params.Amount, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid amount %q", params.Amount)
+	}
+	rate, _ := new(big.Rat).SetString(table.Rate)
+
+	converted := new(big.Rat).Mul(new(big.Rat).SetInt(amount), rate)
+	converted.Mul(converted, new(big.Rat).SetFrac(pow10(table.DstDecimals), pow10(table.SrcDecimals)))
+	market := new(big.Int).Quo(converted.Num(), converted.Denom())
+
+	quoted := scaleBps(market, 10_000-table.SpreadBps)
+	start := scaleBps(quoted, 10_000+table.AuctionStartBps)
+	end := scaleBps(quoted, 10_000-table.AuctionEndBps)
+	if end.Sign() <= 0 {
+		return nil, fmt.Errorf("amount %s is too small to quote", params.Amount)
+	}
+
+	// the rate bump taking the order's minimum to the auction start
+	bump := new(big.Int).Sub(start, end)
+	bump.Mul(bump, big.NewInt(auction.RateBumpDenominator))
+	bump.Quo(bump, end)
+
+	presets := make(common.QuoterPresets, len(rateAuctionDurations))
+	for name, duration := range rateAuctionDurations {
+		preset := common.PresetData{
+			AuctionDuration:    duration,
+			StartAuctionIn:     RateStartAuctionIn,
+			InitialRateBump:    float64(bump.Int64()),
+			AuctionStartAmount: start.String(),
+			StartAmount:        quoted.String(),
+			AuctionEndAmount:   end.String(),
+			CostInDstToken:     "0",
+			SecretsCount:       1,
+		}
+		preset.GasCost.GasPriceEstimate = "0"
+		presets[name] = preset
+	}
+
+	return &common.Quote{
+		QuoteID:           uuid.New(),
+		SrcTokenAmount:    params.Amount,
+		DstTokenAmount:    quoted.String(),
+		Presets:           presets,
+		RecommendedPreset: common.PresetFast,
+		TimeLocks:         rateTimeLocks,
+		SrcSafetyDeposit:  table.SrcSafetyDeposit,
+		DstSafetyDeposit:  table.DstSafetyDeposit,
+	}, nil
+}
+
+func scaleBps(amount *big.Int, bps uint64) *big.Int {
+	scaled := new(big.Int).Mul(amount, new(big.Int).SetUint64(bps))
+	return scaled.Quo(scaled, big.NewInt(10_000))
+}
+
+func pow10(decimals uint8) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+}
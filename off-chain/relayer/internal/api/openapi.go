@@ -0,0 +1,657 @@
+package api
+
+import (
+	"encoding"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"reflect"
+	"relayer/internal/access"
+	"relayer/internal/accounting"
+	"relayer/internal/analytics"
+	"relayer/internal/common"
+	"relayer/internal/config"
+	"relayer/internal/session"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// The OpenAPI document is built from the registered routes, so every route is
+// listed; the operations table below describes their parameters and bodies,
+// whose schemas are reflected from the Go types the handlers encode.
+
+// OpenAPIPath is where the document is served.
+const OpenAPIPath = "/openapi.json"
+
+// responses the handlers build with gin.H, named for the spec only
+type (
+	feeSummaryResponse struct {
+		FeeBps uint64             `json:"feeBps"`
+		Fees   []accounting.Entry `json:"fees"`
+	}
+	adminOrdersResponse struct {
+		Orders []common.AdminOrder `json:"orders"`
+	}
+	chainHealthResponse struct {
+		Chains []common.ChainHealth `json:"chains"`
+	}
+	resolverStatsResponse struct {
+		Resolvers []common.ResolverStats `json:"resolvers"`
+	}
+	safetyDepositsResponse struct {
+		SafetyDeposits []common.SafetyDepositQuote `json:"safetyDeposits"`
+	}
+	upstreamKeysResponse struct {
+		Keys []common.UpstreamKeyUsage `json:"keys"`
+	}
+	chainsResponse struct {
+		Chains []common.ChainInfo `json:"chains"`
+	}
+	tokensResponse struct {
+		Tokens []common.TokenInfo `json:"tokens"`
+	}
+	verifiedResponse struct {
+		Verified bool `json:"verified"`
+	}
+	releasedResponse struct {
+		Released bool `json:"released"`
+	}
+	quarantinedResponse struct {
+		Orders []common.QuarantinedOrder `json:"orders"`
+	}
+	approvedResponse struct {
+		Approved bool `json:"approved"`
+	}
+	rejectedResponse struct {
+		Rejected bool `json:"rejected"`
+	}
+	surplusResponse struct {
+		Surplus []analytics.TokenSurplus `json:"surplus"`
+	}
+	latencyResponse struct {
+		Since  string                   `json:"since"`
+		Stages []analytics.StageLatency `json:"stages"`
+	}
+	conversionResponse struct {
+		Conversion []analytics.PairConversion `json:"conversion"`
+	}
+	integratorFeesResponse struct {
+		Fees []analytics.IntegratorFee `json:"fees"`
+	}
+	errorResponse struct {
+		Error string `json:"error"`
+	}
+)
+
+type apiParam struct {
+	name        string
+	description string
+	required    bool
+}
+
+type apiOperation struct {
+	summary string
+	query   []apiParam
+	body    any
+	// responses are alternatives, e.g. a single or a multi-leg quote
+	responses []any
+	// roles allowed besides admin, nil for public routes
+	roles []access.Role
+	// only served to the order's maker with PRIVATE_ORDER_STATUS
+	maker bool
+}
+
+var quoteParams = []apiParam{
+	{"srcChain", "source chain id, decimal or CAIP-2", true},
+	{"dstChain", "destination chain id, decimal or CAIP-2", true},
+	{"srcTokenAddress", "", true},
+	{"dstTokenAddress", "", true},
+	{"amount", "source amount in base units", true},
+	{"walletAddress", "maker address", true},
+	{"dstReceiver", "receiver on the destination chain, defaults to the maker", false},
+	{"integratorFee", "integrator fee in bps, requires feeReceiver", false},
+	{"feeReceiver", "", false},
+}
+
+var (
+	operatorRoles   = []access.Role{access.RoleOperator}
+	analyticsRoles  = []access.Role{access.RoleOperator, access.RoleAnalytics}
+	adminOnlyRoles  = []access.Role{}
+	integratorRoles = []access.Role{access.RoleOperator, access.RoleAnalytics, access.RoleIntegrator}
+)
+
+// apiOperations describes the routes by "METHOD path" as gin registers them.
+var apiOperations = map[string]apiOperation{
+	"GET " + OpenAPIPath: {
+		summary:   "This document",
+		responses: []any{map[string]any{}},
+	},
+	"GET /": {
+		summary: "Broadcast a test message to the WS clients",
+		query:   []apiParam{{"msg", "", false}},
+	},
+	"GET /quoter/v1.0/quote/receive": {
+		summary:   "Quote a cross-chain swap, multi-leg when routed through a hub chain",
+		query:     quoteParams,
+		responses: []any{common.Quote{}, common.MultiLegQuote{}},
+	},
+	"GET /quoter/v1.0/estimate": {
+		summary:   "Preview the price of a quote request without storing a quote",
+		query:     quoteParams,
+		responses: []any{common.QuoteEstimate{}},
+	},
+	"POST /relayer/v1.0/submit": {
+		summary: "Submit a signed order for a quote",
+		body:    common.Order{},
+	},
+	"POST /relayer/v1.0/submit/secret": {
+		summary: "Reveal a secret of an order",
+		body:    common.Secret{},
+	},
+	"GET /orders/v1.0/order/ready-to-accept-secret-fills/:orderHash": {
+		summary:   "Take the verified fills awaiting the maker's secrets",
+		responses: []any{common.ReadyToAcceptSecretFills{}},
+		maker:     true,
+	},
+	"GET /orders/v1.0/order/status/:orderHash": {
+		summary:   "Get an order's status",
+		responses: []any{common.OrderStatus{}},
+		maker:     true,
+	},
+	"GET /orders/v1.0/order/cancellation-data/:orderHash": {
+		summary:   "Get the escrows and immutables needed to cancel an order's fills",
+		responses: []any{common.CancellationData{}},
+		maker:     true,
+	},
+	"GET /orders/v1.0/order/escrow/:orderHash": {
+		summary:   "Get the escrows of an order's fills with their immutables, also after the order settled",
+		responses: []any{common.OrderEscrows{}},
+		maker:     true,
+	},
+	"GET /orders/v1.0/order/eta/:orderHash": {
+		summary:   "Estimate when an order's swap completes and its next milestone",
+		responses: []any{common.OrderETA{}},
+		maker:     true,
+	},
+	"GET /orders/v1.0/search": {
+		summary: "Search the stored orders, newest first",
+		query: []apiParam{
+			{"status", "comma separated statuses", false},
+			{"srcChain", "decimal or CAIP-2 chain id", false},
+			{"dstChain", "decimal or CAIP-2 chain id", false},
+			{"token", "maker or taker asset", false},
+			{"maker", "", false},
+			{"from", "RFC 3339 time, submitted at or after", false},
+			{"to", "RFC 3339 time, submitted before", false},
+			{"minAmount", "making amount in base units", false},
+			{"maxAmount", "making amount in base units", false},
+			{"sort", "submittedAt or makingAmount", false},
+			{"order", "asc or desc", false},
+			{"cursor", "nextCursor of the previous page", false},
+			{"limit", "page size, at most 500", false},
+		},
+		responses: []any{common.OrderSearchResult{}},
+		roles:     operatorRoles,
+	},
+	"POST /orders/v1.0/session/challenge": {
+		summary:   "Get a message for a maker to sign in with",
+		body:      common.SessionChallengeRequest{},
+		responses: []any{session.Challenge{}},
+	},
+	"POST /orders/v1.0/session": {
+		summary:   "Trade a signed challenge for a maker session token",
+		body:      common.SessionRequest{},
+		responses: []any{common.Session{}},
+	},
+	"POST /relayer/v1.0/secrets": {
+		summary:   "Generate and hold the secrets of a quote",
+		body:      common.SecretsRequest{},
+		responses: []any{common.SecretSet{}},
+	},
+	"POST /relayer/v1.0/secrets/:secretsId/export": {
+		summary:   "Export a held secret set",
+		body:      common.ExportSecretsRequest{},
+		responses: []any{common.SecretSet{}},
+	},
+	"GET /info/v1.0/chains": {
+		summary:   "List the served chains with their escrow factories and destinations",
+		responses: []any{chainsResponse{}},
+	},
+	"GET /info/v1.0/tokens": {
+		summary:   "List the tokens quoted per chain",
+		query:     []apiParam{{"chain", "decimal or CAIP-2 id of the chain to list", false}},
+		responses: []any{tokensResponse{}},
+	},
+
+	"GET /admin/v1.0/fees": {
+		summary:   "Protocol fees accrued per chain and token",
+		query:     []apiParam{{"format", "csv for a CSV export", false}},
+		responses: []any{feeSummaryResponse{}},
+		roles:     operatorRoles,
+	},
+	"GET /admin/v1.0/orders": {
+		summary:   "List the orders in memory",
+		responses: []any{adminOrdersResponse{}},
+		roles:     operatorRoles,
+	},
+	"GET /admin/v1.0/orders/:orderHash": {
+		summary:   "Inspect an order",
+		responses: []any{common.AdminOrder{}},
+		roles:     operatorRoles,
+	},
+	"POST /admin/v1.0/orders/:orderHash/reverify": {
+		summary:   "Re-verify a fill from its escrow transactions",
+		body:      common.ReverifyRequest{},
+		responses: []any{verifiedResponse{}},
+		roles:     operatorRoles,
+	},
+	"POST /admin/v1.0/orders/:orderHash/release": {
+		summary:   "Release a fill without verification",
+		body:      common.ReadyToAcceptSecretFill{},
+		responses: []any{releasedResponse{}},
+		roles:     adminOnlyRoles,
+	},
+	"GET /admin/v1.0/quotes/:quoteId": {
+		summary:   "Inspect a quote",
+		responses: []any{common.AdminQuote{}},
+		roles:     operatorRoles,
+	},
+	"GET /admin/v1.0/quarantine": {
+		summary:   "Orders held back for a price off the oracle's, awaiting approval",
+		responses: []any{quarantinedResponse{}},
+		roles:     operatorRoles,
+	},
+	"POST /admin/v1.0/quarantine/:orderHash/approve": {
+		summary:   "Broadcast a quarantined order",
+		responses: []any{approvedResponse{}},
+		roles:     adminOnlyRoles,
+	},
+	"POST /admin/v1.0/quarantine/:orderHash/reject": {
+		summary:   "Drop a quarantined order",
+		responses: []any{rejectedResponse{}},
+		roles:     adminOnlyRoles,
+	},
+	"GET /admin/v1.0/chains": {
+		summary:   "Chain endpoint health",
+		responses: []any{chainHealthResponse{}},
+		roles:     operatorRoles,
+	},
+	"GET /admin/v1.0/resolvers": {
+		summary:   "Resolver connection uptime, ping round trips and scores, best first",
+		responses: []any{resolverStatsResponse{}},
+		roles:     operatorRoles,
+	},
+	"GET /admin/v1.0/safety-deposits": {
+		summary:   "Safety deposit parameters per chain and the deposits quoted at the current gas price",
+		responses: []any{safetyDepositsResponse{}},
+		roles:     operatorRoles,
+	},
+	"GET /admin/v1.0/upstream-keys": {
+		summary:   "Requests and throttling per 1inch API key",
+		responses: []any{upstreamKeysResponse{}},
+		roles:     operatorRoles,
+	},
+	"GET /admin/v1.0/config": {
+		summary:   "The running configuration",
+		responses: []any{config.Config{}},
+		roles:     operatorRoles,
+	},
+	"POST /admin/v1.0/config/reload": {
+		summary:   "Reload the configuration file",
+		responses: []any{config.Config{}},
+		roles:     adminOnlyRoles,
+	},
+	"GET /admin/v1.0/metrics": {
+		summary:   "Expvar metrics",
+		responses: []any{map[string]any{}},
+		roles:     operatorRoles,
+	},
+
+	"GET /analytics/v1.0/surplus": {
+		summary:   "Surplus per token, or one order's with ?orderHash=",
+		query:     []apiParam{{"orderHash", "", false}},
+		responses: []any{surplusResponse{}, analytics.SurplusRecord{}},
+		roles:     analyticsRoles,
+	},
+	"GET /analytics/v1.0/latency": {
+		summary:   "Latency percentiles between order lifecycle stages",
+		query:     []apiParam{{"since", "lookback like 6h or an RFC 3339 time", false}},
+		responses: []any{latencyResponse{}},
+		roles:     analyticsRoles,
+	},
+	"GET /analytics/v1.0/conversion": {
+		summary:   "Quotes ordered against and expired unused per chain and token pair",
+		responses: []any{conversionResponse{}},
+		roles:     analyticsRoles,
+	},
+	"GET /analytics/v1.0/integrators": {
+		summary:   "Integrator fees, integrators only see their own",
+		query:     []apiParam{{"integrator", "integrator id", false}},
+		responses: []any{integratorFeesResponse{}},
+		roles:     integratorRoles,
+	},
+}
+
+// openAPIHandler serves the OpenAPI document of router's routes, built on
+// the first request once all routes are registered.
+func openAPIHandler(router *gin.Engine) gin.HandlerFunc {
+	spec := sync.OnceValue(func() map[string]any {
+		return openAPISpec(router.Routes())
+	})
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, spec())
+	}
+}
+
+// openAPISpec builds the OpenAPI 3 document of routes.
+func openAPISpec(routes gin.RoutesInfo) map[string]any {
+	schemas := newSchemaRegistry()
+	paths := map[string]map[string]any{}
+
+	for _, route := range routes {
+		op, documented := apiOperations[route.Method+" "+route.Path]
+
+		path, pathParams := openAPIPath(route.Path)
+		params := make([]map[string]any, 0, len(pathParams)+len(op.query))
+		for _, p := range pathParams {
+			params = append(params, map[string]any{
+				"name": p, "in": "path", "required": true, "schema": map[string]any{"type": "string"},
+			})
+		}
+		for _, p := range op.query {
+			param := map[string]any{
+				"name": p.name, "in": "query", "required": p.required, "schema": map[string]any{"type": "string"},
+			}
+			if p.description != "" {
+				param["description"] = p.description
+			}
+			params = append(params, param)
+		}
+
+		operation := map[string]any{
+			"operationId": operationID(route),
+			"tags":        []string{operationTag(path)},
+			"responses":   openAPIResponses(schemas, op),
+		}
+		if documented {
+			operation["summary"] = op.summary
+		}
+		if len(params) > 0 {
+			operation["parameters"] = params
+		}
+		if op.body != nil {
+			operation["requestBody"] = map[string]any{
+				"required": true,
+				"content":  map[string]any{"application/json": map[string]any{"schema": schemas.schema(reflect.TypeOf(op.body))}},
+			}
+		}
+		if op.roles != nil {
+			roles := append([]string{string(access.RoleAdmin)}, rolesOf(op.roles)...)
+			// bearer schemes take no scopes, the roles go in an extension
+			operation["security"] = []map[string][]string{{"bearer": {}}}
+			operation["description"] = "Roles: " + strings.Join(roles, ", ")
+			operation["x-roles"] = roles
+		}
+
+		if op.maker {
+			// optional, an empty requirement stands for public deployments
+			operation["security"] = []map[string][]string{{"makerSession": {}}, {}}
+			operation["description"] = "Requires a maker session when PRIVATE_ORDER_STATUS is set"
+		}
+
+		if paths[path] == nil {
+			paths[path] = map[string]any{}
+		}
+		paths[path][strings.ToLower(route.Method)] = operation
+	}
+
+	return map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":   "Fission relayer",
+			"version": "1.0",
+		},
+		"paths": paths,
+		"components": map[string]any{
+			"schemas": schemas.defs,
+			"securitySchemes": map[string]any{
+				"bearer": map[string]any{
+					"type":        "http",
+					"scheme":      "bearer",
+					"description": "An access file API key, ADMIN_API_KEY or an HS256 JWT",
+				},
+				"makerSession": map[string]any{
+					"type":        "http",
+					"scheme":      "bearer",
+					"description": "A maker session token from POST /orders/v1.0/session",
+				},
+			},
+		},
+	}
+}
+
+func openAPIResponses(schemas *schemaRegistry, op apiOperation) map[string]any {
+	errorContent := map[string]any{"application/json": map[string]any{"schema": schemas.schema(reflect.TypeOf(errorResponse{}))}}
+	responses := map[string]any{
+		"default": map[string]any{"description": "Error", "content": errorContent},
+	}
+
+	ok := map[string]any{"description": "OK"}
+	switch len(op.responses) {
+	case 0:
+	case 1:
+		ok["content"] = map[string]any{"application/json": map[string]any{"schema": schemas.schema(reflect.TypeOf(op.responses[0]))}}
+	default:
+		oneOf := make([]any, len(op.responses))
+		for i, r := range op.responses {
+			oneOf[i] = schemas.schema(reflect.TypeOf(r))
+		}
+		ok["content"] = map[string]any{"application/json": map[string]any{"schema": map[string]any{"oneOf": oneOf}}}
+	}
+	responses["200"] = ok
+	return responses
+}
+
+// openAPIPath turns gin's /orders/:orderHash into /orders/{orderHash}.
+func openAPIPath(path string) (string, []string) {
+	var params []string
+	segments := strings.Split(path, "/")
+	for i, seg := range segments {
+		if strings.HasPrefix(seg, ":") || strings.HasPrefix(seg, "*") {
+			params = append(params, seg[1:])
+			segments[i] = "{" + seg[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/"), params
+}
+
+// operationID is the handler's method name, e.g. GetOrderStatus, or the
+// method and path for anonymous handlers.
+func operationID(route gin.RouteInfo) string {
+	name := strings.TrimSuffix(route.Handler[strings.LastIndex(route.Handler, ".")+1:], "-fm")
+	if !strings.HasPrefix(name, "func") {
+		return name
+	}
+
+	id := strings.ToLower(route.Method)
+	for _, seg := range strings.Split(route.Path, "/") {
+		seg = strings.TrimLeft(strings.ReplaceAll(seg, ".", ""), ":*")
+		if seg != "" {
+			id += strings.ToUpper(seg[:1]) + seg[1:]
+		}
+	}
+	return id
+}
+
+// operationTag groups operations by their first path segment: quoter,
+// relayer, orders, admin or analytics.
+func operationTag(path string) string {
+	tag, _, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	if tag == "" || strings.Contains(tag, ".") {
+		return "default"
+	}
+	return tag
+}
+
+func rolesOf(roles []access.Role) []string {
+	names := make([]string, len(roles))
+	for i, r := range roles {
+		names[i] = string(r)
+	}
+	return names
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	bigIntType        = reflect.TypeOf(big.Int{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	chainIDType       = reflect.TypeOf(common.ChainID(0))
+	durationType      = reflect.TypeOf(config.Duration(0))
+	rawJSONType       = reflect.TypeOf([]byte(nil))
+	emptyIfceType     = reflect.TypeOf((*any)(nil)).Elem()
+)
+
+// schemaRegistry reflects JSON schemas from Go types, registering named
+// structs as components referenced by $ref.
+type schemaRegistry struct {
+	defs map[string]any
+}
+
+func newSchemaRegistry() *schemaRegistry {
+	return &schemaRegistry{defs: map[string]any{}}
+}
+
+func (r *schemaRegistry) schema(t reflect.Type) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t {
+	case timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case bigIntType:
+		return map[string]any{"type": "integer"}
+	case chainIDType:
+		return map[string]any{"type": "integer"}
+	case durationType:
+		return map[string]any{"type": "string", "description": "Go duration, e.g. 30s"}
+	case rawJSONType:
+		return map[string]any{"type": "string", "format": "byte"}
+	case emptyIfceType:
+		return map[string]any{}
+	}
+	// anything else encoding itself is left unconstrained, except for text
+	// marshalers such as uuids, which encode as strings
+	if t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType) {
+		return map[string]any{"type": "string"}
+	}
+	if t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType) {
+		return map[string]any{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": r.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": r.schema(t.Elem())}
+	case reflect.Struct:
+		return r.structSchema(t)
+	}
+	return map[string]any{}
+}
+
+func (r *schemaRegistry) structSchema(t reflect.Type) map[string]any {
+	name := schemaName(t)
+	ref := map[string]any{"$ref": "#/components/schemas/" + name}
+	if name == "" {
+		return r.object(t)
+	}
+	if _, ok := r.defs[name]; !ok {
+		// placeholder first, so recursive types resolve to the ref
+		r.defs[name] = map[string]any{}
+		r.defs[name] = r.object(t)
+	}
+	return ref
+}
+
+func (r *schemaRegistry) object(t reflect.Type) map[string]any {
+	properties := map[string]any{}
+	var required []string
+	r.fields(t, properties, &required)
+
+	schema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		sort.Strings(required)
+		schema["required"] = required
+	}
+	return schema
+}
+
+func (r *schemaRegistry) fields(t reflect.Type, properties map[string]any, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" || (!f.IsExported() && !f.Anonymous) {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		// embedded structs without a name are flattened, as encoding/json does
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				r.fields(ft, properties, required)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+		if strings.Contains(opts, "string") {
+			properties[name] = map[string]any{"type": "string"}
+		} else {
+			properties[name] = r.schema(f.Type)
+		}
+		if !strings.Contains(opts, "omitempty") && f.Type.Kind() != reflect.Pointer {
+			*required = append(*required, name)
+		}
+	}
+}
+
+// schemaName qualifies the type with its package, e.g. common.Order becomes
+// CommonOrder, since several packages share type names.
+func schemaName(t reflect.Type) string {
+	if t.Name() == "" {
+		return ""
+	}
+	name := t.Name()
+	if generic := strings.IndexByte(name, '['); generic >= 0 {
+		name = name[:generic]
+	}
+	if t.PkgPath() == "relayer/internal/api" {
+		return strings.ToUpper(name[:1]) + name[1:]
+	}
+	pkg := t.PkgPath()[strings.LastIndex(t.PkgPath(), "/")+1:]
+	return strings.ToUpper(pkg[:1]) + pkg[1:] + strings.ToUpper(name[:1]) + name[1:]
+}
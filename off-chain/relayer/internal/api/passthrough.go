@@ -0,0 +1,77 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"relayer/internal/common"
+	"time"
+)
+
+// DefaultUpstreamRelayerURL is the order submission endpoint of the official
+// 1inch Fusion+ relayer.
+const DefaultUpstreamRelayerURL = "https://api.1inch.dev/fusion-plus/relayer/v1.0/submit"
+
+// upstreamBodyLimit bounds how much of the upstream answer is recorded.
+const upstreamBodyLimit = 4096
+
+// upstreamOrder is a submission to the 1inch relayer API: the order without
+// the relayer's extensions.
+type upstreamOrder struct {
+	Order        common.LimitOrder `json:"order"`
+	SrcChainID   common.ChainID    `json:"srcChainId"`
+	Signature    string            `json:"signature"`
+	Extension    string            `json:"extension"`
+	QuoteID      string            `json:"quoteId"`
+	SecretHashes []string          `json:"secretHashes,omitempty"`
+}
+
+// forwardOrder submits an accepted order to the official 1inch relayer as
+// well, in passthrough mode (UPSTREAM_SUBMIT=true) and when its src chain is
+// served by Fusion+, and records the answer on the order. Upstream failures
+// do not affect the order here.
+func (s *APIServer) forwardOrder(order common.Order, orderHash string) {
+	if s.passthroughURL == "" || !order.SrcChainID.IsFusionPlus() {
+		return
+	}
+
+	body, err := json.Marshal(upstreamOrder{
+		Order:        order.LimitOrder,
+		SrcChainID:   order.SrcChainID,
+		Signature:    order.Signature,
+		Extension:    order.Extension,
+		QuoteID:      order.QuoteID.String(),
+		SecretHashes: order.SecretHashes,
+	})
+	if err != nil {
+		s.logger.Printf("Failed to encode order %s for the 1inch relayer: %v", orderHash, err)
+		return
+	}
+
+	submission := common.UpstreamSubmission{SubmittedAt: time.Now()}
+	defer func() {
+		s.manager.RecordUpstream(orderHash, submission)
+	}()
+
+	req, err := http.NewRequest(http.MethodPost, s.passthroughURL, bytes.NewReader(body))
+	if err != nil {
+		submission.Error = err.Error()
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.callUpstream(req)
+	if err != nil {
+		submission.Error = err.Error()
+		s.logger.Printf("Failed to forward order %s to the 1inch relayer: %v", orderHash, err)
+		return
+	}
+	defer resp.Body.Close()
+
+	answer, _ := io.ReadAll(io.LimitReader(resp.Body, upstreamBodyLimit))
+	submission.Status = resp.StatusCode
+	submission.Body = string(answer)
+	s.logger.Printf("Forwarded order %s to the 1inch relayer: %d", orderHash, resp.StatusCode)
+}
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"relayer/internal/common"
+	"relayer/internal/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// DefaultSubmitQueueSize and DefaultSubmitWorkers bound order submissions
+	// waiting and in progress, overridable with SUBMIT_QUEUE_SIZE and SUBMIT_WORKERS
+	DefaultSubmitQueueSize = 256
+	DefaultSubmitWorkers   = 8
+	// SubmitRetryAfterSeconds is the Retry-After sent when the queue is full
+	SubmitRetryAfterSeconds = 1
+)
+
+// submitJob is one queued order and, once done is closed, its response.
+type submitJob struct {
+	order  common.Order
+	status int
+	body   gin.H
+	done   chan struct{}
+}
+
+// submitQueue hands submitted orders to a fixed pool of workers so a burst of
+// submissions is processed at a bounded rate and the excess is shed.
+type submitQueue struct {
+	jobs    chan *submitJob
+	process func(common.Order) (int, gin.H)
+}
+
+func newSubmitQueue(size, workers int, process func(common.Order) (int, gin.H)) *submitQueue {
+	q := &submitQueue{
+		jobs:    make(chan *submitJob, size),
+		process: process,
+	}
+	for range workers {
+		go q.work()
+	}
+
+	return q
+}
+
+// enqueue queues an order without blocking. It returns false when the queue
+// is full and the order must be rejected.
+func (q *submitQueue) enqueue(order common.Order) (*submitJob, bool) {
+	job := &submitJob{order: order, done: make(chan struct{})}
+
+	select {
+	case q.jobs <- job:
+		metrics.SubmitQueueDepth.Add(1)
+		return job, true
+	default:
+		metrics.SubmitQueueShed.Add(1)
+		return nil, false
+	}
+}
+
+func (q *submitQueue) work() {
+	for job := range q.jobs {
+		metrics.SubmitQueueDepth.Add(-1)
+		job.status, job.body = q.process(job.order)
+		close(job.done)
+	}
+}
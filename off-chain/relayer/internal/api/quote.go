@@ -0,0 +1,293 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"relayer/internal/auction"
+	"relayer/internal/common"
+	"relayer/internal/manager"
+	"relayer/internal/routing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// quoteError carries the HTTP status a failed quote should be reported with.
+type quoteError struct {
+	status int
+	msg    string
+}
+
+func (e *quoteError) Error() string {
+	return e.msg
+}
+
+// ErrCodeQuoteExpired is the error code of order submissions against a quote
+// past its expiresAt.
+const ErrCodeQuoteExpired = "QUOTE_EXPIRED"
+
+// QuoteRetryAfterSeconds is the Retry-After sent when the quote map is full
+// of live quotes.
+const QuoteRetryAfterSeconds = 5
+
+func quoteErrorStatus(err error) int {
+	var qe *quoteError
+	if errors.As(err, &qe) {
+		return qe.status
+	}
+	return http.StatusInternalServerError
+}
+
+// quoteRoute fetches a quote for a single route, from the pair's rate table,
+// the 1inch Fusion+ API or the dev presets, sets the configured chains'
+// safety deposits, applies the protocol and integrator fees, fits Sui
+// corridor timelocks and auctions to the chains' finality, pins the route's
+// escrow factories and hashlock algorithm and stamps the expiry of the
+// recommended preset. With offlineQuotes only rate tables are used.
+func (s *APIServer) quoteRoute(queryParams common.QuoteRequestParams, route routing.Route) (*common.Quote, error) {
+	var quoteResponse common.Quote
+	cfg := s.manager.Config()
+	table, offline := cfg.RateTable(queryParams.SrcChain, queryParams.SrcTokenAddress, queryParams.DstChain, queryParams.DstTokenAddress)
+	if offline {
+		s.logger.Printf("Quoting %s -> %s from its rate table", queryParams.SrcChain, queryParams.DstChain)
+
+		quote, err := rateQuote(queryParams, table)
+		if err != nil {
+			return nil, &quoteError{http.StatusBadRequest, err.Error()}
+		}
+		quoteResponse = *quote
+	} else if cfg.OfflineQuotes {
+		return nil, &quoteError{http.StatusBadRequest, fmt.Sprintf("No rate table for %s %s -> %s %s",
+			queryParams.SrcChain, queryParams.SrcTokenAddress, queryParams.DstChain, queryParams.DstTokenAddress)}
+	} else if !s.devMode {
+		s.logger.Println("Running in prod mode, Fetching quote from 1inch Fusion+ API")
+
+		// build the url string to fetch
+		urlString, err := buildQuoteRequestParams(s.baseURL, queryParams)
+		if err != nil {
+			return nil, &quoteError{http.StatusBadRequest, "Invalid query parameters"}
+		}
+
+		req, err := http.NewRequest(http.MethodGet, urlString, nil)
+		if err != nil {
+			return nil, &quoteError{http.StatusInternalServerError, "Failed to create HTTP request"}
+		}
+
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json")
+
+		resp, err := s.callUpstream(req)
+		if err != nil {
+			return nil, &quoteError{http.StatusInternalServerError, "Failed to fetch quote"}
+		}
+		defer resp.Body.Close()
+
+		if err := decodeJSON(resp.Body, &quoteResponse, s.manager.Config().StrictDecoding); err != nil {
+			s.logger.Printf("Failed to decode quote response: %v", err)
+			return nil, &quoteError{http.StatusInternalServerError, "Failed to decode quote response from 1inch Fusion+ API"}
+		}
+
+		if err := nativeSafetyDeposits(&quoteResponse, queryParams); err != nil {
+			s.logger.Printf("Error converting safety deposits: %v", err)
+			return nil, &quoteError{http.StatusInternalServerError, "Failed to convert safety deposits"}
+		}
+	} else {
+		s.logger.Println("Running in dev mode, using default quote response")
+
+		if !common.IsSuiChain(queryParams.SrcChain) {
+			quoteResponse = *s.ethToSuiQuote
+		} else {
+			quoteResponse = *s.suiToEthQuote
+		}
+		quoteResponse.QuoteID = uuid.New()
+	}
+
+	// the order's auction and the verification of its fills read the points
+	if err := normalizeAuctions(&quoteResponse); err != nil {
+		s.logger.Printf("Invalid auction in quote: %v", err)
+		return nil, &quoteError{http.StatusInternalServerError, "Invalid auction points in quote"}
+	}
+
+	if err := s.configuredSafetyDeposits(&quoteResponse, queryParams); err != nil {
+		s.logger.Printf("Error computing safety deposits: %v", err)
+		return nil, &quoteError{http.StatusInternalServerError, "Failed to compute safety deposits"}
+	}
+
+	if err := applyProtocolFee(&quoteResponse, s.feeBps); err != nil {
+		s.logger.Printf("Error applying protocol fee: %v", err)
+		return nil, &quoteError{http.StatusInternalServerError, "Failed to apply protocol fee"}
+	}
+	if err := applyIntegratorFee(&quoteResponse, queryParams); err != nil {
+		s.logger.Printf("Error applying integrator fee: %v", err)
+		return nil, &quoteError{http.StatusInternalServerError, "Failed to apply integrator fee"}
+	}
+
+	// Sui corridors pair chains of very different finality, presets are
+	// fitted to both
+	if common.IsSuiChain(queryParams.SrcChain) || common.IsSuiChain(queryParams.DstChain) {
+		adjustForFinality(&quoteResponse, cfg.FinalityDelay(queryParams.SrcChain), cfg.FinalityDelay(queryParams.DstChain))
+	}
+
+	// the routing table is authoritative for which escrows serve the corridor
+	if route.SrcEscrowFactory != "" {
+		quoteResponse.SrcEscrowFactory = route.SrcEscrowFactory
+	}
+	if route.DstEscrowFactory != "" {
+		quoteResponse.DstEscrowFactory = route.DstEscrowFactory
+	}
+
+	quoteResponse.Hashlock = string(route.Hashlock)
+	quoteResponse.ExpiresAt = s.manager.QuoteExpiry(quoteResponse.RecommendedPreset).Unix()
+
+	return &quoteResponse, nil
+}
+
+// nativeSafetyDeposits restates the safety deposits of a 1inch API quote,
+// which are always in wei, in the gas token units of each escrow's chain.
+// The dev presets are already denominated that way.
+func nativeSafetyDeposits(quote *common.Quote, params common.QuoteRequestParams) error {
+	for _, deposit := range []struct {
+		chain  string
+		amount *string
+	}{
+		{params.SrcChain, &quote.SrcSafetyDeposit},
+		{params.DstChain, &quote.DstSafetyDeposit},
+	} {
+		chainID, err := common.ParseChainID(deposit.chain)
+		if err != nil {
+			return err
+		}
+		if *deposit.amount, err = chainID.NativeFromEVM(*deposit.amount); err != nil {
+			return fmt.Errorf("chain %s safety deposit: %w", chainID, err)
+		}
+	}
+	return nil
+}
+
+// normalizeAuctions normalizes the auction points of every preset of quote,
+// failing on the first invalid one, see auction.NormalizePoints.
+func normalizeAuctions(quote *common.Quote) error {
+	return updatePresets(quote, func(name common.PresetEnum, preset common.PresetData) (common.PresetData, error) {
+		normalized, err := auction.NormalizePoints(preset)
+		if err != nil {
+			return preset, fmt.Errorf("%s preset: %w", name, err)
+		}
+		return normalized, nil
+	})
+}
+
+// updatePresets replaces every preset of quote with what update returns for
+// it, leaving the quote unchanged on the first error. The presets map is
+// shared with the cached dev quotes, so a new one is built.
+func updatePresets(quote *common.Quote, update func(name common.PresetEnum, preset common.PresetData) (common.PresetData, error)) error {
+	presets := make(common.QuoterPresets, len(quote.Presets))
+	for name, preset := range quote.Presets {
+		updated, err := update(name, preset)
+		if err != nil {
+			return err
+		}
+		presets[name] = updated
+	}
+	quote.Presets = presets
+	return nil
+}
+
+// configuredSafetyDeposits replaces the quoted safety deposits of the chains
+// with safetyDeposits in the config by those computed at their current gas
+// price.
+func (s *APIServer) configuredSafetyDeposits(quote *common.Quote, params common.QuoteRequestParams) error {
+	for _, deposit := range []struct {
+		chain  string
+		amount *string
+	}{
+		{params.SrcChain, &quote.SrcSafetyDeposit},
+		{params.DstChain, &quote.DstSafetyDeposit},
+	} {
+		v, ok, err := s.manager.SafetyDeposit(context.Background(), deposit.chain)
+		if err != nil {
+			return fmt.Errorf("chain %s safety deposit: %w", deposit.chain, err)
+		}
+		if ok {
+			*deposit.amount = v.String()
+		}
+	}
+	return nil
+}
+
+// hubLegParams splits a quote request into the two legs through hub. The
+// amount of the second leg is only known once the first one is quoted.
+func hubLegParams(queryParams common.QuoteRequestParams, hub *routing.Hub) []common.QuoteRequestParams {
+	first := queryParams
+	first.DstChain = hub.Chain
+	first.DstTokenAddress = hub.Token
+	first.DstReceiver = ""
+
+	second := queryParams
+	second.SrcChain = hub.Chain
+	second.SrcTokenAddress = hub.Token
+	// the integrator fee is taken once, on the first leg
+	second.IntegratorFee = 0
+	second.FeeReceiver = ""
+
+	return []common.QuoteRequestParams{first, second}
+}
+
+// getMultiHopQuote quotes a chain pair without a direct route as two legs
+// through hub: src -> hub token on the hub chain, then hub token -> dst. The
+// second leg is quoted for what the first one delivers.
+func (s *APIServer) getMultiHopQuote(c *gin.Context, queryParams common.QuoteRequestParams, path []routing.Route, hub *routing.Hub) {
+	s.logger.Printf("No direct route %s -> %s, quoting through chain %s", queryParams.SrcChain, queryParams.DstChain, hub.Chain)
+
+	legParams := hubLegParams(queryParams, hub)
+	multiLeg := common.MultiLegQuote{
+		QuoteID: uuid.New(),
+		Legs:    make([]common.QuoteLeg, 0, len(path)),
+	}
+	entries := make([]manager.QuoteEntry, 0, len(path))
+
+	for i, route := range path {
+		params := legParams[i]
+		if i > 0 {
+			params.Amount = multiLeg.Legs[i-1].Quote.DstTokenAmount
+		}
+
+		quote, err := s.quoteRoute(params, route)
+		if err != nil {
+			c.JSON(quoteErrorStatus(err), gin.H{"error": fmt.Sprintf("leg %d: %s", i+1, err)})
+			return
+		}
+
+		multiLeg.Legs = append(multiLeg.Legs, common.QuoteLeg{
+			SrcChain:        params.SrcChain,
+			DstChain:        params.DstChain,
+			SrcTokenAddress: params.SrcTokenAddress,
+			DstTokenAddress: params.DstTokenAddress,
+			Quote:           *quote,
+		})
+		if multiLeg.ExpiresAt == 0 || quote.ExpiresAt < multiLeg.ExpiresAt {
+			multiLeg.ExpiresAt = quote.ExpiresAt
+		}
+		entries = append(entries, manager.QuoteEntry{
+			QuoteID:      quote.QuoteID,
+			QuoteRequest: &params,
+			Quote:        quote,
+			FeeBps:       s.feeBps,
+			Integrator:   integratorID(c),
+			ExpiresAt:    time.Unix(quote.ExpiresAt, 0),
+			Leg: &manager.QuoteLeg{
+				ParentID: multiLeg.QuoteID,
+				Index:    i,
+				Count:    len(path),
+			},
+		})
+	}
+
+	for _, entry := range entries {
+		s.manager.SetQuote(entry)
+	}
+
+	c.JSON(http.StatusOK, multiLeg)
+}
@@ -0,0 +1,37 @@
+package api
+
+import (
+	"errors"
+	"relayer/internal/common"
+	"testing"
+)
+
+func TestUpdatePresets(t *testing.T) {
+	cached := common.QuoterPresets{
+		common.PresetFast: {AuctionDuration: 180},
+		common.PresetSlow: {AuctionDuration: 600},
+	}
+	quote := &common.Quote{Presets: cached}
+
+	err := updatePresets(quote, func(_ common.PresetEnum, preset common.PresetData) (common.PresetData, error) {
+		preset.AuctionDuration *= 2
+		return preset, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if quote.Presets[common.PresetFast].AuctionDuration != 360 || quote.Presets[common.PresetSlow].AuctionDuration != 1200 {
+		t.Fatalf("presets not updated: %+v", quote.Presets)
+	}
+	if cached[common.PresetFast].AuctionDuration != 180 {
+		t.Fatal("updated the presets of the cached quote")
+	}
+
+	err = updatePresets(quote, func(_ common.PresetEnum, preset common.PresetData) (common.PresetData, error) {
+		preset.AuctionDuration = 0
+		return preset, errors.New("invalid")
+	})
+	if err == nil || quote.Presets[common.PresetFast].AuctionDuration != 360 || quote.Presets[common.PresetSlow].AuctionDuration != 1200 {
+		t.Fatalf("failed update: got %v, presets %+v", err, quote.Presets)
+	}
+}
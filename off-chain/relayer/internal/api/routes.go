@@ -1,31 +1,86 @@
 package api
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"math/big"
 	"net/http"
 	"net/url"
+	"relayer/internal/access"
+	"relayer/internal/accounting"
+	"relayer/internal/auction"
 	"relayer/internal/common"
+	"relayer/internal/compliance"
+	"relayer/internal/extension"
 	"relayer/internal/hash"
+	"relayer/internal/hashlock"
+	"relayer/internal/logging"
 	"relayer/internal/manager"
-	"sync"
+	"strconv"
+	"strings"
 	"time"
 
+	ethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 	"github.com/gorilla/schema"
 )
 
 func (s *APIServer) RegisterRoutes() http.Handler {
 	router := gin.New()
+	router.Use(s.requestLogger())
 
 	// Register routes
 	router.GET("/", s.DefaultHandler) // test handler
 
 	router.GET("/quoter/v1.0/quote/receive", s.GetQuote)
-	router.POST("/relayer/v1.0/submit", s.SubmitOrder)
-	router.POST("/relayer/v1.0/submit/secret", s.SubmitSecret)
-	router.GET("/orders/v1.0/order/ready-to-accept-secret-fills/:orderHash", s.GetReadyToAcceptSecretFills)
-	router.GET("/orders/v1.0/order/status/:orderHash", s.GetOrderStatus)
+	router.GET("/quoter/v1.0/estimate", s.GetEstimate)
+	router.POST("/relayer/v1.0/submit", limitBody(func() int64 { return s.manager.Config().MaxOrderBytes }), s.SubmitOrder)
+	router.POST("/relayer/v1.0/submit/secret", limitBody(func() int64 { return s.manager.Config().MaxSecretBytes }), s.SubmitSecret)
+	router.GET("/orders/v1.0/order/ready-to-accept-secret-fills/:orderHash", s.requireMaker(), s.GetReadyToAcceptSecretFills)
+	router.GET("/orders/v1.0/order/status/:orderHash", s.requireMaker(), s.GetOrderStatus)
+	router.GET("/orders/v1.0/order/cancellation-data/:orderHash", s.requireMaker(), s.GetCancellationData)
+	router.GET("/orders/v1.0/order/escrow/:orderHash", s.requireMaker(), s.GetOrderEscrows)
+	router.GET("/orders/v1.0/order/eta/:orderHash", s.requireMaker(), s.GetOrderETA)
+	router.POST("/orders/v1.0/session/challenge", s.CreateSessionChallenge)
+	router.POST("/orders/v1.0/session", s.CreateSession)
+	router.POST("/relayer/v1.0/secrets", limitBody(func() int64 { return s.manager.Config().MaxSecretBytes }), s.GenerateSecrets)
+	router.POST("/relayer/v1.0/secrets/:secretsId/export", limitBody(func() int64 { return s.manager.Config().MaxSecretBytes }), s.ExportSecrets)
+	router.GET("/info/v1.0/chains", s.GetChains)
+	router.GET("/info/v1.0/tokens", s.GetTokens)
+
+	// operators inspect and re-verify, only admins bypass verification or reload
+	admin := router.Group("/admin/v1.0")
+	operator := s.requireRole(access.RoleOperator)
+	admin.GET("/fees", operator, s.GetFeeSummary)
+	admin.GET("/orders", operator, s.ListOrders)
+	admin.GET("/orders/:orderHash", operator, s.InspectOrder)
+	admin.POST("/orders/:orderHash/reverify", operator, s.ReverifyFill)
+	admin.POST("/orders/:orderHash/release", s.requireRole(), s.ReleaseFill)
+	admin.GET("/quotes/:quoteId", operator, s.InspectQuote)
+	admin.GET("/quarantine", operator, s.ListQuarantined)
+	admin.POST("/quarantine/:orderHash/approve", s.requireRole(), s.ApproveQuarantined)
+	admin.POST("/quarantine/:orderHash/reject", s.requireRole(), s.RejectQuarantined)
+	admin.GET("/chains", operator, s.GetChainHealth)
+	admin.GET("/resolvers", operator, s.GetResolverStats)
+	admin.GET("/safety-deposits", operator, s.GetSafetyDeposits)
+	admin.GET("/upstream-keys", operator, s.GetUpstreamKeys)
+	admin.GET("/config", operator, s.GetConfig)
+	admin.POST("/config/reload", s.requireRole(), s.ReloadConfig)
+	admin.GET("/metrics", operator, gin.WrapH(expvar.Handler()))
+	// dashboards search the persistent store, beyond the live orders
+	router.GET("/orders/v1.0/search", operator, s.SearchOrders)
+
+	analytics := router.Group("/analytics/v1.0")
+	analyst := s.requireRole(access.RoleOperator, access.RoleAnalytics)
+	analytics.GET("/surplus", analyst, s.GetSurplus)
+	analytics.GET("/latency", analyst, s.GetLatency)
+	analytics.GET("/conversion", analyst, s.GetConversion)
+	// integrators only see their own fees
+	analytics.GET("/integrators", s.requireRole(access.RoleOperator, access.RoleAnalytics, access.RoleIntegrator), s.GetIntegratorFees)
+
+	router.GET(OpenAPIPath, openAPIHandler(router))
 	// Wrap the router with CORS middleware
 	return s.corsMiddleware(router)
 }
@@ -35,7 +90,7 @@ func (s *APIServer) corsMiddleware(next http.Handler) http.Handler {
 		// Set CORS headers
 		w.Header().Set("Access-Control-Allow-Origin", "*") // Replace "*" with specific origins if needed
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST")
-		w.Header().Set("Access-Control-Allow-Headers", "Accept, Authorization, Content-Type, X-CSRF-Token")
+		w.Header().Set("Access-Control-Allow-Headers", "Accept, Authorization, Content-Type, X-CSRF-Token, X-API-Key")
 		w.Header().Set("Access-Control-Allow-Credentials", "false") // Set to "true" if credentials are required
 
 		// Handle preflight OPTIONS requests
@@ -66,10 +121,9 @@ func buildQuoteRequestParams(base string, params common.QuoteRequestParams) (str
 	return u.String(), nil
 }
 
-func (s *APIServer) GetQuote(c *gin.Context) {
-	s.logger.Println()
-	defer s.logger.Println()
-
+// parseQuoteRequest reads the quote request query parameters, normalizing the
+// chain ids and validating the amount and the receivers.
+func parseQuoteRequest(c *gin.Context) (common.QuoteRequestParams, error) {
 	queryParams := common.QuoteRequestParams{
 		SrcChain:        c.Query("srcChain"),
 		DstChain:        c.Query("dstChain"),
@@ -77,55 +131,93 @@ func (s *APIServer) GetQuote(c *gin.Context) {
 		DstTokenAddress: c.Query("dstTokenAddress"),
 		Amount:          c.Query("amount"),
 		WalletAddress:   c.Query("walletAddress"),
+		DstReceiver:     c.Query("dstReceiver"),
+		FeeReceiver:     c.Query("feeReceiver"),
 	}
 
-	var quoteResponse common.Quote
-	if !s.devMode {
-		s.logger.Println("Running in prod mode, Fetching quote from 1inch Fusion+ API")
-
-		// build the url string to fetch
-		urlString, err := buildQuoteRequestParams(s.baseURL, queryParams)
+	// chains may be given as CAIP-2 ids, quotes and routes use decimal ids
+	for _, chain := range []*string{&queryParams.SrcChain, &queryParams.DstChain} {
+		normalized, err := common.NormalizeChain(*chain)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query parameters"})
-			return
+			return queryParams, err
 		}
+		*chain = normalized
+	}
 
-		req, err := http.NewRequest(http.MethodGet, urlString, nil)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create HTTP request"})
-			return
+	// the amount is in src token units, bounded by the src chain's escrows
+	srcChain, _ := common.ParseChainID(queryParams.SrcChain)
+	if _, err := srcChain.ParseAmount(queryParams.Amount); err != nil {
+		return queryParams, fmt.Errorf("Invalid amount: %w", err)
+	}
+
+	if queryParams.DstReceiver != "" {
+		if err := common.ValidateAddress(queryParams.DstChain, queryParams.DstReceiver); err != nil {
+			return queryParams, fmt.Errorf("Invalid dstReceiver: %w", err)
 		}
+	}
 
-		req.Header.Set("Authorization", "Bearer "+s.authKey)
-		req.Header.Set("Content-Type", "application/json")
-		req.Header.Set("Accept", "application/json")
+	if err := parseIntegratorFee(c, &queryParams); err != nil {
+		return queryParams, err
+	}
+	return queryParams, nil
+}
 
-		resp, err := http.DefaultClient.Do(req)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch quote"})
-			return
-		}
-		defer resp.Body.Close()
+func (s *APIServer) GetQuote(c *gin.Context) {
+	s.logger.Println()
+	defer s.logger.Println()
 
-		if err := json.NewDecoder(resp.Body).Decode(&quoteResponse); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode quote response from 1inch Fusion+ API"})
-			return
-		}
-	} else {
-		s.logger.Println("Running in dev mode, using default quote response")
+	queryParams, err := parseQuoteRequest(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
-		if queryParams.SrcChain == "1" {
-			quoteResponse = *s.ethToSuiQuote
-		} else {
-			quoteResponse = *s.suiToEthQuote
-		}
-		quoteResponse.QuoteID = uuid.New()
+	if s.manager.QuotesSaturated() {
+		c.Header("Retry-After", strconv.Itoa(QuoteRetryAfterSeconds))
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many live quotes, retry later"})
+		return
+	}
+
+	path, hub, err := s.manager.Routes().Path(queryParams.SrcChain, queryParams.DstChain)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := s.manager.Routes().CheckToken(queryParams.SrcChain, queryParams.SrcTokenAddress); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := s.manager.Routes().CheckToken(queryParams.DstChain, queryParams.DstTokenAddress); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	err = s.manager.Screen(c.Request.Context(), compliance.StageQuote, "",
+		compliance.Subject{Chain: queryParams.SrcChain, Address: queryParams.WalletAddress, Role: compliance.RoleMaker},
+		compliance.Subject{Chain: queryParams.DstChain, Address: queryParams.DstReceiver, Role: compliance.RoleReceiver},
+	)
+	if err != nil {
+		c.JSON(screeningStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	if hub != nil {
+		s.getMultiHopQuote(c, queryParams, path, hub)
+		return
+	}
+
+	quoteResponse, err := s.quoteRoute(queryParams, path[0])
+	if err != nil {
+		c.JSON(quoteErrorStatus(err), gin.H{"error": err.Error()})
+		return
 	}
 
 	s.manager.SetQuote(manager.QuoteEntry{
 		QuoteID:      quoteResponse.QuoteID,
 		QuoteRequest: &queryParams,
-		Quote:        &quoteResponse,
+		Quote:        quoteResponse,
+		FeeBps:       s.feeBps,
+		Integrator:   integratorID(c),
+		ExpiresAt:    time.Unix(quoteResponse.ExpiresAt, 0),
 	})
 
 	c.JSON(http.StatusOK, quoteResponse)
@@ -139,33 +231,104 @@ func (s *APIServer) SubmitOrder(c *gin.Context) {
 	defer body.Close()
 
 	order := common.Order{}
-	if err := json.NewDecoder(body).Decode(&order); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order data"})
+	if err := decodeJSON(body, &order, s.manager.Config().StrictDecoding); err != nil {
+		if tooLarge(err) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Request body too large"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order data: " + err.Error()})
 		s.logger.Printf("Failed to decode order data: %v", err)
 		return
 	}
 	s.logger.Printf("Received order @ ID: %s", order.QuoteID)
 	s.logger.Printf("Order details: %+v", order.LimitOrder)
 
+	job, ok := s.submitQueue.enqueue(order)
+	if !ok {
+		c.Header("Retry-After", strconv.Itoa(SubmitRetryAfterSeconds))
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "Relayer is overloaded, retry later"})
+		return
+	}
+
+	select {
+	case <-job.done:
+	case <-c.Request.Context().Done():
+		// the order is still processed, only the caller is gone
+		return
+	}
+
+	if job.body == nil {
+		c.Status(job.status)
+		return
+	}
+	c.JSON(job.status, job.body)
+}
+
+// processOrder validates, hashes, stores and broadcasts a submitted order. It
+// runs on a submission queue worker and returns the response to send.
+func (s *APIServer) processOrder(order common.Order) (int, gin.H) {
+	if !order.SrcChainID.IsSupported() {
+		return http.StatusBadRequest, gin.H{"error": "Unsupported source chain"}
+	}
+
+	quote, err := s.manager.GetQuote(order.QuoteID)
+	if err != nil {
+		return http.StatusBadRequest, gin.H{"error": "Quote not found"}
+	}
+	if quote.Expired(time.Now()) {
+		return http.StatusGone, gin.H{"error": "Quote expired", "code": ErrCodeQuoteExpired, "expiresAt": quote.ExpiresAt.Unix()}
+	}
+
+	srcChain := order.SrcChainID.String()
+	route, err := s.manager.Routes().Lookup(srcChain, quote.QuoteRequest.DstChain)
+	if err != nil {
+		return http.StatusBadRequest, gin.H{"error": err.Error()}
+	}
+
+	// the maker may restate the algorithm, but it is fixed by the route
+	if algo, err := hashlock.Parse(order.Hashlock); err != nil || (order.Hashlock != "" && algo != route.Hashlock) {
+		return http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Route %s -> %s uses %s hashlocks", srcChain, quote.QuoteRequest.DstChain, route.Hashlock)}
+	}
+
+	ext, err := decodeExtension(&order, quote, s.singleFillHashlock(&order))
+	if err != nil {
+		return http.StatusBadRequest, gin.H{"error": "Invalid order extension: " + err.Error()}
+	}
+
+	dstReceiver, err := resolveDstReceiver(&order, quote)
+	if err != nil {
+		return http.StatusBadRequest, gin.H{"error": err.Error()}
+	}
+	if err := checkMinFill(&order); err != nil {
+		return http.StatusBadRequest, gin.H{"error": err.Error()}
+	}
+
 	hash, err := hash.GetOrderHashForLimitOrder(order.SrcChainID, order.LimitOrder)
 	if err != nil {
 		s.logger.Printf("Error computing order hash: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute order hash"})
-		return
+		return http.StatusInternalServerError, gin.H{"error": "Failed to compute order hash"}
 	}
 	s.logger.Printf("Order hash: %s", hash.Hex())
 
-	if err := s.manager.HandleOrderEvent(order); err != nil {
-		s.logger.Printf("Error handling order event: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to handle order event"})
-		return
+	if err := s.manager.VerifyOrderSignature(&order, hash); err != nil {
+		return http.StatusBadRequest, gin.H{"error": "Invalid order signature: " + err.Error()}
+	}
+	if err := s.manager.CheckOrderEpoch(&order); err != nil {
+		return http.StatusBadRequest, gin.H{"error": "Invalid order epoch: " + err.Error()}
+	}
+	err = s.manager.Screen(context.Background(), compliance.StageSubmit, hash.Hex(),
+		compliance.Subject{Chain: srcChain, Address: order.LimitOrder.Maker, Role: compliance.RoleMaker},
+		compliance.Subject{Chain: quote.QuoteRequest.DstChain, Address: orderReceiver(&order, dstReceiver), Role: compliance.RoleReceiver},
+	)
+	if err != nil {
+		return screeningStatus(err), gin.H{"error": err.Error()}
 	}
 
-	orderStatus, err := buildOrderStatus(&order, s.manager)
+	submittedAt := time.Now()
+	orderStatus, err := buildOrderStatus(&order, s.manager, submittedAt)
 	if err != nil {
 		s.logger.Printf("Error building order status: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build order status"})
-		return
+		return http.StatusInternalServerError, gin.H{"error": "Failed to build order status"}
 	}
 
 	var orderType manager.OrderType
@@ -175,18 +338,69 @@ func (s *APIServer) SubmitOrder(c *gin.Context) {
 		orderType = manager.SingleFill
 	}
 
-	s.manager.SetOrder(manager.OrderEntry{
-		OrderType:   orderType,
-		OrderHash:   hash,
-		Order:       &order,
-		OrderStatus: orderStatus,
-		OrderFills: &common.ReadyToAcceptSecretFills{
-			Fills: make([]common.ReadyToAcceptSecretFill, 0),
+	fee, err := accounting.FeeAmount(order.LimitOrder.MakingAmount, quote.FeeBps)
+	if err != nil {
+		return http.StatusBadRequest, gin.H{"error": "Invalid making amount"}
+	}
+	var integratorFee *manager.OrderFee
+	if bps := quote.QuoteRequest.IntegratorFee; bps > 0 {
+		amount, err := accounting.FeeAmount(order.LimitOrder.MakingAmount, bps)
+		if err != nil {
+			return http.StatusBadRequest, gin.H{"error": "Invalid making amount"}
+		}
+		integratorFee = &manager.OrderFee{ChainID: srcChain, Token: order.LimitOrder.MakerAsset, Amount: amount}
+	}
+
+	orderEntry := &manager.OrderEntry{
+		OrderType:          orderType,
+		OrderHash:          hash,
+		Order:              &order,
+		OrderStatus:        *orderStatus,
+		Quote:              quote,
+		SubmittedAt:        submittedAt,
+		FilledMakingAmount: new(big.Int),
+		Escrows:            make(map[string]manager.EscrowSide),
+		Closed:             make(map[string]string),
+		Canonical:          make(map[int]*manager.Verification),
+		DstReceiver:        dstReceiver,
+		Hashlock:           route.Hashlock,
+		SecretsID:          order.SecretsID,
+		Extension:          ext,
+		Fee: &manager.OrderFee{
+			ChainID: srcChain,
+			Token:   order.LimitOrder.MakerAsset,
+			Amount:  fee,
 		},
-		OrderMutMutex: new(sync.Mutex),
-	})
+		IntegratorFee: integratorFee,
+	}
+	if err := s.manager.BindSecrets(orderEntry); err != nil {
+		return http.StatusBadRequest, gin.H{"error": "Invalid secretsId: " + err.Error()}
+	}
 
-	s.logger.Printf("Order broadcasted @ ID: %s", order.QuoteID)
+	// a price far off the oracle's is a typo or manipulation until an admin says otherwise
+	if max := s.manager.Config().PriceDeviationBps; max > 0 {
+		if bps, ok := s.manager.PriceDeviation(&order, quote); ok && uint64(bps) > max {
+			if err := s.manager.Quarantine(orderEntry, bps); err != nil {
+				return http.StatusServiceUnavailable, gin.H{"error": "Order quarantine is full"}
+			}
+			return http.StatusAccepted, gin.H{"quarantined": true, "deviationBps": bps}
+		}
+	}
+
+	return s.dispatchOrder(orderEntry)
+}
+
+// dispatchOrder stores and broadcasts an accepted order and forwards it to
+// the 1inch relayer in passthrough mode.
+func (s *APIServer) dispatchOrder(orderEntry *manager.OrderEntry) (int, gin.H) {
+	if err := s.manager.DispatchOrder(orderEntry); err != nil {
+		s.logger.Printf("Error handling order event: %v", err)
+		return http.StatusInternalServerError, gin.H{"error": "Failed to handle order event"}
+	}
+
+	s.logger.Printf("Order broadcasted @ ID: %s", orderEntry.Order.QuoteID)
+	go s.forwardOrder(*orderEntry.Order, orderEntry.OrderHash.Hex())
+	return http.StatusOK, nil
 }
 
 func (s *APIServer) SubmitSecret(c *gin.Context) {
@@ -197,13 +411,21 @@ func (s *APIServer) SubmitSecret(c *gin.Context) {
 	defer body.Close()
 
 	secret := common.Secret{}
-	if err := json.NewDecoder(body).Decode(&secret); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid secret submission"})
+	if err := decodeJSON(body, &secret, s.manager.Config().StrictDecoding); err != nil {
+		if tooLarge(err) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Request body too large"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid secret submission: " + err.Error()})
 		s.logger.Printf("Failed to decode secret submission data: %v", err)
 		return
 	}
 
-	s.logger.Printf("Received secret submission: %+v for order: %+v", secret.Secret, secret.OrderHash)
+	s.logger.Printf("Received secret submission: %s for order: %s", logging.Redact(secret.Secret), secret.OrderHash)
+	if err := s.manager.CheckSecret(secret); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	if err := s.manager.HandleSecretEvent(secret); err != nil {
 		s.logger.Printf("Error handling secret event: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to handle secret event"})
@@ -224,18 +446,58 @@ func (s *APIServer) GetOrderStatus(c *gin.Context) {
 	s.logger.Printf("Fetching order status for hash: %s", orderHash)
 
 	orderEntry, err := s.manager.GetOrder(orderHash)
+	if err != nil {
+		// expired orders are archived by the sweeper
+		if orderEntry, err = s.manager.GetArchivedOrder(orderHash); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Order not found"})
+			return
+		}
+	}
+
+	// the status is updated in place by cancellations
+	c.JSON(http.StatusOK, orderEntry.Status())
+}
+
+// GetCancellationData returns the escrows of the order's verified fills with
+// the immutables needed to cancel them and the stage each one is in.
+func (s *APIServer) GetCancellationData(c *gin.Context) {
+	orderHash := c.Param("orderHash")
+	if orderHash == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Order hash is required"})
+		return
+	}
+
+	data, err := s.manager.CancellationData(orderHash, time.Now())
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Order not found"})
 		return
 	}
 
-	orderStatus := orderEntry.OrderStatus
-	if orderStatus == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Order status not found"})
+	c.JSON(http.StatusOK, data)
+}
+
+// GetOrderEscrows returns the escrows of an order's verified fills with the
+// immutables withdrawing from or cancelling them takes.
+func (s *APIServer) GetOrderEscrows(c *gin.Context) {
+	escrows, err := s.manager.OrderEscrows(c.Param("orderHash"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Order not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, escrows)
+}
+
+// GetOrderETA returns the phase of an order's swap and when it is expected
+// to complete, for wallets to show a countdown.
+func (s *APIServer) GetOrderETA(c *gin.Context) {
+	eta, err := s.manager.OrderETA(c.Param("orderHash"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Order not found"})
 		return
 	}
 
-	c.JSON(http.StatusOK, orderStatus)
+	c.JSON(http.StatusOK, eta)
 }
 
 func (s *APIServer) GetReadyToAcceptSecretFills(c *gin.Context) {
@@ -254,31 +516,12 @@ func (s *APIServer) GetReadyToAcceptSecretFills(c *gin.Context) {
 		return
 	}
 
-	// lock and borrow ref
-	if !orderEntry.OrderMutMutex.TryLock() {
-		c.JSON(http.StatusOK, common.ReadyToAcceptSecretFills{
-			Fills: []common.ReadyToAcceptSecretFill{},
-		})
-		return
+	fills := orderEntry.TakeFills()
+	if fills == nil {
+		fills = []common.ReadyToAcceptSecretFill{}
 	}
-	fills := orderEntry.OrderFills.Fills
-
-	// replace old ref with new
-	orderEntry.OrderFills.Fills = make([]common.ReadyToAcceptSecretFill, 0, cap(fills)/2)
-	orderEntry.OrderMutMutex.Unlock()
 
-	if len(fills) == 0 {
-		c.JSON(http.StatusOK, common.ReadyToAcceptSecretFills{
-			Fills: []common.ReadyToAcceptSecretFill{},
-		})
-		return
-	}
-
-	readyToAcceptSecretFills := common.ReadyToAcceptSecretFills{
-		Fills: fills,
-	}
-
-	c.JSON(http.StatusOK, readyToAcceptSecretFills)
+	c.JSON(http.StatusOK, common.ReadyToAcceptSecretFills{Fills: fills})
 }
 
 func (s *APIServer) DefaultHandler(c *gin.Context) {
@@ -291,19 +534,153 @@ func (s *APIServer) DefaultHandler(c *gin.Context) {
 	c.String(http.StatusOK, "Message broadcasted: %s", msg)
 }
 
-func buildOrderStatus(order *common.Order, manager *manager.Manager) (*common.OrderStatus, error) {
+// decodeExtension decodes and validates the extension of an EVM-sourced
+// order, flagging orders whose maker traits ask for Permit2. The signed order
+// must commit to the extension, and a single fill order's extension to
+// hashlock when the relayer knows it, see singleFillHashlock. Sui-sourced
+// orders carry their escrow data on chain instead.
+func decodeExtension(order *common.Order, quote manager.QuoteEntry, hashlock ethcommon.Hash) (*extension.Extension, error) {
+	order.Permit2 = false
+	if order.SrcChainID.IsMove() {
+		return nil, nil
+	}
+	if extension.IsEmpty(order.Extension) {
+		return nil, fmt.Errorf("extension is required")
+	}
+
+	traits, err := extension.ParseMakerTraits(order.LimitOrder.MakerTraits)
+	if err != nil {
+		return nil, err
+	}
+	if err := extension.CheckCommitment(order.Extension, order.LimitOrder.Salt, traits); err != nil {
+		return nil, err
+	}
+
+	ext, err := extension.Decode(order.Extension)
+	if err != nil {
+		return nil, err
+	}
+	if err := ext.Validate(quote.Quote, quote.QuoteRequest.DstChain, order.SecretHashes); err != nil {
+		return nil, err
+	}
+	if len(order.SecretHashes) == 0 {
+		if err := ext.CheckHashlock(hashlock); err != nil {
+			return nil, err
+		}
+	}
+
+	order.Permit2 = traits.UsePermit2()
+	if order.Permit2 {
+		if err := ext.CheckPermit2(order.LimitOrder, time.Now()); err != nil {
+			return nil, err
+		}
+	}
+
+	return ext, nil
+}
+
+// singleFillHashlock is the hashlock of a single fill order built with a
+// relayer held secret set, zero when the relayer does not know it. Unknown
+// or multiple fill sets are left to BindSecrets to reject.
+func (s *APIServer) singleFillHashlock(order *common.Order) ethcommon.Hash {
+	if order.SecretsID == "" || len(order.SecretHashes) > 0 || !s.manager.Custody().Enabled() {
+		return ethcommon.Hash{}
+	}
+	set, err := s.manager.Custody().Get(order.SecretsID)
+	if err != nil || set.MultiFill() {
+		return ethcommon.Hash{}
+	}
+	return set.Hashlock
+}
+
+// resolveDstReceiver picks the explicit destination receiver of an order from
+// the order itself or its quote request; both must agree when both are set.
+func resolveDstReceiver(order *common.Order, quote manager.QuoteEntry) (string, error) {
+	receiver := order.DstReceiver
+	if quoted := quote.QuoteRequest.DstReceiver; quoted != "" {
+		if receiver != "" && !strings.EqualFold(receiver, quoted) {
+			return "", fmt.Errorf("dstReceiver %s does not match the quoted %s", receiver, quoted)
+		}
+		receiver = quoted
+	}
+
+	if receiver == "" {
+		return "", nil
+	}
+	if err := common.ValidateAddress(quote.QuoteRequest.DstChain, receiver); err != nil {
+		return "", fmt.Errorf("invalid dstReceiver: %w", err)
+	}
+
+	return receiver, nil
+}
+
+// orderReceiver is the address an order pays out to on the destination
+// chain, empty when that is the maker.
+func orderReceiver(order *common.Order, dstReceiver string) string {
+	if dstReceiver != "" {
+		return dstReceiver
+	}
+	if strings.Trim(strings.TrimPrefix(order.LimitOrder.Receiver, "0x"), "0") == "" {
+		return ""
+	}
+	return order.LimitOrder.Receiver
+}
+
+// screeningStatus answers denied addresses with 403, and a screener that
+// could not decide with 503.
+func screeningStatus(err error) int {
+	if errors.Is(err, compliance.ErrDenied) {
+		return http.StatusForbidden
+	}
+	return http.StatusServiceUnavailable
+}
+
+// checkMinFill validates the minimum partial fill a maker asked for: only
+// multiple fill orders take partial fills, and the minimum must fit in the
+// making amount.
+func checkMinFill(order *common.Order) error {
+	if order.MinFillAmount == "" {
+		return nil
+	}
+	if len(order.SecretHashes) == 0 {
+		return fmt.Errorf("minFillAmount needs a multiple fill order")
+	}
+
+	min, err := order.SrcChainID.ParseAmount(order.MinFillAmount)
+	if err != nil {
+		return fmt.Errorf("invalid minFillAmount: %w", err)
+	}
+	making, err := order.SrcChainID.ParseAmount(order.LimitOrder.MakingAmount)
+	if err != nil {
+		return fmt.Errorf("invalid making amount: %w", err)
+	}
+	if min.Sign() == 0 || min.Cmp(making) > 0 {
+		return fmt.Errorf("minFillAmount %s must be positive and at most the making amount %s", min, making)
+	}
+	return nil
+}
+
+// buildOrderStatus builds the initial status of an order submitted at
+// submittedAt. The auction window comes from the same curve verification
+// checks fills against.
+func buildOrderStatus(order *common.Order, manager *manager.Manager, submittedAt time.Time) (*common.OrderStatus, error) {
 	quote, err := manager.GetQuote(order.QuoteID)
 	if err != nil {
 		return nil, err
 	}
 
+	preset := quote.Quote.Presets[quote.Quote.RecommendedPreset]
+	curve := auction.FromPreset(submittedAt, preset)
+
 	return &common.OrderStatus{
 		Status:              common.OrderStatusPending,
 		Order:               &order.LimitOrder,
 		Extension:           order.Extension,
-		Points:              quote.Quote.Presets[quote.Quote.RecommendedPreset].Points,
-		CreatedAt:           time.Now().Format(time.RFC3339),
-		InitialRateBump:     quote.Quote.Presets[quote.Quote.RecommendedPreset].InitialRateBump,
+		Points:              preset.Points,
+		CreatedAt:           submittedAt.Format(time.RFC3339),
+		AuctionStartDate:    curve.Start.Unix(),
+		AuctionDuration:     int64(curve.Duration.Seconds()),
+		InitialRateBump:     preset.InitialRateBump,
 		FromTokenToUsdPrice: quote.Quote.Prices.USD.SrcToken,
 		ToTokenToUsdPrice:   quote.Quote.Prices.USD.DstToken,
 	}, nil
@@ -0,0 +1,141 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"relayer/internal/common"
+	"relayer/internal/manager"
+	"relayer/internal/store"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Page sizes of order searches.
+const (
+	DefaultSearchLimit = 50
+	MaxSearchLimit     = 500
+)
+
+// SearchOrders finds stored orders by status, chain pair, token, maker,
+// submission time and making amount, a page at a time.
+func (s *APIServer) SearchOrders(c *gin.Context) {
+	query, err := parseOrderQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := s.manager.SearchOrders(c.Request.Context(), query)
+	if errors.Is(err, manager.ErrNoStore) {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Order search requires DATABASE_PATH"})
+		return
+	}
+	if errors.Is(err, store.ErrBadCursor) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cursor for this sort"})
+		return
+	}
+	if err != nil {
+		s.logger.Printf("Error searching orders: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search orders"})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// parseOrderQuery reads the filters, sort and page of an order search.
+func parseOrderQuery(c *gin.Context) (store.OrderQuery, error) {
+	q := store.OrderQuery{
+		Token:  c.Query("token"),
+		Maker:  c.Query("maker"),
+		Cursor: c.Query("cursor"),
+		Limit:  DefaultSearchLimit,
+		Desc:   true,
+	}
+
+	if v := c.Query("status"); v != "" {
+		for _, status := range strings.Split(v, ",") {
+			switch common.OrderStatusMode(status) {
+			case common.OrderStatusPending, common.OrderStatusExecuted, common.OrderStatusExpired,
+				common.OrderStatusCancelled, common.OrderStatusRefunding, common.OrderStatusRefunded:
+				q.Statuses = append(q.Statuses, status)
+			default:
+				return q, fmt.Errorf("Unknown status %q", status)
+			}
+		}
+	}
+
+	for _, chain := range []struct {
+		param string
+		id    *string
+	}{{"srcChain", &q.SrcChainID}, {"dstChain", &q.DstChainID}} {
+		v := c.Query(chain.param)
+		if v == "" {
+			continue
+		}
+		normalized, err := common.NormalizeChain(v)
+		if err != nil {
+			return q, fmt.Errorf("Invalid %s: %w", chain.param, err)
+		}
+		*chain.id = normalized
+	}
+
+	for _, bound := range []struct {
+		param string
+		at    *time.Time
+	}{{"from", &q.From}, {"to", &q.To}} {
+		v := c.Query(bound.param)
+		if v == "" {
+			continue
+		}
+		at, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return q, fmt.Errorf("%s must be an RFC 3339 time", bound.param)
+		}
+		*bound.at = at
+	}
+
+	for _, bound := range []struct {
+		param  string
+		amount *string
+	}{{"minAmount", &q.MinAmount}, {"maxAmount", &q.MaxAmount}} {
+		v := c.Query(bound.param)
+		if v == "" {
+			continue
+		}
+		amount, err := common.ParseAmount(v)
+		if err != nil {
+			return q, fmt.Errorf("Invalid %s: %w", bound.param, err)
+		}
+		*bound.amount = amount.String()
+	}
+
+	switch sort := store.OrderSort(c.DefaultQuery("sort", string(store.SortSubmitted))); sort {
+	case store.SortSubmitted, store.SortMakingAmount:
+		q.Sort = sort
+	default:
+		return q, fmt.Errorf("sort must be %s or %s", store.SortSubmitted, store.SortMakingAmount)
+	}
+
+	switch c.DefaultQuery("order", "desc") {
+	case "asc":
+		q.Desc = false
+	case "desc":
+	default:
+		return q, fmt.Errorf("order must be asc or desc")
+	}
+
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > MaxSearchLimit {
+			return q, fmt.Errorf("limit must be between 1 and %d", MaxSearchLimit)
+		}
+		q.Limit = limit
+	}
+
+	return q, nil
+}
@@ -0,0 +1,133 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"relayer/internal/common"
+	"relayer/internal/custody"
+	"relayer/internal/hashlock"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GenerateSecrets creates the secrets and hashlock of an order built from a
+// quote, for integrations that let the relayer hold and reveal them.
+func (s *APIServer) GenerateSecrets(c *gin.Context) {
+	vault := s.manager.Custody()
+	if !vault.Enabled() {
+		c.JSON(http.StatusNotFound, gin.H{"error": custody.ErrDisabled.Error()})
+		return
+	}
+
+	// sets are free to generate: bound how many are held at once
+	if vault.Len() >= s.manager.Config().MaxSecretSets {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Secret set limit reached"})
+		return
+	}
+
+	var req common.SecretsRequest
+	if err := decodeJSON(c.Request.Body, &req, s.manager.Config().StrictDecoding); err != nil {
+		if tooLarge(err) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Request body too large"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid secrets request: " + err.Error()})
+		return
+	}
+
+	policy, err := custody.ParsePolicy(req.Policy)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	quote, err := s.manager.GetQuote(req.QuoteID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Quote not found"})
+		return
+	}
+	if quote.Expired(time.Now()) {
+		c.JSON(http.StatusGone, gin.H{"error": "Quote expired", "code": ErrCodeQuoteExpired, "expiresAt": quote.ExpiresAt.Unix()})
+		return
+	}
+
+	algo, err := hashlock.Parse(quote.Quote.Hashlock)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	preset := quote.Quote.Presets[quote.Quote.RecommendedPreset]
+	set, token, err := vault.Generate(preset.SecretsCount, algo, policy)
+	switch {
+	case errors.Is(err, custody.ErrStore):
+		s.logger.Printf("Error generating secrets for quote %s: %v", req.QuoteID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store secrets"})
+		return
+	case err != nil:
+		s.logger.Printf("Error generating secrets for quote %s: %v", req.QuoteID, err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	s.logger.Printf("Generated %d secrets @ ID: %s for quote %s", len(set.SecretHashes), set.ID, req.QuoteID)
+
+	resp := secretSet(set)
+	resp.ExportToken = token
+	c.JSON(http.StatusOK, resp)
+}
+
+// ExportSecrets hands a secret set to the holder of its export token, so the
+// maker can reveal the secrets itself.
+func (s *APIServer) ExportSecrets(c *gin.Context) {
+	vault := s.manager.Custody()
+	if !vault.Enabled() {
+		c.JSON(http.StatusNotFound, gin.H{"error": custody.ErrDisabled.Error()})
+		return
+	}
+
+	var req common.ExportSecretsRequest
+	if err := decodeJSON(c.Request.Body, &req, s.manager.Config().StrictDecoding); err != nil {
+		if tooLarge(err) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Request body too large"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid export request: " + err.Error()})
+		return
+	}
+
+	set, secrets, err := vault.Export(c.Param("secretsId"), req.ExportToken)
+	switch {
+	case errors.Is(err, custody.ErrSetNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, custody.ErrBadToken):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		s.logger.Printf("Error exporting secrets %s: %v", c.Param("secretsId"), err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export secrets"})
+		return
+	}
+	s.logger.Printf("Exported secrets @ ID: %s", set.ID)
+
+	resp := secretSet(set)
+	resp.Secrets = secrets
+	c.JSON(http.StatusOK, resp)
+}
+
+func secretSet(set *custody.Set) common.SecretSet {
+	hashes := make([]string, len(set.SecretHashes))
+	for i, h := range set.SecretHashes {
+		hashes[i] = h.Hex()
+	}
+
+	return common.SecretSet{
+		SecretsID:    set.ID.String(),
+		Algorithm:    string(set.Algorithm),
+		Policy:       string(set.Policy),
+		Hashlock:     set.Hashlock.Hex(),
+		SecretHashes: hashes,
+		OrderHash:    set.OrderHash,
+	}
+}
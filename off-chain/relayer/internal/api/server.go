@@ -7,8 +7,11 @@ import (
 	"net/http"
 	"os"
 	"path"
+	"relayer/internal/access"
+	"relayer/internal/accounting"
 	"relayer/internal/common"
 	"relayer/internal/manager"
+	"relayer/internal/session"
 	"strconv"
 	"time"
 
@@ -18,20 +21,54 @@ import (
 type APIServer struct {
 	port          int
 	baseURL       string
-	authKey       string
+	apiKeys       *apiKeys
+	access        *access.Policy
+	feeBps        uint64
 	manager       *manager.Manager
 	logger        *log.Logger
 	devMode       bool
 	ethToSuiQuote *common.Quote
 	suiToEthQuote *common.Quote
+	submitQueue   *submitQueue
+	upstream      *http.Client
+	// 1inch relayer orders are also forwarded to, empty unless UPSTREAM_SUBMIT
+	passthroughURL string
+	// order status is only served to the maker's session
+	privateStatus bool
+	sessions      *session.Sessions
 }
 
 func NewAPIServer(manager *manager.Manager, logger *log.Logger) *http.Server {
 	port, _ := strconv.Atoi(os.Getenv("API_PORT"))
 	baseURL := os.Getenv("1INCH_URL")
-	authKey := os.Getenv("1INCH_API_KEY")
 	mode := os.Getenv("API_MODE")
 
+	// admin and analytics credentials, none disables those routes
+	policy, err := access.Load(os.Getenv("ACCESS_KEYS_FILE"), os.Getenv("ADMIN_API_KEY"), os.Getenv("ACCESS_JWT_SECRET"))
+	if err != nil {
+		logger.Fatalf("failed to load access keys: %v", err)
+	}
+
+	var feeBps uint64
+	if v := os.Getenv("PROTOCOL_FEE_BPS"); v != "" {
+		var err error
+		feeBps, err = strconv.ParseUint(v, 10, 64)
+		if err != nil || feeBps > accounting.MaxFeeBps {
+			logger.Fatalf("PROTOCOL_FEE_BPS must be an integer between 0 and %d", accounting.MaxFeeBps)
+		}
+	}
+
+	var passthroughURL string
+	if os.Getenv("UPSTREAM_SUBMIT") == "true" {
+		passthroughURL = DefaultUpstreamRelayerURL
+		if v := os.Getenv("UPSTREAM_RELAYER_URL"); v != "" {
+			passthroughURL = v
+		}
+	}
+
+	queueSize := envInt(logger, "SUBMIT_QUEUE_SIZE", DefaultSubmitQueueSize)
+	workers := envInt(logger, "SUBMIT_WORKERS", DefaultSubmitWorkers)
+
 	var eth2sui common.Quote
 	var sui2eth common.Quote
 	if mode == "DEV" {
@@ -57,25 +94,53 @@ func NewAPIServer(manager *manager.Manager, logger *log.Logger) *http.Server {
 	}
 
 	newAPIServer := &APIServer{
-		port:          port,
-		baseURL:       baseURL,
-		authKey:       authKey,
-		manager:       manager,
-		logger:        logger,
-		devMode:       mode == "DEV",
-		ethToSuiQuote: &eth2sui,
-		suiToEthQuote: &sui2eth,
+		port:           port,
+		baseURL:        baseURL,
+		apiKeys:        newAPIKeys(os.Getenv("1INCH_API_KEY")),
+		access:         policy,
+		feeBps:         feeBps,
+		manager:        manager,
+		logger:         logger,
+		devMode:        mode == "DEV",
+		ethToSuiQuote:  &eth2sui,
+		suiToEthQuote:  &sui2eth,
+		upstream:       newUpstreamClient(logger),
+		passthroughURL: passthroughURL,
+		privateStatus:  manager.PrivateStatus(),
+		sessions:       manager.Sessions(),
 	}
+	newAPIServer.submitQueue = newSubmitQueue(queueSize, workers, newAPIServer.processOrder)
 
 	// Declare Server config
 	server := &http.Server{
-		Addr:         fmt.Sprintf(":%d", newAPIServer.port),
-		Handler:      newAPIServer.RegisterRoutes(),
-		IdleTimeout:  time.Minute,
-		ReadTimeout:  10 * time.Second,
-		WriteTimeout: 30 * time.Second,
-		ErrorLog:     logger,
+		Addr:              fmt.Sprintf(":%d", newAPIServer.port),
+		Handler:           newAPIServer.RegisterRoutes(),
+		ReadHeaderTimeout: envSeconds(logger, "HTTP_READ_HEADER_TIMEOUT_SECONDS", DefaultReadHeaderTimeout),
+		ReadTimeout:       envSeconds(logger, "HTTP_READ_TIMEOUT_SECONDS", DefaultReadTimeout),
+		WriteTimeout:      envSeconds(logger, "HTTP_WRITE_TIMEOUT_SECONDS", DefaultWriteTimeout),
+		IdleTimeout:       envSeconds(logger, "HTTP_IDLE_TIMEOUT_SECONDS", DefaultIdleTimeout),
+		MaxHeaderBytes:    envInt(logger, "HTTP_MAX_HEADER_BYTES", DefaultMaxHeaderBytes),
+		ErrorLog:          logger,
 	}
 
 	return server
 }
+
+// envSeconds reads a positive duration setting given in seconds.
+func envSeconds(logger *log.Logger, key string, fallback time.Duration) time.Duration {
+	return time.Duration(envInt(logger, key, int(fallback/time.Second))) * time.Second
+}
+
+// envInt reads a positive integer setting, exiting when it is malformed.
+func envInt(logger *log.Logger, key string, fallback int) int {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback
+	}
+
+	n, err := strconv.Atoi(v)
+	if err != nil || n <= 0 {
+		logger.Fatalf("%s must be a positive integer", key)
+	}
+	return n
+}
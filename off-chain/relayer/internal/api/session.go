@@ -0,0 +1,89 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"relayer/internal/common"
+	"relayer/internal/session"
+	"strings"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+)
+
+// CreateSessionChallenge returns a sign-in message for a maker's address.
+func (s *APIServer) CreateSessionChallenge(c *gin.Context) {
+	var req common.SessionChallengeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid challenge request"})
+		return
+	}
+	if !ethcommon.IsHexAddress(req.Address) && common.ValidateAddress(common.Sui.String(), req.Address) != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "address must be an EVM or Sui address"})
+		return
+	}
+
+	challenge, err := s.sessions.Challenge(req.Address, c.ClientIP(), time.Now())
+	if errors.Is(err, session.ErrTooManyChallenges) {
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, session.ErrNoDomain) {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Maker sessions are not configured"})
+		return
+	}
+	if err != nil {
+		s.logger.Printf("Error creating session challenge: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create challenge"})
+		return
+	}
+	c.JSON(http.StatusOK, challenge)
+}
+
+// CreateSession trades a maker's signature of its challenge for a session
+// token reading the status of its orders.
+func (s *APIServer) CreateSession(c *gin.Context) {
+	var req common.SessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.Nonce == "" || req.Signature == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "nonce and signature are required"})
+		return
+	}
+
+	token, expiresAt, err := s.sessions.SignIn(req.Nonce, req.Signature, s.manager.VerifyMessageSignature, time.Now())
+	if errors.Is(err, session.ErrUnknownChallenge) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid signature: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, common.Session{Token: token, ExpiresAt: expiresAt.Unix()})
+}
+
+// requireMaker only lets the order's maker read it when PRIVATE_ORDER_STATUS
+// is set. Orders of other makers are reported as not found, so their hashes
+// cannot be probed.
+func (s *APIServer) requireMaker() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !s.privateStatus {
+			c.Next()
+			return
+		}
+
+		token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		maker, err := s.sessions.Authenticate(token, time.Now())
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "A maker session is required"})
+			return
+		}
+
+		orderMaker, err := s.manager.OrderMaker(c.Param("orderHash"))
+		if err != nil || !common.SameAddress(orderMaker, maker) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "Order not found"})
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,63 @@
+package api
+
+import (
+	"log"
+	"net"
+	"net/http"
+	"relayer/internal/metrics"
+	"time"
+)
+
+const (
+	// DefaultUpstreamTimeout bounds a whole upstream call, body included
+	DefaultUpstreamTimeout = 10 * time.Second
+	// DefaultUpstreamMaxConns limits the connections kept open per upstream host
+	DefaultUpstreamMaxConns = 32
+)
+
+// newUpstreamClient builds the HTTP client shared by all calls to the 1inch
+// API. Connections are pooled and kept alive across quotes, negotiated as
+// HTTP/2 where the upstream supports it, and routed through HTTPS_PROXY or
+// HTTP_PROXY when set. UPSTREAM_TIMEOUT_SECONDS and UPSTREAM_MAX_CONNS tune it.
+func newUpstreamClient(logger *log.Logger) *http.Client {
+	timeout := time.Duration(envInt(logger, "UPSTREAM_TIMEOUT_SECONDS", int(DefaultUpstreamTimeout/time.Second))) * time.Second
+	maxConns := envInt(logger, "UPSTREAM_MAX_CONNS", DefaultUpstreamMaxConns)
+
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   5 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          maxConns,
+		MaxIdleConnsPerHost:   maxConns,
+		MaxConnsPerHost:       maxConns,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   5 * time.Second,
+		ResponseHeaderTimeout: timeout,
+		ExpectContinueTimeout: time.Second,
+	}
+
+	return &http.Client{
+		Transport: observedTransport{transport},
+		Timeout:   timeout,
+	}
+}
+
+// observedTransport records the status and latency of every upstream call.
+type observedTransport struct {
+	next http.RoundTripper
+}
+
+func (t observedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+
+	status := 0
+	if err == nil {
+		status = resp.StatusCode
+	}
+	metrics.ObserveUpstream(req.Method, req.URL.Host+req.URL.Path, status, time.Since(start))
+	return resp, err
+}
@@ -0,0 +1,78 @@
+// Package auction evaluates the Fusion+ Dutch auction curve described by a
+// quote preset.
+package auction
+
+import (
+	"math"
+	"math/big"
+	"relayer/internal/common"
+	"time"
+)
+
+// RateBumpDenominator is the unit of rate bumps and point coefficients:
+// a bump of RateBumpDenominator doubles the taking amount.
+const RateBumpDenominator = 10_000_000
+
+// Curve is a Dutch auction starting at Start. The rate bump begins at
+// InitialRateBump, moves linearly through Points (each Delay seconds after the
+// previous one) and decays to zero at Start+Duration.
+type Curve struct {
+	Start           time.Time
+	Duration        time.Duration
+	InitialRateBump float64
+	Points          []common.AuctionPoint
+}
+
+// FromPreset builds the curve of an order submitted at submittedAt.
+func FromPreset(submittedAt time.Time, preset common.PresetData) Curve {
+	return Curve{
+		Start:           submittedAt.Add(time.Duration(preset.StartAuctionIn) * time.Second),
+		Duration:        time.Duration(preset.AuctionDuration) * time.Second,
+		InitialRateBump: preset.InitialRateBump,
+		Points:          preset.Points,
+	}
+}
+
+// End returns the time the rate bump reaches zero.
+func (c Curve) End() time.Time {
+	return c.Start.Add(c.Duration)
+}
+
+// RateBump returns the rate bump at t.
+func (c Curve) RateBump(t time.Time) float64 {
+	elapsed := t.Sub(c.Start).Seconds()
+	end := c.Duration.Seconds()
+	if elapsed <= 0 {
+		return c.InitialRateBump
+	}
+	if elapsed >= end {
+		return 0
+	}
+
+	prevT, prevBump := 0.0, c.InitialRateBump
+	for _, p := range c.Points {
+		pointT := prevT + float64(p.Delay)
+		if elapsed < pointT {
+			return interpolate(prevT, prevBump, pointT, p.Coefficient, elapsed)
+		}
+		prevT, prevBump = pointT, p.Coefficient
+	}
+
+	return interpolate(prevT, prevBump, end, 0, elapsed)
+}
+
+// TakingAmount returns the amount a taker must pay at t for an order whose
+// minimum (end of auction) taking amount is base.
+func (c Curve) TakingAmount(base *big.Int, t time.Time) *big.Int {
+	bump := int64(math.Round(c.RateBump(t)))
+
+	amount := new(big.Int).Mul(base, big.NewInt(RateBumpDenominator+bump))
+	return amount.Quo(amount, big.NewInt(RateBumpDenominator))
+}
+
+func interpolate(t0, v0, t1, v1, t float64) float64 {
+	if t1 <= t0 {
+		return v1
+	}
+	return v0 + (v1-v0)*(t-t0)/(t1-t0)
+}
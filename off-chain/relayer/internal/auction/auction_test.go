@@ -0,0 +1,92 @@
+package auction
+
+import (
+	"math/big"
+	"relayer/internal/common"
+	"testing"
+	"time"
+)
+
+func TestRateBump(t *testing.T) {
+	start := time.Unix(1_700_000_000, 0)
+	at := func(seconds float64) time.Time {
+		return start.Add(time.Duration(seconds * float64(time.Second)))
+	}
+
+	// 1000 at the start, 600 20s in, 200 50s in and 0 at the end, 100s in
+	curve := FromPreset(start.Add(-5*time.Second), common.PresetData{
+		StartAuctionIn:  5,
+		AuctionDuration: 100,
+		InitialRateBump: 1000,
+		Points:          []common.AuctionPoint{{Delay: 20, Coefficient: 600}, {Delay: 30, Coefficient: 200}},
+	})
+	flat := Curve{Start: start, Duration: 100 * time.Second, InitialRateBump: 1000}
+	instant := Curve{Start: start, InitialRateBump: 1000}
+
+	tests := []struct {
+		name  string
+		curve Curve
+		t     time.Time
+		want  float64
+	}{
+		{name: "before the start", curve: curve, t: at(-10), want: 1000},
+		{name: "at the start", curve: curve, t: at(0), want: 1000},
+		{name: "towards the first point", curve: curve, t: at(10), want: 800},
+		{name: "at the first point", curve: curve, t: at(20), want: 600},
+		{name: "between points", curve: curve, t: at(35), want: 400},
+		{name: "at the last point", curve: curve, t: at(50), want: 200},
+		{name: "after the last point", curve: curve, t: at(75), want: 100},
+		{name: "just before the end", curve: curve, t: at(99.5), want: 2},
+		{name: "at the end", curve: curve, t: at(100), want: 0},
+		{name: "after the end", curve: curve, t: at(500), want: 0},
+		{name: "no points", curve: flat, t: at(25), want: 750},
+		{name: "no duration at the start", curve: instant, t: at(0), want: 1000},
+		{name: "no duration after the start", curve: instant, t: at(1), want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.curve.RateBump(tt.t); got != tt.want {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if curve.End() != at(100) {
+		t.Fatalf("curve ends at %s, want %s", curve.End(), at(100))
+	}
+}
+
+func TestTakingAmount(t *testing.T) {
+	start := time.Unix(1_700_000_000, 0)
+	curve := Curve{
+		Start:           start,
+		Duration:        100 * time.Second,
+		InitialRateBump: 1_000_000,
+		Points:          []common.AuctionPoint{{Delay: 50, Coefficient: 500_000}},
+	}
+	base := big.NewInt(1_000_000)
+
+	tests := []struct {
+		name string
+		t    time.Time
+		want int64
+	}{
+		// 10% above base at the start
+		{name: "at the start", t: start, want: 1_100_000},
+		{name: "at the point", t: start.Add(50 * time.Second), want: 1_050_000},
+		{name: "between", t: start.Add(75 * time.Second), want: 1_025_000},
+		// rounded down to base units
+		{name: "fractional", t: start.Add(99_999_900 * time.Microsecond), want: 1_000_000},
+		{name: "at the end", t: start.Add(100 * time.Second), want: 1_000_000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := curve.TakingAmount(base, tt.t); got.Int64() != tt.want {
+				t.Fatalf("got %s, want %d", got, tt.want)
+			}
+			if base.Int64() != 1_000_000 {
+				t.Fatal("base changed")
+			}
+		})
+	}
+}
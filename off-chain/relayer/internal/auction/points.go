@@ -0,0 +1,63 @@
+package auction
+
+import (
+	"fmt"
+	"math"
+	"relayer/internal/common"
+)
+
+// NormalizePoints checks the auction of preset and returns it with its points
+// normalized, in a new slice. A point's delay counts from the previous point,
+// or the auction start for the first, as the SDK encodes points and Curve
+// reads them: the listed order is their time order, and a negative delay, a
+// point before its predecessor, cannot be put right. The checks are
+//   - delays are not negative and add up to at most the auction duration,
+//     points past the end would never be reached;
+//   - the initial rate bump and the coefficients are finite and not negative.
+//
+// Points with a zero delay, at the same time as their predecessor, are
+// collapsed: the curve continues from the last point of such a run, so its
+// coefficient replaces the predecessor's. Those at the auction start are
+// dropped, the initial rate bump being the curve's value there.
+func NormalizePoints(preset common.PresetData) (common.PresetData, error) {
+	if preset.AuctionDuration < 0 {
+		return preset, fmt.Errorf("negative auction duration %d", preset.AuctionDuration)
+	}
+	if !validBump(preset.InitialRateBump) {
+		return preset, fmt.Errorf("invalid initial rate bump %v", preset.InitialRateBump)
+	}
+	if preset.Points == nil {
+		return preset, nil
+	}
+
+	points := make([]common.AuctionPoint, 0, len(preset.Points))
+	var offset int64
+	for i, p := range preset.Points {
+		if p.Delay < 0 {
+			return preset, fmt.Errorf("point %d has negative delay %d", i, p.Delay)
+		}
+		if !validBump(p.Coefficient) {
+			return preset, fmt.Errorf("point %d has invalid coefficient %v", i, p.Coefficient)
+		}
+		offset += p.Delay
+		if offset > preset.AuctionDuration {
+			return preset, fmt.Errorf("point %d is %ds into a %ds auction", i, offset, preset.AuctionDuration)
+		}
+
+		if p.Delay == 0 {
+			if len(points) > 0 {
+				points[len(points)-1].Coefficient = p.Coefficient
+			}
+			continue
+		}
+		points = append(points, p)
+	}
+
+	preset.Points = points
+	return preset, nil
+}
+
+// validBump excludes NaN too, which compares false.
+func validBump(v float64) bool {
+	return v >= 0 && !math.IsInf(v, 1)
+}
@@ -0,0 +1,181 @@
+// Package bus is the relayer's internal event bus. Subsystems publish what
+// happened to the bus instead of calling whoever needs to know. Handlers run
+// in the publisher's goroutine, for subscribers whose effects must stay in
+// order with the publisher's, such as broadcasts and store writes; other
+// subscribers such as the notifications consume the events at their own pace.
+package bus
+
+import (
+	"relayer/internal/metrics"
+	"sync"
+	"time"
+)
+
+// Kind names an event.
+type Kind string
+
+const (
+	// OrderSubmitted is published once an order is accepted, Subject is its
+	// hash and Details hold srcChain, dstChain, makerAsset and makingAmount
+	OrderSubmitted Kind = "order_submitted"
+	// EscrowsVerified is published when a fill's escrows passed
+	// verification, Details hold hashIdx, srcEscrow and dstEscrow
+	EscrowsVerified Kind = "escrows_verified"
+	// SecretReleased is published once a secret of a stored order was
+	// broadcast to the resolvers
+	SecretReleased Kind = "secret_released"
+	// EscrowClosed is published when a withdrawal or cancellation of one of
+	// an order's escrows is recorded, Details hold side, escrow, state and
+	// txHash
+	EscrowClosed Kind = "escrow_closed"
+	// OrderExpired is published when a pending order is marked expired
+	OrderExpired Kind = "order_expired"
+	// OrderRefunding is published when an order whose verified fills never
+	// received their secret is given up, Details hold the stuck fills
+	OrderRefunding Kind = "order_refunding"
+	// VerificationFailed is published for every failed fill verification,
+	// Subject is the order hash and Details hold resolver, error and the
+	// verdict, retry, alert or reject
+	VerificationFailed Kind = "verification_failed"
+	// ResolverDisconnected is published when an authenticated resolver's
+	// connection ends, Subject is the resolver id
+	ResolverDisconnected Kind = "resolver_disconnected"
+	// ChainUnavailable and ChainBehind are published at every head poll an
+	// endpoint failed or trailed its lag threshold, ChainRecovered once it is
+	// healthy again; Subject is the chain id
+	ChainUnavailable Kind = "chain_unavailable"
+	ChainBehind      Kind = "chain_behind"
+	ChainRecovered   Kind = "chain_recovered"
+)
+
+// Event is one thing that happened in the relayer.
+type Event struct {
+	Kind Kind
+	Time time.Time
+	// order hash, chain id or resolver id the event is about
+	Subject string
+	Details map[string]any
+	// the publisher's own value for handlers in its package, e.g. the
+	// manager's order entry
+	Payload any
+}
+
+// Handler handles events in the publisher's goroutine.
+type Handler func(Event)
+
+// Bus fans published events out to every subscriber. Publishing never
+// blocks: events for a subscriber whose buffer is full are dropped and
+// counted in bus_dropped, while queued subscribers get every event.
+type Bus struct {
+	mu       sync.RWMutex
+	subs     map[string]chan Event
+	queues   map[string]*queue
+	handlers map[Kind][]Handler
+}
+
+func New() *Bus {
+	return &Bus{subs: make(map[string]chan Event), queues: make(map[string]*queue), handlers: make(map[Kind][]Handler)}
+}
+
+// Handle runs h for every event of kind before Publish returns, after the
+// handlers added before it. h may publish events itself.
+func (b *Bus) Handle(kind Kind, h Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.handlers[kind] = append(b.handlers[kind], h)
+}
+
+// Subscribe returns the events published from now on, buffering up to
+// buffer of them. name identifies the subscriber in the metrics.
+func (b *Bus) Subscribe(name string, buffer int) <-chan Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ch := make(chan Event, buffer)
+	b.subs[name] = ch
+	return ch
+}
+
+// Queue returns the events published from now on, every one of them: events
+// the subscriber has not taken yet wait without bound, counted in
+// bus_queued. name identifies the subscriber in the metrics.
+func (b *Bus) Queue(name string) <-chan Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	q := &queue{name: name, ready: make(chan struct{}, 1), out: make(chan Event)}
+	b.queues[name] = q
+	go q.run()
+	return q.out
+}
+
+// Publish runs the handlers of e's kind and hands e to every subscriber.
+func (b *Bus) Publish(e Event) {
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+
+	b.mu.RLock()
+	handlers := b.handlers[e.Kind]
+	b.mu.RUnlock()
+	for _, h := range handlers {
+		h(e)
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for name, ch := range b.subs {
+		select {
+		case ch <- e:
+		default:
+			metrics.BusDropped.Add(name, 1)
+		}
+	}
+	for _, q := range b.queues {
+		q.push(e)
+	}
+}
+
+// queue holds the events of a queued subscriber until it takes them.
+type queue struct {
+	name   string
+	mu     sync.Mutex
+	events []Event
+	// signals run that events were pushed
+	ready chan struct{}
+	out   chan Event
+}
+
+func (q *queue) push(e Event) {
+	q.mu.Lock()
+	q.events = append(q.events, e)
+	q.mu.Unlock()
+	metrics.BusQueued.Add(q.name, 1)
+
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
+}
+
+// run hands the queued events to the subscriber in order.
+func (q *queue) run() {
+	for range q.ready {
+		for {
+			q.mu.Lock()
+			if len(q.events) == 0 {
+				q.mu.Unlock()
+				break
+			}
+			e := q.events[0]
+			q.events[0] = Event{}
+			q.events = q.events[1:]
+			q.mu.Unlock()
+
+			q.out <- e
+			metrics.BusQueued.Add(q.name, -1)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package bus
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestQueue(t *testing.T) {
+	b := New()
+	dropping := b.Subscribe("dropping", 1)
+	queued := b.Queue("queued")
+
+	// published with nobody reading, more than any buffer
+	const n = 2000
+	for i := range n {
+		b.Publish(Event{Kind: OrderSubmitted, Subject: strconv.Itoa(i)})
+	}
+
+	if got := len(dropping); got != 1 {
+		t.Fatalf("dropping subscriber holds %d events, want 1", got)
+	}
+	for i := range n {
+		if e := <-queued; e.Subject != strconv.Itoa(i) {
+			t.Fatalf("event %d: got subject %s", i, e.Subject)
+		}
+	}
+	select {
+	case e := <-queued:
+		t.Fatalf("unexpected event %s", e.Subject)
+	default:
+	}
+}
@@ -0,0 +1,97 @@
+package chain
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// EscrowFactory getters of the implementations its escrows are cloned from
+const escrowImplementationABI = `[
+    {
+        "inputs": [],
+        "name": "ESCROW_SRC_IMPLEMENTATION",
+        "outputs": [{"internalType": "address", "name": "", "type": "address"}],
+        "stateMutability": "view",
+        "type": "function"
+    },
+    {
+        "inputs": [],
+        "name": "ESCROW_DST_IMPLEMENTATION",
+        "outputs": [{"internalType": "address", "name": "", "type": "address"}],
+        "stateMutability": "view",
+        "type": "function"
+    }
+]`
+
+var parsedEscrowImplementationABI = mustParseABI(escrowImplementationABI)
+
+// EIP-1167 minimal proxy runtime code around the implementation address
+var (
+	cloneCodePrefix = common.FromHex("0x363d3d373d3d3d363d73")
+	cloneCodeSuffix = common.FromHex("0x5af43d82803e903d91602b57fd5bf3")
+)
+
+// CloneCode returns the runtime code of a minimal proxy delegating to
+// implementation, as deployed by the escrow factory.
+func CloneCode(implementation common.Address) []byte {
+	code := make([]byte, 0, len(cloneCodePrefix)+common.AddressLength+len(cloneCodeSuffix))
+	code = append(code, cloneCodePrefix...)
+	code = append(code, implementation.Bytes()...)
+	return append(code, cloneCodeSuffix...)
+}
+
+// FetchEscrowImplementation reads the src or dst escrow implementation of an
+// escrow factory.
+func FetchEscrowImplementation(ctx context.Context, client EVMClient, factory common.Address, dst bool) (common.Address, error) {
+	method := "ESCROW_SRC_IMPLEMENTATION"
+	if dst {
+		method = "ESCROW_DST_IMPLEMENTATION"
+	}
+
+	c := bind.NewBoundContract(factory, parsedEscrowImplementationABI, client, client, client)
+
+	var out []any
+	if err := c.Call(&bind.CallOpts{Context: ctx}, &out, method); err != nil {
+		return common.Address{}, rpcError("calling "+method, err)
+	}
+
+	implementation, ok := out[0].(common.Address)
+	if !ok {
+		return common.Address{}, decodeError("implementation address", nil)
+	}
+	return implementation, nil
+}
+
+// VerifyEscrowCode checks that the code deployed at escrow hashes to that of
+// a clone of factory's src or dst escrow implementation, the EXTCODEHASH
+// check, so escrows announced by lookalike events or spoofed factories are
+// not trusted.
+func VerifyEscrowCode(ctx context.Context, client EVMClient, factory, escrow common.Address, dst bool) error {
+	implementation, err := FetchEscrowImplementation(ctx, client, factory, dst)
+	if err != nil {
+		return fmt.Errorf("fetching escrow implementation of factory %s: %w", factory.Hex(), err)
+	}
+
+	return VerifyCloneCode(ctx, client, implementation, escrow)
+}
+
+// VerifyCloneCode is VerifyEscrowCode with the factory's implementation
+// already known, e.g. read once and cached.
+func VerifyCloneCode(ctx context.Context, client EVMClient, implementation, escrow common.Address) error {
+	code, err := client.CodeAt(ctx, escrow, nil)
+	if err != nil {
+		return rpcError("fetching code of escrow "+escrow.Hex(), err)
+	}
+	if len(code) == 0 {
+		return fmt.Errorf("no code deployed at escrow %s", escrow.Hex())
+	}
+
+	if crypto.Keccak256Hash(code) != crypto.Keccak256Hash(CloneCode(implementation)) {
+		return fmt.Errorf("escrow %s is not a clone of implementation %s", escrow.Hex(), implementation.Hex())
+	}
+	return nil
+}
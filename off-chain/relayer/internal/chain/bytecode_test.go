@@ -0,0 +1,19 @@
+package chain_test
+
+import (
+	"context"
+	"relayer/internal/chain"
+	"testing"
+)
+
+func BenchmarkFetchEscrowImplementation(b *testing.B) {
+	evm := callClient()
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := chain.FetchEscrowImplementation(ctx, evm, benchFactory, i%2 == 1); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package chain
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/block-vision/sui-go-sdk/models"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// evmEscrowCancelledSig is the topic of BaseEscrow's `event EscrowCancelled()`.
+var evmEscrowCancelledSig = crypto.Keccak256Hash([]byte("EscrowCancelled()"))
+
+// FetchEvmCancelledEscrows returns the escrows that emitted EscrowCancelled in txHash.
+func FetchEvmCancelledEscrows(ctx context.Context, client EVMClient, txHash common.Hash) ([]string, error) {
+	receipt, err := client.TransactionReceipt(ctx, txHash)
+	if err != nil {
+		return nil, rpcError("fetching receipt", err)
+	}
+
+	var escrows []string
+	for _, vLog := range receipt.Logs {
+		if len(vLog.Topics) > 0 && vLog.Topics[0] == evmEscrowCancelledSig {
+			escrows = append(escrows, vLog.Address.Hex())
+		}
+	}
+
+	if len(escrows) == 0 {
+		return nil, fmt.Errorf("%w: EscrowCancelled not in the logs of tx %s", ErrEventNotFound, txHash.Hex())
+	}
+	return escrows, nil
+}
+
+// FetchMoveCancelledEscrows returns the escrow object IDs cancelled in txDigest,
+// from either src_escrow::EscrowCancelled or dst_escrow::DstEscrowCancelledEvent.
+// Events of packages other than pkg are ignored, unless pkg is empty.
+func FetchMoveCancelledEscrows(ctx context.Context, cli SuiClient, txDigest, pkg string) ([]string, error) {
+	events, err := cli.SuiGetEvents(ctx, models.SuiGetEventsRequest{
+		Digest: txDigest,
+	})
+	if err != nil {
+		return nil, rpcError("fetching events", err)
+	}
+
+	var escrows []string
+	for _, ev := range events {
+		var field string
+		switch {
+		case strings.HasSuffix(ev.Type, "::EscrowCancelled"):
+			field = "escrow_id"
+		case strings.HasSuffix(ev.Type, "::DstEscrowCancelledEvent"):
+			field = "id"
+		default:
+			continue
+		}
+		if pkg != "" && !sameMovePackage(ev.Type, pkg) {
+			continue
+		}
+
+		if id, ok := ev.ParsedJson[field].(string); ok {
+			escrows = append(escrows, id)
+		}
+	}
+
+	if len(escrows) == 0 {
+		return nil, fmt.Errorf("%w: escrow cancellation not in tx %s", ErrEventNotFound, txDigest)
+	}
+	return escrows, nil
+}
+
+// publicCancelSelector is EscrowSrc.publicCancel(Immutables).
+var publicCancelSelector = crypto.Keccak256([]byte("publicCancel((bytes32,bytes32,uint256,uint256,uint256,uint256,uint256,uint256))"))[:4]
+
+// EncodeEvmPublicCancelCalldata returns publicCancel calldata for a src
+// escrow created with immutables, its timelocks stamped with the deployment.
+func EncodeEvmPublicCancelCalldata(immutables Immutables) ([]byte, error) {
+	args, err := abi.Arguments{dstEscrowArgs[0]}.Pack(immutablesToWire(immutables))
+	if err != nil {
+		return nil, fmt.Errorf("encoding publicCancel: %w", err)
+	}
+	return append(append([]byte{}, publicCancelSelector...), args...), nil
+}
+
+// PublicCancelEvmSrcEscrow sends a publicCancel of the src escrow at escrow,
+// returning the maker's funds. It only succeeds in the public cancellation
+// window and from a holder of the escrow factory's access token.
+func PublicCancelEvmSrcEscrow(ctx context.Context, client EVMClient, opts *bind.TransactOpts, escrow common.Address, immutables Immutables) (*types.Transaction, error) {
+	data, err := EncodeEvmPublicCancelCalldata(immutables)
+	if err != nil {
+		return nil, err
+	}
+
+	c := bind.NewBoundContract(escrow, abi.ABI{}, client, client, client)
+	txOpts := *opts
+	txOpts.Context = ctx
+	return c.RawTransact(&txOpts, data)
+}
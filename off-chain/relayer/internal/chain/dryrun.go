@@ -0,0 +1,192 @@
+package chain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/block-vision/sui-go-sdk/models"
+	"github.com/block-vision/sui-go-sdk/mystenbcs"
+	"github.com/block-vision/sui-go-sdk/transaction"
+)
+
+// DryRunMoveWithdraw dev-inspects a withdrawal of the Sui escrow with secret,
+// sent by the escrow's taker, without executing it. It fails when the escrow
+// would reject the withdrawal, e.g. because its hashlock is not the hash of
+// secret under the Move contract's hash function.
+func DryRunMoveWithdraw(ctx context.Context, cli SuiClient, escrowID string, secret []byte) error {
+	resp, err := cli.SuiGetObject(ctx, models.SuiGetObjectRequest{
+		ObjectId: escrowID,
+		Options: models.SuiObjectDataOptions{
+			ShowType:    true,
+			ShowOwner:   true,
+			ShowContent: true,
+		},
+	})
+	if err != nil {
+		return rpcError("SuiGetObject", err)
+	}
+	if resp.Data == nil || resp.Data.Content == nil {
+		return fmt.Errorf("escrow %s has no parsed content", escrowID)
+	}
+
+	// 0x<pkg>::src_escrow::SrcEscrow<T> or 0x<pkg>::dst_escrow::DstEscrow<T>
+	escrowType, err := parseStructTag(resp.Data.Type)
+	if err != nil {
+		return fmt.Errorf("escrow %s: %w", escrowID, err)
+	}
+	if len(escrowType.TypeParams) != 1 {
+		return fmt.Errorf("escrow %s has type %s, expected one type argument", escrowID, resp.Data.Type)
+	}
+
+	sharedVersion, err := initialSharedVersion(resp.Data.Owner)
+	if err != nil {
+		return fmt.Errorf("escrow %s: %w", escrowID, err)
+	}
+
+	immutables, _ := resp.Data.Content.Fields["immutables"].(map[string]any)
+	fields, _ := immutables["fields"].(map[string]any)
+	taker, _ := fields["taker"].(string)
+	if taker == "" {
+		return fmt.Errorf("escrow %s has no taker", escrowID)
+	}
+
+	tx, err := withdrawTx(escrowType, escrowID, sharedVersion, taker, secret)
+	if err != nil {
+		return err
+	}
+	kind, err := tx.Data.V1.Kind.Marshal()
+	if err != nil {
+		return fmt.Errorf("encoding transaction: %w", err)
+	}
+
+	result, err := cli.SuiDevInspectTransactionBlock(ctx, models.SuiDevInspectTransactionBlockRequest{
+		Sender:  taker,
+		TxBytes: mystenbcs.ToBase64(kind),
+	})
+	if err != nil {
+		return rpcError("dev-inspect", err)
+	}
+	if status := result.Effects.Status; status.Status != "success" {
+		return fmt.Errorf("withdrawal of escrow %s would fail: %s", escrowID, status.Error)
+	}
+
+	return nil
+}
+
+// withdrawTx builds the taker's withdrawal of the escrow: dst escrows return
+// the safety deposit, which is sent back to the taker, src escrows pay out to
+// a target, the taker.
+func withdrawTx(escrowType *transaction.StructTag, escrowID string, sharedVersion uint64, taker string, secret []byte) (tx *transaction.Transaction, err error) {
+	// the builder panics on malformed addresses
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("building withdrawal: %v", r)
+		}
+	}()
+
+	clockID, err := transaction.ConvertSuiAddressStringToBytes(suiClockObject)
+	if err != nil {
+		return nil, err
+	}
+	escrowObjectID, err := transaction.ConvertSuiAddressStringToBytes(models.SuiAddress(escrowID))
+	if err != nil {
+		return nil, err
+	}
+
+	tx = transaction.NewTransaction()
+	clock := tx.Object(transaction.CallArg{Object: &transaction.ObjectArg{SharedObject: &transaction.SharedObjectRef{
+		ObjectId:             *clockID,
+		InitialSharedVersion: 1,
+	}}})
+	escrow := tx.Object(transaction.CallArg{Object: &transaction.ObjectArg{SharedObject: &transaction.SharedObjectRef{
+		ObjectId:             *escrowObjectID,
+		InitialSharedVersion: sharedVersion,
+		Mutable:              true,
+	}}})
+
+	pkg := models.SuiAddress(transaction.ConvertSuiAddressBytesToString(escrowType.Address))
+	typeArgs := []transaction.TypeTag{*escrowType.TypeParams[0]}
+
+	switch escrowType.Name {
+	case "DstEscrow":
+		deposit := tx.MoveCall(pkg, escrowType.Module, "withdraw", typeArgs, []transaction.Argument{clock, escrow, tx.Pure(secret)})
+		tx.TransferObjects([]transaction.Argument{deposit}, tx.Pure(taker))
+	case "SrcEscrow":
+		tx.MoveCall(pkg, escrowType.Module, "withdraw_to", typeArgs, []transaction.Argument{clock, escrow, tx.Pure(secret), tx.Pure(taker)})
+	default:
+		return nil, fmt.Errorf("object %s is not an escrow", escrowID)
+	}
+
+	return tx, nil
+}
+
+// initialSharedVersion reads the version a shared object was shared at from
+// its owner field: {"Shared": {"initial_shared_version": N}}.
+func initialSharedVersion(owner any) (uint64, error) {
+	raw, err := json.Marshal(owner)
+	if err != nil {
+		return 0, err
+	}
+
+	var parsed models.ObjectOwner
+	if err := json.Unmarshal(raw, &parsed); err != nil || parsed.Shared.InitialSharedVersion == 0 {
+		return 0, errors.New("not a shared object")
+	}
+	return parsed.Shared.InitialSharedVersion, nil
+}
+
+// parseStructTag parses a Move struct type such as
+// 0x2::coin::Coin<0x2::sui::SUI>. Type arguments must be structs too.
+func parseStructTag(s string) (*transaction.StructTag, error) {
+	s = strings.TrimSpace(s)
+	base, params, generic := strings.Cut(s, "<")
+
+	parts := strings.Split(base, "::")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("invalid move type %q", s)
+	}
+	address, err := transaction.ConvertSuiAddressStringToBytes(models.SuiAddress(parts[0]))
+	if err != nil {
+		return nil, fmt.Errorf("invalid move type %q: %w", s, err)
+	}
+
+	tag := &transaction.StructTag{Address: *address, Module: parts[1], Name: parts[2]}
+	if !generic {
+		return tag, nil
+	}
+	if !strings.HasSuffix(params, ">") {
+		return nil, fmt.Errorf("invalid move type %q", s)
+	}
+
+	for _, param := range splitTypeParams(strings.TrimSuffix(params, ">")) {
+		inner, err := parseStructTag(param)
+		if err != nil {
+			return nil, err
+		}
+		tag.TypeParams = append(tag.TypeParams, &transaction.TypeTag{Struct: inner})
+	}
+	return tag, nil
+}
+
+// splitTypeParams splits a type argument list at its top level commas.
+func splitTypeParams(s string) []string {
+	var params []string
+	depth, start := 0, 0
+	for i, r := range s {
+		switch r {
+		case '<':
+			depth++
+		case '>':
+			depth--
+		case ',':
+			if depth == 0 {
+				params = append(params, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(params, s[start:])
+}
@@ -0,0 +1,88 @@
+package chain
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// The encoders below are the inverse of the Fetch functions decoding escrow
+// deployments, so mock chains can serve fabricated fills the relayer verifies
+// like real ones.
+
+// createDstEscrowSelector is EscrowFactory.createDstEscrow(Immutables, uint256).
+var createDstEscrowSelector = crypto.Keccak256([]byte("createDstEscrow((bytes32,bytes32,uint256,uint256,uint256,uint256,uint256,uint256),uint256)"))[:4]
+
+func immutablesToWire(imm Immutables) dstImmutablesWire {
+	return dstImmutablesWire{
+		OrderHash:     imm.OrderHash,
+		Hashlock:      imm.Hashlock,
+		Maker:         new(big.Int).SetBytes(imm.Maker.Bytes()),
+		Taker:         new(big.Int).SetBytes(imm.Taker.Bytes()),
+		Token:         new(big.Int).SetBytes(imm.Token.Bytes()),
+		Amount:        orZero(imm.Amount),
+		SafetyDeposit: orZero(imm.SafetyDeposit),
+		Timelocks:     orZero(imm.Timelocks),
+	}
+}
+
+func orZero(v *big.Int) *big.Int {
+	if v == nil {
+		return new(big.Int)
+	}
+	return v
+}
+
+// EncodeEvmSrcEscrowCreated returns the SrcEscrowCreated log factory emits
+// for evt, as decoded by FetchEvmSrcEscrowEvent.
+func EncodeEvmSrcEscrowCreated(factory common.Address, evt EvmSrcEscrowCreatedEvent) (*types.Log, error) {
+	// the complement's token is the decimal or hex form of the uint256
+	token, ok := new(big.Int).SetString(evt.DstImmutablesComplement.Token, 0)
+	if !ok {
+		token = new(big.Int).SetBytes(common.HexToAddress(evt.DstImmutablesComplement.Token).Bytes())
+	}
+	complement := struct {
+		Maker         *big.Int
+		Amount        *big.Int
+		Token         *big.Int
+		SafetyDeposit *big.Int
+		ChainId       *big.Int
+	}{
+		Maker:         new(big.Int).SetBytes(evt.DstImmutablesComplement.Maker.Bytes()),
+		Amount:        orZero(evt.DstImmutablesComplement.Amount),
+		Token:         token,
+		SafetyDeposit: orZero(evt.DstImmutablesComplement.SafetyDeposit),
+		ChainId:       orZero(evt.DstImmutablesComplement.ChainId),
+	}
+
+	event := parsedEscrowABI.Events["SrcEscrowCreated"]
+	data, err := event.Inputs.NonIndexed().Pack(immutablesToWire(evt.SrcImmutables), complement)
+	if err != nil {
+		return nil, fmt.Errorf("encoding SrcEscrowCreated: %w", err)
+	}
+	return &types.Log{Address: factory, Topics: []common.Hash{event.ID}, Data: data}, nil
+}
+
+// EncodeEvmDstEscrowCreated returns the DstEscrowCreated log factory emits
+// for evt, as decoded by FetchEvmDstEscrowEvent.
+func EncodeEvmDstEscrowCreated(factory common.Address, evt EvmDstEscrowCreatedEvent) (*types.Log, error) {
+	event := parsedDstEscrowABI.Events["DstEscrowCreated"]
+	data, err := event.Inputs.NonIndexed().Pack(evt.Escrow, [32]byte(evt.Hashlock), new(big.Int).SetBytes(evt.Taker.Bytes()))
+	if err != nil {
+		return nil, fmt.Errorf("encoding DstEscrowCreated: %w", err)
+	}
+	return &types.Log{Address: factory, Topics: []common.Hash{event.ID}, Data: data}, nil
+}
+
+// EncodeEvmDstEscrowCalldata returns createDstEscrow calldata deploying a dst
+// escrow with immutables, as decoded by FetchEvmDstImmutables.
+func EncodeEvmDstEscrowCalldata(immutables Immutables, srcCancellationTimestamp *big.Int) ([]byte, error) {
+	args, err := dstEscrowArgs.Pack(immutablesToWire(immutables), orZero(srcCancellationTimestamp))
+	if err != nil {
+		return nil, fmt.Errorf("encoding createDstEscrow: %w", err)
+	}
+	return append(append([]byte{}, createDstEscrowSelector...), args...), nil
+}
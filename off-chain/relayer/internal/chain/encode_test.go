@@ -0,0 +1,119 @@
+package chain
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	abiFactory    = common.HexToAddress("0xa7bcb4eac8964306f9e3764f67db6a7af6ddf99a")
+	abiImmutables = Immutables{
+		OrderHash:     common.HexToHash("0x01"),
+		Hashlock:      common.HexToHash("0x02"),
+		Maker:         common.HexToAddress("0x00000000219ab540356cbb839cbe05303d7705fa"),
+		Taker:         common.HexToAddress("0x3f1d7a6e5c4b3a2918f7e6d5c4b3a2918f7e6d5c"),
+		Token:         common.HexToAddress("0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2"),
+		Amount:        big.NewInt(1_000_000_000_000_000_000),
+		SafetyDeposit: big.NewInt(10_000_000_000_000_000),
+		Timelocks:     big.NewInt(1_700_000_000),
+	}
+	abiSrcEscrowCreated = EvmSrcEscrowCreatedEvent{
+		SrcImmutables: abiImmutables,
+		DstImmutablesComplement: DstImmutablesComplement{
+			Maker:   common.HexToAddress("0x00000000219ab540356cbb839cbe05303d7705fa"),
+			Amount:  big.NewInt(1_420_000_000),
+			Token:   "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48",
+			ChainId: big.NewInt(101), // Sui
+		},
+	}
+	abiDstEscrowCreated = EvmDstEscrowCreatedEvent{
+		Escrow:   common.HexToAddress("0x9e8d7c6b5a4f3e2d1c0b9a8f7e6d5c4b3a2f1e0d"),
+		Hashlock: common.HexToHash("0x02"),
+		Taker:    common.HexToAddress("0x3f1d7a6e5c4b3a2918f7e6d5c4b3a2918f7e6d5c"),
+	}
+)
+
+func BenchmarkPackSrcEscrowCreated(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := EncodeEvmSrcEscrowCreated(abiFactory, abiSrcEscrowCreated); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkUnpackSrcEscrowCreated(b *testing.B) {
+	log, err := EncodeEvmSrcEscrowCreated(abiFactory, abiSrcEscrowCreated)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := parsedEscrowABI.Unpack("SrcEscrowCreated", log.Data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkPackDstEscrowCreated(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := EncodeEvmDstEscrowCreated(abiFactory, abiDstEscrowCreated); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkUnpackDstEscrowCreated(b *testing.B) {
+	log, err := EncodeEvmDstEscrowCreated(abiFactory, abiDstEscrowCreated)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := parsedDstEscrowABI.Unpack("DstEscrowCreated", log.Data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkPackDstEscrowCalldata(b *testing.B) {
+	cancellation := big.NewInt(1_700_003_600)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := EncodeEvmDstEscrowCalldata(abiImmutables, cancellation); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkUnpackDstEscrowCalldata(b *testing.B) {
+	data, err := EncodeEvmDstEscrowCalldata(abiImmutables, big.NewInt(1_700_003_600))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := dstEscrowArgs.Unpack(data[4:]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkPackPublicCancelCalldata(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := EncodeEvmPublicCancelCalldata(abiImmutables); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package chain
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// SeriesEpochManager getter of the limit order protocol
+const epochManagerABI = `[
+    {
+        "inputs": [
+            {"internalType": "address", "name": "maker", "type": "address"},
+            {"internalType": "uint96", "name": "series", "type": "uint96"}
+        ],
+        "name": "epoch",
+        "outputs": [{"internalType": "uint256", "name": "", "type": "uint256"}],
+        "stateMutability": "view",
+        "type": "function"
+    }
+]`
+
+var parsedEpochManagerABI = mustParseABI(epochManagerABI)
+
+// FetchEvmEpoch reads the current epoch of maker's series from the limit
+// order protocol, which is its own epoch manager. Orders signed for an
+// earlier epoch can no longer be filled.
+func FetchEvmEpoch(ctx context.Context, client EVMClient, protocol, maker common.Address, series uint64) (*big.Int, error) {
+	c := bind.NewBoundContract(protocol, parsedEpochManagerABI, client, client, client)
+
+	var out []any
+	if err := c.Call(&bind.CallOpts{Context: ctx}, &out, "epoch", maker, new(big.Int).SetUint64(series)); err != nil {
+		return nil, err
+	}
+
+	epoch, ok := out[0].(*big.Int)
+	if !ok {
+		return nil, errors.New("failed to unpack epoch")
+	}
+	return epoch, nil
+}
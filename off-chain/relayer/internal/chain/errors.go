@@ -0,0 +1,54 @@
+package chain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Classes of the errors returned by the chain functions, to be matched with
+// errors.Is. An error in none of them, e.g. a reverted call or an escrow that
+// is not a clone of its factory's implementation, is a verdict on what was
+// read rather than a failure to read it.
+var (
+	// ErrTxNotFound is wrapped when the endpoint does not know a transaction,
+	// not mined or not indexed yet; asking again later may succeed.
+	ErrTxNotFound = errors.New("transaction not found")
+	// ErrEventNotFound is wrapped when a transaction the endpoint knows did not
+	// emit the expected event, which asking again will not change.
+	ErrEventNotFound = errors.New("event not found")
+	// ErrRPCUnavailable is wrapped when the endpoint could not be reached or
+	// failed to answer: transport errors, timeouts, HTTP errors.
+	ErrRPCUnavailable = errors.New("rpc unavailable")
+	// ErrDecoding is wrapped when an answer or an event could not be decoded.
+	ErrDecoding = errors.New("decoding failed")
+)
+
+// suiTxNotFound is the message of Sui fullnodes for unknown digests.
+const suiTxNotFound = "Could not find the referenced transaction"
+
+// rpcError classifies the failure of an RPC call made to do what. Answers of
+// the endpoint, JSON-RPC errors such as reverts, are left unclassified.
+func rpcError(what string, err error) error {
+	var answered rpc.Error
+	switch {
+	case errors.Is(err, ethereum.NotFound), strings.Contains(err.Error(), suiTxNotFound):
+		return fmt.Errorf("%s: %w: %w", what, ErrTxNotFound, err)
+	case errors.As(err, &answered), strings.HasPrefix(err.Error(), `{"code"`):
+		// the BlockVision client returns JSON-RPC errors as their JSON
+		return fmt.Errorf("%s: %w", what, err)
+	default:
+		return fmt.Errorf("%s: %w: %w", what, ErrRPCUnavailable, err)
+	}
+}
+
+// decodeError marks the failure to decode what.
+func decodeError(what string, err error) error {
+	if err == nil {
+		return fmt.Errorf("%w: %s", ErrDecoding, what)
+	}
+	return fmt.Errorf("%w: %s: %w", ErrDecoding, what, err)
+}
@@ -2,16 +2,14 @@ package chain
 
 import (
 	"context"
-	"errors"
+	"fmt"
 	"log"
 	"math/big"
 	"strings"
-	"time"
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/ethclient"
 )
 
 // — ABI JSON for the event (only the SrcEscrowCreated part) —
@@ -106,6 +104,17 @@ const escrowABI = `[
     }
 ]`
 
+var parsedEscrowABI = mustParseABI(escrowABI)
+
+// mustParseABI parses the ABI JSON of a contract once, at init.
+func mustParseABI(abiJSON string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
+
 // --- Go types matching the Solidity structs, with `abi` tags for UnpackIntoInterface ---
 type Immutables struct {
 	OrderHash     common.Hash    `abi:"orderHash" json:"orderHash"`
@@ -134,36 +143,30 @@ type EvmSrcEscrowCreatedEvent struct {
 // FetchEvmSrcEscrowEvent pulls the SrcEscrowCreated event from txHash and parses it.
 func FetchEvmSrcEscrowEvent(
 	ctx context.Context,
-	client *ethclient.Client,
+	client EVMClient,
 	txHash common.Hash,
-) (*EvmSrcEscrowCreatedEvent, common.Address, time.Time, error) {
-	// 1. Parse the ABI
-	parsed, err := abi.JSON(strings.NewReader(escrowABI))
-	if err != nil {
-		return nil, common.Address{}, time.Time{}, err
-	}
-
-	// 2. Get the receipt
+) (*EvmSrcEscrowCreatedEvent, common.Address, EventTime, error) {
+	// 1. Get the receipt
 	receipt, err := client.TransactionReceipt(ctx, txHash)
 	if err != nil {
-		return nil, common.Address{}, time.Time{}, err
+		return nil, common.Address{}, EventTime{}, rpcError("fetching receipt", err)
 	}
 
-	// 2a. Fetch the block timestamp
+	// 1a. Fetch the block timestamp
 	timestamp, err := FetchEvmTimeByBlockNumber(ctx, client, receipt.BlockNumber)
 	if err != nil {
-		return nil, common.Address{}, time.Time{}, err
+		return nil, common.Address{}, EventTime{}, err
 	}
 
-	// 3. Iterate logs to find our event
-	sigHash := parsed.Events["SrcEscrowCreated"].ID
+	// 2. Iterate logs to find our event
+	sigHash := parsedEscrowABI.Events["SrcEscrowCreated"].ID
 	for _, vLog := range receipt.Logs {
 		if len(vLog.Topics) > 0 && vLog.Topics[0] == sigHash {
 
-			// 4. Decode into our Go struct
-			unpacked, err := parsed.Unpack("SrcEscrowCreated", vLog.Data)
+			// 3. Decode into our Go struct
+			unpacked, err := parsedEscrowABI.Unpack("SrcEscrowCreated", vLog.Data)
 			if err != nil {
-				return nil, common.Address{}, time.Time{}, err
+				return nil, common.Address{}, EventTime{}, decodeError("SrcEscrowCreated", err)
 			}
 
 			srcImmutables := unpacked[0].(struct {
@@ -206,14 +209,14 @@ func FetchEvmSrcEscrowEvent(
 
 			srcEscrowAddress, err := FetchSrcEscrowAddress(ctx, client, vLog.Address, srcImmutables)
 			if err != nil {
-				return nil, common.Address{}, time.Time{}, err
+				return nil, common.Address{}, EventTime{}, err
 			}
 
 			return &evt, srcEscrowAddress, timestamp, nil
 		}
 	}
 
-	return nil, common.Address{}, time.Time{}, errors.New("SrcEscrowCreated event not found in transaction logs")
+	return nil, common.Address{}, EventTime{}, fmt.Errorf("%w: SrcEscrowCreated not in the logs of tx %s", ErrEventNotFound, txHash.Hex())
 }
 
 // ABI fragment containing only our event
@@ -245,6 +248,8 @@ const dstEscrowABI = `[
     }
 ]`
 
+var parsedDstEscrowABI = mustParseABI(dstEscrowABI)
+
 // Go struct matching the event fields
 type EvmDstEscrowCreatedEvent struct {
 	Escrow   common.Address `abi:"escrow"`
@@ -256,51 +261,45 @@ type EvmDstEscrowCreatedEvent struct {
 // emitted by txHash, returning its strongly-typed Go struct.
 func FetchEvmDstEscrowEvent(
 	ctx context.Context,
-	client *ethclient.Client,
+	client EVMClient,
 	txHash common.Hash,
-) (*EvmDstEscrowCreatedEvent, time.Time, error) {
-	// 1. Parse the minimal ABI
-	parsedABI, err := abi.JSON(strings.NewReader(dstEscrowABI))
-	if err != nil {
-		return nil, time.Time{}, err
-	}
-
-	// 2. Get the transaction receipt (contains all logs)
+) (*EvmDstEscrowCreatedEvent, EventTime, error) {
+	// 1. Get the transaction receipt (contains all logs)
 	receipt, err := client.TransactionReceipt(ctx, txHash)
 	if err != nil {
-		return nil, time.Time{}, err
+		return nil, EventTime{}, rpcError("fetching receipt", err)
 	}
 
-	// 2a. Fetch the block timestamp
+	// 1a. Fetch the block timestamp
 	timestamp, err := FetchEvmTimeByBlockNumber(ctx, client, receipt.BlockNumber)
 	if err != nil {
-		return nil, time.Time{}, err
+		return nil, EventTime{}, err
 	}
 
-	// 3. Compute the event signature hash
-	sig := parsedABI.Events["DstEscrowCreated"].ID
+	// 2. Compute the event signature hash
+	sig := parsedDstEscrowABI.Events["DstEscrowCreated"].ID
 
-	// 4. Scan logs for our event
+	// 3. Scan logs for our event
 	for _, vLog := range receipt.Logs {
 		if len(vLog.Topics) > 0 && vLog.Topics[0] == sig {
-			unpacked, err := parsedABI.Unpack("DstEscrowCreated", vLog.Data)
+			unpacked, err := parsedDstEscrowABI.Unpack("DstEscrowCreated", vLog.Data)
 			if err != nil {
-				return nil, time.Time{}, err
+				return nil, EventTime{}, decodeError("DstEscrowCreated", err)
 			}
 
 			escrow, ok := unpacked[0].(common.Address)
 			if !ok {
-				return nil, time.Time{}, errors.New("failed to unpack escrow address")
+				return nil, EventTime{}, decodeError("DstEscrowCreated escrow address", nil)
 			}
 
 			hashlock, ok := unpacked[1].([32]byte)
 			if !ok {
-				return nil, time.Time{}, errors.New("failed to unpack hashlock")
+				return nil, EventTime{}, decodeError("DstEscrowCreated hashlock", nil)
 			}
 
 			taker, ok := unpacked[2].(*big.Int)
 			if !ok {
-				return nil, time.Time{}, errors.New("failed to unpack taker address")
+				return nil, EventTime{}, decodeError("DstEscrowCreated taker address", nil)
 			}
 
 			evt := EvmDstEscrowCreatedEvent{
@@ -313,24 +312,24 @@ func FetchEvmDstEscrowEvent(
 		}
 	}
 
-	return nil, time.Time{}, errors.New("DstEscrowCreated event not found")
+	return nil, EventTime{}, fmt.Errorf("%w: DstEscrowCreated not in the logs of tx %s", ErrEventNotFound, txHash.Hex())
 }
 
 func FetchEvmTimeByBlockNumber(
 	ctx context.Context,
-	client *ethclient.Client,
+	client EVMClient,
 	blockNumber *big.Int,
-) (time.Time, error) {
-	block, err := client.BlockByNumber(ctx, blockNumber)
+) (EventTime, error) {
+	header, err := client.HeaderByNumber(ctx, blockNumber)
 	if err != nil {
-		return time.Time{}, err
+		return EventTime{}, rpcError("fetching block header", err)
 	}
 
-	return time.Unix(int64(block.Time()), 0), nil
+	return EvmEventTime(header.Time), nil
 }
 
 func FetchERC20Balance(
-	client *ethclient.Client,
+	client EVMClient,
 	token common.Address,
 	account common.Address,
 ) (*big.Int, error) {
@@ -339,7 +338,21 @@ func FetchERC20Balance(
 		log.Fatal(err)
 	}
 
-	return instance.BalanceOf(&bind.CallOpts{}, account)
+	balance, err := instance.BalanceOf(&bind.CallOpts{}, account)
+	if err != nil {
+		return nil, rpcError("fetching ERC20 balance", err)
+	}
+	return balance, nil
+}
+
+// FetchNativeBalance returns the gas token balance of account in wei, e.g.
+// the safety deposit held by an escrow.
+func FetchNativeBalance(ctx context.Context, client EVMClient, account common.Address) (*big.Int, error) {
+	balance, err := client.BalanceAt(ctx, account, nil)
+	if err != nil {
+		return nil, rpcError("fetching native balance", err)
+	}
+	return balance, nil
 }
 
 // EscrowFactory ABI for addressOfEscrowSrc function
@@ -407,10 +420,12 @@ const escrowFactoryABI = `[
     }
 ]`
 
+var parsedEscrowFactoryABI = mustParseABI(escrowFactoryABI)
+
 // FetchSrcEscrowAddress calls the addressOfEscrowSrc function on the escrow factory contract
 func FetchSrcEscrowAddress(
 	ctx context.Context,
-	client *ethclient.Client,
+	client EVMClient,
 	factoryAddress common.Address,
 	immutables struct {
 		OrderHash     [32]byte `json:"orderHash"`
@@ -423,23 +438,17 @@ func FetchSrcEscrowAddress(
 		Timelocks     *big.Int `json:"timelocks"`
 	},
 ) (common.Address, error) {
-	// Parse the ABI
-	parsedABI, err := abi.JSON(strings.NewReader(escrowFactoryABI))
-	if err != nil {
-		return common.Address{}, err
-	}
-
-	c := bind.NewBoundContract(factoryAddress, parsedABI, client, client, client)
+	c := bind.NewBoundContract(factoryAddress, parsedEscrowFactoryABI, client, client, client)
 
 	var out []any
-	err = c.Call(
+	err := c.Call(
 		&bind.CallOpts{Context: ctx},
 		&out,
 		"addressOfEscrowSrc",
 		immutables,
 	)
 	if err != nil {
-		return common.Address{}, err
+		return common.Address{}, rpcError("calling addressOfEscrowSrc", err)
 	}
 
 	return (out[0].(common.Address)), nil
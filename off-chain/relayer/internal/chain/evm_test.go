@@ -0,0 +1,88 @@
+package chain_test
+
+import (
+	"context"
+	"math/big"
+	"relayer/internal/chain"
+	"relayer/internal/chain/mock"
+	"relayer/internal/common"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+var (
+	benchFactory = ethcommon.HexToAddress("0xa7bcb4eac8964306f9e3764f67db6a7af6ddf99a")
+	benchTx      = ethcommon.HexToHash("0x8c2b3f1d7a6e5c4b3a2918f7e6d5c4b3a2918f7e6d5c4b3a2918f7e6d5c4b3a2")
+)
+
+// callClient answers every call, such as addressOfEscrowSrc, with an address.
+func callClient() *mock.EVMClient {
+	evm := mock.NewEVMClient()
+	evm.CallFunc = func(ethereum.CallMsg) ([]byte, error) {
+		return ethcommon.LeftPadBytes(benchFactory.Bytes(), 32), nil
+	}
+	return evm
+}
+
+// receiptClient is a callClient serving log in the receipt of benchTx.
+func receiptClient(log *types.Log) *mock.EVMClient {
+	evm := callClient()
+	evm.AddReceipt(benchTx, &types.Receipt{Status: types.ReceiptStatusSuccessful, Logs: []*types.Log{log}}, 1_700_000_000)
+	return evm
+}
+
+func BenchmarkFetchEvmSrcEscrowEvent(b *testing.B) {
+	log, err := chain.EncodeEvmSrcEscrowCreated(benchFactory, chain.EvmSrcEscrowCreatedEvent{
+		SrcImmutables: chain.Immutables{
+			OrderHash: ethcommon.HexToHash("0x01"),
+			Hashlock:  ethcommon.HexToHash("0x02"),
+			Maker:     ethcommon.HexToAddress("0x00000000219ab540356cbb839cbe05303d7705fa"),
+			Taker:     ethcommon.HexToAddress("0x3f1d7a6e5c4b3a2918f7e6d5c4b3a2918f7e6d5c"),
+			Token:     ethcommon.HexToAddress("0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2"),
+			Amount:    big.NewInt(1_000_000_000_000_000_000),
+		},
+		DstImmutablesComplement: chain.DstImmutablesComplement{
+			Maker:   ethcommon.HexToAddress("0x00000000219ab540356cbb839cbe05303d7705fa"),
+			Amount:  big.NewInt(1_420_000_000),
+			Token:   "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48",
+			ChainId: big.NewInt(int64(common.Sui)),
+		},
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+	evm := receiptClient(log)
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, _, _, err := chain.FetchEvmSrcEscrowEvent(ctx, evm, benchTx); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkFetchEvmDstEscrowEvent(b *testing.B) {
+	log, err := chain.EncodeEvmDstEscrowCreated(benchFactory, chain.EvmDstEscrowCreatedEvent{
+		Escrow:   ethcommon.HexToAddress("0x9e8d7c6b5a4f3e2d1c0b9a8f7e6d5c4b3a2f1e0d"),
+		Hashlock: ethcommon.HexToHash("0x02"),
+		Taker:    ethcommon.HexToAddress("0x3f1d7a6e5c4b3a2918f7e6d5c4b3a2918f7e6d5c"),
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+	evm := receiptClient(log)
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, _, err := chain.FetchEvmDstEscrowEvent(ctx, evm, benchTx); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
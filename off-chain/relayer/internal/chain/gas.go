@@ -0,0 +1,201 @@
+package chain
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math/big"
+	"slices"
+	"strconv"
+
+	"github.com/block-vision/sui-go-sdk/models"
+	"github.com/block-vision/sui-go-sdk/mystenbcs"
+	"github.com/block-vision/sui-go-sdk/transaction"
+)
+
+const (
+	// SuiCoinType is the gas coin type
+	SuiCoinType = "0x2::sui::SUI"
+	// MaxMoveGasCoins is how many coins a Sui transaction may pay gas with
+	MaxMoveGasCoins = 256
+	// moveGasMarginPct is added to an estimated gas budget, as the cost of
+	// shared object transactions moves with the objects between estimate and execution
+	moveGasMarginPct = 20
+	// minMoveGasBudget is the budget floor, in MIST, of an estimated transaction
+	minMoveGasBudget = 2_000_000
+	// suiCoinsPage is the largest page suix_getCoins serves
+	suiCoinsPage = 50
+)
+
+// SuiCoin is an owned coin object, the reference a transaction needs to use
+// it and its balance in the coin's base units.
+type SuiCoin struct {
+	ID      string
+	Version uint64
+	Digest  string
+	Balance uint64
+}
+
+// FetchMoveCoins lists every coin of coinType owned by owner.
+func FetchMoveCoins(ctx context.Context, cli SuiClient, owner, coinType string) ([]SuiCoin, error) {
+	var coins []SuiCoin
+	var cursor any
+	for {
+		page, err := cli.SuiXGetCoins(ctx, models.SuiXGetCoinsRequest{
+			Owner:    owner,
+			CoinType: coinType,
+			Cursor:   cursor,
+			Limit:    suiCoinsPage,
+		})
+		if err != nil {
+			return nil, rpcError(fmt.Sprintf("listing %s coins of %s", coinType, owner), err)
+		}
+
+		for _, c := range page.Data {
+			version, err := strconv.ParseUint(c.Version, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("coin %s has invalid version %q", c.CoinObjectId, c.Version)
+			}
+			balance, err := strconv.ParseUint(c.Balance, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("coin %s has invalid balance %q", c.CoinObjectId, c.Balance)
+			}
+			coins = append(coins, SuiCoin{ID: c.CoinObjectId, Version: version, Digest: c.Digest, Balance: balance})
+		}
+
+		if !page.HasNextPage || page.NextCursor == "" {
+			return coins, nil
+		}
+		cursor = page.NextCursor
+	}
+}
+
+// SelectCoins picks the fewest of coins, largest first, whose balances sum to
+// at least amount, using no more than limit coins.
+func SelectCoins(coins []SuiCoin, amount uint64, limit int) ([]SuiCoin, error) {
+	sorted := slices.Clone(coins)
+	slices.SortFunc(sorted, func(a, b SuiCoin) int { return cmp.Compare(b.Balance, a.Balance) })
+
+	var total uint64
+	for i, c := range sorted {
+		if i == limit {
+			break
+		}
+		total += c.Balance
+		if total >= amount {
+			return sorted[:i+1], nil
+		}
+	}
+	return nil, fmt.Errorf("coins cover %d of the %d needed with at most %d coins", total, amount, limit)
+}
+
+// MergeMoveCoins adds commands merging coins into the first of them to tx
+// and returns the merged coin. Gas coins must be set with SetMoveGasCoins
+// instead, the gas payment is merged by the transaction itself.
+func MergeMoveCoins(tx *transaction.Transaction, coins []SuiCoin) (merged transaction.Argument, err error) {
+	if len(coins) == 0 {
+		return transaction.Argument{}, fmt.Errorf("no coins to merge")
+	}
+
+	// the builder panics on malformed references
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("merging coins: %v", r)
+		}
+	}()
+
+	args := make([]transaction.Argument, len(coins))
+	for i, c := range coins {
+		ref, err := coinRef(c)
+		if err != nil {
+			return transaction.Argument{}, err
+		}
+		args[i] = tx.Object(transaction.CallArg{Object: &transaction.ObjectArg{ImmOrOwnedObject: ref}})
+	}
+
+	if len(args) > 1 {
+		tx.MergeCoins(args[0], args[1:])
+	}
+	return args[0], nil
+}
+
+// SetMoveGasCoins makes tx pay its gas with coins, SUI coins of the sender.
+func SetMoveGasCoins(tx *transaction.Transaction, coins []SuiCoin) error {
+	if len(coins) == 0 || len(coins) > MaxMoveGasCoins {
+		return fmt.Errorf("gas must be paid with 1 to %d coins, got %d", MaxMoveGasCoins, len(coins))
+	}
+
+	payment := make([]transaction.SuiObjectRef, len(coins))
+	for i, c := range coins {
+		ref, err := coinRef(c)
+		if err != nil {
+			return err
+		}
+		payment[i] = *ref
+	}
+	tx.SetGasPayment(payment)
+	return nil
+}
+
+func coinRef(c SuiCoin) (*transaction.SuiObjectRef, error) {
+	id, err := transaction.ConvertSuiAddressStringToBytes(models.SuiAddress(c.ID))
+	if err != nil {
+		return nil, fmt.Errorf("coin %s: %w", c.ID, err)
+	}
+	digest, err := transaction.ConvertObjectDigestStringToBytes(models.ObjectDigest(c.Digest))
+	if err != nil {
+		return nil, fmt.Errorf("coin %s: %w", c.ID, err)
+	}
+	return &transaction.SuiObjectRef{ObjectId: *id, Version: c.Version, Digest: *digest}, nil
+}
+
+// MoveGas is the gas price and budget, in MIST, to send a transaction with.
+type MoveGas struct {
+	Price  uint64
+	Budget uint64
+}
+
+// EstimateMoveGas dev-inspects the commands of tx as sender at the reference
+// gas price and returns a budget covering their computation and storage cost
+// with a margin. It fails when the transaction itself would fail.
+func EstimateMoveGas(ctx context.Context, cli SuiClient, tx *transaction.Transaction, sender string) (MoveGas, error) {
+	price, err := cli.SuiXGetReferenceGasPrice(ctx)
+	if err != nil {
+		return MoveGas{}, rpcError("fetching reference gas price", err)
+	}
+
+	kind, err := tx.Data.V1.Kind.Marshal()
+	if err != nil {
+		return MoveGas{}, fmt.Errorf("encoding transaction: %w", err)
+	}
+	result, err := cli.SuiDevInspectTransactionBlock(ctx, models.SuiDevInspectTransactionBlockRequest{
+		Sender:   sender,
+		TxBytes:  mystenbcs.ToBase64(kind),
+		GasPrice: strconv.FormatUint(price, 10),
+	})
+	if err != nil {
+		return MoveGas{}, rpcError("dev-inspect", err)
+	}
+	if status := result.Effects.Status; status.Status != "success" {
+		return MoveGas{}, fmt.Errorf("transaction would fail: %s", status.Error)
+	}
+
+	// the rebate is only paid back after execution, the budget must cover both costs
+	cost := new(big.Int)
+	for _, v := range []string{result.Effects.GasUsed.ComputationCost, result.Effects.GasUsed.StorageCost} {
+		n, ok := new(big.Int).SetString(v, 10)
+		if !ok && v != "" {
+			return MoveGas{}, fmt.Errorf("invalid gas cost %q", v)
+		}
+		if ok {
+			cost.Add(cost, n)
+		}
+	}
+	cost.Mul(cost, big.NewInt(100+moveGasMarginPct))
+	cost.Quo(cost, big.NewInt(100))
+	if !cost.IsUint64() {
+		return MoveGas{}, fmt.Errorf("gas cost %s overflows", cost)
+	}
+
+	return MoveGas{Price: price, Budget: max(cost.Uint64(), minMoveGasBudget)}, nil
+}
@@ -0,0 +1,69 @@
+package chain
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/block-vision/sui-go-sdk/models"
+)
+
+// suiClockObject is the shared Clock object every Sui network exposes at 0x6.
+const suiClockObject = "0x6"
+
+// Head is the latest block or checkpoint an endpoint has seen.
+type Head struct {
+	Number uint64
+	Time   time.Time
+}
+
+// CheckEvm returns the latest block number of the EVM endpoint.
+func CheckEvm(ctx context.Context, client EVMClient) (uint64, error) {
+	head, err := LatestEvmHead(ctx, client)
+	return head.Number, err
+}
+
+// LatestEvmHead returns the latest block of the EVM endpoint.
+func LatestEvmHead(ctx context.Context, client EVMClient) (Head, error) {
+	header, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return Head{}, rpcError("HeaderByNumber", err)
+	}
+
+	return Head{Number: header.Number.Uint64(), Time: time.Unix(int64(header.Time), 0)}, nil
+}
+
+// LatestMoveHead returns the Sui endpoint's on-chain clock, which advances
+// with every checkpoint. Number is the clock in milliseconds.
+func LatestMoveHead(ctx context.Context, cli SuiClient) (Head, error) {
+	ms, err := CheckMove(ctx, cli)
+	if err != nil {
+		return Head{}, err
+	}
+
+	return Head{Number: ms, Time: time.UnixMilli(int64(ms))}, nil
+}
+
+// CheckMove returns the on-chain clock of the Sui endpoint in milliseconds.
+func CheckMove(ctx context.Context, cli SuiClient) (uint64, error) {
+	resp, err := cli.SuiGetObject(ctx, models.SuiGetObjectRequest{
+		ObjectId: suiClockObject,
+		Options: models.SuiObjectDataOptions{
+			ShowContent: true,
+		},
+	})
+	if err != nil {
+		return 0, rpcError("SuiGetObject", err)
+	}
+	if resp.Data == nil || resp.Data.Content == nil {
+		return 0, fmt.Errorf("clock object has no parsed content")
+	}
+
+	timestamp, ok := resp.Data.Content.Fields["timestamp_ms"].(string)
+	if !ok {
+		return 0, fmt.Errorf("clock object has no timestamp_ms")
+	}
+
+	return strconv.ParseUint(timestamp, 10, 64)
+}
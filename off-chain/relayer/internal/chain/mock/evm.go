@@ -0,0 +1,214 @@
+// Package mock provides in-memory implementations of chain.EVMClient and
+// chain.SuiClient for exercising the relayer without network access.
+package mock
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math/big"
+	"slices"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+
+	"relayer/internal/chain"
+)
+
+var _ chain.EVMClient = (*EVMClient)(nil)
+
+// EVMClient serves receipts, headers and code from maps. Contract calls are
+// delegated to CallFunc, which tests can set to return ABI encoded results.
+type EVMClient struct {
+	mu       sync.RWMutex
+	receipts map[common.Hash]*types.Receipt
+	txs      map[common.Hash]*types.Transaction
+	headers  map[uint64]*types.Header
+	code     map[common.Address][]byte
+	balances map[common.Address]*big.Int
+	latest   uint64
+
+	// CallFunc answers eth_call requests; a nil CallFunc fails every call.
+	CallFunc func(call ethereum.CallMsg) ([]byte, error)
+}
+
+func NewEVMClient() *EVMClient {
+	return &EVMClient{
+		receipts: make(map[common.Hash]*types.Receipt),
+		txs:      make(map[common.Hash]*types.Transaction),
+		headers:  make(map[uint64]*types.Header),
+		code:     make(map[common.Address][]byte),
+		balances: make(map[common.Address]*big.Int),
+	}
+}
+
+// AddReceipt registers a receipt and a header for its block stamped with blockTime (unix seconds).
+func (c *EVMClient) AddReceipt(txHash common.Hash, receipt *types.Receipt, blockTime uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	receipt.TxHash = txHash
+	if receipt.BlockNumber == nil {
+		receipt.BlockNumber = new(big.Int).SetUint64(c.latest + 1)
+	}
+	number := receipt.BlockNumber.Uint64()
+	c.receipts[txHash] = receipt
+	c.headers[number] = &types.Header{Number: new(big.Int).SetUint64(number), Time: blockTime}
+	if number > c.latest {
+		c.latest = number
+	}
+}
+
+// AddTransaction registers a mined transaction under txHash.
+func (c *EVMClient) AddTransaction(txHash common.Hash, tx *types.Transaction) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.txs[txHash] = tx
+}
+
+// SetCode sets the runtime bytecode returned for address.
+func (c *EVMClient) SetCode(address common.Address, code []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.code[address] = code
+}
+
+// SetBalance sets the native balance returned for address.
+func (c *EVMClient) SetBalance(address common.Address, balance *big.Int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.balances[address] = balance
+}
+
+func (c *EVMClient) TransactionReceipt(_ context.Context, txHash common.Hash) (*types.Receipt, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	receipt, ok := c.receipts[txHash]
+	if !ok {
+		return nil, ethereum.NotFound
+	}
+
+	return receipt, nil
+}
+
+func (c *EVMClient) TransactionByHash(_ context.Context, txHash common.Hash) (*types.Transaction, bool, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	tx, ok := c.txs[txHash]
+	if !ok {
+		return nil, false, ethereum.NotFound
+	}
+
+	return tx, false, nil
+}
+
+func (c *EVMClient) HeaderByNumber(_ context.Context, number *big.Int) (*types.Header, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	n := c.latest
+	if number != nil {
+		n = number.Uint64()
+	}
+
+	header, ok := c.headers[n]
+	if !ok {
+		return nil, ethereum.NotFound
+	}
+
+	return header, nil
+}
+
+func (c *EVMClient) CodeAt(_ context.Context, contract common.Address, _ *big.Int) ([]byte, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.code[contract], nil
+}
+
+func (c *EVMClient) BalanceAt(_ context.Context, account common.Address, _ *big.Int) (*big.Int, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if balance, ok := c.balances[account]; ok {
+		return new(big.Int).Set(balance), nil
+	}
+	return new(big.Int), nil
+}
+
+func (c *EVMClient) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	return c.CodeAt(ctx, account, nil)
+}
+
+func (c *EVMClient) CallContract(_ context.Context, call ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	if c.CallFunc == nil {
+		return nil, fmt.Errorf("mock: no CallFunc set for call to %s", call.To)
+	}
+
+	return c.CallFunc(call)
+}
+
+func (c *EVMClient) PendingNonceAt(context.Context, common.Address) (uint64, error) {
+	return 0, nil
+}
+
+func (c *EVMClient) SuggestGasPrice(context.Context) (*big.Int, error) {
+	return big.NewInt(1), nil
+}
+
+func (c *EVMClient) SuggestGasTipCap(context.Context) (*big.Int, error) {
+	return big.NewInt(1), nil
+}
+
+func (c *EVMClient) EstimateGas(context.Context, ethereum.CallMsg) (uint64, error) {
+	return 21000, nil
+}
+
+func (c *EVMClient) SendTransaction(context.Context, *types.Transaction) error {
+	return fmt.Errorf("mock: sending transactions is not supported")
+}
+
+// FilterLogs serves the logs of the registered receipts matching the
+// addresses, first topics and FromBlock of q, in block order.
+func (c *EVMClient) FilterLogs(_ context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var logs []types.Log
+	for txHash, receipt := range c.receipts {
+		if q.FromBlock != nil && receipt.BlockNumber.Cmp(q.FromBlock) < 0 {
+			continue
+		}
+		for _, vLog := range receipt.Logs {
+			if len(q.Addresses) > 0 && !slices.Contains(q.Addresses, vLog.Address) {
+				continue
+			}
+			if len(q.Topics) > 0 && len(q.Topics[0]) > 0 && (len(vLog.Topics) == 0 || !slices.Contains(q.Topics[0], vLog.Topics[0])) {
+				continue
+			}
+			l := *vLog
+			l.TxHash = txHash
+			l.BlockNumber = receipt.BlockNumber.Uint64()
+			logs = append(logs, l)
+		}
+	}
+	slices.SortFunc(logs, func(a, b types.Log) int { return cmp.Compare(a.BlockNumber, b.BlockNumber) })
+	return logs, nil
+}
+
+func (c *EVMClient) SubscribeFilterLogs(context.Context, ethereum.FilterQuery, chan<- types.Log) (ethereum.Subscription, error) {
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		<-quit
+		return nil
+	}), nil
+}
+
+func (c *EVMClient) Close() {}
@@ -0,0 +1,264 @@
+package mock
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/block-vision/sui-go-sdk/models"
+
+	"relayer/internal/chain"
+)
+
+var _ chain.SuiClient = (*SuiClient)(nil)
+
+// SuiClient serves events, transaction blocks and objects keyed by digest or object id.
+type SuiClient struct {
+	mu     sync.RWMutex
+	events map[string]models.GetEventsResponse
+	// digests of the registered transactions, oldest first
+	digests []string
+	txs     map[string]models.SuiTransactionBlockResponse
+	objects map[string]models.SuiObjectResponse
+	// digests of the transactions taking each object as input, oldest first
+	inputs map[string][]string
+
+	inspectErr string
+	inspectGas models.GasCostSummary
+	// coins per owner and coin type
+	coins    map[string][]models.CoinData
+	gasPrice uint64
+}
+
+func NewSuiClient() *SuiClient {
+	return &SuiClient{
+		events:  make(map[string]models.GetEventsResponse),
+		txs:     make(map[string]models.SuiTransactionBlockResponse),
+		objects: make(map[string]models.SuiObjectResponse),
+		inputs:  make(map[string][]string),
+		coins:   make(map[string][]models.CoinData),
+		// the mainnet reference gas price
+		gasPrice: 750,
+	}
+}
+
+// AddTransaction registers the events emitted by digest, executed at timestampMs.
+func (c *SuiClient) AddTransaction(digest string, timestampMs int64, events ...*models.SuiEventResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.events[digest]; !ok {
+		c.digests = append(c.digests, digest)
+	}
+	c.events[digest] = events
+	c.txs[digest] = models.SuiTransactionBlockResponse{
+		Digest:      digest,
+		TimestampMs: strconv.FormatInt(timestampMs, 10),
+	}
+}
+
+// SetObject registers the object returned for objectID.
+func (c *SuiClient) SetObject(objectID string, object models.SuiObjectResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.objects[objectID] = object
+}
+
+// DeleteObject marks objectID as consumed by digest, a transaction registered
+// with AddTransaction.
+func (c *SuiClient) DeleteObject(objectID, digest string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.objects[objectID] = models.SuiObjectResponse{
+		Error: &models.SuiObjectResponseError{Code: "deleted", ObjectId: objectID},
+	}
+	c.inputs[objectID] = append(c.inputs[objectID], digest)
+}
+
+func (c *SuiClient) SuiGetEvents(_ context.Context, req models.SuiGetEventsRequest) (models.GetEventsResponse, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	events, ok := c.events[req.Digest]
+	if !ok {
+		return nil, fmt.Errorf("mock: unknown transaction %s", req.Digest)
+	}
+
+	return events, nil
+}
+
+// SuiXQueryEvents serves MoveEventModule queries from the events registered
+// with AddTransaction, oldest first, after the cursor if any.
+func (c *SuiClient) SuiXQueryEvents(_ context.Context, req models.SuiXQueryEventsRequest) (models.PaginatedEventsResponse, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	filter, _ := req.SuiEventFilter.(map[string]any)
+	module, ok := filter["MoveEventModule"].(map[string]any)
+	if !ok {
+		return models.PaginatedEventsResponse{}, fmt.Errorf("mock: only MoveEventModule filters are supported")
+	}
+	prefix := fmt.Sprintf("%v::%v::", module["package"], module["module"])
+
+	cursor, after := req.Cursor.(models.EventId)
+	var resp models.PaginatedEventsResponse
+	for _, digest := range c.digests {
+		for _, ev := range c.events[digest] {
+			if after {
+				after = ev.Id != cursor
+				continue
+			}
+			if !strings.HasPrefix(ev.Type, prefix) {
+				continue
+			}
+			if req.Limit > 0 && uint64(len(resp.Data)) == req.Limit {
+				resp.HasNextPage = true
+				return resp, nil
+			}
+			resp.Data = append(resp.Data, *ev)
+			resp.NextCursor = ev.Id
+		}
+	}
+	return resp, nil
+}
+
+func (c *SuiClient) SuiGetTransactionBlock(_ context.Context, req models.SuiGetTransactionBlockRequest) (models.SuiTransactionBlockResponse, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	tx, ok := c.txs[req.Digest]
+	if !ok {
+		return models.SuiTransactionBlockResponse{}, fmt.Errorf("mock: unknown transaction %s", req.Digest)
+	}
+
+	return tx, nil
+}
+
+func (c *SuiClient) SuiGetObject(_ context.Context, req models.SuiGetObjectRequest) (models.SuiObjectResponse, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	object, ok := c.objects[req.ObjectId]
+	if !ok {
+		return models.SuiObjectResponse{}, fmt.Errorf("mock: unknown object %s", req.ObjectId)
+	}
+
+	return object, nil
+}
+
+// SuiDevInspectTransactionBlock reports every inspected transaction as
+// successful unless SetDevInspectError was called.
+func (c *SuiClient) SuiDevInspectTransactionBlock(_ context.Context, _ models.SuiDevInspectTransactionBlockRequest) (models.SuiTransactionBlockResponse, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var resp models.SuiTransactionBlockResponse
+	resp.Effects.Status.Status = "success"
+	resp.Effects.GasUsed = c.inspectGas
+	if c.inspectErr != "" {
+		resp.Effects.Status.Status = "failure"
+		resp.Effects.Status.Error = c.inspectErr
+	}
+
+	return resp, nil
+}
+
+// SetDevInspectError makes dev-inspected transactions fail with reason, an
+// empty reason makes them succeed again.
+func (c *SuiClient) SetDevInspectError(reason string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.inspectErr = reason
+}
+
+// SuiXQueryTransactionBlocks serves InputObject queries from the transactions
+// registered with DeleteObject.
+func (c *SuiClient) SuiXQueryTransactionBlocks(_ context.Context, req models.SuiXQueryTransactionBlocksRequest) (models.SuiXQueryTransactionBlocksResponse, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	objectID, ok := req.SuiTransactionBlockResponseQuery.TransactionFilter["InputObject"].(string)
+	if !ok {
+		return models.SuiXQueryTransactionBlocksResponse{}, fmt.Errorf("mock: only InputObject filters are supported")
+	}
+
+	var resp models.SuiXQueryTransactionBlocksResponse
+	for _, digest := range c.inputs[objectID] {
+		resp.Data = append(resp.Data, c.txs[digest])
+	}
+	if req.DescendingOrder {
+		slices.Reverse(resp.Data)
+	}
+	return resp, nil
+}
+
+// SetDevInspectGas sets the gas cost reported for dev-inspected transactions.
+func (c *SuiClient) SetDevInspectGas(gas models.GasCostSummary) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.inspectGas = gas
+}
+
+// AddCoin gives owner a coin of coinType.
+func (c *SuiClient) AddCoin(owner, coinType string, coin models.CoinData) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	coin.CoinType = coinType
+	key := owner + " " + coinType
+	c.coins[key] = append(c.coins[key], coin)
+}
+
+// SetReferenceGasPrice sets the price SuiXGetReferenceGasPrice returns.
+func (c *SuiClient) SetReferenceGasPrice(price uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.gasPrice = price
+}
+
+// SuiXGetCoins pages through the coins registered with AddCoin, the cursor
+// being the index of the next coin.
+func (c *SuiClient) SuiXGetCoins(_ context.Context, req models.SuiXGetCoinsRequest) (models.PaginatedCoinsResponse, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	coinType := req.CoinType
+	if coinType == "" {
+		coinType = chain.SuiCoinType
+	}
+	coins := c.coins[req.Owner+" "+coinType]
+
+	start := 0
+	if cursor, ok := req.Cursor.(string); ok {
+		start, _ = strconv.Atoi(cursor)
+	}
+	limit := int(req.Limit)
+	if limit == 0 {
+		limit = 50
+	}
+	end := min(start+limit, len(coins))
+	if start > end {
+		start = end
+	}
+
+	resp := models.PaginatedCoinsResponse{Data: coins[start:end], HasNextPage: end < len(coins)}
+	if resp.HasNextPage {
+		resp.NextCursor = strconv.Itoa(end)
+	}
+	return resp, nil
+}
+
+func (c *SuiClient) SuiXGetReferenceGasPrice(context.Context) (uint64, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.gasPrice, nil
+}
@@ -5,13 +5,14 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
 	"math/big"
 	"strconv"
 	"strings"
-	"time"
+
+	"relayer/internal/logging"
 
 	"github.com/block-vision/sui-go-sdk/models"
-	"github.com/block-vision/sui-go-sdk/sui"
 	"github.com/ethereum/go-ethereum/common"
 )
 
@@ -42,10 +43,13 @@ func (s *SrcEscrowCreatedEvent) String() string {
 	panic("unimplemented")
 }
 
-func FetchMoveSrcEscrowEvent(ctx context.Context, cli *sui.Client, txDigest string) (*SrcEscrowCreatedEvent, time.Time, error) {
+// FetchMoveSrcEscrowEvent fetches tx events and returns the first
+// SrcEscrowCreated emitted by the escrow package pkg, any package when pkg is
+// empty.
+func FetchMoveSrcEscrowEvent(ctx context.Context, cli SuiClient, txDigest, pkg string) (*SrcEscrowCreatedEvent, EventTime, error) {
 	timestamp, err := FetchMoveTimeByTx(ctx, cli, txDigest)
 	if err != nil {
-		return nil, time.Time{}, fmt.Errorf("fetching move time by tx: %w", err)
+		return nil, EventTime{}, fmt.Errorf("fetching move time by tx: %w", err)
 	}
 
 	// Fetch events for this transaction.
@@ -53,7 +57,7 @@ func FetchMoveSrcEscrowEvent(ctx context.Context, cli *sui.Client, txDigest stri
 		Digest: txDigest,
 	})
 	if err != nil {
-		return nil, time.Time{}, fmt.Errorf("fetching events: %w", err)
+		return nil, EventTime{}, rpcError("fetching events", err)
 	}
 
 	// The response can be:
@@ -96,7 +100,7 @@ func FetchMoveSrcEscrowEvent(ctx context.Context, cli *sui.Client, txDigest stri
 	}
 
 	if len(events) == 0 {
-		return nil, time.Time{}, errors.New("no events found for transaction")
+		return nil, EventTime{}, fmt.Errorf("%w: tx %s emitted none", ErrEventNotFound, txDigest)
 	}
 
 	// Find the event whose Move type ends with ::SrcEscrowCreated
@@ -106,29 +110,49 @@ func FetchMoveSrcEscrowEvent(ctx context.Context, cli *sui.Client, txDigest stri
 		if ev.Type == "" || !strings.HasSuffix(ev.Type, wantSuffix) {
 			continue
 		}
+		if pkg != "" && !sameMovePackage(ev.Type, pkg) {
+			return nil, EventTime{}, fmt.Errorf("%s is not from the escrow package %s", ev.Type, pkg)
+		}
 
-		id, err := models.NewHexData(ev.ParsedJson["id"].(string))
+		id, err := moveID(ev.ParsedJson)
 		if err != nil {
-			return nil, time.Time{}, fmt.Errorf("invalid id hex: %w", err)
+			return nil, EventTime{}, err
 		}
 
-		orderHash := common.HexToHash(ev.ParsedJson["order_hash"].(string))
-		hashlock := common.BytesToHash(ev.ParsedJson["hashlock"].([]byte))
-		maker := models.SuiAddress(ev.ParsedJson["maker"].(string))
-		taker := models.SuiAddress(ev.ParsedJson["taker"].(string))
-
-		makingAmount := new(big.Int)
-		makingAmount.SetString(ev.ParsedJson["making_amount"].(string), 10)
+		orderHashBytes, err := moveBytes(ev.ParsedJson["order_hash"])
+		if err != nil {
+			return nil, EventTime{}, decodeError("order_hash", err)
+		}
+		hashlockBytes, err := moveBytes(ev.ParsedJson["hashlock"])
+		if err != nil {
+			return nil, EventTime{}, decodeError("hashlock", err)
+		}
+		orderHash := common.BytesToHash(orderHashBytes)
+		hashlock := common.BytesToHash(hashlockBytes)
+		maker, err := moveString(ev.ParsedJson, "maker")
+		if err != nil {
+			return nil, EventTime{}, err
+		}
+		taker, err := moveString(ev.ParsedJson, "taker")
+		if err != nil {
+			return nil, EventTime{}, err
+		}
 
-		takingAmount := new(big.Int)
-		takingAmount.SetString(ev.ParsedJson["taking_amount"].(string), 10)
+		makingAmount, err := moveAmount(ev.ParsedJson["making_amount"])
+		if err != nil {
+			return nil, EventTime{}, decodeError("making_amount", err)
+		}
+		takingAmount, err := moveAmount(ev.ParsedJson["taking_amount"])
+		if err != nil {
+			return nil, EventTime{}, decodeError("taking_amount", err)
+		}
 
 		out := &SrcEscrowCreatedEvent{
 			ID:           models.ObjectId(*id), // "0x..." object ID
 			OrderHash:    orderHash,
 			Hashlock:     hashlock,
-			Maker:        maker,
-			Taker:        taker,
+			Maker:        models.SuiAddress(maker),
+			Taker:        models.SuiAddress(taker),
 			MakingAmount: makingAmount,
 			TakingAmount: takingAmount,
 		}
@@ -136,7 +160,7 @@ func FetchMoveSrcEscrowEvent(ctx context.Context, cli *sui.Client, txDigest stri
 		return out, timestamp, nil
 	}
 
-	return nil, time.Time{}, fmt.Errorf("event %s not found in tx %s", wantSuffix, txDigest)
+	return nil, EventTime{}, fmt.Errorf("%w: %s not in tx %s", ErrEventNotFound, wantSuffix, txDigest)
 }
 
 /*
@@ -158,12 +182,13 @@ type DstEscrowCreatedEvent struct {
 	Amount         *big.Int
 }
 
-// FetchMoveDstEscrowEvent fetches tx events and returns the first DstEscrowCreatedEvent found.
-// cli is the BlockVision Sui client (e.g., sui.NewSuiClient(...)); txDigest is the Sui tx digest string.
-func FetchMoveDstEscrowEvent(ctx context.Context, cli *sui.Client, txDigest string) (*DstEscrowCreatedEvent, time.Time, error) {
+// FetchMoveDstEscrowEvent fetches tx events and returns the first DstEscrowCreatedEvent
+// emitted by the escrow package pkg, any package when pkg is empty.
+// cli is a SuiClient (e.g., the BlockVision sui.NewSuiClient(...)); txDigest is the Sui tx digest string.
+func FetchMoveDstEscrowEvent(ctx context.Context, cli SuiClient, txDigest, pkg string) (*DstEscrowCreatedEvent, EventTime, error) {
 	timestamp, err := FetchMoveTimeByTx(ctx, cli, txDigest)
 	if err != nil {
-		return nil, time.Time{}, fmt.Errorf("fetching move time by tx: %w", err)
+		return nil, EventTime{}, fmt.Errorf("fetching move time by tx: %w", err)
 	}
 
 	// Fetch events for this transaction.
@@ -171,7 +196,7 @@ func FetchMoveDstEscrowEvent(ctx context.Context, cli *sui.Client, txDigest stri
 		Digest: txDigest,
 	})
 	if err != nil {
-		return nil, time.Time{}, fmt.Errorf("fetching events: %w", err)
+		return nil, EventTime{}, rpcError("fetching events", err)
 	}
 
 	// The response can be:
@@ -214,7 +239,7 @@ func FetchMoveDstEscrowEvent(ctx context.Context, cli *sui.Client, txDigest stri
 	}
 
 	if len(events) == 0 {
-		return nil, time.Time{}, errors.New("no events found for transaction")
+		return nil, EventTime{}, fmt.Errorf("%w: tx %s emitted none", ErrEventNotFound, txDigest)
 	}
 
 	// Find the event whose Move type ends with ::DstEscrowCreatedEvent
@@ -225,8 +250,9 @@ func FetchMoveDstEscrowEvent(ctx context.Context, cli *sui.Client, txDigest stri
 		if ev.Type == "" || !strings.HasSuffix(ev.Type, wantSuffix) {
 			continue
 		}
-
-		fmt.Println("Found DstEscrowCreatedEvent:", ev.ParsedJson)
+		if pkg != "" && !sameMovePackage(ev.Type, pkg) {
+			return nil, EventTime{}, fmt.Errorf("%s is not from the escrow package %s", ev.Type, pkg)
+		}
 
 		/*
 			// Marshal ParsedJson back to bytes to decode into a strongly-typed wire struct.
@@ -263,59 +289,125 @@ func FetchMoveDstEscrowEvent(ctx context.Context, cli *sui.Client, txDigest stri
 			}
 		*/
 
-		id, err := models.NewHexData(ev.ParsedJson["id"].(string))
+		id, err := moveID(ev.ParsedJson)
 		if err != nil {
-			return nil, time.Time{}, fmt.Errorf("invalid id hex: %w", err)
+			return nil, EventTime{}, err
 		}
 
-		tmp := ev.ParsedJson["hashlock"].([]interface{})
-		hashlockTmp := make([]byte, len(tmp))
-		for i, v := range tmp {
-			hashlockTmp[i] = byte(v.(float64))
+		hashlockBytes, err := moveBytes(ev.ParsedJson["hashlock"])
+		if err != nil {
+			return nil, EventTime{}, decodeError("hashlock", err)
+		}
+		hashlock := common.BytesToHash(hashlockBytes)
+		taker, err := moveString(ev.ParsedJson, "taker")
+		if err != nil {
+			return nil, EventTime{}, err
+		}
+		tokenPackageID, err := moveString(ev.ParsedJson, "token_package_id")
+		if err != nil {
+			return nil, EventTime{}, err
+		}
+
+		amount, err := moveAmount(ev.ParsedJson["amount"])
+		if err != nil {
+			return nil, EventTime{}, decodeError("amount", err)
 		}
-		hashlock := common.BytesToHash(hashlockTmp)
-		taker := models.SuiAddress(ev.ParsedJson["taker"].(string))
 
-		amount := new(big.Int)
-		amount.SetString(ev.ParsedJson["amount"].(string), 10)
+		slog.DebugContext(ctx, "found DstEscrowCreatedEvent",
+			"tx", txDigest,
+			"id", ev.ParsedJson["id"],
+			"hashlock", logging.Redact(hashlock.Hex()),
+			"taker", taker,
+			"amount", amount.String(),
+		)
 
 		out := &DstEscrowCreatedEvent{
 			ID:             models.ObjectId(*id), // "0x..." object ID
 			Hashlock:       hashlock,
-			Taker:          taker,
-			TokenPackageID: ev.ParsedJson["token_package_id"].(string),
+			Taker:          models.SuiAddress(taker),
+			TokenPackageID: tokenPackageID,
 			Amount:         amount,
 		}
 
 		return out, timestamp, nil
 	}
 
-	return nil, time.Time{}, fmt.Errorf("event %s not found in tx %s", wantSuffix, txDigest)
+	return nil, EventTime{}, fmt.Errorf("%w: %s not in tx %s", ErrEventNotFound, wantSuffix, txDigest)
+}
+
+// moveString returns a string event field, such as an address.
+func moveString(fields map[string]any, name string) (string, error) {
+	s, ok := fields[name].(string)
+	if !ok {
+		return "", decodeError(name, fmt.Errorf("is %T, not a string", fields[name]))
+	}
+	return s, nil
+}
+
+// moveID decodes the object id event field id.
+func moveID(fields map[string]any) (*models.HexData, error) {
+	s, err := moveString(fields, "id")
+	if err != nil {
+		return nil, err
+	}
+	id, err := models.NewHexData(s)
+	if err != nil {
+		return nil, decodeError("id", err)
+	}
+	return id, nil
+}
+
+// sameMovePackage tells whether the Move type eventType, package::module::name,
+// is defined in the package pkg. Package ids compare whatever their padding.
+func sameMovePackage(eventType, pkg string) bool {
+	defining, _, ok := strings.Cut(eventType, "::")
+	return ok && common.HexToHash(defining) == common.HexToHash(pkg)
+}
+
+// moveBytes decodes a vector<u8> event field, which the JSON-RPC renders
+// either as an array of numbers or as a 0x-prefixed hex string.
+func moveBytes(v any) ([]byte, error) {
+	switch val := v.(type) {
+	case []any:
+		out := make([]byte, len(val))
+		for i, b := range val {
+			n, ok := b.(float64)
+			if !ok || n < 0 || n > 255 {
+				return nil, fmt.Errorf("element %d is not a byte: %v", i, b)
+			}
+			out[i] = byte(n)
+		}
+		return out, nil
+	case string:
+		return common.FromHex(val), nil
+	default:
+		return nil, fmt.Errorf("unexpected type %T", v)
+	}
 }
 
 func FetchMoveTimeByTx(
 	ctx context.Context,
-	cli *sui.Client,
+	cli SuiClient,
 	txDigest string,
-) (time.Time, error) {
+) (EventTime, error) {
 	txResp, err := cli.SuiGetTransactionBlock(ctx, models.SuiGetTransactionBlockRequest{
 		Digest: txDigest,
 	})
 	if err != nil {
-		return time.Time{}, fmt.Errorf("fetching transaction block: %w", err)
+		return EventTime{}, rpcError("fetching transaction block", err)
 	}
 
-	ts, err := strconv.Atoi(txResp.TimestampMs)
+	ts, err := strconv.ParseInt(txResp.TimestampMs, 10, 64)
 	if err != nil {
-		return time.Time{}, fmt.Errorf("invalid timestamp: %w", err)
+		return EventTime{}, decodeError("timestamp", err)
 	}
-	return time.UnixMilli(int64(ts)), nil
+	return MoveEventTime(ts), nil
 }
 
 // FetchCoinFieldBalance looks up a nested field on a Move object that is a Coin<T>
 // (or a Balance<T>) and returns its numeric balance as uint64.
 //
-// - cli:       BlockVision Sui client (sui.NewSuiClient(...))
+// - cli:       Sui client (sui.NewSuiClient(...) or a mock)
 // - objectID:  the parent object id that contains the field
 // - fieldPath: dot-separated path to the field (e.g. "coin", "vault.coin", "inner.vault.coin")
 //
@@ -325,7 +417,7 @@ func FetchMoveTimeByTx(
 //	struct Vault<T> { bal:  0x2::balance::Balance<T> }            // balance at fields.bal.fields.value
 func FetchCoinFieldBalance(
 	ctx context.Context,
-	cli *sui.Client,
+	cli SuiClient,
 	objectID string,
 	fieldPath string,
 ) (*big.Int, error) {
@@ -349,7 +441,7 @@ func FetchCoinFieldBalance(
 		},
 	})
 	if err != nil {
-		return nil, fmt.Errorf("SuiGetObject failed: %w", err)
+		return nil, rpcError("SuiGetObject", err)
 	}
 	if resp.Data == nil || resp.Data.Content == nil {
 		return nil, fmt.Errorf("object %s has no parsed content", objectID)
@@ -405,12 +497,24 @@ func FetchCoinFieldBalance(
 	}
 
 	// JSON-RPC returns numeric fields as strings; parse to uint64.
-	rawStr, ok := raw.(string)
-	if !ok {
-		return nil, fmt.Errorf("path '%s': balance/value is not a string", fieldPath)
+	amount, err := moveAmount(raw)
+	if err != nil {
+		return nil, decodeError(fmt.Sprintf("path '%s': balance/value", fieldPath), err)
 	}
-	amount := new(big.Int)
-	amount.SetString(rawStr, 10)
 
 	return amount, nil
 }
+
+// moveAmount decodes a u64 amount, which Sui JSON-RPC renders as a decimal
+// string.
+func moveAmount(v any) (*big.Int, error) {
+	s, ok := v.(string)
+	if !ok {
+		return nil, fmt.Errorf("amount is %T, not a string", v)
+	}
+	n, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid u64 amount %q", s)
+	}
+	return new(big.Int).SetUint64(n), nil
+}
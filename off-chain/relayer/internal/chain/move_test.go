@@ -0,0 +1,148 @@
+package chain_test
+
+import (
+	"context"
+	"errors"
+	"relayer/internal/chain"
+	"relayer/internal/chain/mock"
+	"strings"
+	"testing"
+
+	"github.com/block-vision/sui-go-sdk/models"
+)
+
+const (
+	escrowPackage = "0x9947401bada3b7918ed6813946e206841e3c8cd890744056e5153c2f3015c7cf"
+	moveDigest    = "4vJ9JU1bJJE96FWSJKvHsmmFADCg4gpZQff4P3bkLKi"
+)
+
+func dstEscrowFields() map[string]any {
+	return map[string]any{
+		"id":               "0x5d3a1f5c8a1e8f0b6a4c2e9d7b5a3f1e0d9c8b7a6f5e4d3c2b1a0f9e8d7c6b5a",
+		"hashlock":         "0x" + strings.Repeat("ab", 32),
+		"taker":            "0x" + strings.Repeat("cd", 32),
+		"token_package_id": "0x2",
+		"amount":           "1000",
+	}
+}
+
+func TestFetchMoveDstEscrowEvent(t *testing.T) {
+	tests := []struct {
+		name string
+		// event type, of the escrow package when empty
+		typ  string
+		edit func(fields map[string]any)
+		pkg  string
+		// decodes when empty
+		want string
+	}{
+		{name: "pinned package", pkg: escrowPackage},
+		{name: "any package"},
+		{name: "unpadded package id", typ: "0x2::dst_escrow::DstEscrowCreatedEvent", pkg: "0x0000000000000000000000000000000000000000000000000000000000000002"},
+		{name: "other package", typ: "0x2::dst_escrow::DstEscrowCreatedEvent", pkg: escrowPackage, want: "is not from the escrow package"},
+		{name: "missing id", edit: func(f map[string]any) { delete(f, "id") }, want: "decoding failed: id"},
+		{name: "numeric taker", edit: func(f map[string]any) { f["taker"] = 42.0 }, want: "decoding failed: taker"},
+		{name: "missing token package", edit: func(f map[string]any) { delete(f, "token_package_id") }, want: "decoding failed: token_package_id"},
+		{name: "malformed hashlock", edit: func(f map[string]any) { f["hashlock"] = map[string]any{} }, want: "decoding failed: hashlock"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			typ := tt.typ
+			if typ == "" {
+				typ = escrowPackage + "::dst_escrow::DstEscrowCreatedEvent"
+			}
+			fields := dstEscrowFields()
+			if tt.edit != nil {
+				tt.edit(fields)
+			}
+			sui := mock.NewSuiClient()
+			sui.AddTransaction(moveDigest, 1_700_000_000_000, &models.SuiEventResponse{Type: typ, ParsedJson: fields})
+
+			evt, _, err := chain.FetchMoveDstEscrowEvent(context.Background(), sui, moveDigest, tt.pkg)
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("FetchMoveDstEscrowEvent = %v", err)
+				}
+				if evt.Amount.Int64() != 1000 || evt.TokenPackageID != "0x2" {
+					t.Errorf("decoded %+v", evt)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("FetchMoveDstEscrowEvent = %v, want an error containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchMoveSrcEscrowEvent(t *testing.T) {
+	fields := func() map[string]any {
+		return map[string]any{
+			"id":            "0x5d3a1f5c8a1e8f0b6a4c2e9d7b5a3f1e0d9c8b7a6f5e4d3c2b1a0f9e8d7c6b5a",
+			"order_hash":    "0x" + strings.Repeat("01", 32),
+			"hashlock":      "0x" + strings.Repeat("ab", 32),
+			"maker":         "0x" + strings.Repeat("ef", 32),
+			"taker":         "0x" + strings.Repeat("cd", 32),
+			"making_amount": "1000",
+			"taking_amount": "2000",
+		}
+	}
+	tests := []struct {
+		name  string
+		field string
+		value any
+	}{
+		{name: "missing id", field: "id"},
+		{name: "numeric maker", field: "maker", value: 1.0},
+		{name: "missing taker", field: "taker"},
+		{name: "numeric making amount", field: "making_amount", value: 1000.0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := fields()
+			if tt.value == nil {
+				delete(f, tt.field)
+			} else {
+				f[tt.field] = tt.value
+			}
+			sui := mock.NewSuiClient()
+			sui.AddTransaction(moveDigest, 1_700_000_000_000, &models.SuiEventResponse{Type: escrowPackage + "::src_escrow::SrcEscrowCreated", ParsedJson: f})
+
+			_, _, err := chain.FetchMoveSrcEscrowEvent(context.Background(), sui, moveDigest, escrowPackage)
+			if !errors.Is(err, chain.ErrDecoding) || !strings.Contains(err.Error(), tt.field) {
+				t.Fatalf("FetchMoveSrcEscrowEvent = %v, want a decoding error of %s", err, tt.field)
+			}
+		})
+	}
+
+	sui := mock.NewSuiClient()
+	sui.AddTransaction(moveDigest, 1_700_000_000_000, &models.SuiEventResponse{Type: escrowPackage + "::src_escrow::SrcEscrowCreated", ParsedJson: fields()})
+	evt, at, err := chain.FetchMoveSrcEscrowEvent(context.Background(), sui, moveDigest, escrowPackage)
+	if err != nil {
+		t.Fatalf("FetchMoveSrcEscrowEvent = %v", err)
+	}
+	if evt.MakingAmount.Int64() != 1000 || evt.TakingAmount.Int64() != 2000 || at.UnixMilli() != 1_700_000_000_000 {
+		t.Errorf("decoded %+v at %v", evt, at)
+	}
+}
+
+func TestFetchMoveCancelledEscrowsPackage(t *testing.T) {
+	escrow := "0x5d3a1f5c8a1e8f0b6a4c2e9d7b5a3f1e0d9c8b7a6f5e4d3c2b1a0f9e8d7c6b5a"
+	sui := mock.NewSuiClient()
+	sui.AddTransaction(moveDigest, 1_700_000_000_000,
+		// events of the same name from another package do not close the escrow
+		&models.SuiEventResponse{Type: "0x2::src_escrow::EscrowCancelled", ParsedJson: map[string]any{"escrow_id": escrow}},
+		&models.SuiEventResponse{Type: "0x2::src_escrow::EscrowWithdrawal", ParsedJson: map[string]any{"escrow_id": escrow, "secret": "0x01"}},
+	)
+
+	if _, err := chain.FetchMoveCancelledEscrows(context.Background(), sui, moveDigest, escrowPackage); !errors.Is(err, chain.ErrEventNotFound) {
+		t.Errorf("FetchMoveCancelledEscrows of another package = %v, want ErrEventNotFound", err)
+	}
+	if _, err := chain.FetchMoveWithdrawals(context.Background(), sui, moveDigest, escrowPackage); !errors.Is(err, chain.ErrEventNotFound) {
+		t.Errorf("FetchMoveWithdrawals of another package = %v, want ErrEventNotFound", err)
+	}
+	escrows, err := chain.FetchMoveCancelledEscrows(context.Background(), sui, moveDigest, "")
+	if err != nil || len(escrows) != 1 || escrows[0] != escrow {
+		t.Errorf("FetchMoveCancelledEscrows of any package = %v, %v", escrows, err)
+	}
+}
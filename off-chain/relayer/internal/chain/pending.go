@@ -0,0 +1,109 @@
+package chain
+
+import (
+	"context"
+	"fmt"
+	"relayer/pkg/reconnect"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient/gethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// PendingWatcher streams the transactions entering the mempool of an EVM
+// websocket endpoint, with eth_subscribe("newPendingTransactions", true). The
+// endpoint must serve full transactions, as geth, reth and erigon do; public
+// endpoints often do not serve the mempool at all. It reconnects with
+// exponential backoff; transactions sent while disconnected are missed.
+type PendingWatcher struct {
+	url string
+
+	reconnect.Backoff
+
+	// OnError reports connection drops; the watcher keeps running. Nil drops
+	// them.
+	OnError func(err error)
+
+	mu        sync.Mutex
+	connected bool
+}
+
+// NewPendingWatcher watches the mempool of the websocket endpoint wsURL.
+func NewPendingWatcher(wsURL string) *PendingWatcher {
+	return &PendingWatcher{
+		url:     wsURL,
+		Backoff: reconnect.DefaultBackoff(),
+	}
+}
+
+// Connected reports whether the watcher is subscribed to the mempool.
+func (w *PendingWatcher) Connected() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.connected
+}
+
+// Run calls handle with every pending transaction until ctx is cancelled,
+// reconnecting on any failure. handle runs on the watcher's goroutine and
+// should return quickly, as the endpoint drops subscribers falling behind. It
+// only returns ctx.Err().
+func (w *PendingWatcher) Run(ctx context.Context, handle func(tx *types.Transaction)) error {
+	return w.Backoff.Run(ctx, func(ctx context.Context) (bool, error) {
+		return w.runOnce(ctx, handle)
+	}, w.OnError)
+}
+
+// runOnce subscribes on a fresh connection and handles transactions until it
+// fails, reporting whether it got as far as subscribing.
+func (w *PendingWatcher) runOnce(ctx context.Context, handle func(tx *types.Transaction)) (bool, error) {
+	client, err := rpc.DialContext(ctx, w.url)
+	if err != nil {
+		return false, fmt.Errorf("dialing %s: %w", w.url, err)
+	}
+	defer client.Close()
+
+	txs := make(chan *types.Transaction, 256)
+	sub, err := gethclient.New(client).SubscribeFullPendingTransactions(ctx, txs)
+	if err != nil {
+		return false, fmt.Errorf("subscribing to pending transactions: %w", err)
+	}
+	defer sub.Unsubscribe()
+
+	w.setConnected(true)
+	defer w.setConnected(false)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return true, ctx.Err()
+		case err := <-sub.Err():
+			return true, fmt.Errorf("pending transactions subscription: %w", err)
+		case tx := <-txs:
+			handle(tx)
+		}
+	}
+}
+
+func (w *PendingWatcher) setConnected(connected bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.connected = connected
+}
+
+// MatchCalldata returns the first 32-byte word of the calldata of tx, at any
+// offset, that match accepts: order hashes and hashlocks sit in ABI words of
+// escrow factory calls, but packed in the extension data of limit order fills.
+func MatchCalldata(tx *types.Transaction, match func(word common.Hash) bool) (common.Hash, bool) {
+	data := tx.Data()
+	for i := 0; i+common.HashLength <= len(data); i++ {
+		word := common.BytesToHash(data[i : i+common.HashLength])
+		if match(word) {
+			return word, true
+		}
+	}
+	return common.Hash{}, false
+}
@@ -0,0 +1,131 @@
+package chain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/block-vision/sui-go-sdk/models"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// dstEscrowArgs is the calldata layout shared by EscrowFactory.createDstEscrow
+// and Resolver.deployDst: (Immutables dstImmutables, uint256 srcCancellationTimestamp).
+var dstEscrowArgs = func() abi.Arguments {
+	immutables, err := abi.NewType("tuple", "", []abi.ArgumentMarshaling{
+		{Name: "orderHash", Type: "bytes32"},
+		{Name: "hashlock", Type: "bytes32"},
+		{Name: "maker", Type: "uint256"},
+		{Name: "taker", Type: "uint256"},
+		{Name: "token", Type: "uint256"},
+		{Name: "amount", Type: "uint256"},
+		{Name: "safetyDeposit", Type: "uint256"},
+		{Name: "timelocks", Type: "uint256"},
+	})
+	if err != nil {
+		panic(err)
+	}
+	timestamp, _ := abi.NewType("uint256", "", nil)
+
+	return abi.Arguments{{Type: immutables}, {Type: timestamp}}
+}()
+
+type dstImmutablesWire struct {
+	OrderHash     [32]byte
+	Hashlock      [32]byte
+	Maker         *big.Int
+	Taker         *big.Int
+	Token         *big.Int
+	Amount        *big.Int
+	SafetyDeposit *big.Int
+	Timelocks     *big.Int
+}
+
+// FetchEvmDstImmutables decodes the immutables a destination escrow was
+// created with from the calldata of txHash. hashlock guards against decoding
+// an unrelated call with the same layout. The factory stamps the deployment
+// time into the timelocks on chain, so the decoded ones lack it.
+func FetchEvmDstImmutables(ctx context.Context, client EVMClient, txHash common.Hash, hashlock common.Hash) (*Immutables, error) {
+	tx, _, err := client.TransactionByHash(ctx, txHash)
+	if err != nil {
+		return nil, rpcError("fetching transaction", err)
+	}
+
+	data := tx.Data()
+	if len(data) < 4 {
+		return nil, decodeError("dst escrow calldata, the transaction has none", nil)
+	}
+
+	values, err := dstEscrowArgs.Unpack(data[4:])
+	if err != nil {
+		return nil, decodeError("dst escrow calldata", err)
+	}
+
+	wire := *abi.ConvertType(values[0], new(dstImmutablesWire)).(*dstImmutablesWire)
+	if common.Hash(wire.Hashlock) != hashlock {
+		return nil, errors.New("calldata does not create the escrow")
+	}
+
+	return &Immutables{
+		OrderHash:     wire.OrderHash,
+		Hashlock:      wire.Hashlock,
+		Maker:         common.BigToAddress(wire.Maker),
+		Taker:         common.BigToAddress(wire.Taker),
+		Token:         common.BigToAddress(wire.Token),
+		Amount:        wire.Amount,
+		SafetyDeposit: wire.SafetyDeposit,
+		Timelocks:     wire.Timelocks,
+	}, nil
+}
+
+// FetchEvmDstEscrowMaker returns the maker of the immutables a destination
+// escrow was created with, the address the escrow pays out to on withdrawal.
+func FetchEvmDstEscrowMaker(ctx context.Context, client EVMClient, txHash common.Hash, hashlock common.Hash) (common.Address, error) {
+	immutables, err := FetchEvmDstImmutables(ctx, client, txHash, hashlock)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return immutables.Maker, nil
+}
+
+// WithDeployedAt returns timelocks with their deployment time, the top 32
+// bits, set to deployedAt (unix seconds), as TimelocksLib.setDeployedAt does.
+func WithDeployedAt(timelocks *big.Int, deployedAt uint64) *big.Int {
+	mask := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 224), big.NewInt(1))
+	out := new(big.Int).And(timelocks, mask)
+	return out.Or(out, new(big.Int).Lsh(new(big.Int).SetUint64(deployedAt), 224))
+}
+
+// FetchMoveEscrowMaker returns the maker stored in the immutables of a Sui
+// escrow object.
+func FetchMoveEscrowMaker(ctx context.Context, cli SuiClient, objectID string) (string, error) {
+	resp, err := cli.SuiGetObject(ctx, models.SuiGetObjectRequest{
+		ObjectId: objectID,
+		Options: models.SuiObjectDataOptions{
+			ShowContent: true,
+		},
+	})
+	if err != nil {
+		return "", rpcError("SuiGetObject", err)
+	}
+	if resp.Data == nil || resp.Data.Content == nil {
+		return "", fmt.Errorf("object %s has no parsed content", objectID)
+	}
+
+	immutables, ok := resp.Data.Content.Fields["immutables"].(map[string]any)
+	if !ok {
+		return "", fmt.Errorf("object %s has no immutables", objectID)
+	}
+	fields, ok := immutables["fields"].(map[string]any)
+	if !ok {
+		return "", fmt.Errorf("object %s has malformed immutables", objectID)
+	}
+	maker, ok := fields["maker"].(string)
+	if !ok {
+		return "", fmt.Errorf("object %s immutables have no maker", objectID)
+	}
+
+	return maker, nil
+}
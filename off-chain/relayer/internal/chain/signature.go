@@ -0,0 +1,116 @@
+package chain
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/block-vision/sui-go-sdk/models"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ERC-1271 signature check of smart-contract wallets
+const erc1271ABI = `[
+    {
+        "inputs": [
+            {"internalType": "bytes32", "name": "hash", "type": "bytes32"},
+            {"internalType": "bytes", "name": "signature", "type": "bytes"}
+        ],
+        "name": "isValidSignature",
+        "outputs": [{"internalType": "bytes4", "name": "magicValue", "type": "bytes4"}],
+        "stateMutability": "view",
+        "type": "function"
+    }
+]`
+
+var parsedERC1271ABI = mustParseABI(erc1271ABI)
+
+// erc1271MagicValue is what isValidSignature returns for a valid signature,
+// its own selector.
+var erc1271MagicValue = common.FromHex("0x1626ba7e")
+
+// VerifyEvmOrderSignature checks that signature over orderHash was made by
+// maker. Signatures recovering to maker, in the 65-byte (r, s, v) or the
+// compact 64-byte (r, vs) form the limit order protocol accepts, are valid
+// as they are. Otherwise a maker with code is asked through ERC-1271
+// isValidSignature, so Safes and other smart accounts can sign orders.
+func VerifyEvmOrderSignature(ctx context.Context, client EVMClient, orderHash common.Hash, maker common.Address, signature []byte) error {
+	if signer, err := recoverSigner(orderHash, signature); err == nil && signer == maker {
+		return nil
+	}
+
+	code, err := client.CodeAt(ctx, maker, nil)
+	if err != nil {
+		return rpcError("fetching code of maker "+maker.Hex(), err)
+	}
+	if len(code) == 0 {
+		return fmt.Errorf("signature was not made by maker %s", maker.Hex())
+	}
+
+	data, err := parsedERC1271ABI.Pack("isValidSignature", orderHash, signature)
+	if err != nil {
+		return err
+	}
+
+	out, err := client.CallContract(ctx, ethereum.CallMsg{To: &maker, Data: data}, nil)
+	if err != nil {
+		// wallets may revert on invalid signatures instead of returning
+		return fmt.Errorf("isValidSignature of maker %s failed: %w", maker.Hex(), err)
+	}
+	if len(out) < len(erc1271MagicValue) || !bytes.Equal(out[:len(erc1271MagicValue)], erc1271MagicValue) {
+		return fmt.Errorf("maker %s rejected the signature", maker.Hex())
+	}
+	return nil
+}
+
+// VerifyEvmMessageSignature checks that signature is signer's EIP-191
+// personal_sign signature of message, falling back to ERC-1271 like orders.
+func VerifyEvmMessageSignature(ctx context.Context, client EVMClient, message []byte, signer common.Address, signature []byte) error {
+	return VerifyEvmOrderSignature(ctx, client, common.BytesToHash(accounts.TextHash(message)), signer, signature)
+}
+
+// RecoverSuiMessageSigner returns the address whose signPersonalMessage
+// signature of message is signature, a serialized Sui signature.
+func RecoverSuiMessageSigner(message []byte, signature string) (string, error) {
+	signer, ok, err := models.VerifyPersonalMessage(string(message), signature)
+	if err != nil {
+		return "", fmt.Errorf("invalid Sui signature: %w", err)
+	}
+	if !ok {
+		return "", errors.New("invalid Sui signature")
+	}
+	return signer, nil
+}
+
+// recoverSigner returns the EOA that signed hash.
+func recoverSigner(hash common.Hash, signature []byte) (common.Address, error) {
+	sig := make([]byte, crypto.SignatureLength)
+	switch len(signature) {
+	case crypto.SignatureLength:
+		copy(sig, signature)
+		if sig[64] >= 27 {
+			sig[64] -= 27
+		}
+	case crypto.SignatureLength - 1:
+		// EIP-2098: the top bit of vs is v, the rest is s
+		copy(sig, signature[:32])
+		copy(sig[32:64], signature[32:64])
+		sig[64] = sig[32] >> 7
+		sig[32] &= 0x7f
+	default:
+		return common.Address{}, fmt.Errorf("signature has %d bytes", len(signature))
+	}
+	if sig[64] > 1 {
+		return common.Address{}, errors.New("invalid signature recovery id")
+	}
+
+	pub, err := crypto.SigToPub(hash.Bytes(), sig)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
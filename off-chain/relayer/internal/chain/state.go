@@ -0,0 +1,95 @@
+package chain
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+
+	"github.com/block-vision/sui-go-sdk/models"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// EscrowState is what became of an escrow on chain.
+type EscrowState string
+
+const (
+	EscrowActive    EscrowState = "active"
+	EscrowWithdrawn EscrowState = "withdrawn"
+	EscrowCancelled EscrowState = "cancelled"
+)
+
+// suiObjectDeleted is the error code of a Sui object that no longer exists.
+const suiObjectDeleted = "deleted"
+
+// suiEscrowHistory bounds the transactions searched for the one that
+// consumed a Sui escrow; an escrow is only ever an input a few times.
+const suiEscrowHistory = 10
+
+// FetchEvmEscrowState scans the logs of escrow since fromBlock, its
+// deployment, for the EscrowWithdrawal or EscrowCancelled event closing it.
+// It returns the state and the closing transaction, if any.
+func FetchEvmEscrowState(ctx context.Context, client EVMClient, escrow common.Address, fromBlock *big.Int) (EscrowState, string, error) {
+	logs, err := client.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: fromBlock,
+		Addresses: []common.Address{escrow},
+		Topics:    [][]common.Hash{{evmEscrowWithdrawalSig, evmEscrowCancelledSig}},
+	})
+	if err != nil {
+		return "", "", rpcError("filtering logs of escrow "+escrow.Hex(), err)
+	}
+
+	for _, vLog := range logs {
+		if len(vLog.Topics) == 0 {
+			continue
+		}
+		switch vLog.Topics[0] {
+		case evmEscrowWithdrawalSig:
+			return EscrowWithdrawn, vLog.TxHash.Hex(), nil
+		case evmEscrowCancelledSig:
+			return EscrowCancelled, vLog.TxHash.Hex(), nil
+		}
+	}
+	return EscrowActive, "", nil
+}
+
+// FetchMoveEscrowState checks whether the escrow object still exists. Both
+// withdrawing and cancelling consume it, so for a deleted escrow the
+// transactions that took it as input are searched for the closing event of
+// the escrow package pkg.
+func FetchMoveEscrowState(ctx context.Context, cli SuiClient, escrowID, pkg string) (EscrowState, string, error) {
+	obj, err := cli.SuiGetObject(ctx, models.SuiGetObjectRequest{ObjectId: escrowID})
+	if err != nil {
+		return "", "", rpcError("SuiGetObject", err)
+	}
+	if obj.Error == nil && obj.Data != nil {
+		return EscrowActive, "", nil
+	}
+	if obj.Error == nil || obj.Error.Code != suiObjectDeleted {
+		return "", "", fmt.Errorf("escrow object %s unavailable: %+v", escrowID, obj.Error)
+	}
+
+	txs, err := cli.SuiXQueryTransactionBlocks(ctx, models.SuiXQueryTransactionBlocksRequest{
+		SuiTransactionBlockResponseQuery: models.SuiTransactionBlockResponseQuery{
+			TransactionFilter: models.TransactionFilter{"InputObject": escrowID},
+		},
+		Limit:           suiEscrowHistory,
+		DescendingOrder: true,
+	})
+	if err != nil {
+		return "", "", rpcError("querying transactions of escrow "+escrowID, err)
+	}
+
+	closes := func(id string) bool { return strings.EqualFold(id, escrowID) }
+	for _, tx := range txs.Data {
+		if withdrawals, err := FetchMoveWithdrawals(ctx, cli, tx.Digest, pkg); err == nil && slices.ContainsFunc(withdrawals, func(w Withdrawal) bool { return closes(w.Escrow) }) {
+			return EscrowWithdrawn, tx.Digest, nil
+		}
+		if escrows, err := FetchMoveCancelledEscrows(ctx, cli, tx.Digest, pkg); err == nil && slices.ContainsFunc(escrows, closes) {
+			return EscrowCancelled, tx.Digest, nil
+		}
+	}
+	return "", "", fmt.Errorf("escrow object %s is deleted but no withdrawal or cancellation was found", escrowID)
+}
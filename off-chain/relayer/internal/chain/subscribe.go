@@ -0,0 +1,305 @@
+package chain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"relayer/pkg/reconnect"
+	"sync"
+	"time"
+
+	"github.com/block-vision/sui-go-sdk/models"
+	"github.com/coder/websocket"
+)
+
+// Pacing of a SuiSubscription's connection.
+const (
+	SubscribePingInterval = 30 * time.Second
+	SubscribePingTimeout  = 10 * time.Second
+	// events per suix_queryEvents page when catching up, the RPC maximum
+	catchUpPageSize = 50
+)
+
+// CursorStore persists the last event a subscription handled, so a restart
+// catches up from it instead of dropping the events emitted while down.
+type CursorStore interface {
+	// LoadCursor returns the stored cursor of name, nil when there is none.
+	LoadCursor(ctx context.Context, name string) (*models.EventId, error)
+	SaveCursor(ctx context.Context, name string, cursor models.EventId) error
+}
+
+// SuiSubscription follows the Move events matching a filter with
+// suix_subscribeEvent on a Sui websocket endpoint. It reconnects with
+// exponential backoff, and after every (re)connection first pages through the
+// events emitted since its cursor with suix_queryEvents on the HTTP client,
+// so a dropped connection or a restart loses nothing. Without a stored cursor
+// it starts from the events emitted after it first connects.
+type SuiSubscription struct {
+	url     string
+	name    string
+	filter  any
+	cli     SuiClient
+	cursors CursorStore
+
+	reconnect.Backoff
+
+	// OnError reports connection drops and failures to catch up or persist the
+	// cursor; the subscription keeps running. Nil drops them.
+	OnError func(err error)
+
+	mu        sync.Mutex
+	cursor    *models.EventId
+	loaded    bool
+	connected bool
+}
+
+// NewSuiSubscription follows the events matching filter, a suix_subscribeEvent
+// event filter such as {"MoveEventModule": {"package": ..., "module": ...}},
+// on the websocket endpoint wsURL, catching up with cli. The cursor is stored
+// under name in cursors, which may be nil to keep it in memory only.
+func NewSuiSubscription(wsURL, name string, filter any, cli SuiClient, cursors CursorStore) *SuiSubscription {
+	return &SuiSubscription{
+		url:     wsURL,
+		name:    name,
+		filter:  filter,
+		cli:     cli,
+		cursors: cursors,
+		Backoff: reconnect.DefaultBackoff(),
+	}
+}
+
+// Connected reports whether the subscription is live on the websocket.
+func (s *SuiSubscription) Connected() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.connected
+}
+
+// Cursor returns the id of the last event handled, nil before the first.
+func (s *SuiSubscription) Cursor() *models.EventId {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cursor == nil {
+		return nil
+	}
+	cursor := *s.cursor
+	return &cursor
+}
+
+// Run calls handle with every matching event, oldest first, until ctx is
+// cancelled, reconnecting on any failure. Events are delivered at least once:
+// one handled just before a crash, its cursor unsaved, is delivered again. It
+// only returns ctx.Err().
+func (s *SuiSubscription) Run(ctx context.Context, handle func(ev models.SuiEventResponse)) error {
+	return s.Backoff.Run(ctx, func(ctx context.Context) (bool, error) {
+		return s.runOnce(ctx, handle)
+	}, s.reportError)
+}
+
+// rpcMessage is a JSON-RPC response or subscription notification.
+type rpcMessage struct {
+	Error *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+	Params *struct {
+		Result models.SuiEventResponse `json:"result"`
+	} `json:"params"`
+}
+
+// runOnce subscribes on a fresh connection, catches up from the cursor and
+// then handles notifications until the connection fails, reporting whether
+// it got as far as subscribing.
+func (s *SuiSubscription) runOnce(ctx context.Context, handle func(ev models.SuiEventResponse)) (bool, error) {
+	if err := s.loadCursor(ctx); err != nil {
+		return false, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	conn, _, err := websocket.Dial(ctx, s.url, nil)
+	if err != nil {
+		return false, fmt.Errorf("dialing %s: %w", s.url, err)
+	}
+	defer conn.CloseNow()
+	// checkpoints may carry many events, well above the 32KiB default
+	conn.SetReadLimit(8 << 20)
+
+	req, err := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "suix_subscribeEvent",
+		"params":  []any{s.filter},
+	})
+	if err != nil {
+		return false, err
+	}
+	if err := conn.Write(ctx, websocket.MessageText, req); err != nil {
+		return false, fmt.Errorf("subscribing: %w", err)
+	}
+
+	// the answer to the subscription comes before any notification
+	var msg rpcMessage
+	if err := s.read(ctx, conn, &msg); err != nil {
+		return false, fmt.Errorf("subscribing: %w", err)
+	}
+	if msg.Error != nil {
+		return false, fmt.Errorf("suix_subscribeEvent: %s (code %d)", msg.Error.Message, msg.Error.Code)
+	}
+
+	s.setConnected(true)
+	defer s.setConnected(false)
+
+	// notifications queue on the connection meanwhile; those the catch-up
+	// already delivered are skipped
+	seen, err := s.catchUp(ctx, handle)
+	if err != nil {
+		return true, fmt.Errorf("catching up: %w", err)
+	}
+
+	// pongs are only read along with notifications, so pings start here
+	go s.keepAlive(ctx, conn, cancel)
+
+	for {
+		var msg rpcMessage
+		if err := s.read(ctx, conn, &msg); err != nil {
+			return true, err
+		}
+		if msg.Params == nil {
+			continue
+		}
+		ev := msg.Params.Result
+		if _, ok := seen[ev.Id]; ok {
+			delete(seen, ev.Id)
+			continue
+		}
+		s.deliver(ctx, ev, handle)
+	}
+}
+
+// catchUp delivers the events emitted after the cursor, returning their ids.
+func (s *SuiSubscription) catchUp(ctx context.Context, handle func(ev models.SuiEventResponse)) (map[models.EventId]struct{}, error) {
+	seen := make(map[models.EventId]struct{})
+	cursor := s.Cursor()
+	if cursor == nil {
+		return seen, nil
+	}
+
+	for {
+		page, err := s.cli.SuiXQueryEvents(ctx, models.SuiXQueryEventsRequest{
+			SuiEventFilter: s.filter,
+			Cursor:         *cursor,
+			Limit:          catchUpPageSize,
+		})
+		if err != nil {
+			return seen, err
+		}
+		for _, ev := range page.Data {
+			seen[ev.Id] = struct{}{}
+			s.deliver(ctx, ev, handle)
+		}
+		if !page.HasNextPage || page.NextCursor.TxDigest == "" {
+			return seen, nil
+		}
+		next := page.NextCursor
+		cursor = &next
+	}
+}
+
+// deliver hands ev to handle and advances the cursor past it.
+func (s *SuiSubscription) deliver(ctx context.Context, ev models.SuiEventResponse, handle func(ev models.SuiEventResponse)) {
+	handle(ev)
+
+	s.mu.Lock()
+	id := ev.Id
+	s.cursor = &id
+	s.mu.Unlock()
+
+	if s.cursors != nil {
+		if err := s.cursors.SaveCursor(ctx, s.name, id); err != nil {
+			s.reportError(fmt.Errorf("saving cursor: %w", err))
+		}
+	}
+}
+
+// loadCursor reads the stored cursor once, before the first connection.
+func (s *SuiSubscription) loadCursor(ctx context.Context) error {
+	s.mu.Lock()
+	loaded := s.loaded
+	s.mu.Unlock()
+	if loaded || s.cursors == nil {
+		return nil
+	}
+
+	cursor, err := s.cursors.LoadCursor(ctx, s.name)
+	if err != nil {
+		return fmt.Errorf("loading cursor: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cursor, s.loaded = cursor, true
+	return nil
+}
+
+func (s *SuiSubscription) read(ctx context.Context, conn *websocket.Conn, msg *rpcMessage) error {
+	_, data, err := conn.Read(ctx)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, msg); err != nil {
+		return fmt.Errorf("decoding %q: %w", truncate(data), err)
+	}
+	return nil
+}
+
+// keepAlive pings the endpoint, cancelling the connection once a ping goes
+// unanswered, as a silently dropped connection would never fail a read.
+func (s *SuiSubscription) keepAlive(ctx context.Context, conn *websocket.Conn, cancel context.CancelFunc) {
+	ticker := time.NewTicker(SubscribePingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			pingCtx, pingCancel := context.WithTimeout(ctx, SubscribePingTimeout)
+			err := conn.Ping(pingCtx)
+			pingCancel()
+			if err != nil {
+				if !errors.Is(err, context.Canceled) {
+					s.reportError(fmt.Errorf("ping: %w", err))
+				}
+				cancel()
+				return
+			}
+		}
+	}
+}
+
+func (s *SuiSubscription) setConnected(connected bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.connected = connected
+}
+
+func (s *SuiSubscription) reportError(err error) {
+	if s.OnError != nil {
+		s.OnError(fmt.Errorf("%s: %w", s.name, err))
+	}
+}
+
+// truncate shortens a frame quoted in an error.
+func truncate(data []byte) string {
+	if len(data) > 256 {
+		return string(data[:256]) + "..."
+	}
+	return string(data)
+}
@@ -0,0 +1,42 @@
+package chain
+
+import "time"
+
+// EventTime is when a chain event happened, normalized from the chain's own
+// clock: EVM block timestamps are whole seconds, Sui transaction timestamps
+// milliseconds. Precision is the resolution of that clock, so the event
+// happened somewhere in [Time, Time+Precision).
+type EventTime struct {
+	time.Time
+	Precision time.Duration
+}
+
+// EvmEventTime is the time of an event in a block with the given timestamp.
+func EvmEventTime(blockTime uint64) EventTime {
+	return EventTime{Time: time.Unix(int64(blockTime), 0), Precision: time.Second}
+}
+
+// MoveEventTime is the time of an event in a Sui transaction with the given
+// timestamp in milliseconds.
+func MoveEventTime(timestampMs int64) EventTime {
+	return EventTime{Time: time.UnixMilli(timestampMs), Precision: time.Millisecond}
+}
+
+// Earliest is the earliest instant the event may have happened at.
+func (t EventTime) Earliest() time.Time {
+	return t.Time
+}
+
+// Latest is the latest instant the event may have happened at. Waits that
+// must cover the event, like finality, count from here.
+func (t EventTime) Latest() time.Time {
+	if t.Precision <= 0 {
+		return t.Time
+	}
+	return t.Time.Add(t.Precision - time.Nanosecond)
+}
+
+// Elapsed is how long ago the event happened, at least.
+func (t EventTime) Elapsed(now time.Time) time.Duration {
+	return now.Sub(t.Latest())
+}
@@ -0,0 +1,109 @@
+package chain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEventTime(t *testing.T) {
+	base := time.Unix(1_700_000_000, 0)
+
+	tests := []struct {
+		name     string
+		event    EventTime
+		earliest time.Time
+		latest   time.Time
+	}{
+		{
+			name:     "evm block second",
+			event:    EvmEventTime(1_700_000_000),
+			earliest: base,
+			latest:   base.Add(time.Second - time.Nanosecond),
+		},
+		{
+			name:     "move millisecond",
+			event:    MoveEventTime(1_700_000_000_250),
+			earliest: base.Add(250 * time.Millisecond),
+			latest:   base.Add(251*time.Millisecond - time.Nanosecond),
+		},
+		{
+			name:     "move on the second",
+			event:    MoveEventTime(1_700_000_000_000),
+			earliest: base,
+			latest:   base.Add(time.Millisecond - time.Nanosecond),
+		},
+		{
+			name:     "exact",
+			event:    EventTime{Time: base},
+			earliest: base,
+			latest:   base,
+		},
+		{
+			name:     "negative precision",
+			event:    EventTime{Time: base, Precision: -time.Second},
+			earliest: base,
+			latest:   base,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.event.Earliest(); !got.Equal(tt.earliest) {
+				t.Errorf("Earliest() = %s, want %s", got, tt.earliest)
+			}
+			if got := tt.event.Latest(); !got.Equal(tt.latest) {
+				t.Errorf("Latest() = %s, want %s", got, tt.latest)
+			}
+		})
+	}
+}
+
+// An EVM block and a Sui checkpoint of the same second must not order the
+// Sui event first only because the EVM timestamp is truncated.
+func TestEventTimeAcrossChains(t *testing.T) {
+	evm := EvmEventTime(1_700_000_000)
+	sui := MoveEventTime(1_700_000_000_500)
+
+	tests := []struct {
+		name   string
+		before bool
+		a, b   EventTime
+	}{
+		{"same instant", false, evm, evm},
+		{"sui within the evm second", false, sui, evm},
+		{"evm within its second of sui", false, evm, sui},
+		{"sui a second earlier", true, MoveEventTime(1_699_999_999_000), evm},
+		{"evm a second earlier", true, EvmEventTime(1_699_999_999), sui},
+		{"out of order", false, EvmEventTime(1_700_000_001), sui},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// a certainly happened before b
+			if got := tt.a.Latest().Before(tt.b.Earliest()); got != tt.before {
+				t.Errorf("%s before %s = %v, want %v", tt.a.Time, tt.b.Time, got, tt.before)
+			}
+		})
+	}
+}
+
+func TestEventTimeElapsed(t *testing.T) {
+	event := EvmEventTime(1_700_000_000)
+	at := time.Unix(1_700_000_000, 0)
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want time.Duration
+	}{
+		{"within the block second", at.Add(500 * time.Millisecond), 500*time.Millisecond - time.Second + time.Nanosecond},
+		{"end of the block second", at.Add(time.Second), time.Nanosecond},
+		{"a minute later", at.Add(time.Minute + time.Second), time.Minute + time.Nanosecond},
+		{"clock behind the chain", at.Add(-time.Minute), -time.Minute - time.Second + time.Nanosecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := event.Elapsed(tt.now); got != tt.want {
+				t.Errorf("Elapsed() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package chain
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/block-vision/sui-go-sdk/models"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// evmEscrowWithdrawalSig is the topic of BaseEscrow's `event EscrowWithdrawal(bytes32 secret)`.
+var evmEscrowWithdrawalSig = crypto.Keccak256Hash([]byte("EscrowWithdrawal(bytes32)"))
+
+// Withdrawal is an escrow paid out in a transaction and the secret its
+// withdrawal revealed.
+type Withdrawal struct {
+	Escrow string
+	Secret []byte
+}
+
+// FetchEvmWithdrawals returns the escrows that emitted EscrowWithdrawal in
+// txHash with the secrets in the events.
+func FetchEvmWithdrawals(ctx context.Context, client EVMClient, txHash common.Hash) ([]Withdrawal, error) {
+	receipt, err := client.TransactionReceipt(ctx, txHash)
+	if err != nil {
+		return nil, rpcError("fetching receipt", err)
+	}
+
+	var withdrawals []Withdrawal
+	for _, vLog := range receipt.Logs {
+		if len(vLog.Topics) == 0 || vLog.Topics[0] != evmEscrowWithdrawalSig {
+			continue
+		}
+		// the secret is not indexed
+		if len(vLog.Data) != common.HashLength {
+			return nil, decodeError("EscrowWithdrawal", fmt.Errorf("%d bytes of data, expected %d", len(vLog.Data), common.HashLength))
+		}
+		withdrawals = append(withdrawals, Withdrawal{Escrow: vLog.Address.Hex(), Secret: vLog.Data})
+	}
+
+	if len(withdrawals) == 0 {
+		return nil, fmt.Errorf("%w: EscrowWithdrawal not in the logs of tx %s", ErrEventNotFound, txHash.Hex())
+	}
+	return withdrawals, nil
+}
+
+// FetchMoveWithdrawals returns the escrow object IDs withdrawn in txDigest,
+// from either src_escrow::EscrowWithdrawal or dst_escrow::DstEscrowWithdrawnEvent,
+// with the secrets in the events. Events of packages other than pkg are
+// ignored, unless pkg is empty.
+func FetchMoveWithdrawals(ctx context.Context, cli SuiClient, txDigest, pkg string) ([]Withdrawal, error) {
+	events, err := cli.SuiGetEvents(ctx, models.SuiGetEventsRequest{
+		Digest: txDigest,
+	})
+	if err != nil {
+		return nil, rpcError("fetching events", err)
+	}
+
+	var withdrawals []Withdrawal
+	for _, ev := range events {
+		var field string
+		switch {
+		case strings.HasSuffix(ev.Type, "::EscrowWithdrawal"):
+			field = "escrow_id"
+		case strings.HasSuffix(ev.Type, "::DstEscrowWithdrawnEvent"):
+			field = "id"
+		default:
+			continue
+		}
+		if pkg != "" && !sameMovePackage(ev.Type, pkg) {
+			continue
+		}
+
+		id, ok := ev.ParsedJson[field].(string)
+		if !ok {
+			continue
+		}
+		secret, err := moveBytes(ev.ParsedJson["secret"])
+		if err != nil {
+			return nil, decodeError(ev.Type+" secret", err)
+		}
+		withdrawals = append(withdrawals, Withdrawal{Escrow: id, Secret: secret})
+	}
+
+	if len(withdrawals) == 0 {
+		return nil, fmt.Errorf("%w: escrow withdrawal not in tx %s", ErrEventNotFound, txDigest)
+	}
+	return withdrawals, nil
+}
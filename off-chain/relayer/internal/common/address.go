@@ -0,0 +1,69 @@
+package common
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/mr-tron/base58"
+)
+
+var (
+	evmAddressRe = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+	suiAddressRe = regexp.MustCompile(`^0x[0-9a-fA-F]{1,64}$`)
+	evmTxHashRe  = regexp.MustCompile(`^0x[0-9a-fA-F]{64}$`)
+)
+
+// suiDigestLength is the byte length of a Sui transaction digest.
+const suiDigestLength = 32
+
+// IsSuiChain reports whether a decimal or CAIP-2 chain ID names Sui.
+func IsSuiChain(chainID string) bool {
+	id, err := parseChainRef(chainID)
+	return err == nil && id.IsMove()
+}
+
+// ValidateAddress checks that addr is well formed for the chain with the
+// given decimal or CAIP-2 ID: 32 byte hex on Sui, 20 byte hex on EVM chains.
+func ValidateAddress(chainID string, addr string) error {
+	if IsSuiChain(chainID) {
+		if !suiAddressRe.MatchString(addr) {
+			return fmt.Errorf("invalid Sui address: %q", addr)
+		}
+		return nil
+	}
+
+	if !evmAddressRe.MatchString(addr) {
+		return fmt.Errorf("invalid EVM address: %q", addr)
+	}
+	return nil
+}
+
+// ValidateTxHash checks that hash is a well formed transaction identifier
+// on the chain with the given decimal or CAIP-2 ID: a base58 digest of 32
+// bytes on Sui, 0x and 64 hex digits on EVM chains.
+func ValidateTxHash(chainID string, hash string) error {
+	if IsSuiChain(chainID) {
+		digest, err := base58.Decode(hash)
+		if err != nil || len(digest) != suiDigestLength {
+			return fmt.Errorf("invalid Sui transaction digest: %q", hash)
+		}
+		return nil
+	}
+
+	if !evmTxHashRe.MatchString(hash) {
+		return fmt.Errorf("invalid EVM transaction hash: %q", hash)
+	}
+	return nil
+}
+
+// SameAddress compares hex addresses ignoring case and leading zero padding,
+// since Sui addresses are 32 bytes and EVM addresses 20.
+func SameAddress(a, b string) bool {
+	return strings.EqualFold(trimAddress(a), trimAddress(b))
+}
+
+func trimAddress(addr string) string {
+	addr = strings.TrimPrefix(strings.ToLower(addr), "0x")
+	return strings.TrimLeft(addr, "0")
+}
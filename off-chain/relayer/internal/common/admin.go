@@ -0,0 +1,144 @@
+package common
+
+import "time"
+
+// Admin API types, relayer extension with no TS equivalent.
+
+// AdminOrder is an operator view of an order tracked by the relayer.
+type AdminOrder struct {
+	OrderHash          string                    `json:"orderHash"`
+	SrcChainID         string                    `json:"srcChainId"`
+	OrderType          string                    `json:"orderType"`
+	Status             OrderStatusMode           `json:"status"`
+	QuoteID            string                    `json:"quoteId"`
+	SubmittedAt        time.Time                 `json:"submittedAt"`
+	FilledMakingAmount string                    `json:"filledMakingAmount"`
+	CancelTx           *string                   `json:"cancelTx,omitempty"`
+	Fills              []ReadyToAcceptSecretFill `json:"fills"`
+	Escrows            map[string]string         `json:"escrows,omitempty"`
+	DstReceiver        string                    `json:"dstReceiver,omitempty"`
+	Hashlock           string                    `json:"hashlock,omitempty"`
+	Archived           bool                      `json:"archived"`
+	LimitOrder         *LimitOrder               `json:"order,omitempty"`
+	Extension          string                    `json:"extension,omitempty"`
+	SecretHashes       []string                  `json:"secretHashes,omitempty"`
+	Upstream           *UpstreamSubmission       `json:"upstream,omitempty"`
+}
+
+// OrderSummary is a stored order as found by an order search.
+type OrderSummary struct {
+	OrderHash    string          `json:"orderHash"`
+	Status       OrderStatusMode `json:"status"`
+	SrcChainID   string          `json:"srcChainId"`
+	DstChainID   string          `json:"dstChainId"`
+	Maker        string          `json:"maker"`
+	MakerAsset   string          `json:"makerAsset"`
+	TakerAsset   string          `json:"takerAsset"`
+	MakingAmount string          `json:"makingAmount"`
+	TakingAmount string          `json:"takingAmount"`
+	QuoteID      string          `json:"quoteId"`
+	SubmittedAt  time.Time       `json:"submittedAt"`
+	UpdatedAt    time.Time       `json:"updatedAt"`
+}
+
+// OrderSearchResult is a page of an order search. NextCursor fetches the
+// next page, it is empty on the last one.
+type OrderSearchResult struct {
+	Orders     []OrderSummary `json:"orders"`
+	NextCursor string         `json:"nextCursor,omitempty"`
+}
+
+// UpstreamSubmission is the answer of the official 1inch relayer to an order
+// forwarded to it in passthrough mode. Status is 0 when it could not be reached.
+type UpstreamSubmission struct {
+	Status      int       `json:"status"`
+	Body        string    `json:"body,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	SubmittedAt time.Time `json:"submittedAt"`
+}
+
+// QuarantinedOrder is an order held back from broadcast because the price it
+// implies deviates from the oracle price of its quote by more than
+// priceDeviationBps, awaiting an admin's approval.
+type QuarantinedOrder struct {
+	OrderHash     string    `json:"orderHash"`
+	SrcChainID    string    `json:"srcChainId"`
+	DstChainID    string    `json:"dstChainId"`
+	QuoteID       string    `json:"quoteId"`
+	Maker         string    `json:"maker"`
+	MakingAmount  string    `json:"makingAmount"`
+	TakingAmount  string    `json:"takingAmount"`
+	DeviationBps  int64     `json:"deviationBps"`
+	QuarantinedAt time.Time `json:"quarantinedAt"`
+}
+
+// SafetyDepositQuote is the safety deposit a chain's quotes currently get
+// from its configured parameters, in the chain's native base units.
+type SafetyDepositQuote struct {
+	Chain         string `json:"chain"`
+	GasUnits      uint64 `json:"gasUnits"`
+	MultiplierBps uint64 `json:"multiplierBps"`
+	Floor         string `json:"floor,omitempty"`
+	GasPrice      string `json:"gasPrice,omitempty"`
+	Deposit       string `json:"deposit,omitempty"`
+	Error         string `json:"error,omitempty"`
+}
+
+// AdminQuote is an operator view of a cached quote.
+type AdminQuote struct {
+	QuoteID      string              `json:"quoteId"`
+	FeeBps       uint64              `json:"feeBps"`
+	QuoteRequest *QuoteRequestParams `json:"quoteRequest"`
+	Quote        *Quote              `json:"quote"`
+	ParentID     string              `json:"parentId,omitempty"`
+	LegIndex     int                 `json:"legIndex,omitempty"`
+	// order placed against the quote, if any
+	OrderHash string `json:"orderHash,omitempty"`
+}
+
+// ChainHealth is the connectivity of one chain endpoint. Head is the latest
+// block number for EVM chains and the clock in milliseconds for Sui; Lag is
+// how far its timestamp trails the wall clock.
+type ChainHealth struct {
+	Chain   string `json:"chain"`
+	OK      bool   `json:"ok"`
+	Head    uint64 `json:"head,omitempty"`
+	Lag     string `json:"lag,omitempty"`
+	Behind  bool   `json:"behind,omitempty"`
+	Latency string `json:"latency"`
+	Error   string `json:"error,omitempty"`
+}
+
+// UpstreamKeyUsage is the use of one 1inch API key since the relayer
+// started. Key is a label, not the key itself; CoolingUntil is set while the
+// key is left out of the rotation after a 429.
+type UpstreamKeyUsage struct {
+	Key          string     `json:"key"`
+	Requests     int64      `json:"requests"`
+	Throttled    int64      `json:"throttled"`
+	CoolingUntil *time.Time `json:"coolingUntil,omitempty"`
+}
+
+// ResolverStats is the liveness of an authenticated resolver since the
+// relayer started. Uptime is the share of the time since it first connected
+// it had a connection open; Score, from 0 to 1, weighs uptime, answered
+// pings and ping round trips.
+type ResolverStats struct {
+	ID             string     `json:"id"`
+	Connected      bool       `json:"connected"`
+	Connections    int        `json:"connections"`
+	ConnectedSince *time.Time `json:"connectedSince,omitempty"`
+	Uptime         float64    `json:"uptime"`
+	// moving average round trip of the keepalive pings
+	PingRTT      string     `json:"pingRtt,omitempty"`
+	Pings        int        `json:"pings"`
+	PingFailures int        `json:"pingFailures"`
+	LastPing     *time.Time `json:"lastPing,omitempty"`
+	Score        float64    `json:"score"`
+}
+
+// ReverifyRequest asks the relayer to verify a fill again.
+type ReverifyRequest struct {
+	SrcTxHash string `json:"srcTxHash"`
+	DstTxHash string `json:"dstTxHash"`
+}
@@ -0,0 +1,104 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+)
+
+// Amounts travel as decimal strings of a token's base units, as the 1inch
+// API and the order structs carry them. The parsers below check what a bare
+// big.Int SetString does not: the sign and the width of the chain's amounts.
+
+// ErrInvalidAmount is wrapped by every amount parsing error.
+var ErrInvalidAmount = errors.New("invalid amount")
+
+var (
+	// MaxUint256 bounds EVM token amounts
+	MaxUint256 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+	// MaxUint64 bounds Sui coin balances
+	MaxUint64 = new(big.Int).SetUint64(math.MaxUint64)
+)
+
+// ParseAmount parses a non-negative decimal amount of base units that fits
+// in a uint256.
+func ParseAmount(s string) (*big.Int, error) {
+	return parseBounded(s, MaxUint256)
+}
+
+// MaxAmount is the largest token amount the chain's escrows hold: a u64 on
+// Sui, a uint256 on EVM chains.
+func (c ChainID) MaxAmount() *big.Int {
+	if c.IsMove() {
+		return MaxUint64
+	}
+	return MaxUint256
+}
+
+// ParseAmount parses a non-negative decimal amount of base units that fits
+// in the chain's amounts.
+func (c ChainID) ParseAmount(s string) (*big.Int, error) {
+	return parseBounded(s, c.MaxAmount())
+}
+
+// ParseUnits parses a human readable amount such as "1.5" of a token with
+// decimals into base units. More fractional digits than decimals are
+// rejected rather than rounded.
+func ParseUnits(s string, decimals uint8) (*big.Int, error) {
+	whole, frac, _ := strings.Cut(s, ".")
+	if len(frac) > int(decimals) {
+		return nil, fmt.Errorf("%w %q: more than %d decimals", ErrInvalidAmount, s, decimals)
+	}
+	if whole == "" && frac == "" {
+		return nil, fmt.Errorf("%w %q", ErrInvalidAmount, s)
+	}
+	if whole == "" {
+		whole = "0"
+	}
+
+	v, err := ParseAmount(whole + frac + strings.Repeat("0", int(decimals)-len(frac)))
+	if err != nil {
+		return nil, fmt.Errorf("%w %q", ErrInvalidAmount, s)
+	}
+	return v, nil
+}
+
+// FormatUnits renders base units of a token with decimals as a human
+// readable amount, without trailing zeros.
+func FormatUnits(v *big.Int, decimals uint8) string {
+	digits := new(big.Int).Abs(v).String()
+	sign := ""
+	if v.Sign() < 0 {
+		sign = "-"
+	}
+	if decimals == 0 {
+		return sign + digits
+	}
+
+	if len(digits) <= int(decimals) {
+		digits = strings.Repeat("0", int(decimals)-len(digits)+1) + digits
+	}
+	split := len(digits) - int(decimals)
+	frac := strings.TrimRight(digits[split:], "0")
+	if frac == "" {
+		return sign + digits[:split]
+	}
+	return sign + digits[:split] + "." + frac
+}
+
+func parseBounded(s string, max *big.Int) (*big.Int, error) {
+	if s == "" || strings.TrimLeft(s, "0123456789") != "" {
+		return nil, fmt.Errorf("%w %q", ErrInvalidAmount, s)
+	}
+
+	v, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrInvalidAmount, s)
+	}
+	if v.Cmp(max) > 0 {
+		return nil, fmt.Errorf("%w %q: overflows %d bits", ErrInvalidAmount, s, max.BitLen())
+	}
+	return v, nil
+}
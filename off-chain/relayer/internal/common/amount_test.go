@@ -0,0 +1,111 @@
+package common
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestParseAmount(t *testing.T) {
+	maxUint256 := MaxUint256.String()
+	overUint256 := new(big.Int).Add(MaxUint256, big.NewInt(1)).String()
+
+	tests := []struct {
+		name    string
+		chain   ChainID
+		s       string
+		want    string
+		wantErr bool
+	}{
+		{name: "zero", chain: EthereumMainnet, s: "0", want: "0"},
+		{name: "amount", chain: EthereumMainnet, s: "1500000000000000000", want: "1500000000000000000"},
+		{name: "leading zeros", chain: EthereumMainnet, s: "007", want: "7"},
+		{name: "max uint256", chain: EthereumMainnet, s: maxUint256, want: maxUint256},
+		{name: "overflows uint256", chain: EthereumMainnet, s: overUint256, wantErr: true},
+		{name: "max u64 on Sui", chain: Sui, s: "18446744073709551615", want: "18446744073709551615"},
+		{name: "overflows u64 on Sui", chain: Sui, s: "18446744073709551616", wantErr: true},
+		{name: "negative", chain: EthereumMainnet, s: "-1", wantErr: true},
+		{name: "plus sign", chain: EthereumMainnet, s: "+1", wantErr: true},
+		{name: "empty", chain: EthereumMainnet, s: "", wantErr: true},
+		{name: "decimal point", chain: EthereumMainnet, s: "1.5", wantErr: true},
+		{name: "hex", chain: EthereumMainnet, s: "0x10", wantErr: true},
+		{name: "exponent", chain: EthereumMainnet, s: "1e18", wantErr: true},
+		{name: "underscores", chain: EthereumMainnet, s: "1_000", wantErr: true},
+		{name: "spaces", chain: EthereumMainnet, s: " 1", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.chain.ParseAmount(tt.s)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidAmount) {
+					t.Fatalf("got %v, %v, want %v", got, err, ErrInvalidAmount)
+				}
+				return
+			}
+			if err != nil || got.String() != tt.want {
+				t.Fatalf("got %v, %v, want %s", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseUnits(t *testing.T) {
+	tests := []struct {
+		s        string
+		decimals uint8
+		want     string
+		wantErr  bool
+	}{
+		{s: "1.5", decimals: 6, want: "1500000"},
+		{s: "1", decimals: 18, want: "1000000000000000000"},
+		{s: ".25", decimals: 2, want: "25"},
+		{s: "3.", decimals: 2, want: "300"},
+		{s: "0.000001", decimals: 6, want: "1"},
+		{s: "42", decimals: 0, want: "42"},
+		{s: "0.0000001", decimals: 6, wantErr: true},
+		{s: "1.5", decimals: 0, wantErr: true},
+		{s: ".", decimals: 6, wantErr: true},
+		{s: "", decimals: 6, wantErr: true},
+		{s: "-1.5", decimals: 6, wantErr: true},
+		{s: "1.2.3", decimals: 6, wantErr: true},
+		{s: "1" + strings.Repeat("0", 78), decimals: 0, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			got, err := ParseUnits(tt.s, tt.decimals)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidAmount) {
+					t.Fatalf("got %v, %v, want %v", got, err, ErrInvalidAmount)
+				}
+				return
+			}
+			if err != nil || got.String() != tt.want {
+				t.Fatalf("got %v, %v, want %s", got, err, tt.want)
+			}
+			if back, _ := ParseUnits(FormatUnits(got, tt.decimals), tt.decimals); back.Cmp(got) != 0 {
+				t.Fatalf("FormatUnits(%s) = %s does not parse back", got, FormatUnits(got, tt.decimals))
+			}
+		})
+	}
+}
+
+func TestFormatUnits(t *testing.T) {
+	tests := []struct {
+		v        int64
+		decimals uint8
+		want     string
+	}{
+		{v: 1_500_000, decimals: 6, want: "1.5"},
+		{v: 1, decimals: 6, want: "0.000001"},
+		{v: 2_000_000, decimals: 6, want: "2"},
+		{v: 0, decimals: 6, want: "0"},
+		{v: -1_250, decimals: 3, want: "-1.25"},
+		{v: 42, decimals: 0, want: "42"},
+	}
+	for _, tt := range tests {
+		if got := FormatUnits(big.NewInt(tt.v), tt.decimals); got != tt.want {
+			t.Errorf("FormatUnits(%d, %d) = %s, want %s", tt.v, tt.decimals, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package common
+
+// Public order book types, relayer extension with no TS equivalent.
+
+// PublicOrder is the anonymized summary of an order on the public order book
+// feed: no maker, receiver or order hash. ID correlates the updates of one
+// order. The auction runs from AuctionStartDate for AuctionDuration seconds,
+// its taking amount decaying from AuctionStartAmount to AuctionEndAmount.
+type PublicOrder struct {
+	ID                 string          `json:"id"`
+	SrcChainID         string          `json:"srcChainId"`
+	DstChainID         string          `json:"dstChainId"`
+	SrcToken           string          `json:"srcToken"`
+	DstToken           string          `json:"dstToken"`
+	MakingAmount       string          `json:"makingAmount"`
+	TakingAmount       string          `json:"takingAmount"`
+	Status             OrderStatusMode `json:"status"`
+	AuctionStartDate   int64           `json:"auctionStartDate"`
+	AuctionDuration    int64           `json:"auctionDuration"`
+	AuctionStartAmount string          `json:"auctionStartAmount"`
+	AuctionEndAmount   string          `json:"auctionEndAmount"`
+	MultipleFills      bool            `json:"multipleFills"`
+}
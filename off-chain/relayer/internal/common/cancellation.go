@@ -0,0 +1,85 @@
+package common
+
+// Cancellation data types, relayer extension with no TS equivalent.
+
+// EscrowWindow is the timelock stage an escrow is in.
+type EscrowWindow string
+
+const (
+	// only the escrows' deployments are not final yet, nothing may be called
+	WindowFinality           EscrowWindow = "finality"
+	WindowWithdrawal         EscrowWindow = "withdrawal"
+	WindowPublicWithdrawal   EscrowWindow = "public-withdrawal"
+	WindowCancellation       EscrowWindow = "cancellation"
+	WindowPublicCancellation EscrowWindow = "public-cancellation"
+	// the escrow was withdrawn or cancelled already
+	WindowClosed EscrowWindow = "closed"
+)
+
+// EscrowImmutables are the immutables of an EVM escrow as its withdraw and
+// cancel functions take them, addresses hex and amounts decimal.
+type EscrowImmutables struct {
+	OrderHash     string `json:"orderHash"`
+	Hashlock      string `json:"hashlock"`
+	Maker         string `json:"maker"`
+	Taker         string `json:"taker"`
+	Token         string `json:"token"`
+	Amount        string `json:"amount"`
+	SafetyDeposit string `json:"safetyDeposit"`
+	Timelocks     string `json:"timelocks"`
+}
+
+// EscrowCancellation is what cancelling one escrow takes and when it may be
+// cancelled. Escrow is an address on EVM chains and the escrow object id on
+// Sui, whose escrows carry their immutables themselves.
+type EscrowCancellation struct {
+	ChainID    string            `json:"chainId"`
+	Escrow     string            `json:"escrow"`
+	DeployTx   string            `json:"deployTx"`
+	Immutables *EscrowImmutables `json:"immutables,omitempty"`
+	Window     EscrowWindow      `json:"window"`
+	// unix seconds the cancellation and, for src escrows, public cancellation stages open
+	CancellationAt       int64  `json:"cancellationAt"`
+	PublicCancellationAt int64  `json:"publicCancellationAt,omitempty"`
+	ClosedTx             string `json:"closedTx,omitempty"`
+}
+
+// FillCancellation is the cancellation data of both escrows of a verified fill.
+type FillCancellation struct {
+	Idx int                `json:"idx"`
+	Src EscrowCancellation `json:"src"`
+	Dst EscrowCancellation `json:"dst"`
+}
+
+// CancellationData is the cancellation data of an order's verified fills.
+type CancellationData struct {
+	OrderHash string             `json:"orderHash"`
+	Status    OrderStatusMode    `json:"status"`
+	Fills     []FillCancellation `json:"fills"`
+}
+
+// Escrow is one escrow of a verified fill and what withdrawing from or
+// cancelling it takes. Escrow is an address on EVM chains and the escrow
+// object id on Sui, whose escrows carry their immutables themselves.
+type Escrow struct {
+	ChainID    string            `json:"chainId"`
+	Escrow     string            `json:"escrow"`
+	DeployTx   string            `json:"deployTx"`
+	Immutables *EscrowImmutables `json:"immutables,omitempty"`
+	// unix milliseconds of the deployment
+	DeployedAt int64 `json:"deployedAt"`
+}
+
+// FillEscrows are both escrows of a verified fill.
+type FillEscrows struct {
+	Idx int    `json:"idx"`
+	Src Escrow `json:"src"`
+	Dst Escrow `json:"dst"`
+}
+
+// OrderEscrows are the escrows of an order's verified fills, kept after the
+// order left the relayer's memory.
+type OrderEscrows struct {
+	OrderHash string        `json:"orderHash"`
+	Fills     []FillEscrows `json:"fills"`
+}
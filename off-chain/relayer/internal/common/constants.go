@@ -1,47 +1,206 @@
 package common
 
 import (
+	"encoding/json"
+	"fmt"
 	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ChainID identifies a supported network. The zero value is not a chain.
+// EVM chains use their EIP-155 id; Sui has none and uses 101, the id its
+// escrow contracts encode, which is why APIs also accept CAIP-2 identifiers
+// ("eip155:1", "sui:mainnet") that cannot collide with other networks.
+type ChainID uint64
 
-	"github.com/holiman/uint256"
+const (
+	EthereumMainnet ChainID = 1
+	ArbitrumOne     ChainID = 42161
+	Polygon         ChainID = 137
+	BSC             ChainID = 56
+	Optimism        ChainID = 10
+	Base            ChainID = 8453
+	Sui             ChainID = 101
+
+	// testnets
+	Sepolia         ChainID = 11155111
+	BaseSepolia     ChainID = 84532
+	ArbitrumSepolia ChainID = 421614
 )
 
-// ChainID represents supported network chain IDs as an enum type
-type ChainID *uint256.Int
-
-var (
-	EthereumMainnet ChainID = uint256.NewInt(1)
-	ArbitrumOne     ChainID = uint256.NewInt(42161)
-	Polygon         ChainID = uint256.NewInt(137)
-	BSC             ChainID = uint256.NewInt(56)
-	Optimism        ChainID = uint256.NewInt(10)
-	Base            ChainID = uint256.NewInt(8453)
-	Sui             ChainID = uint256.NewInt(101)
+// supportedChains are the chains of the selected network profile.
+var supportedChains = map[ChainID]bool{
+	EthereumMainnet: true,
+	ArbitrumOne:     true,
+	Polygon:         true,
+	BSC:             true,
+	Optimism:        true,
+	Base:            true,
+	Sui:             true,
+}
+
+// SetSupportedChains replaces the set of chains the relayer serves. It is
+// called once at startup when a network profile is selected.
+func SetSupportedChains(chains ...ChainID) {
+	supportedChains = make(map[ChainID]bool, len(chains))
+	for _, c := range chains {
+		supportedChains[c] = true
+	}
+}
+
+// SupportedChains returns the chains the relayer serves, by id.
+func SupportedChains() []ChainID {
+	chains := make([]ChainID, 0, len(supportedChains))
+	for c := range supportedChains {
+		chains = append(chains, c)
+	}
+	sort.Slice(chains, func(i, j int) bool { return chains[i] < chains[j] })
+	return chains
+}
+
+// CAIP-2 namespaces of the supported chain families.
+const (
+	NamespaceEIP155 = "eip155"
+	NamespaceSui    = "sui"
 )
 
+// SuiNetwork is the Sui network the relayer serves, the reference part of the
+// Sui CAIP-2 identifier.
+var SuiNetwork = "mainnet"
+
+// GetChainID returns the chain with the given numeric ID, or 0 when it is not
+// supported.
 func GetChainID(num big.Int) ChainID {
-	val, overflow := uint256.FromBig(&num)
-	if overflow {
-		return nil
+	if !num.IsUint64() {
+		return 0
+	}
+
+	id := ChainID(num.Uint64())
+	if !id.IsSupported() {
+		return 0
+	}
+	return id
+}
+
+// ParseChainID parses a chain identifier as sent by clients: a CAIP-2 id
+// such as "eip155:1" or "sui:mainnet", or a bare decimal id for backwards
+// compatibility.
+func ParseChainID(s string) (ChainID, error) {
+	id, err := parseChainRef(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid chain id %q: %w", s, err)
+	}
+	if !id.IsSupported() {
+		return 0, fmt.Errorf("unsupported chain id: %q", s)
+	}
+
+	return id, nil
+}
+
+// NormalizeChain rewrites a client chain identifier to the decimal form used
+// in routes, quotes and the 1inch API.
+func NormalizeChain(s string) (string, error) {
+	id, err := ParseChainID(s)
+	if err != nil {
+		return "", err
+	}
+
+	return id.String(), nil
+}
+
+func parseChainRef(s string) (ChainID, error) {
+	namespace, reference, namespaced := strings.Cut(s, ":")
+	if !namespaced {
+		v, err := strconv.ParseUint(s, 10, 64)
+		return ChainID(v), err
 	}
 
-	// Check if the chain ID is supported
-	switch {
-	case val.Eq(EthereumMainnet):
-		return EthereumMainnet
-	case val.Eq(ArbitrumOne):
-		return ArbitrumOne
-	case val.Eq(Polygon):
-		return Polygon
-	case val.Eq(BSC):
-		return BSC
-	case val.Eq(Optimism):
-		return Optimism
-	case val.Eq(Base):
-		return Base
-	case val.Eq(Sui):
-		return Sui
+	switch namespace {
+	case NamespaceEIP155:
+		v, err := strconv.ParseUint(reference, 10, 64)
+		if err != nil || ChainID(v).IsMove() {
+			return 0, fmt.Errorf("invalid eip155 reference %q", reference)
+		}
+		return ChainID(v), nil
+	case NamespaceSui:
+		if reference != SuiNetwork {
+			return 0, fmt.Errorf("relayer serves sui:%s, not sui:%s", SuiNetwork, reference)
+		}
+		return Sui, nil
 	default:
+		return 0, fmt.Errorf("unknown chain namespace %q", namespace)
+	}
+}
+
+// IsSupported reports whether the relayer serves the chain.
+func (c ChainID) IsSupported() bool {
+	return supportedChains[c]
+}
+
+// fusionPlusChains are the mainnets the official 1inch Fusion+ relayer serves.
+var fusionPlusChains = map[ChainID]bool{
+	EthereumMainnet: true,
+	ArbitrumOne:     true,
+	Polygon:         true,
+	BSC:             true,
+	Optimism:        true,
+	Base:            true,
+}
+
+// IsFusionPlus reports whether the official 1inch Fusion+ relayer accepts
+// orders from the chain.
+func (c ChainID) IsFusionPlus() bool {
+	return fusionPlusChains[c]
+}
+
+// IsMove reports whether the chain runs Move escrows (Sui).
+func (c ChainID) IsMove() bool {
+	return c == Sui
+}
+
+// IsEVM reports whether the chain runs the EVM escrow contracts.
+func (c ChainID) IsEVM() bool {
+	return c.IsSupported() && !c.IsMove()
+}
+
+// String returns the decimal chain ID.
+func (c ChainID) String() string {
+	return strconv.FormatUint(uint64(c), 10)
+}
+
+// CAIP2 returns the namespaced chain identifier.
+func (c ChainID) CAIP2() string {
+	if c.IsMove() {
+		return NamespaceSui + ":" + SuiNetwork
+	}
+	return NamespaceEIP155 + ":" + c.String()
+}
+
+// MarshalJSON encodes the chain ID as a JSON number, like the 1inch SDK.
+func (c ChainID) MarshalJSON() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// UnmarshalJSON accepts a JSON number, a decimal string or a CAIP-2 string;
+// null leaves the chain unchanged. Unsupported chains decode fine and are
+// rejected by callers through IsSupported.
+func (c *ChainID) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
 		return nil
 	}
+	if strings.HasPrefix(s, `"`) {
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+	}
+
+	v, err := parseChainRef(s)
+	if err != nil {
+		return fmt.Errorf("invalid chain id %s: %w", data, err)
+	}
+	*c = v
+	return nil
 }
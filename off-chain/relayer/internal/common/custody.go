@@ -0,0 +1,31 @@
+package common
+
+import "github.com/google/uuid"
+
+// Secret custody types, relayer extension with no TS equivalent.
+
+// SecretsRequest asks the relayer to generate the secrets of an order built
+// from QuoteID. Policy is "finality" (default) for the relayer to reveal each
+// fill's secret once its escrows are final, or "manual".
+type SecretsRequest struct {
+	QuoteID uuid.UUID `json:"quoteId"`
+	Policy  string    `json:"policy,omitempty"`
+}
+
+// SecretSet describes generated secrets. ExportToken is only returned on
+// generation and Secrets only on export.
+type SecretSet struct {
+	SecretsID    string   `json:"secretsId"`
+	Algorithm    string   `json:"algorithm"`
+	Policy       string   `json:"policy"`
+	Hashlock     string   `json:"hashlock"`
+	SecretHashes []string `json:"secretHashes"`
+	OrderHash    string   `json:"orderHash,omitempty"`
+	ExportToken  string   `json:"exportToken,omitempty"`
+	Secrets      []string `json:"secrets,omitempty"`
+}
+
+// ExportSecretsRequest carries the token handed out with a secret set.
+type ExportSecretsRequest struct {
+	ExportToken string `json:"exportToken"`
+}
@@ -0,0 +1,15 @@
+package common
+
+// WS order lookup types, relayer extension with no TS equivalent.
+
+// OrderDetail is the ORDER_DETAIL answer to a resolver's GET_ORDER, what it
+// needs to fill an order whose broadcast it missed: the submitted order with
+// its current status and how much of it is filled.
+type OrderDetail struct {
+	OrderHash          string      `json:"orderHash"`
+	Order              *Order      `json:"order"`
+	DstChainID         string      `json:"dstChainId"`
+	Status             OrderStatus `json:"status"`
+	FilledMakingAmount string      `json:"filledMakingAmount"`
+	Archived           bool        `json:"archived"`
+}
@@ -0,0 +1,58 @@
+package common
+
+// Swap progress types, relayer extension with no TS equivalent.
+
+// Phases of a swap in OrderETA, in the order it goes through them. Orders
+// that ended otherwise are in the phase named by their status, e.g. expired.
+const (
+	// waiting for a resolver to deploy the escrows of a fill
+	PhaseAuction = "auction"
+	// escrows deployed, waiting for both deployments to be final
+	PhaseFinality = "finality"
+	// fill final, waiting for the maker to reveal its secret
+	PhaseSecret = "secret"
+	// secret revealed, waiting for the resolver to withdraw from the escrows
+	PhaseWithdrawal = "withdrawal"
+	// both escrows of every fill withdrawn from or cancelled
+	PhaseCompleted = "completed"
+)
+
+// Milestones ending each phase.
+const (
+	MilestoneEscrowsDeployed = "escrowsDeployed"
+	MilestoneSecretReady     = "secretReady"
+	MilestoneSecretRevealed  = "secretRevealed"
+	MilestoneWithdrawn       = "withdrawn"
+)
+
+// Milestone is the next step of a swap and when it is expected, in unix
+// seconds. Steps waiting on the maker are expected right away.
+type Milestone struct {
+	Name       string `json:"name"`
+	ExpectedAt int64  `json:"expectedAt"`
+}
+
+// FillETA is the progress of one verified fill, with the deployment times
+// of its escrows in unix seconds.
+type FillETA struct {
+	Idx           int        `json:"idx"`
+	Phase         string     `json:"phase"`
+	NextMilestone *Milestone `json:"nextMilestone"`
+	SrcDeployedAt int64      `json:"srcDeployedAt"`
+	DstDeployedAt int64      `json:"dstDeployedAt"`
+}
+
+// OrderETA estimates when a swap completes, for countdowns in wallets. The
+// phase and next milestone are those of the least advanced fill, or of the
+// auction while the order waits for more fills. EstimatedCompletion, in unix
+// seconds, and RemainingSeconds are 0 once the order completed or ended
+// otherwise.
+type OrderETA struct {
+	OrderHash           string          `json:"orderHash"`
+	Status              OrderStatusMode `json:"status"`
+	Phase               string          `json:"phase"`
+	NextMilestone       *Milestone      `json:"nextMilestone"`
+	EstimatedCompletion int64           `json:"estimatedCompletion"`
+	RemainingSeconds    int64           `json:"remainingSeconds"`
+	Fills               []FillETA       `json:"fills"`
+}
@@ -0,0 +1,25 @@
+package common
+
+// ChainInfo describes a chain the relayer serves, for frontends building
+// their chain selectors.
+type ChainInfo struct {
+	ChainID ChainID `json:"chainId"`
+	CAIP2   string  `json:"caip2"`
+	// "evm" or "move"
+	VM string `json:"vm"`
+	// escrow factories of the chain's routes, EVM factory addresses or Move
+	// package ids as VM says
+	EscrowFactories []string `json:"escrowFactories,omitempty"`
+	PackageIDs      []string `json:"packageIds,omitempty"`
+	// decimal ids of the chains enabled routes lead to from this one
+	Destinations []string `json:"destinations"`
+}
+
+// TokenInfo is a token the relayer quotes on a chain.
+type TokenInfo struct {
+	Chain string `json:"chain"`
+	// EVM token address or Sui coin type
+	Address  string `json:"address"`
+	Symbol   string `json:"symbol,omitempty"`
+	Decimals uint8  `json:"decimals"`
+}
@@ -0,0 +1,46 @@
+package common
+
+import (
+	"math/big"
+)
+
+// EVMNativeDecimals is the precision of EVM gas tokens (wei). The 1inch API
+// quotes safety deposits in it whatever the chain.
+const EVMNativeDecimals = 18
+
+// SuiNativeDecimals is the precision of SUI (MIST).
+const SuiNativeDecimals = 9
+
+// NativeDecimals returns the number of decimals of the chain's gas token,
+// which safety deposits are paid in.
+func (c ChainID) NativeDecimals() uint8 {
+	if c.IsMove() {
+		return SuiNativeDecimals
+	}
+	return EVMNativeDecimals
+}
+
+// ConvertNative rescales a gas token amount from one precision to another,
+// rounding down when precision is lost.
+func ConvertNative(amount *big.Int, from, to uint8) *big.Int {
+	switch {
+	case from < to:
+		scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(to-from)), nil)
+		return new(big.Int).Mul(amount, scale)
+	case from > to:
+		scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(from-to)), nil)
+		return new(big.Int).Quo(amount, scale)
+	default:
+		return new(big.Int).Set(amount)
+	}
+}
+
+// NativeFromEVM converts a decimal amount quoted in EVM gas token units to
+// the native units of the chain.
+func (c ChainID) NativeFromEVM(amount string) (string, error) {
+	v, err := ParseAmount(amount)
+	if err != nil {
+		return "", err
+	}
+	return ConvertNative(v, EVMNativeDecimals, c.NativeDecimals()).String(), nil
+}
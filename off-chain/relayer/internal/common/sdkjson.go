@@ -0,0 +1,66 @@
+package common
+
+import (
+	"encoding/json"
+
+	"github.com/google/uuid"
+)
+
+// The 1inch SDK reads these payloads without null checks where its types are
+// not nullable, so nil slices are encoded as empty arrays, and quotes without
+// an id carry a null quoteId as the SDK's QuoterResponse does.
+
+// MarshalJSON encodes a nil quote id as null and a nil whitelist as [].
+func (q Quote) MarshalJSON() ([]byte, error) {
+	type quote Quote
+	payload := struct {
+		quote
+		QuoteID   *uuid.UUID `json:"quoteId"`
+		Whitelist []string   `json:"whitelist"`
+	}{quote: quote(q), Whitelist: nonNil(q.Whitelist)}
+	if q.QuoteID != uuid.Nil {
+		payload.QuoteID = &q.QuoteID
+	}
+	return json.Marshal(payload)
+}
+
+// MarshalJSON encodes nil points as [].
+func (p PresetData) MarshalJSON() ([]byte, error) {
+	type presetData PresetData
+	return json.Marshal(struct {
+		presetData
+		Points []AuctionPoint `json:"points"`
+	}{presetData(p), nonNil(p.Points)})
+}
+
+// MarshalJSON encodes nil fills as []; points stay nullable like the SDK's.
+func (s OrderStatus) MarshalJSON() ([]byte, error) {
+	type orderStatus OrderStatus
+	return json.Marshal(struct {
+		orderStatus
+		Fills []Fill `json:"fills"`
+	}{orderStatus(s), nonNil(s.Fills)})
+}
+
+// MarshalJSON encodes nil escrow events as [].
+func (f Fill) MarshalJSON() ([]byte, error) {
+	type fill Fill
+	return json.Marshal(struct {
+		fill
+		EscrowEvents []EscrowEventData `json:"escrowEvents"`
+	}{fill(f), nonNil(f.EscrowEvents)})
+}
+
+// MarshalJSON encodes nil fills as [].
+func (r ReadyToAcceptSecretFills) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Fills []ReadyToAcceptSecretFill `json:"fills"`
+	}{nonNil(r.Fills)})
+}
+
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
@@ -0,0 +1,27 @@
+package common
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func BenchmarkQuoteJSON(b *testing.B) {
+	golden, err := os.ReadFile(filepath.Join("testdata", "quote.json"))
+	if err != nil {
+		b.Fatal(err)
+	}
+	var quote Quote
+	if err := json.Unmarshal(golden, &quote); err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := json.Marshal(&quote); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
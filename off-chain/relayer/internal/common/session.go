@@ -0,0 +1,23 @@
+package common
+
+// Maker session types, relayer extension with no TS equivalent.
+
+// SessionChallengeRequest asks for a sign-in message for Address, a maker's
+// EVM or Sui address.
+type SessionChallengeRequest struct {
+	Address string `json:"address"`
+}
+
+// SessionRequest trades the maker's signature of a challenge for a session:
+// a hex personal_sign signature for EVM makers, a serialized Sui signature
+// for Sui makers.
+type SessionRequest struct {
+	Nonce     string `json:"nonce"`
+	Signature string `json:"signature"`
+}
+
+// Session is a maker session token, sent as a bearer token.
+type Session struct {
+	Token     string `json:"token"`
+	ExpiresAt int64  `json:"expiresAt"`
+}
@@ -1,9 +1,6 @@
 package common
 
 import (
-	"encoding/json"
-	"math/big"
-
 	"github.com/google/uuid"
 )
 
@@ -27,6 +24,14 @@ type QuoteRequestParams struct {
 	DstTokenAddress string `schema:"dstTokenAddress"`
 	Amount          string `schema:"amount"`
 	WalletAddress   string `schema:"walletAddress"`
+	// relayer extension: maker's address on the destination chain when it cannot be
+	// encoded in the order's receiver field, e.g. a Sui address for an EVM maker
+	DstReceiver string `schema:"dstReceiver,omitempty"`
+	// relayer extension: integrator fee in bps of the making amount, deducted from the
+	// quoted amounts on top of the protocol fee, and its receiver on the source chain.
+	// Applied by the relayer, not forwarded to the 1inch API
+	IntegratorFee uint64 `schema:"-"`
+	FeeReceiver   string `schema:"-"`
 }
 
 /*
@@ -66,6 +71,58 @@ type Quote struct {
 	SrcSafetyDeposit  string        `json:"srcSafetyDeposit"`
 	DstSafetyDeposit  string        `json:"dstSafetyDeposit"`
 	AutoK             float64       `json:"autoK"`
+	// relayer extension: protocol fee (bps of the making amount) already deducted from the amounts above
+	ProtocolFeeBps uint64 `json:"protocolFeeBps,omitempty"`
+	// relayer extension: integrator fee (bps of the making amount) deducted from the amounts
+	// above, the order extension must pay it to FeeReceiver
+	IntegratorFeeBps uint64 `json:"integratorFeeBps,omitempty"`
+	FeeReceiver      string `json:"feeReceiver,omitempty"`
+	// relayer extension: unix time after which orders against this quote are rejected
+	ExpiresAt int64 `json:"expiresAt,omitempty"`
+	// relayer extension: hash function the order's secret hashes must use, keccak256 or sha256
+	Hashlock string `json:"hashlock,omitempty"`
+}
+
+// MultiLegQuote is returned instead of a Quote when a chain pair has no
+// direct route and is served through an intermediate chain. Each leg is a
+// regular quote that the maker signs a separate order against; the relayer
+// holds later legs until the previous one has settled.
+type MultiLegQuote struct {
+	QuoteID uuid.UUID  `json:"quoteId"`
+	Legs    []QuoteLeg `json:"legs"`
+	// unix time the first leg quote expires
+	ExpiresAt int64 `json:"expiresAt"`
+}
+
+// QuoteEstimate previews the price of a quote request without issuing a quote:
+// the amounts of the recommended preset and the fees taken on each leg.
+type QuoteEstimate struct {
+	SrcTokenAmount     string        `json:"srcTokenAmount"`
+	DstTokenAmount     string        `json:"dstTokenAmount"`
+	AuctionStartAmount string        `json:"auctionStartAmount"`
+	AuctionEndAmount   string        `json:"auctionEndAmount"`
+	RecommendedPreset  PresetEnum    `json:"recommendedPreset"`
+	Prices             Cost          `json:"prices"`
+	Fees               []FeeEstimate `json:"fees"`
+}
+
+// FeeEstimate is the fees one leg of a quote takes in its source token.
+type FeeEstimate struct {
+	ChainID          string `json:"chainId"`
+	Token            string `json:"token"`
+	ProtocolFeeBps   uint64 `json:"protocolFeeBps"`
+	ProtocolFee      string `json:"protocolFee"`
+	IntegratorFeeBps uint64 `json:"integratorFeeBps,omitempty"`
+	IntegratorFee    string `json:"integratorFee,omitempty"`
+}
+
+// QuoteLeg is one hop of a MultiLegQuote.
+type QuoteLeg struct {
+	SrcChain        string `json:"srcChain"`
+	DstChain        string `json:"dstChain"`
+	SrcTokenAddress string `json:"srcTokenAddress"`
+	DstTokenAddress string `json:"dstTokenAddress"`
+	Quote           Quote  `json:"quote"`
 }
 
 /*
@@ -117,7 +174,7 @@ type PresetData struct {
 		GasBumpEstimate  float64 `json:"gasBumpEstimate"`
 		GasPriceEstimate string  `json:"gasPriceEstimate"`
 	} `json:"gasCost"`
-	ExclusiveResolver *string `json:"exclusiveResolver,omitempty"` // Optional field
+	ExclusiveResolver *string `json:"exclusiveResolver"` // null when any resolver may fill
 	SecretsCount      int     `json:"secretsCount"`
 }
 
@@ -215,33 +272,15 @@ type Order struct {
 	Extension        string     `json:"extension"`
 	SecretHashes     []string   `json:"secretHashes,omitempty"`
 	MakerPubKey      string     `json:"makerPubKey,omitempty"` // Optional field for maker's public key
-}
-
-func (o *Order) UnmarshalJSON(bytes []byte) error {
-	var alias struct {
-		SrcChainID       big.Int    `json:"srcChainId"`
-		LimitOrder       LimitOrder `json:"order"`
-		RelayerSignature string     `json:"relayerSignature,omitempty"` // Optional field
-		Signature        string     `json:"signature"`
-		QuoteID          uuid.UUID  `json:"quoteId"`
-		Extension        string     `json:"extension"`
-		SecretHashes     []string   `json:"secretHashes"`
-	}
-
-	err := json.Unmarshal(bytes, &alias)
-	if err != nil {
-		return err
-	}
-
-	o.SrcChainID = GetChainID(alias.SrcChainID)
-	o.LimitOrder = alias.LimitOrder
-	o.RelayerSignature = alias.RelayerSignature
-	o.Signature = alias.Signature
-	o.QuoteID = alias.QuoteID
-	o.Extension = alias.Extension
-	o.SecretHashes = alias.SecretHashes
-
-	return nil
+	DstReceiver      string     `json:"dstReceiver,omitempty"` // relayer extension, see QuoteRequestParams.DstReceiver
+	Hashlock         string     `json:"hashlock,omitempty"`    // relayer extension, see Quote.Hashlock
+	SecretsID        string     `json:"secretsId,omitempty"`   // relayer extension, set when the relayer generated the secrets
+	// relayer extension: set by the relayer when the maker traits carry USE_PERMIT2_FLAG,
+	// resolvers must fill through the Permit2 path
+	Permit2 bool `json:"permit2,omitempty"`
+	// relayer extension: smallest making amount a partial fill may take, but for the
+	// fill completing the order
+	MinFillAmount string `json:"minFillAmount,omitempty"`
 }
 
 /*
@@ -476,4 +515,6 @@ type ReadyToAcceptSecretFill struct {
 	Idx                   int    `json:"idx"`
 	SrcEscrowDeployTxHash string `json:"srcEscrowDeployTxHash"`
 	DstEscrowDeployTxHash string `json:"dstEscrowDeployTxHash"`
+	// relayer extension: the evidence the fill was verified on
+	Verification *VerificationReport `json:"verification,omitempty"`
 }
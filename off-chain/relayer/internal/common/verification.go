@@ -0,0 +1,31 @@
+package common
+
+// Verification report types, relayer extension with no TS equivalent.
+
+// EscrowEvidence is what the relayer read from chain about one escrow of a
+// fill. Escrow is an address on EVM chains and the escrow object id on Sui.
+type EscrowEvidence struct {
+	ChainID  string `json:"chainId"`
+	Escrow   string `json:"escrow"`
+	DeployTx string `json:"deployTx"`
+	Taker    string `json:"taker"`
+	Amount   string `json:"amount"`
+	// unix milliseconds of the deployment, per the chain's clock
+	DeployedAt int64 `json:"deployedAt"`
+}
+
+// VerificationReport is the evidence a fill was verified on, so makers'
+// clients can check on chain why the relayer considers it safe to release
+// the fill's secret. Checks names the checks that were performed, in order,
+// and Policies the tolerance each amount check applied, e.g. "atLeast -50bps".
+type VerificationReport struct {
+	HashIdx  int               `json:"idx"`
+	Hashlock string            `json:"hashlock"`
+	Src      EscrowEvidence    `json:"src"`
+	Dst      EscrowEvidence    `json:"dst"`
+	Checks   []string          `json:"checks"`
+	Policies map[string]string `json:"policies,omitempty"`
+	// unix seconds the fill was verified and its secret may be released
+	VerifiedAt int64 `json:"verifiedAt"`
+	ReadyAt    int64 `json:"readyAt"`
+}
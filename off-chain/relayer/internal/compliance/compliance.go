@@ -0,0 +1,125 @@
+// Package compliance screens the addresses an order pays from and to, for
+// operators with sanctions obligations. The relayer asks a Screener about the
+// maker and the receiver when a quote is requested and when an order is
+// submitted, and refuses to serve denied addresses. Screeners come from the
+// environment: a blocklist file, an external screening service, both, or
+// none, which allows everything.
+package compliance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// ScreenTimeout bounds the screening of one address.
+const ScreenTimeout = time.Second * 5
+
+// Stage is when an address is screened.
+type Stage string
+
+const (
+	StageQuote  Stage = "quote"
+	StageSubmit Stage = "submit"
+)
+
+// Role is what an address is to the order.
+type Role string
+
+const (
+	RoleMaker    Role = "maker"
+	RoleReceiver Role = "receiver"
+)
+
+// Subject is an address to screen.
+type Subject struct {
+	Chain   string `json:"chain"`
+	Address string `json:"address"`
+	Role    Role   `json:"role"`
+}
+
+// Decision is a screener's verdict on a subject.
+type Decision struct {
+	Allowed bool   `json:"allowed"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// Screener decides whether the relayer may serve an address. Implementations
+// must be safe for concurrent use.
+type Screener interface {
+	// Name identifies the screener in audit records.
+	Name() string
+	Screen(ctx context.Context, stage Stage, subject Subject) (Decision, error)
+}
+
+// ErrDenied is wrapped by the errors of denied subjects.
+var ErrDenied = errors.New("address denied by screening")
+
+// Nop allows every address. It is the screener without configuration.
+type Nop struct{}
+
+func (Nop) Name() string { return "none" }
+
+func (Nop) Screen(context.Context, Stage, Subject) (Decision, error) {
+	return Decision{Allowed: true}, nil
+}
+
+// Enabled reports whether s screens anything.
+func Enabled(s Screener) bool {
+	_, nop := s.(Nop)
+	return s != nil && !nop
+}
+
+// All asks every screener in turn and denies what any of them denies.
+type All []Screener
+
+func (a All) Name() string {
+	name := ""
+	for i, s := range a {
+		if i > 0 {
+			name += "+"
+		}
+		name += s.Name()
+	}
+	return name
+}
+
+func (a All) Screen(ctx context.Context, stage Stage, subject Subject) (Decision, error) {
+	for _, s := range a {
+		d, err := s.Screen(ctx, stage, subject)
+		if err != nil {
+			return Decision{}, fmt.Errorf("%s: %w", s.Name(), err)
+		}
+		if !d.Allowed {
+			return d, nil
+		}
+	}
+	return Decision{Allowed: true}, nil
+}
+
+// FromEnv builds the screener of SANCTIONS_FILE, a JSON array of denied
+// addresses, and SCREENING_URL, a screening service. Neither set yields Nop.
+func FromEnv() (Screener, error) {
+	var screeners All
+	if path := os.Getenv("SANCTIONS_FILE"); path != "" {
+		list, err := LoadList(path)
+		if err != nil {
+			return nil, err
+		}
+		screeners = append(screeners, list)
+	}
+	if url := os.Getenv("SCREENING_URL"); url != "" {
+		screeners = append(screeners, NewWebhook(url, os.Getenv("SCREENING_API_KEY")))
+	}
+
+	switch len(screeners) {
+	case 0:
+		return Nop{}, nil
+	case 1:
+		return screeners[0], nil
+	default:
+		return screeners, nil
+	}
+}
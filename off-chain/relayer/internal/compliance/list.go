@@ -0,0 +1,43 @@
+package compliance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// List denies the addresses of a blocklist, on every chain. EVM addresses
+// match case-insensitively.
+type List struct {
+	denied map[string]struct{}
+}
+
+// LoadList reads a JSON array of addresses from path.
+func LoadList(path string) (*List, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading sanctions list: %w", err)
+	}
+
+	var addresses []string
+	if err := json.Unmarshal(raw, &addresses); err != nil {
+		return nil, fmt.Errorf("parsing sanctions list: %w", err)
+	}
+
+	list := &List{denied: make(map[string]struct{}, len(addresses))}
+	for _, addr := range addresses {
+		list.denied[strings.ToLower(strings.TrimSpace(addr))] = struct{}{}
+	}
+	return list, nil
+}
+
+func (l *List) Name() string { return "list" }
+
+func (l *List) Screen(_ context.Context, _ Stage, subject Subject) (Decision, error) {
+	if _, denied := l.denied[strings.ToLower(subject.Address)]; denied {
+		return Decision{Reason: "listed in SANCTIONS_FILE"}, nil
+	}
+	return Decision{Allowed: true}, nil
+}
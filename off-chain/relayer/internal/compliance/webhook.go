@@ -0,0 +1,63 @@
+package compliance
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Webhook asks an external screening service. It POSTs the stage and subject
+// as JSON and expects a Decision back.
+type Webhook struct {
+	url    string
+	apiKey string
+	client *http.Client
+}
+
+// NewWebhook returns a screener posting to url, with apiKey as a bearer
+// token if set.
+func NewWebhook(url, apiKey string) *Webhook {
+	return &Webhook{url: url, apiKey: apiKey, client: &http.Client{Timeout: ScreenTimeout}}
+}
+
+func (w *Webhook) Name() string { return "webhook" }
+
+func (w *Webhook) Screen(ctx context.Context, stage Stage, subject Subject) (Decision, error) {
+	payload, err := json.Marshal(struct {
+		Stage Stage `json:"stage"`
+		Subject
+	}{stage, subject})
+	if err != nil {
+		return Decision{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(payload))
+	if err != nil {
+		return Decision{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if w.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+w.apiKey)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return Decision{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return Decision{}, fmt.Errorf("screening service returned %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+
+	var d Decision
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&d); err != nil {
+		return Decision{}, fmt.Errorf("decoding screening decision: %w", err)
+	}
+	return d, nil
+}
@@ -0,0 +1,588 @@
+// Package config holds the relayer settings that can be changed at runtime
+// without a restart. Everything else stays in environment variables.
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"relayer/internal/common"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultInboundRate and DefaultInboundBurst limit how many messages per
+	// second a single WS connection may send before messages are rejected
+	DefaultInboundRate  = 5
+	DefaultInboundBurst = 10
+	// DefaultFinalityDelay is how long an escrow deployment must have been on
+	// chain before the fill may receive its secret
+	DefaultFinalityDelay = time.Second * 2
+	// DefaultQuoteTTL is how long a quote can be ordered against
+	DefaultQuoteTTL = time.Minute * 15
+	// DefaultFillIntentWindow is how long a FILL_INTENT reserves an order
+	// segment for the declaring resolver
+	DefaultFillIntentWindow = time.Second * 30
+	// DefaultHeadLagThreshold is how far a chain head may trail the wall
+	// clock before an alert is raised
+	DefaultHeadLagThreshold = time.Minute
+	// DefaultVerifyRate and DefaultVerifyBurst budget the chain calls a WS
+	// client may cause with TXHASH, CANCEL and WITHDRAWN events, in calls per
+	// second; a TXHASH costs two, the others one
+	DefaultVerifyRate  = 1
+	DefaultVerifyBurst = 10
+	// DefaultBanThreshold invalid events within DefaultBanWindow ban a WS
+	// client for DefaultBanDuration
+	DefaultBanThreshold = 5
+	DefaultBanWindow    = time.Minute
+	DefaultBanDuration  = time.Minute * 10
+	// DefaultMaxQuotes and DefaultMaxOrders cap the quotes and orders held in
+	// memory, the least recently used are evicted past them
+	DefaultMaxQuotes = 100_000
+	DefaultMaxOrders = 50_000
+	// DefaultVerifyFailures failed verifications within
+	// DefaultVerifyFailureWindow, and DefaultDisconnects resolver disconnects
+	// within DefaultDisconnectWindow, are notified; a rule notifies at most
+	// once per DefaultNotifyCooldown
+	DefaultVerifyFailures      = 10
+	DefaultVerifyFailureWindow = time.Minute * 5
+	DefaultDisconnects         = 10
+	DefaultDisconnectWindow    = time.Minute
+	DefaultNotifyCooldown      = time.Minute * 10
+	// DefaultFillGrace is how long after its auction end an order's verified
+	// fills may still receive their secret before the order is refunded
+	DefaultFillGrace = time.Minute * 30
+	// DefaultVerifyDrainWindow is how long a shutdown waits for the fill
+	// verifications in progress before persisting them for the next start
+	DefaultVerifyDrainWindow = time.Second * 30
+	// DefaultMaxOrderBytes and DefaultMaxSecretBytes bound the request bodies
+	// of order and secret submissions
+	DefaultMaxOrderBytes  = 64 << 10
+	DefaultMaxSecretBytes = 4 << 10
+	// DefaultMaxSecretSets is how many custodial secret sets the relayer
+	// holds at once before refusing to generate more
+	DefaultMaxSecretSets = 10000
+	// DefaultAuctionToleranceBps is how far below the auction curve a fill
+	// may settle, covering the gap between the block timestamp and the
+	// taker's quote
+	DefaultAuctionToleranceBps = 50
+	// DefaultRateAuctionEndBps is how far below its quoted amount the auction
+	// of a rate table quote ends
+	DefaultRateAuctionEndBps = 100
+)
+
+// Names of the verification checks whose amounts take a tolerance.
+const (
+	CheckAuctionAmount = "auction-amount"
+	CheckSafetyDeposit = "safety-deposit"
+)
+
+// ToleranceMode is how a verified amount is compared to the one owed.
+type ToleranceMode string
+
+const (
+	// ToleranceExact requires the owed amount
+	ToleranceExact ToleranceMode = "exact"
+	// ToleranceAtLeast accepts anything from belowBps under the owed amount
+	ToleranceAtLeast ToleranceMode = "atLeast"
+	// ToleranceBand accepts belowBps under to aboveBps over the owed amount
+	ToleranceBand ToleranceMode = "band"
+)
+
+// Tolerance is how far a verified amount may be from the one a fill owes.
+type Tolerance struct {
+	Mode     ToleranceMode `json:"mode"`
+	BelowBps uint64        `json:"belowBps,omitempty"`
+	AboveBps uint64        `json:"aboveBps,omitempty"`
+}
+
+// String describes the tolerance for verification reports, e.g.
+// "atLeast -50bps" or "band -10bps +100bps".
+func (t Tolerance) String() string {
+	switch t.Mode {
+	case ToleranceExact:
+		return string(t.Mode)
+	case ToleranceBand:
+		return fmt.Sprintf("%s -%dbps +%dbps", t.Mode, t.BelowBps, t.AboveBps)
+	default:
+		return fmt.Sprintf("%s -%dbps", t.Mode, t.BelowBps)
+	}
+}
+
+// Duration is a time.Duration read from JSON as a string such as "12s".
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a string like \"12s\": %w", err)
+	}
+
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// NotifyRules are the conditions the notification channels are sent,
+// see package notify.
+type NotifyRules struct {
+	// failed fill verifications within verifyFailureWindow making a spike,
+	// 0 disables the rule
+	VerifyFailures      int      `json:"verifyFailures"`
+	VerifyFailureWindow Duration `json:"verifyFailureWindow"`
+	// resolver disconnects within disconnectWindow making a storm, 0
+	// disables the rule
+	Disconnects      int      `json:"disconnects"`
+	DisconnectWindow Duration `json:"disconnectWindow"`
+	// chain endpoints failing or trailing their headLagThreshold
+	RPCOutage bool `json:"rpcOutage"`
+	// making amount by lowercase maker asset from which an order is large
+	LargeOrders map[string]string `json:"largeOrders"`
+	// least time between two notifications of one rule and subject
+	Cooldown Duration `json:"cooldown"`
+}
+
+// SafetyDeposit computes a chain's safety deposits at quote time from its
+// gas price, in place of those the 1inch API or the dev presets quote.
+type SafetyDeposit struct {
+	// gas of the withdrawal or cancellation a deposit pays a taker to send
+	GasUnits uint64 `json:"gasUnits"`
+	// share of that gas's cost at the current price, in bps, 10000 covering
+	// it exactly
+	MultiplierBps uint64 `json:"multiplierBps"`
+	// least deposit, in the chain's native base units
+	Floor string `json:"floor,omitempty"`
+}
+
+// RateTable quotes a token pair offline, without the 1inch API. Amounts are
+// converted with Rate, the destination tokens one source token is worth,
+// then lowered by SpreadBps. Orders take at least the quoted amount less
+// AuctionEndBps, the auction starting AuctionStartBps above it.
+type RateTable struct {
+	SrcChain string `json:"srcChain"`
+	SrcToken string `json:"srcToken"`
+	DstChain string `json:"dstChain"`
+	DstToken string `json:"dstToken"`
+	// decimals of the tokens, to convert Rate to base units
+	SrcDecimals uint8 `json:"srcDecimals"`
+	DstDecimals uint8 `json:"dstDecimals"`
+	// decimal number of destination tokens per source token, e.g. "3412.5"
+	Rate string `json:"rate"`
+	// bps of the converted amount kept as the relayer's margin
+	SpreadBps       uint64 `json:"spreadBps"`
+	AuctionStartBps uint64 `json:"auctionStartBps"`
+	AuctionEndBps   uint64 `json:"auctionEndBps"`
+	// in each chain's native base units; safetyDeposits of the chain, if
+	// set, take precedence
+	SrcSafetyDeposit string `json:"srcSafetyDeposit"`
+	DstSafetyDeposit string `json:"dstSafetyDeposit"`
+}
+
+// Config is the reloadable part of the relayer configuration.
+type Config struct {
+	LogLevel string `json:"logLevel"`
+	// per WS connection inbound message limit, in messages per second
+	InboundRate  float64 `json:"inboundRate"`
+	InboundBurst int     `json:"inboundBurst"`
+	// share of API requests, 0 to 1, whose redacted bodies are logged
+	BodySampleRate float64 `json:"bodySampleRate"`
+	// confirmation wait per chain id before a fill's escrow counts as final
+	FinalityDelays map[string]Duration `json:"finalityDelays"`
+	// quote validity per preset name (fast, medium, slow, custom)
+	QuoteTTLs map[string]Duration `json:"quoteTTLs"`
+	// exclusivity granted to a resolver declaring a FILL_INTENT
+	FillIntentWindow Duration `json:"fillIntentWindow"`
+	// dev-inspect the withdrawal of a fill's Sui escrows before its secret is released
+	SuiDryRun bool `json:"suiDryRun"`
+	// head lag per chain id above which the endpoint is reported as behind
+	HeadLagThresholds map[string]Duration `json:"headLagThresholds"`
+	// announce orders dropped from memory while still pending with EXPIRED
+	ExpiryNotifications bool `json:"expiryNotifications"`
+	// per WS client budget of chain calls caused by its events, in calls per
+	// second, shared by the client's connections
+	VerifyRate  float64 `json:"verifyRate"`
+	VerifyBurst int     `json:"verifyBurst"`
+	// share in bps of a fill's making amount its src escrow may hold less of,
+	// for fee-on-transfer tokens, by lowercase token address or Sui coin type,
+	// srcBalanceToleranceBps for other tokens
+	SrcBalanceToleranceBps uint64            `json:"srcBalanceToleranceBps"`
+	SrcBalanceTolerances   map[string]uint64 `json:"srcBalanceTolerances"`
+	// invalid chain events within banWindow after which a WS client is banned
+	// for banDuration, 0 disables bans
+	BanThreshold int      `json:"banThreshold"`
+	BanWindow    Duration `json:"banWindow"`
+	BanDuration  Duration `json:"banDuration"`
+	// quotes and orders held in memory past which the least recently used are
+	// evicted, 0 for no limit
+	MaxQuotes int `json:"maxQuotes"`
+	MaxOrders int `json:"maxOrders"`
+	// when to send notifications to Slack, Telegram and email
+	Notify NotifyRules `json:"notify"`
+	// time after an order's auction end from which verified fills whose
+	// secret was not revealed are given up and the order is refunded
+	FillGrace Duration `json:"fillGrace"`
+	// time a shutdown waits for fill verifications in progress; those still
+	// running are resumed at the next start, 0 persists them right away
+	VerifyDrainWindow Duration `json:"verifyDrainWindow"`
+	// reject order, secret and quote payloads with fields their structs do
+	// not have, rather than ignoring them
+	StrictDecoding bool `json:"strictDecoding"`
+	// largest request bodies of order and secret submissions, in bytes
+	MaxOrderBytes  int64 `json:"maxOrderBytes"`
+	MaxSecretBytes int64 `json:"maxSecretBytes"`
+	// custodial secret sets held at once, bound to an order or not, past
+	// which POST /relayer/v1.0/secrets is refused
+	MaxSecretSets int `json:"maxSecretSets"`
+	// bps by which the price an order implies may deviate from the oracle
+	// price of its quote before the order is quarantined for an admin's
+	// approval, 0 disables the check
+	PriceDeviationBps uint64 `json:"priceDeviationBps"`
+	// safety deposit parameters per chain id, chains without use the quoted
+	// deposits
+	SafetyDeposits map[string]SafetyDeposit `json:"safetyDeposits"`
+	// tolerance of the amount checks of fill verification by check name,
+	// auction-amount and safety-deposit
+	VerifyTolerances map[string]Tolerance `json:"verifyTolerances"`
+	// token pairs quoted offline from a rate; with offlineQuotes the 1inch
+	// API is never asked and other pairs are not quoted
+	RateTables    []RateTable `json:"rateTables"`
+	OfflineQuotes bool        `json:"offlineQuotes"`
+}
+
+// FinalityDelay returns the confirmation wait for chainID.
+func (c *Config) FinalityDelay(chainID string) time.Duration {
+	if d, ok := c.FinalityDelays[chainID]; ok {
+		return time.Duration(d)
+	}
+	return DefaultFinalityDelay
+}
+
+// HeadLagThreshold returns how far chainID's head may trail the wall clock.
+func (c *Config) HeadLagThreshold(chainID string) time.Duration {
+	if d, ok := c.HeadLagThresholds[chainID]; ok {
+		return time.Duration(d)
+	}
+	return DefaultHeadLagThreshold
+}
+
+// SrcBalanceTolerance returns the share in bps of a fill's making amount a
+// src escrow of token may hold less of.
+func (c *Config) SrcBalanceTolerance(token string) uint64 {
+	if bps, ok := c.SrcBalanceTolerances[strings.ToLower(token)]; ok {
+		return bps
+	}
+	return c.SrcBalanceToleranceBps
+}
+
+// VerifyTolerance returns the tolerance of the verification check named
+// check: by default fills may overpay, and settle DefaultAuctionToleranceBps
+// below the auction curve.
+func (c *Config) VerifyTolerance(check string) Tolerance {
+	if t, ok := c.VerifyTolerances[check]; ok {
+		return t
+	}
+	if check == CheckAuctionAmount {
+		return Tolerance{Mode: ToleranceAtLeast, BelowBps: DefaultAuctionToleranceBps}
+	}
+	return Tolerance{Mode: ToleranceAtLeast}
+}
+
+// RateTable returns the rate table of a token pair, chains given as decimal
+// ids.
+func (c *Config) RateTable(srcChain, srcToken, dstChain, dstToken string) (RateTable, bool) {
+	for _, t := range c.RateTables {
+		if t.SrcChain == srcChain && t.DstChain == dstChain &&
+			strings.EqualFold(t.SrcToken, srcToken) && strings.EqualFold(t.DstToken, dstToken) {
+			return t, true
+		}
+	}
+	return RateTable{}, false
+}
+
+// QuoteTTL returns how long a quote recommending preset stays valid.
+func (c *Config) QuoteTTL(preset string) time.Duration {
+	if d, ok := c.QuoteTTLs[preset]; ok {
+		return time.Duration(d)
+	}
+	return DefaultQuoteTTL
+}
+
+func defaults() Config {
+	return Config{
+		LogLevel:     os.Getenv("LOG_LEVEL"),
+		InboundRate:  DefaultInboundRate,
+		InboundBurst: DefaultInboundBurst,
+
+		FillIntentWindow: Duration(DefaultFillIntentWindow),
+
+		VerifyRate:   DefaultVerifyRate,
+		VerifyBurst:  DefaultVerifyBurst,
+		BanThreshold: DefaultBanThreshold,
+		BanWindow:    Duration(DefaultBanWindow),
+		BanDuration:  Duration(DefaultBanDuration),
+
+		MaxQuotes: DefaultMaxQuotes,
+		MaxOrders: DefaultMaxOrders,
+
+		FillGrace:         Duration(DefaultFillGrace),
+		VerifyDrainWindow: Duration(DefaultVerifyDrainWindow),
+
+		MaxOrderBytes:  DefaultMaxOrderBytes,
+		MaxSecretBytes: DefaultMaxSecretBytes,
+		MaxSecretSets:  DefaultMaxSecretSets,
+
+		Notify: NotifyRules{
+			VerifyFailures:      DefaultVerifyFailures,
+			VerifyFailureWindow: Duration(DefaultVerifyFailureWindow),
+			Disconnects:         DefaultDisconnects,
+			DisconnectWindow:    Duration(DefaultDisconnectWindow),
+			RPCOutage:           true,
+			Cooldown:            Duration(DefaultNotifyCooldown),
+		},
+	}
+}
+
+func (c *Config) validate() error {
+	if c.InboundRate <= 0 || c.InboundBurst <= 0 {
+		return fmt.Errorf("inboundRate and inboundBurst must be positive")
+	}
+	if c.BodySampleRate < 0 || c.BodySampleRate > 1 {
+		return fmt.Errorf("bodySampleRate must be between 0 and 1")
+	}
+	if c.FillIntentWindow <= 0 {
+		return fmt.Errorf("fillIntentWindow must be positive")
+	}
+	if c.VerifyRate <= 0 || c.VerifyBurst < 2 {
+		return fmt.Errorf("verifyRate must be positive and verifyBurst at least 2")
+	}
+	if c.SrcBalanceToleranceBps > 10_000 {
+		return fmt.Errorf("srcBalanceToleranceBps must be at most 10000")
+	}
+	tolerances := make(map[string]uint64, len(c.SrcBalanceTolerances))
+	for token, bps := range c.SrcBalanceTolerances {
+		if bps > 10_000 {
+			return fmt.Errorf("src balance tolerance of token %s must be at most 10000 bps", token)
+		}
+		tolerances[strings.ToLower(token)] = bps
+	}
+	c.SrcBalanceTolerances = tolerances
+	if c.BanThreshold < 0 {
+		return fmt.Errorf("banThreshold must not be negative")
+	}
+	if c.BanThreshold > 0 && (c.BanWindow <= 0 || c.BanDuration <= 0) {
+		return fmt.Errorf("banWindow and banDuration must be positive")
+	}
+	if c.MaxQuotes < 0 || c.MaxOrders < 0 {
+		return fmt.Errorf("maxQuotes and maxOrders must not be negative")
+	}
+	if c.FillGrace <= 0 {
+		return fmt.Errorf("fillGrace must be positive")
+	}
+	if c.VerifyDrainWindow < 0 {
+		return fmt.Errorf("verifyDrainWindow must not be negative")
+	}
+	if c.MaxOrderBytes <= 0 || c.MaxSecretBytes <= 0 {
+		return fmt.Errorf("maxOrderBytes and maxSecretBytes must be positive")
+	}
+	if c.MaxSecretSets <= 0 {
+		return fmt.Errorf("maxSecretSets must be positive")
+	}
+	if err := c.Notify.validate(); err != nil {
+		return fmt.Errorf("notify: %w", err)
+	}
+	for preset, d := range c.QuoteTTLs {
+		if d <= 0 {
+			return fmt.Errorf("quote ttl of preset %s must be positive", preset)
+		}
+	}
+	// keys may be CAIP-2 ids, lookups use decimal ids
+	delays := make(map[string]Duration, len(c.FinalityDelays))
+	for chainID, d := range c.FinalityDelays {
+		if d < 0 {
+			return fmt.Errorf("finality delay of chain %s is negative", chainID)
+		}
+		normalized, err := common.NormalizeChain(chainID)
+		if err != nil {
+			return fmt.Errorf("finalityDelays: %w", err)
+		}
+		delays[normalized] = d
+	}
+	c.FinalityDelays = delays
+
+	thresholds := make(map[string]Duration, len(c.HeadLagThresholds))
+	for chainID, d := range c.HeadLagThresholds {
+		if d <= 0 {
+			return fmt.Errorf("head lag threshold of chain %s must be positive", chainID)
+		}
+		normalized, err := common.NormalizeChain(chainID)
+		if err != nil {
+			return fmt.Errorf("headLagThresholds: %w", err)
+		}
+		thresholds[normalized] = d
+	}
+	c.HeadLagThresholds = thresholds
+
+	for check, t := range c.VerifyTolerances {
+		if check != CheckAuctionAmount && check != CheckSafetyDeposit {
+			return fmt.Errorf("verifyTolerances: %s takes no tolerance, only %s and %s do", check, CheckAuctionAmount, CheckSafetyDeposit)
+		}
+		switch t.Mode {
+		case ToleranceExact, ToleranceAtLeast, ToleranceBand:
+		default:
+			return fmt.Errorf("verifyTolerances: mode of %s must be exact, atLeast or band", check)
+		}
+		if t.BelowBps > 10_000 {
+			return fmt.Errorf("verifyTolerances: belowBps of %s must be at most 10000", check)
+		}
+	}
+
+	deposits := make(map[string]SafetyDeposit, len(c.SafetyDeposits))
+	for chainID, d := range c.SafetyDeposits {
+		normalized, err := common.NormalizeChain(chainID)
+		if err != nil {
+			return fmt.Errorf("safetyDeposits: %w", err)
+		}
+		if d.Floor != "" {
+			if _, err := common.ParseAmount(d.Floor); err != nil {
+				return fmt.Errorf("safety deposit floor of chain %s: %w", chainID, err)
+			}
+		}
+		if d.Floor == "" && (d.GasUnits == 0 || d.MultiplierBps == 0) {
+			return fmt.Errorf("safety deposit of chain %s needs gasUnits and multiplierBps, or a floor", chainID)
+		}
+		deposits[normalized] = d
+	}
+	c.SafetyDeposits = deposits
+
+	for i := range c.RateTables {
+		if err := c.RateTables[i].validate(); err != nil {
+			return fmt.Errorf("rateTables[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// validate checks a rate table, normalizing its chains to decimal ids and
+// defaulting AuctionEndBps.
+func (t *RateTable) validate() error {
+	for _, chain := range []*string{&t.SrcChain, &t.DstChain} {
+		normalized, err := common.NormalizeChain(*chain)
+		if err != nil {
+			return err
+		}
+		*chain = normalized
+	}
+	if t.SrcToken == "" || t.DstToken == "" {
+		return fmt.Errorf("srcToken and dstToken are required")
+	}
+	if rate, ok := new(big.Rat).SetString(t.Rate); !ok || rate.Sign() <= 0 {
+		return fmt.Errorf("rate must be a positive decimal number")
+	}
+	if t.SpreadBps >= 10_000 || t.AuctionEndBps >= 10_000 {
+		return fmt.Errorf("spreadBps and auctionEndBps must be below 10000")
+	}
+	if t.AuctionEndBps == 0 {
+		t.AuctionEndBps = DefaultRateAuctionEndBps
+	}
+	for _, deposit := range []string{t.SrcSafetyDeposit, t.DstSafetyDeposit} {
+		if _, err := common.ParseAmount(deposit); err != nil {
+			return fmt.Errorf("safety deposit %q: %w", deposit, err)
+		}
+	}
+	return nil
+}
+
+func (r *NotifyRules) validate() error {
+	if r.VerifyFailures < 0 || r.Disconnects < 0 {
+		return fmt.Errorf("verifyFailures and disconnects must not be negative")
+	}
+	if r.VerifyFailures > 0 && r.VerifyFailureWindow <= 0 {
+		return fmt.Errorf("verifyFailureWindow must be positive")
+	}
+	if r.Disconnects > 0 && r.DisconnectWindow <= 0 {
+		return fmt.Errorf("disconnectWindow must be positive")
+	}
+	if r.Cooldown < 0 {
+		return fmt.Errorf("cooldown must not be negative")
+	}
+
+	large := make(map[string]string, len(r.LargeOrders))
+	for asset, amount := range r.LargeOrders {
+		if v, err := common.ParseAmount(amount); err != nil || v.Sign() <= 0 {
+			return fmt.Errorf("large order amount of %s must be a positive integer", asset)
+		}
+		large[strings.ToLower(asset)] = amount
+	}
+	r.LargeOrders = large
+	return nil
+}
+
+// Store holds the current Config. Readers get an immutable snapshot, so a
+// reload never changes a value under a caller's feet.
+type Store struct {
+	path     string
+	finality map[string]Duration
+
+	mu      sync.RWMutex
+	current *Config
+}
+
+// NewStore loads the config file at path (CONFIG_FILE). An empty path keeps
+// the defaults and makes Reload a no-op beyond re-reading LOG_LEVEL.
+// finality holds the network profile's finality delays, which the file's
+// finalityDelays override per chain.
+func NewStore(path string, finality map[string]Duration) (*Store, error) {
+	s := &Store{path: path, finality: finality}
+	if _, err := s.Reload(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Current returns the active config snapshot.
+func (s *Store) Current() *Config {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.current
+}
+
+// Reload re-reads the config file and swaps it in. On error the previous
+// config stays active.
+func (s *Store) Reload() (*Config, error) {
+	cfg := defaults()
+	if s.path != "" {
+		file, err := os.ReadFile(s.path)
+		if err != nil {
+			return nil, fmt.Errorf("reading config file: %w", err)
+		}
+		if err := json.Unmarshal(file, &cfg); err != nil {
+			return nil, fmt.Errorf("decoding config file: %w", err)
+		}
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	for chainID, d := range s.finality {
+		if _, ok := cfg.FinalityDelays[chainID]; !ok {
+			cfg.FinalityDelays[chainID] = d
+		}
+	}
+
+	s.mu.Lock()
+	s.current = &cfg
+	s.mu.Unlock()
+
+	return &cfg, nil
+}
@@ -0,0 +1,417 @@
+// Package custody generates and keeps order secrets for custodial integrations
+// that let the relayer reveal them instead of the maker. Secrets are sealed
+// with AES-GCM under a key from the environment and only opened to reveal a
+// fill's secret or to export the set to its maker. With a Store the sealed
+// sets outlive restarts.
+package custody
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"relayer/internal/hashlock"
+	"strings"
+	"sync"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/google/uuid"
+	"github.com/imkira/go-ttlmap"
+)
+
+const (
+	// SetTTL is how long a secret set is kept, bound to an order or not.
+	SetTTL = 7 * 24 * time.Hour
+	// MaxSecrets bounds the secrets of a set, multiple fill orders having
+	// one per part plus one.
+	MaxSecrets = 256
+	// StoreTimeout bounds a write of a set to the Store.
+	StoreTimeout = 5 * time.Second
+)
+
+var (
+	ErrDisabled     = errors.New("secret custody is disabled")
+	ErrWrongKey     = errors.New("stored secret set was sealed under another key")
+	ErrStore        = errors.New("failed to store secret set")
+	ErrSetNotFound  = errors.New("secret set not found")
+	ErrBadToken     = errors.New("invalid export token")
+	ErrAlreadyBound = errors.New("secret set is already bound to an order")
+)
+
+// Policy decides when the relayer reveals a custodial secret.
+type Policy string
+
+const (
+	// ReleaseOnFinality reveals a fill's secret once its escrows are verified
+	// and final, as a maker frontend would.
+	ReleaseOnFinality Policy = "finality"
+	// ReleaseManual never reveals; the maker exports the secrets and reveals
+	// them itself.
+	ReleaseManual Policy = "manual"
+)
+
+// ParsePolicy returns the named policy; the empty name is ReleaseOnFinality.
+func ParsePolicy(name string) (Policy, error) {
+	switch Policy(name) {
+	case "", ReleaseOnFinality:
+		return ReleaseOnFinality, nil
+	case ReleaseManual:
+		return ReleaseManual, nil
+	default:
+		return "", fmt.Errorf("unknown release policy %q", name)
+	}
+}
+
+// Set is a generated group of secrets, one per order with a single fill, or
+// parts+1 for a multiple fill order.
+type Set struct {
+	ID           uuid.UUID
+	Algorithm    hashlock.Algorithm
+	Policy       Policy
+	Hashlock     ethcommon.Hash // the escrow hashlock committing to the secrets
+	SecretHashes []ethcommon.Hash
+	OrderHash    string // set once an order is submitted with the set
+	CreatedAt    time.Time
+
+	sealed      [][]byte
+	exportToken [32]byte // sha256 of the token handed to the maker
+}
+
+// MultiFill reports whether the set is for a multiple fill order.
+func (s *Set) MultiFill() bool {
+	return len(s.SecretHashes) > 1
+}
+
+// Record is a set as persisted, its secrets sealed.
+type Record struct {
+	Set
+	Sealed      [][]byte
+	ExportToken [32]byte
+}
+
+// Store persists the sets of a vault.
+type Store interface {
+	// SaveSet stores a set, or its order hash once bound.
+	SaveSet(ctx context.Context, rec Record) error
+	// LoadSets returns the sets created at or after since.
+	LoadSets(ctx context.Context, since time.Time) ([]Record, error)
+}
+
+// Vault keeps secret sets in memory, sealed, and in its Store if any.
+type Vault struct {
+	aead  cipher.AEAD
+	mu    sync.Mutex // guards Set.OrderHash
+	sets  *ttlmap.Map
+	store Store // nil keeps the sets in memory only, see UseStore
+}
+
+// NewVault builds a vault sealing with a 32 byte AES-256 key.
+func NewVault(key []byte) (*Vault, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("secret key must be 32 bytes, got %d", len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Vault{
+		aead: aead,
+		sets: ttlmap.New(&ttlmap.Options{InitialCapacity: 32}),
+	}, nil
+}
+
+// Load builds a vault from a hex encoded key. An empty key disables custody
+// and returns a nil vault.
+func Load(hexKey string) (*Vault, error) {
+	if hexKey == "" {
+		return nil, nil
+	}
+
+	key, err := hex.DecodeString(strings.TrimPrefix(hexKey, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("decoding secret key: %w", err)
+	}
+	return NewVault(key)
+}
+
+// FromEnv builds a vault from the hex encoded key in SECRETS_KEY, or in the
+// file at SECRETS_KEY_FILE as written by a KMS or secret manager agent.
+// Neither disables custody and returns a nil vault.
+func FromEnv() (*Vault, error) {
+	hexKey := os.Getenv("SECRETS_KEY")
+	if path := os.Getenv("SECRETS_KEY_FILE"); path != "" {
+		if hexKey != "" {
+			return nil, errors.New("set SECRETS_KEY or SECRETS_KEY_FILE, not both")
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading SECRETS_KEY_FILE: %w", err)
+		}
+		hexKey = strings.TrimSpace(string(data))
+	}
+	return Load(hexKey)
+}
+
+// UseStore persists the sets generated or bound from now on in st. It is set
+// before the vault is used, see Restore for the sets already stored.
+func (v *Vault) UseStore(st Store) {
+	v.store = st
+}
+
+// Restore loads the sets of the Store created within SetTTL, e.g. after a
+// restart or when taking over from another replica, and returns how many.
+// Sets sealed under another key fail with ErrWrongKey: they could never be
+// revealed, and their makers must know.
+func (v *Vault) Restore(ctx context.Context) (int, error) {
+	if !v.Enabled() || v.store == nil {
+		return 0, nil
+	}
+
+	recs, err := v.store.LoadSets(ctx, time.Now().Add(-SetTTL))
+	if err != nil {
+		return 0, err
+	}
+	for _, rec := range recs {
+		if len(rec.Sealed) == 0 || len(rec.Sealed) != len(rec.SecretHashes) {
+			return 0, fmt.Errorf("stored secret set %s has %d sealed secrets for %d hashes", rec.ID, len(rec.Sealed), len(rec.SecretHashes))
+		}
+		if _, err := v.open(rec.ID, 0, rec.Sealed[0]); err != nil {
+			return 0, fmt.Errorf("%w: %s", ErrWrongKey, rec.ID)
+		}
+
+		set := rec.Set
+		set.sealed = rec.Sealed
+		set.exportToken = rec.ExportToken
+		if err := v.sets.Set(set.ID.String(), ttlmap.NewItem(&set, ttlmap.WithExpiration(set.CreatedAt.Add(SetTTL))), nil); err != nil {
+			return 0, err
+		}
+	}
+	return len(recs), nil
+}
+
+// save persists a copy of a set, if the vault has a Store.
+func (v *Vault) save(set *Set) error {
+	if v.store == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), StoreTimeout)
+	defer cancel()
+
+	rec := Record{Set: *set, Sealed: set.sealed, ExportToken: set.exportToken}
+	if err := v.store.SaveSet(ctx, rec); err != nil {
+		return fmt.Errorf("%w: %v", ErrStore, err)
+	}
+	return nil
+}
+
+// Len returns the number of sets held.
+func (v *Vault) Len() int {
+	if !v.Enabled() {
+		return 0
+	}
+	return v.sets.Len()
+}
+
+// Enabled reports whether the relayer offers secret custody.
+func (v *Vault) Enabled() bool {
+	return v != nil
+}
+
+// Generate creates count random 32 byte secrets hashed with algo and returns
+// the set along with the export token the maker needs to retrieve them.
+func (v *Vault) Generate(count int, algo hashlock.Algorithm, policy Policy) (*Set, string, error) {
+	if !v.Enabled() {
+		return nil, "", ErrDisabled
+	}
+	if count != 1 && (count < 3 || count > MaxSecrets) {
+		return nil, "", fmt.Errorf("need 1 secret, or 3 to %d for multiple fills, got %d", MaxSecrets, count)
+	}
+
+	set := &Set{
+		ID:           uuid.New(),
+		Algorithm:    algo,
+		Policy:       policy,
+		SecretHashes: make([]ethcommon.Hash, count),
+		CreatedAt:    time.Now(),
+		sealed:       make([][]byte, count),
+	}
+
+	for i := range count {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, "", err
+		}
+		set.SecretHashes[i] = algo.Hash(secret)
+
+		sealed, err := v.seal(set.ID, i, secret)
+		if err != nil {
+			return nil, "", err
+		}
+		set.sealed[i] = sealed
+	}
+
+	if set.MultiFill() {
+		set.Hashlock = hashlock.MultiFill(set.SecretHashes)
+	} else {
+		set.Hashlock = set.SecretHashes[0]
+	}
+
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return nil, "", err
+	}
+	set.exportToken = sha256.Sum256(token)
+
+	// a set the relayer could lose is not handed out
+	if err := v.save(set); err != nil {
+		return nil, "", err
+	}
+	if err := v.sets.Set(set.ID.String(), ttlmap.NewItem(set, ttlmap.WithTTL(SetTTL)), nil); err != nil {
+		return nil, "", err
+	}
+
+	return set, hexutil.Encode(token), nil
+}
+
+// Get returns a copy of a stored set.
+func (v *Vault) Get(id string) (*Set, error) {
+	set, err := v.get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	cp := *set
+	return &cp, nil
+}
+
+func (v *Vault) get(id string) (*Set, error) {
+	if !v.Enabled() {
+		return nil, ErrDisabled
+	}
+
+	item, err := v.sets.Get(strings.ToLower(id))
+	if err != nil {
+		return nil, ErrSetNotFound
+	}
+	return item.Value().(*Set), nil
+}
+
+// Bind ties a set to the order submitted with it, after checking the order
+// commits to the set: its secret hashes for multiple fills, or its escrow
+// hashlock when known (zero for orders whose hashlock is only on chain).
+func (v *Vault) Bind(id, orderHash string, secretHashes []string, escrowHashlock ethcommon.Hash) (*Set, error) {
+	set, err := v.get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if set.MultiFill() {
+		if len(secretHashes) != len(set.SecretHashes) {
+			return nil, fmt.Errorf("order has %d secret hashes, secret set has %d", len(secretHashes), len(set.SecretHashes))
+		}
+		for i, h := range secretHashes {
+			if ethcommon.HexToHash(h) != set.SecretHashes[i] {
+				return nil, fmt.Errorf("secret hash %d does not match the secret set", i)
+			}
+		}
+	} else if len(secretHashes) > 0 {
+		return nil, errors.New("secret set is for a single fill order")
+	}
+	if escrowHashlock != (ethcommon.Hash{}) && escrowHashlock != set.Hashlock {
+		return nil, errors.New("order hashlock does not match the secret set")
+	}
+
+	v.mu.Lock()
+	if set.OrderHash != "" && !strings.EqualFold(set.OrderHash, orderHash) {
+		v.mu.Unlock()
+		return nil, ErrAlreadyBound
+	}
+	set.OrderHash = orderHash
+	cp := *set
+	v.mu.Unlock()
+
+	if err := v.save(&cp); err != nil {
+		return nil, err
+	}
+	return &cp, nil
+}
+
+// Secret opens the secret at idx of a set, hex encoded.
+func (v *Vault) Secret(id string, idx int) (string, error) {
+	set, err := v.Get(id)
+	if err != nil {
+		return "", err
+	}
+	if idx < 0 || idx >= len(set.sealed) {
+		return "", fmt.Errorf("secret index %d out of range", idx)
+	}
+
+	secret, err := v.open(set.ID, idx, set.sealed[idx])
+	if err != nil {
+		return "", err
+	}
+	return hexutil.Encode(secret), nil
+}
+
+// Export opens every secret of a set for the holder of its export token.
+func (v *Vault) Export(id, token string) (*Set, []string, error) {
+	set, err := v.Get(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	raw, err := hexutil.Decode(token)
+	if err != nil {
+		return nil, nil, ErrBadToken
+	}
+	if sum := sha256.Sum256(raw); subtle.ConstantTimeCompare(sum[:], set.exportToken[:]) != 1 {
+		return nil, nil, ErrBadToken
+	}
+
+	secrets := make([]string, len(set.sealed))
+	for i := range set.sealed {
+		if secrets[i], err = v.Secret(id, i); err != nil {
+			return nil, nil, err
+		}
+	}
+	return set, secrets, nil
+}
+
+// seal encrypts a secret, binding it to its set and index as additional data.
+func (v *Vault) seal(id uuid.UUID, idx int, secret []byte) ([]byte, error) {
+	nonce := make([]byte, v.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return v.aead.Seal(nonce, nonce, secret, additionalData(id, idx)), nil
+}
+
+func (v *Vault) open(id uuid.UUID, idx int, sealed []byte) ([]byte, error) {
+	n := v.aead.NonceSize()
+	if len(sealed) < n {
+		return nil, errors.New("sealed secret is truncated")
+	}
+	return v.aead.Open(nil, sealed[:n], sealed[n:], additionalData(id, idx))
+}
+
+func additionalData(id uuid.UUID, idx int) []byte {
+	return []byte(fmt.Sprintf("%s/%d", id, idx))
+}
@@ -0,0 +1,170 @@
+package custody
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"relayer/internal/hashlock"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/google/uuid"
+)
+
+func newVault(t *testing.T, key byte) *Vault {
+	t.Helper()
+	v, err := NewVault(bytes.Repeat([]byte{key}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		count int
+		algo  hashlock.Algorithm
+	}{
+		{name: "single fill keccak256", count: 1, algo: hashlock.Keccak256},
+		{name: "single fill sha256", count: 1, algo: hashlock.SHA256},
+		{name: "multiple fills", count: 5, algo: hashlock.Keccak256},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := newVault(t, 1)
+			set, token, err := v.Generate(tt.count, tt.algo, ReleaseOnFinality)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, exported, err := v.Export(set.ID.String(), token)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(exported) != tt.count {
+				t.Fatalf("exported %d secrets, want %d", len(exported), tt.count)
+			}
+			for i, secret := range exported {
+				if got := tt.algo.Hash(hexutil.MustDecode(secret)); got != set.SecretHashes[i] {
+					t.Fatalf("secret %d hashes to %s, set has %s", i, got, set.SecretHashes[i])
+				}
+				revealed, err := v.Secret(set.ID.String(), i)
+				if err != nil || revealed != secret {
+					t.Fatalf("secret %d: revealed %s, %v, exported %s", i, revealed, err, secret)
+				}
+			}
+		})
+	}
+}
+
+func TestTamperedAdditionalData(t *testing.T) {
+	v := newVault(t, 1)
+	set, _, err := v.Generate(3, hashlock.Keccak256, ReleaseOnFinality)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed := set.sealed[0]
+	if _, err := v.open(set.ID, 0, sealed); err != nil {
+		t.Fatal(err)
+	}
+
+	flipped := bytes.Clone(sealed)
+	flipped[len(flipped)-1] ^= 1
+	tests := []struct {
+		name   string
+		id     uuid.UUID
+		idx    int
+		sealed []byte
+	}{
+		{name: "other index", id: set.ID, idx: 1, sealed: sealed},
+		{name: "other set", id: uuid.New(), idx: 0, sealed: sealed},
+		{name: "flipped ciphertext", id: set.ID, idx: 0, sealed: flipped},
+		{name: "truncated", id: set.ID, idx: 0, sealed: sealed[:v.aead.NonceSize()-1]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if secret, err := v.open(tt.id, tt.idx, tt.sealed); err == nil {
+				t.Fatalf("opened %x", secret)
+			}
+		})
+	}
+
+	// secrets moved between indexes of a set no longer open
+	set.sealed[0], set.sealed[1] = set.sealed[1], set.sealed[0]
+	if _, err := v.Secret(set.ID.String(), 0); err == nil {
+		t.Fatal("revealed a secret sealed for another index")
+	}
+}
+
+func TestExportToken(t *testing.T) {
+	v := newVault(t, 1)
+	set, token, err := v.Generate(1, hashlock.Keccak256, ReleaseManual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, otherToken, err := v.Generate(1, hashlock.Keccak256, ReleaseManual)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "token of another set", token: otherToken},
+		{name: "empty", token: ""},
+		{name: "not hex", token: "export-token"},
+		{name: "truncated", token: token[:len(token)-2]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, secrets, err := v.Export(set.ID.String(), tt.token); !errors.Is(err, ErrBadToken) {
+				t.Fatalf("got %v, %v, want %v", secrets, err, ErrBadToken)
+			}
+		})
+	}
+	if _, _, err := v.Export(other.ID.String(), otherToken); err != nil {
+		t.Fatalf("own token: %v", err)
+	}
+}
+
+// memStore keeps records in memory.
+type memStore struct {
+	recs []Record
+}
+
+func (s *memStore) SaveSet(_ context.Context, rec Record) error {
+	s.recs = append(s.recs, rec)
+	return nil
+}
+
+func (s *memStore) LoadSets(context.Context, time.Time) ([]Record, error) {
+	return s.recs, nil
+}
+
+func TestRestoreWrongKey(t *testing.T) {
+	st := &memStore{}
+	v := newVault(t, 1)
+	v.UseStore(st)
+	set, token, err := v.Generate(1, hashlock.Keccak256, ReleaseOnFinality)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	same := newVault(t, 1)
+	same.UseStore(st)
+	if n, err := same.Restore(context.Background()); err != nil || n != 1 {
+		t.Fatalf("Restore = %d, %v", n, err)
+	}
+	if _, _, err := same.Export(set.ID.String(), token); err != nil {
+		t.Fatalf("export after restore: %v", err)
+	}
+
+	other := newVault(t, 2)
+	other.UseStore(st)
+	if _, err := other.Restore(context.Background()); !errors.Is(err, ErrWrongKey) {
+		t.Fatalf("got %v, want %v", err, ErrWrongKey)
+	}
+}
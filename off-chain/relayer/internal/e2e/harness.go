@@ -0,0 +1,289 @@
+//go:build e2e
+
+// Package e2e runs the relayer against local chains (anvil and a Sui localnet)
+// together with the TypeScript resolver, and drives a full swap through it.
+// It is compiled only with the e2e build tag: go test -tags e2e ./internal/e2e
+package e2e
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"relayer/pkg/client"
+)
+
+// Config holds the locations and ports used by the harness. Zero values are
+// filled in from the environment by ConfigFromEnv.
+type Config struct {
+	RepoRoot      string // root of the fission repository
+	ForkURL       string // mainnet RPC anvil forks from (LOP and escrow factory live there)
+	AnvilPort     int
+	SuiRPCPort    int
+	SuiFaucetPort int
+	APIPort       int
+	WSPort        int
+	DeployerKey   string // funded anvil private key used for forge deployments
+	ResolverID    string
+	StartupWait   time.Duration
+	OrderFixture  string // JSON order produced by the TS SDK, see swap.go
+	SecretFixture string // secret matching the fixture's hashlock
+}
+
+// ConfigFromEnv builds a Config from E2E_* environment variables.
+func ConfigFromEnv() Config {
+	root := os.Getenv("E2E_REPO_ROOT")
+	if root == "" {
+		// tests run in internal/e2e of off-chain/relayer
+		root, _ = filepath.Abs(filepath.Join("..", "..", "..", ".."))
+	}
+
+	return Config{
+		RepoRoot:      root,
+		ForkURL:       os.Getenv("E2E_FORK_URL"),
+		AnvilPort:     8545,
+		SuiRPCPort:    9000,
+		SuiFaucetPort: 9123,
+		APIPort:       18080,
+		WSPort:        18081,
+		DeployerKey:   envOr("E2E_DEPLOYER_KEY", "0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"),
+		ResolverID:    envOr("E2E_RESOLVER_ID", "1"),
+		StartupWait:   2 * time.Minute,
+		OrderFixture:  os.Getenv("E2E_ORDER_FIXTURE"),
+		SecretFixture: os.Getenv("E2E_SECRET"),
+	}
+}
+
+// Harness owns every process started for a run.
+type Harness struct {
+	cfg    Config
+	logger *log.Logger
+	procs  []*exec.Cmd
+
+	EVMRPC       string
+	SuiRPC       string
+	EVMResolver  string
+	SuiPackageID string
+	API          *client.Client
+	WSURL        string
+}
+
+// Start brings up anvil, the Sui localnet, deploys the contracts and starts the
+// relayer and a resolver. Close must be called even when Start fails.
+func Start(ctx context.Context, cfg Config, logger *log.Logger) (*Harness, error) {
+	h := &Harness{
+		cfg:    cfg,
+		logger: logger,
+		EVMRPC: fmt.Sprintf("http://127.0.0.1:%d", cfg.AnvilPort),
+		SuiRPC: fmt.Sprintf("http://127.0.0.1:%d", cfg.SuiRPCPort),
+		WSURL:  fmt.Sprintf("ws://127.0.0.1:%d/", cfg.WSPort),
+	}
+
+	if cfg.ForkURL == "" {
+		return h, fmt.Errorf("E2E_FORK_URL is required, anvil forks mainnet for the LOP and escrow factory")
+	}
+
+	// 1. chains
+	anvilArgs := []string{"--port", fmt.Sprint(cfg.AnvilPort), "--fork-url", cfg.ForkURL}
+	if err := h.spawn(ctx, "anvil", "", nil, "anvil", anvilArgs...); err != nil {
+		return h, err
+	}
+	if err := h.spawn(ctx, "sui", "", nil, "sui", "start", "--with-faucet", "--force-regenesis"); err != nil {
+		return h, err
+	}
+	if err := waitForPort(ctx, cfg.AnvilPort, cfg.StartupWait); err != nil {
+		return h, fmt.Errorf("anvil: %w", err)
+	}
+	if err := waitForPort(ctx, cfg.SuiRPCPort, cfg.StartupWait); err != nil {
+		return h, fmt.Errorf("sui localnet: %w", err)
+	}
+
+	// 2. contracts
+	if err := h.deployEVM(ctx); err != nil {
+		return h, err
+	}
+	if err := h.deployMove(ctx); err != nil {
+		return h, err
+	}
+
+	// 3. relayer
+	relayerEnv := []string{
+		"API_MODE=DEV",
+		fmt.Sprintf("API_PORT=%d", cfg.APIPort),
+		fmt.Sprintf("WS_PORT=%d", cfg.WSPort),
+		"EVM_RPC_URL=" + h.EVMRPC,
+		"SUI_RPC_URL=" + h.SuiRPC,
+		"LOG_LEVEL=debug",
+	}
+	relayerDir := filepath.Join(cfg.RepoRoot, "off-chain", "relayer")
+	if err := h.spawn(ctx, "relayer", relayerDir, relayerEnv, "go", "run", "./cmd"); err != nil {
+		return h, err
+	}
+	if err := waitForPort(ctx, cfg.APIPort, cfg.StartupWait); err != nil {
+		return h, fmt.Errorf("relayer api: %w", err)
+	}
+	h.API = client.New(fmt.Sprintf("http://127.0.0.1:%d", cfg.APIPort), nil)
+
+	// 4. resolver
+	resolverEnv := []string{
+		"RELAYER_WS_URL=" + h.WSURL,
+		"RESOLVER_ID=" + cfg.ResolverID,
+		"EVM_RPC_URL=" + h.EVMRPC,
+		"SUI_RPC_URL=" + h.SuiRPC,
+		"EVM_PRIVATE_KEY=" + cfg.DeployerKey,
+		"EVM_RESOLVER_CONTRACT=" + h.EVMResolver,
+		"SUI_RESOLVER_PACKAGE=" + h.SuiPackageID,
+		"SUI_ESCROW_FACTORY=" + h.SuiPackageID,
+	}
+	resolverDir := filepath.Join(cfg.RepoRoot, "off-chain", "resolver")
+	if err := h.spawn(ctx, "resolver", resolverDir, resolverEnv, "npm", "run", "dev"); err != nil {
+		return h, err
+	}
+
+	return h, nil
+}
+
+// Close stops every process started by the harness, newest first.
+func (h *Harness) Close() {
+	for i := len(h.procs) - 1; i >= 0; i-- {
+		p := h.procs[i]
+		if p.Process == nil {
+			continue
+		}
+		_ = p.Process.Signal(os.Interrupt)
+
+		done := make(chan struct{})
+		go func() {
+			_ = p.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(10 * time.Second):
+			_ = p.Process.Kill()
+		}
+	}
+}
+
+var deployedAt = regexp.MustCompile(`Resolver deployed at:\s*(0x[0-9a-fA-F]{40})`)
+
+func (h *Harness) deployEVM(ctx context.Context) error {
+	dir := filepath.Join(h.cfg.RepoRoot, "contracts", "evm", "resolver")
+	out, err := h.run(ctx, dir, []string{"PRIVATE_KEY=" + h.cfg.DeployerKey},
+		"forge", "script", "script/DeployResolver.s.sol:DeployResolver", "--rpc-url", h.EVMRPC, "--broadcast")
+	if err != nil {
+		return fmt.Errorf("deploying evm resolver: %w", err)
+	}
+
+	m := deployedAt.FindStringSubmatch(out)
+	if m == nil {
+		return fmt.Errorf("evm resolver address not found in forge output")
+	}
+	h.EVMResolver = m[1]
+	h.logger.Printf("evm resolver deployed at %s", h.EVMResolver)
+
+	return nil
+}
+
+var packageID = regexp.MustCompile(`PackageID:\s*(0x[0-9a-fA-F]+)`)
+
+func (h *Harness) deployMove(ctx context.Context) error {
+	if _, err := h.run(ctx, "", nil, "sui", "client", "faucet", "--url", fmt.Sprintf("http://127.0.0.1:%d/gas", h.cfg.SuiFaucetPort)); err != nil {
+		return fmt.Errorf("funding sui deployer: %w", err)
+	}
+
+	dir := filepath.Join(h.cfg.RepoRoot, "contracts", "move", "fusion_plus")
+	out, err := h.run(ctx, dir, nil, "sui", "client", "publish", "--gas-budget", "500000000", "--skip-dependency-verification")
+	if err != nil {
+		return fmt.Errorf("publishing fusion_plus: %w", err)
+	}
+
+	m := packageID.FindStringSubmatch(out)
+	if m == nil {
+		return fmt.Errorf("package id not found in sui publish output")
+	}
+	h.SuiPackageID = m[1]
+	h.logger.Printf("fusion_plus published at %s", h.SuiPackageID)
+
+	return nil
+}
+
+// spawn starts a long running process and streams its output with a prefix.
+func (h *Harness) spawn(ctx context.Context, name, dir string, env []string, bin string, args ...string) error {
+	cmd := exec.CommandContext(ctx, bin, args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), env...)
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	cmd.Stderr = cmd.Stdout
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("starting %s: %w", name, err)
+	}
+	h.procs = append(h.procs, cmd)
+	go h.pipe(name, stdout)
+
+	return nil
+}
+
+// run executes a command to completion and returns its combined output.
+func (h *Harness) run(ctx context.Context, dir string, env []string, bin string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, bin, args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), env...)
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return string(out), fmt.Errorf("%s %s: %w\n%s", bin, strings.Join(args, " "), err, out)
+	}
+
+	return string(out), nil
+}
+
+func (h *Harness) pipe(name string, r io.Reader) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		h.logger.Printf("[%s] %s", name, scanner.Text())
+	}
+}
+
+func waitForPort(ctx context.Context, port int, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	addr := fmt.Sprintf("127.0.0.1:%d", port)
+
+	for time.Now().Before(deadline) {
+		conn, err := net.DialTimeout("tcp", addr, time.Second)
+		if err == nil {
+			conn.Close()
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+
+	return fmt.Errorf("%s not reachable after %s", addr, timeout)
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+
+	return fallback
+}
@@ -0,0 +1,120 @@
+//go:build e2e
+
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"relayer/internal/common"
+	"relayer/internal/hash"
+	"relayer/pkg/client"
+)
+
+// SwapTimeout bounds every waiting step of RunSwap.
+const SwapTimeout = 5 * time.Minute
+
+// RunSwap drives a full swap against a started harness:
+//
+//  1. request a quote and submit the fixture order under its quote id
+//  2. wait for the resolver to deploy both escrows and the relayer to verify them
+//  3. submit the maker secret and wait for the SECRET broadcast
+//
+// The order fixture is a signed order as produced by the TS cross-chain SDK,
+// swapping into the Sui localnet.
+func RunSwap(ctx context.Context, h *Harness) error {
+	raw, err := os.ReadFile(h.cfg.OrderFixture)
+	if err != nil {
+		return fmt.Errorf("reading order fixture: %w", err)
+	}
+
+	var order client.Order
+	if err := json.Unmarshal(raw, &order); err != nil {
+		return fmt.Errorf("decoding order fixture: %w", err)
+	}
+	if !common.ChainID(order.SrcChainID).IsSupported() {
+		return fmt.Errorf("order fixture has an unsupported srcChainId")
+	}
+
+	quote, err := h.API.GetQuote(ctx, client.QuoteRequestParams{
+		SrcChain:        order.SrcChainID.String(),
+		DstChain:        common.Sui.String(),
+		SrcTokenAddress: order.LimitOrder.MakerAsset,
+		DstTokenAddress: order.LimitOrder.TakerAsset,
+		Amount:          order.LimitOrder.MakingAmount,
+		WalletAddress:   order.LimitOrder.Maker,
+	})
+	if err != nil {
+		return fmt.Errorf("requesting quote: %w", err)
+	}
+	order.QuoteID = quote.QuoteID
+
+	orderHash, err := hash.GetOrderHashForLimitOrder(common.ChainID(order.SrcChainID), common.LimitOrder(order.LimitOrder))
+	if err != nil {
+		return fmt.Errorf("hashing fixture order: %w", err)
+	}
+	h.logger.Printf("submitting order %s", orderHash.Hex())
+
+	// subscribe before submitting so the secret broadcast cannot be missed
+	released := make(chan string, 1)
+	stream := client.NewStream(h.WSURL, client.Handlers{
+		OnSecret: func(hash, secret string) {
+			if hash == orderHash.Hex() {
+				released <- secret
+			}
+		},
+	})
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go stream.Subscribe(streamCtx)
+
+	if err := h.API.SubmitOrder(ctx, order); err != nil {
+		return fmt.Errorf("submitting order: %w", err)
+	}
+
+	if err := waitForFills(ctx, h.API, orderHash.Hex()); err != nil {
+		return err
+	}
+
+	if err := h.API.SubmitSecret(ctx, orderHash.Hex(), h.cfg.SecretFixture); err != nil {
+		return fmt.Errorf("submitting secret: %w", err)
+	}
+
+	select {
+	case secret := <-released:
+		if secret != h.cfg.SecretFixture {
+			return fmt.Errorf("relayer broadcast secret %s, expected %s", secret, h.cfg.SecretFixture)
+		}
+	case <-time.After(SwapTimeout):
+		return fmt.Errorf("secret was not broadcast within %s", SwapTimeout)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	h.logger.Printf("swap %s completed", orderHash.Hex())
+	return nil
+}
+
+func waitForFills(ctx context.Context, api *client.Client, orderHash string) error {
+	deadline := time.Now().Add(SwapTimeout)
+	for time.Now().Before(deadline) {
+		fills, err := api.GetReadyToAcceptSecretFills(ctx, orderHash)
+		if err != nil {
+			return fmt.Errorf("polling ready fills: %w", err)
+		}
+		if len(fills.Fills) > 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(2 * time.Second):
+		}
+	}
+
+	return fmt.Errorf("no fill became ready within %s", SwapTimeout)
+}
@@ -0,0 +1,33 @@
+//go:build e2e
+
+package e2e
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"testing"
+)
+
+func TestSwap(t *testing.T) {
+	cfg := ConfigFromEnv()
+	if cfg.ForkURL == "" {
+		t.Skip("E2E_FORK_URL unset, anvil has no mainnet to fork")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	logger := log.New(os.Stdout, "e2e: ", log.LstdFlags)
+	h, err := Start(ctx, cfg, logger)
+	defer h.Close()
+	if err != nil {
+		t.Fatalf("starting harness: %v", err)
+	}
+
+	if err := RunSwap(ctx, h); err != nil {
+		t.Fatalf("e2e swap failed: %v", err)
+	}
+}
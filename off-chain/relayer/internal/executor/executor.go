@@ -0,0 +1,80 @@
+// Package executor sends transactions on the relayer's own behalf, so far
+// the public cancellation of src escrows left behind by orders nobody
+// completed. It signs with a key from the environment; without one the
+// relayer never sends transactions.
+package executor
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"relayer/internal/chain"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ErrDisabled is returned when the relayer runs without an executor key.
+var ErrDisabled = errors.New("executor disabled")
+
+// Executor signs and sends the relayer's transactions on EVM chains.
+type Executor struct {
+	key *ecdsa.PrivateKey
+
+	// transactions from one account are sent one at a time so their nonces
+	// are taken in order
+	mu sync.Mutex
+}
+
+// Load builds an executor from a hex encoded secp256k1 key. An empty key
+// disables the executor and returns a nil executor.
+func Load(hexKey string) (*Executor, error) {
+	if hexKey == "" {
+		return nil, nil
+	}
+
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(hexKey, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("decoding executor key: %w", err)
+	}
+	return &Executor{key: key}, nil
+}
+
+// Enabled reports whether the relayer sends transactions.
+func (e *Executor) Enabled() bool {
+	return e != nil
+}
+
+// Address is the account the executor sends from.
+func (e *Executor) Address() ethcommon.Address {
+	return crypto.PubkeyToAddress(e.key.PublicKey)
+}
+
+// CancelSrcEscrow publicly cancels the src escrow at escrow on chainID, sent
+// through client, refunding the maker, and returns the cancellation's
+// transaction hash. The executor's account must hold the escrow factory's
+// access token.
+func (e *Executor) CancelSrcEscrow(ctx context.Context, client chain.EVMClient, chainID *big.Int, escrow ethcommon.Address, immutables chain.Immutables) (ethcommon.Hash, error) {
+	if !e.Enabled() {
+		return ethcommon.Hash{}, ErrDisabled
+	}
+
+	opts, err := bind.NewKeyedTransactorWithChainID(e.key, chainID)
+	if err != nil {
+		return ethcommon.Hash{}, err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	tx, err := chain.PublicCancelEvmSrcEscrow(ctx, client, opts, escrow, immutables)
+	if err != nil {
+		return ethcommon.Hash{}, err
+	}
+	return tx.Hash(), nil
+}
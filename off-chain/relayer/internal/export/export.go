@@ -0,0 +1,291 @@
+// Package export keeps daily snapshots of the relayer's activity in object
+// storage, for retention beyond the in-memory state and offline analysis.
+// Events of the bus, among them submitted orders, verified fills and closed
+// escrows, are spooled to a local JSONL file per UTC day. Once a day is over
+// its events, and the stored orders that changed during it, are uploaded as
+// <prefix>/dt=YYYY-MM-DD/events.jsonl and orders.jsonl.
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"relayer/internal/bus"
+	"relayer/internal/store"
+	"sort"
+	"strings"
+	"time"
+)
+
+// UploadTimeout bounds the upload of one object.
+const UploadTimeout = time.Minute * 5
+
+// UploadInterval is how often finished days are looked for and uploaded.
+const UploadInterval = time.Hour
+
+// DefaultSpoolDir holds the events of days not uploaded yet.
+const DefaultSpoolDir = "export-spool"
+
+const dayLayout = "2006-01-02"
+
+// Exporter spools bus events and uploads them by day.
+type Exporter struct {
+	bucket *Bucket
+	prefix string
+	dir    string
+	// nil without a database, the orders snapshot is then skipped
+	store  *store.Store
+	logger *log.Logger
+
+	// spool file of the day being written, only touched by Run
+	day  string
+	file *os.File
+}
+
+// FromEnv builds an exporter from EXPORT_URL, s3://bucket/prefix or
+// gs://bucket/prefix, and the EXPORT_* credentials. An unset EXPORT_URL
+// disables the export and returns a nil exporter.
+func FromEnv(logger *log.Logger, db *store.Store) (*Exporter, error) {
+	target := os.Getenv("EXPORT_URL")
+	if target == "" {
+		return nil, nil
+	}
+
+	u, err := url.Parse(target)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid EXPORT_URL %q", target)
+	}
+
+	region := envOr("EXPORT_REGION", "us-east-1")
+	endpoint := os.Getenv("EXPORT_ENDPOINT")
+	switch u.Scheme {
+	case "s3":
+		if endpoint == "" {
+			endpoint = "https://s3." + region + ".amazonaws.com"
+		}
+	case "gs":
+		// Cloud Storage's XML API with HMAC keys
+		if endpoint == "" {
+			endpoint = "https://storage.googleapis.com"
+		}
+		region = envOr("EXPORT_REGION", "auto")
+	default:
+		return nil, fmt.Errorf("EXPORT_URL scheme must be s3 or gs, got %q", u.Scheme)
+	}
+
+	accessKey := envOr("EXPORT_ACCESS_KEY_ID", os.Getenv("AWS_ACCESS_KEY_ID"))
+	secretKey := envOr("EXPORT_SECRET_ACCESS_KEY", os.Getenv("AWS_SECRET_ACCESS_KEY"))
+	if accessKey == "" || secretKey == "" {
+		return nil, errors.New("EXPORT_URL needs EXPORT_ACCESS_KEY_ID and EXPORT_SECRET_ACCESS_KEY")
+	}
+
+	bucket, err := NewBucket(endpoint, u.Host, region, accessKey, secretKey)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := envOr("EXPORT_SPOOL_DIR", DefaultSpoolDir)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("creating export spool: %w", err)
+	}
+
+	return &Exporter{
+		bucket: bucket,
+		prefix: strings.Trim(u.Path, "/"),
+		dir:    dir,
+		store:  db,
+		logger: logger,
+	}, nil
+}
+
+// Enabled reports whether snapshots are exported.
+func (x *Exporter) Enabled() bool {
+	return x != nil
+}
+
+// eventLine is an event as exported.
+type eventLine struct {
+	Kind    bus.Kind       `json:"kind"`
+	Time    time.Time      `json:"time"`
+	Subject string         `json:"subject"`
+	Details map[string]any `json:"details,omitempty"`
+}
+
+// orderLine is a stored order as exported.
+type orderLine struct {
+	OrderHash   string          `json:"orderHash"`
+	SrcChainID  string          `json:"srcChainId"`
+	Maker       string          `json:"maker"`
+	Status      string          `json:"status"`
+	QuoteID     string          `json:"quoteId"`
+	Order       json.RawMessage `json:"order"`
+	State       json.RawMessage `json:"state"`
+	SubmittedAt time.Time       `json:"submittedAt"`
+}
+
+// Run spools events, which must not drop any, and uploads finished days
+// until done is closed. Days left in the spool by an earlier run are
+// uploaded first.
+func (x *Exporter) Run(events <-chan bus.Event, done <-chan struct{}) {
+	ticker := time.NewTicker(UploadInterval)
+	defer ticker.Stop()
+	defer x.closeSpool()
+
+	x.uploadFinished(time.Now())
+	for {
+		select {
+		case <-done:
+			return
+		case e := <-events:
+			if err := x.spool(e); err != nil {
+				x.logger.Printf("Failed to spool %s event for export: %v", e.Kind, err)
+			}
+		case now := <-ticker.C:
+			x.uploadFinished(now)
+		}
+	}
+}
+
+// spool appends e to the spool file of its day.
+func (x *Exporter) spool(e bus.Event) error {
+	line, err := json.Marshal(eventLine{Kind: e.Kind, Time: e.Time.UTC(), Subject: e.Subject, Details: e.Details})
+	if err != nil {
+		return err
+	}
+
+	day := e.Time.UTC().Format(dayLayout)
+	if day != x.day {
+		x.closeSpool()
+		f, err := os.OpenFile(x.spoolPath(day), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+		if err != nil {
+			return err
+		}
+		x.day, x.file = day, f
+	}
+
+	_, err = x.file.Write(append(line, '\n'))
+	return err
+}
+
+func (x *Exporter) closeSpool() {
+	if x.file == nil {
+		return
+	}
+	if err := x.file.Close(); err != nil {
+		x.logger.Printf("Failed to close export spool %s: %v", x.day, err)
+	}
+	x.day, x.file = "", nil
+}
+
+func (x *Exporter) spoolPath(day string) string {
+	return filepath.Join(x.dir, "events-"+day+".jsonl")
+}
+
+// uploadFinished uploads every spooled day before now's and removes its
+// spool file. Failed days stay spooled for the next attempt.
+func (x *Exporter) uploadFinished(now time.Time) {
+	paths, err := filepath.Glob(filepath.Join(x.dir, "events-*.jsonl"))
+	if err != nil {
+		x.logger.Printf("Failed to list export spool: %v", err)
+		return
+	}
+	sort.Strings(paths)
+
+	today := now.UTC().Format(dayLayout)
+	for _, path := range paths {
+		day := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(path), "events-"), ".jsonl")
+		if _, err := time.Parse(dayLayout, day); err != nil || day >= today {
+			continue
+		}
+		if day == x.day {
+			// a late event reopened the day
+			x.closeSpool()
+		}
+
+		if err := x.uploadDay(day, path); err != nil {
+			x.logger.Printf("Failed to export %s: %v", day, err)
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			x.logger.Printf("Failed to remove exported spool %s: %v", path, err)
+		}
+		x.logger.Printf("Exported %s to %s", day, x.key(day, ""))
+	}
+}
+
+// uploadDay uploads the events spooled at path and the orders changed on
+// day.
+func (x *Exporter) uploadDay(day, path string) error {
+	events, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), UploadTimeout)
+	defer cancel()
+
+	if err := x.bucket.Put(ctx, x.key(day, "events.jsonl"), "application/x-ndjson", events); err != nil {
+		return fmt.Errorf("uploading events: %w", err)
+	}
+	if x.store == nil {
+		return nil
+	}
+
+	orders, err := x.orders(ctx, day)
+	if err != nil {
+		return fmt.Errorf("reading orders: %w", err)
+	}
+	if err := x.bucket.Put(ctx, x.key(day, "orders.jsonl"), "application/x-ndjson", orders); err != nil {
+		return fmt.Errorf("uploading orders: %w", err)
+	}
+	return nil
+}
+
+// orders renders the stored orders changed on day as JSONL.
+func (x *Exporter) orders(ctx context.Context, day string) ([]byte, error) {
+	from, _ := time.Parse(dayLayout, day)
+	recs, err := x.store.OrdersUpdated(ctx, from, from.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+
+	var out []byte
+	for _, rec := range recs {
+		line, err := json.Marshal(orderLine{
+			OrderHash:   rec.OrderHash,
+			SrcChainID:  rec.SrcChainID,
+			Maker:       rec.Maker,
+			Status:      rec.Status,
+			QuoteID:     rec.QuoteID,
+			Order:       rec.Order,
+			State:       rec.State,
+			SubmittedAt: rec.SubmittedAt.UTC(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		out = append(append(out, line...), '\n')
+	}
+	return out, nil
+}
+
+// key is the object key of name in day's partition.
+func (x *Exporter) key(day, name string) string {
+	key := "dt=" + day + "/" + name
+	if x.prefix != "" {
+		key = x.prefix + "/" + key
+	}
+	return key
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
@@ -0,0 +1,315 @@
+// Package extension decodes the limit order extension of EVM-sourced
+// cross-chain orders, as built by the SDK's EscrowExtension.
+package extension
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Field indices of the extension offsets word, see ExtensionLib in the
+// limit order protocol.
+const (
+	makerAssetSuffix = iota
+	takerAssetSuffix
+	makingAmountData
+	takingAmountData
+	predicate
+	makerPermit
+	preInteractionData
+	postInteractionData
+	fieldCount
+)
+
+// escrowDataLength is the ABI encoded escrow data appended to the fusion
+// post interaction: hashlock, dst chain id, dst token, safety deposits and timelocks.
+const escrowDataLength = 5 * 32
+
+// Extension is the decoded extension of a cross-chain order.
+type Extension struct {
+	Settlement ethcommon.Address
+	Auction    AuctionDetails
+	Whitelist  []WhitelistEntry
+	// ResolvingStartTime is when the first whitelisted resolver may fill (unix seconds).
+	ResolvingStartTime uint32
+	IntegratorFee      *IntegratorFee
+	// MakerPermit is nil when the maker asset is pulled with a plain allowance.
+	MakerPermit *MakerPermit
+	Escrow      EscrowData
+	// DstAddressComplement holds the leading bytes of a destination address
+	// longer than 20 bytes, e.g. a Sui receiver.
+	DstAddressComplement []byte
+}
+
+// AuctionDetails is the auction encoded in the making/taking amount data.
+type AuctionDetails struct {
+	GasBumpEstimate  uint32
+	GasPriceEstimate uint32
+	StartTime        uint32
+	Duration         uint32
+	InitialRateBump  uint32
+	Points           []AuctionPoint
+}
+
+// AuctionPoint is a rate bump reached Delay seconds after the previous point.
+type AuctionPoint struct {
+	Coefficient uint32
+	Delay       uint16
+}
+
+// WhitelistEntry allows the resolver whose address ends in AddressHalf to
+// fill Delay seconds after the previous entry.
+type WhitelistEntry struct {
+	AddressHalf [10]byte
+	Delay       uint16
+}
+
+// Allows reports whether the entry matches addr.
+func (w WhitelistEntry) Allows(addr ethcommon.Address) bool {
+	return [10]byte(addr[10:]) == w.AddressHalf
+}
+
+// IntegratorFee is the fee taken by the integrator, Ratio in units of 1e-5.
+type IntegratorFee struct {
+	Ratio          uint16
+	Receiver       ethcommon.Address
+	CustomReceiver *ethcommon.Address
+}
+
+// EscrowData is the cross-chain part of the extension.
+type EscrowData struct {
+	Hashlock         ethcommon.Hash
+	DstChainID       *big.Int
+	DstToken         ethcommon.Hash
+	SrcSafetyDeposit *big.Int
+	DstSafetyDeposit *big.Int
+	Timelocks        Timelocks
+}
+
+// PartsCount returns the number of parts of a multiple fill order, stored in
+// the top 16 bits of its hashlock.
+func (e EscrowData) PartsCount() uint64 {
+	return uint64(e.Hashlock[0])<<8 | uint64(e.Hashlock[1])
+}
+
+// Timelocks are the escrow stage offsets in seconds, packed 32 bits each.
+type Timelocks struct {
+	SrcWithdrawal         uint32
+	SrcPublicWithdrawal   uint32
+	SrcCancellation       uint32
+	SrcPublicCancellation uint32
+	DstWithdrawal         uint32
+	DstPublicWithdrawal   uint32
+	DstCancellation       uint32
+	DeployedAt            uint32
+}
+
+// Decode parses a 0x prefixed extension.
+func Decode(s string) (*Extension, error) {
+	data, err := hexutil.Decode(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid extension hex: %w", err)
+	}
+
+	fields, customData, err := split(data)
+	if err != nil {
+		return nil, err
+	}
+
+	making := fields[makingAmountData]
+	if len(making) < 20 {
+		return nil, errors.New("extension has no making amount data")
+	}
+
+	ext := &Extension{
+		Settlement:           ethcommon.BytesToAddress(making[:20]),
+		DstAddressComplement: customData,
+	}
+
+	if ext.Auction, err = decodeAuction(making[20:]); err != nil {
+		return nil, err
+	}
+
+	if permit := fields[makerPermit]; len(permit) > 0 {
+		if len(permit) < 20 {
+			return nil, errors.New("maker permit is too short")
+		}
+		ext.MakerPermit = &MakerPermit{Token: ethcommon.BytesToAddress(permit[:20]), Data: permit[20:]}
+	}
+
+	post := fields[postInteractionData]
+	if len(post) < 20+escrowDataLength+1 {
+		return nil, errors.New("extension post interaction is too short")
+	}
+	if ethcommon.BytesToAddress(post[:20]) != ext.Settlement {
+		return nil, errors.New("post interaction targets a different settlement contract")
+	}
+
+	ext.Escrow = decodeEscrow(post[len(post)-escrowDataLength:])
+	if err := ext.decodeSettlementData(post[20 : len(post)-escrowDataLength]); err != nil {
+		return nil, err
+	}
+
+	return ext, nil
+}
+
+// split cuts the extension into its fields using the leading offsets word,
+// which stores the end offset of field i in bits [32*i, 32*i+32).
+func split(data []byte) ([fieldCount][]byte, []byte, error) {
+	var fields [fieldCount][]byte
+	if len(data) < 32 {
+		return fields, nil, errors.New("extension is too short")
+	}
+
+	offsets, body := data[:32], data[32:]
+	begin := 0
+	for i := 0; i < fieldCount; i++ {
+		// word is big endian, field 0 sits in the lowest 4 bytes
+		pos := 32 - 4*(i+1)
+		end := int(uint32(offsets[pos])<<24 | uint32(offsets[pos+1])<<16 | uint32(offsets[pos+2])<<8 | uint32(offsets[pos+3]))
+		if end < begin || end > len(body) {
+			return fields, nil, fmt.Errorf("invalid offset for extension field %d", i)
+		}
+		fields[i] = body[begin:end]
+		begin = end
+	}
+
+	return fields, body[begin:], nil
+}
+
+// decodeAuction parses the packed auction details: gas bump (uint24), gas
+// price (uint32), start time (uint32), duration (uint24), initial rate bump
+// (uint24), then points of coefficient (uint24) and delay (uint16).
+func decodeAuction(data []byte) (AuctionDetails, error) {
+	const header, point = 3 + 4 + 4 + 3 + 3, 3 + 2
+	if len(data) < header || (len(data)-header)%point != 0 {
+		return AuctionDetails{}, errors.New("malformed auction details")
+	}
+
+	r := reader{data: data}
+	details := AuctionDetails{
+		GasBumpEstimate:  r.uint(3),
+		GasPriceEstimate: r.uint(4),
+		StartTime:        r.uint(4),
+		Duration:         r.uint(3),
+		InitialRateBump:  r.uint(3),
+	}
+	for r.len() > 0 {
+		details.Points = append(details.Points, AuctionPoint{
+			Coefficient: r.uint(3),
+			Delay:       uint16(r.uint(2)),
+		})
+	}
+
+	return details, nil
+}
+
+// decodeSettlementData parses the fusion post interaction data that precedes
+// the escrow data: optional integrator fee, resolving start time, whitelist
+// and a trailing flags byte (bit 0 fee, bit 1 custom receiver, bits 3-7
+// whitelist length).
+func (ext *Extension) decodeSettlementData(data []byte) error {
+	if len(data) < 1 {
+		return errors.New("missing settlement flags")
+	}
+
+	flags := data[len(data)-1]
+	r := reader{data: data[:len(data)-1]}
+
+	if flags&1 != 0 {
+		if r.len() < 22 {
+			return errors.New("malformed integrator fee")
+		}
+		fee := &IntegratorFee{Ratio: uint16(r.uint(2)), Receiver: ethcommon.BytesToAddress(r.bytes(20))}
+		if flags&2 != 0 {
+			if r.len() < 20 {
+				return errors.New("malformed custom receiver")
+			}
+			receiver := ethcommon.BytesToAddress(r.bytes(20))
+			fee.CustomReceiver = &receiver
+		}
+		ext.IntegratorFee = fee
+	}
+
+	count := int(flags >> 3)
+	if r.len() != 4+count*12 {
+		return errors.New("malformed whitelist")
+	}
+
+	ext.ResolvingStartTime = r.uint(4)
+	for i := 0; i < count; i++ {
+		var entry WhitelistEntry
+		copy(entry.AddressHalf[:], r.bytes(10))
+		entry.Delay = uint16(r.uint(2))
+		ext.Whitelist = append(ext.Whitelist, entry)
+	}
+
+	return nil
+}
+
+func decodeEscrow(data []byte) EscrowData {
+	deposits := new(big.Int).SetBytes(data[96:128])
+	mask := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+
+	return EscrowData{
+		Hashlock:         ethcommon.BytesToHash(data[0:32]),
+		DstChainID:       new(big.Int).SetBytes(data[32:64]),
+		DstToken:         ethcommon.BytesToHash(data[64:96]),
+		SrcSafetyDeposit: new(big.Int).Rsh(deposits, 128),
+		DstSafetyDeposit: new(big.Int).And(deposits, mask),
+		Timelocks:        decodeTimelocks(data[128:160]),
+	}
+}
+
+func decodeTimelocks(word []byte) Timelocks {
+	at := func(i int) uint32 {
+		pos := 32 - 4*(i+1)
+		return uint32(word[pos])<<24 | uint32(word[pos+1])<<16 | uint32(word[pos+2])<<8 | uint32(word[pos+3])
+	}
+
+	return Timelocks{
+		SrcWithdrawal:         at(0),
+		SrcPublicWithdrawal:   at(1),
+		SrcCancellation:       at(2),
+		SrcPublicCancellation: at(3),
+		DstWithdrawal:         at(4),
+		DstPublicWithdrawal:   at(5),
+		DstCancellation:       at(6),
+		DeployedAt:            at(7),
+	}
+}
+
+// IsEmpty reports whether s carries no extension.
+func IsEmpty(s string) bool {
+	s = strings.TrimPrefix(s, "0x")
+	return s == ""
+}
+
+// reader consumes big endian integers from a byte slice. Callers check the
+// length up front.
+type reader struct {
+	data []byte
+}
+
+func (r *reader) len() int {
+	return len(r.data)
+}
+
+func (r *reader) bytes(n int) []byte {
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b
+}
+
+func (r *reader) uint(n int) uint32 {
+	var v uint32
+	for _, b := range r.bytes(n) {
+		v = v<<8 | uint32(b)
+	}
+	return v
+}
@@ -0,0 +1,107 @@
+package extension
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"relayer/internal/common"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+// Permit2 calldata lengths accepted by SafeERC20.tryPermit: the compact
+// (amount, expiration, nonce, sigDeadline, r, vs) form and IPermit2.permit's
+// ABI encoded (owner, permitSingle, signature) arguments, with a 64 or 65 byte
+// signature.
+const (
+	permit2CompactLength = 20 + 4 + 4 + 4 + 32 + 32
+	permit2Length        = 11 * 32
+	permit2LongSigLength = 12 * 32
+)
+
+// MakerPermit is the permit the protocol applies before pulling the maker
+// asset: the token it targets followed by the permit calldata.
+type MakerPermit struct {
+	Token ethcommon.Address
+	Data  []byte
+}
+
+// Permit2 is the allowance a Permit2 permit grants to the protocol.
+type Permit2 struct {
+	// Owner is only encoded in the full form
+	Owner       *ethcommon.Address
+	Token       ethcommon.Address
+	Amount      *big.Int
+	Expiration  uint64
+	Nonce       uint64
+	SigDeadline uint64
+}
+
+// Permit2 decodes p as Permit2 calldata.
+func (p *MakerPermit) Permit2() (*Permit2, error) {
+	switch len(p.Data) {
+	case permit2CompactLength:
+		r := reader{data: p.Data}
+		return &Permit2{
+			Token:       p.Token,
+			Amount:      new(big.Int).SetBytes(r.bytes(20)),
+			Expiration:  uint64(r.uint(4)),
+			Nonce:       uint64(r.uint(4)),
+			SigDeadline: uint64(r.uint(4)),
+		}, nil
+	case permit2Length, permit2LongSigLength:
+		word := func(i int) []byte { return p.Data[32*i : 32*(i+1)] }
+		owner := ethcommon.BytesToAddress(word(0))
+		return &Permit2{
+			Owner:       &owner,
+			Token:       ethcommon.BytesToAddress(word(1)),
+			Amount:      new(big.Int).SetBytes(word(2)),
+			Expiration:  new(big.Int).SetBytes(word(3)).Uint64(),
+			Nonce:       new(big.Int).SetBytes(word(4)).Uint64(),
+			SigDeadline: new(big.Int).SetBytes(word(6)).Uint64(),
+		}, nil
+	default:
+		return nil, fmt.Errorf("permit of %d bytes is not a Permit2 permit", len(p.Data))
+	}
+}
+
+// CheckPermit2 checks that the extension of an order flagged USE_PERMIT2
+// carries a Permit2 permit letting the protocol pull the making amount of the
+// maker asset, still valid at now. Without it the fill reverts on transfer.
+func (ext *Extension) CheckPermit2(order common.LimitOrder, now time.Time) error {
+	if ext.MakerPermit == nil {
+		return errors.New("order uses Permit2 but its extension has no maker permit")
+	}
+
+	permit, err := ext.MakerPermit.Permit2()
+	if err != nil {
+		return err
+	}
+
+	asset := ethcommon.HexToAddress(order.MakerAsset)
+	if ext.MakerPermit.Token != asset || permit.Token != asset {
+		return fmt.Errorf("permit token %s is not the maker asset %s", permit.Token.Hex(), asset.Hex())
+	}
+	if permit.Owner != nil && *permit.Owner != ethcommon.HexToAddress(order.Maker) {
+		return fmt.Errorf("permit owner %s is not the maker", permit.Owner.Hex())
+	}
+
+	making, ok := new(big.Int).SetString(order.MakingAmount, 10)
+	if !ok {
+		return fmt.Errorf("invalid making amount %q", order.MakingAmount)
+	}
+	if permit.Amount.Cmp(making) < 0 {
+		return fmt.Errorf("permit amount %s is below the making amount %s", permit.Amount, making)
+	}
+
+	if permit.SigDeadline < uint64(now.Unix()) {
+		return errors.New("permit signature deadline has passed")
+	}
+	// a zero expiration grants the allowance for the permitting transaction only
+	if permit.Expiration != 0 && permit.Expiration < uint64(now.Unix()) {
+		return errors.New("permit allowance has expired")
+	}
+
+	return nil
+}
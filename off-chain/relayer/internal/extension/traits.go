@@ -0,0 +1,76 @@
+package extension
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// MakerTraits flag bits, see MakerTraitsLib in the limit order protocol.
+const (
+	noPartialFillsFlag        = 255
+	allowMultipleFillsFlag    = 254
+	needCheckEpochManagerFlag = 250
+	hasExtensionFlag          = 249
+	usePermit2Flag            = 248
+)
+
+// MakerTraits uint40 fields below the flags
+const (
+	nonceOrEpochOffset = 120
+	seriesOffset       = 160
+	uint40Mask         = 1<<40 - 1
+)
+
+// MakerTraits is the uint256 of order flags and limits signed with an order.
+type MakerTraits struct {
+	v *big.Int
+}
+
+// ParseMakerTraits parses traits given in decimal or 0x prefixed hex, as the
+// SDK's BigInt accepts them.
+func ParseMakerTraits(s string) (MakerTraits, error) {
+	v, ok := new(big.Int).SetString(s, 0)
+	if !ok || v.Sign() < 0 || v.BitLen() > 256 {
+		return MakerTraits{}, fmt.Errorf("invalid maker traits %q", s)
+	}
+	return MakerTraits{v: v}, nil
+}
+
+// HasExtension reports whether the order commits to an extension.
+func (t MakerTraits) HasExtension() bool {
+	return t.v.Bit(hasExtensionFlag) == 1
+}
+
+// UsePermit2 reports whether the maker asset is pulled through Permit2, the
+// allowance being granted by the permit in the extension.
+func (t MakerTraits) UsePermit2() bool {
+	return t.v.Bit(usePermit2Flag) == 1
+}
+
+// NeedCheckEpochManager reports whether the order is invalidated by the
+// maker advancing the epoch of its series, rather than by a bit invalidator.
+func (t MakerTraits) NeedCheckEpochManager() bool {
+	return t.v.Bit(needCheckEpochManagerFlag) == 1
+}
+
+// UseBitInvalidator reports whether the protocol tracks the order's nonce in
+// a bit invalidator, as it does for orders without partial or multiple fills.
+// Such orders cannot also be checked against the epoch manager.
+func (t MakerTraits) UseBitInvalidator() bool {
+	return t.v.Bit(noPartialFillsFlag) == 1 || t.v.Bit(allowMultipleFillsFlag) == 0
+}
+
+// NonceOrEpoch is the order's nonce, or its epoch when the epoch manager is
+// checked.
+func (t MakerTraits) NonceOrEpoch() uint64 {
+	return t.field(nonceOrEpochOffset)
+}
+
+// Series is the epoch series the order belongs to.
+func (t MakerTraits) Series() uint64 {
+	return t.field(seriesOffset)
+}
+
+func (t MakerTraits) field(offset uint) uint64 {
+	return new(big.Int).Rsh(t.v, offset).Uint64() & uint40Mask
+}
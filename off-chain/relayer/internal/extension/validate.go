@@ -0,0 +1,173 @@
+package extension
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"relayer/internal/common"
+	"relayer/internal/hashlock"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// saltHashBits are the bits of an order's salt holding the hash of its
+// extension, see OrderLib.isValidExtension in the limit order protocol.
+const saltHashBits = 160
+
+// CheckCommitment checks that the signed order commits to the hex encoded
+// extension: its maker traits set HAS_EXTENSION and the low 160 bits of its
+// salt are those of the extension's keccak256. The protocol executes no
+// other extension, so any other would be trusted for nothing.
+func CheckCommitment(extension, salt string, traits MakerTraits) error {
+	if !traits.HasExtension() {
+		return errors.New("maker traits do not set HAS_EXTENSION")
+	}
+
+	data, err := hexutil.Decode(extension)
+	if err != nil {
+		return fmt.Errorf("invalid extension hex: %w", err)
+	}
+	v, ok := new(big.Int).SetString(salt, 0)
+	if !ok || v.Sign() < 0 || v.BitLen() > 256 {
+		return fmt.Errorf("invalid salt %q", salt)
+	}
+
+	mask := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), saltHashBits), big.NewInt(1))
+	hash := new(big.Int).SetBytes(crypto.Keccak256(data))
+	if new(big.Int).And(v, mask).Cmp(hash.And(hash, mask)) != 0 {
+		return errors.New("salt does not commit to the extension")
+	}
+	return nil
+}
+
+// CheckHashlock checks the hashlock of a single fill order's extension: it
+// cannot be zero, which no secret opens, and must be want when the relayer
+// knows the order's hashlock, want being zero otherwise.
+func (ext *Extension) CheckHashlock(want ethcommon.Hash) error {
+	if ext.Escrow.Hashlock == (ethcommon.Hash{}) {
+		return errors.New("extension hashlock is zero")
+	}
+	if want != (ethcommon.Hash{}) && ext.Escrow.Hashlock != want {
+		return fmt.Errorf("extension hashlock %s does not match the order's %s", ext.Escrow.Hashlock.Hex(), want.Hex())
+	}
+	return nil
+}
+
+// Validate checks the extension against the quote the order was built from,
+// its destination chain and, for multiple fill orders, its secret hashes.
+func (ext *Extension) Validate(quote *common.Quote, dstChain string, secretHashes []string) error {
+	if !ext.matchesPreset(quote.Presets) {
+		return errors.New("auction does not match any quoted preset")
+	}
+
+	if ext.Escrow.DstChainID.String() != dstChain {
+		return fmt.Errorf("extension dst chain %s does not match quoted %s", ext.Escrow.DstChainID, dstChain)
+	}
+
+	if err := sameAmount("src safety deposit", ext.Escrow.SrcSafetyDeposit, quote.SrcSafetyDeposit); err != nil {
+		return err
+	}
+	if err := sameAmount("dst safety deposit", ext.Escrow.DstSafetyDeposit, quote.DstSafetyDeposit); err != nil {
+		return err
+	}
+
+	if !ext.Escrow.Timelocks.matches(quote.TimeLocks) {
+		return errors.New("timelocks do not match the quote")
+	}
+
+	if err := ext.checkIntegratorFee(quote); err != nil {
+		return err
+	}
+
+	if len(secretHashes) > 0 {
+		return ext.Escrow.checkMerkleRoot(secretHashes)
+	}
+	return nil
+}
+
+// checkIntegratorFee requires the integrator fee quoted to be charged to the
+// quoted receiver, bps being ten ratio units.
+func (ext *Extension) checkIntegratorFee(quote *common.Quote) error {
+	if quote.IntegratorFeeBps == 0 {
+		return nil
+	}
+	fee := ext.IntegratorFee
+	if fee == nil {
+		return errors.New("extension lacks the quoted integrator fee")
+	}
+	if uint64(fee.Ratio) != quote.IntegratorFeeBps*10 {
+		return fmt.Errorf("integrator fee ratio %d does not match quoted %d bps", fee.Ratio, quote.IntegratorFeeBps)
+	}
+	if ethcommon.IsHexAddress(quote.FeeReceiver) && fee.Receiver != ethcommon.HexToAddress(quote.FeeReceiver) {
+		return fmt.Errorf("integrator fee receiver %s does not match quoted %s", fee.Receiver.Hex(), quote.FeeReceiver)
+	}
+	return nil
+}
+
+func (ext *Extension) matchesPreset(presets common.QuoterPresets) bool {
+	for _, preset := range presets {
+		if ext.Auction.matches(preset) {
+			return true
+		}
+	}
+	return false
+}
+
+func (a AuctionDetails) matches(preset common.PresetData) bool {
+	if int64(a.Duration) != preset.AuctionDuration || float64(a.InitialRateBump) != preset.InitialRateBump {
+		return false
+	}
+	if len(a.Points) != len(preset.Points) {
+		return false
+	}
+	for i, p := range preset.Points {
+		if float64(a.Points[i].Coefficient) != p.Coefficient || int64(a.Points[i].Delay) != p.Delay {
+			return false
+		}
+	}
+	return true
+}
+
+func (t Timelocks) matches(quoted common.TimeLocksRaw) bool {
+	return int64(t.SrcWithdrawal) == quoted.SrcWithdrawal &&
+		int64(t.SrcPublicWithdrawal) == quoted.SrcPublicWithdrawal &&
+		int64(t.SrcCancellation) == quoted.SrcCancellation &&
+		int64(t.SrcPublicCancellation) == quoted.SrcPublicCancellation &&
+		int64(t.DstWithdrawal) == quoted.DstWithdrawal &&
+		int64(t.DstPublicWithdrawal) == quoted.DstPublicWithdrawal &&
+		int64(t.DstCancellation) == quoted.DstCancellation
+}
+
+func sameAmount(name string, got *big.Int, quoted string) error {
+	want, ok := new(big.Int).SetString(quoted, 10)
+	if !ok {
+		return fmt.Errorf("quote has invalid %s: %q", name, quoted)
+	}
+	if got.Cmp(want) != 0 {
+		return fmt.Errorf("%s %s does not match quoted %s", name, got, quoted)
+	}
+	return nil
+}
+
+// checkMerkleRoot verifies that the hashlock commits to secretHashes, see
+// hashlock.MultiFill.
+func (e EscrowData) checkMerkleRoot(secretHashes []string) error {
+	if len(secretHashes) < 3 {
+		return errors.New("multiple fill orders need at least 3 secret hashes")
+	}
+	if e.PartsCount() != uint64(len(secretHashes)-1) {
+		return fmt.Errorf("hashlock is for %d parts, order has %d secret hashes", e.PartsCount(), len(secretHashes))
+	}
+
+	hashes := make([]ethcommon.Hash, len(secretHashes))
+	for i, h := range secretHashes {
+		hashes[i] = ethcommon.HexToHash(h)
+	}
+
+	if hashlock.MultiFill(hashes) != e.Hashlock {
+		return errors.New("hashlock is not the merkle root of the secret hashes")
+	}
+	return nil
+}
@@ -22,6 +22,10 @@ var limitOrderContracts = map[common.ChainID]string{
 	common.BSC:             "0x111111125421cA6dc452d289314280a0f8842A65", // Example address - replace with actual
 	common.Optimism:        "0x111111125421cA6dc452d289314280a0f8842A65", // Example address - replace with actual
 	common.Base:            "0x111111125421cA6dc452d289314280a0f8842A65", // Example address - replace with actual
+
+	common.Sepolia:         "0x111111125421cA6dc452d289314280a0f8842A65",
+	common.BaseSepolia:     "0x111111125421cA6dc452d289314280a0f8842A65",
+	common.ArbitrumSepolia: "0x111111125421cA6dc452d289314280a0f8842A65",
 }
 
 // GetLimitOrderContract returns the 1inch Aggregation Router contract address for the given chain ID
@@ -29,7 +33,7 @@ var limitOrderContracts = map[common.ChainID]string{
 func GetLimitOrderContract(chainID common.ChainID) (ethcommon.Address, error) {
 	contractAddress, exists := limitOrderContracts[chainID]
 	if !exists {
-		return ethcommon.Address{}, fmt.Errorf("unsupported chain ID: %d", chainID)
+		return ethcommon.Address{}, fmt.Errorf("unsupported chain ID: %s", chainID)
 	}
 
 	return ethcommon.HexToAddress(contractAddress), nil
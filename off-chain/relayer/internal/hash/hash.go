@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/hex"
 	"fmt"
+	"log/slog"
 	"math/big"
 	"strings"
 
@@ -14,7 +15,6 @@ import (
 	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/signer/core/apitypes"
-	"github.com/holiman/uint256"
 )
 
 // GetOrderHash computes the EIP712 hash for a given typed data
@@ -28,8 +28,6 @@ func GetOrderHash(typedData apitypes.TypedData) (ethcommon.Hash, error) {
 
 // BuildOrderTypedData constructs the EIP712 typed data for a limit order
 func BuildOrderTypedData(chainID common.ChainID, verifyingContract ethcommon.Address, name, version string, order common.LimitOrder) apitypes.TypedData {
-	chainIDHex := (*uint256.Int)(chainID)
-
 	// sui address (32 bytes) to evm address (20 bytes)
 	receiverAddr := ethcommon.HexToAddress(order.Receiver)
 	takerAssetAddr := ethcommon.HexToAddress(order.TakerAsset)
@@ -43,7 +41,7 @@ func BuildOrderTypedData(chainID common.ChainID, verifyingContract ethcommon.Add
 		Domain: apitypes.TypedDataDomain{
 			Name:              name,
 			Version:           version,
-			ChainId:           (*math.HexOrDecimal256)(chainIDHex.ToBig()),
+			ChainId:           math.NewHexOrDecimal256(int64(chainID)),
 			VerifyingContract: verifyingContract.Hex(),
 		},
 		Message: apitypes.TypedDataMessage{
@@ -66,12 +64,10 @@ func GetLimitOrderV4Domain(chainID common.ChainID) (apitypes.TypedDataDomain, er
 		return apitypes.TypedDataDomain{}, fmt.Errorf("failed to get contract address: %w", err)
 	}
 
-	chainIDHex := (*uint256.Int)(chainID)
-
 	return apitypes.TypedDataDomain{
 		Name:              LimitOrderV4TypeDataName,
 		Version:           LimitOrderV4TypeDataVersion,
-		ChainId:           (*math.HexOrDecimal256)(chainIDHex.ToBig()),
+		ChainId:           math.NewHexOrDecimal256(int64(chainID)),
 		VerifyingContract: contract.Hex(),
 	}, nil
 }
@@ -79,20 +75,16 @@ func GetLimitOrderV4Domain(chainID common.ChainID) (apitypes.TypedDataDomain, er
 // GetOrderHashForLimitOrder is a convenience function that builds typed data and computes hash for a limit order
 // This is the main function you'll want to call with your order type & chainID
 func GetOrderHashForLimitOrder(chainID common.ChainID, order common.LimitOrder) (ethcommon.Hash, error) {
-	if (*uint256.Int)(chainID).Eq(common.Sui) {
+	if chainID.IsMove() {
 		bcsEncodedOrder := bytes.Buffer{}
 		bcsEncoder := mystenbcs.NewEncoder(&bcsEncodedOrder)
 
 		// salt big.Int from string
 		saltBytes := ethcommon.Hex2Bytes(order.Salt)
-		fmt.Println("Salt value:", saltBytes)
 
 		// hex to bytes for Maker address
 		makerBytes := ethcommon.Hex2Bytes(strings.TrimPrefix(order.Maker, "0x"))
-		fmt.Println("Maker address bytes:", makerBytes, len(makerBytes), order.Maker)
-
 		receiverBytes := ethcommon.HexToAddress(order.Receiver)
-		fmt.Println("Receiver address bytes:", receiverBytes.Bytes(), len(receiverBytes.Bytes()))
 
 		// Convert MakingAmount string to uint64
 		makingAmountBigInt, ok := new(big.Int).SetString(order.MakingAmount, 10)
@@ -100,7 +92,6 @@ func GetOrderHashForLimitOrder(chainID common.ChainID, order common.LimitOrder)
 			return ethcommon.Hash{}, fmt.Errorf("invalid makingAmount value: %s", order.MakingAmount)
 		}
 		makingAmountUint64 := makingAmountBigInt.Uint64()
-		fmt.Println("Making amount:", makingAmountUint64)
 
 		// Convert TakingAmount string to uint64
 		takingAmountBigInt, ok := new(big.Int).SetString(order.TakingAmount, 10)
@@ -108,7 +99,6 @@ func GetOrderHashForLimitOrder(chainID common.ChainID, order common.LimitOrder)
 			return ethcommon.Hash{}, fmt.Errorf("invalid takingAmount value: %s", order.TakingAmount)
 		}
 		takingAmountUint64 := takingAmountBigInt.Uint64()
-		fmt.Println("Taking amount:", takingAmountUint64)
 
 		if err := bcsEncoder.Encode(OrderHashType{
 			Salt:         saltBytes,
@@ -120,7 +110,14 @@ func GetOrderHashForLimitOrder(chainID common.ChainID, order common.LimitOrder)
 			return ethcommon.Hash{}, fmt.Errorf("failed to encode order: %w", err)
 		}
 
-		fmt.Println("Encoded order bytes:", bcsEncodedOrder.Bytes())
+		slog.Debug("bcs encoded sui order",
+			"maker", order.Maker,
+			"makerBytes", len(makerBytes),
+			"receiver", receiverBytes.Hex(),
+			"makingAmount", makingAmountUint64,
+			"takingAmount", takingAmountUint64,
+			"encoded", hex.EncodeToString(bcsEncodedOrder.Bytes()),
+		)
 
 		return crypto.Keccak256Hash(bcsEncodedOrder.Bytes()), nil
 	}
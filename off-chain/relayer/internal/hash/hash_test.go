@@ -0,0 +1,48 @@
+package hash
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"relayer/internal/common"
+	"testing"
+)
+
+func BenchmarkOrderHashEvm(b *testing.B) {
+	golden, err := os.ReadFile(filepath.Join("..", "common", "testdata", "order.json"))
+	if err != nil {
+		b.Fatal(err)
+	}
+	var order common.Order
+	if err := json.Unmarshal(golden, &order); err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := GetOrderHashForLimitOrder(order.SrcChainID, order.LimitOrder); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkOrderHashSui(b *testing.B) {
+	order := common.LimitOrder{
+		Salt:         "0x6e3b6d0c2bd8f7a1d1e2f30c3f1f4b0a9c1d7e2f5a6b7c8d9e0f1a2b3c4d5e6f",
+		Maker:        "0x5f2a1f8b3c2a4f9e8d7c6b5a4f3e2d1c0b9a8f7e6d5c4b3a2f1e0d9c8b7a6f5e",
+		Receiver:     "0x00000000219ab540356cbb839cbe05303d7705fa",
+		MakerAsset:   "0x2::sui::SUI",
+		TakerAsset:   "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48",
+		MakingAmount: "1000000000",
+		TakingAmount: "1420000000",
+		MakerTraits:  "0",
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := GetOrderHashForLimitOrder(common.Sui, order); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
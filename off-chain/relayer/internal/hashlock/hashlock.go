@@ -0,0 +1,60 @@
+package hashlock
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strings"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Algorithm is the hash function an escrow's hashlock is computed with, the
+// hash of the secret the maker reveals. The merkle tree of multiple fill
+// orders always uses keccak256; only its leaves' secret hashes vary.
+type Algorithm string
+
+const (
+	// Keccak256 is used by the EVM and Move escrows and is the default.
+	Keccak256 Algorithm = "keccak256"
+	// SHA256 is used by Bitcoin-style HTLCs and some Move implementations.
+	SHA256 Algorithm = "sha256"
+)
+
+// Parse returns the named algorithm; the empty name is Keccak256.
+func Parse(name string) (Algorithm, error) {
+	switch Algorithm(strings.ToLower(name)) {
+	case "", Keccak256:
+		return Keccak256, nil
+	case SHA256:
+		return SHA256, nil
+	default:
+		return "", fmt.Errorf("unknown hashlock algorithm %q", name)
+	}
+}
+
+// Hash returns the hashlock of secret.
+func (a Algorithm) Hash(secret []byte) ethcommon.Hash {
+	if a == SHA256 {
+		return sha256.Sum256(secret)
+	}
+	return crypto.Keccak256Hash(secret)
+}
+
+// Verify checks that the hex encoded secret hashes to one of hashlocks and
+// returns its index.
+func (a Algorithm) Verify(secret string, hashlocks ...ethcommon.Hash) (int, error) {
+	raw, err := hexutil.Decode(secret)
+	if err != nil {
+		return 0, fmt.Errorf("invalid secret: %w", err)
+	}
+
+	h := a.Hash(raw)
+	for i, lock := range hashlocks {
+		if h == lock {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("secret does not match the order's %s hashlock", a)
+}
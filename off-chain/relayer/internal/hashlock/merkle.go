@@ -0,0 +1,48 @@
+package hashlock
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sort"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// MultiFill returns the escrow hashlock of a multiple fill order: the top 16
+// bits hold the parts count, len(secretHashes)-1, and the rest the root of
+// the tree over keccak256(uint64 idx ++ secretHash), as built by the SDK's
+// HashLock.
+func MultiFill(secretHashes []ethcommon.Hash) ethcommon.Hash {
+	leaves := make([][]byte, len(secretHashes))
+	for i, h := range secretHashes {
+		var idx [8]byte
+		binary.BigEndian.PutUint64(idx[:], uint64(i))
+		leaves[i] = crypto.Keccak256(idx[:], h.Bytes())
+	}
+
+	lock := ethcommon.BytesToHash(merkleRoot(leaves))
+	parts := len(secretHashes) - 1
+	lock[0], lock[1] = byte(parts>>8), byte(parts)
+	return lock
+}
+
+// merkleRoot mirrors OpenZeppelin's SimpleMerkleTree: sorted leaves laid out
+// in reverse at the end of a complete binary tree, hashing sorted pairs.
+func merkleRoot(leaves [][]byte) []byte {
+	sort.Slice(leaves, func(i, j int) bool { return bytes.Compare(leaves[i], leaves[j]) < 0 })
+
+	tree := make([][]byte, 2*len(leaves)-1)
+	for i, leaf := range leaves {
+		tree[len(tree)-1-i] = leaf
+	}
+	for i := len(tree) - 1 - len(leaves); i >= 0; i-- {
+		a, b := tree[2*i+1], tree[2*i+2]
+		if bytes.Compare(a, b) > 0 {
+			a, b = b, a
+		}
+		tree[i] = crypto.Keccak256(a, b)
+	}
+
+	return tree[0]
+}
@@ -0,0 +1,331 @@
+// Package loadgen drives a relayer running on mock chains with synthetic
+// traffic: quotes, EVM to EVM orders and, once each order is broadcast, the
+// escrow deployments of its fill and the TXHASH reporting them. Fills pass
+// verification like real ones, so the broadcaster, the verification workers
+// and the store see the load of a production relayer at a chosen rate.
+package loadgen
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"fmt"
+	"log"
+	"math/big"
+	"relayer/internal/chain"
+	"relayer/internal/chain/mock"
+	"relayer/internal/common"
+	"relayer/internal/hash"
+	"relayer/internal/hashlock"
+	"relayer/internal/manager"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultFillDelay is how long after its broadcast an order is filled
+	DefaultFillDelay = time.Second * 2
+	// ReportInterval is how often the generator logs its progress
+	ReportInterval = time.Second * 10
+
+	// making amount of every order, taking twice as much
+	makingAmount = 1_000_000_000_000_000_000
+)
+
+// balanceOfSelector is ERC20.balanceOf(address), answered for both escrows.
+var balanceOfSelector = crypto.Keccak256([]byte("balanceOf(address)"))[:4]
+
+// Config sets the traffic the generator produces.
+type Config struct {
+	// Rate is the number of orders submitted per second
+	Rate float64
+	// FillDelay is the time between an order's broadcast and its TXHASH
+	FillDelay time.Duration
+	// SrcChain and DstChain are the EVM chains the orders swap between, both
+	// served by the same mock client
+	SrcChain common.ChainID
+	DstChain common.ChainID
+}
+
+// Generator submits synthetic orders to a Manager built on mock chains.
+type Generator struct {
+	cfg     Config
+	manager *manager.Manager
+	evm     *mock.EVMClient
+	logger  *log.Logger
+
+	// factory emitting the fabricated escrow deployments, and the resolver deploying them
+	factory ethcommon.Address
+	taker   ethcommon.Address
+
+	orders   atomic.Uint64
+	fills    atomic.Uint64
+	failures atomic.Uint64
+}
+
+// New creates a generator for m, whose EVM client must be evm. It answers the
+// contract calls of verification on evm: escrow addresses and balances.
+func New(m *manager.Manager, evm *mock.EVMClient, cfg Config, logger *log.Logger) *Generator {
+	if cfg.FillDelay == 0 {
+		cfg.FillDelay = DefaultFillDelay
+	}
+	if cfg.SrcChain == 0 {
+		cfg.SrcChain = common.EthereumMainnet
+	}
+	if cfg.DstChain == 0 {
+		cfg.DstChain = common.Base
+	}
+
+	evm.CallFunc = func(call ethereum.CallMsg) ([]byte, error) {
+		if len(call.Data) >= 4 && bytes.Equal(call.Data[:4], balanceOfSelector) {
+			// dst escrows hold everything asked of them
+			return ethcommon.LeftPadBytes(new(big.Int).Lsh(big.NewInt(1), 128).Bytes(), 32), nil
+		}
+		// addressOfEscrowSrc, any deterministic address does
+		return ethcommon.LeftPadBytes(crypto.Keccak256(call.Data)[12:], 32), nil
+	}
+
+	return &Generator{
+		cfg:     cfg,
+		manager: m,
+		evm:     evm,
+		logger:  logger,
+		factory: randomAddress(),
+		taker:   randomAddress(),
+	}
+}
+
+// Run submits orders at the configured rate until ctx is done, then waits
+// for the fills in flight.
+func (g *Generator) Run(ctx context.Context) error {
+	if g.cfg.Rate <= 0 {
+		return fmt.Errorf("rate must be positive, got %v", g.cfg.Rate)
+	}
+	g.logger.Printf("Load test: %.1f orders/s, %s -> %s, filled after %s", g.cfg.Rate, g.cfg.SrcChain, g.cfg.DstChain, g.cfg.FillDelay)
+
+	ticker := time.NewTicker(time.Duration(float64(time.Second) / g.cfg.Rate))
+	defer ticker.Stop()
+	report := time.NewTicker(ReportInterval)
+	defer report.Stop()
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for {
+		select {
+		case <-ctx.Done():
+			g.report()
+			return nil
+		case <-report.C:
+			g.report()
+		case <-ticker.C:
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if err := g.order(ctx); err != nil {
+					g.failures.Add(1)
+					g.logger.Printf("Load test order failed: %v", err)
+				}
+			}()
+		}
+	}
+}
+
+func (g *Generator) report() {
+	g.logger.Printf("Load test: %d orders, %d fills verified, %d failed", g.orders.Load(), g.fills.Load(), g.failures.Load())
+}
+
+// order submits one quote and order and fills it after FillDelay.
+func (g *Generator) order(ctx context.Context) error {
+	quote := g.quote()
+	if err := g.manager.SetQuote(quote); err != nil {
+		return fmt.Errorf("storing quote: %w", err)
+	}
+
+	order := common.Order{
+		SrcChainID: g.cfg.SrcChain,
+		LimitOrder: common.LimitOrder{
+			Salt:         new(big.Int).SetBytes(randomBytes(16)).String(),
+			Maker:        randomAddress().Hex(),
+			Receiver:     ethcommon.Address{}.Hex(),
+			MakerAsset:   quote.QuoteRequest.SrcTokenAddress,
+			TakerAsset:   quote.QuoteRequest.DstTokenAddress,
+			MakingAmount: quote.Quote.SrcTokenAmount,
+			TakingAmount: quote.Quote.DstTokenAmount,
+			MakerTraits:  "0",
+		},
+		QuoteID: quote.QuoteID,
+	}
+	orderHash, err := hash.GetOrderHashForLimitOrder(order.SrcChainID, order.LimitOrder)
+	if err != nil {
+		return fmt.Errorf("hashing order: %w", err)
+	}
+
+	// as the API stores a verified submission
+	submittedAt := time.Now()
+	preset := quote.Quote.Presets[quote.Quote.RecommendedPreset]
+	orderEntry := &manager.OrderEntry{
+		OrderType: manager.SingleFill,
+		OrderHash: orderHash,
+		Order:     &order,
+		OrderStatus: common.OrderStatus{
+			Status:           common.OrderStatusPending,
+			Order:            &order.LimitOrder,
+			Points:           preset.Points,
+			CreatedAt:        submittedAt.Format(time.RFC3339),
+			AuctionStartDate: submittedAt.Unix(),
+			AuctionDuration:  preset.AuctionDuration,
+		},
+		Quote:              quote,
+		SubmittedAt:        submittedAt,
+		FilledMakingAmount: new(big.Int),
+		Escrows:            make(map[string]manager.EscrowSide),
+		Closed:             make(map[string]string),
+		Canonical:          make(map[int]*manager.Verification),
+		Hashlock:           hashlock.Keccak256,
+		Fee:                &manager.OrderFee{ChainID: order.SrcChainID.String(), Token: order.LimitOrder.MakerAsset, Amount: new(big.Int)},
+	}
+	if err := g.manager.DispatchOrder(orderEntry); err != nil {
+		return fmt.Errorf("broadcasting order: %w", err)
+	}
+	g.orders.Add(1)
+
+	select {
+	case <-ctx.Done():
+		return nil
+	case <-time.After(g.cfg.FillDelay):
+	}
+
+	srcTx, dstTx, err := g.deployEscrows(orderEntry)
+	if err != nil {
+		return err
+	}
+	event := fmt.Sprintf("%s %s %s %s", manager.TXHASH_EVENT, orderHash.Hex(), srcTx.Hex(), dstTx.Hex())
+	if err := g.manager.HandleReceiveEvent(nil, []byte(event)); err != nil {
+		return fmt.Errorf("order %s: %w", orderHash.Hex(), err)
+	}
+	g.fills.Add(1)
+	return nil
+}
+
+// quote fabricates a quote for one order, with a flat auction so any fill
+// at the taking amount is accepted.
+func (g *Generator) quote() manager.QuoteEntry {
+	id := uuid.New()
+	making := big.NewInt(makingAmount)
+	taking := new(big.Int).Mul(making, big.NewInt(2))
+
+	return manager.QuoteEntry{
+		QuoteID: id,
+		QuoteRequest: &common.QuoteRequestParams{
+			SrcChain:        g.cfg.SrcChain.String(),
+			DstChain:        g.cfg.DstChain.String(),
+			SrcTokenAddress: randomAddress().Hex(),
+			DstTokenAddress: randomAddress().Hex(),
+			Amount:          making.String(),
+		},
+		Quote: &common.Quote{
+			QuoteID:        id,
+			SrcTokenAmount: making.String(),
+			DstTokenAmount: taking.String(),
+			Presets: common.QuoterPresets{
+				common.PresetFast: {
+					AuctionDuration:    180,
+					AuctionStartAmount: taking.String(),
+					StartAmount:        taking.String(),
+					AuctionEndAmount:   taking.String(),
+					SecretsCount:       1,
+				},
+			},
+			RecommendedPreset: common.PresetFast,
+			TimeLocks: common.TimeLocksRaw{
+				SrcWithdrawal:         12,
+				SrcPublicWithdrawal:   120,
+				SrcCancellation:       300,
+				SrcPublicCancellation: 600,
+				DstWithdrawal:         12,
+				DstPublicWithdrawal:   120,
+				DstCancellation:       300,
+			},
+			SrcSafetyDeposit: "0",
+			DstSafetyDeposit: "0",
+		},
+		ExpiresAt: g.manager.QuoteExpiry(common.PresetFast),
+	}
+}
+
+// deployEscrows fabricates the src and dst escrow deployments of the order's
+// fill on the mock chain and returns their transactions.
+func (g *Generator) deployEscrows(orderEntry *manager.OrderEntry) (ethcommon.Hash, ethcommon.Hash, error) {
+	limitOrder := orderEntry.Order.LimitOrder
+	secret := randomBytes(32)
+	lock := orderEntry.Hashlock.Hash(secret)
+	making, _ := new(big.Int).SetString(limitOrder.MakingAmount, 10)
+	taking, _ := new(big.Int).SetString(limitOrder.TakingAmount, 10)
+	now := uint64(time.Now().Unix())
+
+	src, err := chain.EncodeEvmSrcEscrowCreated(g.factory, chain.EvmSrcEscrowCreatedEvent{
+		SrcImmutables: chain.Immutables{
+			OrderHash: orderEntry.OrderHash,
+			Hashlock:  lock,
+			Maker:     ethcommon.HexToAddress(limitOrder.Maker),
+			Taker:     g.taker,
+			Token:     ethcommon.HexToAddress(limitOrder.MakerAsset),
+			Amount:    making,
+		},
+		DstImmutablesComplement: chain.DstImmutablesComplement{
+			Maker:   ethcommon.HexToAddress(limitOrder.Maker),
+			Amount:  taking,
+			Token:   limitOrder.TakerAsset,
+			ChainId: new(big.Int).SetUint64(uint64(g.cfg.DstChain)),
+		},
+	})
+	if err != nil {
+		return ethcommon.Hash{}, ethcommon.Hash{}, err
+	}
+
+	dstImmutables := chain.Immutables{
+		OrderHash: orderEntry.OrderHash,
+		Hashlock:  lock,
+		Maker:     ethcommon.HexToAddress(limitOrder.Maker),
+		Taker:     g.taker,
+		Token:     ethcommon.HexToAddress(limitOrder.TakerAsset),
+		Amount:    taking,
+	}
+	dst, err := chain.EncodeEvmDstEscrowCreated(g.factory, chain.EvmDstEscrowCreatedEvent{
+		Escrow:   randomAddress(),
+		Hashlock: lock,
+		Taker:    g.taker,
+	})
+	if err != nil {
+		return ethcommon.Hash{}, ethcommon.Hash{}, err
+	}
+	calldata, err := chain.EncodeEvmDstEscrowCalldata(dstImmutables, new(big.Int).SetUint64(now+600))
+	if err != nil {
+		return ethcommon.Hash{}, ethcommon.Hash{}, err
+	}
+
+	srcTx, dstTx := ethcommon.BytesToHash(randomBytes(32)), ethcommon.BytesToHash(randomBytes(32))
+	g.evm.AddReceipt(srcTx, &types.Receipt{Status: types.ReceiptStatusSuccessful, Logs: []*types.Log{src}}, now)
+	g.evm.AddReceipt(dstTx, &types.Receipt{Status: types.ReceiptStatusSuccessful, Logs: []*types.Log{dst}}, now)
+	g.evm.AddTransaction(dstTx, types.NewTx(&types.LegacyTx{To: &g.factory, Data: calldata}))
+
+	return srcTx, dstTx, nil
+}
+
+func randomAddress() ethcommon.Address {
+	return ethcommon.BytesToAddress(randomBytes(20))
+}
+
+func randomBytes(n int) []byte {
+	b := make([]byte, n)
+	rand.Read(b)
+	return b
+}
@@ -0,0 +1,95 @@
+package logging
+
+import (
+	"context"
+	"log"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// attrsKey keys the attributes a context adds to the lines logged under it.
+type attrsKey struct{}
+
+// With returns a child of ctx whose log lines carry args, key-value pairs as
+// taken by slog.Logger.With, on top of those ctx already carries. Lines
+// logged through Logger(ctx, ...) or slog's *Context functions get them, so
+// the lines of concurrent operations can be told apart.
+func With(ctx context.Context, args ...any) context.Context {
+	attrs := attrsFrom(ctx)
+	r := slog.NewRecord(time.Time{}, 0, "", 0)
+	r.Add(args...)
+	added := make([]slog.Attr, 0, len(attrs)+r.NumAttrs())
+	added = append(added, attrs...)
+	r.Attrs(func(a slog.Attr) bool {
+		added = append(added, a)
+		return true
+	})
+	return context.WithValue(ctx, attrsKey{}, added)
+}
+
+func attrsFrom(ctx context.Context) []slog.Attr {
+	attrs, _ := ctx.Value(attrsKey{}).([]slog.Attr)
+	return attrs
+}
+
+// Logger derives from base a logger whose lines carry the attributes of ctx,
+// base itself when ctx carries none. Loggers built by New get them as slog
+// attributes; others, such as those of tests and tools, as a key=value
+// prefix of the message.
+func Logger(ctx context.Context, base *log.Logger) *log.Logger {
+	attrs := attrsFrom(ctx)
+	if len(attrs) == 0 {
+		return base
+	}
+
+	if w, ok := base.Writer().(*handlerWriter); ok {
+		return log.New(&handlerWriter{handler: w.handler, ctx: ctx}, base.Prefix(), base.Flags())
+	}
+
+	var prefix strings.Builder
+	prefix.WriteString(base.Prefix())
+	for _, a := range attrs {
+		prefix.WriteString(a.String())
+		prefix.WriteByte(' ')
+	}
+	return log.New(base.Writer(), prefix.String(), base.Flags()|log.Lmsgprefix)
+}
+
+// contextHandler adds the attributes of the context a record is logged under.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if attrs := attrsFrom(ctx); len(attrs) > 0 {
+		r = r.Clone()
+		r.AddAttrs(attrs...)
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
+
+// handlerWriter writes the lines of a *log.Logger as info records of handler
+// logged under ctx, like slog.NewLogLogger but with a context.
+type handlerWriter struct {
+	handler slog.Handler
+	ctx     context.Context
+}
+
+func (w *handlerWriter) Write(buf []byte) (int, error) {
+	if !w.handler.Enabled(w.ctx, slog.LevelInfo) {
+		return len(buf), nil
+	}
+
+	msg := strings.TrimSuffix(string(buf), "\n")
+	r := slog.NewRecord(time.Now(), slog.LevelInfo, msg, 0)
+	return len(buf), w.handler.Handle(w.ctx, r)
+}
@@ -0,0 +1,57 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// Level is the process-wide minimum log level, shared by every logger built
+// through this package so it can be adjusted at runtime.
+var Level = new(slog.LevelVar)
+
+// New builds the root relayer logger. Both the returned *log.Logger (used by the
+// servers and manager) and the default slog logger (used for debug output in
+// leaf packages such as hash and chain) write through the same handler, which
+// adds the attributes of the context set by With, see Logger.
+func New() *log.Logger {
+	if err := SetLevel(os.Getenv("LOG_LEVEL")); err != nil {
+		fmt.Fprintf(os.Stderr, "relayer: %v, falling back to info\n", err)
+	}
+
+	handler := contextHandler{slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: Level}).
+		WithAttrs([]slog.Attr{slog.String("service", "relayer")})}
+	slog.SetDefault(slog.New(handler))
+
+	return log.New(&handlerWriter{handler: handler, ctx: context.Background()}, "", 0)
+}
+
+// SetLevel parses one of debug, info, warn or error (case-insensitive) and
+// applies it. An empty string resets the level to info.
+func SetLevel(level string) error {
+	if level == "" {
+		Level.Set(slog.LevelInfo)
+		return nil
+	}
+
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(strings.ToUpper(level))); err != nil {
+		return fmt.Errorf("invalid log level %q", level)
+	}
+
+	Level.Set(l)
+	return nil
+}
+
+// Redact shortens sensitive hex values (secrets, hashlocks) so that they can be
+// correlated in logs without being reproducible from them.
+func Redact(s string) string {
+	if len(s) <= 12 {
+		return "[redacted]"
+	}
+
+	return s[:6] + "…" + s[len(s)-4:]
+}
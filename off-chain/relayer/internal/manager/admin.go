@@ -0,0 +1,187 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"relayer/internal/chain"
+	"relayer/internal/common"
+	"relayer/internal/network"
+	"sort"
+	"time"
+)
+
+// AdminOrders returns a snapshot of every order the sweeper still tracks,
+// newest first.
+func (m *Manager) AdminOrders() []common.AdminOrder {
+	m.activeMu.Lock()
+	hashes := make([]string, 0, len(m.active))
+	for hash := range m.active {
+		hashes = append(hashes, hash)
+	}
+	m.activeMu.Unlock()
+
+	orders := make([]common.AdminOrder, 0, len(hashes))
+	for _, hash := range hashes {
+		orderEntry, err := m.peekOrder(hash)
+		if err != nil {
+			continue
+		}
+		orders = append(orders, adminOrder(orderEntry, false, false))
+	}
+	sort.Slice(orders, func(i, j int) bool {
+		return orders[i].SubmittedAt.After(orders[j].SubmittedAt)
+	})
+
+	return orders
+}
+
+// AdminOrder returns the full operator view of an order, looking in the
+// archive when it is no longer live.
+func (m *Manager) AdminOrder(orderHash string) (common.AdminOrder, error) {
+	if orderEntry, err := m.GetOrder(orderHash); err == nil {
+		return adminOrder(orderEntry, false, true), nil
+	}
+
+	orderEntry, err := m.GetArchivedOrder(orderHash)
+	if err != nil {
+		return common.AdminOrder{}, err
+	}
+	return adminOrder(orderEntry, true, true), nil
+}
+
+func adminOrder(orderEntry *OrderEntry, archived, detailed bool) common.AdminOrder {
+	orderEntry.Lock()
+	defer orderEntry.Unlock()
+
+	order := common.AdminOrder{
+		OrderHash:          orderEntry.OrderHash.Hex(),
+		OrderType:          string(orderEntry.OrderType),
+		Status:             orderEntry.OrderStatus.Status,
+		QuoteID:            orderEntry.Quote.QuoteID.String(),
+		SubmittedAt:        orderEntry.SubmittedAt,
+		FilledMakingAmount: "0",
+		CancelTx:           orderEntry.OrderStatus.CancelTx,
+		Fills:              append([]common.ReadyToAcceptSecretFill{}, orderEntry.OrderFills...),
+		DstReceiver:        orderEntry.DstReceiver,
+		Hashlock:           string(orderEntry.Hashlock),
+		Archived:           archived,
+		Upstream:           orderEntry.Upstream,
+	}
+	if orderEntry.FilledMakingAmount != nil {
+		order.FilledMakingAmount = orderEntry.FilledMakingAmount.String()
+	}
+	if orderEntry.Order != nil {
+		order.SrcChainID = orderEntry.Order.SrcChainID.String()
+	}
+	if !detailed {
+		return order
+	}
+
+	order.Escrows = make(map[string]string, len(orderEntry.Escrows))
+	for escrow, side := range orderEntry.Escrows {
+		order.Escrows[escrow] = string(side)
+	}
+	if orderEntry.Order != nil {
+		limitOrder := orderEntry.Order.LimitOrder
+		order.LimitOrder = &limitOrder
+		order.Extension = orderEntry.Order.Extension
+		order.SecretHashes = orderEntry.Order.SecretHashes
+	}
+
+	return order
+}
+
+// AdminQuote returns the operator view of a cached quote.
+func (m *Manager) AdminQuote(quoteEntry QuoteEntry) common.AdminQuote {
+	quote := common.AdminQuote{
+		QuoteID:      quoteEntry.QuoteID.String(),
+		FeeBps:       quoteEntry.FeeBps,
+		QuoteRequest: quoteEntry.QuoteRequest,
+		Quote:        quoteEntry.Quote,
+	}
+	if quoteEntry.Leg != nil {
+		quote.ParentID = quoteEntry.Leg.ParentID.String()
+		quote.LegIndex = quoteEntry.Leg.Index
+	}
+	quote.OrderHash, _ = m.conversions.Order(quote.QuoteID)
+
+	return quote
+}
+
+// Reverify drops the cached verification of a fill and checks it again, for
+// fills that were rejected or verified against a lagging RPC node. Resolver
+// ownership is not checked since no resolver is claiming the fill.
+func (m *Manager) Reverify(orderHash, srcTxHash, dstTxHash string) error {
+	orderEntry, err := m.GetOrder(orderHash)
+	if err != nil {
+		return err
+	}
+
+	m.verifyMu.Lock()
+	m.verifications.Delete(verificationKey(orderEntry.OrderHash.Hex(), srcTxHash, dstTxHash, nil))
+	m.verifyMu.Unlock()
+
+	return m.handleTxHashEvent(nil, []string{orderHash, srcTxHash, dstTxHash})
+}
+
+// ForceRelease marks a fill ready to accept its secret without verifying it,
+// ahead of the fills of lower secret indexes if need be.
+func (m *Manager) ForceRelease(orderHash string, fill common.ReadyToAcceptSecretFill) error {
+	orderEntry, err := m.GetOrder(orderHash)
+	if err != nil {
+		return err
+	}
+
+	parts := 1
+	if orderEntry.Order != nil && len(orderEntry.Order.SecretHashes) > 0 {
+		parts = len(orderEntry.Order.SecretHashes)
+	}
+	if fill.Idx < 0 || fill.Idx >= parts {
+		return fmt.Errorf("secret index %d out of range, order has %d secrets", fill.Idx, parts)
+	}
+
+	m.logger.Printf("Forcing secret release for order %s, idx %d", orderHash, fill.Idx)
+	m.allowSecretRelease(orderHash, fill.Idx, fill.SrcEscrowDeployTxHash, fill.DstEscrowDeployTxHash, false)
+	return nil
+}
+
+// ChainHealth probes the endpoints of each EVM chain of the network profile
+// and the Sui one. An endpoint is behind when
+// its head trails the wall clock by more than the chain's headLagThresholds.
+func (m *Manager) ChainHealth(ctx context.Context) []common.ChainHealth {
+	ctx, cancel := context.WithTimeout(ctx, ChainCallTimeout)
+	defer cancel()
+
+	cfg := m.Config()
+	probe := func(name string, check func(context.Context) (chain.Head, error)) common.ChainHealth {
+		start := time.Now()
+		head, err := check(ctx)
+		health := common.ChainHealth{Chain: name, OK: err == nil, Head: head.Number, Latency: time.Since(start).String()}
+		if err != nil {
+			health.Error = err.Error()
+			return health
+		}
+
+		lag := max(time.Since(head.Time), 0)
+		health.Lag = lag.Round(time.Millisecond).String()
+		health.Behind = lag > cfg.HeadLagThreshold(name)
+		return health
+	}
+
+	var health []common.ChainHealth
+	for _, id := range network.Current().Chains {
+		if id.IsMove() {
+			continue
+		}
+		health = append(health, probe(id.String(), func(ctx context.Context) (chain.Head, error) {
+			client, err := m.evm(id)
+			if err != nil {
+				return chain.Head{}, err
+			}
+			return chain.LatestEvmHead(ctx, client)
+		}))
+	}
+	return append(health, probe(common.Sui.String(), func(ctx context.Context) (chain.Head, error) {
+		return chain.LatestMoveHead(ctx, m.suiClient)
+	}))
+}
@@ -0,0 +1,99 @@
+package manager
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"relayer/internal/auction"
+	"relayer/internal/common"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+// BookRoom is the room of the public order book feed. Its messages are
+// targeted, so resolvers on the firehose never see them.
+const BookRoom = "book"
+
+// publicOrderID stands in for the order hash on the public feed, which would
+// otherwise lead to the maker.
+func publicOrderID(orderHash ethcommon.Hash) string {
+	sum := sha256.Sum256(orderHash[:])
+	return hex.EncodeToString(sum[:8])
+}
+
+// publicOrder summarizes an order for the public feed. status is passed in
+// since callers may hold the order lock.
+func publicOrder(orderEntry *OrderEntry, status common.OrderStatusMode) (common.PublicOrder, bool) {
+	quote := orderEntry.Quote
+	if orderEntry.Order == nil || quote.Quote == nil || quote.QuoteRequest == nil {
+		return common.PublicOrder{}, false
+	}
+
+	limitOrder := orderEntry.Order.LimitOrder
+	preset := quote.Quote.Presets[quote.Quote.RecommendedPreset]
+	curve := auction.FromPreset(orderEntry.SubmittedAt, preset)
+
+	return common.PublicOrder{
+		ID:                 publicOrderID(orderEntry.OrderHash),
+		SrcChainID:         orderEntry.Order.SrcChainID.String(),
+		DstChainID:         quote.QuoteRequest.DstChain,
+		SrcToken:           limitOrder.MakerAsset,
+		DstToken:           quote.QuoteRequest.DstTokenAddress,
+		MakingAmount:       limitOrder.MakingAmount,
+		TakingAmount:       limitOrder.TakingAmount,
+		Status:             status,
+		AuctionStartDate:   curve.Start.Unix(),
+		AuctionDuration:    int64(curve.Duration.Seconds()),
+		AuctionStartAmount: preset.AuctionStartAmount,
+		AuctionEndAmount:   preset.AuctionEndAmount,
+		MultipleFills:      orderEntry.OrderType == MultiFill,
+	}, true
+}
+
+// publishBook sends the summary of an order to the public feed:
+// BOOK <PUBLIC_ORDER_JSON>
+func (m *Manager) publishBook(orderEntry *OrderEntry, status common.OrderStatusMode) {
+	summary, ok := publicOrder(orderEntry, status)
+	if !ok {
+		return
+	}
+
+	data, err := json.Marshal(summary)
+	if err != nil {
+		m.logger.Printf("Failed to encode public summary of order %s: %v", orderEntry.OrderHash.Hex(), err)
+		return
+	}
+	m.broadcaster.BroadcastTo(append([]byte(BOOK_EVENT+" "), data...), BookRoom)
+}
+
+// OrderBook returns the summaries of the active orders still open to fills,
+// the snapshot a public feed connection starts from.
+func (m *Manager) OrderBook() []common.PublicOrder {
+	m.activeMu.Lock()
+	hashes := make([]string, 0, len(m.active))
+	for hash := range m.active {
+		hashes = append(hashes, hash)
+	}
+	m.activeMu.Unlock()
+
+	book := make([]common.PublicOrder, 0, len(hashes))
+	for _, hash := range hashes {
+		orderEntry, err := m.peekOrder(hash)
+		if err != nil {
+			continue
+		}
+
+		orderEntry.Lock()
+		status := orderEntry.OrderStatus.Status
+		filled := orderEntry.fullyFilled()
+		orderEntry.Unlock()
+
+		if status != common.OrderStatusPending || filled {
+			continue
+		}
+		if summary, ok := publicOrder(orderEntry, status); ok {
+			book = append(book, summary)
+		}
+	}
+	return book
+}
@@ -2,25 +2,61 @@ package manager
 
 import "sync"
 
+// Message is a single broadcast frame tagged with its position in the stream,
+// so that reconnecting clients can ask for everything after the last one seen.
+// Rooms name the orders and makers the message concerns; a Targeted message
+// is only delivered to receivers that joined one of its rooms. Data is the
+// ProtocolV1 frame, Versions the frames of later versions where they differ.
+type Message struct {
+	Seq      uint64
+	Data     []byte
+	Versions map[int][]byte
+	Rooms    []string
+	Targeted bool
+}
+
+// receiver is a registered client channel. A receiver without rooms gets
+// every untargeted message; once it joins rooms it only gets their messages.
+type receiver struct {
+	ch    chan Message
+	rooms map[string]struct{}
+}
+
+func (r *receiver) wants(msg Message) bool {
+	if len(r.rooms) == 0 {
+		return !msg.Targeted
+	}
+	for _, room := range msg.Rooms {
+		if _, ok := r.rooms[room]; ok {
+			return true
+		}
+	}
+	return false
+}
+
 type Broadcaster struct {
 	mu        *sync.Mutex
 	id        uint64
-	receivers map[uint64]chan []byte
+	seq       uint64
+	receivers map[uint64]*receiver
+	history   []Message
 }
 
 func NewBroadcaster() *Broadcaster {
 	return &Broadcaster{
 		mu:        &sync.Mutex{},
 		id:        0,
-		receivers: make(map[uint64]chan []byte),
+		seq:       0,
+		receivers: make(map[uint64]*receiver),
+		history:   make([]Message, 0, BroadcastHistorySize),
 	}
 }
 
-func (b *Broadcaster) RegisterReceiver(receiver chan []byte) uint64 {
+func (b *Broadcaster) RegisterReceiver(ch chan Message) uint64 {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
-	b.receivers[b.id] = receiver
+	b.receivers[b.id] = &receiver{ch: ch, rooms: make(map[string]struct{})}
 	b.id++
 
 	return b.id - 1
@@ -30,20 +66,86 @@ func (b *Broadcaster) UnregisterReceiver(id uint64) {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
-	if _, exists := b.receivers[id]; exists {
-		close(b.receivers[id])
+	if r, exists := b.receivers[id]; exists {
+		close(r.ch)
 		delete(b.receivers, id)
 	}
 }
 
-func (b *Broadcaster) Broadcast(message []byte) {
+// Join subscribes a receiver to a room and returns how many rooms it is in.
+func (b *Broadcaster) Join(id uint64, room string) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	r, exists := b.receivers[id]
+	if !exists {
+		return 0
+	}
+	r.rooms[room] = struct{}{}
+	return len(r.rooms)
+}
+
+// Rooms returns the rooms a receiver is in.
+func (b *Broadcaster) Rooms(id uint64) []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	r, exists := b.receivers[id]
+	if !exists {
+		return nil
+	}
+	rooms := make([]string, 0, len(r.rooms))
+	for room := range r.rooms {
+		rooms = append(rooms, room)
+	}
+	return rooms
+}
+
+// Leave unsubscribes a receiver from a room.
+func (b *Broadcaster) Leave(id uint64, room string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if r, exists := b.receivers[id]; exists {
+		delete(r.rooms, room)
+	}
+}
+
+// Broadcast sends an untargeted message to every receiver that wants it.
+func (b *Broadcaster) Broadcast(message []byte, rooms ...string) {
+	b.send(Message{Data: message, Rooms: rooms})
+}
+
+// BroadcastVersioned is Broadcast for an event with a rendering per protocol version.
+func (b *Broadcaster) BroadcastVersioned(message []byte, versions map[int][]byte, rooms ...string) {
+	b.send(Message{Data: message, Versions: versions, Rooms: rooms})
+}
+
+// BroadcastTo sends a message only to the receivers in one of rooms.
+func (b *Broadcaster) BroadcastTo(message []byte, rooms ...string) {
+	b.send(Message{Data: message, Rooms: rooms, Targeted: true})
+}
+
+func (b *Broadcaster) send(msg Message) {
 	go func() {
 		b.mu.Lock()
 		defer b.mu.Unlock()
 
-		for _, receiver := range b.receivers {
+		b.seq++
+		msg.Seq = b.seq
+
+		// keep a bounded window of recent messages for replay
+		if len(b.history) == BroadcastHistorySize {
+			b.history = append(b.history[:0], b.history[1:]...)
+		}
+		b.history = append(b.history, msg)
+
+		for _, r := range b.receivers {
+			if !r.wants(msg) {
+				continue
+			}
 			select {
-			case receiver <- message:
+			case r.ch <- msg:
 			default:
 				// If the channel is full, we skip sending the message
 				// to avoid blocking the broadcaster.
@@ -52,12 +154,33 @@ func (b *Broadcaster) Broadcast(message []byte) {
 	}()
 }
 
+// Replay returns the retained messages for receiver id with a sequence
+// number greater than since, oldest first.
+func (b *Broadcaster) Replay(id uint64, since uint64) []Message {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	r, exists := b.receivers[id]
+	if !exists {
+		return nil
+	}
+
+	out := make([]Message, 0)
+	for _, msg := range b.history {
+		if msg.Seq > since && r.wants(msg) {
+			out = append(out, msg)
+		}
+	}
+
+	return out
+}
+
 func (b *Broadcaster) Close() {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
-	for id, receiver := range b.receivers {
-		close(receiver)
+	for id, r := range b.receivers {
+		close(r.ch)
 		delete(b.receivers, id)
 	}
 
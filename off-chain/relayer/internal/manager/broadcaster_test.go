@@ -0,0 +1,63 @@
+package manager
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// broadcastReceivers is the number of connections a benchmarked broadcast
+// fans out to.
+const broadcastReceivers = 256
+
+// fanout registers broadcastReceivers receivers, each joining the rooms
+// join returns for it, and returns the group their deliveries are done in.
+// Closing the broadcaster stops the receivers.
+func fanout(join func(i int) []string) (*Broadcaster, *sync.WaitGroup) {
+	broadcaster := NewBroadcaster()
+
+	wg := new(sync.WaitGroup)
+	for i := 0; i < broadcastReceivers; i++ {
+		ch := make(chan Message, 1)
+		id := broadcaster.RegisterReceiver(ch)
+		for _, room := range join(i) {
+			broadcaster.Join(id, room)
+		}
+		go func() {
+			for range ch {
+				wg.Done()
+			}
+		}()
+	}
+
+	return broadcaster, wg
+}
+
+func BenchmarkBroadcastFanout(b *testing.B) {
+	broadcaster, delivered := fanout(func(int) []string { return nil })
+	defer broadcaster.Close()
+	frame := []byte(`ORDER_CREATED {"orderHash":"0x01"}`)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// one message in flight at a time, so no receiver's channel is full
+		delivered.Add(broadcastReceivers)
+		broadcaster.Broadcast(frame)
+		delivered.Wait()
+	}
+}
+
+func BenchmarkBroadcastTargeted(b *testing.B) {
+	broadcaster, delivered := fanout(func(i int) []string { return []string{OrderRoom(fmt.Sprint(i))} })
+	defer broadcaster.Close()
+	frame := []byte(`SECRET_RELEASED 0x01`)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		delivered.Add(1)
+		broadcaster.BroadcastTo(frame, OrderRoom(fmt.Sprint(i%broadcastReceivers)))
+		delivered.Wait()
+	}
+}
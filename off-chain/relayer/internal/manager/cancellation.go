@@ -0,0 +1,135 @@
+package manager
+
+import (
+	"fmt"
+	"math/big"
+	"relayer/internal/chain"
+	"relayer/internal/common"
+	"sort"
+	"strings"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+// CancellationData returns what the maker needs to cancel the escrows of
+// the order's verified fills on chain, and which stage each escrow is in at now.
+func (m *Manager) CancellationData(orderHash string, now time.Time) (common.CancellationData, error) {
+	orderEntry, err := m.GetOrder(orderHash)
+	if err != nil {
+		if orderEntry, err = m.GetArchivedOrder(orderHash); err != nil {
+			return common.CancellationData{}, err
+		}
+	}
+
+	locks := orderTimelocks(orderEntry)
+	srcChain, dstChain := orderChains(orderEntry)
+
+	orderEntry.Lock()
+	defer orderEntry.Unlock()
+
+	data := common.CancellationData{
+		OrderHash: orderEntry.OrderHash.Hex(),
+		Status:    orderEntry.OrderStatus.Status,
+		Fills:     make([]common.FillCancellation, 0, len(orderEntry.Canonical)),
+	}
+	for idx, v := range orderEntry.Canonical {
+		src := common.EscrowCancellation{
+			ChainID:              srcChain,
+			Escrow:               v.SrcEscrow,
+			DeployTx:             v.SrcTxHash,
+			Immutables:           escrowImmutables(v.SrcImmutables),
+			Window:               srcWindow(locks, now.Sub(v.SrcTimestamp.Time)),
+			CancellationAt:       v.SrcTimestamp.Add(locks.srcCancellation).Unix(),
+			PublicCancellationAt: v.SrcTimestamp.Add(locks.srcPublicCancellation).Unix(),
+			ClosedTx:             orderEntry.Closed[strings.ToLower(v.SrcEscrow)],
+		}
+		dst := common.EscrowCancellation{
+			ChainID:        dstChain,
+			Escrow:         v.DstEscrow,
+			DeployTx:       v.DstTxHash,
+			Immutables:     escrowImmutables(v.DstImmutables),
+			Window:         dstWindow(locks, now.Sub(v.DstTimestamp.Time)),
+			CancellationAt: v.DstTimestamp.Add(locks.dstCancellation).Unix(),
+			ClosedTx:       orderEntry.Closed[strings.ToLower(v.DstEscrow)],
+		}
+		for _, e := range []*common.EscrowCancellation{&src, &dst} {
+			if e.ClosedTx != "" {
+				e.Window = common.WindowClosed
+			}
+		}
+		data.Fills = append(data.Fills, common.FillCancellation{Idx: idx, Src: src, Dst: dst})
+	}
+	sort.Slice(data.Fills, func(i, j int) bool { return data.Fills[i].Idx < data.Fills[j].Idx })
+
+	return data, nil
+}
+
+// srcWindow is the stage of a src escrow deployed elapsed ago.
+func srcWindow(locks timelocks, elapsed time.Duration) common.EscrowWindow {
+	switch {
+	case elapsed >= locks.srcPublicCancellation:
+		return common.WindowPublicCancellation
+	case elapsed >= locks.srcCancellation:
+		return common.WindowCancellation
+	case elapsed >= locks.srcPublicWithdrawal:
+		return common.WindowPublicWithdrawal
+	case elapsed >= locks.srcWithdrawal:
+		return common.WindowWithdrawal
+	default:
+		return common.WindowFinality
+	}
+}
+
+// dstWindow is the stage of a dst escrow deployed elapsed ago. Dst escrows
+// have no public cancellation.
+func dstWindow(locks timelocks, elapsed time.Duration) common.EscrowWindow {
+	switch {
+	case elapsed >= locks.dstCancellation:
+		return common.WindowCancellation
+	case elapsed >= locks.dstPublicWithdrawal:
+		return common.WindowPublicWithdrawal
+	case elapsed >= locks.dstWithdrawal:
+		return common.WindowWithdrawal
+	default:
+		return common.WindowFinality
+	}
+}
+
+func escrowImmutables(i *chain.Immutables) *common.EscrowImmutables {
+	if i == nil {
+		return nil
+	}
+	return &common.EscrowImmutables{
+		OrderHash:     i.OrderHash.Hex(),
+		Hashlock:      i.Hashlock.Hex(),
+		Maker:         i.Maker.Hex(),
+		Taker:         i.Taker.Hex(),
+		Token:         i.Token.Hex(),
+		Amount:        i.Amount.String(),
+		SafetyDeposit: i.SafetyDeposit.String(),
+		Timelocks:     i.Timelocks.String(),
+	}
+}
+
+// chainImmutables decodes immutables recorded with escrowImmutables.
+func chainImmutables(i *common.EscrowImmutables) (*chain.Immutables, error) {
+	amounts := make([]*big.Int, 3)
+	for n, s := range []string{i.Amount, i.SafetyDeposit, i.Timelocks} {
+		amount, ok := new(big.Int).SetString(s, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid immutable %q", s)
+		}
+		amounts[n] = amount
+	}
+	return &chain.Immutables{
+		OrderHash:     ethcommon.HexToHash(i.OrderHash),
+		Hashlock:      ethcommon.HexToHash(i.Hashlock),
+		Maker:         ethcommon.HexToAddress(i.Maker),
+		Taker:         ethcommon.HexToAddress(i.Taker),
+		Token:         ethcommon.HexToAddress(i.Token),
+		Amount:        amounts[0],
+		SafetyDeposit: amounts[1],
+		Timelocks:     amounts[2],
+	}, nil
+}
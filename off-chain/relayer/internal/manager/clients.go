@@ -0,0 +1,87 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"relayer/internal/chain"
+	"relayer/internal/common"
+	"relayer/internal/network"
+)
+
+// evmWSURLs returns the mempool endpoints of the EVM chains of the network
+// profile, EVM_WS_URL_<chain id>. Without any, EVM_WS_URL is the endpoint of
+// the profile's first EVM chain.
+func evmWSURLs() map[common.ChainID]string {
+	urls := make(map[common.ChainID]string)
+	var first common.ChainID
+	for _, id := range network.Current().Chains {
+		if id.IsMove() {
+			continue
+		}
+		if first == 0 {
+			first = id
+		}
+		if url := os.Getenv(fmt.Sprintf("EVM_WS_URL_%d", id)); url != "" {
+			urls[id] = url
+		}
+	}
+	if url := os.Getenv("EVM_WS_URL"); len(urls) == 0 && url != "" && first != 0 {
+		urls[first] = url
+	}
+	return urls
+}
+
+// evm returns the client of the EVM chain chainID, an error for chains the
+// relayer has no endpoint of.
+func (m *Manager) evm(chainID common.ChainID) (chain.EVMClient, error) {
+	client, ok := m.evmClients[chainID]
+	if !ok {
+		return nil, fmt.Errorf("no RPC endpoint for chain %d, set EVM_RPC_URL_%d", chainID, chainID)
+	}
+	return client, nil
+}
+
+// firstEvm returns the client of the first EVM chain of the network profile
+// the relayer has an endpoint of.
+func (m *Manager) firstEvm() (chain.EVMClient, error) {
+	for _, id := range network.Current().Chains {
+		if client, ok := m.evmClients[id]; ok {
+			return client, nil
+		}
+	}
+	return nil, errors.New("no EVM RPC endpoint")
+}
+
+// escrowChain returns the chain of the order's escrows on side.
+func escrowChain(orderEntry *OrderEntry, side EscrowSide) common.ChainID {
+	if side == SrcEscrow {
+		return orderEntry.Order.SrcChainID
+	}
+	if orderEntry.Quote.QuoteRequest == nil {
+		return 0
+	}
+	id, _ := common.ParseChainID(orderEntry.Quote.QuoteRequest.DstChain)
+	return id
+}
+
+// onOrderEvm runs fetch with the clients of the order's EVM chains, src
+// first, until one finds the transaction fetch looks up.
+func (m *Manager) onOrderEvm(orderEntry *OrderEntry, fetch func(chain.EVMClient) error) error {
+	err := errors.New("order has no EVM chain")
+	for _, side := range []EscrowSide{SrcEscrow, DstEscrow} {
+		id := escrowChain(orderEntry, side)
+		if id == 0 || id.IsMove() {
+			continue
+		}
+		client, clientErr := m.evm(id)
+		if clientErr != nil {
+			err = clientErr
+			continue
+		}
+		if err = fetch(client); !errors.Is(err, chain.ErrTxNotFound) {
+			return err
+		}
+	}
+	return err
+}
@@ -0,0 +1,85 @@
+package manager
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// claimCanonical records v as the fill of its secret index. When another
+// resolver's dst escrow was already verified for the index, the one deployed
+// first on chain stays canonical, or the one reported first when the chains'
+// timestamps cannot tell them apart. The losing escrow's owner is advised to
+// cancel it; a losing v is rejected. The canonical fill of an index holds
+// its portion of the order, so a v displacing another takes it over.
+func (m *Manager) claimCanonical(orderEntry *OrderEntry, v *Verification) error {
+	orderEntry.Lock()
+	current, ok := orderEntry.Canonical[v.HashIdx]
+	if !ok {
+		if err := claimFillPortion(orderEntry, v.HashIdx, v.SrcAmount); err != nil {
+			orderEntry.Unlock()
+			return err
+		}
+		orderEntry.Canonical[v.HashIdx] = v
+		orderEntry.Unlock()
+		return nil
+	}
+
+	if current.DstEscrow == v.DstEscrow {
+		orderEntry.Unlock()
+		return nil
+	}
+
+	winner, loser := current, v
+	if v.DstTimestamp.Latest().Before(current.DstTimestamp.Earliest()) {
+		// v's portion replaces current's, which may differ in amount
+		if err := reclaimFillPortion(orderEntry, current, v); err != nil {
+			orderEntry.Unlock()
+			return err
+		}
+		winner, loser = v, current
+		orderEntry.Canonical[v.HashIdx] = v
+	}
+	orderEntry.Unlock()
+
+	m.logger.Printf("Competing dst escrows for order %s, secret %d: %s is canonical, %s should be cancelled",
+		v.OrderHash, v.HashIdx, winner.DstEscrow, loser.DstEscrow)
+	msg := fmt.Sprintf("%s %s %d %s %s", CANCEL_ADVICE_EVENT, v.OrderHash, v.HashIdx, loser.DstEscrow, winner.DstEscrow)
+	m.broadcaster.Broadcast([]byte(msg), orderRooms(orderEntry)...)
+
+	if loser == v {
+		return fmt.Errorf("dst escrow %s competes with %s, deployed first, for secret %d: cancel it", v.DstEscrow, winner.DstEscrow, v.HashIdx)
+	}
+	return nil
+}
+
+// reclaimFillPortion releases the portion of the order claimed by current
+// and claims winner's instead, keeping current's if winner's is invalid. The
+// caller holds the entry's lock.
+func reclaimFillPortion(orderEntry *OrderEntry, current, winner *Verification) error {
+	if orderEntry.OrderType != MultiFill {
+		return nil
+	}
+
+	filled := new(big.Int).Set(orderEntry.FilledMakingAmount)
+	orderEntry.FilledMakingAmount.Sub(orderEntry.FilledMakingAmount, current.SrcAmount)
+	if err := claimFillPortion(orderEntry, winner.HashIdx, winner.SrcAmount); err != nil {
+		orderEntry.FilledMakingAmount.Set(filled)
+		return err
+	}
+	return nil
+}
+
+// isCanonical reports whether the fill with the dst escrow deployed in
+// dstTxHash is still the fill of its secret index.
+func (m *Manager) isCanonical(orderHash string, hashIdx int, dstTxHash string) bool {
+	orderEntry, err := m.GetOrder(orderHash)
+	if err != nil {
+		return false
+	}
+
+	orderEntry.Lock()
+	defer orderEntry.Unlock()
+
+	current, ok := orderEntry.Canonical[hashIdx]
+	return !ok || current.DstTxHash == dstTxHash
+}
@@ -0,0 +1,52 @@
+package manager
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestReclaimFillPortion(t *testing.T) {
+	// the first two parts verified, the second of them by a dst escrow
+	// deployed after a competitor's, which is to be cancelled
+	current := &Verification{HashIdx: 1, SrcAmount: big.NewInt(25)}
+
+	tests := []struct {
+		name       string
+		winner     *Verification
+		wantErr    bool
+		wantFilled int64
+	}{
+		{name: "same amount", winner: &Verification{HashIdx: 1, SrcAmount: big.NewInt(25)}, wantFilled: 50},
+		{name: "smaller amount", winner: &Verification{HashIdx: 1, SrcAmount: big.NewInt(10)}, wantFilled: 35},
+		{name: "amount of another part", winner: &Verification{HashIdx: 1, SrcAmount: big.NewInt(30)}, wantErr: true},
+		{name: "overfill", winner: &Verification{HashIdx: 1, SrcAmount: big.NewInt(80)}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orderEntry := partsOrder(50, "")
+			err := reclaimFillPortion(orderEntry, current, tt.winner)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("reclaimed the portion")
+				}
+				// current keeps its portion
+				if got := orderEntry.FilledMakingAmount.Int64(); got != 50 {
+					t.Fatalf("filled %d after a failed reclaim, want 50", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := orderEntry.FilledMakingAmount.Int64(); got != tt.wantFilled {
+				t.Fatalf("filled %d, want %d", got, tt.wantFilled)
+			}
+
+			// the rest of the order fills after the reclaimed portion
+			rest := big.NewInt(100 - tt.wantFilled)
+			if err := claimFillPortion(orderEntry, 4, rest); err != nil {
+				t.Fatalf("completing after the reclaim: %v", err)
+			}
+		})
+	}
+}
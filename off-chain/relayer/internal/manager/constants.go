@@ -4,12 +4,66 @@ import (
 	"time"
 )
 
-// QuoteTTL defines the time-to-live for quotes in the manager
+// QuoteExpiredGrace is how long a quote is kept after it expires so late
+// orders get a QUOTE_EXPIRED error instead of an unknown quote
 const (
-	QuoteTTL        = time.Minute * 15
-	SecretTTLBuffer = time.Second * 2
+	QuoteExpiredGrace = time.Minute * 15
+	SecretTTLBuffer   = time.Second * 2
 )
 
+// ChainCallTimeout bounds a single round of RPC calls made while handling an event
+const ChainCallTimeout = time.Second * 30
+
+// VerificationCacheTTL is how long a verified TXHASH tuple is remembered so
+// resends are answered from cache
+const VerificationCacheTTL = time.Hour
+
+// RPCAlertInterval is how often fill verifications failing on an unavailable
+// endpoint alert about it
+const RPCAlertInterval = time.Minute * 15
+
+// StoreTimeout bounds a single write to the persistent store
+const StoreTimeout = time.Second * 5
+
+// MultiHopTTL bounds how long the linked orders of a multi-hop quote are tracked
+const MultiHopTTL = time.Hour * 24
+
+// SweepInterval is how often unfilled orders past their auction end are expired
+const SweepInterval = time.Second * 30
+
+// ReconcileInterval is how often the escrows of active orders are re-scanned
+// for withdrawals and cancellations nobody reported
+const ReconcileInterval = time.Minute * 5
+
+// HeadPollInterval is how often chain heads are polled for lag metrics and alerts
+const HeadPollInterval = time.Second * 15
+
+// PrewarmTTL is how long a pending fill transaction spotted in the mempool is
+// tracked, and its decoded escrow creation kept for the fill's TXHASH
+const PrewarmTTL = time.Minute * 10
+
+// PrewarmPollInterval is how often the receipt of a pending fill transaction
+// is polled for until it is mined or PrewarmTTL passes
+const PrewarmPollInterval = time.Second
+
+// PrewarmWorkers bounds the pending fill transactions prewarmed at once, and
+// MaxPrewarmed those tracked; further ones are skipped until some are done
+const (
+	PrewarmWorkers = 16
+	MaxPrewarmed   = 1024
+)
+
+// NeedleRefresh is how often the order hashes and hashlocks pending
+// transactions are matched against are rebuilt from the active orders
+const NeedleRefresh = time.Second * 2
+
+// ArchiveTTL is how long expired orders stay queryable after being swept
+const ArchiveTTL = time.Hour * 24
+
+// BroadcastHistorySize is the number of recent broadcast messages kept for
+// sequence replay to reconnecting clients
+const BroadcastHistorySize = 1024
+
 // // chainID -> finality lock mapping
 // var FinalityLocks = map[common.ChainID]time.Duration{
 // 	common.EthereumMainnet: time.Minute * 12, // roughly 2 epochs
@@ -0,0 +1,156 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"relayer/internal/common"
+	"relayer/internal/custody"
+	"relayer/internal/hashlock"
+	"relayer/internal/store"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+)
+
+// Custody returns the vault of relayer generated secrets, nil when disabled.
+func (m *Manager) Custody() *custody.Vault {
+	return m.custody
+}
+
+// BindSecrets ties the secret set an order was built with to the order, so the
+// relayer can reveal its secrets per the set's policy.
+func (m *Manager) BindSecrets(orderEntry *OrderEntry) error {
+	if orderEntry.SecretsID == "" {
+		return nil
+	}
+	if !m.custody.Enabled() {
+		return custody.ErrDisabled
+	}
+
+	var escrowHashlock ethcommon.Hash
+	if orderEntry.Extension != nil {
+		escrowHashlock = orderEntry.Extension.Escrow.Hashlock
+	}
+
+	set, err := m.custody.Get(orderEntry.SecretsID)
+	if err != nil {
+		return err
+	}
+	if set.Algorithm != orderEntry.Hashlock {
+		return fmt.Errorf("secret set uses %s hashlocks, the route %s", set.Algorithm, orderEntry.Hashlock)
+	}
+
+	_, err = m.custody.Bind(orderEntry.SecretsID, orderEntry.OrderHash.Hex(), orderEntry.Order.SecretHashes, escrowHashlock)
+	return err
+}
+
+// revealCustodial reveals the secret of a fill whose escrows are final, when
+// the order's secrets are held by the relayer under the finality policy.
+func (m *Manager) revealCustodial(orderHash, secretsID string, hashIdx int) {
+	set, err := m.custody.Get(secretsID)
+	if err != nil {
+		m.logger.Printf("Custodial secrets of order %s unavailable: %v", orderHash, err)
+		return
+	}
+	if set.Policy != custody.ReleaseOnFinality {
+		return
+	}
+
+	secret, err := m.custody.Secret(secretsID, hashIdx)
+	if err != nil {
+		m.logger.Printf("Failed to open secret %d of order %s: %v", hashIdx, orderHash, err)
+		return
+	}
+
+	if orderEntry, err := m.GetOrder(orderHash); err == nil {
+		if err := m.dryRunWithdraw(orderEntry, hashIdx, secret); err != nil {
+			m.logger.Printf("Withholding secret %d of order %s: %v", hashIdx, orderHash, err)
+			return
+		}
+	}
+
+	if err := m.HandleSecretEvent(common.Secret{OrderHash: orderHash, Secret: secret}); err != nil {
+		m.logger.Printf("Failed to reveal secret %d of order %s: %v", hashIdx, orderHash, err)
+		return
+	}
+	m.logger.Printf("Revealed custodial secret %d of order %s", hashIdx, orderHash)
+}
+
+// restoreSecretSets loads the custodial secret sets of the store, so the
+// releases restored after them can still reveal their secrets. A set sealed
+// under another key cannot be revealed, and the relayer refuses to start
+// without it.
+func (m *Manager) restoreSecretSets() {
+	ctx, cancel := context.WithTimeout(context.Background(), StoreTimeout)
+	defer cancel()
+
+	n, err := m.custody.Restore(ctx)
+	if err != nil {
+		m.logger.Fatalf("Failed to restore custodial secret sets: %v", err)
+	}
+	if n > 0 {
+		m.logger.Printf("Restored %d custodial secret sets", n)
+	}
+}
+
+// storeSecretSets keeps the sets of the custody vault in the store.
+type storeSecretSets struct {
+	db *store.Store
+}
+
+func (s storeSecretSets) SaveSet(ctx context.Context, rec custody.Record) error {
+	hashes := make([]string, len(rec.SecretHashes))
+	for i, h := range rec.SecretHashes {
+		hashes[i] = h.Hex()
+	}
+
+	return s.db.PutSecretSet(ctx, store.SecretSetRecord{
+		ID:           rec.ID.String(),
+		Algorithm:    string(rec.Algorithm),
+		Policy:       string(rec.Policy),
+		Hashlock:     rec.Hashlock.Hex(),
+		SecretHashes: hashes,
+		Sealed:       rec.Sealed,
+		ExportToken:  rec.ExportToken[:],
+		OrderHash:    rec.OrderHash,
+		CreatedAt:    rec.CreatedAt,
+	})
+}
+
+// LoadSets also drops the sets created before since, expired for good.
+func (s storeSecretSets) LoadSets(ctx context.Context, since time.Time) ([]custody.Record, error) {
+	if err := s.db.DeleteSecretSets(ctx, since); err != nil {
+		return nil, err
+	}
+	stored, err := s.db.SecretSets(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+
+	recs := make([]custody.Record, 0, len(stored))
+	for _, st := range stored {
+		id, err := uuid.Parse(st.ID)
+		if err != nil {
+			return nil, fmt.Errorf("secret set %s: %w", st.ID, err)
+		}
+		if len(st.ExportToken) != 32 {
+			return nil, fmt.Errorf("secret set %s has a %d byte export token", st.ID, len(st.ExportToken))
+		}
+		hashes := make([]ethcommon.Hash, len(st.SecretHashes))
+		for i, h := range st.SecretHashes {
+			hashes[i] = ethcommon.HexToHash(h)
+		}
+
+		rec := custody.Record{Sealed: st.Sealed, ExportToken: [32]byte(st.ExportToken)}
+		rec.ID = id
+		rec.Algorithm = hashlock.Algorithm(st.Algorithm)
+		rec.Policy = custody.Policy(st.Policy)
+		rec.Hashlock = ethcommon.HexToHash(st.Hashlock)
+		rec.SecretHashes = hashes
+		rec.OrderHash = st.OrderHash
+		rec.CreatedAt = st.CreatedAt
+		recs = append(recs, rec)
+	}
+	return recs, nil
+}
@@ -0,0 +1,120 @@
+package manager
+
+import (
+	"relayer/internal/auction"
+	"time"
+
+	"github.com/imkira/go-ttlmap"
+)
+
+// timelocks are the escrow timelocks of an order, as offsets from each
+// escrow's deployment.
+type timelocks struct {
+	srcWithdrawal         time.Duration
+	srcPublicWithdrawal   time.Duration
+	srcCancellation       time.Duration
+	srcPublicCancellation time.Duration
+	dstWithdrawal         time.Duration
+	dstPublicWithdrawal   time.Duration
+	dstCancellation       time.Duration
+}
+
+// orderTimelocks returns the timelocks of an order: from its signed
+// extension, which is what the escrows encode, or from its quote for orders
+// without one.
+func orderTimelocks(orderEntry *OrderEntry) timelocks {
+	if ext := orderEntry.Extension; ext != nil {
+		t := ext.Escrow.Timelocks
+		return timelocks{
+			srcWithdrawal:         time.Duration(t.SrcWithdrawal) * time.Second,
+			srcPublicWithdrawal:   time.Duration(t.SrcPublicWithdrawal) * time.Second,
+			srcCancellation:       time.Duration(t.SrcCancellation) * time.Second,
+			srcPublicCancellation: time.Duration(t.SrcPublicCancellation) * time.Second,
+			dstWithdrawal:         time.Duration(t.DstWithdrawal) * time.Second,
+			dstPublicWithdrawal:   time.Duration(t.DstPublicWithdrawal) * time.Second,
+			dstCancellation:       time.Duration(t.DstCancellation) * time.Second,
+		}
+	}
+
+	if quote := orderEntry.Quote.Quote; quote != nil {
+		t := quote.TimeLocks
+		return timelocks{
+			srcWithdrawal:         time.Duration(t.SrcWithdrawal) * time.Second,
+			srcPublicWithdrawal:   time.Duration(t.SrcPublicWithdrawal) * time.Second,
+			srcCancellation:       time.Duration(t.SrcCancellation) * time.Second,
+			srcPublicCancellation: time.Duration(t.SrcPublicCancellation) * time.Second,
+			dstWithdrawal:         time.Duration(t.DstWithdrawal) * time.Second,
+			dstPublicWithdrawal:   time.Duration(t.DstPublicWithdrawal) * time.Second,
+			dstCancellation:       time.Duration(t.DstCancellation) * time.Second,
+		}
+	}
+	return timelocks{}
+}
+
+// orderDeadline is when an order can be dropped: once anyone may cancel the
+// escrows of its verified fills. Until the order is completely filled, an
+// escrow may still be deployed as late as the auction end.
+func orderDeadline(orderEntry *OrderEntry) time.Time {
+	deadline := orderEntry.EscrowDeadline
+	if orderEntry.fullyFilled() {
+		return deadline
+	}
+
+	end := orderEntry.SubmittedAt
+	if quote := orderEntry.Quote.Quote; quote != nil {
+		end = auction.FromPreset(orderEntry.SubmittedAt, quote.Presets[quote.RecommendedPreset]).End()
+	}
+
+	if latest := end.Add(orderTimelocks(orderEntry).srcPublicCancellation); latest.After(deadline) {
+		deadline = latest
+	}
+	return deadline
+}
+
+// fullyFilled reports whether no more escrows can be deployed for the order.
+// Must be called with the entry's lock held, or before the order is stored.
+func (orderEntry *OrderEntry) fullyFilled() bool {
+	if orderEntry.OrderType != MultiFill {
+		return len(orderEntry.Escrows) > 0
+	}
+
+	making, err := orderEntry.Order.SrcChainID.ParseAmount(orderEntry.Order.LimitOrder.MakingAmount)
+	return err == nil && orderEntry.FilledMakingAmount != nil && orderEntry.FilledMakingAmount.Cmp(making) >= 0
+}
+
+// escrowDeadline is when anyone may cancel both escrows of a verified fill,
+// counted from their deployment on chain.
+func escrowDeadline(orderEntry *OrderEntry, v *Verification) time.Time {
+	locks := orderTimelocks(orderEntry)
+
+	// the escrows count their timelocks from the deployment block itself
+	deadline := v.SrcTimestamp.Time.Add(locks.srcPublicCancellation)
+	if dst := v.DstTimestamp.Time.Add(locks.dstCancellation); dst.After(deadline) {
+		deadline = dst
+	}
+	return deadline
+}
+
+// adjustDeadline recomputes the TTL of a stored order after one of its fills
+// was verified, from the deployment time of the fill's escrows.
+func (m *Manager) adjustDeadline(orderHash string, v *Verification) {
+	orderEntry, err := m.GetOrder(orderHash)
+	if err != nil {
+		return
+	}
+
+	orderEntry.Lock()
+	defer orderEntry.Unlock()
+
+	if deadline := escrowDeadline(orderEntry, v); deadline.After(orderEntry.EscrowDeadline) {
+		orderEntry.EscrowDeadline = deadline
+	}
+
+	deadline := orderDeadline(orderEntry)
+	// an update, so that an order swept meanwhile stays gone
+	if _, err := m.orders.Update(orderEntry.OrderHash.String(), ttlmap.NewItem(orderEntry, ttlmap.WithExpiration(deadline)), nil); err != nil {
+		m.logger.Printf("Failed to adjust ttl of order %s: %v", orderHash, err)
+		return
+	}
+	m.logger.Printf("Order %s now expires at %s", orderHash, deadline.Format(time.RFC3339))
+}
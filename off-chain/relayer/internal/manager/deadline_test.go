@@ -0,0 +1,174 @@
+package manager
+
+import (
+	"relayer/internal/chain"
+	"relayer/internal/common"
+	"relayer/internal/config"
+	"testing"
+	"time"
+)
+
+// deployedAt is the block second the src escrows of these tests are deployed in.
+const deployedAt = 1_700_000_000
+
+func deadlineEntry(locks common.TimeLocksRaw) *OrderEntry {
+	return &OrderEntry{
+		Order: &common.Order{SrcChainID: common.EthereumMainnet},
+		Quote: QuoteEntry{
+			QuoteRequest: &common.QuoteRequestParams{DstChain: common.Sui.String()},
+			Quote: &common.Quote{
+				TimeLocks:         locks,
+				RecommendedPreset: common.PresetFast,
+				Presets:           common.QuoterPresets{common.PresetFast: {StartAuctionIn: 10, AuctionDuration: 120}},
+			},
+		},
+		SubmittedAt: time.Unix(deployedAt, 0),
+	}
+}
+
+func finalityManager(t *testing.T, src, dst time.Duration) *Manager {
+	t.Helper()
+	cfg, err := config.NewStore("", map[string]config.Duration{
+		common.EthereumMainnet.String(): config.Duration(src),
+		common.Sui.String():             config.Duration(dst),
+	})
+	if err != nil {
+		t.Fatalf("config.NewStore: %v", err)
+	}
+	return &Manager{config: cfg}
+}
+
+func TestEscrowDeadline(t *testing.T) {
+	src := chain.EvmEventTime(deployedAt)
+	at := time.Unix(deployedAt, 0)
+	locks := common.TimeLocksRaw{SrcPublicCancellation: 600, DstCancellation: 500}
+
+	tests := []struct {
+		name  string
+		locks common.TimeLocksRaw
+		dst   chain.EventTime
+		want  time.Time
+	}{
+		{"equal timestamps", locks, chain.MoveEventTime(deployedAt * 1000), at.Add(600 * time.Second)},
+		{"dst skewed past the src deadline", locks, chain.MoveEventTime((deployedAt + 200) * 1000), at.Add(700 * time.Second)},
+		{"dst skewed within the src deadline", locks, chain.MoveEventTime((deployedAt + 50) * 1000), at.Add(600 * time.Second)},
+		{"dst deployed before src", locks, chain.MoveEventTime((deployedAt - 300) * 1000), at.Add(600 * time.Second)},
+		// timelocks count from the block timestamp, not the end of its precision
+		{"dst in the src block second", locks, chain.MoveEventTime(deployedAt*1000 + 999), at.Add(600 * time.Second)},
+		{"dst on the src deadline", common.TimeLocksRaw{SrcPublicCancellation: 600, DstCancellation: 600}, chain.MoveEventTime(deployedAt * 1000), at.Add(600 * time.Second)},
+		{"zero timelocks", common.TimeLocksRaw{}, chain.MoveEventTime(deployedAt*1000 + 1500), at.Add(1500 * time.Millisecond)},
+		{"zero timelocks, dst first", common.TimeLocksRaw{}, chain.MoveEventTime((deployedAt - 1) * 1000), at},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &Verification{SrcTimestamp: src, DstTimestamp: tt.dst}
+			if got := escrowDeadline(deadlineEntry(tt.locks), v); !got.Equal(tt.want) {
+				t.Errorf("escrowDeadline() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReleaseAt(t *testing.T) {
+	src := chain.EvmEventTime(deployedAt)
+	// finality counts from the end of the block second
+	srcFinal := time.Unix(deployedAt+1, 0).Add(-time.Nanosecond)
+	dstAt := func(ms int64) chain.EventTime { return chain.MoveEventTime(deployedAt*1000 + ms) }
+	dstFinal := func(ms int64) time.Time { return time.UnixMilli(deployedAt*1000 + ms + 1).Add(-time.Nanosecond) }
+
+	tests := []struct {
+		name               string
+		srcDelay, dstDelay time.Duration
+		locks              common.TimeLocksRaw
+		dst                chain.EventTime
+		want               time.Time
+	}{
+		{
+			name: "zero finality, equal timestamps",
+			dst:  dstAt(0),
+			want: srcFinal,
+		},
+		{
+			name: "zero finality, dst later in the block second",
+			dst:  dstAt(999),
+			want: dstFinal(999),
+		},
+		{
+			name:     "src finality",
+			srcDelay: time.Minute, dstDelay: 10 * time.Second,
+			dst:  dstAt(0),
+			want: srcFinal.Add(time.Minute),
+		},
+		{
+			name:     "dst skewed past the src finality",
+			srcDelay: time.Minute, dstDelay: 10 * time.Second,
+			dst:  dstAt(100_000),
+			want: dstFinal(100_000).Add(10 * time.Second),
+		},
+		{
+			name:     "dst deployed before src",
+			srcDelay: 10 * time.Second, dstDelay: time.Minute,
+			dst:  dstAt(-30_000),
+			want: dstFinal(-30_000).Add(time.Minute),
+		},
+		{
+			name:     "withdrawal timelocks longer than finality",
+			srcDelay: 10 * time.Second, dstDelay: 10 * time.Second,
+			locks: common.TimeLocksRaw{SrcWithdrawal: 120, DstWithdrawal: 60},
+			dst:   dstAt(0),
+			want:  srcFinal.Add(2 * time.Minute),
+		},
+		{
+			name:     "withdrawal timelock equal to finality",
+			srcDelay: time.Minute, dstDelay: 0,
+			locks: common.TimeLocksRaw{SrcWithdrawal: 60},
+			dst:   dstAt(0),
+			want:  srcFinal.Add(time.Minute),
+		},
+		{
+			name:  "zero finality, dst timelock only",
+			locks: common.TimeLocksRaw{DstWithdrawal: 30},
+			dst:   dstAt(0),
+			want:  dstFinal(0).Add(30 * time.Second),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := finalityManager(t, tt.srcDelay, tt.dstDelay)
+			v := &Verification{SrcTimestamp: src, DstTimestamp: tt.dst}
+			if got := m.releaseAt(deadlineEntry(tt.locks), v); !got.Equal(tt.want) {
+				t.Errorf("releaseAt() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOrderDeadline(t *testing.T) {
+	// the auction of deadlineEntry ends 130s after submission
+	auctionEnd := time.Unix(deployedAt+130, 0)
+	locks := common.TimeLocksRaw{SrcPublicCancellation: 600}
+
+	tests := []struct {
+		name     string
+		escrows  bool
+		deadline time.Time
+		want     time.Time
+	}{
+		{"unfilled", false, time.Time{}, auctionEnd.Add(600 * time.Second)},
+		{"unfilled, later escrow deadline", false, auctionEnd.Add(time.Hour), auctionEnd.Add(time.Hour)},
+		{"unfilled, escrow deadline on the auction deadline", false, auctionEnd.Add(600 * time.Second), auctionEnd.Add(600 * time.Second)},
+		{"filled", true, auctionEnd.Add(time.Minute), auctionEnd.Add(time.Minute)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orderEntry := deadlineEntry(locks)
+			orderEntry.EscrowDeadline = tt.deadline
+			if tt.escrows {
+				orderEntry.Escrows = map[string]EscrowSide{"0xsrc": SrcEscrow}
+			}
+			if got := orderDeadline(orderEntry); !got.Equal(tt.want) {
+				t.Errorf("orderDeadline() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"relayer/internal/common"
+	"relayer/internal/config"
+	"sort"
+)
+
+// maxSafetyDeposit is the largest deposit an order's extension packs, 128
+// bits.
+var maxSafetyDeposit = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+
+// SafetyDeposit returns the safety deposit quotes on chain get from its
+// configured parameters at the current gas price, in the chain's native base
+// units. ok is false for chains without parameters, whose quotes keep their
+// deposits. The deposit is fixed in the quote and the order's extension from
+// then on; verification checks fills against that, not the current price.
+func (m *Manager) SafetyDeposit(ctx context.Context, chain string) (deposit *big.Int, ok bool, err error) {
+	// quote requests may name chains by CAIP-2 id
+	if normalized, err := common.NormalizeChain(chain); err == nil {
+		chain = normalized
+	}
+	params, ok := m.Config().SafetyDeposits[chain]
+	if !ok {
+		return nil, false, nil
+	}
+
+	var price *big.Int
+	if params.GasUnits > 0 && params.MultiplierBps > 0 {
+		chainID, err := common.ParseChainID(chain)
+		if err != nil {
+			return nil, true, err
+		}
+		if price, err = m.gasPrice(ctx, chainID); err != nil {
+			return nil, true, err
+		}
+	}
+
+	deposit, err = safetyDeposit(params, price)
+	return deposit, true, err
+}
+
+// safetyDeposit is gasUnits at gasPrice times multiplierBps, at least the
+// floor. gasPrice is nil for deposits made of the floor only.
+func safetyDeposit(params config.SafetyDeposit, gasPrice *big.Int) (*big.Int, error) {
+	deposit := new(big.Int)
+	if gasPrice != nil {
+		deposit.SetUint64(params.GasUnits)
+		deposit.Mul(deposit, gasPrice)
+		deposit.Mul(deposit, new(big.Int).SetUint64(params.MultiplierBps))
+		deposit.Quo(deposit, big.NewInt(10_000))
+	}
+	if params.Floor != "" {
+		floor, err := common.ParseAmount(params.Floor)
+		if err != nil {
+			return nil, err
+		}
+		if deposit.Cmp(floor) < 0 {
+			deposit = floor
+		}
+	}
+
+	if deposit.Cmp(maxSafetyDeposit) > 0 {
+		return nil, fmt.Errorf("safety deposit %s overflows 128 bits", deposit)
+	}
+	return deposit, nil
+}
+
+// gasPrice returns the current gas price of chainID in its native base units:
+// the suggested price on EVM chains, the reference price on Sui.
+func (m *Manager) gasPrice(ctx context.Context, chainID common.ChainID) (*big.Int, error) {
+	ctx, cancel := context.WithTimeout(ctx, ChainCallTimeout)
+	defer cancel()
+
+	if chainID.IsMove() {
+		price, err := m.suiClient.SuiXGetReferenceGasPrice(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("fetching Sui reference gas price: %w", err)
+		}
+		return new(big.Int).SetUint64(price), nil
+	}
+
+	client, err := m.evm(chainID)
+	if err != nil {
+		return nil, err
+	}
+	price, err := client.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fetching gas price: %w", err)
+	}
+	return price, nil
+}
+
+// SafetyDeposits reports the parameters of the configured chains with the
+// deposits their quotes currently get.
+func (m *Manager) SafetyDeposits(ctx context.Context) []common.SafetyDepositQuote {
+	cfg := m.Config()
+	chains := make([]string, 0, len(cfg.SafetyDeposits))
+	for chain := range cfg.SafetyDeposits {
+		chains = append(chains, chain)
+	}
+	sort.Strings(chains)
+
+	quotes := make([]common.SafetyDepositQuote, 0, len(chains))
+	for _, chain := range chains {
+		params := cfg.SafetyDeposits[chain]
+		q := common.SafetyDepositQuote{
+			Chain:         chain,
+			GasUnits:      params.GasUnits,
+			MultiplierBps: params.MultiplierBps,
+			Floor:         params.Floor,
+		}
+
+		var price *big.Int
+		if params.GasUnits > 0 && params.MultiplierBps > 0 {
+			chainID, err := common.ParseChainID(chain)
+			if err == nil {
+				price, err = m.gasPrice(ctx, chainID)
+			}
+			if err != nil {
+				q.Error = err.Error()
+				quotes = append(quotes, q)
+				continue
+			}
+			q.GasPrice = price.String()
+		}
+
+		if deposit, err := safetyDeposit(params, price); err != nil {
+			q.Error = err.Error()
+		} else {
+			q.Deposit = deposit.String()
+		}
+		quotes = append(quotes, q)
+	}
+	return quotes
+}
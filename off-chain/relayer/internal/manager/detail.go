@@ -0,0 +1,41 @@
+package manager
+
+import (
+	"encoding/json"
+	"relayer/internal/common"
+)
+
+// OrderDetailEvent renders the answer to GET_ORDER <ORDER_HASH_HEX> for a
+// live or archived order, the same in every protocol version.
+func (m *Manager) OrderDetailEvent(orderHash string) ([]byte, error) {
+	orderEntry, err := m.GetOrder(orderHash)
+	archived := false
+	if err != nil {
+		if orderEntry, err = m.GetArchivedOrder(orderHash); err != nil {
+			return nil, err
+		}
+		archived = true
+	}
+
+	detail := common.OrderDetail{
+		OrderHash: orderEntry.OrderHash.Hex(),
+		Order:     orderEntry.Order,
+		Archived:  archived,
+	}
+	if req := orderEntry.Quote.QuoteRequest; req != nil {
+		detail.DstChainID = req.DstChain
+	}
+
+	// the status shares its slices with the entry: marshal it under the lock
+	orderEntry.Lock()
+	detail.Status = orderEntry.OrderStatus
+	if orderEntry.FilledMakingAmount != nil {
+		detail.FilledMakingAmount = orderEntry.FilledMakingAmount.String()
+	}
+	data, err := json.Marshal(detail)
+	orderEntry.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(ORDER_DETAIL_EVENT+" "+detail.OrderHash+" "), data...), nil
+}
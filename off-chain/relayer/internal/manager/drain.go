@@ -0,0 +1,268 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"relayer/internal/resolver"
+	"relayer/internal/store"
+	"sync"
+	"time"
+)
+
+// TXHASH events being handled are tracked so a shutdown does not drop them:
+// Close waits verifyDrainWindow for them, persists those still running and
+// interrupts them, and the next start resumes them, see restoreVerifications.
+// Events received meanwhile are persisted right away.
+
+// ErrShuttingDown is returned for TXHASH events received during a shutdown.
+var ErrShuttingDown = errors.New("relayer is shutting down")
+
+// errVerifyInterrupted is returned by verifications a shutdown interrupted.
+var errVerifyInterrupted = errors.New("interrupted by shutdown, resumed after the restart")
+
+// verifyJob is a TXHASH event being handled.
+type verifyJob struct {
+	orderHash  string
+	srcTxHash  string
+	dstTxHash  string
+	resolverID string
+	receivedAt time.Time
+}
+
+// trackedJob is a verifyJob with whether a shutdown persisted it.
+type trackedJob struct {
+	verifyJob
+	persisted bool
+}
+
+// inflight tracks the TXHASH events being handled.
+type inflight struct {
+	mu       sync.Mutex
+	jobs     map[uint64]*trackedJob
+	next     uint64
+	draining bool
+	idle     chan struct{} // closed once draining with no job left
+}
+
+func newInflight() *inflight {
+	return &inflight{jobs: make(map[uint64]*trackedJob), idle: make(chan struct{})}
+}
+
+// begin tracks a job, unless a shutdown is draining the jobs.
+func (f *inflight) begin(job verifyJob) (uint64, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.draining {
+		return 0, false
+	}
+	f.next++
+	f.jobs[f.next] = &trackedJob{verifyJob: job}
+	return f.next, true
+}
+
+// persisted reports whether a shutdown persisted the job.
+func (f *inflight) persisted(id uint64) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	job, ok := f.jobs[id]
+	return ok && job.persisted
+}
+
+// end stops tracking a finished job.
+func (f *inflight) end(id uint64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	delete(f.jobs, id)
+	if f.draining && len(f.jobs) == 0 {
+		close(f.idle)
+	}
+}
+
+// drain refuses new jobs and returns the number still running and a channel
+// closed once none is.
+func (f *inflight) drain() (int, <-chan struct{}) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !f.draining {
+		f.draining = true
+		if len(f.jobs) == 0 {
+			close(f.idle)
+		}
+	}
+	return len(f.jobs), f.idle
+}
+
+// takeRunning marks the jobs still running persisted and returns them.
+func (f *inflight) takeRunning() []verifyJob {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	jobs := make([]verifyJob, 0, len(f.jobs))
+	for _, job := range f.jobs {
+		job.persisted = true
+		jobs = append(jobs, job.verifyJob)
+	}
+	return jobs
+}
+
+// drainVerifications waits up to verifyDrainWindow for the TXHASH events
+// being handled, then persists those still running for the next start and
+// interrupts them.
+func (m *Manager) drainVerifications() {
+	running, idle := m.inflight.drain()
+	if running == 0 {
+		return
+	}
+
+	window := time.Duration(m.Config().VerifyDrainWindow)
+	m.logger.Printf("Waiting up to %s for %d fill verifications", window, running)
+	select {
+	case <-idle:
+		m.logger.Println("Fill verifications drained")
+		return
+	case <-time.After(window):
+	}
+
+	jobs := m.inflight.takeRunning()
+	persisted := 0
+	for _, job := range jobs {
+		if m.persistVerifyJob(job) {
+			persisted++
+		}
+	}
+	m.stopVerify()
+	m.logger.Printf("Interrupted %d fill verifications, %d persisted for the next start", len(jobs), persisted)
+
+	// interrupted verifications return promptly, let them finish before the store closes
+	select {
+	case <-idle:
+	case <-time.After(StoreTimeout):
+	}
+}
+
+// postponeVerification persists a TXHASH event received during a shutdown.
+func (m *Manager) postponeVerification(job verifyJob) error {
+	if m.persistVerifyJob(job) {
+		return fmt.Errorf("%w, the fill is verified after its restart", ErrShuttingDown)
+	}
+	return fmt.Errorf("%w, resend the fill after its restart", ErrShuttingDown)
+}
+
+// persistVerifyJob stores an unfinished verification, reporting whether it
+// could.
+func (m *Manager) persistVerifyJob(job verifyJob) bool {
+	if m.store == nil {
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), StoreTimeout)
+	defer cancel()
+
+	rec := store.VerifyJobRecord{
+		OrderHash:  job.orderHash,
+		SrcTxHash:  job.srcTxHash,
+		DstTxHash:  job.dstTxHash,
+		ResolverID: job.resolverID,
+		ReceivedAt: job.receivedAt,
+	}
+	if err := m.store.PutVerifyJob(ctx, rec); err != nil {
+		m.logger.Printf("Failed to store verification of order %s, src %s, dst %s: %v", job.orderHash, job.srcTxHash, job.dstTxHash, err)
+		return false
+	}
+	return true
+}
+
+// forgetVerifyJob drops the persisted record of a finished verification.
+func (m *Manager) forgetVerifyJob(job verifyJob) {
+	if m.store == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), StoreTimeout)
+	defer cancel()
+
+	if err := m.store.DeleteVerifyJob(ctx, job.orderHash, job.srcTxHash, job.dstTxHash); err != nil {
+		m.logger.Printf("Failed to delete verification of order %s, src %s, dst %s: %v", job.orderHash, job.srcTxHash, job.dstTxHash, err)
+	}
+}
+
+// restoreVerifications resumes the verifications a shutdown persisted,
+// reloading their orders from the store. Those of orders whose escrows anyone
+// may cancel by now, and of resolvers no longer registered, are dropped.
+func (m *Manager) restoreVerifications() {
+	if m.store == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), StoreTimeout)
+	defer cancel()
+
+	recs, err := m.store.PendingVerifyJobs(ctx)
+	if err != nil {
+		m.logger.Printf("Failed to load pending fill verifications: %v", err)
+		return
+	}
+
+	resumed := 0
+	for _, rec := range recs {
+		job := verifyJob{
+			orderHash:  rec.OrderHash,
+			srcTxHash:  rec.SrcTxHash,
+			dstTxHash:  rec.DstTxHash,
+			resolverID: rec.ResolverID,
+			receivedAt: rec.ReceivedAt,
+		}
+
+		orderEntry, err := m.GetOrder(rec.OrderHash)
+		if err != nil {
+			if orderEntry, err = m.restoreOrder(ctx, rec.OrderHash); err != nil {
+				m.logger.Printf("Dropping verification of order %s, src %s: %v", rec.OrderHash, rec.SrcTxHash, err)
+				m.forgetVerifyJob(job)
+				continue
+			}
+		}
+		orderEntry.Lock()
+		deadline := orderDeadline(orderEntry)
+		orderEntry.Unlock()
+		if deadline.Before(time.Now()) {
+			m.logger.Printf("Dropping verification of order %s, src %s: its escrows may be cancelled by anyone", rec.OrderHash, rec.SrcTxHash)
+			m.forgetVerifyJob(job)
+			continue
+		}
+
+		var claimant *resolver.Resolver
+		if rec.ResolverID != "" {
+			var ok bool
+			if claimant, ok = m.resolvers.ByID(rec.ResolverID); !ok {
+				m.logger.Printf("Dropping verification of order %s, src %s: resolver %s is no longer registered", rec.OrderHash, rec.SrcTxHash, rec.ResolverID)
+				m.forgetVerifyJob(job)
+				continue
+			}
+		}
+
+		go m.resumeVerification(job, claimant)
+		resumed++
+	}
+
+	if resumed > 0 {
+		m.logger.Printf("Resuming %d fill verifications interrupted by the last shutdown", resumed)
+	}
+}
+
+// resumeVerification handles a persisted TXHASH event again, forgetting it
+// unless another shutdown interrupts it.
+func (m *Manager) resumeVerification(job verifyJob, claimant *resolver.Resolver) {
+	err := m.handleTxHashEvent(claimant, []string{job.orderHash, job.srcTxHash, job.dstTxHash})
+	if errors.Is(err, ErrShuttingDown) || errors.Is(err, errVerifyInterrupted) {
+		return
+	}
+	if err != nil {
+		m.logger.Printf("Resumed verification of order %s, src %s failed: %v", job.orderHash, job.srcTxHash, err)
+	}
+	m.forgetVerifyJob(job)
+}
@@ -0,0 +1,51 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"relayer/internal/chain"
+	"relayer/internal/common"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// dryRunWithdraw dev-inspects the withdrawal of the Sui escrows of the fill
+// at hashIdx with secret, when enabled by the suiDryRun setting. It catches
+// escrows that would not pay out, e.g. ones locked with a hashlock the
+// relayer did not verify, before the secret is shared. Fills that were not
+// verified are left to the regular checks.
+func (m *Manager) dryRunWithdraw(orderEntry *OrderEntry, hashIdx int, secret string) error {
+	if !m.Config().SuiDryRun {
+		return nil
+	}
+
+	orderEntry.Lock()
+	v, ok := orderEntry.Canonical[hashIdx]
+	orderEntry.Unlock()
+	if !ok {
+		return nil
+	}
+
+	raw, err := hexutil.Decode(secret)
+	if err != nil {
+		return fmt.Errorf("invalid secret: %w", err)
+	}
+
+	var escrows []string
+	if orderEntry.Order.SrcChainID.IsMove() {
+		escrows = append(escrows, v.SrcEscrow)
+	}
+	if orderEntry.Quote.QuoteRequest != nil && orderEntry.Quote.QuoteRequest.DstChain == common.Sui.String() {
+		escrows = append(escrows, v.DstEscrow)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), ChainCallTimeout)
+	defer cancel()
+
+	for _, escrow := range escrows {
+		if err := chain.DryRunMoveWithdraw(ctx, m.suiClient, escrow, raw); err != nil {
+			return fmt.Errorf("dry run of escrow %s failed: %w", escrow, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"relayer/internal/chain"
+	"relayer/internal/common"
+	"relayer/internal/extension"
+	"relayer/internal/hash"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+// CheckOrderEpoch rejects EVM-sourced orders whose maker traits ask for the
+// epoch manager check when the maker has already advanced the epoch of the
+// order's series, so resolvers are not sent orders every fill of would revert.
+func (m *Manager) CheckOrderEpoch(order *common.Order) error {
+	if order.SrcChainID.IsMove() {
+		return nil
+	}
+
+	traits, err := extension.ParseMakerTraits(order.LimitOrder.MakerTraits)
+	if err != nil {
+		return err
+	}
+	if !traits.NeedCheckEpochManager() {
+		return nil
+	}
+	if traits.UseBitInvalidator() {
+		return errors.New("epoch manager check requires partial and multiple fills to be allowed")
+	}
+
+	protocol, err := hash.GetLimitOrderContract(order.SrcChainID)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), ChainCallTimeout)
+	defer cancel()
+
+	client, err := m.evm(order.SrcChainID)
+	if err != nil {
+		return err
+	}
+	series := traits.Series()
+	epoch, err := chain.FetchEvmEpoch(ctx, client, protocol, ethcommon.HexToAddress(order.LimitOrder.Maker), series)
+	if err != nil {
+		return fmt.Errorf("fetching epoch of series %d: %w", series, err)
+	}
+	if !epoch.IsUint64() || epoch.Uint64() != traits.NonceOrEpoch() {
+		return fmt.Errorf("order was invalidated, series %d is at epoch %s but the order is for epoch %d", series, epoch, traits.NonceOrEpoch())
+	}
+	return nil
+}
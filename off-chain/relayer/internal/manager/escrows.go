@@ -0,0 +1,216 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"relayer/internal/common"
+	"relayer/internal/store"
+	"sort"
+)
+
+// persistEscrows records both escrows of a verified fill with their
+// immutables in the persistent store, if any, so they can be withdrawn from
+// or cancelled after the order left memory.
+func (m *Manager) persistEscrows(orderEntry *OrderEntry, v *Verification) {
+	if m.store == nil {
+		return
+	}
+
+	srcChain, dstChain := orderChains(orderEntry)
+	recs := []store.EscrowRecord{
+		{Side: string(SrcEscrow), ChainID: srcChain, Escrow: v.SrcEscrow, DeployTx: v.SrcTxHash, DeployedAt: v.SrcTimestamp.Time, Taker: v.SrcTaker, Amount: v.SrcAmount.String()},
+		{Side: string(DstEscrow), ChainID: dstChain, Escrow: v.DstEscrow, DeployTx: v.DstTxHash, DeployedAt: v.DstTimestamp.Time, Taker: v.DstTaker, Amount: v.DstAmount.String()},
+	}
+	for i, immutables := range []*common.EscrowImmutables{escrowImmutables(v.SrcImmutables), escrowImmutables(v.DstImmutables)} {
+		if immutables == nil {
+			continue
+		}
+		data, err := json.Marshal(immutables)
+		if err != nil {
+			m.logger.Printf("Failed to encode %s immutables of order %s for the store: %v", recs[i].Side, v.OrderHash, err)
+			return
+		}
+		recs[i].Immutables = data
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), StoreTimeout)
+	defer cancel()
+
+	for _, rec := range recs {
+		rec.OrderHash = orderEntry.OrderHash.Hex()
+		rec.HashIdx = v.HashIdx
+		rec.Hashlock = v.Hashlock.Hex()
+		rec.VerifiedAt = v.VerifiedAt
+		if err := m.store.PutEscrow(ctx, rec); err != nil {
+			m.logger.Printf("Failed to store %s escrow of fill %d of order %s: %v", rec.Side, v.HashIdx, rec.OrderHash, err)
+		}
+	}
+}
+
+// persistClosed records the tx that withdrew from or cancelled an escrow
+// of an order in the persistent store, if any.
+func (m *Manager) persistClosed(orderHash, escrow, txHash string) {
+	if m.store == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), StoreTimeout)
+	defer cancel()
+
+	if err := m.store.CloseEscrow(ctx, orderHash, escrow, txHash); err != nil {
+		m.logger.Printf("Failed to store closing of escrow %s of order %s: %v", escrow, orderHash, err)
+	}
+}
+
+// persistFillSecret records in the persistent store, if any, that the fill
+// at hashIdx was allowed its secret or, with revealed set, that the secret
+// was shared.
+func (m *Manager) persistFillSecret(orderHash string, hashIdx int, revealed bool) {
+	if m.store == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), StoreTimeout)
+	defer cancel()
+
+	mark := m.store.MarkFillAllowed
+	if revealed {
+		mark = m.store.MarkFillRevealed
+	}
+	if err := mark(ctx, orderHash, hashIdx); err != nil {
+		m.logger.Printf("Failed to store secret progress of fill %d of order %s: %v", hashIdx, orderHash, err)
+	}
+}
+
+// OrderEscrows returns the escrows of the order's verified fills with their
+// immutables, from memory while the order is held and from the persistent
+// store afterwards.
+func (m *Manager) OrderEscrows(orderHash string) (common.OrderEscrows, error) {
+	orderEntry, err := m.GetOrder(orderHash)
+	if err != nil {
+		orderEntry, err = m.GetArchivedOrder(orderHash)
+	}
+	if err == nil {
+		return liveEscrows(orderEntry), nil
+	}
+	if m.store == nil {
+		return common.OrderEscrows{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), StoreTimeout)
+	defer cancel()
+
+	recs, err := m.store.Escrows(ctx, orderHash)
+	if err != nil {
+		return common.OrderEscrows{}, err
+	}
+	if len(recs) == 0 {
+		return common.OrderEscrows{}, fmt.Errorf("order not found: %s", orderHash)
+	}
+	return storedEscrows(orderHash, recs)
+}
+
+// OrderMaker returns the maker of an order held in memory or, once it left,
+// in the persistent store.
+func (m *Manager) OrderMaker(orderHash string) (string, error) {
+	orderEntry, err := m.GetOrder(orderHash)
+	if err != nil {
+		orderEntry, err = m.GetArchivedOrder(orderHash)
+	}
+	if err == nil {
+		if orderEntry.Order == nil {
+			return "", errors.New("order has no maker")
+		}
+		return orderEntry.Order.LimitOrder.Maker, nil
+	}
+	if m.store == nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), StoreTimeout)
+	defer cancel()
+
+	rec, err := m.store.GetOrder(ctx, orderHash)
+	if err != nil {
+		return "", err
+	}
+	return rec.Maker, nil
+}
+
+func liveEscrows(orderEntry *OrderEntry) common.OrderEscrows {
+	srcChain, dstChain := orderChains(orderEntry)
+
+	orderEntry.Lock()
+	defer orderEntry.Unlock()
+
+	escrows := common.OrderEscrows{
+		OrderHash: orderEntry.OrderHash.Hex(),
+		Fills:     make([]common.FillEscrows, 0, len(orderEntry.Canonical)),
+	}
+	for idx, v := range orderEntry.Canonical {
+		escrows.Fills = append(escrows.Fills, common.FillEscrows{
+			Idx: idx,
+			Src: common.Escrow{
+				ChainID:    srcChain,
+				Escrow:     v.SrcEscrow,
+				DeployTx:   v.SrcTxHash,
+				Immutables: escrowImmutables(v.SrcImmutables),
+				DeployedAt: v.SrcTimestamp.UnixMilli(),
+			},
+			Dst: common.Escrow{
+				ChainID:    dstChain,
+				Escrow:     v.DstEscrow,
+				DeployTx:   v.DstTxHash,
+				Immutables: escrowImmutables(v.DstImmutables),
+				DeployedAt: v.DstTimestamp.UnixMilli(),
+			},
+		})
+	}
+	sort.Slice(escrows.Fills, func(i, j int) bool { return escrows.Fills[i].Idx < escrows.Fills[j].Idx })
+
+	return escrows
+}
+
+func storedEscrows(orderHash string, recs []store.EscrowRecord) (common.OrderEscrows, error) {
+	escrows := common.OrderEscrows{OrderHash: orderHash, Fills: []common.FillEscrows{}}
+	for _, rec := range recs {
+		escrow := common.Escrow{
+			ChainID:    rec.ChainID,
+			Escrow:     rec.Escrow,
+			DeployTx:   rec.DeployTx,
+			DeployedAt: rec.DeployedAt.UnixMilli(),
+		}
+		if len(rec.Immutables) > 0 {
+			escrow.Immutables = new(common.EscrowImmutables)
+			if err := json.Unmarshal(rec.Immutables, escrow.Immutables); err != nil {
+				return common.OrderEscrows{}, fmt.Errorf("decoding immutables of fill %d: %w", rec.HashIdx, err)
+			}
+		}
+
+		// records come by fill, src first
+		if n := len(escrows.Fills); n == 0 || escrows.Fills[n-1].Idx != rec.HashIdx {
+			escrows.Fills = append(escrows.Fills, common.FillEscrows{Idx: rec.HashIdx})
+		}
+		fill := &escrows.Fills[len(escrows.Fills)-1]
+		if rec.Side == string(SrcEscrow) {
+			fill.Src = escrow
+		} else {
+			fill.Dst = escrow
+		}
+	}
+	return escrows, nil
+}
+
+// orderChains returns the decimal ids of the order's src and dst chains.
+func orderChains(orderEntry *OrderEntry) (string, string) {
+	srcChain, dstChain := "", ""
+	if orderEntry.Order != nil {
+		srcChain = orderEntry.Order.SrcChainID.String()
+	}
+	if orderEntry.Quote.QuoteRequest != nil {
+		dstChain = orderEntry.Quote.QuoteRequest.DstChain
+	}
+	return srcChain, dstChain
+}
@@ -0,0 +1,148 @@
+package manager
+
+import (
+	"math"
+	"relayer/internal/auction"
+	"relayer/internal/common"
+	"sort"
+	"strings"
+	"time"
+)
+
+// phaseRank orders the phases of a swap, the least advanced first.
+var phaseRank = map[string]int{
+	common.PhaseAuction:    0,
+	common.PhaseFinality:   1,
+	common.PhaseSecret:     2,
+	common.PhaseWithdrawal: 3,
+	common.PhaseCompleted:  4,
+}
+
+// OrderETA estimates when the swap of an order held in memory completes.
+// An order waiting for escrows is expected to get them by its auction end,
+// and a verified fill to be final once the finality locks passed since its
+// escrows were deployed, see releaseAt. Revealing the secret and withdrawing
+// are expected right after.
+func (m *Manager) OrderETA(orderHash string) (common.OrderETA, error) {
+	orderEntry, err := m.GetOrder(orderHash)
+	if err != nil {
+		orderEntry, err = m.GetArchivedOrder(orderHash)
+	}
+	if err != nil {
+		return common.OrderETA{}, err
+	}
+
+	now := time.Now()
+	srcFinality, dstFinality := m.finalityLocks(orderEntry)
+	auctionEnd := orderEntry.SubmittedAt
+	if quote := orderEntry.Quote.Quote; quote != nil {
+		auctionEnd = auction.FromPreset(orderEntry.SubmittedAt, quote.Presets[quote.RecommendedPreset]).End()
+	}
+
+	orderEntry.Lock()
+	defer orderEntry.Unlock()
+
+	eta := common.OrderETA{
+		OrderHash: orderEntry.OrderHash.Hex(),
+		Status:    orderEntry.OrderStatus.Status,
+		Phase:     common.PhaseCompleted,
+		Fills:     make([]common.FillETA, 0, len(orderEntry.Canonical)),
+	}
+	if eta.Status != common.OrderStatusPending {
+		eta.Phase = string(eta.Status)
+		for idx, v := range orderEntry.Canonical {
+			eta.Fills = append(eta.Fills, common.FillETA{
+				Idx:           idx,
+				Phase:         fillPhase(orderEntry, idx, v),
+				SrcDeployedAt: v.SrcTimestamp.Unix(),
+				DstDeployedAt: v.DstTimestamp.Unix(),
+			})
+		}
+		sortFills(eta.Fills)
+		return eta, nil
+	}
+
+	var completion time.Time
+	for idx, v := range orderEntry.Canonical {
+		fill := common.FillETA{
+			Idx:           idx,
+			Phase:         fillPhase(orderEntry, idx, v),
+			SrcDeployedAt: v.SrcTimestamp.Unix(),
+			DstDeployedAt: v.DstTimestamp.Unix(),
+		}
+		if fill.Phase != common.PhaseCompleted {
+			final := latest(now, m.releaseAt(orderEntry, v))
+			fill.NextMilestone = fillMilestone(fill.Phase, final, now)
+			completion = latest(completion, final)
+		}
+		eta.Fills = append(eta.Fills, fill)
+	}
+	sortFills(eta.Fills)
+
+	for _, fill := range eta.Fills {
+		if fill.Phase == common.PhaseCompleted {
+			continue
+		}
+		switch {
+		case phaseRank[fill.Phase] < phaseRank[eta.Phase]:
+			eta.Phase, eta.NextMilestone = fill.Phase, fill.NextMilestone
+		case fill.Phase == eta.Phase && fill.NextMilestone.ExpectedAt < eta.NextMilestone.ExpectedAt:
+			eta.NextMilestone = fill.NextMilestone
+		}
+	}
+
+	// more fills may come until the auction ends
+	if len(orderEntry.Canonical) == 0 || (!orderEntry.fullyFilled() && now.Before(auctionEnd)) {
+		deployed := latest(now, auctionEnd)
+		eta.Phase = common.PhaseAuction
+		eta.NextMilestone = &common.Milestone{Name: common.MilestoneEscrowsDeployed, ExpectedAt: deployed.Unix()}
+		completion = latest(completion, deployed.Add(max(srcFinality, dstFinality)))
+	}
+
+	if !completion.IsZero() {
+		eta.EstimatedCompletion = completion.Unix()
+		eta.RemainingSeconds = int64(math.Ceil(completion.Sub(now).Seconds()))
+	}
+	return eta, nil
+}
+
+// fillPhase returns the phase of the verified fill v of secret index idx.
+// The caller holds the entry's lock.
+func fillPhase(orderEntry *OrderEntry, idx int, v *Verification) string {
+	_, srcClosed := orderEntry.Closed[strings.ToLower(v.SrcEscrow)]
+	_, dstClosed := orderEntry.Closed[strings.ToLower(v.DstEscrow)]
+	switch {
+	case srcClosed && dstClosed:
+		return common.PhaseCompleted
+	case orderEntry.Revealed[idx]:
+		return common.PhaseWithdrawal
+	case orderEntry.Allowed[idx]:
+		return common.PhaseSecret
+	default:
+		return common.PhaseFinality
+	}
+}
+
+// fillMilestone returns the milestone ending phase for a fill final at
+// final: the secret is ready then, and waits on nobody after.
+func fillMilestone(phase string, final, now time.Time) *common.Milestone {
+	switch phase {
+	case common.PhaseFinality:
+		return &common.Milestone{Name: common.MilestoneSecretReady, ExpectedAt: final.Unix()}
+	case common.PhaseSecret:
+		return &common.Milestone{Name: common.MilestoneSecretRevealed, ExpectedAt: now.Unix()}
+	default:
+		return &common.Milestone{Name: common.MilestoneWithdrawn, ExpectedAt: now.Unix()}
+	}
+}
+
+func sortFills(fills []common.FillETA) {
+	sort.Slice(fills, func(i, j int) bool { return fills[i].Idx < fills[j].Idx })
+}
+
+func latest(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
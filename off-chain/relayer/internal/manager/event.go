@@ -1,84 +1,617 @@
 package manager
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"math"
+	"log/slog"
+	"math/big"
+	"strconv"
 	"time"
 
+	"relayer/internal/auction"
+	"relayer/internal/bus"
+	"relayer/internal/chain"
 	"relayer/internal/common"
+	"relayer/internal/hash"
+	"relayer/internal/resolver"
 
 	"strings"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
 )
 
 func (m *Manager) HandleOrderEvent(order common.Order) error {
-	op := []byte(ORDER_EVENT + " ")
+	orderHash, err := hash.GetOrderHashForLimitOrder(order.SrcChainID, order.LimitOrder)
+	if err != nil {
+		return err
+	}
+
 	orderBytes, err := json.Marshal(order)
 	if err != nil {
 		return err
 	}
+	v1, versions, err := versioned(ORDER_EVENT, orderBytes, OrderPayload{OrderHash: orderHash.Hex(), Order: &order})
+	if err != nil {
+		return err
+	}
+	m.broadcaster.BroadcastVersioned(v1, versions, submittedRooms(order)...)
 
-	orderBytes = append(op, orderBytes...)
-	m.Broadcast(orderBytes)
+	if orderEntry, err := m.GetOrder(orderHash.Hex()); err == nil {
+		m.publishBook(orderEntry, common.OrderStatusPending)
+	}
 	return nil
 }
 
+// orderHashlocks returns the hashlocks of an order's secrets, none for orders
+// whose hashlock is only known on chain, single fill Sui-sourced ones.
+func orderHashlocks(orderEntry *OrderEntry) []ethcommon.Hash {
+	var hashlocks []ethcommon.Hash
+	switch {
+	case orderEntry.Order != nil && len(orderEntry.Order.SecretHashes) > 0:
+		for _, h := range orderEntry.Order.SecretHashes {
+			hashlocks = append(hashlocks, ethcommon.HexToHash(h))
+		}
+	case orderEntry.Extension != nil:
+		hashlocks = append(hashlocks, orderEntry.Extension.Escrow.Hashlock)
+	}
+	return hashlocks
+}
+
+// CheckSecret verifies a maker's secret against the hashlocks of its order
+// with the order's hashlock algorithm, then against the hashlock the verified
+// fill of its index was deployed with, and that the fills of lower secret
+// indexes were released first. Single fill orders whose hashlock is only
+// known on chain are checked against their verified fill alone. Secrets of
+// unknown orders and of fills not verified are refused.
+func (m *Manager) CheckSecret(secret common.Secret) error {
+	orderEntry, err := m.GetOrder(secret.OrderHash)
+	if err != nil {
+		return fmt.Errorf("unknown order %s", secret.OrderHash)
+	}
+
+	hashIdx := 0
+	if hashlocks := orderHashlocks(orderEntry); len(hashlocks) > 0 {
+		if hashIdx, err = orderEntry.Hashlock.Verify(secret.Secret, hashlocks...); err != nil {
+			return err
+		}
+	}
+
+	orderEntry.Lock()
+	v, ok := orderEntry.Canonical[hashIdx]
+	orderEntry.Unlock()
+	if !ok {
+		return fmt.Errorf("no verified fill of secret %d", hashIdx)
+	}
+	if _, err := orderEntry.Hashlock.Verify(secret.Secret, v.Hashlock); err != nil {
+		return fmt.Errorf("secret does not match the hashlock of the verified fill %d", hashIdx)
+	}
+
+	if err := checkSecretSequence(orderEntry, hashIdx); err != nil {
+		return err
+	}
+	return m.dryRunWithdraw(orderEntry, hashIdx, secret.Secret)
+}
+
 func (m *Manager) HandleSecretEvent(secret common.Secret) error {
-	op := []byte(SECRET_EVENT + " ")
-	secretBytes := []byte(secret.OrderHash + " " + secret.Secret)
-	secretBytes = append(op, secretBytes...)
+	payload := SecretPayload{OrderHash: secret.OrderHash, Secret: secret.Secret}
+	orderEntry, orderErr := m.GetOrder(secret.OrderHash)
+	if orderErr == nil {
+		if hashlocks := orderHashlocks(orderEntry); len(hashlocks) > 0 {
+			if idx, err := orderEntry.Hashlock.Verify(secret.Secret, hashlocks...); err == nil {
+				payload.HashIdx = &idx
+			}
+		}
+		m.markRevealed(orderEntry, payload.HashIdx)
+	}
 
-	m.Broadcast(secretBytes)
+	v1, versions, err := versioned(SECRET_EVENT, []byte(secret.OrderHash+" "+secret.Secret), payload)
+	if err != nil {
+		return err
+	}
+	m.broadcaster.BroadcastVersioned(v1, versions, m.roomsOf(secret.OrderHash)...)
+	if orderErr == nil {
+		m.publishOrder(bus.SecretReleased, orderEvent{entry: orderEntry}, nil)
+	}
 	return nil
 }
 
-func (m *Manager) HandleReceiveEvent(event []byte) error {
+// markRevealed records that the secret at hashIdx of an order was shared,
+// so its fill is not refunded. Single fill orders have one secret, whose
+// index is not known for those with their hashlock only on chain.
+func (m *Manager) markRevealed(orderEntry *OrderEntry, hashIdx *int) {
+	idx := 0
+	if hashIdx != nil {
+		idx = *hashIdx
+	} else if orderEntry.OrderType != SingleFill {
+		return
+	}
+
+	orderEntry.Lock()
+	defer orderEntry.Unlock()
+
+	if orderEntry.Revealed == nil {
+		orderEntry.Revealed = make(map[int]bool)
+	}
+	orderEntry.Revealed[idx] = true
+	go m.persistFillSecret(orderEntry.OrderHash.Hex(), idx, true)
+}
+
+// HandleReceiveEvent processes a message sent by a resolver. claimant is the
+// authenticated resolver, or nil when the registry is disabled.
+func (m *Manager) HandleReceiveEvent(claimant *resolver.Resolver, event []byte) error {
 	msg := string(event)
 	m.logger.Printf("Received event: %s", msg)
 
-	parts := strings.Split(msg, " ")
+	parts := strings.Fields(msg)
+	if len(parts) == 0 {
+		return fmt.Errorf("empty event")
+	}
+
 	switch parts[0] {
 	case TXHASH_EVENT:
 		m.logger.Printf("Received tx hash event: %s", msg)
-		m.handleTxHashEvent(parts[1:])
+		return m.handleTxHashEvent(claimant, parts[1:])
+	case CANCEL_EVENT:
+		return m.handleCancelEvent(parts[1:])
+	case WITHDRAWN_EVENT, WITHDRAW_EVENT:
+		return m.handleWithdrawEvent(parts[1:])
+	case FILL_INTENT_EVENT:
+		return m.handleFillIntentEvent(claimant, parts[1:])
 	default:
 		return fmt.Errorf("unknown event type: %s", parts[0])
 	}
+}
+
+func (m *Manager) handleTxHashEvent(claimant *resolver.Resolver, parts []string) error {
+	if len(parts) != 3 {
+		return fmt.Errorf("invalid tx hash event format, expected 3 parts, got %d", len(parts))
+	}
+
+	orderHash, srcTxHash, dstTxHash := parts[0], parts[1], parts[2]
+	orderEntry, err := m.GetOrder(orderHash)
+	if err != nil {
+		return err
+	}
+
+	// malformed hashes would only burn RPC quota
+	if err := common.ValidateTxHash(orderEntry.Order.SrcChainID.String(), srcTxHash); err != nil {
+		return fmt.Errorf("src tx: %w", err)
+	}
+	if err := common.ValidateTxHash(orderEntry.Quote.QuoteRequest.DstChain, dstTxHash); err != nil {
+		return fmt.Errorf("dst tx: %w", err)
+	}
+
+	job := verifyJob{orderHash: orderEntry.OrderHash.Hex(), srcTxHash: srcTxHash, dstTxHash: dstTxHash, receivedAt: time.Now()}
+	if claimant != nil {
+		job.resolverID = claimant.ID
+	}
+	id, ok := m.inflight.begin(job)
+	if !ok {
+		return m.postponeVerification(job)
+	}
+
+	ctx := verifyContext(m.verifyCtx, orderEntry, claimant)
+	err = m.acceptFill(ctx, orderEntry, claimant, orderHash, srcTxHash, dstTxHash)
+	if m.inflight.persisted(id) && !errors.Is(err, errVerifyInterrupted) {
+		// finished after a shutdown persisted it after all
+		m.forgetVerifyJob(job)
+	}
+	m.inflight.end(id)
+	return err
+}
 
+// acceptFill verifies a fill reported by TXHASH and schedules the release of
+// its secret once its escrows are final.
+func (m *Manager) acceptFill(ctx context.Context, orderEntry *OrderEntry, claimant *resolver.Resolver, orderHash, srcTxHash, dstTxHash string) error {
+	v, duplicate, err := m.verifyOnce(ctx, orderEntry, claimant, srcTxHash, dstTxHash)
+	if err != nil {
+		return fmt.Errorf("verification failed: %w", err)
+	}
+	if duplicate {
+		m.logFor(ctx).Printf("Duplicate tx hash event for order %s, already verified", orderHash)
+		return nil
+	}
+
+	orderEntry.Lock()
+	orderEntry.Escrows[strings.ToLower(v.SrcEscrow)] = SrcEscrow
+	orderEntry.Escrows[strings.ToLower(v.DstEscrow)] = DstEscrow
+	orderEntry.Unlock()
+	m.adjustDeadline(orderHash, v)
+
+	m.publishOrder(bus.EscrowsVerified, orderEvent{entry: orderEntry, verification: v}, map[string]any{
+		"hashIdx":   v.HashIdx,
+		"srcEscrow": v.SrcEscrow,
+		"dstEscrow": v.DstEscrow,
+	})
+
+	hashIdx := strconv.Itoa(v.HashIdx)
+
+	delay := m.releaseDelay(orderEntry, v)
+	m.notify(orderEntry, FINALITY_WAIT_EVENT, hashIdx, strconv.FormatInt(time.Now().Add(delay).Unix(), 10))
+
+	m.scheduleRelease(orderHash, v.HashIdx, srcTxHash, dstTxHash, delay)
 	return nil
 }
 
-func (m *Manager) handleTxHashEvent(parts []string) {
-	if len(parts) != 3 {
-		m.logger.Printf("invalid tx hash event format, expected 3 parts, got %d", len(parts))
-		return
+// handleCancelEvent records the cancellation of one of the order's escrows.
+// A cancelled src escrow refunds the maker, so the order becomes cancelled.
+func (m *Manager) handleCancelEvent(parts []string) error {
+	if len(parts) != 2 {
+		return fmt.Errorf("invalid cancel event format, expected 2 parts, got %d", len(parts))
+	}
+
+	orderHash, txHash := parts[0], parts[1]
+	orderEntry, err := m.GetOrder(orderHash)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), ChainCallTimeout)
+	defer cancel()
+
+	// EVM hashes are hex, Sui digests are base58
+	var escrows []string
+	if err := validateEscrowTxHash(orderEntry, txHash); err != nil {
+		return err
+	}
+	if strings.HasPrefix(txHash, "0x") {
+		err = m.onOrderEvm(orderEntry, func(client chain.EVMClient) (err error) {
+			escrows, err = chain.FetchEvmCancelledEscrows(ctx, client, ethcommon.HexToHash(txHash))
+			return err
+		})
+	} else {
+		escrows, err = chain.FetchMoveCancelledEscrows(ctx, m.suiClient, txHash, moveEscrowPackage(orderEntry))
+	}
+	if err != nil {
+		return fmt.Errorf("fetching cancellation: %w", err)
+	}
+
+	orderEntry.Lock()
+	matched, refunded := false, false
+	for _, escrow := range escrows {
+		side, ok := orderEntry.Escrows[strings.ToLower(escrow)]
+		if !ok {
+			continue
+		}
+		matched = true
+		orderEntry.Closed[strings.ToLower(escrow)] = txHash
+		m.publishEscrowClosed(orderEntry, side, escrow, chain.EscrowCancelled, txHash)
+		if side == SrcEscrow && orderEntry.OrderStatus.Status != common.OrderStatusCancelled {
+			orderEntry.OrderStatus.Status = common.OrderStatusCancelled
+			refunded = true
+		}
+	}
+	if !matched {
+		orderEntry.Unlock()
+		return fmt.Errorf("tx %s does not cancel an escrow of order %s", txHash, orderHash)
+	}
+	// a cancelled fill no longer holds back the secrets after it
+	m.releaseHeld(orderEntry)
+	orderEntry.OrderStatus.CancelTx = &txHash
+	orderEntry.Unlock()
+
+	if refunded {
+		m.notifyStatus(orderEntry, string(common.OrderStatusCancelled))
+		m.persistStatus(orderEntry, string(common.OrderStatusCancelled))
+	}
+	m.logger.Printf("Recorded cancellation %s for order %s", txHash, orderHash)
+	return nil
+}
+
+// handleWithdrawEvent records a resolver's proof of withdrawal: a transaction
+// paying out escrows of the order whose withdrawal events reveal secrets
+// opening the escrows' hashlocks. The withdrawals are announced to the maker,
+// stop the refunds scheduled for the src escrows and, once every fill is
+// settled, complete the order, see executeIfSettled.
+func (m *Manager) handleWithdrawEvent(parts []string) error {
+	if len(parts) != 2 {
+		return fmt.Errorf("invalid withdraw event format, expected 2 parts, got %d", len(parts))
+	}
+
+	orderHash, txHash := parts[0], parts[1]
+	orderEntry, err := m.GetOrder(orderHash)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), ChainCallTimeout)
+	defer cancel()
+
+	// EVM hashes are hex, Sui digests are base58
+	var withdrawals []chain.Withdrawal
+	if err := validateEscrowTxHash(orderEntry, txHash); err != nil {
+		return err
+	}
+	if strings.HasPrefix(txHash, "0x") {
+		err = m.onOrderEvm(orderEntry, func(client chain.EVMClient) (err error) {
+			withdrawals, err = chain.FetchEvmWithdrawals(ctx, client, ethcommon.HexToHash(txHash))
+			return err
+		})
+	} else {
+		withdrawals, err = chain.FetchMoveWithdrawals(ctx, m.suiClient, txHash, moveEscrowPackage(orderEntry))
+	}
+	if err != nil {
+		return fmt.Errorf("fetching withdrawal: %w", err)
+	}
+
+	// secrets are all checked before any escrow is recorded closed
+	orderEntry.Lock()
+	matched := make([]chain.Withdrawal, 0, len(withdrawals))
+	sides := make([]EscrowSide, 0, len(withdrawals))
+	revealed := make([]int, 0, len(withdrawals))
+	for _, w := range withdrawals {
+		side, ok := orderEntry.Escrows[strings.ToLower(w.Escrow)]
+		if !ok {
+			continue
+		}
+		idx, err := withdrawnSecretIdx(orderEntry, w)
+		if err != nil {
+			orderEntry.Unlock()
+			return fmt.Errorf("tx %s withdrew escrow %s: %w", txHash, w.Escrow, err)
+		}
+		matched = append(matched, w)
+		sides = append(sides, side)
+		revealed = append(revealed, idx)
+	}
+	if len(sides) == 0 {
+		orderEntry.Unlock()
+		return fmt.Errorf("tx %s does not withdraw from an escrow of order %s", txHash, orderHash)
+	}
+
+	if orderEntry.Revealed == nil {
+		orderEntry.Revealed = make(map[int]bool)
+	}
+	var accrued []int
+	var settled []*big.Int
+	for i, w := range matched {
+		if paidFee(orderEntry, w.Escrow, revealed[i]) {
+			accrued = append(accrued, revealed[i])
+		}
+		if paid := paidMaker(orderEntry, w.Escrow, revealed[i]); paid != nil {
+			settled = append(settled, paid)
+		}
+		orderEntry.Closed[strings.ToLower(w.Escrow)] = txHash
+		orderEntry.Revealed[revealed[i]] = true
+		go m.persistFillSecret(orderEntry.OrderHash.Hex(), revealed[i], true)
+		stopRefund(orderEntry, w.Escrow)
+		m.publishEscrowClosed(orderEntry, sides[i], w.Escrow, chain.EscrowWithdrawn, txHash)
+	}
+	executed := executeIfSettled(orderEntry, time.Now())
+	orderEntry.Unlock()
+
+	for _, idx := range accrued {
+		m.accrueFee(orderEntry, idx)
+	}
+	for _, paid := range settled {
+		if err := m.recordSurplus(orderEntry, paid); err != nil {
+			m.logger.Printf("failed to record surplus for order %s: %v", orderHash, err)
+		}
+	}
+	for _, side := range sides {
+		m.notify(orderEntry, WITHDRAWN_EVENT, string(side), txHash)
+	}
+	m.recordStage(orderEntry.OrderHash.Hex(), StageWithdrawn, time.Now())
+	if executed {
+		m.notifyStatus(orderEntry, string(common.OrderStatusExecuted))
+		m.persistStatus(orderEntry, string(common.OrderStatusExecuted))
+		m.settleLeg(orderEntry)
+	}
+	m.logger.Printf("Recorded withdrawal %s for order %s", txHash, orderHash)
+	return nil
+}
+
+// paidFee reports whether withdrawing escrow pays the fee of the verified
+// fill at hashIdx, it being the fill's source escrow and not closed yet. The
+// caller holds the entry's lock.
+func paidFee(orderEntry *OrderEntry, escrow string, hashIdx int) bool {
+	if _, closed := orderEntry.Closed[strings.ToLower(escrow)]; closed {
+		return false
+	}
+	v, ok := orderEntry.Canonical[hashIdx]
+	return ok && strings.EqualFold(v.SrcEscrow, escrow)
+}
+
+// withdrawnSecretIdx checks that the secret a withdrawal revealed opens the
+// hashlock of the withdrawn escrow's fill and returns the fill's secret
+// index. Escrows of fills that lost to a competing one are checked against
+// the order's hashlocks, and refused when the order has none known, as no
+// index can be told for their secret. The caller holds the entry's lock.
+func withdrawnSecretIdx(orderEntry *OrderEntry, w chain.Withdrawal) (int, error) {
+	lock := orderEntry.Hashlock.Hash(w.Secret)
+	for idx, v := range orderEntry.Canonical {
+		if strings.EqualFold(v.SrcEscrow, w.Escrow) || strings.EqualFold(v.DstEscrow, w.Escrow) {
+			if lock != v.Hashlock {
+				return 0, fmt.Errorf("revealed secret does not match the hashlock of fill %d", idx)
+			}
+			return idx, nil
+		}
+	}
+
+	hashlocks := orderHashlocks(orderEntry)
+	if len(hashlocks) == 0 {
+		return 0, errors.New("order has no known hashlock to check the revealed secret against")
+	}
+	for idx, h := range hashlocks {
+		if h == lock {
+			return idx, nil
+		}
+	}
+	return 0, errors.New("revealed secret does not match a hashlock of the order")
+}
+
+// executeIfSettled marks a pending or refunding order executed once no more
+// fills can come, it being fully filled or its auction over, and both escrows
+// of each verified fill were withdrawn from or cancelled. It reports whether
+// it did. The caller holds the entry's lock.
+func executeIfSettled(orderEntry *OrderEntry, now time.Time) bool {
+	status := orderEntry.OrderStatus.Status
+	if status != common.OrderStatusPending && status != common.OrderStatusRefunding {
+		return false
+	}
+	if len(orderEntry.Canonical) == 0 {
+		return false
+	}
+	if !orderEntry.fullyFilled() {
+		quote := orderEntry.Quote.Quote
+		if quote == nil || now.Before(auction.FromPreset(orderEntry.SubmittedAt, quote.Presets[quote.RecommendedPreset]).End()) {
+			return false
+		}
+	}
+	for _, v := range orderEntry.Canonical {
+		for _, escrow := range []string{v.SrcEscrow, v.DstEscrow} {
+			if _, closed := orderEntry.Closed[strings.ToLower(escrow)]; !closed {
+				return false
+			}
+		}
 	}
 
-	orderHash := parts[0]
-	m.allowSecretRelease(orderHash, 0, "", "")
+	orderEntry.OrderStatus.Status = common.OrderStatusExecuted
+	return true
 }
 
-func computeTTL(_ time.Time, dstTimestamp time.Time, _ *common.Quote) time.Duration {
-	dstDuration := time.Since(dstTimestamp)
+// validateEscrowTxHash checks that txHash is well formed on one of the
+// order's chains, as a closing transaction may be on either side.
+func validateEscrowTxHash(orderEntry *OrderEntry, txHash string) error {
+	srcErr := common.ValidateTxHash(orderEntry.Order.SrcChainID.String(), txHash)
+	if srcErr == nil || common.ValidateTxHash(orderEntry.Quote.QuoteRequest.DstChain, txHash) == nil {
+		return nil
+	}
+	return srcErr
+}
+
+// moveEscrowPackage returns the escrow package of the order's Sui side it
+// was quoted with, empty when it has none.
+func moveEscrowPackage(orderEntry *OrderEntry) string {
+	quote := orderEntry.Quote.Quote
+	switch {
+	case quote == nil:
+		return ""
+	case orderEntry.Order != nil && orderEntry.Order.SrcChainID.IsMove():
+		return quote.SrcEscrowFactory
+	case orderEntry.Quote.QuoteRequest != nil && common.IsSuiChain(orderEntry.Quote.QuoteRequest.DstChain):
+		return quote.DstEscrowFactory
+	}
+	return ""
+}
 
-	return time.Duration(math.Max(2-dstDuration.Seconds(), 0) * float64(time.Second))
+// releaseDelay is how long to wait before a verified fill may receive its
+// secret: until both escrow deployments are final, see releaseAt.
+func (m *Manager) releaseDelay(orderEntry *OrderEntry, v *Verification) time.Duration {
+	return max(time.Until(m.releaseAt(orderEntry, v)), 0)
+}
+
+// releaseAt is when both escrow deployments of a verified fill are final. A
+// side is final once the chain's configured finality delay and the order's
+// withdrawal timelock for that side, its signed finality lock, have both
+// passed since deployment.
+func (m *Manager) releaseAt(orderEntry *OrderEntry, v *Verification) time.Time {
+	srcFinality, dstFinality := m.finalityLocks(orderEntry)
+
+	var at time.Time
+	if orderEntry.Order != nil {
+		at = v.SrcTimestamp.Latest().Add(srcFinality)
+	}
+	if orderEntry.Quote.QuoteRequest != nil {
+		if dst := v.DstTimestamp.Latest().Add(dstFinality); dst.After(at) {
+			at = dst
+		}
+	}
+	return at
 }
 
-func (m *Manager) allowSecretRelease(orderHash string, hashIdx int, srcTxHash string, dstTxHash string) {
+// finalityLocks returns how long the src and dst escrow deployments of the
+// order take to be final: the chain's finality delay or the side's
+// withdrawal timelock, whichever is longer.
+func (m *Manager) finalityLocks(orderEntry *OrderEntry) (time.Duration, time.Duration) {
+	cfg := m.Config()
+	locks := orderTimelocks(orderEntry)
+
+	var src, dst time.Duration
+	if orderEntry.Order != nil {
+		src = max(cfg.FinalityDelay(orderEntry.Order.SrcChainID.String()), locks.srcWithdrawal)
+	}
+	if orderEntry.Quote.QuoteRequest != nil {
+		dst = max(cfg.FinalityDelay(orderEntry.Quote.QuoteRequest.DstChain), locks.dstWithdrawal)
+	}
+	return src, dst
+}
+
+// allowSecretRelease marks a final fill ready to receive its secret, and the
+// held fills it unblocks. With sequenced set, a fill of a multiple fill order
+// is held while a fill of a lower secret index was not allowed its secret
+// yet, and true is returned.
+func (m *Manager) allowSecretRelease(orderHash string, hashIdx int, srcTxHash string, dstTxHash string, sequenced bool) (held bool) {
 	orderEntry, err := m.GetOrder(orderHash)
 	if err != nil {
 		m.logger.Printf("Error getting order for hash %s: %v", orderHash, err)
-		return
+		return false
 	}
 
-	orderEntry.OrderMutMutex.Lock()
-	defer orderEntry.OrderMutMutex.Unlock()
+	orderEntry.Lock()
+	defer orderEntry.Unlock()
+
+	if sequenced {
+		if blocker := releaseBlocker(orderEntry, hashIdx); blocker >= 0 {
+			m.holdRelease(orderEntry, hashIdx, blocker, srcTxHash, dstTxHash)
+			return true
+		}
+	}
+	delete(orderEntry.Held, hashIdx)
+
+	m.allowFill(orderEntry, hashIdx, srcTxHash, dstTxHash)
+	m.releaseHeld(orderEntry)
+	return false
+}
 
-	orderEntry.OrderFills.Fills = append(orderEntry.OrderFills.Fills, common.ReadyToAcceptSecretFill{
+// allowFill adds a fill to those ready to receive their secret. Called with
+// the entry locked.
+func (m *Manager) allowFill(orderEntry *OrderEntry, hashIdx int, srcTxHash string, dstTxHash string) {
+	orderHash := orderEntry.OrderHash.Hex()
+	if orderEntry.Allowed == nil {
+		orderEntry.Allowed = make(map[int]bool)
+	}
+	orderEntry.Allowed[hashIdx] = true
+	go m.persistFillSecret(orderHash, hashIdx, false)
+
+	var report *common.VerificationReport
+	if v, ok := orderEntry.Canonical[hashIdx]; ok && v.DstTxHash == dstTxHash {
+		report = verificationReport(orderEntry, v, time.Now())
+	}
+
+	for i, fill := range orderEntry.OrderFills {
+		if fill.Idx != hashIdx {
+			continue
+		}
+		if fill.DstEscrowDeployTxHash != dstTxHash {
+			// a competing escrow deployed earlier became the canonical fill
+			orderEntry.OrderFills[i].SrcEscrowDeployTxHash = srcTxHash
+			orderEntry.OrderFills[i].DstEscrowDeployTxHash = dstTxHash
+			orderEntry.OrderFills[i].Verification = report
+		}
+		slog.Debug("secret release already allowed", "orderHash", orderHash, "hashIdx", hashIdx)
+		return
+	}
+
+	orderEntry.OrderFills = append(orderEntry.OrderFills, common.ReadyToAcceptSecretFill{
 		Idx:                   hashIdx,
 		SrcEscrowDeployTxHash: srcTxHash,
 		DstEscrowDeployTxHash: dstTxHash,
+		Verification:          report,
 	})
+	if report != nil {
+		m.notifyFillReady(orderEntry, report)
+	}
+	if orderEntry.SecretsID != "" {
+		// revealing takes the order lock
+		go m.revealCustodial(orderHash, orderEntry.SecretsID, hashIdx)
+	}
 
-	fmt.Println("Allowing secret release for order:", orderHash, "hash index:", hashIdx, "src tx hash:", srcTxHash, "dst tx hash:", dstTxHash)
+	slog.Debug("allowing secret release",
+		"orderHash", orderHash,
+		"hashIdx", hashIdx,
+		"srcTxHash", srcTxHash,
+		"dstTxHash", dstTxHash,
+	)
 }
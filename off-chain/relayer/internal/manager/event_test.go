@@ -0,0 +1,120 @@
+package manager
+
+import (
+	"relayer/internal/chain"
+	"relayer/internal/common"
+	"relayer/internal/hashlock"
+	"strings"
+	"testing"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+func TestWithdrawnSecretIdx(t *testing.T) {
+	secrets := [][]byte{randomBytes(32), randomBytes(32), randomBytes(32)}
+	hashes := make([]string, len(secrets))
+	for i, secret := range secrets {
+		hashes[i] = hashlock.Keccak256.Hash(secret).Hex()
+	}
+	canonical := map[int]*Verification{
+		1: {Hashlock: hashlock.Keccak256.Hash(secrets[1]), SrcEscrow: "0xSrc1", DstEscrow: "0xdst1"},
+	}
+
+	tests := []struct {
+		name   string
+		hashes []string
+		escrow string
+		secret []byte
+		want   int
+		err    string
+	}{
+		{name: "canonical fill", hashes: hashes, escrow: "0xsrc1", secret: secrets[1], want: 1},
+		{name: "canonical dst escrow", hashes: hashes, escrow: "0xDST1", secret: secrets[1], want: 1},
+		{name: "canonical fill, wrong secret", hashes: hashes, escrow: "0xsrc1", secret: secrets[2], err: "hashlock of fill 1"},
+		{name: "displaced fill", hashes: hashes, escrow: "0xother", secret: secrets[2], want: 2},
+		{name: "displaced fill, unknown secret", hashes: hashes, escrow: "0xother", secret: randomBytes(32), err: "a hashlock of the order"},
+		{name: "no known hashlocks", escrow: "0xother", secret: secrets[0], err: "no known hashlock"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orderEntry := &OrderEntry{
+				Order:     &common.Order{SecretHashes: tt.hashes},
+				Canonical: canonical,
+				Hashlock:  hashlock.Keccak256,
+			}
+			got, err := withdrawnSecretIdx(orderEntry, chain.Withdrawal{Escrow: tt.escrow, Secret: tt.secret})
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("withdrawnSecretIdx() error = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("withdrawnSecretIdx() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("withdrawnSecretIdx() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckSecret(t *testing.T) {
+	f := newFillFixture(t, common.Base)
+	secrets := [][]byte{randomBytes(32), randomBytes(32), randomBytes(32)}
+	hashes := make([]string, len(secrets))
+	for i, secret := range secrets {
+		hashes[i] = hashlock.Keccak256.Hash(secret).Hex()
+	}
+
+	// multiple fill orders with the given verified fills, by secret index
+	multi := func(canonical map[int]ethcommon.Hash) string {
+		return f.order(t, MultiFill, func(orderEntry *OrderEntry) {
+			orderEntry.Order.SecretHashes = hashes
+			for idx, lock := range canonical {
+				orderEntry.Canonical[idx] = &Verification{HashIdx: idx, Hashlock: lock}
+			}
+		}).OrderHash.Hex()
+	}
+	// a single fill order whose hashlock is only known from its verified fill
+	single := func(lock *ethcommon.Hash) string {
+		return f.order(t, SingleFill, func(orderEntry *OrderEntry) {
+			if lock != nil {
+				orderEntry.Canonical[0] = &Verification{Hashlock: *lock}
+			}
+		}).OrderHash.Hex()
+	}
+	lock0 := hashlock.Keccak256.Hash(secrets[0])
+	other := hashlock.Keccak256.Hash(randomBytes(32))
+
+	tests := []struct {
+		name      string
+		orderHash string
+		secret    []byte
+		err       string
+	}{
+		{name: "verified fill", orderHash: multi(map[int]ethcommon.Hash{1: ethcommon.HexToHash(hashes[1])}), secret: secrets[1]},
+		{name: "unknown order", orderHash: ethcommon.BytesToHash(randomBytes(32)).Hex(), secret: secrets[0], err: "unknown order"},
+		{name: "not a secret of the order", orderHash: multi(map[int]ethcommon.Hash{1: ethcommon.HexToHash(hashes[1])}), secret: randomBytes(32), err: "does not match the order's"},
+		{name: "fill not verified", orderHash: multi(map[int]ethcommon.Hash{1: ethcommon.HexToHash(hashes[1])}), secret: secrets[2], err: "no verified fill of secret 2"},
+		{name: "verified with another hashlock", orderHash: multi(map[int]ethcommon.Hash{1: other}), secret: secrets[1], err: "verified fill 1"},
+		{name: "hashlock on chain only", orderHash: single(&lock0), secret: secrets[0]},
+		{name: "hashlock on chain only, wrong secret", orderHash: single(&lock0), secret: secrets[1], err: "verified fill 0"},
+		{name: "hashlock on chain only, no fill", orderHash: single(nil), secret: secrets[0], err: "no verified fill of secret 0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := f.m.CheckSecret(common.Secret{OrderHash: tt.orderHash, Secret: hexutil.Encode(tt.secret)})
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("CheckSecret() error = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CheckSecret() error = %v", err)
+			}
+		})
+	}
+}
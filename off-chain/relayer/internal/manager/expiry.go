@@ -0,0 +1,89 @@
+package manager
+
+import (
+	"log/slog"
+	"relayer/internal/bus"
+	"relayer/internal/common"
+	"relayer/internal/metrics"
+
+	"github.com/imkira/go-ttlmap"
+)
+
+// Kinds of the manager's ttlmaps, as counted in the ttl_expired and
+// ttl_evicted metrics.
+const (
+	QuoteKind        = "quote"
+	OrderKind        = "order"
+	VerificationKind = "verification"
+	MultiHopKind     = "multihop"
+	IntentKind       = "intent"
+	ArchiveKind      = "archive"
+	PrewarmKind      = "prewarm"
+)
+
+// ttlOptions are the options of the ttlmap holding entries of kind. Every
+// expiry and eviction is counted; onExpire, if any, is also called for
+// expiries. The callbacks run with the map locked, so they must not use it.
+func (m *Manager) ttlOptions(kind string, onExpire func(key string, item ttlmap.Item)) *ttlmap.Options {
+	return &ttlmap.Options{
+		InitialCapacity: 32,
+		OnWillExpire: func(key string, item ttlmap.Item) {
+			metrics.TTLExpired.Add(kind, 1)
+			if onExpire != nil {
+				onExpire(key, item)
+			}
+		},
+		OnWillEvict: func(key string, item ttlmap.Item) {
+			// expired entries are evicted right after OnWillExpire
+			if item.Expired() {
+				return
+			}
+			metrics.TTLEvicted.Add(kind, 1)
+			slog.Debug("ttlmap entry evicted", "kind", kind, "key", key)
+		},
+	}
+}
+
+func (m *Manager) onQuoteExpired(key string, item ttlmap.Item) {
+	slog.Debug("quote expired", "quoteId", key)
+	m.quoteRecency.remove(key)
+	if quote, ok := item.Value().(QuoteEntry); ok {
+		m.conversions.Expired(key, quotePair(quote))
+	}
+}
+
+// onOrderExpired hands an order dropped from memory at its deadline to
+// expireOrder. Holders of an order's lock may wait on the orders map, so the
+// order is not locked from the callback.
+func (m *Manager) onOrderExpired(key string, item ttlmap.Item) {
+	m.orderRecency.remove(key)
+	if orderEntry, ok := item.Value().(*OrderEntry); ok {
+		go m.expireOrder(key, orderEntry)
+	}
+}
+
+// expireOrder records an order dropped from memory at its deadline. Orders
+// the sweeper did not archive are normally settled by then; one still
+// pending is marked expired and, with expiryNotifications, announced.
+func (m *Manager) expireOrder(key string, orderEntry *OrderEntry) {
+	m.deactivate(key)
+
+	orderEntry.Lock()
+	status := orderEntry.OrderStatus.Status
+	pending := status == common.OrderStatusPending
+	if pending {
+		orderEntry.OrderStatus.Status = common.OrderStatusExpired
+	}
+	orderEntry.Unlock()
+
+	m.logger.Printf("Order %s reached its deadline with status %s, dropped from memory", key, status)
+	if !pending {
+		return
+	}
+
+	m.publishOrder(bus.OrderExpired, orderEvent{entry: orderEntry, announce: m.Config().ExpiryNotifications}, nil)
+}
+
+func (m *Manager) onArchiveExpired(key string, _ ttlmap.Item) {
+	m.logger.Printf("Archived order %s expired, dropped from memory", key)
+}
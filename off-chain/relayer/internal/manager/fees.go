@@ -0,0 +1,127 @@
+package manager
+
+import (
+	"context"
+	"math/big"
+	"relayer/internal/store"
+	"time"
+)
+
+// accrueFee books the share of the protocol and integrator fees of an order
+// taken by the verified fill at hashIdx, once its source escrow was
+// withdrawn: each fee times the fill's making amount over the order's. The
+// accruals are persisted, see restoreFees.
+func (m *Manager) accrueFee(orderEntry *OrderEntry, hashIdx int) {
+	orderEntry.Lock()
+	v, ok := orderEntry.Canonical[hashIdx]
+	making, err := orderEntry.Order.SrcChainID.ParseAmount(orderEntry.Order.LimitOrder.MakingAmount)
+	if !ok || v.SrcAmount == nil || err != nil || making.Sign() == 0 {
+		orderEntry.Unlock()
+		return
+	}
+
+	var accruals []store.FeeAccrualRecord
+	var first []bool
+	book := func(fee *OrderFee, kind, integrator string) {
+		if fee == nil || fee.Amount.Sign() == 0 {
+			return
+		}
+		share := new(big.Int).Mul(fee.Amount, v.SrcAmount)
+		share.Div(share, making)
+		accruals = append(accruals, store.FeeAccrualRecord{
+			OrderHash:  orderEntry.OrderHash.Hex(),
+			HashIdx:    hashIdx,
+			Kind:       kind,
+			Integrator: integrator,
+			ChainID:    fee.ChainID,
+			Token:      fee.Token,
+			Amount:     share.String(),
+			AccruedAt:  time.Now(),
+		})
+		first = append(first, !fee.Accrued)
+		fee.Accrued = true
+	}
+	book(orderEntry.Fee, store.ProtocolFee, "")
+	book(orderEntry.IntegratorFee, store.IntegratorFee, orderEntry.Quote.Integrator)
+	orderEntry.Unlock()
+
+	for i, rec := range accruals {
+		m.bookFee(rec, first[i])
+		m.persistFee(rec)
+	}
+}
+
+// bookFee adds an accrual to the protocol or integrator fee ledger.
+func (m *Manager) bookFee(rec store.FeeAccrualRecord, newOrder bool) {
+	amount, ok := new(big.Int).SetString(rec.Amount, 10)
+	if !ok {
+		m.logger.Printf("Invalid fee amount %q of order %s", rec.Amount, rec.OrderHash)
+		return
+	}
+	if rec.Kind == store.IntegratorFee {
+		m.integrators.Accrue(rec.Integrator, rec.ChainID, rec.Token, amount, newOrder)
+		return
+	}
+	m.fees.Accrue(rec.ChainID, rec.Token, amount, newOrder)
+}
+
+// persistFee records a fee accrual in the persistent store, if any.
+func (m *Manager) persistFee(rec store.FeeAccrualRecord) {
+	if m.store == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), StoreTimeout)
+	defer cancel()
+
+	if err := m.store.PutFeeAccrual(ctx, rec); err != nil {
+		m.logger.Printf("Failed to store %s fee of order %s, idx %d: %v", rec.Kind, rec.OrderHash, rec.HashIdx, err)
+	}
+}
+
+// restoreFees rebuilds the fee ledgers from the accruals persisted before a
+// restart.
+func (m *Manager) restoreFees() {
+	if m.store == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), StoreTimeout)
+	defer cancel()
+
+	accruals, err := m.store.FeeAccruals(ctx, "")
+	if err != nil {
+		m.logger.Printf("Failed to load fee accruals: %v", err)
+		return
+	}
+
+	type orderFee struct{ orderHash, kind string }
+	booked := make(map[orderFee]bool)
+	for _, rec := range accruals {
+		k := orderFee{rec.OrderHash, rec.Kind}
+		m.bookFee(rec, !booked[k])
+		booked[k] = true
+	}
+	if len(accruals) > 0 {
+		m.logger.Printf("Restored %d fee accruals of %d orders", len(accruals), len(booked))
+	}
+}
+
+// restoreAccrued marks the fees of a restored order that accrued before the
+// restart, so its order is not counted again by the ledgers.
+func (m *Manager) restoreAccrued(ctx context.Context, orderEntry *OrderEntry) error {
+	accruals, err := m.store.FeeAccruals(ctx, orderEntry.OrderHash.Hex())
+	if err != nil {
+		return err
+	}
+	for _, rec := range accruals {
+		fee := orderEntry.Fee
+		if rec.Kind == store.IntegratorFee {
+			fee = orderEntry.IntegratorFee
+		}
+		if fee != nil {
+			fee.Accrued = true
+		}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package manager
+
+import (
+	"context"
+	"math/big"
+	"path/filepath"
+	"relayer/internal/common"
+	"relayer/internal/hashlock"
+	"relayer/internal/resolver"
+	"testing"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestAccrueFee(t *testing.T) {
+	t.Setenv("DATABASE_PATH", filepath.Join(t.TempDir(), "relayer.db"))
+	f := newFillFixture(t, common.Base)
+	claimant := &resolver.Resolver{ID: "resolver-1", EVMAddress: f.taker.Hex()}
+
+	secrets := [][]byte{randomBytes(32), randomBytes(32), randomBytes(32)}
+	orderEntry := f.order(t, MultiFill, func(orderEntry *OrderEntry) {
+		orderEntry.Order.SecretHashes = nil
+		for _, secret := range secrets {
+			orderEntry.Order.SecretHashes = append(orderEntry.Order.SecretHashes, hashlock.Keccak256.Hash(secret).Hex())
+		}
+		orderEntry.Fee.Amount = big.NewInt(1000)
+		orderEntry.Quote.Integrator = "integrator-1"
+		orderEntry.IntegratorFee = &OrderFee{ChainID: orderEntry.Fee.ChainID, Token: orderEntry.Fee.Token, Amount: big.NewInt(100)}
+	})
+	orderHash := orderEntry.OrderHash.Hex()
+
+	// withdraw reports the src escrow of the fill at idx withdrawn with its secret
+	withdraw := func(idx int) string {
+		orderEntry.Lock()
+		escrow := orderEntry.Canonical[idx].SrcEscrow
+		orderEntry.Unlock()
+		tx := ethcommon.BytesToHash(randomBytes(32))
+		f.evm.AddReceipt(tx, &types.Receipt{Status: types.ReceiptStatusSuccessful, Logs: []*types.Log{{
+			Address: ethcommon.HexToAddress(escrow),
+			Topics:  []ethcommon.Hash{crypto.Keccak256Hash([]byte("EscrowWithdrawal(bytes32)"))},
+			Data:    secrets[idx],
+		}}}, uint64(time.Now().Unix()))
+		return WITHDRAWN_EVENT + " " + orderHash + " " + tx.Hex()
+	}
+	accrued := func() (string, int, string, int) {
+		var protocol, integrator string
+		var protocolOrders, integratorOrders int
+		for _, e := range f.m.Fees().Summary() {
+			protocol, protocolOrders = e.Accrued, e.Orders
+		}
+		for _, e := range f.m.IntegratorFees().Summary("integrator-1") {
+			integrator, integratorOrders = e.Accrued, e.Orders
+		}
+		return protocol, protocolOrders, integrator, integratorOrders
+	}
+
+	// a quarter, then half of the order
+	for idx, part := range []int64{1, 2} {
+		if err := f.m.HandleReceiveEvent(claimant, []byte(f.deploy(t, orderEntry, f.partial(orderEntry, idx, part)))); err != nil {
+			t.Fatalf("fill %d = %v", idx, err)
+		}
+	}
+	if protocol, _, _, _ := accrued(); protocol != "" {
+		t.Fatalf("fee accrued before any withdrawal: %s", protocol)
+	}
+
+	tests := []struct {
+		name       string
+		idx        int
+		protocol   string
+		integrator string
+	}{
+		{name: "first fill", idx: 0, protocol: "250", integrator: "25"},
+		{name: "second fill", idx: 1, protocol: "750", integrator: "75"},
+		{name: "second fill reported again", idx: 1, protocol: "750", integrator: "75"},
+	}
+	for _, tt := range tests {
+		if err := f.m.HandleReceiveEvent(claimant, []byte(withdraw(tt.idx))); err != nil {
+			t.Fatalf("%s: withdrawal = %v", tt.name, err)
+		}
+		protocol, protocolOrders, integrator, integratorOrders := accrued()
+		if protocol != tt.protocol || integrator != tt.integrator {
+			t.Errorf("%s: accrued %s protocol and %s integrator fees, want %s and %s", tt.name, protocol, integrator, tt.protocol, tt.integrator)
+		}
+		if protocolOrders != 1 || integratorOrders != 1 {
+			t.Errorf("%s: fees counted on %d and %d orders, want 1", tt.name, protocolOrders, integratorOrders)
+		}
+	}
+
+	waitStored(t, "fee accruals", func(ctx context.Context) bool {
+		accruals, err := f.m.store.FeeAccruals(ctx, orderHash)
+		return err == nil && len(accruals) == 4
+	})
+
+	f.restart()
+
+	protocol, protocolOrders, integrator, integratorOrders := accrued()
+	if protocol != "750" || integrator != "75" || protocolOrders != 1 || integratorOrders != 1 {
+		t.Errorf("restored fees = %s on %d orders and %s on %d, want 750 and 75 on 1", protocol, protocolOrders, integrator, integratorOrders)
+	}
+}
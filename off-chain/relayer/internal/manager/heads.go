@@ -0,0 +1,111 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"relayer/internal/alert"
+	"relayer/internal/bus"
+	"relayer/internal/common"
+	"relayer/internal/metrics"
+	"time"
+)
+
+// headLoop polls the chain heads for lag metrics and alerts until the
+// manager is closed. Stale heads break finality timing: fills would get their
+// secret before their escrows are as deep as the finality delay assumes.
+func (m *Manager) headLoop() {
+	ticker := time.NewTicker(HeadPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.done:
+			return
+		case <-ticker.C:
+			m.pollHeads()
+		}
+	}
+}
+
+// pollHeads records every endpoint's head. Unavailable and lagging endpoints
+// are published to the bus at every poll, and recovered ones once.
+func (m *Manager) pollHeads() {
+	for _, health := range m.ChainHealth(context.Background()) {
+		if !health.OK {
+			m.logger.Printf("Head of chain %s unavailable: %s", health.Chain, health.Error)
+
+			m.headMu.Lock()
+			m.outage[health.Chain] = true
+			m.headMu.Unlock()
+
+			m.events.Publish(bus.Event{Kind: bus.ChainUnavailable, Subject: health.Chain, Details: map[string]any{"error": health.Error}})
+			continue
+		}
+
+		lag, _ := time.ParseDuration(health.Lag)
+		metrics.ObserveHead(health.Chain, health.Head, lag)
+
+		m.headMu.Lock()
+		recovered := m.outage[health.Chain] && !health.Behind
+		m.outage[health.Chain] = health.Behind
+		m.headMu.Unlock()
+
+		details := map[string]any{"head": health.Head, "lag": health.Lag}
+		if health.Behind {
+			m.events.Publish(bus.Event{Kind: bus.ChainBehind, Subject: health.Chain, Details: details, Payload: health})
+		} else if recovered {
+			m.events.Publish(bus.Event{Kind: bus.ChainRecovered, Subject: health.Chain, Details: details, Payload: health})
+		}
+	}
+}
+
+// onChainHead raises an alert when a chain endpoint falls behind, resolving
+// it once the endpoint has caught up.
+func (m *Manager) onChainHead(e bus.Event) {
+	health, ok := e.Payload.(common.ChainHealth)
+	if !ok {
+		return
+	}
+
+	m.headMu.Lock()
+	changed := m.behind[health.Chain] != health.Behind
+	m.behind[health.Chain] = health.Behind
+	m.headMu.Unlock()
+
+	if changed {
+		m.alertHeadLag(health)
+	}
+}
+
+// alertHeadLag reports a chain endpoint that fell behind or caught up again.
+func (m *Manager) alertHeadLag(health common.ChainHealth) {
+	threshold := m.Config().HeadLagThreshold(health.Chain)
+
+	a := alert.Alert{
+		Key:      "head-lag-" + health.Chain,
+		Severity: alert.Critical,
+		Resolved: !health.Behind,
+		Details: map[string]any{
+			"chain":     health.Chain,
+			"head":      health.Head,
+			"lag":       health.Lag,
+			"threshold": threshold.String(),
+		},
+	}
+	if health.Behind {
+		a.Summary = fmt.Sprintf("chain %s RPC head is %s behind, threshold %s", health.Chain, health.Lag, threshold)
+	} else {
+		a.Summary = fmt.Sprintf("chain %s RPC head caught up, %s behind", health.Chain, health.Lag)
+	}
+	m.logger.Println(a.Summary)
+
+	if !m.alerts.Enabled() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), alert.SendTimeout)
+	defer cancel()
+	if err := m.alerts.Send(ctx, a); err != nil {
+		m.logger.Printf("Failed to send head lag alert for chain %s: %v", health.Chain, err)
+	}
+}
@@ -0,0 +1,126 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"relayer/internal/common"
+	"relayer/internal/resolver"
+	"strconv"
+	"time"
+
+	"github.com/imkira/go-ttlmap"
+)
+
+// errSegmentReserved is wrapped by the errors of fills of a segment another
+// resolver reserved: they may be sent again once its window ended.
+var errSegmentReserved = errors.New("segment reserved by another resolver")
+
+// fillIntent is a resolver's reservation of one segment of an order, the part
+// filled with the secret at hashIdx.
+type fillIntent struct {
+	resolverID string
+	until      time.Time
+}
+
+func intentKey(orderHash string, hashIdx int) string {
+	return fmt.Sprintf("%s:%d", orderHash, hashIdx)
+}
+
+// handleFillIntentEvent grants the claiming resolver a short exclusivity
+// window on an order segment and announces it, so other resolvers do not
+// spend gas racing for the same fill. A holder declaring again does not
+// extend its window. FILL_INTENT <ORDER_HASH_HEX> <HASH_IDX>
+func (m *Manager) handleFillIntentEvent(claimant *resolver.Resolver, parts []string) error {
+	if len(parts) != 2 {
+		return fmt.Errorf("invalid fill intent event format, expected 2 parts, got %d", len(parts))
+	}
+	if claimant == nil {
+		return errors.New("fill intents require an authenticated resolver")
+	}
+
+	orderHash := parts[0]
+	hashIdx, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return fmt.Errorf("invalid hash index %q", parts[1])
+	}
+
+	orderEntry, err := m.GetOrder(orderHash)
+	if err != nil {
+		return err
+	}
+	if err := checkIntent(orderEntry, hashIdx); err != nil {
+		return err
+	}
+	if exclusive := exclusiveResolver(orderEntry); exclusive != "" {
+		if owner, ok := m.resolvers.ByAddress(exclusive); ok && owner.ID != claimant.ID {
+			return fmt.Errorf("order is exclusive to resolver %s", owner.ID)
+		}
+	}
+
+	intent, err := m.reserveSegment(orderEntry.OrderHash.Hex(), hashIdx, claimant.ID)
+	if err != nil {
+		return err
+	}
+
+	m.logger.Printf("Resolver %s reserved segment %d of order %s until %s", claimant.ID, hashIdx, orderHash, intent.until.Format(time.RFC3339))
+	msg := fmt.Sprintf("%s %s %d %s %d", FILL_RESERVED_EVENT, orderEntry.OrderHash.Hex(), hashIdx, claimant.ID, intent.until.Unix())
+	m.broadcaster.Broadcast([]byte(msg), orderRooms(orderEntry)...)
+	return nil
+}
+
+// checkIntent makes sure hashIdx is a segment of the order that is still open.
+func checkIntent(orderEntry *OrderEntry, hashIdx int) error {
+	orderEntry.Lock()
+	status := orderEntry.OrderStatus.Status
+	orderEntry.Unlock()
+	if status != common.OrderStatusPending {
+		return fmt.Errorf("order %s is %s", orderEntry.OrderHash.Hex(), status)
+	}
+
+	segments := 1
+	if orderEntry.OrderType == MultiFill {
+		segments = len(orderEntry.Order.SecretHashes)
+	}
+	if hashIdx < 0 || hashIdx >= segments {
+		return fmt.Errorf("hash index %d out of range, order has %d segments", hashIdx, segments)
+	}
+	return nil
+}
+
+// reserveSegment records resolverID's intent unless another resolver holds
+// the segment.
+func (m *Manager) reserveSegment(orderHash string, hashIdx int, resolverID string) (fillIntent, error) {
+	m.intentMu.Lock()
+	defer m.intentMu.Unlock()
+
+	key := intentKey(orderHash, hashIdx)
+	if item, err := m.intents.Get(key); err == nil {
+		held := item.Value().(fillIntent)
+		if held.resolverID != resolverID {
+			return fillIntent{}, fmt.Errorf("segment %d of order %s is reserved by resolver %s until %s", hashIdx, orderHash, held.resolverID, held.until.Format(time.RFC3339))
+		}
+		return held, nil
+	}
+
+	window := time.Duration(m.Config().FillIntentWindow)
+	intent := fillIntent{resolverID: resolverID, until: time.Now().Add(window)}
+	m.intents.Set(key, ttlmap.NewItem(intent, ttlmap.WithExpiration(intent.until)), nil)
+	return intent, nil
+}
+
+// checkReservation refuses fills of a reserved segment by any resolver but
+// the one holding it, until its window ends.
+func (m *Manager) checkReservation(orderHash string, hashIdx int, claimant *resolver.Resolver) error {
+	m.intentMu.Lock()
+	item, err := m.intents.Get(intentKey(orderHash, hashIdx))
+	m.intentMu.Unlock()
+	if err != nil {
+		return nil
+	}
+
+	held := item.Value().(fillIntent)
+	if claimant != nil && claimant.ID == held.resolverID {
+		return nil
+	}
+	return fmt.Errorf("%w: segment %d of order %s is reserved by resolver %s until %s", errSegmentReserved, hashIdx, orderHash, held.resolverID, held.until.Format(time.RFC3339))
+}
@@ -0,0 +1,148 @@
+package manager
+
+import (
+	"container/list"
+	"relayer/internal/common"
+	"relayer/internal/metrics"
+	"sync"
+	"time"
+
+	"github.com/imkira/go-ttlmap"
+)
+
+// recency orders the keys of a ttlmap from most to least recently used, so
+// the map can be held to maxQuotes or maxOrders. It never calls into the
+// ttlmap, whose expiry callbacks remove keys from it under the map's lock.
+type recency struct {
+	mu    sync.Mutex
+	order *list.List
+	keys  map[string]*list.Element
+}
+
+func newRecency() *recency {
+	return &recency{order: list.New(), keys: make(map[string]*list.Element)}
+}
+
+func (r *recency) touch(key string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if e, ok := r.keys[key]; ok {
+		r.order.MoveToFront(e)
+		return
+	}
+	r.keys[key] = r.order.PushFront(key)
+}
+
+func (r *recency) remove(key string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if e, ok := r.keys[key]; ok {
+		r.order.Remove(e)
+		delete(r.keys, key)
+	}
+}
+
+// oldest returns up to n keys, least recently used first.
+func (r *recency) oldest(n int) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	keys := make([]string, 0, min(n, r.order.Len()))
+	for e := r.order.Back(); e != nil && len(keys) < n; e = e.Prev() {
+		keys = append(keys, e.Value.(string))
+	}
+	return keys
+}
+
+// QuotesSaturated reports whether the quote map is at maxQuotes with no
+// quote left to evict but ones makers may still order against, in which
+// case new quotes are refused rather than evicting live ones.
+func (m *Manager) QuotesSaturated() bool {
+	limit := m.Config().MaxQuotes
+	if limit == 0 || m.quotes.Len() < limit {
+		return false
+	}
+
+	oldest := m.quoteRecency.oldest(1)
+	if len(oldest) == 0 {
+		return false
+	}
+	item, err := m.quotes.Get(oldest[0])
+	if err != nil {
+		return false
+	}
+	if item.Value().(QuoteEntry).Expired(time.Now()) {
+		return false
+	}
+
+	metrics.QuotesSaturated.Add(1)
+	return true
+}
+
+// boundQuotes evicts the least recently used quotes past maxQuotes. Evicted
+// quotes count as expired in the conversion analytics.
+func (m *Manager) boundQuotes() {
+	limit := m.Config().MaxQuotes
+	if limit == 0 {
+		return
+	}
+
+	excess := m.quotes.Len() - limit
+	if excess <= 0 {
+		return
+	}
+	for _, key := range m.quoteRecency.oldest(excess) {
+		m.quoteRecency.remove(key)
+		item, err := m.quotes.Delete(key)
+		if err != nil {
+			continue
+		}
+		metrics.LRUEvicted.Add(QuoteKind, 1)
+		if quote, ok := item.Value().(QuoteEntry); ok {
+			m.conversions.Expired(key, quotePair(quote))
+		}
+	}
+}
+
+// boundOrders moves the least recently used settled orders past maxOrders to
+// the archive, where they stay queryable for ArchiveTTL. Pending orders are
+// never evicted, so the map may stay above maxOrders while they are.
+func (m *Manager) boundOrders() {
+	limit := m.Config().MaxOrders
+	if limit == 0 {
+		return
+	}
+
+	excess := m.orders.Len() - limit
+	if excess <= 0 {
+		return
+	}
+	for _, key := range m.orderRecency.oldest(m.orders.Len()) {
+		if excess == 0 {
+			return
+		}
+		orderEntry, err := m.peekOrder(key)
+		if err != nil {
+			m.orderRecency.remove(key)
+			continue
+		}
+
+		orderEntry.Lock()
+		pending := orderEntry.OrderStatus.Status == common.OrderStatusPending
+		orderEntry.Unlock()
+		if pending {
+			continue
+		}
+
+		m.orderRecency.remove(key)
+		if _, err := m.orders.Delete(key); err != nil {
+			continue
+		}
+		m.archive.Set(key, ttlmap.NewItem(orderEntry, ttlmap.WithTTL(ArchiveTTL)), nil)
+		m.deactivate(key)
+		metrics.LRUEvicted.Add(OrderKind, 1)
+		excess--
+	}
+}
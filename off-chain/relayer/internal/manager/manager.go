@@ -1,12 +1,33 @@
 package manager
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
+	"relayer/internal/accounting"
+	"relayer/internal/alert"
+	"relayer/internal/analytics"
+	"relayer/internal/bus"
+	"relayer/internal/chain"
+	"relayer/internal/common"
+	"relayer/internal/compliance"
+	"relayer/internal/config"
+	"relayer/internal/custody"
+	"relayer/internal/executor"
+	"relayer/internal/export"
+	"relayer/internal/logging"
+	"relayer/internal/network"
+	"relayer/internal/resolver"
+	"relayer/internal/routing"
+	"relayer/internal/session"
+	"relayer/internal/store"
+	"sync"
 	"time"
 
 	"github.com/block-vision/sui-go-sdk/sui"
+	ethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/google/uuid"
 	"github.com/imkira/go-ttlmap"
@@ -16,57 +37,385 @@ type Manager struct {
 	quotes      *ttlmap.Map
 	orders      *ttlmap.Map
 	broadcaster *Broadcaster
-	evmClient   *ethclient.Client
-	suiClient   *sui.Client
+	evmClients  map[common.ChainID]chain.EVMClient // by chain, see evm
+	suiClient   chain.SuiClient
+	suiWS       string                    // SUI_WS_URL, empty polls Sui escrows only
+	evmWS       map[common.ChainID]string // mempool endpoints by chain, see evmWSURLs
+	routes      *routing.Table
+	resolvers   *resolver.Registry
+	liveness    *resolver.Liveness
+	fees        *accounting.Ledger
+	surplus     *analytics.Surplus
+	integrators *analytics.IntegratorFees
+	conversions *analytics.Conversions
+	config      *config.Store
+	custody     *custody.Vault
+	sessions    *session.Sessions
+	private     bool               // PRIVATE_ORDER_STATUS, order updates only reach the maker's session
+	executor    *executor.Executor // nil without EXECUTOR_PRIVATE_KEY
+	store       *store.Store       // nil without DATABASE_PATH
+	exporter    *export.Exporter   // nil without EXPORT_URL
+	alerts      *alert.Notifier
+	screener    compliance.Screener
+	events      *bus.Bus
 	logger      *log.Logger
+
+	verifyMu      sync.Mutex
+	verifications *ttlmap.Map
+
+	// when a verification last alerted about an unavailable endpoint
+	rpcAlertMu sync.Mutex
+	rpcAlerted time.Time
+
+	// TXHASH events being handled, and the context their chain calls are
+	// interrupted with when a shutdown gives up waiting for them
+	inflight   *inflight
+	verifyCtx  context.Context
+	stopVerify context.CancelFunc
+
+	multiHopMu sync.Mutex
+	multiHop   *ttlmap.Map
+
+	intentMu sync.Mutex
+	intents  *ttlmap.Map
+
+	// fill transactions spotted in the mempool by tx hash, those waiting for
+	// a prewarm worker, the order hashes and hashlocks they are spotted by,
+	// and the escrow implementations of the factories, which never change
+	prewarmMu       sync.Mutex
+	prewarmed       *ttlmap.Map
+	prewarmQueue    chan prewarmJob
+	needleMu        sync.Mutex
+	needles         map[ethcommon.Hash]string
+	needlesAt       time.Time
+	implMu          sync.Mutex
+	implementations map[implementationKey]ethcommon.Address
+
+	// orders held back from broadcast until an admin approves them, by hash
+	quarantineMu sync.Mutex
+	quarantine   map[string]*quarantinedOrder
+
+	// chains whose head was behind, and chains unavailable or behind, at the
+	// last poll
+	headMu sync.Mutex
+	behind map[string]bool
+	outage map[string]bool
+
+	// active order hashes for the sweeper, ttlmap cannot be iterated
+	activeMu sync.Mutex
+	active   map[string]struct{}
+	archive  *ttlmap.Map
+	done     chan struct{}
+
+	// use order of quotes and orders, to hold maxQuotes and maxOrders
+	quoteRecency *recency
+	orderRecency *recency
 }
 
 func NewManager(logger *log.Logger) *Manager {
-	options := &ttlmap.Options{
-		InitialCapacity: 32,
-		OnWillExpire: func(key string, item ttlmap.Item) {
-			fmt.Printf("expired: [%s=%v]\n", key, item.Value())
-		},
-		OnWillEvict: func(key string, item ttlmap.Item) {
-			fmt.Printf("evicted: [%s=%v]\n", key, item.Value())
-		},
+	// init the clients, one per EVM chain of the profile: EVM_RPC_URL_<chain id>,
+	// or EVM_RPC_URL for the first one; chains without an endpoint are not served
+	evmClients := make(map[common.ChainID]chain.EVMClient)
+	dialed := make(map[string]chain.EVMClient)
+	first := true
+	for _, id := range network.Current().Chains {
+		if id.IsMove() {
+			continue
+		}
+		evmRPC := os.Getenv(fmt.Sprintf("EVM_RPC_URL_%d", id))
+		if evmRPC == "" && first {
+			evmRPC = os.Getenv("EVM_RPC_URL")
+		}
+		first = false
+		if evmRPC == "" {
+			logger.Printf("No RPC endpoint for chain %d, set EVM_RPC_URL_%d to serve it", id, id)
+			continue
+		}
+		if _, ok := dialed[evmRPC]; !ok {
+			client, err := ethclient.Dial(evmRPC)
+			if err != nil {
+				logger.Fatalf("failed to connect to the EVM RPC of chain %d: %v", id, err)
+			}
+			dialed[evmRPC] = client
+		}
+		evmClients[id] = dialed[evmRPC]
+	}
+	if len(evmClients) == 0 {
+		logger.Fatal("neither EVM_RPC_URL nor any EVM_RPC_URL_<chain id> environment variable is set")
 	}
 
-	// init the ttlmap for quotes and orders
-	quotes := ttlmap.New(options)
-	orders := ttlmap.New(options)
+	suiRPC := os.Getenv("SUI_RPC_URL")
+	if suiRPC == "" {
+		suiRPC = network.Current().SuiRPC
+	}
+	suiClient := (sui.NewSuiClient(suiRPC)).(*sui.Client)
 
+	return NewManagerWithChainClients(logger, evmClients, suiClient)
+}
+
+// NewManagerWithClients builds a Manager around already constructed chain
+// clients, e.g. the implementations in internal/chain/mock, evmClient serving
+// every supported EVM chain.
+func NewManagerWithClients(logger *log.Logger, evmClient chain.EVMClient, suiClient chain.SuiClient) *Manager {
+	evmClients := make(map[common.ChainID]chain.EVMClient)
+	for _, id := range common.SupportedChains() {
+		if !id.IsMove() {
+			evmClients[id] = evmClient
+		}
+	}
+	return NewManagerWithChainClients(logger, evmClients, suiClient)
+}
+
+// NewManagerWithChainClients builds a Manager around already constructed
+// chain clients, the EVM ones by chain.
+func NewManagerWithChainClients(logger *log.Logger, evmClients map[common.ChainID]chain.EVMClient, suiClient chain.SuiClient) *Manager {
 	// Initialize the broadcaster for comms
 	broadcaster := NewBroadcaster()
 
-	// init the clients
-	evmRPC := os.Getenv("EVM_RPC_URL")
-	if evmRPC == "" {
-		logger.Fatal("EVM_RPC_URL environment variable is not set")
+	// load the enabled corridors
+	profile := network.Current()
+	routes, err := routing.Load(os.Getenv("ROUTES_FILE"), profile.Routes)
+	if err != nil {
+		logger.Fatalf("failed to load routes: %v", err)
+	}
+	if profile.PinFactories {
+		if err := routes.RequireFactories(); err != nil {
+			logger.Fatalf("%s profile: %v, set them in ROUTES_FILE or with ESCROW_FACTORY_<chain id>", profile.Name, err)
+		}
 	}
-	evmClient, err := ethclient.Dial(evmRPC)
+
+	// load the resolvers allowed to claim fills, none disables authentication
+	resolvers, err := resolver.Load(os.Getenv("RESOLVERS_FILE"))
 	if err != nil {
-		logger.Fatalf("failed to connect to EVM RPC: %v", err)
+		logger.Fatalf("failed to load resolvers: %v", err)
 	}
 
-	suiRPC := os.Getenv("SUI_RPC_URL")
-	if suiRPC == "" {
-		logger.Fatal("SUI_RPC_URL environment variable is not set")
+	// load the runtime settings, reloadable with ReloadConfig
+	cfg, err := config.NewStore(os.Getenv("CONFIG_FILE"), profile.FinalityDelays)
+	if err != nil {
+		logger.Fatalf("failed to load config: %v", err)
+	}
+
+	// maker sign-in, shared by the API and WebSocket servers
+	sessions, err := session.FromEnv()
+	if err != nil {
+		logger.Fatalf("failed to set up maker sessions: %v", err)
+	}
+	if os.Getenv("PRIVATE_ORDER_STATUS") == "true" && !sessions.Enabled() {
+		logger.Fatal("PRIVATE_ORDER_STATUS needs MAKER_SESSION_DOMAIN, the host makers sign in to")
+	}
+
+	// relayer generated secrets, disabled without a key
+	vault, err := custody.FromEnv()
+	if err != nil {
+		logger.Fatalf("failed to load the secrets key: %v", err)
+	}
+
+	// the relayer's own transactions, refunds of stuck orders; disabled without a key
+	exec, err := executor.Load(os.Getenv("EXECUTOR_PRIVATE_KEY"))
+	if err != nil {
+		logger.Fatalf("failed to load EXECUTOR_PRIVATE_KEY: %v", err)
+	}
+
+	// open the persistent store, migrating its schema; none keeps state in memory only
+	var db *store.Store
+	if path := os.Getenv("DATABASE_PATH"); path != "" {
+		if db, err = store.Open(context.Background(), path); err != nil {
+			logger.Fatalf("failed to open database: %v", err)
+		}
+	}
+
+	// custodial secrets outlive restarts only in the store
+	if vault.Enabled() {
+		if db != nil {
+			vault.UseStore(storeSecretSets{db})
+		} else {
+			logger.Printf("Secret custody without DATABASE_PATH: generated secrets are lost on restart")
+		}
+	}
+
+	// daily snapshots of orders and events in object storage; disabled without EXPORT_URL
+	exporter, err := export.FromEnv(logger, db)
+	if err != nil {
+		logger.Fatalf("failed to configure export: %v", err)
 	}
-	suiClient := (sui.NewSuiClient(suiRPC)).(*sui.Client)
 
-	return &Manager{
-		quotes:      quotes,
-		orders:      orders,
+	// sanctions screening of makers and receivers, allowing everything when unset
+	screener, err := compliance.FromEnv()
+	if err != nil {
+		logger.Fatalf("failed to configure screening: %v", err)
+	}
+
+	// operator alerts, dropped when neither target is set
+	alerts := alert.New(os.Getenv("ALERT_WEBHOOK_URL"), os.Getenv("PAGERDUTY_ROUTING_KEY"), "fission-relayer/"+profile.Name)
+
+	m := &Manager{
 		broadcaster: broadcaster,
-		evmClient:   evmClient,
+		evmClients:  evmClients,
 		suiClient:   suiClient,
+		suiWS:       os.Getenv("SUI_WS_URL"),
+		evmWS:       evmWSURLs(),
+		routes:      routes,
+		resolvers:   resolvers,
+		liveness:    resolver.NewLiveness(),
+		fees:        accounting.NewLedger(),
+		surplus:     analytics.NewSurplus(),
+		integrators: analytics.NewIntegratorFees(),
+		conversions: analytics.NewConversions(),
+		config:      cfg,
+		custody:     vault,
+		sessions:    sessions,
+		private:     os.Getenv("PRIVATE_ORDER_STATUS") == "true",
+		executor:    exec,
+		store:       db,
+		exporter:    exporter,
+		alerts:      alerts,
+		screener:    screener,
+		events:      bus.New(),
 		logger:      logger,
+
+		behind: make(map[string]bool),
+		outage: make(map[string]bool),
+
+		quarantine: make(map[string]*quarantinedOrder),
+
+		implementations: make(map[implementationKey]ethcommon.Address),
+
+		active: make(map[string]struct{}),
+		done:   make(chan struct{}),
+
+		quoteRecency: newRecency(),
+		orderRecency: newRecency(),
+
+		inflight: newInflight(),
 	}
+	m.verifyCtx, m.stopVerify = context.WithCancel(context.Background())
+
+	// init the ttlmaps, their expiries are counted and logged by kind
+	m.quotes = ttlmap.New(m.ttlOptions(QuoteKind, m.onQuoteExpired))
+	m.orders = ttlmap.New(m.ttlOptions(OrderKind, m.onOrderExpired))
+	m.verifications = ttlmap.New(m.ttlOptions(VerificationKind, nil))
+	m.multiHop = ttlmap.New(m.ttlOptions(MultiHopKind, nil))
+	m.intents = ttlmap.New(m.ttlOptions(IntentKind, nil))
+	m.prewarmed = ttlmap.New(m.ttlOptions(PrewarmKind, nil))
+	m.archive = ttlmap.New(m.ttlOptions(ArchiveKind, m.onArchiveExpired))
+
+	m.subscribe()
+	m.restoreSecretSets()
+	m.restoreReleases()
+	m.restoreVerifications()
+	m.restoreLegs()
+	m.restoreFees()
+	go m.sweepLoop()
+	go m.headLoop()
+	go m.reconcileLoop()
+	m.watchMove()
+	m.watchMempool()
+
+	return m
 }
 
+// Surplus returns the settlement surplus records.
+func (m *Manager) Surplus() *analytics.Surplus {
+	return m.surplus
+}
+
+// IntegratorFees returns the integrator fees accrued per API key.
+func (m *Manager) IntegratorFees() *analytics.IntegratorFees {
+	return m.integrators
+}
+
+// Conversions returns which quotes were ordered against and which expired
+// unused.
+func (m *Manager) Conversions() *analytics.Conversions {
+	return m.conversions
+}
+
+// Events returns the internal event bus.
+func (m *Manager) Events() *bus.Bus {
+	return m.events
+}
+
+// Fees returns the protocol fee ledger.
+func (m *Manager) Fees() *accounting.Ledger {
+	return m.fees
+}
+
+// Resolvers returns the resolver registry.
+func (m *Manager) Resolvers() *resolver.Registry {
+	return m.resolvers
+}
+
+// Sessions returns the maker sessions.
+func (m *Manager) Sessions() *session.Sessions {
+	return m.sessions
+}
+
+// PrivateStatus reports whether order status and updates are only served to
+// the order's maker, signed in with a session.
+func (m *Manager) PrivateStatus() bool {
+	return m.private
+}
+
+// Liveness returns the connection uptime and ping accounting of the
+// resolvers.
+func (m *Manager) Liveness() *resolver.Liveness {
+	return m.liveness
+}
+
+// Config returns the active runtime config.
+func (m *Manager) Config() *config.Config {
+	return m.config.Current()
+}
+
+// logFor returns the logger of the lines logged within ctx, carrying the
+// attributes set on it with logging.With.
+func (m *Manager) logFor(ctx context.Context) *log.Logger {
+	return logging.Logger(ctx, m.logger)
+}
+
+// ReloadConfig re-reads CONFIG_FILE and applies it. Connections and in-flight
+// orders are kept; on error the previous config stays active.
+func (m *Manager) ReloadConfig() (*config.Config, error) {
+	cfg, err := m.config.Reload()
+	if err != nil {
+		return nil, err
+	}
+	if err := logging.SetLevel(cfg.LogLevel); err != nil {
+		m.logger.Printf("Config reloaded with %v, keeping log level", err)
+	}
+
+	m.logger.Printf("Config reloaded: log level %s, inbound %.0f/s burst %d, %d finality overrides",
+		logging.Level.Level(), cfg.InboundRate, cfg.InboundBurst, len(cfg.FinalityDelays))
+	return cfg, nil
+}
+
+// Routes returns the corridor routing table.
+func (m *Manager) Routes() *routing.Table {
+	return m.routes
+}
+
+// QuoteExpiry returns when a quote issued now with the given recommended
+// preset expires.
+func (m *Manager) QuoteExpiry(preset common.PresetEnum) time.Time {
+	return time.Now().Add(m.Config().QuoteTTL(string(preset)))
+}
+
+// SetQuote stores a quote until QuoteExpiredGrace after its ExpiresAt.
 func (m *Manager) SetQuote(quote QuoteEntry) error {
-	return m.quotes.Set(quote.QuoteID.String(), ttlmap.NewItem(quote, ttlmap.WithTTL(QuoteTTL)), nil)
+	if quote.QuotedAt.IsZero() {
+		quote.QuotedAt = time.Now()
+	}
+	ttl := time.Until(quote.ExpiresAt) + QuoteExpiredGrace
+	if err := m.quotes.Set(quote.QuoteID.String(), ttlmap.NewItem(quote, ttlmap.WithTTL(ttl)), nil); err != nil {
+		return err
+	}
+
+	m.quoteRecency.touch(quote.QuoteID.String())
+	m.boundQuotes()
+
+	m.conversions.Quoted(quotePair(quote))
+	return nil
 }
 
 func (m *Manager) GetQuote(quoteID uuid.UUID) (QuoteEntry, error) {
@@ -79,37 +428,95 @@ func (m *Manager) GetQuote(quoteID uuid.UUID) (QuoteEntry, error) {
 	if quote.QuoteID == uuid.Nil || quote.Quote == nil {
 		return QuoteEntry{}, fmt.Errorf("invalid quote type for ID: %s", quoteID)
 	}
+	m.quoteRecency.touch(quoteID.String())
 
 	return quote, nil
 }
 
-func (m *Manager) SetOrder(orderEntry OrderEntry) error {
-	quote, err := m.GetQuote(orderEntry.Order.QuoteID)
+func (m *Manager) SetOrder(orderEntry *OrderEntry) error {
+	// not looked up again: a held multi-hop leg outlives its quote
+	quote := orderEntry.Quote
+	if quote.Quote == nil {
+		return errors.New("order has no quote")
+	}
+
+	// kept until its escrows can be cancelled by anyone, see adjustDeadline
+	key := orderEntry.OrderHash.String()
+	if err := m.orders.Set(key, ttlmap.NewItem(orderEntry, ttlmap.WithExpiration(orderDeadline(orderEntry))), nil); err != nil {
+		return err
+	}
+
+	m.activeMu.Lock()
+	m.active[key] = struct{}{}
+	m.activeMu.Unlock()
+	m.orderRecency.touch(key)
+	m.boundOrders()
+
+	pair := quotePair(quote)
+	m.conversions.Converted(quote.QuoteID.String(), pair, key)
+
+	limitOrder := orderEntry.Order.LimitOrder
+	m.publishOrder(bus.OrderSubmitted, orderEvent{entry: orderEntry}, map[string]any{
+		"srcChain":     pair.SrcChain,
+		"dstChain":     pair.DstChain,
+		"maker":        limitOrder.Maker,
+		"makerAsset":   limitOrder.MakerAsset,
+		"makingAmount": limitOrder.MakingAmount,
+	})
+	return nil
+}
+
+func (m *Manager) GetOrder(orderHash string) (*OrderEntry, error) {
+	orderEntry, err := m.peekOrder(orderHash)
 	if err != nil {
-		return fmt.Errorf("failed to get quote for order: %w", err)
+		return nil, err
 	}
+	m.orderRecency.touch(orderHash)
 
-	return m.orders.Set(orderEntry.OrderHash.String(), ttlmap.NewItem(orderEntry, ttlmap.WithTTL(time.Second*time.Duration(quote.Quote.TimeLocks.SrcPublicCancellation))), nil)
+	return orderEntry, nil
 }
 
-func (m *Manager) GetOrder(orderHash string) (OrderEntry, error) {
+// peekOrder is GetOrder for the loops over every active order, which leave
+// the orders' use order alone.
+func (m *Manager) peekOrder(orderHash string) (*OrderEntry, error) {
 	item, err := m.orders.Get(orderHash)
 	if err != nil {
-		return OrderEntry{}, fmt.Errorf("order not found: %s", orderHash)
+		return nil, fmt.Errorf("order not found: %s", orderHash)
 	}
 
-	orderEntry := (item.Value()).(OrderEntry)
+	orderEntry := (item.Value()).(*OrderEntry)
 	if orderEntry.OrderHash.String() == "" {
-		return OrderEntry{}, fmt.Errorf("invalid order type for hash: %s", orderHash)
+		return nil, fmt.Errorf("invalid order type for hash: %s", orderHash)
 	}
 
 	return orderEntry, nil
 }
 
-func (m *Manager) RegisterReceiver(receiver chan []byte) uint64 {
+func (m *Manager) RegisterReceiver(receiver chan Message) uint64 {
 	return m.broadcaster.RegisterReceiver(receiver)
 }
 
+// Replay returns the retained messages receiver id would have received after since.
+func (m *Manager) Replay(id uint64, since uint64) []Message {
+	return m.broadcaster.Replay(id, since)
+}
+
+// JoinRoom subscribes a receiver to the updates of an order or maker room,
+// see OrderRoom and MakerRoom. It returns how many rooms the receiver is in.
+func (m *Manager) JoinRoom(id uint64, room string) int {
+	return m.broadcaster.Join(id, room)
+}
+
+// Rooms returns the rooms a receiver is subscribed to.
+func (m *Manager) Rooms(id uint64) []string {
+	return m.broadcaster.Rooms(id)
+}
+
+// LeaveRoom unsubscribes a receiver from a room.
+func (m *Manager) LeaveRoom(id uint64, room string) {
+	m.broadcaster.Leave(id, room)
+}
+
 func (m *Manager) UnregisterReceiver(id uint64) {
 	m.broadcaster.UnregisterReceiver(id)
 }
@@ -124,8 +531,15 @@ func (m *Manager) Broadcast(msg []byte) error {
 }
 
 func (m *Manager) Close() {
+	m.drainVerifications()
+	close(m.done)
 	m.quotes.Drain()
 	m.orders.Drain()
+	m.verifications.Drain()
+	m.multiHop.Drain()
+	m.intents.Drain()
+	m.prewarmed.Drain()
+	m.archive.Drain()
 	m.broadcaster.Close()
 	m.logger.Println("Manager closed, all resources drained/draining.")
 
@@ -133,5 +547,17 @@ func (m *Manager) Close() {
 	<-m.orders.Draining()
 	m.logger.Println("All quotes and orders have been drained successfully.")
 
-	m.evmClient.Close()
+	if m.store != nil {
+		if err := m.store.Close(); err != nil {
+			m.logger.Printf("Error closing database: %v", err)
+		}
+	}
+
+	closed := make(map[chain.EVMClient]bool)
+	for _, client := range m.evmClients {
+		if !closed[client] {
+			closed[client] = true
+			client.Close()
+		}
+	}
 }
@@ -0,0 +1,267 @@
+package manager
+
+import (
+	"context"
+	"relayer/internal/chain"
+	"relayer/internal/common"
+	"relayer/internal/metrics"
+	"strings"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/imkira/go-ttlmap"
+)
+
+// With EVM_WS_URL set, pending transactions of the chain it serves whose calldata mentions the hash
+// or a hashlock of an active order are taken for fills in the making: the
+// escrow implementations of the order's factories are read ahead, and once
+// the transaction is mined its escrow creation is decoded, so verifying the
+// fill's TXHASH skips those calls.
+
+// prewarmedTx is a fill transaction spotted in the mempool.
+type prewarmedTx struct {
+	// set once mined, for the side whose escrow it created
+	src *srcEscrow
+	dst *evmDstCreation
+}
+
+// evmDstCreation is the decoded creation of an EVM dst escrow, without its
+// balances, which are read when the fill is verified.
+type evmDstCreation struct {
+	event      *chain.EvmDstEscrowCreatedEvent
+	timestamp  chain.EventTime
+	immutables *chain.Immutables
+}
+
+// prewarmJob is a spotted fill transaction waiting for a prewarm worker.
+type prewarmJob struct {
+	orderEntry *OrderEntry
+	chainID    common.ChainID
+	txHash     ethcommon.Hash
+	p          *prewarmedTx
+}
+
+// implementationKey is an escrow factory on a chain and whether its dst
+// implementation is meant.
+type implementationKey struct {
+	chainID common.ChainID
+	factory ethcommon.Address
+	dst     bool
+}
+
+// watchMempool watches the mempool of each EVM chain with an endpoint, see
+// evmWSURLs.
+func (m *Manager) watchMempool() {
+	if len(m.evmWS) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-m.done
+		cancel()
+	}()
+
+	m.prewarmQueue = make(chan prewarmJob, MaxPrewarmed)
+	for range PrewarmWorkers {
+		go func() {
+			for {
+				select {
+				case <-m.done:
+					return
+				case job := <-m.prewarmQueue:
+					m.prewarm(job.orderEntry, job.chainID, job.txHash, job.p)
+				}
+			}
+		}()
+	}
+
+	for id, url := range m.evmWS {
+		watcher := chain.NewPendingWatcher(url)
+		watcher.OnError = func(err error) {
+			m.logger.Printf("Mempool watcher of chain %d: %v", id, err)
+		}
+		go watcher.Run(ctx, func(tx *types.Transaction) {
+			m.onPendingTx(id, tx)
+		})
+		m.logger.Printf("Watching the mempool of chain %d at %s for fills of active orders", id, url)
+	}
+}
+
+// onPendingTx queues the prewarming of the verification of tx, pending on
+// chainID, if it mentions an active order. With MaxPrewarmed transactions
+// tracked already it is skipped, and verified without prewarming.
+func (m *Manager) onPendingTx(chainID common.ChainID, tx *types.Transaction) {
+	needles := m.pendingNeedles()
+	needle, ok := chain.MatchCalldata(tx, func(word ethcommon.Hash) bool {
+		_, ok := needles[word]
+		return ok
+	})
+	if !ok {
+		return
+	}
+	orderEntry, err := m.peekOrder(needles[needle])
+	if err != nil {
+		return
+	}
+
+	key := strings.ToLower(tx.Hash().Hex())
+	m.prewarmMu.Lock()
+	defer m.prewarmMu.Unlock()
+
+	if _, err := m.prewarmed.Get(key); err == nil {
+		return
+	}
+	if m.prewarmed.Len() >= MaxPrewarmed {
+		metrics.PrewarmSkipped.Add(1)
+		return
+	}
+	p := &prewarmedTx{}
+	select {
+	case m.prewarmQueue <- prewarmJob{orderEntry: orderEntry, chainID: chainID, txHash: tx.Hash(), p: p}:
+		m.prewarmed.Set(key, ttlmap.NewItem(p, ttlmap.WithTTL(PrewarmTTL)), nil)
+	default:
+		// jobs outlived their tracking while waiting for a worker
+		metrics.PrewarmSkipped.Add(1)
+	}
+}
+
+// pendingNeedles returns the order hashes by the hashes and hashlocks of the
+// active orders, rebuilt at most every NeedleRefresh. The map is not modified
+// once returned.
+func (m *Manager) pendingNeedles() map[ethcommon.Hash]string {
+	m.needleMu.Lock()
+	defer m.needleMu.Unlock()
+
+	if m.needles != nil && time.Since(m.needlesAt) < NeedleRefresh {
+		return m.needles
+	}
+
+	m.activeMu.Lock()
+	hashes := make([]string, 0, len(m.active))
+	for hash := range m.active {
+		hashes = append(hashes, hash)
+	}
+	m.activeMu.Unlock()
+
+	needles := make(map[ethcommon.Hash]string, len(hashes)*2)
+	for _, hash := range hashes {
+		orderEntry, err := m.peekOrder(hash)
+		if err != nil {
+			continue
+		}
+		orderEntry.Lock()
+		needles[orderEntry.OrderHash] = hash
+		for _, hashlock := range orderHashlocks(orderEntry) {
+			needles[hashlock] = hash
+		}
+		orderEntry.Unlock()
+	}
+
+	m.needles, m.needlesAt = needles, time.Now()
+	return needles
+}
+
+// prewarm reads the escrow implementation of the order's factory on chainID,
+// then waits for txHash to be mined there and decodes the escrow it created.
+func (m *Manager) prewarm(orderEntry *OrderEntry, chainID common.ChainID, txHash ethcommon.Hash, p *prewarmedTx) {
+	ctx, cancel := context.WithTimeout(context.Background(), PrewarmTTL)
+	defer cancel()
+
+	client, err := m.evm(chainID)
+	if err != nil {
+		return
+	}
+
+	orderEntry.Lock()
+	srcEvm := escrowChain(orderEntry, SrcEscrow) == chainID
+	dstEvm := escrowChain(orderEntry, DstEscrow) == chainID
+	var srcFactory, dstFactory string
+	if quote := orderEntry.Quote.Quote; quote != nil {
+		srcFactory, dstFactory = quote.SrcEscrowFactory, quote.DstEscrowFactory
+	}
+	orderEntry.Unlock()
+
+	if srcEvm && ethcommon.IsHexAddress(srcFactory) {
+		m.escrowImplementation(ctx, chainID, ethcommon.HexToAddress(srcFactory), false)
+	}
+	if dstEvm && ethcommon.IsHexAddress(dstFactory) {
+		m.escrowImplementation(ctx, chainID, ethcommon.HexToAddress(dstFactory), true)
+	}
+
+	ticker := time.NewTicker(PrewarmPollInterval)
+	defer ticker.Stop()
+	for {
+		receipt, err := client.TransactionReceipt(ctx, txHash)
+		if err == nil {
+			if receipt.Status != types.ReceiptStatusSuccessful {
+				return
+			}
+			break
+		}
+		select {
+		case <-m.done:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+
+	if srcEvm {
+		if src, err := m.fetchEvmSrcEscrow(ctx, client, txHash); err == nil {
+			m.prewarmMu.Lock()
+			p.src = src
+			m.prewarmMu.Unlock()
+			return
+		}
+	}
+	if dstEvm {
+		if dst, err := m.fetchEvmDstCreation(ctx, client, txHash); err == nil {
+			m.prewarmMu.Lock()
+			p.dst = dst
+			m.prewarmMu.Unlock()
+		}
+	}
+}
+
+// prewarmedEscrow returns what prewarming decoded of txHash so far, if it
+// was spotted in the mempool.
+func (m *Manager) prewarmedEscrow(txHash string) (*srcEscrow, *evmDstCreation) {
+	m.prewarmMu.Lock()
+	defer m.prewarmMu.Unlock()
+
+	item, err := m.prewarmed.Get(strings.ToLower(txHash))
+	if err != nil {
+		return nil, nil
+	}
+	p := item.Value().(*prewarmedTx)
+	return p.src, p.dst
+}
+
+// escrowImplementation returns the src or dst escrow implementation of
+// factory on chainID, read once and cached.
+func (m *Manager) escrowImplementation(ctx context.Context, chainID common.ChainID, factory ethcommon.Address, dst bool) (ethcommon.Address, error) {
+	key := implementationKey{chainID, factory, dst}
+	m.implMu.Lock()
+	implementation, ok := m.implementations[key]
+	m.implMu.Unlock()
+	if ok {
+		return implementation, nil
+	}
+
+	client, err := m.evm(chainID)
+	if err != nil {
+		return ethcommon.Address{}, err
+	}
+	implementation, err = chain.FetchEscrowImplementation(ctx, client, factory, dst)
+	if err != nil {
+		return ethcommon.Address{}, err
+	}
+
+	m.implMu.Lock()
+	m.implementations[key] = implementation
+	m.implMu.Unlock()
+	return implementation, nil
+}
@@ -0,0 +1,53 @@
+package manager
+
+import (
+	"fmt"
+	"relayer/internal/common"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/imkira/go-ttlmap"
+)
+
+func TestPrewarmLimits(t *testing.T) {
+	f := newFillFixture(t, common.Sui)
+	orderEntry := f.order(t, SingleFill, nil)
+	// a fill transaction mentioning the order, distinct by nonce
+	fill := func(nonce uint64) *types.Transaction {
+		return types.NewTx(&types.LegacyTx{Nonce: nonce, Data: orderEntry.OrderHash.Bytes()})
+	}
+	tracked := func(tx *types.Transaction) bool {
+		_, err := f.m.prewarmed.Get(strings.ToLower(tx.Hash().Hex()))
+		return err == nil
+	}
+
+	// no workers, so jobs stay queued
+	f.m.prewarmQueue = make(chan prewarmJob, 1)
+
+	first := fill(0)
+	f.m.onPendingTx(common.EthereumMainnet, first)
+	if !tracked(first) || len(f.m.prewarmQueue) != 1 {
+		t.Fatal("fill transaction was not queued")
+	}
+	f.m.onPendingTx(common.EthereumMainnet, first)
+	if len(f.m.prewarmQueue) != 1 {
+		t.Fatal("fill transaction was queued twice")
+	}
+
+	second := fill(1)
+	f.m.onPendingTx(common.EthereumMainnet, second)
+	if tracked(second) {
+		t.Fatal("fill transaction was tracked with the queue full")
+	}
+
+	<-f.m.prewarmQueue
+	for i := f.m.prewarmed.Len(); i < MaxPrewarmed; i++ {
+		f.m.prewarmed.Set(fmt.Sprint(i), ttlmap.NewItem(&prewarmedTx{}, ttlmap.WithTTL(PrewarmTTL)), nil)
+	}
+	third := fill(2)
+	f.m.onPendingTx(common.EthereumMainnet, third)
+	if tracked(third) || len(f.m.prewarmQueue) != 0 {
+		t.Fatalf("fill transaction was tracked beyond %d", MaxPrewarmed)
+	}
+}
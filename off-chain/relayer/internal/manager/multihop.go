@@ -0,0 +1,251 @@
+package manager
+
+import (
+	"context"
+	"relayer/internal/auction"
+	"relayer/internal/store"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/imkira/go-ttlmap"
+)
+
+// multiHopOrders tracks the linked orders of one multi-hop quote. A leg's
+// order is only broadcast once the previous leg has settled, since the maker
+// funds it with what the previous leg delivers.
+type multiHopOrders struct {
+	mu      sync.Mutex
+	settled []bool
+	held    map[int]*OrderEntry
+}
+
+// DispatchOrder stores and broadcasts an accepted order, unless it is a
+// later leg of a multi-hop quote whose previous leg has not settled yet.
+// Such orders are held, and persisted, until settleLeg broadcasts them, so
+// their auction and deadline only start then.
+func (m *Manager) DispatchOrder(orderEntry *OrderEntry) error {
+	leg := orderEntry.Quote.Leg
+	if leg == nil || leg.Index == 0 {
+		return m.broadcastOrder(orderEntry)
+	}
+
+	state := m.multiHopOrders(leg)
+	state.mu.Lock()
+	if state.settled[leg.Index-1] {
+		state.mu.Unlock()
+		return m.broadcastOrder(orderEntry)
+	}
+	state.held[leg.Index] = orderEntry
+	state.mu.Unlock()
+
+	m.persistHeldLeg(orderEntry)
+	m.logger.Printf("Holding leg %d/%d of quote %s until the previous leg settles", leg.Index+1, leg.Count, leg.ParentID)
+	return nil
+}
+
+// broadcastOrder stores an order, then broadcasts it, so resolvers can report
+// fills right away.
+func (m *Manager) broadcastOrder(orderEntry *OrderEntry) error {
+	if err := m.SetOrder(orderEntry); err != nil {
+		return err
+	}
+	return m.HandleOrderEvent(*orderEntry.Order)
+}
+
+// settleLeg marks the order's leg as settled once the order was fully filled
+// and executed, that is, the maker received all the previous leg delivers,
+// and broadcasts the next leg if it was held.
+func (m *Manager) settleLeg(orderEntry *OrderEntry) {
+	leg := orderEntry.Quote.Leg
+	if leg == nil {
+		return
+	}
+	orderEntry.Lock()
+	filled := orderEntry.fullyFilled()
+	orderEntry.Unlock()
+	if !filled {
+		return
+	}
+
+	state := m.multiHopOrders(leg)
+	state.mu.Lock()
+	state.settled[leg.Index] = true
+	next, ok := state.held[leg.Index+1]
+	delete(state.held, leg.Index+1)
+	state.mu.Unlock()
+
+	m.persistSettledLeg(orderEntry)
+	if ok {
+		m.logger.Printf("Leg %d/%d of quote %s settled, broadcasting the next leg", leg.Index+1, leg.Count, leg.ParentID)
+		m.broadcastHeldLeg(next, time.Now())
+	}
+}
+
+// broadcastHeldLeg starts the auction of a held leg at now and broadcasts it.
+func (m *Manager) broadcastHeldLeg(orderEntry *OrderEntry, now time.Time) {
+	leg := orderEntry.Quote.Leg
+	if quote := orderEntry.Quote.Quote; quote != nil {
+		curve := auction.FromPreset(now, quote.Presets[quote.RecommendedPreset])
+		orderEntry.OrderStatus.CreatedAt = now.Format(time.RFC3339)
+		orderEntry.OrderStatus.AuctionStartDate = curve.Start.Unix()
+	}
+	orderEntry.SubmittedAt = now
+
+	if err := m.broadcastOrder(orderEntry); err != nil {
+		m.logger.Printf("failed to broadcast leg %d of quote %s: %v", leg.Index+1, leg.ParentID, err)
+		return
+	}
+
+	if m.store == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), StoreTimeout)
+	defer cancel()
+
+	if err := m.store.BroadcastLeg(ctx, leg.ParentID.String(), leg.Index); err != nil {
+		m.logger.Printf("Failed to store broadcast of leg %d of quote %s: %v", leg.Index+1, leg.ParentID, err)
+	}
+}
+
+func (m *Manager) multiHopOrders(leg *QuoteLeg) *multiHopOrders {
+	m.multiHopMu.Lock()
+	defer m.multiHopMu.Unlock()
+
+	key := leg.ParentID.String()
+	if item, err := m.multiHop.Get(key); err == nil {
+		return item.Value().(*multiHopOrders)
+	}
+
+	state := &multiHopOrders{
+		settled: make([]bool, leg.Count),
+		held:    make(map[int]*OrderEntry),
+	}
+	m.multiHop.Set(key, ttlmap.NewItem(state, ttlmap.WithTTL(MultiHopTTL)), nil)
+	return state
+}
+
+// persistHeldLeg stores a held leg, so a restart does not lose it.
+func (m *Manager) persistHeldLeg(orderEntry *OrderEntry) {
+	if m.store == nil {
+		return
+	}
+
+	leg := orderEntry.Quote.Leg
+	order, state, _, err := encodeOrder(orderEntry)
+	if err != nil {
+		m.logger.Printf("Failed to encode leg %d of quote %s for the store: %v", leg.Index+1, leg.ParentID, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), StoreTimeout)
+	defer cancel()
+
+	err = m.store.HoldLeg(ctx, store.LegRecord{
+		ParentID:  leg.ParentID.String(),
+		Index:     leg.Index,
+		Count:     leg.Count,
+		OrderHash: orderEntry.OrderHash.Hex(),
+		Order:     order,
+		State:     state,
+		CreatedAt: orderEntry.SubmittedAt,
+	})
+	if err != nil {
+		m.logger.Printf("Failed to store held leg %d of quote %s: %v", leg.Index+1, leg.ParentID, err)
+	}
+}
+
+// persistSettledLeg records that the order's leg settled.
+func (m *Manager) persistSettledLeg(orderEntry *OrderEntry) {
+	if m.store == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), StoreTimeout)
+	defer cancel()
+
+	leg := orderEntry.Quote.Leg
+	err := m.store.SettleLeg(ctx, store.LegRecord{
+		ParentID:  leg.ParentID.String(),
+		Index:     leg.Index,
+		Count:     leg.Count,
+		OrderHash: orderEntry.OrderHash.Hex(),
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		m.logger.Printf("Failed to store settlement of leg %d of quote %s: %v", leg.Index+1, leg.ParentID, err)
+	}
+}
+
+// restoreLegs rebuilds the multi-hop quotes tracked before a restart,
+// reloading their held legs. A held leg whose previous leg settled while its
+// broadcast was interrupted is broadcast right away.
+func (m *Manager) restoreLegs() {
+	if m.store == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), StoreTimeout)
+	defer cancel()
+
+	since := time.Now().Add(-MultiHopTTL)
+	if err := m.store.DeleteLegs(ctx, since); err != nil {
+		m.logger.Printf("Failed to delete expired multi-hop legs: %v", err)
+	}
+	legs, err := m.store.Legs(ctx, since)
+	if err != nil {
+		m.logger.Printf("Failed to load multi-hop legs: %v", err)
+		return
+	}
+
+	var held []*OrderEntry
+	for _, rec := range legs {
+		parentID, err := uuid.Parse(rec.ParentID)
+		if err != nil || rec.Index < 0 || rec.Index >= rec.Count {
+			m.logger.Printf("Dropping invalid leg %d of quote %s", rec.Index+1, rec.ParentID)
+			continue
+		}
+		var orderEntry *OrderEntry
+		if len(rec.Order) > 0 {
+			if orderEntry, err = decodeOrder(rec.OrderHash, rec.Order, rec.State, rec.CreatedAt); err != nil {
+				m.logger.Printf("Dropping held leg %d of quote %s: %v", rec.Index+1, rec.ParentID, err)
+				continue
+			}
+			if leg := orderEntry.Quote.Leg; leg == nil || leg.Index != rec.Index || leg.Index == 0 {
+				m.logger.Printf("Dropping held leg %d of quote %s: its order is not that leg", rec.Index+1, rec.ParentID)
+				continue
+			}
+		}
+
+		state := m.multiHopOrders(&QuoteLeg{ParentID: parentID, Index: rec.Index, Count: rec.Count})
+		state.mu.Lock()
+		if rec.Index < len(state.settled) {
+			state.settled[rec.Index] = state.settled[rec.Index] || rec.Settled
+			if orderEntry != nil {
+				state.held[rec.Index] = orderEntry
+				held = append(held, orderEntry)
+			}
+		}
+		state.mu.Unlock()
+	}
+
+	var broadcast int
+	for _, orderEntry := range held {
+		leg := orderEntry.Quote.Leg
+		state := m.multiHopOrders(leg)
+		state.mu.Lock()
+		ready := state.settled[leg.Index-1] && state.held[leg.Index] == orderEntry
+		if ready {
+			delete(state.held, leg.Index)
+		}
+		state.mu.Unlock()
+		if ready {
+			m.broadcastHeldLeg(orderEntry, time.Now())
+			broadcast++
+		}
+	}
+
+	if len(held) > 0 {
+		m.logger.Printf("Restored %d held multi-hop legs, broadcast %d whose previous leg settled", len(held), broadcast)
+	}
+}
@@ -0,0 +1,78 @@
+package manager
+
+import (
+	"context"
+	"path/filepath"
+	"relayer/internal/common"
+	"relayer/internal/resolver"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestHeldLeg(t *testing.T) {
+	t.Setenv("DATABASE_PATH", filepath.Join(t.TempDir(), "relayer.db"))
+	f := newFillFixture(t, common.Base)
+	parentID := uuid.New()
+	asLeg := func(idx int) func(*OrderEntry) {
+		return func(orderEntry *OrderEntry) {
+			orderEntry.Quote.Leg = &QuoteLeg{ParentID: parentID, Index: idx, Count: 2}
+		}
+	}
+
+	heldAt := time.Now().Add(-time.Hour)
+	next := f.newOrder(t, SingleFill, func(orderEntry *OrderEntry) {
+		asLeg(1)(orderEntry)
+		orderEntry.SubmittedAt = heldAt
+	})
+	nextHash := next.OrderHash.Hex()
+	if err := f.m.DispatchOrder(next); err != nil {
+		t.Fatalf("DispatchOrder = %v", err)
+	}
+	if _, err := f.m.GetOrder(nextHash); err == nil {
+		t.Fatal("held leg stored before the previous leg settled")
+	}
+
+	f.restart()
+
+	state := f.m.multiHopOrders(next.Quote.Leg)
+	state.mu.Lock()
+	restored := state.held[1]
+	state.mu.Unlock()
+	if restored == nil || restored.OrderHash != next.OrderHash {
+		t.Fatal("held leg not restored")
+	}
+
+	// not settled before it is fully filled
+	first := f.order(t, SingleFill, asLeg(0))
+	f.m.settleLeg(first)
+	if _, err := f.m.GetOrder(nextHash); err == nil {
+		t.Fatal("held leg broadcast before the previous leg was filled")
+	}
+
+	claimant := &resolver.Resolver{ID: "resolver-1", EVMAddress: f.taker.Hex()}
+	if err := f.m.HandleReceiveEvent(claimant, []byte(f.deploy(t, first, f.fill(first, 0)))); err != nil {
+		t.Fatalf("HandleReceiveEvent = %v", err)
+	}
+	f.m.settleLeg(first)
+
+	broadcast, err := f.m.GetOrder(nextHash)
+	if err != nil {
+		t.Fatalf("held leg not broadcast once the previous leg settled: %v", err)
+	}
+	if !broadcast.SubmittedAt.After(heldAt.Add(time.Minute)) {
+		t.Errorf("broadcast leg submitted at %v, want its auction started at broadcast", broadcast.SubmittedAt)
+	}
+	if deadline := orderDeadline(broadcast); !deadline.After(time.Now()) {
+		t.Errorf("broadcast leg deadline = %v, want after its broadcast", deadline)
+	}
+
+	legs, err := f.m.store.Legs(context.Background(), time.Time{})
+	if err != nil {
+		t.Fatalf("Legs = %v", err)
+	}
+	if len(legs) != 2 || !legs[0].Settled || legs[1].Settled || len(legs[1].Order) != 0 {
+		t.Errorf("stored legs = %+v, want the first settled and the second broadcast", legs)
+	}
+}
@@ -0,0 +1,304 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"relayer/internal/chain"
+	"relayer/internal/common"
+	"relayer/internal/extension"
+	"relayer/internal/hashlock"
+	"relayer/internal/store"
+	"strings"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/imkira/go-ttlmap"
+)
+
+// storedState is the part of an OrderEntry besides the order itself that a
+// restarted relayer needs to finish the order.
+type storedState struct {
+	OrderType      OrderType           `json:"orderType"`
+	OrderStatus    *common.OrderStatus `json:"orderStatus"`
+	Quote          QuoteEntry          `json:"quote"`
+	Fee            *OrderFee           `json:"fee"`
+	IntegratorFee  *OrderFee           `json:"integratorFee,omitempty"`
+	EscrowDeadline time.Time           `json:"escrowDeadline"`
+	DstReceiver    string              `json:"dstReceiver"`
+	Hashlock       hashlock.Algorithm  `json:"hashlock"`
+}
+
+// persistOrder writes a submitted order to the persistent store, if any.
+// Failures are logged: the in-memory state stays authoritative.
+func (m *Manager) persistOrder(orderEntry *OrderEntry) {
+	if m.store == nil || orderEntry.Order == nil {
+		return
+	}
+
+	order, state, status, err := encodeOrder(orderEntry)
+	if err != nil {
+		m.logger.Printf("Failed to encode order %s for the store: %v", orderEntry.OrderHash.Hex(), err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), StoreTimeout)
+	defer cancel()
+
+	limitOrder := orderEntry.Order.LimitOrder
+	rec := store.OrderRecord{
+		OrderHash:    orderEntry.OrderHash.Hex(),
+		SrcChainID:   orderEntry.Order.SrcChainID.String(),
+		Maker:        limitOrder.Maker,
+		MakerAsset:   limitOrder.MakerAsset,
+		TakerAsset:   limitOrder.TakerAsset,
+		MakingAmount: limitOrder.MakingAmount,
+		TakingAmount: limitOrder.TakingAmount,
+		Status:       string(status),
+		QuoteID:      orderEntry.Order.QuoteID.String(),
+		Order:        order,
+		State:        state,
+		SubmittedAt:  orderEntry.SubmittedAt,
+	}
+	if req := orderEntry.Quote.QuoteRequest; req != nil {
+		rec.DstChainID = req.DstChain
+	}
+	if err := m.store.PutOrder(ctx, rec); err != nil {
+		m.logger.Printf("Failed to store order %s: %v", rec.OrderHash, err)
+		return
+	}
+
+	if !orderEntry.Quote.QuotedAt.IsZero() {
+		m.recordStage(rec.OrderHash, StageQuoted, orderEntry.Quote.QuotedAt)
+	}
+	m.recordStage(rec.OrderHash, StageSubmitted, orderEntry.SubmittedAt)
+}
+
+// encodeOrder encodes an order and the state needed to restore it, see
+// decodeOrder, along with its status.
+func encodeOrder(orderEntry *OrderEntry) (order, state []byte, status common.OrderStatusMode, err error) {
+	if order, err = json.Marshal(orderEntry.Order); err != nil {
+		return nil, nil, "", err
+	}
+
+	orderEntry.Lock()
+	orderStatus := orderEntry.OrderStatus
+	state, err = json.Marshal(storedState{
+		OrderType:      orderEntry.OrderType,
+		OrderStatus:    &orderStatus,
+		Quote:          orderEntry.Quote,
+		Fee:            orderEntry.Fee,
+		IntegratorFee:  orderEntry.IntegratorFee,
+		EscrowDeadline: orderEntry.EscrowDeadline,
+		DstReceiver:    orderEntry.DstReceiver,
+		Hashlock:       orderEntry.Hashlock,
+	})
+	orderEntry.Unlock()
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("encoding state: %w", err)
+	}
+	return order, state, orderStatus.Status, nil
+}
+
+// persistStatus records an order status change in the persistent store, if any.
+func (m *Manager) persistStatus(orderEntry *OrderEntry, status string) {
+	if m.store == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), StoreTimeout)
+	defer cancel()
+
+	if err := m.store.SetOrderStatus(ctx, orderEntry.OrderHash.Hex(), status); err != nil {
+		m.logger.Printf("Failed to store status of order %s: %v", orderEntry.OrderHash.Hex(), err)
+	}
+}
+
+// restoreOrder loads an order lost by a restart back from the persistent
+// store, with the fills verified before the restart, see restoreFills.
+func (m *Manager) restoreOrder(ctx context.Context, orderHash string) (*OrderEntry, error) {
+	rec, err := m.store.GetOrder(ctx, orderHash)
+	if err != nil {
+		return nil, err
+	}
+
+	orderEntry, err := decodeOrder(rec.OrderHash, rec.Order, rec.State, rec.SubmittedAt)
+	if err != nil {
+		return nil, err
+	}
+	orderEntry.OrderStatus.Status = common.OrderStatusMode(rec.Status)
+
+	if err := m.restoreFills(ctx, orderEntry); err != nil {
+		return nil, fmt.Errorf("restoring fills: %w", err)
+	}
+	if err := m.restoreAccrued(ctx, orderEntry); err != nil {
+		return nil, fmt.Errorf("restoring fee accruals: %w", err)
+	}
+
+	key := orderEntry.OrderHash.String()
+	if err := m.orders.Set(key, ttlmap.NewItem(orderEntry, ttlmap.WithExpiration(orderDeadline(orderEntry))), nil); err != nil {
+		return nil, err
+	}
+
+	m.activeMu.Lock()
+	m.active[key] = struct{}{}
+	m.activeMu.Unlock()
+	m.orderRecency.touch(key)
+	m.boundOrders()
+
+	return orderEntry, nil
+}
+
+// decodeOrder rebuilds an order entry, without any fills, from what
+// encodeOrder stored.
+func decodeOrder(orderHash string, encoded, encodedState []byte, submittedAt time.Time) (*OrderEntry, error) {
+	var order common.Order
+	if err := json.Unmarshal(encoded, &order); err != nil {
+		return nil, fmt.Errorf("decoding order: %w", err)
+	}
+	var state storedState
+	if err := json.Unmarshal(encodedState, &state); err != nil {
+		return nil, fmt.Errorf("decoding order state: %w", err)
+	}
+	if state.OrderStatus == nil || state.Quote.QuoteRequest == nil {
+		return nil, errors.New("order was stored without its state")
+	}
+
+	var ext *extension.Extension
+	if !order.SrcChainID.IsMove() && !extension.IsEmpty(order.Extension) {
+		// validated when the order was submitted
+		var err error
+		if ext, err = extension.Decode(order.Extension); err != nil {
+			return nil, fmt.Errorf("decoding extension: %w", err)
+		}
+	}
+
+	return &OrderEntry{
+		OrderType:          state.OrderType,
+		OrderHash:          ethcommon.HexToHash(orderHash),
+		Order:              &order,
+		OrderStatus:        *state.OrderStatus,
+		Fee:                state.Fee,
+		IntegratorFee:      state.IntegratorFee,
+		Quote:              state.Quote,
+		SubmittedAt:        submittedAt,
+		FilledMakingAmount: new(big.Int),
+		Escrows:            make(map[string]EscrowSide),
+		Closed:             make(map[string]string),
+		Canonical:          make(map[int]*Verification),
+		EscrowDeadline:     state.EscrowDeadline,
+		DstReceiver:        state.DstReceiver,
+		Hashlock:           state.Hashlock,
+		SecretsID:          order.SecretsID,
+		Extension:          ext,
+	}, nil
+}
+
+// restoreFills rebuilds the verified fills of a restored order from its
+// recorded escrows: the canonical fill of each secret index, the escrows the
+// WITHDRAWN and CANCEL events are matched against, those already closed,
+// the making amount filled and how far each fill's secret got. Fills
+// allowed their secret that the maker had not revealed yet are handed to it
+// again.
+func (m *Manager) restoreFills(ctx context.Context, orderEntry *OrderEntry) error {
+	orderHash := orderEntry.OrderHash.Hex()
+	recs, err := m.store.Escrows(ctx, orderHash)
+	if err != nil {
+		return err
+	}
+	secrets, err := m.store.FillSecrets(ctx, orderHash)
+	if err != nil {
+		return err
+	}
+
+	for _, rec := range recs {
+		v, ok := orderEntry.Canonical[rec.HashIdx]
+		if !ok {
+			v = &Verification{OrderHash: orderHash, HashIdx: rec.HashIdx, VerifiedAt: rec.VerifiedAt}
+			orderEntry.Canonical[rec.HashIdx] = v
+		}
+		if err := restoreEscrow(v, rec); err != nil {
+			return fmt.Errorf("%s escrow of fill %d: %w", rec.Side, rec.HashIdx, err)
+		}
+
+		orderEntry.Escrows[strings.ToLower(rec.Escrow)] = EscrowSide(rec.Side)
+		if rec.ClosedTx != "" {
+			orderEntry.Closed[strings.ToLower(rec.Escrow)] = rec.ClosedTx
+		}
+		if rec.Side == string(SrcEscrow) && orderEntry.OrderType == MultiFill {
+			orderEntry.FilledMakingAmount.Add(orderEntry.FilledMakingAmount, v.SrcAmount)
+		}
+	}
+
+	for _, rec := range secrets {
+		if rec.Allowed {
+			if orderEntry.Allowed == nil {
+				orderEntry.Allowed = make(map[int]bool)
+			}
+			orderEntry.Allowed[rec.HashIdx] = true
+		}
+		if rec.Revealed {
+			if orderEntry.Revealed == nil {
+				orderEntry.Revealed = make(map[int]bool)
+			}
+			orderEntry.Revealed[rec.HashIdx] = true
+			continue
+		}
+		if v, ok := orderEntry.Canonical[rec.HashIdx]; ok && rec.Allowed {
+			orderEntry.OrderFills = append(orderEntry.OrderFills, common.ReadyToAcceptSecretFill{
+				Idx:                   rec.HashIdx,
+				SrcEscrowDeployTxHash: v.SrcTxHash,
+				DstEscrowDeployTxHash: v.DstTxHash,
+				Verification:          verificationReport(orderEntry, v, v.VerifiedAt),
+			})
+		}
+	}
+	return nil
+}
+
+// restoreEscrow fills in the side of v a recorded escrow is on. Escrows
+// recorded without their amount and hashlock take them from their
+// immutables.
+func restoreEscrow(v *Verification, rec store.EscrowRecord) error {
+	var immutables *chain.Immutables
+	if len(rec.Immutables) > 0 {
+		var recorded common.EscrowImmutables
+		if err := json.Unmarshal(rec.Immutables, &recorded); err != nil {
+			return fmt.Errorf("decoding immutables: %w", err)
+		}
+		var err error
+		if immutables, err = chainImmutables(&recorded); err != nil {
+			return err
+		}
+	}
+
+	amount := new(big.Int)
+	if rec.Amount != "" {
+		if _, ok := amount.SetString(rec.Amount, 10); !ok {
+			return fmt.Errorf("invalid amount %q", rec.Amount)
+		}
+	} else if immutables != nil {
+		amount.Set(immutables.Amount)
+	}
+	if rec.Hashlock != "" {
+		v.Hashlock = ethcommon.HexToHash(rec.Hashlock)
+	} else if immutables != nil {
+		v.Hashlock = immutables.Hashlock
+	}
+
+	at := chain.EvmEventTime(uint64(rec.DeployedAt.Unix()))
+	if id, err := common.ParseChainID(rec.ChainID); err == nil && id.IsMove() {
+		at = chain.MoveEventTime(rec.DeployedAt.UnixMilli())
+	}
+
+	if rec.Side == string(SrcEscrow) {
+		v.SrcEscrow, v.SrcTxHash, v.SrcTaker = rec.Escrow, rec.DeployTx, rec.Taker
+		v.SrcAmount, v.SrcTimestamp, v.SrcImmutables = amount, at, immutables
+	} else {
+		v.DstEscrow, v.DstTxHash, v.DstTaker = rec.Escrow, rec.DeployTx, rec.Taker
+		v.DstAmount, v.DstTimestamp, v.DstImmutables = amount, at, immutables
+	}
+	return nil
+}
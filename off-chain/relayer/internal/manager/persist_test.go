@@ -0,0 +1,171 @@
+package manager
+
+import (
+	"context"
+	"io"
+	"log"
+	"math/big"
+	"path/filepath"
+	"relayer/internal/common"
+	"relayer/internal/resolver"
+	"strings"
+	"testing"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// restart closes the fixture's manager and starts another one on the same
+// store and chains.
+func (f *fillFixture) restart() {
+	f.m.Close()
+	f.m = NewManagerWithClients(log.New(io.Discard, "", 0), f.evm, f.sui)
+}
+
+// partial returns the deployments filling part/4 of orderEntry with the
+// secret at hashIdx.
+func (f *fillFixture) partial(orderEntry *OrderEntry, hashIdx int, part int64) deployment {
+	d := f.fill(orderEntry, hashIdx)
+	d.srcAmount = big.NewInt(testMaking / 4 * part)
+	d.dstAmount = big.NewInt(testTaking / 4 * part)
+	return d
+}
+
+// waitStored waits for the store writes made in the background to satisfy
+// done.
+func waitStored(t *testing.T, what string, done func(ctx context.Context) bool) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for !done(ctx) {
+		select {
+		case <-ctx.Done():
+			t.Fatalf("%s was not stored", what)
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+func TestRestoreFills(t *testing.T) {
+	t.Setenv("DATABASE_PATH", filepath.Join(t.TempDir(), "relayer.db"))
+	f := newFillFixture(t, common.Base)
+	claimant := &resolver.Resolver{ID: "resolver-1", EVMAddress: f.taker.Hex()}
+
+	// half the order with the first secret, released on a timer the restart loses
+	orderEntry := f.order(t, MultiFill, nil)
+	orderHash := orderEntry.OrderHash.Hex()
+	if err := f.m.HandleReceiveEvent(claimant, []byte(f.deploy(t, orderEntry, f.partial(orderEntry, 0, 2)))); err != nil {
+		t.Fatalf("HandleReceiveEvent = %v", err)
+	}
+	orderEntry.Lock()
+	verified := *orderEntry.Canonical[0]
+	orderEntry.Unlock()
+
+	f.restart()
+
+	restored, err := f.m.GetOrder(orderHash)
+	if err != nil {
+		t.Fatalf("order with a pending release not restored: %v", err)
+	}
+	restored.Lock()
+	v, ok := restored.Canonical[0]
+	escrows := len(restored.Escrows)
+	filled := new(big.Int).Set(restored.FilledMakingAmount)
+	restored.Unlock()
+	if !ok {
+		t.Fatal("verified fill not restored")
+	}
+	if v.SrcEscrow != verified.SrcEscrow || v.DstEscrow != verified.DstEscrow || v.Hashlock != verified.Hashlock {
+		t.Errorf("restored fill = %s/%s/%s, want %s/%s/%s", v.SrcEscrow, v.DstEscrow, v.Hashlock, verified.SrcEscrow, verified.DstEscrow, verified.Hashlock)
+	}
+	if v.SrcAmount.Cmp(verified.SrcAmount) != 0 || v.DstAmount.Cmp(verified.DstAmount) != 0 {
+		t.Errorf("restored amounts = %s/%s, want %s/%s", v.SrcAmount, v.DstAmount, verified.SrcAmount, verified.DstAmount)
+	}
+	if !v.SrcTimestamp.Equal(verified.SrcTimestamp.Time) || v.SrcImmutables == nil || v.SrcImmutables.Amount.Cmp(verified.SrcAmount) != 0 {
+		t.Errorf("restored src escrow deployed at %v with immutables %+v", v.SrcTimestamp, v.SrcImmutables)
+	}
+	if escrows != 2 {
+		t.Errorf("restored order has %d escrows, want 2", escrows)
+	}
+	if filled.Cmp(big.NewInt(testMaking/2)) != 0 {
+		t.Errorf("restored filled making amount = %s, want %d", filled, testMaking/2)
+	}
+
+	if f.m.orderByEscrow(v.DstEscrow) != restored {
+		t.Error("restored escrow not matched to its order")
+	}
+	if f.m.expireIfStale(restored, time.Now().Add(time.Hour)) {
+		t.Error("sweeper expired a filled order")
+	}
+
+	// the filled half counts against fills after the restart
+	if err := f.m.HandleReceiveEvent(claimant, []byte(f.deploy(t, restored, f.partial(restored, 2, 4)))); err == nil || !strings.Contains(err.Error(), "overfills") {
+		t.Errorf("overfill after restart = %v, want an overfill error", err)
+	}
+	event := f.deploy(t, restored, f.partial(restored, 1, 1))
+	if err := f.m.HandleReceiveEvent(claimant, []byte(event)); err != nil {
+		t.Fatalf("next fill after restart = %v", err)
+	}
+
+	// the second fill waits for the first one's secret
+	dstTx := strings.Fields(event)[3]
+	srcTx := strings.Fields(event)[2]
+	if held := f.m.allowSecretRelease(orderHash, 1, srcTx, dstTx, true); !held {
+		t.Error("fill released before the restored fill of a lower secret")
+	}
+
+	// cancelling the restored dst escrow is recorded and unblocks the second fill
+	cancelTx := ethcommon.BytesToHash(randomBytes(32))
+	f.evm.AddReceipt(cancelTx, &types.Receipt{Status: types.ReceiptStatusSuccessful, Logs: []*types.Log{{
+		Address: ethcommon.HexToAddress(v.DstEscrow),
+		Topics:  []ethcommon.Hash{crypto.Keccak256Hash([]byte("EscrowCancelled()"))},
+	}}}, uint64(time.Now().Unix()))
+	if err := f.m.HandleReceiveEvent(claimant, []byte(CANCEL_EVENT+" "+orderHash+" "+cancelTx.Hex())); err != nil {
+		t.Fatalf("cancel of a restored escrow = %v", err)
+	}
+	restored.Lock()
+	allowed := restored.Allowed[1]
+	restored.Unlock()
+	if !allowed {
+		t.Fatal("fill still held after the lower fill was cancelled")
+	}
+
+	waitStored(t, "cancellation and secret release", func(ctx context.Context) bool {
+		recs, err := f.m.store.Escrows(ctx, orderHash)
+		if err != nil {
+			return false
+		}
+		secrets, err := f.m.store.FillSecrets(ctx, orderHash)
+		if err != nil {
+			return false
+		}
+		closed := false
+		for _, rec := range recs {
+			closed = closed || rec.ClosedTx == cancelTx.Hex()
+		}
+		return closed && len(secrets) == 1 && secrets[0].HashIdx == 1 && secrets[0].Allowed
+	})
+
+	f.restart()
+
+	restored, err = f.m.GetOrder(orderHash)
+	if err != nil {
+		t.Fatalf("order not restored again: %v", err)
+	}
+	restored.Lock()
+	defer restored.Unlock()
+	if got := restored.Closed[strings.ToLower(v.DstEscrow)]; got != cancelTx.Hex() {
+		t.Errorf("restored closing tx of the dst escrow = %q, want %s", got, cancelTx.Hex())
+	}
+	if !restored.Allowed[1] || restored.Allowed[0] {
+		t.Errorf("restored allowed fills = %v, want only 1", restored.Allowed)
+	}
+	if len(restored.OrderFills) != 1 || restored.OrderFills[0].Idx != 1 {
+		t.Errorf("restored fills ready for their secret = %+v, want fill 1", restored.OrderFills)
+	}
+	if restored.FilledMakingAmount.Cmp(big.NewInt(testMaking/4*3)) != 0 {
+		t.Errorf("restored filled making amount = %s, want %d", restored.FilledMakingAmount, testMaking/4*3)
+	}
+}
@@ -0,0 +1,82 @@
+package manager
+
+import (
+	"encoding/json"
+	"fmt"
+	"relayer/internal/common"
+	"strconv"
+	"strings"
+)
+
+// WS protocol versions. Clients negotiate one with the WebSocket subprotocol
+// fission.v<N> on connect; clients offering none speak ProtocolV1.
+const (
+	// ProtocolV1 is the original wire format: BROADC <ORDER_JSON> and
+	// SECRET <ORDER_HASH_HEX> <SECRET_HEX>
+	ProtocolV1 = 1
+	// ProtocolV2 sends ORDER and SECRET payloads as JSON objects naming the
+	// order hash, and the hash index for secrets: BROADC <OrderPayload JSON>
+	// and SECRET <SecretPayload JSON>
+	ProtocolV2 = 2
+
+	// LatestProtocol is the newest version the relayer speaks
+	LatestProtocol = ProtocolV2
+
+	protocolPrefix = "fission.v"
+)
+
+// OrderPayload is the ProtocolV2 ORDER payload.
+type OrderPayload struct {
+	OrderHash string        `json:"orderHash"`
+	Order     *common.Order `json:"order"`
+}
+
+// SecretPayload is the ProtocolV2 SECRET payload. HashIdx is omitted when the
+// secret's hashlock is only known on chain.
+type SecretPayload struct {
+	OrderHash string `json:"orderHash"`
+	HashIdx   *int   `json:"idx,omitempty"`
+	Secret    string `json:"secret"`
+}
+
+// Subprotocols are the WebSocket subprotocols of the supported versions,
+// newest first, the order the relayer prefers them in.
+func Subprotocols() []string {
+	names := make([]string, 0, LatestProtocol)
+	for v := LatestProtocol; v >= ProtocolV1; v-- {
+		names = append(names, protocolPrefix+strconv.Itoa(v))
+	}
+	return names
+}
+
+// ParseSubprotocol returns the version of a negotiated subprotocol,
+// ProtocolV1 when none was.
+func ParseSubprotocol(name string) (int, error) {
+	if name == "" {
+		return ProtocolV1, nil
+	}
+	v, err := strconv.Atoi(strings.TrimPrefix(name, protocolPrefix))
+	if err != nil || !strings.HasPrefix(name, protocolPrefix) || v < ProtocolV1 || v > LatestProtocol {
+		return 0, fmt.Errorf("unsupported protocol %q", name)
+	}
+	return v, nil
+}
+
+// Encode renders the message for a connection speaking version. Messages
+// without a rendering for it are the same in every version.
+func (msg Message) Encode(version int) []byte {
+	if data, ok := msg.Versions[version]; ok {
+		return data
+	}
+	return msg.Data
+}
+
+// versioned renders an event whose payload changed in ProtocolV2.
+func versioned(event string, v1 []byte, v2 any) ([]byte, map[int][]byte, error) {
+	data, err := json.Marshal(v2)
+	if err != nil {
+		return nil, nil, err
+	}
+	v1 = append([]byte(event+" "), v1...)
+	return v1, map[int][]byte{ProtocolV2: append([]byte(event+" "), data...)}, nil
+}
@@ -0,0 +1,182 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"relayer/internal/alert"
+	"relayer/internal/auction"
+	"relayer/internal/common"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MaxQuarantined bounds the orders waiting for an admin's approval; further
+// deviating orders are refused.
+const MaxQuarantined = 1000
+
+// quarantinedOrder is an order held back from broadcast because its price is
+// too far off the oracle price of its quote.
+type quarantinedOrder struct {
+	entry         *OrderEntry
+	deviationBps  int64
+	quarantinedAt time.Time
+}
+
+// PriceDeviation returns by how many bps the price an order implies, its
+// taking amount per making amount, is off the oracle price of its quote. ok
+// is false when the quote has no usable price.
+func (m *Manager) PriceDeviation(order *common.Order, quote QuoteEntry) (bps int64, ok bool) {
+	making, okMaking := new(big.Rat).SetString(order.LimitOrder.MakingAmount)
+	taking, okTaking := new(big.Rat).SetString(order.LimitOrder.TakingAmount)
+	if !okMaking || !okTaking || making.Sign() <= 0 {
+		return 0, false
+	}
+	oracle, ok := m.oraclePrice(quote)
+	if !ok {
+		return 0, false
+	}
+
+	off := new(big.Rat).Quo(new(big.Rat).Quo(taking, making), oracle)
+	off.Sub(off, big.NewRat(1, 1))
+	off.Abs(off)
+	off.Mul(off, big.NewRat(10_000, 1))
+
+	f, _ := off.Float64()
+	if f >= math.MaxInt64 {
+		return math.MaxInt64, true
+	}
+	return int64(f), true
+}
+
+// oraclePrice is the price of a quote in dst base units per src base unit:
+// from the USD prices of its tokens when both are listed with their decimals,
+// otherwise from its amounts.
+func (m *Manager) oraclePrice(quote QuoteEntry) (*big.Rat, bool) {
+	req, q := quote.QuoteRequest, quote.Quote
+	if req == nil || q == nil {
+		return nil, false
+	}
+
+	srcToken, srcListed := m.routes.Token(req.SrcChain, req.SrcTokenAddress)
+	dstToken, dstListed := m.routes.Token(req.DstChain, req.DstTokenAddress)
+	srcUSD, srcPriced := new(big.Rat).SetString(q.Prices.USD.SrcToken)
+	dstUSD, dstPriced := new(big.Rat).SetString(q.Prices.USD.DstToken)
+	if srcListed && dstListed && srcPriced && dstPriced && srcUSD.Sign() > 0 && dstUSD.Sign() > 0 {
+		price := new(big.Rat).Quo(srcUSD, dstUSD)
+		price.Mul(price, new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(dstToken.Decimals)), nil)))
+		price.Quo(price, new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(srcToken.Decimals)), nil)))
+		return price, true
+	}
+
+	src, okSrc := new(big.Rat).SetString(q.SrcTokenAmount)
+	dst, okDst := new(big.Rat).SetString(q.DstTokenAmount)
+	if !okSrc || !okDst || src.Sign() <= 0 || dst.Sign() <= 0 {
+		return nil, false
+	}
+	return new(big.Rat).Quo(dst, src), true
+}
+
+// Quarantine holds a submitted order back from broadcast until an admin
+// approves it, and alerts the operators.
+func (m *Manager) Quarantine(orderEntry *OrderEntry, deviationBps int64) error {
+	orderHash := orderEntry.OrderHash.Hex()
+
+	m.quarantineMu.Lock()
+	if _, held := m.quarantine[orderHash]; !held && len(m.quarantine) >= MaxQuarantined {
+		m.quarantineMu.Unlock()
+		return fmt.Errorf("%d orders already await approval", MaxQuarantined)
+	}
+	m.quarantine[orderHash] = &quarantinedOrder{entry: orderEntry, deviationBps: deviationBps, quarantinedAt: time.Now()}
+	m.quarantineMu.Unlock()
+
+	m.logger.Printf("Order %s quarantined, its price is %d bps off the oracle price", orderHash, deviationBps)
+	go m.alertQuarantine(orderEntry, deviationBps)
+	return nil
+}
+
+// alertQuarantine tells the operators an order awaits their approval.
+func (m *Manager) alertQuarantine(orderEntry *OrderEntry, deviationBps int64) {
+	if !m.alerts.Enabled() {
+		return
+	}
+
+	orderHash := orderEntry.OrderHash.Hex()
+	a := alert.Alert{
+		Key:      "quarantine-" + orderHash,
+		Summary:  fmt.Sprintf("order %s is %d bps off the oracle price and awaits approval", orderHash, deviationBps),
+		Severity: alert.Warning,
+		Details: map[string]any{
+			"maker":        orderEntry.Order.LimitOrder.Maker,
+			"srcChain":     orderEntry.Order.SrcChainID.String(),
+			"deviationBps": deviationBps,
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), alert.SendTimeout)
+	defer cancel()
+	if err := m.alerts.Send(ctx, a); err != nil {
+		m.logger.Printf("Failed to send quarantine alert for order %s: %v", orderHash, err)
+	}
+}
+
+// Quarantined lists the orders awaiting approval, oldest first.
+func (m *Manager) Quarantined() []common.QuarantinedOrder {
+	m.quarantineMu.Lock()
+	defer m.quarantineMu.Unlock()
+
+	orders := make([]common.QuarantinedOrder, 0, len(m.quarantine))
+	for hash, q := range m.quarantine {
+		order := q.entry.Order
+		orders = append(orders, common.QuarantinedOrder{
+			OrderHash:     hash,
+			SrcChainID:    order.SrcChainID.String(),
+			DstChainID:    q.entry.Quote.QuoteRequest.DstChain,
+			QuoteID:       order.QuoteID.String(),
+			Maker:         order.LimitOrder.Maker,
+			MakingAmount:  order.LimitOrder.MakingAmount,
+			TakingAmount:  order.LimitOrder.TakingAmount,
+			DeviationBps:  q.deviationBps,
+			QuarantinedAt: q.quarantinedAt,
+		})
+	}
+	sort.Slice(orders, func(i, j int) bool { return orders[i].QuarantinedAt.Before(orders[j].QuarantinedAt) })
+	return orders
+}
+
+// TakeQuarantined removes an order from quarantine and returns it, for an
+// admin to broadcast or drop.
+func (m *Manager) TakeQuarantined(orderHash string) (*OrderEntry, error) {
+	m.quarantineMu.Lock()
+	defer m.quarantineMu.Unlock()
+
+	for hash, q := range m.quarantine {
+		if strings.EqualFold(hash, orderHash) {
+			delete(m.quarantine, hash)
+			return q.entry, nil
+		}
+	}
+	return nil, fmt.Errorf("order %s is not quarantined", orderHash)
+}
+
+// sweepQuarantine drops quarantined orders whose auction ended, as nobody
+// could fill them any more.
+func (m *Manager) sweepQuarantine(now time.Time) {
+	m.quarantineMu.Lock()
+	defer m.quarantineMu.Unlock()
+
+	for hash, q := range m.quarantine {
+		quote := q.entry.Quote.Quote
+		if quote == nil {
+			continue
+		}
+		curve := auction.FromPreset(q.entry.SubmittedAt, quote.Presets[quote.RecommendedPreset])
+		if now.Before(curve.End()) {
+			continue
+		}
+		delete(m.quarantine, hash)
+		m.logger.Printf("Quarantined order %s dropped unapproved, its auction ended", hash)
+	}
+}
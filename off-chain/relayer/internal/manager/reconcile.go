@@ -0,0 +1,174 @@
+package manager
+
+import (
+	"context"
+	"math/big"
+	"relayer/internal/chain"
+	"relayer/internal/common"
+	"strings"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+// reconcileLoop periodically re-scans the escrows of active orders until the
+// manager is closed, so withdrawals and cancellations made while no resolver
+// reported them, e.g. while the WS server was down, still reach the order.
+func (m *Manager) reconcileLoop() {
+	ticker := time.NewTicker(ReconcileInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.done:
+			return
+		case <-ticker.C:
+			m.reconcile()
+		}
+	}
+}
+
+// reconcile checks every escrow of the verified fills of each non-terminal
+// active order against the chain.
+func (m *Manager) reconcile() {
+	m.activeMu.Lock()
+	hashes := make([]string, 0, len(m.active))
+	for hash := range m.active {
+		hashes = append(hashes, hash)
+	}
+	m.activeMu.Unlock()
+
+	for _, hash := range hashes {
+		orderEntry, err := m.peekOrder(hash)
+		if err != nil {
+			continue
+		}
+		m.reconcileOrder(orderEntry)
+	}
+}
+
+// reconciledEscrow is an escrow of the verified fill at hashIdx and the
+// transaction that deployed it.
+type reconciledEscrow struct {
+	side     EscrowSide
+	escrow   string
+	deployTx string
+	hashIdx  int
+}
+
+func (m *Manager) reconcileOrder(orderEntry *OrderEntry) {
+	orderEntry.Lock()
+	status := orderEntry.OrderStatus.Status
+	var escrows []reconciledEscrow
+	for idx, v := range orderEntry.Canonical {
+		for _, e := range []reconciledEscrow{{SrcEscrow, v.SrcEscrow, v.SrcTxHash, idx}, {DstEscrow, v.DstEscrow, v.DstTxHash, idx}} {
+			if _, closed := orderEntry.Closed[strings.ToLower(e.escrow)]; !closed {
+				escrows = append(escrows, e)
+			}
+		}
+	}
+	orderEntry.Unlock()
+
+	if status != common.OrderStatusPending && status != common.OrderStatusRefunding {
+		return
+	}
+
+	for _, e := range escrows {
+		ctx, cancel := context.WithTimeout(context.Background(), ChainCallTimeout)
+		state, txHash, err := m.fetchEscrowState(ctx, orderEntry, e)
+		cancel()
+		if err != nil {
+			m.logger.Printf("Reconciler: failed to fetch %s escrow %s of order %s: %v", e.side, e.escrow, orderEntry.OrderHash.Hex(), err)
+			continue
+		}
+		if state != chain.EscrowActive {
+			m.correctEscrow(orderEntry, e, state, txHash)
+		}
+	}
+}
+
+// fetchEscrowState reads the on-chain state of e, on the order's src or dst
+// chain depending on its side.
+func (m *Manager) fetchEscrowState(ctx context.Context, orderEntry *OrderEntry, e reconciledEscrow) (chain.EscrowState, string, error) {
+	var move bool
+	if e.side == SrcEscrow {
+		move = orderEntry.Order != nil && orderEntry.Order.SrcChainID.IsMove()
+	} else {
+		move = orderEntry.Quote.QuoteRequest != nil && common.IsSuiChain(orderEntry.Quote.QuoteRequest.DstChain)
+	}
+	if move {
+		return chain.FetchMoveEscrowState(ctx, m.suiClient, e.escrow, moveEscrowPackage(orderEntry))
+	}
+
+	client, err := m.evm(escrowChain(orderEntry, e.side))
+	if err != nil {
+		return "", "", err
+	}
+	// the escrow's logs start at its deployment
+	receipt, err := client.TransactionReceipt(ctx, ethcommon.HexToHash(e.deployTx))
+	if err != nil {
+		return "", "", err
+	}
+	return chain.FetchEvmEscrowState(ctx, client, ethcommon.HexToAddress(e.escrow), receipt.BlockNumber)
+}
+
+// correctEscrow applies a withdrawal or cancellation the relayer missed, the
+// way the WITHDRAWN and CANCEL handlers would have, and logs the discrepancy.
+func (m *Manager) correctEscrow(orderEntry *OrderEntry, e reconciledEscrow, state chain.EscrowState, txHash string) {
+	orderHash := orderEntry.OrderHash.Hex()
+
+	orderEntry.Lock()
+	if _, closed := orderEntry.Closed[strings.ToLower(e.escrow)]; closed {
+		// reported while we were looking
+		orderEntry.Unlock()
+		return
+	}
+	accrue := state == chain.EscrowWithdrawn && paidFee(orderEntry, e.escrow, e.hashIdx)
+	var settled *big.Int
+	if state == chain.EscrowWithdrawn {
+		settled = paidMaker(orderEntry, e.escrow, e.hashIdx)
+	}
+	orderEntry.Closed[strings.ToLower(e.escrow)] = txHash
+
+	refunded, executed := false, false
+	if state == chain.EscrowWithdrawn {
+		stopRefund(orderEntry, e.escrow)
+		executed = executeIfSettled(orderEntry, time.Now())
+	}
+	if state == chain.EscrowCancelled {
+		if e.side == SrcEscrow && orderEntry.OrderStatus.Status != common.OrderStatusCancelled {
+			orderEntry.OrderStatus.Status = common.OrderStatusCancelled
+			refunded = true
+		}
+		orderEntry.OrderStatus.CancelTx = &txHash
+		m.releaseHeld(orderEntry)
+	}
+	orderEntry.Unlock()
+
+	m.publishEscrowClosed(orderEntry, e.side, e.escrow, state, txHash)
+	m.logger.Printf("Reconciler: %s escrow %s of order %s was %s in %s without being reported", e.side, e.escrow, orderHash, state, txHash)
+
+	switch state {
+	case chain.EscrowWithdrawn:
+		if accrue {
+			m.accrueFee(orderEntry, e.hashIdx)
+		}
+		if settled != nil {
+			if err := m.recordSurplus(orderEntry, settled); err != nil {
+				m.logger.Printf("Reconciler: failed to record surplus for order %s: %v", orderHash, err)
+			}
+		}
+		m.notify(orderEntry, WITHDRAWN_EVENT, string(e.side), txHash)
+		m.recordStage(orderHash, StageWithdrawn, time.Now())
+		if executed {
+			m.notifyStatus(orderEntry, string(common.OrderStatusExecuted))
+			m.persistStatus(orderEntry, string(common.OrderStatusExecuted))
+			m.settleLeg(orderEntry)
+		}
+	case chain.EscrowCancelled:
+		if refunded {
+			m.notifyStatus(orderEntry, string(common.OrderStatusCancelled))
+			m.persistStatus(orderEntry, string(common.OrderStatusCancelled))
+		}
+	}
+}
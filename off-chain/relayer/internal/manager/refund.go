@@ -0,0 +1,160 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"relayer/internal/alert"
+	"relayer/internal/auction"
+	"relayer/internal/bus"
+	"relayer/internal/common"
+	"sort"
+	"strings"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+// refundIfStuck marks a pending order refunding once fillGrace has passed
+// since its auction end while some of its verified fills never had their
+// secret revealed, and returns those fills. Their src escrows can only be
+// cancelled from then on, refunding the maker.
+func (m *Manager) refundIfStuck(orderEntry *OrderEntry, now time.Time) []*Verification {
+	quote := orderEntry.Quote.Quote
+	if quote == nil {
+		return nil
+	}
+	curve := auction.FromPreset(orderEntry.SubmittedAt, quote.Presets[quote.RecommendedPreset])
+	if now.Before(curve.End().Add(time.Duration(m.Config().FillGrace))) {
+		return nil
+	}
+
+	orderEntry.Lock()
+	defer orderEntry.Unlock()
+
+	if orderEntry.OrderStatus.Status != common.OrderStatusPending {
+		return nil
+	}
+
+	var stuck []*Verification
+	for idx, v := range orderEntry.Canonical {
+		if orderEntry.Revealed[idx] {
+			continue
+		}
+		if _, closed := orderEntry.Closed[strings.ToLower(v.SrcEscrow)]; closed {
+			continue
+		}
+		stuck = append(stuck, v)
+	}
+	if len(stuck) == 0 {
+		return nil
+	}
+	sort.Slice(stuck, func(i, j int) bool { return stuck[i].HashIdx < stuck[j].HashIdx })
+
+	orderEntry.OrderStatus.Status = common.OrderStatusRefunding
+	return stuck
+}
+
+// refund announces an order given up with its stuck fills. It stays active,
+// so the reconciler records the cancellations of its escrows.
+func (m *Manager) refund(orderEntry *OrderEntry, stuck []*Verification) {
+	escrows := make([]string, 0, len(stuck))
+	for _, v := range stuck {
+		escrows = append(escrows, v.SrcEscrow)
+	}
+
+	m.publishOrder(bus.OrderRefunding, orderEvent{entry: orderEntry, stuck: stuck}, map[string]any{
+		"srcEscrows": escrows,
+	})
+	m.logger.Printf("Order %s unfilled past its grace, refunding src escrows %s", orderEntry.OrderHash.Hex(), strings.Join(escrows, ", "))
+}
+
+// alertRefund tells the alert webhook about a refunding order, so the maker
+// can be reached outside the WS.
+func (m *Manager) alertRefund(e bus.Event, ev orderEvent) {
+	if !m.alerts.Enabled() {
+		return
+	}
+
+	details := map[string]any{"srcEscrows": e.Details["srcEscrows"]}
+	if order := ev.entry.Order; order != nil {
+		details["maker"] = order.LimitOrder.Maker
+		details["srcChain"] = order.SrcChainID.String()
+	}
+	a := alert.Alert{
+		Key:      "refund-" + e.Subject,
+		Summary:  fmt.Sprintf("order %s was not filled in time, %d src escrows to refund", e.Subject, len(ev.stuck)),
+		Severity: alert.Warning,
+		Details:  details,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), alert.SendTimeout)
+	defer cancel()
+	if err := m.alerts.Send(ctx, a); err != nil {
+		m.logger.Printf("Failed to send refund alert for order %s: %v", e.Subject, err)
+	}
+}
+
+// scheduleRefund cancels the src escrow of a stuck fill with the executor
+// once its public cancellation window opens. Sui escrows and escrows without
+// decoded immutables are left to their maker or taker.
+func (m *Manager) scheduleRefund(orderEntry *OrderEntry, v *Verification) {
+	if !m.executor.Enabled() {
+		return
+	}
+	if orderEntry.Order == nil || !orderEntry.Order.SrcChainID.IsEVM() || v.SrcImmutables == nil {
+		m.logger.Printf("Src escrow %s of order %s cannot be cancelled by the executor", v.SrcEscrow, orderEntry.OrderHash.Hex())
+		return
+	}
+
+	at := v.SrcTimestamp.Add(orderTimelocks(orderEntry).srcPublicCancellation)
+	timer := time.AfterFunc(time.Until(at), func() {
+		m.cancelSrcEscrow(orderEntry, v)
+	})
+
+	orderEntry.Lock()
+	if orderEntry.Refunds == nil {
+		orderEntry.Refunds = make(map[string]*time.Timer)
+	}
+	orderEntry.Refunds[strings.ToLower(v.SrcEscrow)] = timer
+	orderEntry.Unlock()
+	m.logger.Printf("Src escrow %s of order %s will be cancelled at %s", v.SrcEscrow, orderEntry.OrderHash.Hex(), at.Format(time.RFC3339))
+}
+
+// cancelSrcEscrow sends the public cancellation of a stuck fill's src escrow,
+// unless it was closed meanwhile.
+func (m *Manager) cancelSrcEscrow(orderEntry *OrderEntry, v *Verification) {
+	orderEntry.Lock()
+	_, closed := orderEntry.Closed[strings.ToLower(v.SrcEscrow)]
+	delete(orderEntry.Refunds, strings.ToLower(v.SrcEscrow))
+	orderEntry.Unlock()
+	if closed {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), ChainCallTimeout)
+	defer cancel()
+
+	client, err := m.evm(orderEntry.Order.SrcChainID)
+	if err != nil {
+		m.logger.Printf("Failed to cancel src escrow %s of order %s: %v", v.SrcEscrow, orderEntry.OrderHash.Hex(), err)
+		return
+	}
+	chainID := new(big.Int).SetUint64(uint64(orderEntry.Order.SrcChainID))
+	txHash, err := m.executor.CancelSrcEscrow(ctx, client, chainID, ethcommon.HexToAddress(v.SrcEscrow), *v.SrcImmutables)
+	if err != nil {
+		m.logger.Printf("Failed to cancel src escrow %s of order %s: %v", v.SrcEscrow, orderEntry.OrderHash.Hex(), err)
+		return
+	}
+	m.logger.Printf("Sent cancellation %s of src escrow %s of order %s", txHash.Hex(), v.SrcEscrow, orderEntry.OrderHash.Hex())
+}
+
+// stopRefund stops the cancellation scheduled for a src escrow, if any, once
+// it was withdrawn from. The caller holds the entry's lock.
+func stopRefund(orderEntry *OrderEntry, escrow string) {
+	key := strings.ToLower(escrow)
+	if timer, ok := orderEntry.Refunds[key]; ok {
+		timer.Stop()
+		delete(orderEntry.Refunds, key)
+	}
+}
@@ -0,0 +1,112 @@
+package manager
+
+import (
+	"context"
+	"relayer/internal/store"
+	"time"
+)
+
+// scheduleRelease allows the fill's secret to be released after delay. The
+// schedule is persisted, so a restart does not lose it, see restoreReleases.
+func (m *Manager) scheduleRelease(orderHash string, hashIdx int, srcTxHash, dstTxHash string, delay time.Duration) {
+	if m.store != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), StoreTimeout)
+		rec := store.ReleaseRecord{
+			OrderHash: orderHash,
+			HashIdx:   hashIdx,
+			SrcTxHash: srcTxHash,
+			DstTxHash: dstTxHash,
+			ReleaseAt: time.Now().Add(delay),
+		}
+		if err := m.store.PutRelease(ctx, rec); err != nil {
+			m.logger.Printf("Failed to store secret release of order %s, idx %d: %v", orderHash, hashIdx, err)
+		}
+		cancel()
+	}
+
+	time.AfterFunc(delay, func() {
+		m.releaseScheduled(orderHash, hashIdx, srcTxHash, dstTxHash)
+	})
+}
+
+// releaseScheduled allows a fill whose release time came its secret. A fill
+// held for lower secret indexes keeps its persisted schedule until released.
+func (m *Manager) releaseScheduled(orderHash string, hashIdx int, srcTxHash, dstTxHash string) {
+	if !m.isCanonical(orderHash, hashIdx, dstTxHash) {
+		m.logger.Printf("Dst escrow deployed in %s for order %s lost to a competing escrow, not releasing its secret", dstTxHash, orderHash)
+		m.forgetRelease(orderHash, hashIdx)
+		return
+	}
+	if m.allowSecretRelease(orderHash, hashIdx, srcTxHash, dstTxHash, true) {
+		return
+	}
+	m.forgetRelease(orderHash, hashIdx)
+}
+
+// forgetRelease drops the persisted schedule of a released fill.
+func (m *Manager) forgetRelease(orderHash string, hashIdx int) {
+	if m.store == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), StoreTimeout)
+	defer cancel()
+
+	if err := m.store.DeleteRelease(ctx, orderHash, hashIdx); err != nil {
+		m.logger.Printf("Failed to delete secret release of order %s, idx %d: %v", orderHash, hashIdx, err)
+	}
+}
+
+// restoreReleases rebuilds the release timers persisted before a restart,
+// reloading their orders from the store. Releases whose time passed while
+// the relayer was down are caught up right away; those of orders whose
+// escrows anyone may cancel by now are dropped.
+func (m *Manager) restoreReleases() {
+	if m.store == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), StoreTimeout)
+	defer cancel()
+
+	releases, err := m.store.PendingReleases(ctx)
+	if err != nil {
+		m.logger.Printf("Failed to load pending secret releases: %v", err)
+		return
+	}
+
+	var scheduled, caughtUp int
+	for _, rec := range releases {
+		orderEntry, err := m.GetOrder(rec.OrderHash)
+		if err != nil {
+			if orderEntry, err = m.restoreOrder(ctx, rec.OrderHash); err != nil {
+				m.logger.Printf("Dropping secret release of order %s, idx %d: %v", rec.OrderHash, rec.HashIdx, err)
+				m.forgetRelease(rec.OrderHash, rec.HashIdx)
+				continue
+			}
+		}
+		orderEntry.Lock()
+		deadline := orderDeadline(orderEntry)
+		orderEntry.Unlock()
+		if deadline.Before(time.Now()) {
+			m.logger.Printf("Dropping secret release of order %s, idx %d: its escrows may be cancelled by anyone", rec.OrderHash, rec.HashIdx)
+			m.forgetRelease(rec.OrderHash, rec.HashIdx)
+			continue
+		}
+
+		delay := time.Until(rec.ReleaseAt)
+		if delay <= 0 {
+			m.releaseScheduled(rec.OrderHash, rec.HashIdx, rec.SrcTxHash, rec.DstTxHash)
+			caughtUp++
+			continue
+		}
+		time.AfterFunc(delay, func() {
+			m.releaseScheduled(rec.OrderHash, rec.HashIdx, rec.SrcTxHash, rec.DstTxHash)
+		})
+		scheduled++
+	}
+
+	if len(releases) > 0 {
+		m.logger.Printf("Restored %d secret release timers, caught up %d overdue releases", scheduled, caughtUp)
+	}
+}
@@ -0,0 +1,52 @@
+package manager
+
+import (
+	"encoding/json"
+	"relayer/internal/common"
+	"time"
+)
+
+// verificationReport is the evidence v was verified on, for a fill whose
+// secret may be released from readyAt.
+func verificationReport(orderEntry *OrderEntry, v *Verification, readyAt time.Time) *common.VerificationReport {
+	report := &common.VerificationReport{
+		HashIdx:  v.HashIdx,
+		Hashlock: v.Hashlock.Hex(),
+		Src: common.EscrowEvidence{
+			Escrow:     v.SrcEscrow,
+			DeployTx:   v.SrcTxHash,
+			Taker:      v.SrcTaker,
+			Amount:     v.SrcAmount.String(),
+			DeployedAt: v.SrcTimestamp.UnixMilli(),
+		},
+		Dst: common.EscrowEvidence{
+			Escrow:     v.DstEscrow,
+			DeployTx:   v.DstTxHash,
+			Taker:      v.DstTaker,
+			Amount:     v.DstAmount.String(),
+			DeployedAt: v.DstTimestamp.UnixMilli(),
+		},
+		Checks:     v.Checks,
+		Policies:   v.Policies,
+		VerifiedAt: v.VerifiedAt.Unix(),
+		ReadyAt:    readyAt.Unix(),
+	}
+	if orderEntry.Order != nil {
+		report.Src.ChainID = orderEntry.Order.SrcChainID.String()
+	}
+	if orderEntry.Quote.QuoteRequest != nil {
+		report.Dst.ChainID = orderEntry.Quote.QuoteRequest.DstChain
+	}
+	return report
+}
+
+// notifyFillReady sends the verification report of a fill that became ready
+// to accept its secret to the order's rooms: FILL_READY <ORDER_HASH_HEX> <REPORT_JSON>
+func (m *Manager) notifyFillReady(orderEntry *OrderEntry, report *common.VerificationReport) {
+	raw, err := json.Marshal(report)
+	if err != nil {
+		m.logger.Printf("Failed to encode verification report of order %s: %v", orderEntry.OrderHash.Hex(), err)
+		return
+	}
+	m.notify(orderEntry, FILL_READY_EVENT, string(raw))
+}
@@ -0,0 +1,63 @@
+package manager
+
+import (
+	"relayer/internal/common"
+	"relayer/internal/hash"
+	"strings"
+)
+
+// OrderRoom is the room of a single order's updates.
+func OrderRoom(orderHash string) string {
+	return "order:" + strings.ToLower(orderHash)
+}
+
+// MakerRoom is the room of the updates of every order by maker.
+func MakerRoom(maker string) string {
+	return "maker:" + strings.ToLower(maker)
+}
+
+// orderRooms are the rooms interested in an order.
+func orderRooms(orderEntry *OrderEntry) []string {
+	rooms := []string{OrderRoom(orderEntry.OrderHash.Hex())}
+	if orderEntry.Order != nil && orderEntry.Order.LimitOrder.Maker != "" {
+		rooms = append(rooms, MakerRoom(orderEntry.Order.LimitOrder.Maker))
+	}
+	return rooms
+}
+
+// submittedRooms are the rooms interested in a submitted order.
+func submittedRooms(order common.Order) []string {
+	rooms := make([]string, 0, 2)
+	if orderHash, err := hash.GetOrderHashForLimitOrder(order.SrcChainID, order.LimitOrder); err == nil {
+		rooms = append(rooms, OrderRoom(orderHash.Hex()))
+	}
+	if order.LimitOrder.Maker != "" {
+		rooms = append(rooms, MakerRoom(order.LimitOrder.Maker))
+	}
+	return rooms
+}
+
+// roomsOf returns the rooms of a stored order, or just its order room when it
+// is no longer stored.
+func (m *Manager) roomsOf(orderHash string) []string {
+	if orderEntry, err := m.GetOrder(orderHash); err == nil {
+		return orderRooms(orderEntry)
+	}
+	return []string{OrderRoom(orderHash)}
+}
+
+// notify sends a maker-facing event to the order's rooms: <EVENT> <ORDER_HASH_HEX> <ARGS...>
+func (m *Manager) notify(orderEntry *OrderEntry, event string, args ...string) {
+	msg := event + " " + orderEntry.OrderHash.Hex()
+	if len(args) > 0 {
+		msg += " " + strings.Join(args, " ")
+	}
+	m.broadcaster.BroadcastTo([]byte(msg), orderRooms(orderEntry)...)
+}
+
+// notifyStatus tells the order's rooms that its status changed:
+// STATUS <ORDER_HASH_HEX> <STATUS>
+func (m *Manager) notifyStatus(orderEntry *OrderEntry, status string) {
+	m.notify(orderEntry, ORDER_STATUS_EVENT, status)
+	m.publishBook(orderEntry, common.OrderStatusMode(status))
+}
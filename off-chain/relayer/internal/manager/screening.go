@@ -0,0 +1,69 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"relayer/internal/compliance"
+	"relayer/internal/store"
+	"time"
+)
+
+// Screen asks the compliance screener about subjects at stage and records
+// every decision for audit; ref is the order hash at submit. The first
+// denied subject fails with an error wrapping compliance.ErrDenied. A
+// screener that cannot decide denies too, with its own error.
+func (m *Manager) Screen(ctx context.Context, stage compliance.Stage, ref string, subjects ...compliance.Subject) error {
+	if !compliance.Enabled(m.screener) {
+		return nil
+	}
+
+	for _, subject := range subjects {
+		if subject.Address == "" {
+			continue
+		}
+
+		screenCtx, cancel := context.WithTimeout(ctx, compliance.ScreenTimeout)
+		decision, err := m.screener.Screen(screenCtx, stage, subject)
+		cancel()
+		if err != nil {
+			decision = compliance.Decision{Reason: "screening failed: " + err.Error()}
+		}
+		m.auditScreening(stage, ref, subject, decision)
+
+		if err != nil {
+			return fmt.Errorf("screening %s %s: %w", subject.Role, subject.Address, err)
+		}
+		if !decision.Allowed {
+			m.logger.Printf("Screening denied %s %s on chain %s at %s: %s", subject.Role, subject.Address, subject.Chain, stage, decision.Reason)
+			return fmt.Errorf("%w: %s %s", compliance.ErrDenied, subject.Role, subject.Address)
+		}
+	}
+	return nil
+}
+
+// auditScreening stores a screening decision, or logs it without a
+// database.
+func (m *Manager) auditScreening(stage compliance.Stage, ref string, subject compliance.Subject, decision compliance.Decision) {
+	if m.store == nil {
+		m.logger.Printf("Screening %s: %s %s on chain %s allowed=%t %s", stage, subject.Role, subject.Address, subject.Chain, decision.Allowed, decision.Reason)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), StoreTimeout)
+	defer cancel()
+
+	rec := store.ScreeningRecord{
+		Stage:      string(stage),
+		Ref:        ref,
+		ChainID:    subject.Chain,
+		Address:    subject.Address,
+		Role:       string(subject.Role),
+		Screener:   m.screener.Name(),
+		Allowed:    decision.Allowed,
+		Reason:     decision.Reason,
+		ScreenedAt: time.Now(),
+	}
+	if err := m.store.PutScreening(ctx, rec); err != nil {
+		m.logger.Printf("Failed to store screening of %s %s: %v", subject.Role, subject.Address, err)
+	}
+}
@@ -0,0 +1,39 @@
+package manager
+
+import (
+	"context"
+	"relayer/internal/common"
+	"relayer/internal/store"
+)
+
+// SearchOrders finds the stored orders matching q, submitted before or after
+// the last restart alike, see store.SearchOrders.
+func (m *Manager) SearchOrders(ctx context.Context, q store.OrderQuery) (*common.OrderSearchResult, error) {
+	if m.store == nil {
+		return nil, ErrNoStore
+	}
+
+	recs, next, err := m.store.SearchOrders(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &common.OrderSearchResult{Orders: make([]common.OrderSummary, 0, len(recs)), NextCursor: next}
+	for _, rec := range recs {
+		result.Orders = append(result.Orders, common.OrderSummary{
+			OrderHash:    rec.OrderHash,
+			Status:       common.OrderStatusMode(rec.Status),
+			SrcChainID:   rec.SrcChainID,
+			DstChainID:   rec.DstChainID,
+			Maker:        rec.Maker,
+			MakerAsset:   rec.MakerAsset,
+			TakerAsset:   rec.TakerAsset,
+			MakingAmount: rec.MakingAmount,
+			TakingAmount: rec.TakingAmount,
+			QuoteID:      rec.QuoteID,
+			SubmittedAt:  rec.SubmittedAt,
+			UpdatedAt:    rec.UpdatedAt,
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,97 @@
+package manager
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Secrets of a multiple fill order are released in index order: the secret
+// of a fill crossing threshold N+1 is only handed out once every verified
+// fill up to threshold N was final and allowed its own. A fill that became
+// final quickly, on a fast chain or with short timelocks, waits for the
+// earlier fills still waiting for finality.
+
+// heldRelease is a final fill waiting for the fills of lower secret indexes.
+type heldRelease struct {
+	srcTxHash string
+	dstTxHash string
+}
+
+// releaseBlocker returns the lowest secret index below hashIdx whose verified
+// fill was not allowed its secret yet, or -1 when none blocks it. Fills whose
+// escrows were closed meanwhile no longer need their secret and do not block.
+// Called with the entry locked.
+func releaseBlocker(orderEntry *OrderEntry, hashIdx int) int {
+	if orderEntry.OrderType != MultiFill {
+		return -1
+	}
+
+	blocker := -1
+	for idx, v := range orderEntry.Canonical {
+		if idx >= hashIdx || orderEntry.Allowed[idx] || (blocker >= 0 && idx > blocker) {
+			continue
+		}
+		if _, closed := orderEntry.Closed[strings.ToLower(v.SrcEscrow)]; closed {
+			continue
+		}
+		if _, closed := orderEntry.Closed[strings.ToLower(v.DstEscrow)]; closed {
+			continue
+		}
+		blocker = idx
+	}
+	return blocker
+}
+
+// holdRelease keeps a final fill until the fill of blocker is allowed its
+// secret. Called with the entry locked.
+func (m *Manager) holdRelease(orderEntry *OrderEntry, hashIdx, blocker int, srcTxHash, dstTxHash string) {
+	if orderEntry.Held == nil {
+		orderEntry.Held = make(map[int]heldRelease)
+	}
+	orderEntry.Held[hashIdx] = heldRelease{srcTxHash: srcTxHash, dstTxHash: dstTxHash}
+	m.logger.Printf("Holding secret %d of order %s until the fill of secret %d is released", hashIdx, orderEntry.OrderHash.Hex(), blocker)
+}
+
+// releaseHeld allows the held fills no longer blocked, lowest index first, so
+// releasing one may unblock the next. Held fills that lost to a competing
+// escrow are dropped. Their persisted schedules are deleted in the
+// background. Called with the entry locked.
+func (m *Manager) releaseHeld(orderEntry *OrderEntry) {
+	orderHash := orderEntry.OrderHash.Hex()
+
+	idxs := make([]int, 0, len(orderEntry.Held))
+	for idx := range orderEntry.Held {
+		idxs = append(idxs, idx)
+	}
+	sort.Ints(idxs)
+
+	for _, idx := range idxs {
+		if releaseBlocker(orderEntry, idx) >= 0 {
+			break
+		}
+
+		held := orderEntry.Held[idx]
+		delete(orderEntry.Held, idx)
+		go m.forgetRelease(orderHash, idx)
+
+		if v, ok := orderEntry.Canonical[idx]; ok && v.DstTxHash != held.dstTxHash {
+			m.logger.Printf("Dst escrow deployed in %s for order %s lost to a competing escrow, not releasing its secret", held.dstTxHash, orderHash)
+			continue
+		}
+		m.logger.Printf("Releasing held secret %d of order %s", idx, orderHash)
+		m.allowFill(orderEntry, idx, held.srcTxHash, held.dstTxHash)
+	}
+}
+
+// checkSecretSequence rejects a secret of a multiple fill order revealed
+// before those of the verified fills of lower indexes.
+func checkSecretSequence(orderEntry *OrderEntry, hashIdx int) error {
+	orderEntry.Lock()
+	defer orderEntry.Unlock()
+
+	if blocker := releaseBlocker(orderEntry, hashIdx); blocker >= 0 {
+		return fmt.Errorf("secret %d cannot be revealed before the fill of secret %d is released", hashIdx, blocker)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"relayer/internal/chain"
+	"relayer/internal/common"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// VerifyOrderSignature checks that the maker of an EVM-sourced order signed
+// orderHash, falling back to ERC-1271 for smart-contract wallets. Sui-sourced
+// orders are created by the maker on chain and carry no signature.
+func (m *Manager) VerifyOrderSignature(order *common.Order, orderHash ethcommon.Hash) error {
+	if order.SrcChainID.IsMove() {
+		return nil
+	}
+	if !ethcommon.IsHexAddress(order.LimitOrder.Maker) {
+		return errors.New("maker is not an address")
+	}
+
+	signature, err := hexutil.Decode(order.Signature)
+	if err != nil {
+		return errors.New("signature is not hex")
+	}
+
+	client, err := m.evm(order.SrcChainID)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), ChainCallTimeout)
+	defer cancel()
+
+	return chain.VerifyEvmOrderSignature(ctx, client, orderHash, ethcommon.HexToAddress(order.LimitOrder.Maker), signature)
+}
+
+// VerifyMessageSignature checks that signer signed message with its wallet:
+// EIP-191 personal_sign (or ERC-1271) with a hex signature for 20 byte EVM
+// addresses, signPersonalMessage with a serialized signature for Sui ones.
+// ERC-1271 wallets are checked on the first EVM chain of the network profile.
+func (m *Manager) VerifyMessageSignature(signer string, message []byte, signature string) error {
+	if !ethcommon.IsHexAddress(signer) {
+		recovered, err := chain.RecoverSuiMessageSigner(message, signature)
+		if err != nil {
+			return err
+		}
+		if !common.SameAddress(recovered, signer) {
+			return errors.New("signature was not made by the signer")
+		}
+		return nil
+	}
+
+	sig, err := hexutil.Decode(signature)
+	if err != nil {
+		return errors.New("signature is not hex")
+	}
+
+	client, err := m.firstEvm()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), ChainCallTimeout)
+	defer cancel()
+
+	return chain.VerifyEvmMessageSignature(ctx, client, message, ethcommon.HexToAddress(signer), sig)
+}
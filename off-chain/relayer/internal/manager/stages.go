@@ -0,0 +1,57 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"relayer/internal/analytics"
+	"time"
+)
+
+// Order lifecycle stages whose timestamps are stored for latency analytics.
+const (
+	StageQuoted    = "quoted"
+	StageSubmitted = "submitted"
+	StageEscrows   = "escrows"
+	StageSecret    = "secret"
+	StageWithdrawn = "withdrawn"
+)
+
+// lifecycle is the order of the stages, each latency is measured from one to the next.
+var lifecycle = []string{StageQuoted, StageSubmitted, StageEscrows, StageSecret, StageWithdrawn}
+
+// ErrNoStore is returned by queries that need the persistent store when the
+// relayer runs without DATABASE_PATH.
+var ErrNoStore = errors.New("no persistent store configured")
+
+// recordStage stores the time an order reached a stage, if there is a store.
+func (m *Manager) recordStage(orderHash, stage string, at time.Time) {
+	if m.store == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), StoreTimeout)
+	defer cancel()
+
+	if err := m.store.RecordStage(ctx, orderHash, stage, at); err != nil {
+		m.logger.Printf("Failed to store stage %s of order %s: %v", stage, orderHash, err)
+	}
+}
+
+// Latency returns the p50/p95 time between consecutive lifecycle stages of
+// the orders quoted since the given time.
+func (m *Manager) Latency(ctx context.Context, since time.Time) ([]analytics.StageLatency, error) {
+	if m.store == nil {
+		return nil, ErrNoStore
+	}
+
+	stats := make([]analytics.StageLatency, 0, len(lifecycle)-1)
+	for i := 1; i < len(lifecycle); i++ {
+		from, to := lifecycle[i-1], lifecycle[i]
+		durations, err := m.store.StageDurations(ctx, from, to, since)
+		if err != nil {
+			return nil, err
+		}
+		stats = append(stats, analytics.Latency(from, to, durations))
+	}
+	return stats, nil
+}
@@ -0,0 +1,130 @@
+package manager
+
+import (
+	"fmt"
+	"relayer/internal/bus"
+	"relayer/internal/chain"
+	"relayer/internal/common"
+	"relayer/internal/config"
+	"relayer/internal/metrics"
+	"relayer/internal/notify"
+	"strconv"
+)
+
+// The manager publishes the lifecycle of orders and the health of chains to
+// its bus. The broadcaster, the store, the metrics, the alert webhooks, the
+// executor, the notifications and the exporter subscribe to it, rather than being called
+// from every place an order changes.
+
+// orderEvent is the payload of the order lifecycle events.
+type orderEvent struct {
+	entry *OrderEntry
+	// the fill that passed, for EscrowsVerified
+	verification *Verification
+	// whether an OrderExpired is announced to the order's rooms
+	announce bool
+	// the fills left without their secret, for OrderRefunding
+	stuck []*Verification
+}
+
+// publishEscrowClosed publishes the recorded closing of one of the order's
+// escrows.
+func (m *Manager) publishEscrowClosed(orderEntry *OrderEntry, side EscrowSide, escrow string, state chain.EscrowState, txHash string) {
+	m.publishOrder(bus.EscrowClosed, orderEvent{entry: orderEntry}, map[string]any{
+		"side":   string(side),
+		"escrow": escrow,
+		"state":  string(state),
+		"txHash": txHash,
+	})
+}
+
+// publishOrder publishes an order lifecycle event of kind.
+func (m *Manager) publishOrder(kind bus.Kind, ev orderEvent, details map[string]any) {
+	m.events.Publish(bus.Event{Kind: kind, Subject: ev.entry.OrderHash.Hex(), Details: details, Payload: ev})
+}
+
+// handleOrder adds h as a handler of the order lifecycle events of kind.
+func (m *Manager) handleOrder(kind bus.Kind, h func(bus.Event, orderEvent)) {
+	m.events.Handle(kind, func(e bus.Event) {
+		if ev, ok := e.Payload.(orderEvent); ok {
+			h(e, ev)
+		}
+	})
+}
+
+// subscribe wires the subsystems to the bus. Handlers of one event run in
+// the order they are added here: the store first, then the broadcaster.
+func (m *Manager) subscribe() {
+	// store
+	m.handleOrder(bus.OrderSubmitted, func(_ bus.Event, ev orderEvent) {
+		m.persistOrder(ev.entry)
+	})
+	m.handleOrder(bus.EscrowsVerified, func(e bus.Event, ev orderEvent) {
+		m.persistEscrows(ev.entry, ev.verification)
+		m.recordStage(e.Subject, StageEscrows, e.Time)
+	})
+	m.handleOrder(bus.SecretReleased, func(e bus.Event, _ orderEvent) {
+		m.recordStage(e.Subject, StageSecret, e.Time)
+	})
+	m.handleOrder(bus.EscrowClosed, func(e bus.Event, _ orderEvent) {
+		escrow, _ := e.Details["escrow"].(string)
+		txHash, _ := e.Details["txHash"].(string)
+		// the event handlers publish it with the entry locked
+		go m.persistClosed(e.Subject, escrow, txHash)
+	})
+	m.handleOrder(bus.OrderExpired, func(_ bus.Event, ev orderEvent) {
+		m.persistStatus(ev.entry, string(common.OrderStatusExpired))
+	})
+	m.handleOrder(bus.OrderRefunding, func(_ bus.Event, ev orderEvent) {
+		m.persistStatus(ev.entry, string(common.OrderStatusRefunding))
+	})
+
+	// broadcaster
+	m.handleOrder(bus.EscrowsVerified, func(_ bus.Event, ev orderEvent) {
+		v := ev.verification
+		m.notify(ev.entry, ESCROWS_VERIFIED_EVENT, strconv.Itoa(v.HashIdx), v.SrcEscrow, v.DstEscrow)
+	})
+	m.handleOrder(bus.SecretReleased, func(_ bus.Event, ev orderEvent) {
+		m.notify(ev.entry, SECRET_RELEASED_EVENT)
+	})
+	m.handleOrder(bus.OrderExpired, func(e bus.Event, ev orderEvent) {
+		if !ev.announce {
+			return
+		}
+		m.broadcaster.Broadcast([]byte(fmt.Sprintf("%s %s", ORDER_EXPIRED_EVENT, e.Subject)), orderRooms(ev.entry)...)
+		m.notifyStatus(ev.entry, string(common.OrderStatusExpired))
+	})
+	m.handleOrder(bus.OrderRefunding, func(_ bus.Event, ev orderEvent) {
+		m.notifyStatus(ev.entry, string(common.OrderStatusRefunding))
+	})
+
+	// metrics
+	for _, kind := range []bus.Kind{bus.OrderSubmitted, bus.EscrowsVerified, bus.SecretReleased, bus.OrderExpired, bus.OrderRefunding} {
+		m.events.Handle(kind, func(e bus.Event) {
+			metrics.OrderEvents.Add(string(e.Kind), 1)
+		})
+	}
+
+	// alert webhooks
+	m.events.Handle(bus.ChainBehind, m.onChainHead)
+	m.events.Handle(bus.ChainRecovered, m.onChainHead)
+	m.handleOrder(bus.OrderRefunding, m.alertRefund)
+
+	// executor
+	m.handleOrder(bus.OrderRefunding, func(_ bus.Event, ev orderEvent) {
+		for _, v := range ev.stuck {
+			m.scheduleRefund(ev.entry, v)
+		}
+	})
+
+	// operator notifications on Slack, Telegram and email
+	notifier := notify.New(m.logger, notify.FromEnv(), func() config.NotifyRules { return m.Config().Notify })
+	if notifier.Enabled() {
+		go notifier.Run(m.events.Subscribe("notify", notify.EventBuffer), m.done)
+	}
+
+	// daily snapshots in object storage, missing no event
+	if m.exporter.Enabled() {
+		go m.exporter.Run(m.events.Queue("export"), m.done)
+	}
+}
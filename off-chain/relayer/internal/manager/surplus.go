@@ -0,0 +1,56 @@
+package manager
+
+import (
+	"fmt"
+	"math/big"
+	"relayer/internal/common"
+	"strings"
+)
+
+// paidMaker returns the amount withdrawing escrow pays the maker, when it is
+// the destination escrow of the verified fill at hashIdx and not closed yet.
+// The caller holds the entry's lock.
+func paidMaker(orderEntry *OrderEntry, escrow string, hashIdx int) *big.Int {
+	if _, closed := orderEntry.Closed[strings.ToLower(escrow)]; closed {
+		return nil
+	}
+	v, ok := orderEntry.Canonical[hashIdx]
+	if !ok || !strings.EqualFold(v.DstEscrow, escrow) {
+		return nil
+	}
+	return v.DstAmount
+}
+
+// recordSurplus takes the amount the maker was paid from the destination
+// escrow of a fill and records how much it beats the quoted auction end
+// amount. Only single fill orders are tracked since partial fills settle a
+// fraction of the quote.
+func (m *Manager) recordSurplus(orderEntry *OrderEntry, settled *big.Int) error {
+	if orderEntry.OrderType != SingleFill || orderEntry.Quote.Quote == nil {
+		return nil
+	}
+
+	quote := orderEntry.Quote.Quote
+	preset, ok := quote.Presets[quote.RecommendedPreset]
+	if !ok {
+		return fmt.Errorf("quote %s has no %s preset", quote.QuoteID, quote.RecommendedPreset)
+	}
+
+	quoted, err := common.ParseAmount(preset.AuctionEndAmount)
+	if err != nil {
+		return fmt.Errorf("auction end amount: %w", err)
+	}
+
+	dstChain := orderEntry.Quote.QuoteRequest.DstChain
+	record := m.surplus.Record(
+		orderEntry.OrderHash.Hex(),
+		orderEntry.Order.SrcChainID.String(),
+		dstChain,
+		orderEntry.Order.LimitOrder.TakerAsset,
+		quoted,
+		settled,
+	)
+	m.logger.Printf("Order %s settled %s against quoted %s, surplus %s", record.OrderHash, record.Settled, record.Quoted, record.Surplus)
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package manager
+
+import (
+	"math/big"
+	"relayer/internal/common"
+	"relayer/internal/hashlock"
+	"relayer/internal/resolver"
+	"testing"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestRecordSurplus(t *testing.T) {
+	f := newFillFixture(t, common.Base)
+	claimant := &resolver.Resolver{ID: "resolver-1", EVMAddress: f.taker.Hex()}
+	orderEntry := f.order(t, SingleFill, nil)
+	orderHash := orderEntry.OrderHash.Hex()
+
+	secret := randomBytes(32)
+	d := f.fill(orderEntry, 0)
+	d.srcHashlock = hashlock.Keccak256.Hash(secret)
+	d.dstHashlock = d.srcHashlock
+	d.dstAmount = big.NewInt(testTaking + 5)
+	if err := f.m.HandleReceiveEvent(claimant, []byte(f.deploy(t, orderEntry, d))); err != nil {
+		t.Fatalf("fill = %v", err)
+	}
+	if _, ok := f.m.Surplus().Get(orderHash); ok {
+		t.Fatal("surplus recorded before the maker was paid")
+	}
+
+	// withdraw reports the escrow of side withdrawn with the secret
+	withdraw := func(side EscrowSide) string {
+		orderEntry.Lock()
+		escrow := orderEntry.Canonical[0].SrcEscrow
+		if side == DstEscrow {
+			escrow = orderEntry.Canonical[0].DstEscrow
+		}
+		orderEntry.Unlock()
+		tx := ethcommon.BytesToHash(randomBytes(32))
+		f.evm.AddReceipt(tx, &types.Receipt{Status: types.ReceiptStatusSuccessful, Logs: []*types.Log{{
+			Address: ethcommon.HexToAddress(escrow),
+			Topics:  []ethcommon.Hash{crypto.Keccak256Hash([]byte("EscrowWithdrawal(bytes32)"))},
+			Data:    secret,
+		}}}, uint64(time.Now().Unix()))
+		return WITHDRAWN_EVENT + " " + orderHash + " " + tx.Hex()
+	}
+
+	if err := f.m.HandleReceiveEvent(claimant, []byte(withdraw(SrcEscrow))); err != nil {
+		t.Fatalf("src withdrawal = %v", err)
+	}
+	if _, ok := f.m.Surplus().Get(orderHash); ok {
+		t.Fatal("surplus recorded on the src withdrawal")
+	}
+
+	if err := f.m.HandleReceiveEvent(claimant, []byte(withdraw(DstEscrow))); err != nil {
+		t.Fatalf("dst withdrawal = %v", err)
+	}
+	record, ok := f.m.Surplus().Get(orderHash)
+	if !ok {
+		t.Fatal("no surplus recorded on the dst withdrawal")
+	}
+	if record.Settled != big.NewInt(testTaking+5).String() || record.Surplus != "5" {
+		t.Errorf("recorded %s settled, %s surplus, want %d and 5", record.Settled, record.Surplus, testTaking+5)
+	}
+}
@@ -0,0 +1,103 @@
+package manager
+
+import (
+	"fmt"
+	"relayer/internal/auction"
+	"relayer/internal/bus"
+	"relayer/internal/common"
+	"time"
+
+	"github.com/imkira/go-ttlmap"
+)
+
+// sweepLoop periodically expires orders nobody filled before their auction
+// ended, refunds those whose fills got stuck and drops quarantined orders
+// nobody approved in time, until the manager is closed.
+func (m *Manager) sweepLoop() {
+	ticker := time.NewTicker(SweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.done:
+			return
+		case now := <-ticker.C:
+			m.sweep(now)
+			m.sweepQuarantine(now)
+		}
+	}
+}
+
+// sweep expires every active order that is still pending with no verified
+// fills after its auction end: the order is marked expired, announced to
+// resolvers and moved to the archive. Orders whose verified fills did not get
+// their secret within fillGrace of the auction end are refunded instead.
+func (m *Manager) sweep(now time.Time) {
+	m.activeMu.Lock()
+	hashes := make([]string, 0, len(m.active))
+	for hash := range m.active {
+		hashes = append(hashes, hash)
+	}
+	m.activeMu.Unlock()
+
+	for _, hash := range hashes {
+		orderEntry, err := m.peekOrder(hash)
+		if err != nil {
+			// evicted by its ttl
+			m.deactivate(hash)
+			continue
+		}
+
+		if stuck := m.refundIfStuck(orderEntry, now); len(stuck) > 0 {
+			m.refund(orderEntry, stuck)
+			continue
+		}
+		if !m.expireIfStale(orderEntry, now) {
+			continue
+		}
+
+		m.orders.Delete(hash)
+		m.orderRecency.remove(hash)
+		m.archive.Set(hash, ttlmap.NewItem(orderEntry, ttlmap.WithTTL(ArchiveTTL)), nil)
+		m.deactivate(hash)
+
+		m.publishOrder(bus.OrderExpired, orderEvent{entry: orderEntry, announce: true}, nil)
+		m.logger.Printf("Order %s expired unfilled, archived", hash)
+	}
+}
+
+func (m *Manager) expireIfStale(orderEntry *OrderEntry, now time.Time) bool {
+	quote := orderEntry.Quote.Quote
+	if quote == nil {
+		return false
+	}
+	curve := auction.FromPreset(orderEntry.SubmittedAt, quote.Presets[quote.RecommendedPreset])
+	if now.Before(curve.End()) {
+		return false
+	}
+
+	orderEntry.Lock()
+	defer orderEntry.Unlock()
+
+	if len(orderEntry.Escrows) > 0 || orderEntry.OrderStatus.Status != common.OrderStatusPending {
+		return false
+	}
+	orderEntry.OrderStatus.Status = common.OrderStatusExpired
+	return true
+}
+
+func (m *Manager) deactivate(hash string) {
+	m.activeMu.Lock()
+	delete(m.active, hash)
+	m.activeMu.Unlock()
+}
+
+// GetArchivedOrder returns an order the sweeper expired.
+func (m *Manager) GetArchivedOrder(orderHash string) (*OrderEntry, error) {
+	item, err := m.archive.Get(orderHash)
+	if err != nil {
+		return nil, fmt.Errorf("order not found: %s", orderHash)
+	}
+
+	return item.Value().(*OrderEntry), nil
+}
@@ -0,0 +1,34 @@
+package manager
+
+import (
+	"fmt"
+	"math/big"
+	"relayer/internal/config"
+)
+
+// checkTolerance rejects an amount outside the tolerance t of the owed
+// amount: different from it when exact, below it by more than t.BelowBps
+// when at least, and over it by more than t.AboveBps too when in a band.
+func checkTolerance(t config.Tolerance, amount, owed *big.Int) error {
+	if t.Mode == config.ToleranceExact {
+		if amount.Cmp(owed) != 0 {
+			return fmt.Errorf("%s is not exactly %s", amount, owed)
+		}
+		return nil
+	}
+
+	floor := new(big.Int).Mul(owed, new(big.Int).SetUint64(10_000-t.BelowBps))
+	floor.Quo(floor, big.NewInt(10_000))
+	if amount.Cmp(floor) < 0 {
+		return fmt.Errorf("%s is below %s, %s less %d bps", amount, floor, owed, t.BelowBps)
+	}
+
+	if t.Mode == config.ToleranceBand {
+		ceil := new(big.Int).Mul(owed, new(big.Int).SetUint64(10_000+t.AboveBps))
+		ceil.Quo(ceil, big.NewInt(10_000))
+		if amount.Cmp(ceil) > 0 {
+			return fmt.Errorf("%s is above %s, %s plus %d bps", amount, ceil, owed, t.AboveBps)
+		}
+	}
+	return nil
+}
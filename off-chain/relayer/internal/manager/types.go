@@ -1,8 +1,13 @@
 package manager
 
 import (
+	"math/big"
+	"relayer/internal/analytics"
 	"relayer/internal/common"
+	"relayer/internal/extension"
+	"relayer/internal/hashlock"
 	"sync"
+	"time"
 
 	ethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/google/uuid"
@@ -11,20 +16,99 @@ import (
 const (
 	// Relayer -> Resolver
 
-	// Order broadcast event: BROADC <ACTUAL_JSON_OF_ORDER>
+	// Order broadcast event: BROADC <ACTUAL_JSON_OF_ORDER>, BROADC <OrderPayload JSON> in ProtocolV2
 	ORDER_EVENT = "BROADC"
-	// broadcast orderhash and secret: SECRET <ORDER_HASH_HEX> <SECRET_HEX>
+	// broadcast orderhash and secret: SECRET <ORDER_HASH_HEX> <SECRET_HEX>, SECRET <SecretPayload JSON> in ProtocolV2
 	SECRET_EVENT = "SECRET"
+	// order went unfilled past its auction and was archived: EXPIRED <ORDER_HASH_HEX>
+	ORDER_EXPIRED_EVENT = "EXPIRED"
+	// a dst escrow lost to a competing one for the same secret and should be cancelled:
+	// CANCEL_ADVICE <ORDER_HASH_HEX> <HASH_IDX> <LOSING_DST_ESCROW> <CANONICAL_DST_ESCROW>
+	CANCEL_ADVICE_EVENT = "CANCEL_ADVICE"
+	// a resolver reserved an order segment: FILL_RESERVED <ORDER_HASH_HEX> <HASH_IDX> <RESOLVER_ID> <UNTIL_UNIX_SECONDS>
+	FILL_RESERVED_EVENT = "FILL_RESERVED"
+	// resumable session of an authenticated resolver connection, pass it as
+	// ?resume=<TOKEN> when reconnecting: SESSION <TOKEN>
+	SESSION_EVENT = "SESSION"
+	// first frame to clients that negotiated a protocol version:
+	// PROTOCOL <VERSION> <SUPPORTED_SUBPROTOCOLS_COMMA_SEPARATED>
+	PROTOCOL_EVENT = "PROTOCOL"
+
+	// BOOK <PUBLIC_ORDER_JSON>, an order added to or updated on the public order book feed
+	BOOK_EVENT = "BOOK"
+	// Relayer -> Maker (only sent to the order's and maker's rooms)
+	// order status changed: STATUS <ORDER_HASH_HEX> <STATUS>
+	ORDER_STATUS_EVENT = "STATUS"
+	// a fill's escrows passed verification: ESCROWS_VERIFIED <ORDER_HASH_HEX> <HASH_IDX> <SRC_ESCROW> <DST_ESCROW>
+	ESCROWS_VERIFIED_EVENT = "ESCROWS_VERIFIED"
+	// the fill's secret may be shared once both escrows are final: FINALITY_WAIT <ORDER_HASH_HEX> <HASH_IDX> <UNIX_SECONDS>
+	FINALITY_WAIT_EVENT = "FINALITY_WAIT"
+	// a fill is ready to accept its secret, with the evidence it was verified on:
+	// FILL_READY <ORDER_HASH_HEX> <VERIFICATION_REPORT_JSON>
+	FILL_READY_EVENT = "FILL_READY"
+	// the maker's secret was shared with the resolvers: SECRET_RELEASED <ORDER_HASH_HEX>
+	SECRET_RELEASED_EVENT = "SECRET_RELEASED"
+	// an escrow of the order paid out: WITHDRAWN <ORDER_HASH_HEX> <src|dst> <WITHDRAW_TX_HASH>,
+	// and from resolvers its proof: WITHDRAWN <ORDER_HASH_HEX> <WITHDRAW_TX_HASH>
+	WITHDRAWN_EVENT = "WITHDRAWN"
+
+	// reply to a rejected client message: ERROR <REASON>
+	ERROR_EVENT = "ERROR"
+	// reply to GET_ORDER, to the asking connection only: ORDER_DETAIL <ORDER_HASH_HEX> <OrderDetail JSON>
+	ORDER_DETAIL_EVENT = "ORDER_DETAIL"
 
 	// Resolver -> Relayer
 	// Transaction hash event: TXHASH <ORDER_HASH_HEX> <SRC_TX_HASH> <DST_TX_HASH>
 	TXHASH_EVENT = "TXHASH"
+	// Escrow cancellation: CANCEL <ORDER_HASH_HEX> <CANCEL_TX_HASH>
+	CANCEL_EVENT = "CANCEL"
+	// Escrow withdrawal, the older name of the WITHDRAWN proof: WITHDRAW <ORDER_HASH_HEX> <WITHDRAW_TX_HASH>
+	WITHDRAW_EVENT = "WITHDRAW"
+	// Reservation of the order segment filled with the secret at HASH_IDX: FILL_INTENT <ORDER_HASH_HEX> <HASH_IDX>
+	FILL_INTENT_EVENT = "FILL_INTENT"
+	// Order lookup for a hash the resolver missed the broadcast of: GET_ORDER <ORDER_HASH_HEX>
+	GET_ORDER_EVENT = "GET_ORDER"
+
+	// Framing
+	// Sequenced frame, opt-in by connecting with ?since=<SEQ>: SEQ <SEQ> <EVENT>
+	SEQ_PREFIX = "SEQ"
 )
 
 type QuoteEntry struct {
 	QuoteID      uuid.UUID
 	QuoteRequest *common.QuoteRequestParams
 	Quote        *common.Quote
+	FeeBps       uint64
+	// fingerprint of the API key the quote was requested with, see analytics.IntegratorID
+	Integrator string
+	Leg        *QuoteLeg // set for legs of a multi-hop quote
+	QuotedAt   time.Time
+	ExpiresAt  time.Time
+}
+
+// Expired reports whether orders may no longer be placed against the quote.
+func (q QuoteEntry) Expired(now time.Time) bool {
+	return !q.ExpiresAt.IsZero() && now.After(q.ExpiresAt)
+}
+
+// quotePair is the chain and token pair conversions of the quote count toward.
+func quotePair(q QuoteEntry) analytics.QuotePair {
+	if q.QuoteRequest == nil {
+		return analytics.QuotePair{}
+	}
+	return analytics.QuotePair{
+		SrcChain: q.QuoteRequest.SrcChain,
+		DstChain: q.QuoteRequest.DstChain,
+		SrcToken: q.QuoteRequest.SrcTokenAddress,
+		DstToken: q.QuoteRequest.DstTokenAddress,
+	}
+}
+
+// QuoteLeg links a leg quote to the multi-leg quote it is part of.
+type QuoteLeg struct {
+	ParentID uuid.UUID
+	Index    int
+	Count    int
 }
 
 type OrderType string
@@ -34,11 +118,103 @@ const (
 	MultiFill  OrderType = "MULTI_FILL"
 )
 
+// OrderEntry is the state of one order. The manager stores a single entry
+// per order and hands out pointers to it, so an entry must not be copied once
+// stored. Fields documented as guarded are read and written under the
+// entry's lock, the others are fixed before the order is stored.
 type OrderEntry struct {
-	OrderType     OrderType
-	OrderHash     ethcommon.Hash
-	Order         *common.Order
-	OrderStatus   *common.OrderStatus
-	OrderFills    *common.ReadyToAcceptSecretFills
-	OrderMutMutex *sync.Mutex
+	mu sync.Mutex
+
+	OrderType OrderType
+	OrderHash ethcommon.Hash
+	Order     *common.Order
+	// guarded, read it with Status
+	OrderStatus common.OrderStatus
+	// fills ready to receive their secret until the maker polls them,
+	// guarded, taken with TakeFills
+	OrderFills []common.ReadyToAcceptSecretFill
+	Fee        *OrderFee
+	// integrator fee requested with the quote, nil without one
+	IntegratorFee *OrderFee
+	Quote         QuoteEntry
+	SubmittedAt   time.Time
+	// making amount covered by verified fills, guarded
+	FilledMakingAmount *big.Int
+	// escrows of verified fills, lowercased, guarded
+	Escrows map[string]EscrowSide
+	// escrows seen withdrawn or cancelled, lowercased, to the closing tx,
+	// guarded
+	Closed map[string]string
+	// fill verified for each secret index, the first dst escrow on chain when
+	// resolvers compete, guarded
+	Canonical map[int]*Verification
+	// answer of the 1inch relayer the order was forwarded to, guarded
+	Upstream *common.UpstreamSubmission
+	// secret indexes revealed to the resolvers, guarded
+	Revealed map[int]bool
+	// secret indexes whose fills were allowed to receive their secret,
+	// guarded
+	Allowed map[int]bool
+	// final fills waiting for the fills of lower secret indexes, guarded,
+	// see releaseHeld
+	Held map[int]heldRelease
+	// src escrow cancellations scheduled by the executor, by lowercased
+	// escrow, guarded, see scheduleRefund
+	Refunds map[string]*time.Timer
+	// latest time anyone may cancel a verified fill's escrows, guarded, see
+	// adjustDeadline
+	EscrowDeadline time.Time
+	// address the dst escrow must pay out to, empty when not given explicitly
+	DstReceiver string
+	// hash function of the order's hashlocks, fixed by its route
+	Hashlock hashlock.Algorithm
+	// id of the relayer held secret set the order was built with, if any
+	SecretsID string
+	// decoded order extension, nil for Sui-sourced orders
+	Extension *extension.Extension
+}
+
+// Lock takes the entry's lock, guarding its mutable fields.
+func (e *OrderEntry) Lock() {
+	e.mu.Lock()
+}
+
+func (e *OrderEntry) Unlock() {
+	e.mu.Unlock()
+}
+
+// Status returns a snapshot of the order's status.
+func (e *OrderEntry) Status() common.OrderStatus {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.OrderStatus
+}
+
+// TakeFills returns the fills ready to receive their secret and clears them.
+func (e *OrderEntry) TakeFills() []common.ReadyToAcceptSecretFill {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	fills := e.OrderFills
+	e.OrderFills = nil
+	return fills
+}
+
+// EscrowSide tells whether an escrow holds the maker's or the taker's funds.
+type EscrowSide string
+
+const (
+	SrcEscrow EscrowSide = "src"
+	DstEscrow EscrowSide = "dst"
+)
+
+// OrderFee is the protocol or integrator fee charged on an order, accrued
+// fill by fill as their source escrows are withdrawn, see accrueFee. Accrued
+// tells whether any of it was. Guarded by the entry's lock.
+type OrderFee struct {
+	ChainID string
+	Token   string
+	Amount  *big.Int
+	Accrued bool
 }
@@ -0,0 +1,16 @@
+package manager
+
+import "relayer/internal/common"
+
+// RecordUpstream keeps the answer of the 1inch relayer to an order forwarded
+// to it, for the admin order view.
+func (m *Manager) RecordUpstream(orderHash string, submission common.UpstreamSubmission) {
+	orderEntry, err := m.peekOrder(orderHash)
+	if err != nil {
+		return
+	}
+
+	orderEntry.Lock()
+	orderEntry.Upstream = &submission
+	orderEntry.Unlock()
+}
@@ -0,0 +1,696 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"relayer/internal/auction"
+	"relayer/internal/bus"
+	"relayer/internal/chain"
+	"relayer/internal/common"
+	"relayer/internal/config"
+	"relayer/internal/logging"
+	"relayer/internal/metrics"
+	"relayer/internal/resolver"
+	"strings"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/imkira/go-ttlmap"
+)
+
+// Verification is the evidence gathered for one fill reported by a resolver
+// via TXHASH: both escrows exist, belong to the order, share a hashlock and
+// were created by the claiming resolver.
+type Verification struct {
+	OrderHash    string
+	SrcTxHash    string
+	DstTxHash    string
+	HashIdx      int
+	Hashlock     ethcommon.Hash
+	SrcEscrow    string
+	DstEscrow    string
+	SrcTaker     string
+	DstTaker     string
+	SrcAmount    *big.Int
+	DstAmount    *big.Int
+	SrcTimestamp chain.EventTime
+	DstTimestamp chain.EventTime
+	// immutables of the EVM escrows, needed to withdraw or cancel them; nil
+	// for Sui escrows, which hold their own, and when they could not be decoded
+	SrcImmutables *chain.Immutables
+	DstImmutables *chain.Immutables
+	// names of the checks the fill passed, for its verification report
+	Checks []string
+	// tolerance applied by each amount check, by check name
+	Policies   map[string]string
+	VerifiedAt time.Time
+}
+
+// srcEscrow is the chain-agnostic view of a source escrow creation.
+type srcEscrow struct {
+	orderHash ethcommon.Hash
+	hashlock  ethcommon.Hash
+	escrow    string
+	taker     string
+	amount    *big.Int
+	timestamp chain.EventTime
+	// EVM escrows only
+	immutables *chain.Immutables
+}
+
+// dstEscrow is the chain-agnostic view of a destination escrow creation.
+type dstEscrow struct {
+	hashlock ethcommon.Hash
+	escrow   string
+	taker    string
+	amount   *big.Int
+	// gas token held by the escrow, in the dst chain's native units
+	safetyDeposit *big.Int
+	timestamp     chain.EventTime
+	// EVM escrows only
+	immutables *chain.Immutables
+}
+
+// verifyFill fetches both escrow creations of a fill and checks that they
+// belong to the order, lock the same secret and were created by claimant.
+func (m *Manager) verifyFill(ctx context.Context, orderEntry *OrderEntry, claimant *resolver.Resolver, srcTxHash, dstTxHash string) (*Verification, error) {
+	// Sui escrow events must come from the package the order was quoted with
+	var srcFactory, dstFactory string
+	if quote := orderEntry.Quote.Quote; quote != nil {
+		srcFactory, dstFactory = quote.SrcEscrowFactory, quote.DstEscrowFactory
+	}
+
+	src, err := m.fetchSrcEscrow(ctx, orderEntry.Order.SrcChainID, srcFactory, srcTxHash)
+	if err != nil {
+		return nil, fmt.Errorf("fetching src escrow: %w", err)
+	}
+
+	if src.orderHash != orderEntry.OrderHash {
+		return nil, fmt.Errorf("src escrow is for order %s, not %s", src.orderHash.Hex(), orderEntry.OrderHash.Hex())
+	}
+	checks := []string{"order-hash"}
+
+	dst, err := m.fetchDstEscrow(ctx, orderEntry.Quote.QuoteRequest.DstChain, dstFactory, orderEntry.Order.LimitOrder.TakerAsset, dstTxHash)
+	if err != nil {
+		return nil, fmt.Errorf("fetching dst escrow: %w", err)
+	}
+
+	codeChecks, err := m.checkEscrowCode(ctx, orderEntry, src, dst)
+	if err != nil {
+		return nil, err
+	}
+	checks = append(checks, codeChecks...)
+
+	// after the code check, so the balance is read at a verified escrow clone
+	if err := m.checkSrcBalance(ctx, orderEntry, src); err != nil {
+		return nil, err
+	}
+	checks = append(checks, "src-balance")
+
+	if src.hashlock != dst.hashlock {
+		return nil, fmt.Errorf("hashlock mismatch: src %s, dst %s", src.hashlock.Hex(), dst.hashlock.Hex())
+	}
+	checks = append(checks, "hashlock")
+
+	if err := m.checkTakers(orderEntry, claimant, src.taker, dst.taker); err != nil {
+		return nil, err
+	}
+	if claimant != nil {
+		checks = append(checks, "resolver-takers")
+	}
+	if exclusiveResolver(orderEntry) != "" {
+		checks = append(checks, "exclusive-resolver")
+	}
+
+	if err := m.checkDstReceiver(ctx, orderEntry, dstTxHash, dst); err != nil {
+		return nil, err
+	}
+	if orderEntry.DstReceiver != "" {
+		checks = append(checks, "dst-receiver")
+	}
+
+	cfg := m.Config()
+	policies := make(map[string]string, 2)
+
+	depositTolerance := cfg.VerifyTolerance(config.CheckSafetyDeposit)
+	if err := checkSafetyDeposit(orderEntry, dst, depositTolerance); err != nil {
+		return nil, err
+	}
+	checks = append(checks, config.CheckSafetyDeposit)
+	policies[config.CheckSafetyDeposit] = depositTolerance.String()
+
+	hashIdx, err := secretIndex(orderEntry, src.hashlock)
+	if err != nil {
+		return nil, err
+	}
+	checks = append(checks, "secret-index")
+
+	if err := m.checkReservation(orderEntry.OrderHash.Hex(), hashIdx, claimant); err != nil {
+		return nil, err
+	}
+	checks = append(checks, "reservation")
+
+	auctionTolerance := cfg.VerifyTolerance(config.CheckAuctionAmount)
+	if err := checkAuctionAmount(orderEntry, src.timestamp.Time, src.amount, dst.amount, auctionTolerance); err != nil {
+		return nil, err
+	}
+	checks = append(checks, config.CheckAuctionAmount)
+	policies[config.CheckAuctionAmount] = auctionTolerance.String()
+	if orderEntry.OrderType == MultiFill {
+		// checked while claiming the fill below
+		checks = append(checks, "fill-portion")
+	}
+
+	v := &Verification{
+		OrderHash:     orderEntry.OrderHash.Hex(),
+		SrcTxHash:     srcTxHash,
+		DstTxHash:     dstTxHash,
+		HashIdx:       hashIdx,
+		Hashlock:      src.hashlock,
+		SrcEscrow:     src.escrow,
+		DstEscrow:     dst.escrow,
+		SrcTaker:      src.taker,
+		DstTaker:      dst.taker,
+		SrcAmount:     src.amount,
+		DstAmount:     dst.amount,
+		SrcTimestamp:  src.timestamp,
+		DstTimestamp:  dst.timestamp,
+		SrcImmutables: src.immutables,
+		DstImmutables: dst.immutables,
+		Checks:        checks,
+		Policies:      policies,
+		VerifiedAt:    time.Now(),
+	}
+
+	// last, since it commits the fill's portion of the order
+	if err := m.claimCanonical(orderEntry, v); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// checkTakers makes sure both escrows were created from addresses of the
+// resolver claiming the fill and, for exclusive orders, of the exclusive resolver.
+func (m *Manager) checkTakers(orderEntry *OrderEntry, claimant *resolver.Resolver, srcTaker, dstTaker string) error {
+	if claimant != nil {
+		if !claimant.Owns(srcTaker) {
+			return fmt.Errorf("src escrow taker %s does not belong to resolver %s", srcTaker, claimant.ID)
+		}
+		if !claimant.Owns(dstTaker) {
+			return fmt.Errorf("dst escrow taker %s does not belong to resolver %s", dstTaker, claimant.ID)
+		}
+	}
+
+	exclusive := exclusiveResolver(orderEntry)
+	if exclusive == "" {
+		return nil
+	}
+
+	if owner, ok := m.resolvers.ByAddress(exclusive); ok {
+		if claimant != nil && claimant.ID != owner.ID {
+			return fmt.Errorf("order is exclusive to resolver %s", owner.ID)
+		}
+		if !owner.Owns(srcTaker) || !owner.Owns(dstTaker) {
+			return fmt.Errorf("escrow takers do not belong to exclusive resolver %s", owner.ID)
+		}
+		return nil
+	}
+
+	// an unregistered exclusive resolver is only known by its EVM address
+	evmTaker := srcTaker
+	if orderEntry.Order.SrcChainID.IsMove() {
+		evmTaker = dstTaker
+	}
+	if ethcommon.HexToAddress(evmTaker) != ethcommon.HexToAddress(exclusive) {
+		return fmt.Errorf("escrow taker %s is not the exclusive resolver %s", evmTaker, exclusive)
+	}
+
+	return nil
+}
+
+// checkAuctionAmount rejects fills whose destination amount is outside the
+// tolerance of what the Dutch auction required when the source escrow was
+// created. Partial fills owe the share of the taking amount matching the
+// making amount they take.
+func checkAuctionAmount(orderEntry *OrderEntry, filledAt time.Time, fillMaking, amount *big.Int, tolerance config.Tolerance) error {
+	quote := orderEntry.Quote.Quote
+	if quote == nil {
+		return nil
+	}
+
+	preset, ok := quote.Presets[quote.RecommendedPreset]
+	if !ok {
+		return fmt.Errorf("quote %s has no %s preset", quote.QuoteID, quote.RecommendedPreset)
+	}
+
+	limitOrder := orderEntry.Order.LimitOrder
+	base, err := common.ParseAmount(limitOrder.TakingAmount)
+	if err != nil {
+		return fmt.Errorf("taking amount: %w", err)
+	}
+
+	if orderEntry.OrderType == MultiFill {
+		making, err := orderEntry.Order.SrcChainID.ParseAmount(limitOrder.MakingAmount)
+		if err != nil || making.Sign() == 0 {
+			return fmt.Errorf("invalid making amount: %q", limitOrder.MakingAmount)
+		}
+		base.Mul(base, fillMaking)
+		base.Quo(base, making)
+	}
+
+	curve := auction.FromPreset(orderEntry.SubmittedAt, preset)
+	required := curve.TakingAmount(base, filledAt)
+
+	if err := checkTolerance(tolerance, amount, required); err != nil {
+		return fmt.Errorf("dst amount off the auction amount at %s: %w", filledAt.Format(time.RFC3339), err)
+	}
+
+	return nil
+}
+
+// claimFillPortion checks that a partial fill used the secret implied by the
+// order's cumulative filled amount and, if so, adds the fill to it. With N+1
+// secrets the order is split into N parts; the extra secret is reserved for
+// the fill that completes the order. Fills below the maker's minimum are
+// refused unless they complete it. The caller holds the entry's lock.
+func claimFillPortion(orderEntry *OrderEntry, hashIdx int, fillMaking *big.Int) error {
+	if orderEntry.OrderType != MultiFill {
+		return nil
+	}
+
+	making, err := orderEntry.Order.SrcChainID.ParseAmount(orderEntry.Order.LimitOrder.MakingAmount)
+	if err != nil || making.Sign() == 0 {
+		return fmt.Errorf("invalid making amount: %q", orderEntry.Order.LimitOrder.MakingAmount)
+	}
+	if fillMaking.Sign() <= 0 {
+		return fmt.Errorf("src escrow amount must be positive")
+	}
+
+	cumulative := new(big.Int).Add(orderEntry.FilledMakingAmount, fillMaking)
+	if cumulative.Cmp(making) > 0 {
+		return fmt.Errorf("fill of %s overfills the order, %s of %s already filled", fillMaking, orderEntry.FilledMakingAmount, making)
+	}
+	if min, ok := minFill(orderEntry); ok && fillMaking.Cmp(min) < 0 && cumulative.Cmp(making) != 0 {
+		return fmt.Errorf("fill of %s is below the maker's minimum fill of %s", fillMaking, min)
+	}
+
+	parts := big.NewInt(int64(len(orderEntry.Order.SecretHashes) - 1))
+	expected := new(big.Int).Sub(cumulative, big.NewInt(1))
+	expected.Mul(expected, parts)
+	expected.Quo(expected, making)
+
+	completes := cumulative.Cmp(making) == 0 && int64(hashIdx) == parts.Int64()
+	if int64(hashIdx) != expected.Int64() && !completes {
+		return fmt.Errorf("fill up to %s of %s must use secret %d, not %d", cumulative, making, expected.Int64(), hashIdx)
+	}
+
+	orderEntry.FilledMakingAmount.Set(cumulative)
+	return nil
+}
+
+// minFill returns the smallest making amount the maker allows a partial fill
+// to take, if it asked for one.
+func minFill(orderEntry *OrderEntry) (*big.Int, bool) {
+	if orderEntry.Order == nil || orderEntry.Order.MinFillAmount == "" {
+		return nil, false
+	}
+	min, err := orderEntry.Order.SrcChainID.ParseAmount(orderEntry.Order.MinFillAmount)
+	if err != nil {
+		return nil, false
+	}
+	return min, true
+}
+
+// checkDstReceiver makes sure the dst escrow pays out to the receiver the
+// maker asked for. Orders without an explicit receiver rely on the escrow
+// factory deriving it from the signed order.
+func (m *Manager) checkDstReceiver(ctx context.Context, orderEntry *OrderEntry, dstTxHash string, dst *dstEscrow) error {
+	if orderEntry.DstReceiver == "" {
+		return nil
+	}
+
+	var maker string
+	if common.IsSuiChain(orderEntry.Quote.QuoteRequest.DstChain) {
+		var err error
+		if maker, err = chain.FetchMoveEscrowMaker(ctx, m.suiClient, dst.escrow); err != nil {
+			return fmt.Errorf("fetching dst escrow maker: %w", err)
+		}
+	} else {
+		client, err := m.evm(escrowChain(orderEntry, DstEscrow))
+		if err != nil {
+			return err
+		}
+		addr, err := chain.FetchEvmDstEscrowMaker(ctx, client, ethcommon.HexToHash(dstTxHash), dst.hashlock)
+		if err != nil {
+			return fmt.Errorf("fetching dst escrow maker: %w", err)
+		}
+		maker = addr.Hex()
+	}
+
+	if !common.SameAddress(maker, orderEntry.DstReceiver) {
+		return fmt.Errorf("dst escrow pays %s, not the receiver %s", maker, orderEntry.DstReceiver)
+	}
+
+	return nil
+}
+
+// checkEscrowCode makes sure the fill's EVM escrow is a clone of the escrow
+// implementation of the factory the order was quoted with. Factories that are
+// not EVM addresses, i.e. Sui packages, are skipped. It returns the names of
+// the checks it made.
+func (m *Manager) checkEscrowCode(ctx context.Context, orderEntry *OrderEntry, src *srcEscrow, dst *dstEscrow) ([]string, error) {
+	quote := orderEntry.Quote.Quote
+	if quote == nil {
+		return nil, nil
+	}
+
+	var checks []string
+	if !orderEntry.Order.SrcChainID.IsMove() && ethcommon.IsHexAddress(quote.SrcEscrowFactory) {
+		if err := m.verifyEscrowCode(ctx, orderEntry.Order.SrcChainID, ethcommon.HexToAddress(quote.SrcEscrowFactory), ethcommon.HexToAddress(src.escrow), false); err != nil {
+			return nil, fmt.Errorf("src escrow: %w", err)
+		}
+		checks = append(checks, "src-escrow-code")
+	}
+
+	if orderEntry.Quote.QuoteRequest.DstChain != common.Sui.String() && ethcommon.IsHexAddress(quote.DstEscrowFactory) {
+		if err := m.verifyEscrowCode(ctx, escrowChain(orderEntry, DstEscrow), ethcommon.HexToAddress(quote.DstEscrowFactory), ethcommon.HexToAddress(dst.escrow), true); err != nil {
+			return nil, fmt.Errorf("dst escrow: %w", err)
+		}
+		checks = append(checks, "dst-escrow-code")
+	}
+
+	return checks, nil
+}
+
+// verifyEscrowCode is chain.VerifyEscrowCode on chainID with the factory's
+// escrow implementation cached.
+func (m *Manager) verifyEscrowCode(ctx context.Context, chainID common.ChainID, factory, escrow ethcommon.Address, dst bool) error {
+	client, err := m.evm(chainID)
+	if err != nil {
+		return err
+	}
+	implementation, err := m.escrowImplementation(ctx, chainID, factory, dst)
+	if err != nil {
+		return fmt.Errorf("fetching escrow implementation of factory %s: %w", factory.Hex(), err)
+	}
+	return chain.VerifyCloneCode(ctx, client, implementation, escrow)
+}
+
+// checkSrcBalance makes sure the src escrow holds the making amount of the
+// fill, less the configured tolerance for fee-on-transfer tokens. EVM escrows
+// are proxies holding the tokens at their own address; Sui escrows hold them
+// in their deposit coin.
+func (m *Manager) checkSrcBalance(ctx context.Context, orderEntry *OrderEntry, src *srcEscrow) error {
+	var balance *big.Int
+	var err error
+	if orderEntry.Order.SrcChainID.IsMove() {
+		balance, err = chain.FetchCoinFieldBalance(ctx, m.suiClient, src.escrow, "deposit")
+	} else {
+		token := ethcommon.HexToAddress(orderEntry.Order.LimitOrder.MakerAsset)
+		if src.immutables != nil {
+			token = src.immutables.Token
+		}
+		var client chain.EVMClient
+		if client, err = m.evm(orderEntry.Order.SrcChainID); err == nil {
+			balance, err = chain.FetchERC20Balance(client, token, ethcommon.HexToAddress(src.escrow))
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("fetching src escrow balance: %w", err)
+	}
+
+	bps := m.Config().SrcBalanceTolerance(orderEntry.Order.LimitOrder.MakerAsset)
+	floor := new(big.Int).Mul(src.amount, new(big.Int).SetUint64(10_000-bps))
+	floor.Quo(floor, big.NewInt(10_000))
+	if balance.Cmp(floor) < 0 {
+		return fmt.Errorf("src escrow holds %s, fill requires %s", balance, src.amount)
+	}
+	return nil
+}
+
+// checkSafetyDeposit makes sure the dst escrow holds the order's dst safety
+// deposit, within tolerance. Both are in the dst chain's native units: quotes are
+// normalized when they are fetched, see common.ChainID.NativeDecimals. The
+// deposit is the one fixed at quote time, never recomputed from the current
+// gas price, so fills are not rejected when gas moves, see SafetyDeposit.
+func checkSafetyDeposit(orderEntry *OrderEntry, dst *dstEscrow, tolerance config.Tolerance) error {
+	var want *big.Int
+	if ext := orderEntry.Extension; ext != nil {
+		want = ext.Escrow.DstSafetyDeposit
+	} else if quote := orderEntry.Quote.Quote; quote != nil {
+		v, err := common.ParseAmount(quote.DstSafetyDeposit)
+		if err != nil {
+			return fmt.Errorf("quote dst safety deposit: %w", err)
+		}
+		want = v
+	}
+	if want == nil {
+		return nil
+	}
+
+	if err := checkTolerance(tolerance, dst.safetyDeposit, want); err != nil {
+		return fmt.Errorf("dst escrow safety deposit: %w", err)
+	}
+	return nil
+}
+
+// exclusiveResolver returns the exclusive resolver of the order's preset, if any.
+func exclusiveResolver(orderEntry *OrderEntry) string {
+	quote := orderEntry.Quote.Quote
+	if quote == nil {
+		return ""
+	}
+
+	preset, ok := quote.Presets[quote.RecommendedPreset]
+	if !ok || preset.ExclusiveResolver == nil {
+		return ""
+	}
+
+	return *preset.ExclusiveResolver
+}
+
+// secretIndex maps an escrow hashlock to the index of the secret it locks.
+// Single fill orders have exactly one secret.
+func secretIndex(orderEntry *OrderEntry, hashlock ethcommon.Hash) (int, error) {
+	if orderEntry.OrderType == SingleFill {
+		return 0, nil
+	}
+
+	for i, secretHash := range orderEntry.Order.SecretHashes {
+		if strings.EqualFold(ethcommon.HexToHash(secretHash).Hex(), hashlock.Hex()) {
+			return i, nil
+		}
+	}
+
+	return 0, fmt.Errorf("hashlock %s is not one of the order's secret hashes", hashlock.Hex())
+}
+
+func (m *Manager) fetchSrcEscrow(ctx context.Context, srcChainID common.ChainID, factory, txHash string) (*srcEscrow, error) {
+	if srcChainID.IsMove() {
+		evt, timestamp, err := chain.FetchMoveSrcEscrowEvent(ctx, m.suiClient, txHash, factory)
+		if err != nil {
+			return nil, err
+		}
+
+		return &srcEscrow{
+			orderHash: evt.OrderHash,
+			hashlock:  evt.Hashlock,
+			escrow:    hexutil.Encode(evt.ID.Data()),
+			taker:     string(evt.Taker),
+			amount:    evt.MakingAmount,
+			timestamp: timestamp,
+		}, nil
+	}
+
+	if src, _ := m.prewarmedEscrow(txHash); src != nil {
+		cached := *src
+		return &cached, nil
+	}
+	client, err := m.evm(srcChainID)
+	if err != nil {
+		return nil, err
+	}
+	return m.fetchEvmSrcEscrow(ctx, client, ethcommon.HexToHash(txHash))
+}
+
+// fetchEvmSrcEscrow decodes the EVM src escrow creation of txHash.
+func (m *Manager) fetchEvmSrcEscrow(ctx context.Context, client chain.EVMClient, txHash ethcommon.Hash) (*srcEscrow, error) {
+	evt, escrow, timestamp, err := chain.FetchEvmSrcEscrowEvent(ctx, client, txHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return &srcEscrow{
+		orderHash:  evt.SrcImmutables.OrderHash,
+		hashlock:   evt.SrcImmutables.Hashlock,
+		escrow:     escrow.Hex(),
+		taker:      evt.SrcImmutables.Taker.Hex(),
+		amount:     evt.SrcImmutables.Amount,
+		timestamp:  timestamp,
+		immutables: &evt.SrcImmutables,
+	}, nil
+}
+
+func (m *Manager) fetchDstEscrow(ctx context.Context, dstChain, factory, token, txHash string) (*dstEscrow, error) {
+	if dstChain == common.Sui.String() {
+		evt, timestamp, err := chain.FetchMoveDstEscrowEvent(ctx, m.suiClient, txHash, factory)
+		if err != nil {
+			return nil, err
+		}
+
+		escrow := hexutil.Encode(evt.ID.Data())
+		deposit, err := chain.FetchCoinFieldBalance(ctx, m.suiClient, escrow, "safety_deposit")
+		if err != nil {
+			return nil, fmt.Errorf("fetching safety deposit: %w", err)
+		}
+
+		return &dstEscrow{
+			hashlock:      evt.Hashlock,
+			escrow:        escrow,
+			taker:         string(evt.Taker),
+			amount:        evt.Amount,
+			safetyDeposit: deposit,
+			timestamp:     timestamp,
+		}, nil
+	}
+
+	dstChainID, err := common.ParseChainID(dstChain)
+	if err != nil {
+		return nil, err
+	}
+	client, err := m.evm(dstChainID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, creation := m.prewarmedEscrow(txHash)
+	if creation == nil {
+		if creation, err = m.fetchEvmDstCreation(ctx, client, ethcommon.HexToHash(txHash)); err != nil {
+			return nil, err
+		}
+	}
+	evt := creation.event
+
+	amount, err := chain.FetchERC20Balance(client, ethcommon.HexToAddress(token), evt.Escrow)
+	if err != nil {
+		return nil, err
+	}
+
+	deposit, err := chain.FetchNativeBalance(ctx, client, evt.Escrow)
+	if err != nil {
+		return nil, fmt.Errorf("fetching safety deposit: %w", err)
+	}
+
+	return &dstEscrow{
+		hashlock:      evt.Hashlock,
+		escrow:        evt.Escrow.Hex(),
+		taker:         evt.Taker.Hex(),
+		amount:        amount,
+		safetyDeposit: deposit,
+		timestamp:     creation.timestamp,
+		immutables:    creation.immutables,
+	}, nil
+}
+
+// fetchEvmDstCreation decodes the EVM dst escrow creation of txHash.
+func (m *Manager) fetchEvmDstCreation(ctx context.Context, client chain.EVMClient, txHash ethcommon.Hash) (*evmDstCreation, error) {
+	evt, timestamp, err := chain.FetchEvmDstEscrowEvent(ctx, client, txHash)
+	if err != nil {
+		return nil, err
+	}
+
+	// only needed by makers cancelling later, a deployment through a contract
+	// with another calldata layout is not an invalid fill
+	immutables, err := chain.FetchEvmDstImmutables(ctx, client, txHash, evt.Hashlock)
+	if err != nil {
+		m.logFor(ctx).Printf("Failed to decode dst escrow immutables of tx %s: %v", txHash.Hex(), err)
+	} else {
+		immutables.Timelocks = chain.WithDeployedAt(immutables.Timelocks, uint64(timestamp.Unix()))
+	}
+
+	return &evmDstCreation{event: evt, timestamp: timestamp, immutables: immutables}, nil
+}
+
+// verification is a cached result for one (orderHash, srcTx, dstTx, resolver) tuple.
+// done is closed once result/err are set, so duplicates that arrive while the
+// first check is in flight wait for it instead of verifying again.
+type verification struct {
+	done   chan struct{}
+	result *Verification
+	err    error
+}
+
+func verificationKey(orderHash, srcTxHash, dstTxHash string, claimant *resolver.Resolver) string {
+	key := strings.ToLower(orderHash + ":" + srcTxHash + ":" + dstTxHash)
+	if claimant != nil {
+		key += ":" + claimant.ID
+	}
+	return key
+}
+
+// verifyContext derives from ctx the context of verifying a fill of
+// orderEntry claimed by claimant, whose log lines carry the order hash, the
+// quote id and the resolver id.
+func verifyContext(ctx context.Context, orderEntry *OrderEntry, claimant *resolver.Resolver) context.Context {
+	args := []any{"orderHash", orderEntry.OrderHash.Hex(), "quoteId", orderEntry.Quote.QuoteID.String()}
+	if claimant != nil {
+		args = append(args, "resolver", claimant.ID)
+	}
+	return logging.With(ctx, args...)
+}
+
+// verifyOnce runs verifyFill at most once per tuple, within ctx, a child of
+// verifyCtx. The second return value reports whether the tuple had already
+// been seen. Failures are not cached so a resolver can retry once its
+// transactions are indexed.
+func (m *Manager) verifyOnce(ctx context.Context, orderEntry *OrderEntry, claimant *resolver.Resolver, srcTxHash, dstTxHash string) (*Verification, bool, error) {
+	key := verificationKey(orderEntry.OrderHash.Hex(), srcTxHash, dstTxHash, claimant)
+
+	m.verifyMu.Lock()
+	if item, err := m.verifications.Get(key); err == nil {
+		m.verifyMu.Unlock()
+		cached := item.Value().(*verification)
+		<-cached.done
+		return cached.result, true, cached.err
+	}
+
+	v := &verification{done: make(chan struct{})}
+	m.verifications.Set(key, ttlmap.NewItem(v, ttlmap.WithTTL(VerificationCacheTTL)), nil)
+	m.verifyMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, ChainCallTimeout)
+	defer cancel()
+
+	v.result, v.err = m.verifyFill(ctx, orderEntry, claimant, srcTxHash, dstTxHash)
+	if v.err != nil {
+		m.verifyMu.Lock()
+		m.verifications.Delete(key)
+		m.verifyMu.Unlock()
+
+		if m.verifyCtx.Err() != nil {
+			// not the fill's fault, it is verified again after the restart
+			v.err = fmt.Errorf("%w: %v", errVerifyInterrupted, v.err)
+		} else {
+			verdict := classifyVerifyError(v.err)
+			metrics.VerifyFailures.Add(string(verdict), 1)
+			details := map[string]any{"error": v.err.Error(), "verdict": string(verdict)}
+			if claimant != nil {
+				details["resolver"] = claimant.ID
+			}
+			m.events.Publish(bus.Event{Kind: bus.VerificationFailed, Subject: orderEntry.OrderHash.Hex(), Details: details})
+
+			if verdict == verdictAlert {
+				m.alertRPCUnavailable(ctx, orderEntry, v.err)
+			}
+			if verdict != verdictReject {
+				v.err = fmt.Errorf("%w: %w", ErrVerifyRetry, v.err)
+			}
+		}
+	}
+	close(v.done)
+
+	return v.result, false, v.err
+}
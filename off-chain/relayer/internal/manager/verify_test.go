@@ -0,0 +1,655 @@
+package manager
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"relayer/internal/chain"
+	"relayer/internal/chain/mock"
+	"relayer/internal/common"
+	"relayer/internal/hash"
+	"relayer/internal/hashlock"
+	"relayer/internal/resolver"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/block-vision/sui-go-sdk/models"
+	"github.com/ethereum/go-ethereum"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/google/uuid"
+	"github.com/mr-tron/base58"
+)
+
+// amounts of every test order
+const (
+	testMaking = 1_000_000_000_000_000_000
+	testTaking = 2_000_000_000
+)
+
+var balanceOfSelector = crypto.Keccak256([]byte("balanceOf(address)"))[:4]
+
+// fillFixture is a Manager on mock chains, with orders swapping from an EVM
+// chain to dstChain and the escrow deployments of their fills.
+type fillFixture struct {
+	m        *Manager
+	evm      *mock.EVMClient
+	sui      *mock.SuiClient
+	dstChain common.ChainID
+	factory  ethcommon.Address
+	taker    ethcommon.Address
+	suiTaker string
+
+	mu sync.Mutex
+	// ERC20 balances of every holder by token, plenty when unset
+	balances map[ethcommon.Address]*big.Int
+}
+
+func newFillFixture(t *testing.T, dstChain common.ChainID) *fillFixture {
+	t.Helper()
+	f := &fillFixture{
+		evm:      mock.NewEVMClient(),
+		sui:      mock.NewSuiClient(),
+		dstChain: dstChain,
+		factory:  randomAddress(),
+		taker:    randomAddress(),
+		suiTaker: hexutil.Encode(randomBytes(32)),
+		balances: make(map[ethcommon.Address]*big.Int),
+	}
+	f.evm.CallFunc = func(call ethereum.CallMsg) ([]byte, error) {
+		if len(call.Data) >= 4 && bytes.Equal(call.Data[:4], balanceOfSelector) {
+			f.mu.Lock()
+			balance, ok := f.balances[*call.To]
+			f.mu.Unlock()
+			if !ok {
+				balance = new(big.Int).Lsh(big.NewInt(1), 128)
+			}
+			return ethcommon.LeftPadBytes(balance.Bytes(), 32), nil
+		}
+		// addressOfEscrowSrc and the escrow implementations, any deterministic address does
+		return ethcommon.LeftPadBytes(crypto.Keccak256(call.Data)[12:], 32), nil
+	}
+
+	f.m = NewManagerWithClients(log.New(io.Discard, "", 0), f.evm, f.sui)
+	// closes the manager a restart left running
+	t.Cleanup(func() { f.m.Close() })
+	return f
+}
+
+func (f *fillFixture) setBalance(token string, balance *big.Int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.balances[ethcommon.HexToAddress(token)] = balance
+}
+
+// order stores a pending order of a fresh quote, changed by edit if not nil
+// before it is stored.
+func (f *fillFixture) order(t *testing.T, orderType OrderType, edit func(*OrderEntry)) *OrderEntry {
+	t.Helper()
+	orderEntry := f.newOrder(t, orderType, edit)
+	if err := f.m.SetOrder(orderEntry); err != nil {
+		t.Fatalf("storing order: %v", err)
+	}
+	return orderEntry
+}
+
+// newOrder returns a pending order of a fresh quote, changed by edit if not
+// nil, storing only its quote.
+func (f *fillFixture) newOrder(t *testing.T, orderType OrderType, edit func(*OrderEntry)) *OrderEntry {
+	t.Helper()
+	id := uuid.New()
+	quote := QuoteEntry{
+		QuoteID: id,
+		QuoteRequest: &common.QuoteRequestParams{
+			SrcChain:        common.EthereumMainnet.String(),
+			DstChain:        f.dstChain.String(),
+			SrcTokenAddress: randomAddress().Hex(),
+			DstTokenAddress: randomAddress().Hex(),
+			Amount:          fmt.Sprint(testMaking),
+		},
+		Quote: &common.Quote{
+			QuoteID:        id,
+			SrcTokenAmount: fmt.Sprint(testMaking),
+			DstTokenAmount: fmt.Sprint(testTaking),
+			Presets: common.QuoterPresets{
+				common.PresetFast: {
+					AuctionDuration:    180,
+					AuctionStartAmount: fmt.Sprint(testTaking),
+					StartAmount:        fmt.Sprint(testTaking),
+					AuctionEndAmount:   fmt.Sprint(testTaking),
+					SecretsCount:       1,
+				},
+			},
+			RecommendedPreset: common.PresetFast,
+			TimeLocks: common.TimeLocksRaw{
+				SrcWithdrawal:         12,
+				SrcPublicWithdrawal:   120,
+				SrcCancellation:       300,
+				SrcPublicCancellation: 600,
+				DstWithdrawal:         12,
+				DstPublicWithdrawal:   120,
+				DstCancellation:       300,
+			},
+			SrcSafetyDeposit: "0",
+			DstSafetyDeposit: "0",
+		},
+		ExpiresAt: f.m.QuoteExpiry(common.PresetFast),
+	}
+
+	order := common.Order{
+		SrcChainID: common.EthereumMainnet,
+		LimitOrder: common.LimitOrder{
+			Salt:         new(big.Int).SetBytes(randomBytes(16)).String(),
+			Maker:        randomAddress().Hex(),
+			Receiver:     ethcommon.Address{}.Hex(),
+			MakerAsset:   quote.QuoteRequest.SrcTokenAddress,
+			TakerAsset:   quote.QuoteRequest.DstTokenAddress,
+			MakingAmount: quote.Quote.SrcTokenAmount,
+			TakingAmount: quote.Quote.DstTokenAmount,
+			MakerTraits:  "0",
+		},
+		QuoteID: id,
+	}
+	if orderType == MultiFill {
+		for range 3 {
+			order.SecretHashes = append(order.SecretHashes, hashlock.Keccak256.Hash(randomBytes(32)).Hex())
+		}
+	}
+	orderHash, err := hash.GetOrderHashForLimitOrder(order.SrcChainID, order.LimitOrder)
+	if err != nil {
+		t.Fatalf("hashing order: %v", err)
+	}
+
+	submittedAt := time.Now()
+	orderEntry := &OrderEntry{
+		OrderType: orderType,
+		OrderHash: orderHash,
+		Order:     &order,
+		OrderStatus: common.OrderStatus{
+			Status:    common.OrderStatusPending,
+			Order:     &order.LimitOrder,
+			CreatedAt: submittedAt.Format(time.RFC3339),
+		},
+		Quote:              quote,
+		SubmittedAt:        submittedAt,
+		FilledMakingAmount: new(big.Int),
+		Escrows:            make(map[string]EscrowSide),
+		Closed:             make(map[string]string),
+		Canonical:          make(map[int]*Verification),
+		Hashlock:           hashlock.Keccak256,
+		Fee:                &OrderFee{ChainID: order.SrcChainID.String(), Token: order.LimitOrder.MakerAsset, Amount: new(big.Int)},
+	}
+	if edit != nil {
+		edit(orderEntry)
+	}
+
+	if err := f.m.SetQuote(orderEntry.Quote); err != nil {
+		t.Fatalf("storing quote: %v", err)
+	}
+	return orderEntry
+}
+
+// deployment describes the escrow deployments of a fill.
+type deployment struct {
+	orderHash   ethcommon.Hash
+	srcHashlock ethcommon.Hash
+	dstHashlock ethcommon.Hash
+	srcAmount   *big.Int
+	dstAmount   *big.Int
+	srcTaker    ethcommon.Address
+	dstTaker    string
+}
+
+// fill returns the deployments filling orderEntry with the secret at hashIdx,
+// the only one of single fill orders, for the whole making amount.
+func (f *fillFixture) fill(orderEntry *OrderEntry, hashIdx int) deployment {
+	lock := hashlock.Keccak256.Hash(randomBytes(32))
+	if orderEntry.OrderType == MultiFill {
+		lock = ethcommon.HexToHash(orderEntry.Order.SecretHashes[hashIdx])
+	}
+	dstTaker := f.taker.Hex()
+	if f.dstChain.IsMove() {
+		dstTaker = f.suiTaker
+	}
+	return deployment{
+		orderHash:   orderEntry.OrderHash,
+		srcHashlock: lock,
+		dstHashlock: lock,
+		srcAmount:   big.NewInt(testMaking),
+		dstAmount:   big.NewInt(testTaking),
+		srcTaker:    f.taker,
+		dstTaker:    dstTaker,
+	}
+}
+
+// deploy adds the escrow deployments of d to the mock chains and returns the
+// TXHASH event reporting them.
+func (f *fillFixture) deploy(t *testing.T, orderEntry *OrderEntry, d deployment) string {
+	t.Helper()
+	limitOrder := orderEntry.Order.LimitOrder
+	maker := ethcommon.HexToAddress(limitOrder.Maker)
+	now := time.Now()
+
+	src, err := chain.EncodeEvmSrcEscrowCreated(f.factory, chain.EvmSrcEscrowCreatedEvent{
+		SrcImmutables: chain.Immutables{
+			OrderHash: d.orderHash,
+			Hashlock:  d.srcHashlock,
+			Maker:     maker,
+			Taker:     d.srcTaker,
+			Token:     ethcommon.HexToAddress(limitOrder.MakerAsset),
+			Amount:    d.srcAmount,
+		},
+		DstImmutablesComplement: chain.DstImmutablesComplement{
+			Maker:   maker,
+			Amount:  d.dstAmount,
+			Token:   limitOrder.TakerAsset,
+			ChainId: new(big.Int).SetUint64(uint64(f.dstChain)),
+		},
+	})
+	if err != nil {
+		t.Fatalf("encoding src escrow: %v", err)
+	}
+	srcTx := ethcommon.BytesToHash(randomBytes(32))
+	f.evm.AddReceipt(srcTx, &types.Receipt{Status: types.ReceiptStatusSuccessful, Logs: []*types.Log{src}}, uint64(now.Unix()))
+
+	var dstTx string
+	if f.dstChain.IsMove() {
+		dstTx = f.deploySuiDst(d, now)
+	} else {
+		dstTx = f.deployEvmDst(t, orderEntry, d, now)
+	}
+	return fmt.Sprintf("%s %s %s %s", TXHASH_EVENT, orderEntry.OrderHash.Hex(), srcTx.Hex(), dstTx)
+}
+
+func (f *fillFixture) deployEvmDst(t *testing.T, orderEntry *OrderEntry, d deployment, now time.Time) string {
+	t.Helper()
+	limitOrder := orderEntry.Order.LimitOrder
+	dst, err := chain.EncodeEvmDstEscrowCreated(f.factory, chain.EvmDstEscrowCreatedEvent{
+		Escrow:   randomAddress(),
+		Hashlock: d.dstHashlock,
+		Taker:    ethcommon.HexToAddress(d.dstTaker),
+	})
+	if err != nil {
+		t.Fatalf("encoding dst escrow: %v", err)
+	}
+	calldata, err := chain.EncodeEvmDstEscrowCalldata(chain.Immutables{
+		OrderHash: d.orderHash,
+		Hashlock:  d.dstHashlock,
+		Maker:     ethcommon.HexToAddress(limitOrder.Maker),
+		Taker:     ethcommon.HexToAddress(d.dstTaker),
+		Token:     ethcommon.HexToAddress(limitOrder.TakerAsset),
+		Amount:    d.dstAmount,
+	}, big.NewInt(now.Unix()+600))
+	if err != nil {
+		t.Fatalf("encoding dst calldata: %v", err)
+	}
+
+	// the dst amount is what the escrow holds
+	f.setBalance(limitOrder.TakerAsset, d.dstAmount)
+
+	dstTx := ethcommon.BytesToHash(randomBytes(32))
+	f.evm.AddReceipt(dstTx, &types.Receipt{Status: types.ReceiptStatusSuccessful, Logs: []*types.Log{dst}}, uint64(now.Unix()))
+	f.evm.AddTransaction(dstTx, types.NewTx(&types.LegacyTx{To: &f.factory, Data: calldata}))
+	return dstTx.Hex()
+}
+
+func (f *fillFixture) deploySuiDst(d deployment, now time.Time) string {
+	escrow := hexutil.Encode(randomBytes(32))
+	digest := base58.Encode(randomBytes(32))
+	f.sui.AddTransaction(digest, now.UnixMilli(), &models.SuiEventResponse{
+		Type: "0x2::escrow::DstEscrowCreatedEvent",
+		ParsedJson: map[string]any{
+			"id":               escrow,
+			"hashlock":         d.dstHashlock.Hex(),
+			"taker":            d.dstTaker,
+			"token_package_id": "0x2",
+			"amount":           d.dstAmount.String(),
+		},
+	})
+	f.sui.SetObject(escrow, models.SuiObjectResponse{Data: &models.SuiObjectData{
+		ObjectId: escrow,
+		Content: &models.SuiParsedData{
+			DataType: "moveObject",
+			SuiMoveObject: models.SuiMoveObject{Fields: map[string]any{
+				"safety_deposit": map[string]any{"fields": map[string]any{"balance": "0"}},
+			}},
+		},
+	}})
+	return digest
+}
+
+func TestHandleTxHashEvent(t *testing.T) {
+	for _, dstChain := range []common.ChainID{common.Base, common.Sui} {
+		t.Run(fmt.Sprintf("to %s", dstChain), func(t *testing.T) {
+			f := newFillFixture(t, dstChain)
+			claimant := &resolver.Resolver{ID: "resolver-1", EVMAddress: f.taker.Hex(), SuiAddress: f.suiTaker}
+
+			orderEntry := f.order(t, SingleFill, nil)
+			event := f.deploy(t, orderEntry, f.fill(orderEntry, 0))
+			if err := f.m.HandleReceiveEvent(claimant, []byte(event)); err != nil {
+				t.Fatalf("HandleReceiveEvent(%q) = %v", event, err)
+			}
+
+			orderEntry.Lock()
+			v, ok := orderEntry.Canonical[0]
+			escrows := len(orderEntry.Escrows)
+			orderEntry.Unlock()
+			if !ok {
+				t.Fatal("verified fill is not canonical")
+			}
+			if escrows != 2 {
+				t.Errorf("order has %d escrows, want 2", escrows)
+			}
+			for _, check := range []string{"order-hash", "src-balance", "hashlock", "resolver-takers", "safety-deposit", "secret-index", "reservation", "auction-amount"} {
+				if !slices.Contains(v.Checks, check) {
+					t.Errorf("checks %v miss %s", v.Checks, check)
+				}
+			}
+
+			// reported again, it is not verified twice
+			if err := f.m.HandleReceiveEvent(claimant, []byte(event)); err != nil {
+				t.Errorf("duplicate HandleReceiveEvent = %v", err)
+			}
+		})
+	}
+}
+
+func TestHandleTxHashEventMultiFill(t *testing.T) {
+	f := newFillFixture(t, common.Base)
+
+	// the whole amount in one fill takes the last secret
+	orderEntry := f.order(t, MultiFill, nil)
+	if err := f.m.HandleReceiveEvent(nil, []byte(f.deploy(t, orderEntry, f.fill(orderEntry, 2)))); err != nil {
+		t.Fatalf("HandleReceiveEvent = %v", err)
+	}
+
+	orderEntry.Lock()
+	defer orderEntry.Unlock()
+	if _, ok := orderEntry.Canonical[2]; !ok {
+		t.Error("fill of secret 2 is not canonical")
+	}
+	if orderEntry.FilledMakingAmount.Cmp(big.NewInt(testMaking)) != 0 {
+		t.Errorf("filled making amount = %s, want %d", orderEntry.FilledMakingAmount, testMaking)
+	}
+}
+
+func TestHandleTxHashEventRejects(t *testing.T) {
+	tests := []struct {
+		name     string
+		dstChain common.ChainID
+		multi    bool
+		// claimant of the fill, the resolver deploying it when nil
+		claimant func(f *fillFixture) *resolver.Resolver
+		order    func(f *fillFixture, orderEntry *OrderEntry)
+		fill     func(f *fillFixture, d *deployment)
+		// before the fill is reported
+		setup func(f *fillFixture, orderEntry *OrderEntry)
+		// the deployments are not on chain when false
+		deployed bool
+		// the dst tx hash is malformed
+		malformed bool
+		retry     bool
+		want      string
+	}{
+		{
+			name:      "malformed dst tx",
+			malformed: true,
+			want:      "dst tx: invalid EVM transaction hash",
+		},
+		{
+			name:  "transactions not indexed",
+			retry: true,
+			want:  "fetching src escrow",
+		},
+		{
+			name:     "order hash",
+			deployed: true,
+			fill:     func(_ *fillFixture, d *deployment) { d.orderHash = ethcommon.BytesToHash(randomBytes(32)) },
+			want:     "src escrow is for order",
+		},
+		{
+			name:     "src escrow code",
+			deployed: true,
+			order:    func(f *fillFixture, o *OrderEntry) { o.Quote.Quote.SrcEscrowFactory = f.factory.Hex() },
+			want:     "src escrow: no code deployed at escrow",
+		},
+		{
+			name:     "src balance",
+			deployed: true,
+			setup: func(f *fillFixture, o *OrderEntry) {
+				f.setBalance(o.Order.LimitOrder.MakerAsset, big.NewInt(testMaking/2))
+			},
+			want: "src escrow holds",
+		},
+		{
+			name:     "hashlock",
+			deployed: true,
+			fill:     func(_ *fillFixture, d *deployment) { d.dstHashlock = ethcommon.BytesToHash(randomBytes(32)) },
+			want:     "hashlock mismatch",
+		},
+		{
+			name:     "hashlock, sui dst",
+			dstChain: common.Sui,
+			deployed: true,
+			fill:     func(_ *fillFixture, d *deployment) { d.dstHashlock = ethcommon.BytesToHash(randomBytes(32)) },
+			want:     "hashlock mismatch",
+		},
+		{
+			name:     "src taker of another resolver",
+			deployed: true,
+			claimant: func(f *fillFixture) *resolver.Resolver {
+				return &resolver.Resolver{ID: "resolver-2", EVMAddress: randomAddress().Hex()}
+			},
+			want: "src escrow taker",
+		},
+		{
+			name:     "dst taker of another resolver, sui dst",
+			dstChain: common.Sui,
+			deployed: true,
+			fill:     func(_ *fillFixture, d *deployment) { d.dstTaker = hexutil.Encode(randomBytes(32)) },
+			want:     "dst escrow taker",
+		},
+		{
+			name:     "exclusive resolver",
+			deployed: true,
+			order: func(_ *fillFixture, o *OrderEntry) {
+				exclusive := randomAddress().Hex()
+				preset := o.Quote.Quote.Presets[common.PresetFast]
+				preset.ExclusiveResolver = &exclusive
+				o.Quote.Quote.Presets[common.PresetFast] = preset
+			},
+			want: "is not the exclusive resolver",
+		},
+		{
+			name:     "dst receiver",
+			deployed: true,
+			order:    func(_ *fillFixture, o *OrderEntry) { o.DstReceiver = randomAddress().Hex() },
+			want:     "not the receiver",
+		},
+		{
+			name:     "safety deposit",
+			deployed: true,
+			order:    func(_ *fillFixture, o *OrderEntry) { o.Quote.Quote.DstSafetyDeposit = "1000000" },
+			want:     "dst escrow safety deposit",
+		},
+		{
+			name:     "secret index",
+			multi:    true,
+			deployed: true,
+			fill: func(_ *fillFixture, d *deployment) {
+				d.srcHashlock = ethcommon.BytesToHash(randomBytes(32))
+				d.dstHashlock = d.srcHashlock
+			},
+			want: "is not one of the order's secret hashes",
+		},
+		{
+			name:     "reservation",
+			deployed: true,
+			setup: func(f *fillFixture, o *OrderEntry) {
+				if _, err := f.m.reserveSegment(o.OrderHash.Hex(), 0, "resolver-2"); err != nil {
+					panic(err)
+				}
+			},
+			retry: true,
+			want:  "is reserved by resolver resolver-2",
+		},
+		{
+			name:     "auction amount",
+			deployed: true,
+			fill:     func(_ *fillFixture, d *deployment) { d.dstAmount = big.NewInt(testTaking / 2) },
+			want:     "dst amount off the auction amount",
+		},
+		{
+			name:     "fill portion",
+			multi:    true,
+			deployed: true,
+			// the whole amount must use the last secret, not the first
+			want: "must use secret",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dstChain := tt.dstChain
+			if dstChain == 0 {
+				dstChain = common.Base
+			}
+			f := newFillFixture(t, dstChain)
+
+			orderType := SingleFill
+			if tt.multi {
+				orderType = MultiFill
+			}
+			orderEntry := f.order(t, orderType, func(o *OrderEntry) {
+				if tt.order != nil {
+					tt.order(f, o)
+				}
+			})
+			if tt.setup != nil {
+				tt.setup(f, orderEntry)
+			}
+
+			d := f.fill(orderEntry, 0)
+			if tt.fill != nil {
+				tt.fill(f, &d)
+			}
+			var event string
+			if tt.deployed {
+				event = f.deploy(t, orderEntry, d)
+			} else {
+				dstTx := ethcommon.BytesToHash(randomBytes(32)).Hex()
+				if tt.malformed {
+					dstTx = "0x1234"
+				}
+				event = fmt.Sprintf("%s %s %s %s", TXHASH_EVENT, orderEntry.OrderHash.Hex(), ethcommon.BytesToHash(randomBytes(32)).Hex(), dstTx)
+			}
+
+			claimant := &resolver.Resolver{ID: "resolver-1", EVMAddress: f.taker.Hex(), SuiAddress: f.suiTaker}
+			if tt.claimant != nil {
+				claimant = tt.claimant(f)
+			}
+			err := f.m.HandleReceiveEvent(claimant, []byte(event))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("HandleReceiveEvent = %v, want an error containing %q", err, tt.want)
+			}
+			if retry := errors.Is(err, ErrVerifyRetry); retry != tt.retry {
+				t.Errorf("retry = %v, want %v: %v", retry, tt.retry, err)
+			}
+
+			orderEntry.Lock()
+			canonical, escrows := len(orderEntry.Canonical), len(orderEntry.Escrows)
+			orderEntry.Unlock()
+			if canonical != 0 || escrows != 0 {
+				t.Errorf("rejected fill recorded: %d canonical fills, %d escrows", canonical, escrows)
+			}
+		})
+	}
+}
+
+// partsOrder returns a multiple fill order of 100 split into 4 parts, with
+// filled already verified and minFill the maker's minimum fill, if not empty.
+func partsOrder(filled int64, minFill string) *OrderEntry {
+	return &OrderEntry{
+		OrderType: MultiFill,
+		Order: &common.Order{
+			SrcChainID:    common.EthereumMainnet,
+			LimitOrder:    common.LimitOrder{MakingAmount: "100"},
+			SecretHashes:  make([]string, 5),
+			MinFillAmount: minFill,
+		},
+		FilledMakingAmount: big.NewInt(filled),
+	}
+}
+
+func TestClaimFillPortion(t *testing.T) {
+	tests := []struct {
+		name       string
+		filled     int64
+		minFill    string
+		hashIdx    int
+		fill       int64
+		wantErr    bool
+		wantFilled int64
+	}{
+		{name: "first part", fill: 25, hashIdx: 0, wantFilled: 25},
+		{name: "into the second part", fill: 30, hashIdx: 1, wantFilled: 30},
+		{name: "secret of another part", fill: 25, hashIdx: 1, wantErr: true},
+		{name: "reused index", filled: 25, fill: 10, hashIdx: 0, wantErr: true},
+		{name: "next index", filled: 25, fill: 10, hashIdx: 1, wantFilled: 35},
+		{name: "overfill", filled: 90, fill: 20, hashIdx: 3, wantErr: true},
+		{name: "full fill with the last index", fill: 100, hashIdx: 4, wantFilled: 100},
+		{name: "full fill with the last part's index", fill: 100, hashIdx: 3, wantFilled: 100},
+		{name: "completing fill with the last index", filled: 80, fill: 20, hashIdx: 4, wantFilled: 100},
+		{name: "last index short of completing", filled: 80, fill: 10, hashIdx: 4, wantErr: true},
+		{name: "below the minimum", minFill: "20", fill: 10, hashIdx: 0, wantErr: true},
+		{name: "completing below the minimum", filled: 95, minFill: "20", fill: 5, hashIdx: 4, wantFilled: 100},
+		{name: "empty fill", filled: 25, fill: 0, hashIdx: 1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orderEntry := partsOrder(tt.filled, tt.minFill)
+			err := claimFillPortion(orderEntry, tt.hashIdx, big.NewInt(tt.fill))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("claimed the fill")
+				}
+				if orderEntry.FilledMakingAmount.Int64() != tt.filled {
+					t.Fatalf("rejected fill changed the filled amount to %s", orderEntry.FilledMakingAmount)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := orderEntry.FilledMakingAmount.Int64(); got != tt.wantFilled {
+				t.Fatalf("filled %d, want %d", got, tt.wantFilled)
+			}
+		})
+	}
+
+	t.Run("single fill", func(t *testing.T) {
+		orderEntry := partsOrder(0, "")
+		orderEntry.OrderType = SingleFill
+		if err := claimFillPortion(orderEntry, 0, big.NewInt(1000)); err != nil || orderEntry.FilledMakingAmount.Sign() != 0 {
+			t.Fatalf("got %v, filled %s", err, orderEntry.FilledMakingAmount)
+		}
+	})
+}
+
+func randomAddress() ethcommon.Address {
+	return ethcommon.BytesToAddress(randomBytes(20))
+}
+
+func randomBytes(n int) []byte {
+	b := make([]byte, n)
+	rand.Read(b)
+	return b
+}
@@ -0,0 +1,75 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"relayer/internal/alert"
+	"relayer/internal/chain"
+	"time"
+)
+
+// ErrVerifyRetry is wrapped by the errors of verifications that failed on
+// something other than the fill, e.g. transactions not indexed yet: the
+// resolver should send the TXHASH again.
+var ErrVerifyRetry = errors.New("fill not verifiable yet, resend it")
+
+// verifyVerdict is what a failed verification calls for, from the class of
+// the chain error it failed on.
+type verifyVerdict string
+
+const (
+	// the transactions are not indexed yet, or the segment is reserved by
+	// another resolver: the resolver retries
+	verdictRetry verifyVerdict = "retry"
+	// an endpoint is failing: the operator is alerted and the resolver retries
+	verdictAlert verifyVerdict = "alert"
+	// the fill is invalid: missing events, undecodable escrows, failed checks
+	verdictReject verifyVerdict = "reject"
+)
+
+func classifyVerifyError(err error) verifyVerdict {
+	switch {
+	case errors.Is(err, chain.ErrRPCUnavailable):
+		return verdictAlert
+	case errors.Is(err, chain.ErrTxNotFound), errors.Is(err, errSegmentReserved):
+		return verdictRetry
+	default:
+		return verdictReject
+	}
+}
+
+// alertRPCUnavailable tells the operator that fill verifications fail on an
+// unavailable endpoint, at most every RPCAlertInterval.
+func (m *Manager) alertRPCUnavailable(ctx context.Context, orderEntry *OrderEntry, err error) {
+	logger := m.logFor(ctx)
+	logger.Printf("Verification of order %s failed on an unavailable endpoint: %v", orderEntry.OrderHash.Hex(), err)
+
+	m.rpcAlertMu.Lock()
+	due := time.Since(m.rpcAlerted) >= RPCAlertInterval
+	if due {
+		m.rpcAlerted = time.Now()
+	}
+	m.rpcAlertMu.Unlock()
+	if !due || !m.alerts.Enabled() {
+		return
+	}
+
+	a := alert.Alert{
+		Key:      "verify-rpc-unavailable",
+		Summary:  fmt.Sprintf("fill verifications fail on an unavailable chain endpoint: %v", err),
+		Severity: alert.Critical,
+		Details: map[string]any{
+			"order":    orderEntry.OrderHash.Hex(),
+			"srcChain": orderEntry.Order.SrcChainID.String(),
+			"dstChain": orderEntry.Quote.QuoteRequest.DstChain,
+			"error":    err.Error(),
+		},
+	}
+
+	sendCtx, cancel := context.WithTimeout(context.Background(), alert.SendTimeout)
+	defer cancel()
+	if err := m.alerts.Send(sendCtx, a); err != nil {
+		logger.Printf("Failed to send endpoint alert for order %s: %v", orderEntry.OrderHash.Hex(), err)
+	}
+}
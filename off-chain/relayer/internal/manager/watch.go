@@ -0,0 +1,141 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"relayer/internal/chain"
+	"relayer/internal/common"
+	"relayer/internal/store"
+	"strings"
+
+	"github.com/block-vision/sui-go-sdk/models"
+)
+
+// moveEscrowModules are the fusion_plus modules whose events close escrows.
+var moveEscrowModules = []string{"src_escrow", "dst_escrow"}
+
+// watchMove subscribes, with SUI_WS_URL set, to the escrow events of the Move
+// packages of the enabled routes, so a withdrawal or cancellation on Sui is
+// reconciled as it happens instead of at the next reconciliation pass. The
+// subscriptions' cursors are kept in the store, if any, to catch up on the
+// events emitted while the relayer was down.
+func (m *Manager) watchMove() {
+	if m.suiWS == "" {
+		return
+	}
+
+	packages := make(map[string]struct{})
+	for _, r := range m.routes.Routes() {
+		if !r.Enabled {
+			continue
+		}
+		for _, side := range []struct{ chain, factory string }{{r.SrcChain, r.SrcEscrowFactory}, {r.DstChain, r.DstEscrowFactory}} {
+			if id, err := common.ParseChainID(side.chain); err == nil && id.IsMove() && side.factory != "" {
+				packages[strings.ToLower(side.factory)] = struct{}{}
+			}
+		}
+	}
+
+	var cursors chain.CursorStore
+	if m.store != nil {
+		cursors = storeCursors{m.store}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-m.done
+		cancel()
+	}()
+
+	for pkg := range packages {
+		for _, module := range moveEscrowModules {
+			name := "sui:" + pkg + "::" + module
+			filter := map[string]any{"MoveEventModule": map[string]any{"package": pkg, "module": module}}
+			sub := chain.NewSuiSubscription(m.suiWS, name, filter, m.suiClient, cursors)
+			sub.OnError = func(err error) {
+				m.logger.Printf("Sui subscription %v", err)
+			}
+			go sub.Run(ctx, m.onMoveEvent)
+		}
+	}
+	m.logger.Printf("Watching the escrow events of %d Move packages on %s", len(packages), m.suiWS)
+}
+
+// onMoveEvent reconciles the active order owning the escrow a Move event
+// withdrew from or cancelled.
+func (m *Manager) onMoveEvent(ev models.SuiEventResponse) {
+	var field string
+	switch {
+	case strings.HasSuffix(ev.Type, "::EscrowWithdrawal"), strings.HasSuffix(ev.Type, "::EscrowCancelled"):
+		field = "escrow_id"
+	case strings.HasSuffix(ev.Type, "::DstEscrowWithdrawnEvent"), strings.HasSuffix(ev.Type, "::DstEscrowCancelledEvent"):
+		field = "id"
+	default:
+		return
+	}
+	escrow, ok := ev.ParsedJson[field].(string)
+	if !ok {
+		return
+	}
+
+	orderEntry := m.orderByEscrow(escrow)
+	if orderEntry == nil {
+		return
+	}
+	m.logger.Printf("Sui event %s of tx %s closed escrow %s of order %s", ev.Type, ev.Id.TxDigest, escrow, orderEntry.OrderHash.Hex())
+	m.reconcileOrder(orderEntry)
+}
+
+// orderByEscrow returns the active order with a verified fill deploying
+// escrow, nil if none has.
+func (m *Manager) orderByEscrow(escrow string) *OrderEntry {
+	m.activeMu.Lock()
+	hashes := make([]string, 0, len(m.active))
+	for hash := range m.active {
+		hashes = append(hashes, hash)
+	}
+	m.activeMu.Unlock()
+
+	for _, hash := range hashes {
+		orderEntry, err := m.peekOrder(hash)
+		if err != nil {
+			continue
+		}
+		orderEntry.Lock()
+		found := false
+		for _, v := range orderEntry.Canonical {
+			if strings.EqualFold(v.SrcEscrow, escrow) || strings.EqualFold(v.DstEscrow, escrow) {
+				found = true
+				break
+			}
+		}
+		orderEntry.Unlock()
+		if found {
+			return orderEntry
+		}
+	}
+	return nil
+}
+
+// storeCursors keeps the cursors of Sui subscriptions in the store.
+type storeCursors struct {
+	db *store.Store
+}
+
+func (c storeCursors) LoadCursor(ctx context.Context, name string) (*models.EventId, error) {
+	txDigest, eventSeq, err := c.db.EventCursor(ctx, name)
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &models.EventId{TxDigest: txDigest, EventSeq: eventSeq}, nil
+}
+
+func (c storeCursors) SaveCursor(ctx context.Context, name string, cursor models.EventId) error {
+	ctx, cancel := context.WithTimeout(ctx, StoreTimeout)
+	defer cancel()
+
+	return c.db.PutEventCursor(ctx, name, cursor.TxDigest, cursor.EventSeq)
+}
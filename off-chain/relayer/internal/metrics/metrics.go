@@ -0,0 +1,109 @@
+// Package metrics publishes relayer counters through expvar, served on the
+// admin API at /admin/v1.0/metrics.
+package metrics
+
+import (
+	"expvar"
+	"fmt"
+	"time"
+)
+
+var (
+	// HTTPRequests counts API requests by "METHOD route status"
+	HTTPRequests = expvar.NewMap("http_requests")
+	// HTTPLatencyMs sums API request latency in milliseconds by "METHOD route"
+	HTTPLatencyMs = expvar.NewMap("http_latency_ms")
+
+	// SubmitQueueDepth is the number of submitted orders waiting for a worker
+	SubmitQueueDepth = expvar.NewInt("submit_queue_depth")
+	// SubmitQueueShed counts submissions rejected with 429 because the queue was full
+	SubmitQueueShed = expvar.NewInt("submit_queue_shed")
+
+	// ChainHead is the latest block, or Sui clock in milliseconds, seen per chain
+	ChainHead = expvar.NewMap("chain_head")
+	// ChainHeadLagMs is how far each chain's head trails the wall clock
+	ChainHeadLagMs = expvar.NewMap("chain_head_lag_ms")
+
+	// UpstreamRequests counts calls to upstream APIs by "METHOD host/path status",
+	// status "error" when no response was received
+	UpstreamRequests = expvar.NewMap("upstream_requests")
+	// UpstreamLatencyMs sums upstream call latency in milliseconds by "METHOD host/path"
+	UpstreamLatencyMs = expvar.NewMap("upstream_latency_ms")
+	// UpstreamKeyRequests counts 1inch API calls by "key status", keys
+	// labelled by position and last characters, status "error" when no
+	// response was received
+	UpstreamKeyRequests = expvar.NewMap("upstream_key_requests")
+
+	// TTLExpired counts in-memory entries dropped by their ttl, by kind
+	// (quote, order, verification, multihop, intent, archive)
+	TTLExpired = expvar.NewMap("ttl_expired")
+	// TTLEvicted counts entries dropped before their ttl, replaced by a newer
+	// entry under the same key or drained on shutdown, by kind
+	TTLEvicted = expvar.NewMap("ttl_evicted")
+	// LRUEvicted counts quotes and orders evicted as least recently used to
+	// hold maxQuotes and maxOrders, by kind
+	LRUEvicted = expvar.NewMap("lru_evicted")
+	// QuotesSaturated counts quote requests rejected with 429 because the
+	// quote map was full of quotes still valid
+	QuotesSaturated = expvar.NewInt("quotes_saturated")
+
+	// VerifyFailures counts failed fill verifications by verdict: retry
+	// (transactions not indexed yet), alert (endpoint unavailable) or reject
+	VerifyFailures = expvar.NewMap("verify_failures")
+
+	// WSBudgetExceeded counts WS events rejected for exceeding the client's
+	// chain call budget
+	WSBudgetExceeded = expvar.NewInt("ws_budget_exceeded")
+	// WSBans counts WS clients banned for sending invalid events
+	WSBans = expvar.NewInt("ws_bans")
+
+	// PrewarmSkipped counts pending fill transactions not prewarmed because
+	// MaxPrewarmed were tracked already
+	PrewarmSkipped = expvar.NewInt("prewarm_skipped")
+
+	// BusDropped counts internal events dropped for a subscriber that fell
+	// behind, by subscriber
+	BusDropped = expvar.NewMap("bus_dropped")
+	// BusQueued is the number of internal events waiting for a queued
+	// subscriber, by subscriber
+	BusQueued = expvar.NewMap("bus_queued")
+	// OrderEvents counts the order lifecycle events published to the bus,
+	// by kind
+	OrderEvents = expvar.NewMap("order_events")
+	// Notifications counts notifications sent by "channel ok|error"
+	Notifications = expvar.NewMap("notifications")
+)
+
+// ObserveHTTP records one served API request. route is the registered route
+// pattern rather than the raw path, so order hashes do not create new keys.
+func ObserveHTTP(method, route string, status int, latency time.Duration) {
+	if route == "" {
+		route = "unmatched"
+	}
+
+	HTTPRequests.Add(fmt.Sprintf("%s %s %d", method, route, status), 1)
+	HTTPLatencyMs.AddFloat(method+" "+route, float64(latency.Microseconds())/1000)
+}
+
+// ObserveUpstream records one call to an upstream API. status is 0 when the
+// call failed without a response.
+func ObserveUpstream(method, endpoint string, status int, latency time.Duration) {
+	code := "error"
+	if status != 0 {
+		code = fmt.Sprint(status)
+	}
+
+	UpstreamRequests.Add(fmt.Sprintf("%s %s %s", method, endpoint, code), 1)
+	UpstreamLatencyMs.AddFloat(method+" "+endpoint, float64(latency.Microseconds())/1000)
+}
+
+// ObserveHead records the latest head polled from chain and its lag.
+func ObserveHead(chain string, head uint64, lag time.Duration) {
+	number := new(expvar.Int)
+	number.Set(int64(head))
+	ChainHead.Set(chain, number)
+
+	lagMs := new(expvar.Int)
+	lagMs.Set(lag.Milliseconds())
+	ChainHeadLagMs.Set(chain, lagMs)
+}
@@ -0,0 +1,134 @@
+// Package network defines the deployments the relayer can run against. A
+// profile fixes the served chains, the Sui network, the default corridors with
+// their escrow factories and the finality delays, so pointing the relayer at
+// public testnets needs no code edits.
+package network
+
+import (
+	"fmt"
+	"os"
+	"relayer/internal/common"
+	"relayer/internal/config"
+	"relayer/internal/routing"
+	"sort"
+	"time"
+)
+
+// Profile is one deployment target.
+type Profile struct {
+	Name       string
+	Chains     []common.ChainID
+	SuiNetwork string
+	// SuiRPC is used when SUI_RPC_URL is not set
+	SuiRPC string
+	// Routes are the corridors served when ROUTES_FILE is not set
+	Routes []routing.Route
+	// FinalityDelays are overridden per chain by CONFIG_FILE
+	FinalityDelays map[string]config.Duration
+	// PinFactories requires every enabled route to name both escrow
+	// factories, the upstream ones not being the relayer's deployment
+	PinFactories bool
+}
+
+// FactoryEnv names the variable pinning the escrow factory of a chain, by
+// decimal id, in the default routes of the testnet and devnet profiles: the
+// escrow package on Sui.
+func FactoryEnv(chain string) string {
+	return "ESCROW_FACTORY_" + chain
+}
+
+// testnetRoute pairs an EVM testnet with Sui testnet in one direction. Its
+// escrow factories are pinned by Select from FactoryEnv.
+func testnetRoute(src, dst common.ChainID) routing.Route {
+	return routing.Route{SrcChain: src.String(), DstChain: dst.String(), Enabled: true}
+}
+
+var profiles = map[string]Profile{
+	"mainnet": {
+		Name:       "mainnet",
+		Chains:     []common.ChainID{common.EthereumMainnet, common.ArbitrumOne, common.Polygon, common.BSC, common.Optimism, common.Base, common.Sui},
+		SuiNetwork: "mainnet",
+		SuiRPC:     "https://fullnode.mainnet.sui.io:443",
+		Routes:     routing.DefaultRoutes,
+	},
+	"testnet": {
+		Name:       "testnet",
+		Chains:     []common.ChainID{common.Sepolia, common.BaseSepolia, common.ArbitrumSepolia, common.Sui},
+		SuiNetwork: "testnet",
+		SuiRPC:     "https://fullnode.testnet.sui.io:443",
+		Routes: []routing.Route{
+			testnetRoute(common.Sepolia, common.Sui),
+			testnetRoute(common.Sui, common.Sepolia),
+			testnetRoute(common.BaseSepolia, common.Sui),
+			testnetRoute(common.Sui, common.BaseSepolia),
+			testnetRoute(common.ArbitrumSepolia, common.Sui),
+			testnetRoute(common.Sui, common.ArbitrumSepolia),
+		},
+		FinalityDelays: map[string]config.Duration{
+			common.Sepolia.String():         config.Duration(24 * time.Second),
+			common.BaseSepolia.String():     config.Duration(4 * time.Second),
+			common.ArbitrumSepolia.String(): config.Duration(2 * time.Second),
+			common.Sui.String():             config.Duration(2 * time.Second),
+		},
+		PinFactories: true,
+	},
+	"devnet": {
+		Name:       "devnet",
+		Chains:     []common.ChainID{common.Sepolia, common.Sui},
+		SuiNetwork: "devnet",
+		SuiRPC:     "https://fullnode.devnet.sui.io:443",
+		Routes: []routing.Route{
+			testnetRoute(common.Sepolia, common.Sui),
+			testnetRoute(common.Sui, common.Sepolia),
+		},
+		FinalityDelays: map[string]config.Duration{
+			common.Sepolia.String(): config.Duration(24 * time.Second),
+			common.Sui.String():     config.Duration(2 * time.Second),
+		},
+		PinFactories: true,
+	},
+}
+
+var current = profiles["mainnet"]
+
+// Select makes the named profile current and restricts the supported chains
+// to it. The escrow factories of profiles pinning them are read from
+// FactoryEnv; routes left without one are refused when the routes are
+// loaded, unless ROUTES_FILE replaces them. It must run before the manager
+// is created.
+func Select(name string) error {
+	p, ok := profiles[name]
+	if !ok {
+		return fmt.Errorf("unknown network profile %q, expected one of %v", name, Names())
+	}
+
+	if p.PinFactories {
+		routes := make([]routing.Route, len(p.Routes))
+		for i, r := range p.Routes {
+			r.SrcEscrowFactory = os.Getenv(FactoryEnv(r.SrcChain))
+			r.DstEscrowFactory = os.Getenv(FactoryEnv(r.DstChain))
+			routes[i] = r
+		}
+		p.Routes = routes
+	}
+
+	current = p
+	common.SetSupportedChains(p.Chains...)
+	common.SuiNetwork = p.SuiNetwork
+	return nil
+}
+
+// Current returns the selected profile, mainnet unless Select was called.
+func Current() Profile {
+	return current
+}
+
+// Names lists the available profiles.
+func Names() []string {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
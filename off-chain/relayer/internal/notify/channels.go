@@ -0,0 +1,136 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/smtp"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// TelegramAPIURL is the Telegram Bot API the bot token is appended to.
+const TelegramAPIURL = "https://api.telegram.org/bot"
+
+// Channel delivers notifications to operators.
+type Channel interface {
+	Name() string
+	Send(ctx context.Context, n Notification) error
+}
+
+// FromEnv returns the channels configured in the environment: Slack with
+// SLACK_WEBHOOK_URL, Telegram with TELEGRAM_BOT_TOKEN and TELEGRAM_CHAT_ID,
+// email with SMTP_ADDR, NOTIFY_EMAIL_FROM and NOTIFY_EMAIL_TO, a comma
+// separated list, authenticating with SMTP_USERNAME and SMTP_PASSWORD if set.
+func FromEnv() []Channel {
+	client := &http.Client{Timeout: SendTimeout}
+
+	var channels []Channel
+	if webhook := os.Getenv("SLACK_WEBHOOK_URL"); webhook != "" {
+		channels = append(channels, &Slack{WebhookURL: webhook, client: client})
+	}
+	if token, chat := os.Getenv("TELEGRAM_BOT_TOKEN"), os.Getenv("TELEGRAM_CHAT_ID"); token != "" && chat != "" {
+		channels = append(channels, &Telegram{Token: token, ChatID: chat, client: client})
+	}
+	if addr, to := os.Getenv("SMTP_ADDR"), os.Getenv("NOTIFY_EMAIL_TO"); addr != "" && to != "" {
+		channels = append(channels, &Email{
+			Addr:     addr,
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     os.Getenv("NOTIFY_EMAIL_FROM"),
+			To:       strings.Split(to, ","),
+		})
+	}
+	return channels
+}
+
+// Slack posts to an incoming webhook.
+type Slack struct {
+	WebhookURL string
+	client     *http.Client
+}
+
+func (s *Slack) Name() string { return "slack" }
+
+func (s *Slack) Send(ctx context.Context, n Notification) error {
+	return postJSON(ctx, s.client, s.WebhookURL, map[string]string{"text": n.Text()})
+}
+
+// Telegram sends messages through a bot to a chat.
+type Telegram struct {
+	Token  string
+	ChatID string
+	client *http.Client
+}
+
+func (t *Telegram) Name() string { return "telegram" }
+
+func (t *Telegram) Send(ctx context.Context, n Notification) error {
+	return postJSON(ctx, t.client, TelegramAPIURL+t.Token+"/sendMessage", map[string]string{
+		"chat_id": t.ChatID,
+		"text":    n.Text(),
+	})
+}
+
+// Email sends plain text mail through an SMTP server.
+type Email struct {
+	Addr     string
+	Username string
+	Password string
+	From     string
+	To       []string
+}
+
+func (e *Email) Name() string { return "email" }
+
+// Send ignores ctx, net/smtp has no context support.
+func (e *Email) Send(_ context.Context, n Notification) error {
+	var auth smtp.Auth
+	if e.Username != "" {
+		host, _, err := net.SplitHostPort(e.Addr)
+		if err != nil {
+			return err
+		}
+		auth = smtp.PlainAuth("", e.Username, e.Password, host)
+	}
+
+	msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: [fission] %s\r\n\r\n%s\r\n",
+		e.From, strings.Join(e.To, ", "), n.Summary, n.Text())
+	return smtp.SendMail(e.Addr, auth, e.From, e.To, []byte(msg))
+}
+
+func postJSON(ctx context.Context, client *http.Client, endpoint string, body any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		// the url may hold a bot token
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			return urlErr.Err
+		}
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,232 @@
+// Package notify sends operators notifications on Slack, Telegram and email
+// when the events on the relayer's bus match the alert rules of the config:
+// a spike of failed verifications, a storm of resolver disconnects, a chain
+// RPC outage or a large order. It only reads the bus, so no subsystem calls
+// it or logs through it.
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"relayer/internal/bus"
+	"relayer/internal/common"
+	"relayer/internal/config"
+	"relayer/internal/metrics"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SendTimeout bounds the delivery of one notification to all channels.
+const SendTimeout = time.Second * 10
+
+// EventBuffer is how many bus events may wait for the notifier.
+const EventBuffer = 256
+
+// Rule names the alert rule a notification was sent for.
+type Rule string
+
+const (
+	VerifyFailureSpike  Rule = "verify-failure-spike"
+	ResolverDisconnects Rule = "resolver-disconnect-storm"
+	RPCOutage           Rule = "rpc-outage"
+	RPCRecovered        Rule = "rpc-recovered"
+	LargeOrder          Rule = "large-order"
+)
+
+// Notification is one message to operators.
+type Notification struct {
+	Rule    Rule
+	Summary string
+	Details map[string]any
+	Time    time.Time
+}
+
+// Text renders the notification as a plain text message.
+func (n Notification) Text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[%s] %s", n.Rule, n.Summary)
+
+	keys := make([]string, 0, len(n.Details))
+	for k := range n.Details {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(&b, "\n%s: %v", k, n.Details[k])
+	}
+	return b.String()
+}
+
+// Notifier matches bus events against the rules and sends the resulting
+// notifications to every channel.
+type Notifier struct {
+	channels []Channel
+	rules    func() config.NotifyRules
+	logger   *log.Logger
+
+	// recent events of the windowed rules, and when each rule and subject
+	// was last notified; only touched by Run
+	recent   map[Rule][]time.Time
+	notified map[string]time.Time
+	// chains an outage was notified for
+	down map[string]bool
+}
+
+// New returns a notifier sending to channels under the rules returned by
+// rules, read at every event so config reloads apply.
+func New(logger *log.Logger, channels []Channel, rules func() config.NotifyRules) *Notifier {
+	return &Notifier{
+		channels: channels,
+		rules:    rules,
+		logger:   logger,
+		recent:   make(map[Rule][]time.Time),
+		notified: make(map[string]time.Time),
+		down:     make(map[string]bool),
+	}
+}
+
+// Enabled reports whether notifications go anywhere.
+func (n *Notifier) Enabled() bool {
+	return len(n.channels) > 0
+}
+
+// Run handles events until done is closed.
+func (n *Notifier) Run(events <-chan bus.Event, done <-chan struct{}) {
+	for {
+		select {
+		case <-done:
+			return
+		case e := <-events:
+			if note, ok := n.match(e, n.rules()); ok {
+				go n.send(note)
+			}
+		}
+	}
+}
+
+// match returns the notification e triggers, if any.
+func (n *Notifier) match(e bus.Event, rules config.NotifyRules) (Notification, bool) {
+	switch e.Kind {
+	case bus.VerificationFailed:
+		count, ok := n.spike(VerifyFailureSpike, e.Time, rules.VerifyFailures, rules.VerifyFailureWindow)
+		if !ok {
+			return Notification{}, false
+		}
+		return n.once(rules, "", Notification{
+			Rule:    VerifyFailureSpike,
+			Summary: fmt.Sprintf("%d fill verifications failed within %s", count, time.Duration(rules.VerifyFailureWindow)),
+			Details: map[string]any{"lastOrder": e.Subject, "lastError": e.Details["error"]},
+			Time:    e.Time,
+		})
+
+	case bus.ResolverDisconnected:
+		count, ok := n.spike(ResolverDisconnects, e.Time, rules.Disconnects, rules.DisconnectWindow)
+		if !ok {
+			return Notification{}, false
+		}
+		return n.once(rules, "", Notification{
+			Rule:    ResolverDisconnects,
+			Summary: fmt.Sprintf("%d resolver connections ended within %s", count, time.Duration(rules.DisconnectWindow)),
+			Details: map[string]any{"lastResolver": e.Subject},
+			Time:    e.Time,
+		})
+
+	case bus.ChainUnavailable, bus.ChainBehind:
+		if !rules.RPCOutage {
+			return Notification{}, false
+		}
+		n.down[e.Subject] = true
+		summary := fmt.Sprintf("chain %s RPC is unavailable", e.Subject)
+		if e.Kind == bus.ChainBehind {
+			summary = fmt.Sprintf("chain %s RPC head is behind", e.Subject)
+		}
+		return n.once(rules, e.Subject, Notification{Rule: RPCOutage, Summary: summary, Details: e.Details, Time: e.Time})
+
+	case bus.ChainRecovered:
+		if !n.down[e.Subject] {
+			return Notification{}, false
+		}
+		delete(n.down, e.Subject)
+		delete(n.notified, string(RPCOutage)+":"+e.Subject)
+		return Notification{
+			Rule:    RPCRecovered,
+			Summary: fmt.Sprintf("chain %s RPC recovered", e.Subject),
+			Details: e.Details,
+			Time:    e.Time,
+		}, true
+
+	case bus.OrderSubmitted:
+		asset, _ := e.Details["makerAsset"].(string)
+		threshold, ok := rules.LargeOrders[strings.ToLower(asset)]
+		if !ok {
+			return Notification{}, false
+		}
+		amount, _ := e.Details["makingAmount"].(string)
+		making, err := common.ParseAmount(amount)
+		floor, _ := new(big.Int).SetString(threshold, 10)
+		if err != nil || making.Cmp(floor) < 0 {
+			return Notification{}, false
+		}
+		// every large order is notified
+		return Notification{
+			Rule:    LargeOrder,
+			Summary: fmt.Sprintf("order %s makes %s of %s", e.Subject, amount, asset),
+			Details: e.Details,
+			Time:    e.Time,
+		}, true
+	}
+
+	return Notification{}, false
+}
+
+// spike records an event of rule at now and reports whether threshold of
+// them happened within window, along with their count.
+func (n *Notifier) spike(rule Rule, now time.Time, threshold int, window config.Duration) (int, bool) {
+	if threshold == 0 {
+		return 0, false
+	}
+
+	recent := n.recent[rule][:0]
+	for _, at := range n.recent[rule] {
+		if now.Sub(at) < time.Duration(window) {
+			recent = append(recent, at)
+		}
+	}
+	recent = append(recent, now)
+	n.recent[rule] = recent
+
+	return len(recent), len(recent) >= threshold
+}
+
+// once passes note unless its rule was notified for subject within the
+// cooldown.
+func (n *Notifier) once(rules config.NotifyRules, subject string, note Notification) (Notification, bool) {
+	key := string(note.Rule) + ":" + subject
+	if last, ok := n.notified[key]; ok && note.Time.Sub(last) < time.Duration(rules.Cooldown) {
+		return Notification{}, false
+	}
+	n.notified[key] = note.Time
+	return note, true
+}
+
+func (n *Notifier) send(note Notification) {
+	ctx, cancel := context.WithTimeout(context.Background(), SendTimeout)
+	defer cancel()
+
+	var errs []error
+	for _, channel := range n.channels {
+		if err := channel.Send(ctx, note); err != nil {
+			metrics.Notifications.Add(channel.Name()+" error", 1)
+			errs = append(errs, fmt.Errorf("%s: %w", channel.Name(), err))
+			continue
+		}
+		metrics.Notifications.Add(channel.Name()+" ok", 1)
+	}
+	if err := errors.Join(errs...); err != nil {
+		n.logger.Printf("Failed to send %s notification: %v", note.Rule, err)
+	}
+}
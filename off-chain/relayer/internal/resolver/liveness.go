@@ -0,0 +1,169 @@
+package resolver
+
+import (
+	"relayer/internal/common"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ReferenceRTT is the ping round trip at which a resolver's responsiveness
+// counts half.
+const ReferenceRTT = time.Millisecond * 250
+
+// rttWeight is the weight of the latest ping in the moving average RTT.
+const rttWeight = 0.2
+
+// Liveness accounts the connection uptime and ping round trips of the
+// authenticated resolvers, from which each gets a score.
+type Liveness struct {
+	mu        sync.Mutex
+	resolvers map[string]*liveness
+}
+
+type liveness struct {
+	// open connections, and since when one was open without interruption
+	conns          int
+	connectedSince time.Time
+	// first connection, and the connected time of past connections
+	firstSeen time.Time
+	uptime    time.Duration
+
+	rtt      time.Duration
+	pings    int
+	failures int
+	lastPing time.Time
+}
+
+func NewLiveness() *Liveness {
+	return &Liveness{resolvers: make(map[string]*liveness)}
+}
+
+func (l *Liveness) get(id string) *liveness {
+	r, ok := l.resolvers[id]
+	if !ok {
+		r = &liveness{}
+		l.resolvers[id] = r
+	}
+	return r
+}
+
+// Connected records a connection of resolver id opening at now.
+func (l *Liveness) Connected(id string, now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	r := l.get(id)
+	if r.firstSeen.IsZero() {
+		r.firstSeen = now
+	}
+	if r.conns == 0 {
+		r.connectedSince = now
+	}
+	r.conns++
+}
+
+// Disconnected records a connection of resolver id closing at now.
+func (l *Liveness) Disconnected(id string, now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	r := l.get(id)
+	if r.conns == 0 {
+		return
+	}
+	r.conns--
+	if r.conns == 0 {
+		r.uptime += now.Sub(r.connectedSince)
+	}
+}
+
+// Pinged records a ping of resolver id answered after rtt at now.
+func (l *Liveness) Pinged(id string, rtt time.Duration, now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	r := l.get(id)
+	if r.pings == 0 {
+		r.rtt = rtt
+	} else {
+		r.rtt = time.Duration(rttWeight*float64(rtt) + (1-rttWeight)*float64(r.rtt))
+	}
+	r.pings++
+	r.lastPing = now
+}
+
+// PingFailed records a ping of resolver id left unanswered.
+func (l *Liveness) PingFailed(id string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.get(id).failures++
+}
+
+// Stats returns the liveness of every resolver seen at now, best score
+// first.
+func (l *Liveness) Stats(now time.Time) []common.ResolverStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	stats := make([]common.ResolverStats, 0, len(l.resolvers))
+	for id, r := range l.resolvers {
+		s := common.ResolverStats{
+			ID:           id,
+			Connected:    r.conns > 0,
+			Connections:  r.conns,
+			Uptime:       r.uptimeShare(now),
+			Pings:        r.pings,
+			PingFailures: r.failures,
+			Score:        r.score(now),
+		}
+		if s.Connected {
+			since := r.connectedSince
+			s.ConnectedSince = &since
+		}
+		if r.pings > 0 {
+			s.PingRTT = r.rtt.String()
+			lastPing := r.lastPing
+			s.LastPing = &lastPing
+		}
+		stats = append(stats, s)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Score != stats[j].Score {
+			return stats[i].Score > stats[j].Score
+		}
+		return stats[i].ID < stats[j].ID
+	})
+
+	return stats
+}
+
+// uptimeShare is the share of the time since the resolver first connected it
+// had a connection open.
+func (r *liveness) uptimeShare(now time.Time) float64 {
+	observed := now.Sub(r.firstSeen)
+	if r.firstSeen.IsZero() || observed <= 0 {
+		return 0
+	}
+
+	uptime := r.uptime
+	if r.conns > 0 {
+		uptime += now.Sub(r.connectedSince)
+	}
+	return min(1, float64(uptime)/float64(observed))
+}
+
+// score rates the resolver from 0 to 1: its uptime share, times the share of
+// pings it answered, times its responsiveness, which halves at ReferenceRTT.
+func (r *liveness) score(now time.Time) float64 {
+	answered := 1.0
+	if total := r.pings + r.failures; total > 0 {
+		answered = float64(r.pings) / float64(total)
+	}
+	responsiveness := 1.0
+	if r.pings > 0 {
+		responsiveness = 1 / (1 + float64(r.rtt)/float64(ReferenceRTT))
+	}
+	return r.uptimeShare(now) * answered * responsiveness
+}
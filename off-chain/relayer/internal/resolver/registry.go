@@ -0,0 +1,121 @@
+// Package resolver holds the set of resolvers allowed to fill orders through
+// the relayer, along with the addresses they fill from on each chain.
+package resolver
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"relayer/internal/common"
+)
+
+// Resolver is a registered resolver. EVMAddress and SuiAddress are the taker
+// addresses it creates escrows with.
+type Resolver struct {
+	ID         string `json:"id"`
+	APIKey     string `json:"apiKey"`
+	EVMAddress string `json:"evmAddress"`
+	SuiAddress string `json:"suiAddress"`
+}
+
+// Owns reports whether addr is one of the resolver's taker addresses.
+func (r *Resolver) Owns(addr string) bool {
+	if addr == "" {
+		return false
+	}
+	return (r.EVMAddress != "" && common.SameAddress(addr, r.EVMAddress)) ||
+		(r.SuiAddress != "" && common.SameAddress(addr, r.SuiAddress))
+}
+
+// Registry is an immutable set of resolvers loaded at startup. An empty
+// registry disables authentication so local setups keep working.
+type Registry struct {
+	resolvers []*Resolver
+}
+
+// NewRegistry validates resolvers and builds a registry from them.
+func NewRegistry(resolvers []*Resolver) (*Registry, error) {
+	ids := make(map[string]bool, len(resolvers))
+	keys := make(map[string]bool, len(resolvers))
+
+	for _, r := range resolvers {
+		if r.ID == "" || r.APIKey == "" {
+			return nil, errors.New("resolver id and apiKey are required")
+		}
+		if r.EVMAddress == "" && r.SuiAddress == "" {
+			return nil, fmt.Errorf("resolver %s has no taker address", r.ID)
+		}
+		if ids[r.ID] {
+			return nil, fmt.Errorf("duplicate resolver id %s", r.ID)
+		}
+		if keys[r.APIKey] {
+			return nil, fmt.Errorf("resolver %s reuses another resolver's apiKey", r.ID)
+		}
+		ids[r.ID] = true
+		keys[r.APIKey] = true
+	}
+
+	return &Registry{resolvers: resolvers}, nil
+}
+
+// Load reads the registry from a JSON array of resolvers. An empty path
+// returns an empty registry.
+func Load(path string) (*Registry, error) {
+	if path == "" {
+		return NewRegistry(nil)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var resolvers []*Resolver
+	if err := json.Unmarshal(data, &resolvers); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+
+	return NewRegistry(resolvers)
+}
+
+// Enabled reports whether any resolvers are registered.
+func (r *Registry) Enabled() bool {
+	return len(r.resolvers) > 0
+}
+
+// Authenticate returns the resolver holding apiKey.
+func (r *Registry) Authenticate(apiKey string) (*Resolver, bool) {
+	var found *Resolver
+	for _, res := range r.resolvers {
+		// compare against every key so timing does not reveal the position
+		if subtle.ConstantTimeCompare([]byte(res.APIKey), []byte(apiKey)) == 1 {
+			found = res
+		}
+	}
+
+	return found, found != nil
+}
+
+// ByAddress returns the resolver owning a taker address.
+func (r *Registry) ByAddress(addr string) (*Resolver, bool) {
+	for _, res := range r.resolvers {
+		if res.Owns(addr) {
+			return res, true
+		}
+	}
+
+	return nil, false
+}
+
+// ByID returns the resolver registered as id.
+func (r *Registry) ByID(id string) (*Resolver, bool) {
+	for _, res := range r.resolvers {
+		if res.ID == id {
+			return res, true
+		}
+	}
+
+	return nil, false
+}
@@ -0,0 +1,292 @@
+package routing
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"relayer/internal/common"
+	"relayer/internal/hashlock"
+	"sort"
+	"strings"
+)
+
+var (
+	ErrRouteNotFound  = errors.New("chain pair is not supported")
+	ErrRouteDisabled  = errors.New("chain pair is disabled")
+	ErrTokenNotListed = errors.New("token is not listed")
+)
+
+// Route declares a (srcChain, dstChain) corridor, the escrow factories
+// (EVM factory address or Move package id) serving each side of it and the
+// hashlock algorithm both escrows check secrets with.
+type Route struct {
+	SrcChain         string             `json:"srcChain"`
+	DstChain         string             `json:"dstChain"`
+	Enabled          bool               `json:"enabled"`
+	SrcEscrowFactory string             `json:"srcEscrowFactory"`
+	DstEscrowFactory string             `json:"dstEscrowFactory"`
+	Hashlock         hashlock.Algorithm `json:"hashlock,omitempty"` // keccak256 when empty
+}
+
+// Hub is an intermediate chain that multi-hop quotes may route through,
+// swapping into Token there before continuing to the destination chain.
+type Hub struct {
+	Chain string `json:"chain"`
+	Token string `json:"token"`
+}
+
+type routeKey struct {
+	src string
+	dst string
+}
+
+// Table is the set of corridors the relayer serves. It is read-only after load.
+type Table struct {
+	routes map[routeKey]Route
+	hubs   []Hub
+	// listed tokens by chain, chains without any quote every token
+	tokens map[string][]common.TokenInfo
+}
+
+// DefaultRoutes enables the Ethereum <-> Sui corridor in both directions.
+var DefaultRoutes = []Route{
+	{
+		SrcChain:         "1",
+		DstChain:         "101",
+		Enabled:          true,
+		SrcEscrowFactory: "0xa7bCb4EAc8964306F9e3764f67Db6A7af6DdF99A",
+		DstEscrowFactory: "0x9947401bada3b7918ed6813946e206841e3c8cd890744056e5153c2f3015c7cf",
+	},
+	{
+		SrcChain:         "101",
+		DstChain:         "1",
+		Enabled:          true,
+		SrcEscrowFactory: "0x9947401bada3b7918ed6813946e206841e3c8cd890744056e5153c2f3015c7cf",
+		DstEscrowFactory: "0xa7bCb4EAc8964306F9e3764f67Db6A7af6DdF99A",
+	},
+}
+
+// NewTable builds a table from routes and hubs, rejecting duplicate or
+// same-chain pairs. Chains may be given as decimal or CAIP-2 ids.
+func NewTable(routes []Route, hubs ...Hub) (*Table, error) {
+	t := &Table{routes: make(map[routeKey]Route, len(routes))}
+	for i, h := range hubs {
+		if h.Chain == "" || h.Token == "" {
+			return nil, fmt.Errorf("hub is missing chain or token: %+v", h)
+		}
+		chain, err := common.NormalizeChain(h.Chain)
+		if err != nil {
+			return nil, fmt.Errorf("hub %+v: %w", h, err)
+		}
+		hubs[i].Chain = chain
+	}
+	t.hubs = hubs
+
+	for _, r := range routes {
+		if r.SrcChain == "" || r.DstChain == "" {
+			return nil, fmt.Errorf("route is missing srcChain or dstChain: %+v", r)
+		}
+		var err error
+		if r.SrcChain, err = common.NormalizeChain(r.SrcChain); err != nil {
+			return nil, fmt.Errorf("route %+v: %w", r, err)
+		}
+		if r.DstChain, err = common.NormalizeChain(r.DstChain); err != nil {
+			return nil, fmt.Errorf("route %+v: %w", r, err)
+		}
+		if r.Hashlock, err = hashlock.Parse(string(r.Hashlock)); err != nil {
+			return nil, fmt.Errorf("route %s -> %s: %w", r.SrcChain, r.DstChain, err)
+		}
+		if r.SrcChain == r.DstChain {
+			return nil, fmt.Errorf("route %s -> %s must cross chains", r.SrcChain, r.DstChain)
+		}
+
+		key := routeKey{src: r.SrcChain, dst: r.DstChain}
+		if _, exists := t.routes[key]; exists {
+			return nil, fmt.Errorf("duplicate route %s -> %s", r.SrcChain, r.DstChain)
+		}
+		t.routes[key] = r
+	}
+
+	return t, nil
+}
+
+// listTokens restricts the tokens quoted on each chain of tokens to those
+// listed.
+func (t *Table) listTokens(tokens []common.TokenInfo) error {
+	t.tokens = make(map[string][]common.TokenInfo)
+	for _, tok := range tokens {
+		chain, err := common.NormalizeChain(tok.Chain)
+		if err != nil {
+			return fmt.Errorf("token %+v: %w", tok, err)
+		}
+		if common.IsSuiChain(chain) {
+			if !strings.Contains(tok.Address, "::") {
+				return fmt.Errorf("token %+v: Sui tokens are coin types such as 0x2::sui::SUI", tok)
+			}
+		} else if err := common.ValidateAddress(chain, tok.Address); err != nil {
+			return fmt.Errorf("token %+v: %w", tok, err)
+		}
+		if _, listed := t.Token(chain, tok.Address); listed {
+			return fmt.Errorf("duplicate token %s on chain %s", tok.Address, chain)
+		}
+
+		tok.Chain = chain
+		t.tokens[chain] = append(t.tokens[chain], tok)
+	}
+	return nil
+}
+
+// Load reads the table from path, either a JSON array of routes or an object
+// with "routes", "hubs" and "tokens". An empty path yields defaults, the
+// routes of the selected network profile.
+func Load(path string, defaults []Route) (*Table, error) {
+	if path == "" {
+		return NewTable(defaults)
+	}
+
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading routes file: %w", err)
+	}
+
+	var routes []Route
+	if err := json.Unmarshal(file, &routes); err == nil {
+		return NewTable(routes)
+	}
+
+	var config struct {
+		Routes []Route            `json:"routes"`
+		Hubs   []Hub              `json:"hubs"`
+		Tokens []common.TokenInfo `json:"tokens"`
+	}
+	if err := json.Unmarshal(file, &config); err != nil {
+		return nil, fmt.Errorf("decoding routes file: %w", err)
+	}
+
+	t, err := NewTable(config.Routes, config.Hubs...)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.listTokens(config.Tokens); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Lookup returns the enabled route for a chain pair.
+func (t *Table) Lookup(srcChain, dstChain string) (Route, error) {
+	r, ok := t.routes[routeKey{src: srcChain, dst: dstChain}]
+	if !ok {
+		return Route{}, fmt.Errorf("%w: %s -> %s", ErrRouteNotFound, srcChain, dstChain)
+	}
+	if !r.Enabled {
+		return Route{}, fmt.Errorf("%w: %s -> %s", ErrRouteDisabled, srcChain, dstChain)
+	}
+
+	return r, nil
+}
+
+// Path returns the routes an order from srcChain to dstChain takes: the
+// direct route when there is one, otherwise two legs through the first hub
+// with both legs enabled. The second return value is the hub used, if any.
+func (t *Table) Path(srcChain, dstChain string) ([]Route, *Hub, error) {
+	direct, err := t.Lookup(srcChain, dstChain)
+	if err == nil {
+		return []Route{direct}, nil, nil
+	}
+	if !errors.Is(err, ErrRouteNotFound) {
+		return nil, nil, err
+	}
+
+	for i := range t.hubs {
+		hub := &t.hubs[i]
+		if hub.Chain == srcChain || hub.Chain == dstChain {
+			continue
+		}
+
+		first, err := t.Lookup(srcChain, hub.Chain)
+		if err != nil {
+			continue
+		}
+		second, err := t.Lookup(hub.Chain, dstChain)
+		if err != nil {
+			continue
+		}
+		return []Route{first, second}, hub, nil
+	}
+
+	return nil, nil, err
+}
+
+// Token returns the listed token at address on chain.
+func (t *Table) Token(chain, address string) (common.TokenInfo, bool) {
+	for _, tok := range t.tokens[chain] {
+		if strings.EqualFold(tok.Address, address) {
+			return tok, true
+		}
+	}
+	return common.TokenInfo{}, false
+}
+
+// CheckToken returns ErrTokenNotListed for a token missing from the list of
+// a chain that has one.
+func (t *Table) CheckToken(chain, address string) error {
+	if len(t.tokens[chain]) == 0 {
+		return nil
+	}
+	if _, ok := t.Token(chain, address); !ok {
+		return fmt.Errorf("%w on chain %s: %s", ErrTokenNotListed, chain, address)
+	}
+	return nil
+}
+
+// Tokens returns the listed tokens, ordered by chain.
+func (t *Table) Tokens() []common.TokenInfo {
+	chains := make([]string, 0, len(t.tokens))
+	for chain := range t.tokens {
+		chains = append(chains, chain)
+	}
+	sort.Strings(chains)
+
+	var out []common.TokenInfo
+	for _, chain := range chains {
+		out = append(out, t.tokens[chain]...)
+	}
+	return out
+}
+
+// Hubs returns the configured intermediate chains.
+func (t *Table) Hubs() []Hub {
+	return t.hubs
+}
+
+// Routes returns every configured route, ordered by source then destination chain.
+func (t *Table) Routes() []Route {
+	out := make([]Route, 0, len(t.routes))
+	for _, r := range t.routes {
+		out = append(out, r)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].SrcChain != out[j].SrcChain {
+			return out[i].SrcChain < out[j].SrcChain
+		}
+		return out[i].DstChain < out[j].DstChain
+	})
+
+	return out
+}
+
+// RequireFactories checks that every enabled route names both its escrow
+// factories, so quotes never carry the upstream ones.
+func (t *Table) RequireFactories() error {
+	for _, r := range t.Routes() {
+		if !r.Enabled {
+			continue
+		}
+		if r.SrcEscrowFactory == "" || r.DstEscrowFactory == "" {
+			return fmt.Errorf("route %s -> %s does not pin its escrow factories", r.SrcChain, r.DstChain)
+		}
+	}
+	return nil
+}
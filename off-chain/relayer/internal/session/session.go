@@ -0,0 +1,226 @@
+// Package session signs makers in with their wallet, in the style of
+// Sign-In with Ethereum (EIP-4361), so order status can be restricted to the
+// order's maker. A maker requests a challenge for its address, signs the
+// challenge message and trades the signature for a short-lived token.
+package session
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// ChallengeTTL is how long a challenge may be signed.
+	ChallengeTTL = 5 * time.Minute
+	// DefaultTTL is how long a session token is valid.
+	DefaultTTL = time.Hour
+	// MaxChallenges bounds the challenges issued within ChallengeTTL, and
+	// MaxClientChallenges those issued to one client, whether signed in with
+	// or not
+	MaxChallenges       = 10000
+	MaxClientChallenges = 10
+)
+
+var (
+	ErrUnknownChallenge  = errors.New("unknown or expired challenge")
+	ErrTooManyChallenges = errors.New("too many challenges, try again later")
+	ErrNoDomain          = errors.New("maker sessions need MAKER_SESSION_DOMAIN")
+	ErrBadToken          = errors.New("invalid session token")
+	ErrExpired           = errors.New("session expired")
+)
+
+// Verifier checks that signer signed message with its wallet.
+type Verifier func(signer string, message []byte, signature string) error
+
+// Challenge is a message for the maker to sign.
+type Challenge struct {
+	Nonce     string `json:"nonce"`
+	Message   string `json:"message"`
+	ExpiresAt int64  `json:"expiresAt"`
+}
+
+type challenge struct {
+	address   string
+	message   string
+	expiresAt time.Time
+}
+
+// issued is a challenge counted against its client until it expires.
+type issued struct {
+	nonce     string
+	client    string
+	expiresAt time.Time
+}
+
+// Sessions issues challenges and the tokens signed challenges are traded
+// for. Tokens are HMACs of the address and expiry, so with a configured
+// secret they survive restarts and are accepted by every instance.
+type Sessions struct {
+	secret []byte
+	ttl    time.Duration
+	// the host makers sign in to, named in the challenge message
+	domain string
+
+	mu         sync.Mutex
+	challenges map[string]challenge
+	// the challenges issued within ChallengeTTL, oldest first, and their
+	// number by client
+	issued  []issued
+	clients map[string]int
+}
+
+// New returns sessions for domain signing tokens valid for ttl with secret,
+// or with a random secret if none is given.
+func New(domain string, secret []byte, ttl time.Duration) (*Sessions, error) {
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, err
+		}
+	}
+	return &Sessions{
+		secret:     secret,
+		ttl:        ttl,
+		domain:     domain,
+		challenges: make(map[string]challenge),
+		clients:    make(map[string]int),
+	}, nil
+}
+
+// FromEnv returns sessions for MAKER_SESSION_DOMAIN signing with
+// MAKER_SESSION_SECRET, random per process when unset, valid for
+// MAKER_SESSION_TTL seconds, DefaultTTL when unset.
+func FromEnv() (*Sessions, error) {
+	ttl := DefaultTTL
+	if v := os.Getenv("MAKER_SESSION_TTL"); v != "" {
+		secs, err := strconv.Atoi(v)
+		if err != nil || secs <= 0 {
+			return nil, fmt.Errorf("MAKER_SESSION_TTL must be a positive number of seconds, got %q", v)
+		}
+		ttl = time.Duration(secs) * time.Second
+	}
+	return New(os.Getenv("MAKER_SESSION_DOMAIN"), []byte(os.Getenv("MAKER_SESSION_SECRET")), ttl)
+}
+
+// Enabled reports whether makers can sign in, which needs a domain.
+func (s *Sessions) Enabled() bool {
+	return s.domain != ""
+}
+
+// Challenge returns a message for address to sign, bound to the sessions'
+// domain, on behalf of client, the caller's IP. It fails with
+// ErrTooManyChallenges once client, or all clients together, were issued
+// their limit within ChallengeTTL.
+func (s *Sessions) Challenge(address, client string, now time.Time) (Challenge, error) {
+	if !s.Enabled() {
+		return Challenge{}, ErrNoDomain
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.prune(now)
+	if len(s.issued) >= MaxChallenges || s.clients[client] >= MaxClientChallenges {
+		return Challenge{}, ErrTooManyChallenges
+	}
+
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		return Challenge{}, err
+	}
+	nonce := hex.EncodeToString(raw)
+	expiresAt := now.Add(ChallengeTTL)
+
+	message := fmt.Sprintf("%s wants you to sign in with your account:\n%s\n\n"+
+		"Sign in to read the status of your orders.\n\n"+
+		"Nonce: %s\nIssued At: %s\nExpiration Time: %s",
+		s.domain, address, nonce, now.UTC().Format(time.RFC3339), expiresAt.UTC().Format(time.RFC3339))
+
+	s.challenges[nonce] = challenge{address: address, message: message, expiresAt: expiresAt}
+	s.issued = append(s.issued, issued{nonce: nonce, client: client, expiresAt: expiresAt})
+	s.clients[client]++
+	return Challenge{Nonce: nonce, Message: message, ExpiresAt: expiresAt.Unix()}, nil
+}
+
+// prune forgets the challenges expired at now. The caller holds mu.
+func (s *Sessions) prune(now time.Time) {
+	n := 0
+	for _, i := range s.issued {
+		if now.Before(i.expiresAt) {
+			break
+		}
+		delete(s.challenges, i.nonce)
+		if s.clients[i.client]--; s.clients[i.client] == 0 {
+			delete(s.clients, i.client)
+		}
+		n++
+	}
+	s.issued = s.issued[n:]
+}
+
+// SignIn consumes the challenge nonce and returns a token for its address if
+// verify accepts signature of the challenge message.
+func (s *Sessions) SignIn(nonce, signature string, verify Verifier, now time.Time) (string, time.Time, error) {
+	// taking the challenge, so each can be signed in with once
+	s.mu.Lock()
+	c, ok := s.challenges[nonce]
+	delete(s.challenges, nonce)
+	s.mu.Unlock()
+	if !ok || !now.Before(c.expiresAt) {
+		return "", time.Time{}, ErrUnknownChallenge
+	}
+
+	if err := verify(c.address, []byte(c.message), signature); err != nil {
+		return "", time.Time{}, err
+	}
+
+	expiresAt := now.Add(s.ttl)
+	payload := strings.ToLower(c.address) + "|" + strconv.FormatInt(expiresAt.Unix(), 10)
+	token := base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." + base64.RawURLEncoding.EncodeToString(s.sign(payload))
+	return token, expiresAt, nil
+}
+
+// Authenticate returns the address token was issued to.
+func (s *Sessions) Authenticate(token string, now time.Time) (string, error) {
+	encoded, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return "", ErrBadToken
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", ErrBadToken
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(mac, s.sign(string(payload))) {
+		return "", ErrBadToken
+	}
+
+	address, expiry, ok := strings.Cut(string(payload), "|")
+	if !ok {
+		return "", ErrBadToken
+	}
+	expiresAt, err := strconv.ParseInt(expiry, 10, 64)
+	if err != nil {
+		return "", ErrBadToken
+	}
+	if now.Unix() >= expiresAt {
+		return "", ErrExpired
+	}
+	return address, nil
+}
+
+func (s *Sessions) sign(payload string) []byte {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,143 @@
+package session
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+const maker = "0x1111111111111111111111111111111111111111"
+
+func newSessions(t *testing.T) *Sessions {
+	t.Helper()
+	s, err := New("relayer.example", []byte("secret"), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// signedBy accepts the signature "ok" of signer.
+func signedBy(signer string) Verifier {
+	return func(address string, _ []byte, signature string) error {
+		if address != signer || signature != "ok" {
+			return errors.New("bad signature")
+		}
+		return nil
+	}
+}
+
+func TestSignIn(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+
+	tests := []struct {
+		name      string
+		after     time.Duration
+		signature string
+		reuse     bool
+		wantErr   error
+	}{
+		{name: "signed", after: time.Minute, signature: "ok"},
+		{name: "bad signature", after: time.Minute, signature: "bad"},
+		{name: "expired", after: ChallengeTTL, signature: "ok", wantErr: ErrUnknownChallenge},
+		{name: "reused", after: time.Minute, signature: "ok", reuse: true, wantErr: ErrUnknownChallenge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newSessions(t)
+			c, err := s.Challenge(maker, "ip", now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.HasPrefix(c.Message, "relayer.example wants you to sign in") || !strings.Contains(c.Message, maker) {
+				t.Fatalf("message %q does not name the domain and the maker", c.Message)
+			}
+
+			at := now.Add(tt.after)
+			if tt.reuse {
+				if _, _, err := s.SignIn(c.Nonce, "ok", signedBy(maker), at); err != nil {
+					t.Fatal(err)
+				}
+			}
+			token, expiresAt, err := s.SignIn(c.Nonce, tt.signature, signedBy(maker), at)
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("got %v, want %v", err, tt.wantErr)
+				}
+				return
+			case tt.signature != "ok":
+				if err == nil {
+					t.Fatal("signed in with a bad signature")
+				}
+				return
+			case err != nil:
+				t.Fatal(err)
+			}
+
+			address, err := s.Authenticate(token, at)
+			if err != nil || address != maker {
+				t.Fatalf("Authenticate = %s, %v", address, err)
+			}
+			if _, err := s.Authenticate(token, expiresAt); !errors.Is(err, ErrExpired) {
+				t.Fatalf("token at its expiry: got %v, want %v", err, ErrExpired)
+			}
+		})
+	}
+}
+
+func TestChallengeLimits(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+
+	t.Run("per client", func(t *testing.T) {
+		s := newSessions(t)
+		for range MaxClientChallenges {
+			if _, err := s.Challenge(maker, "a", now); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if _, err := s.Challenge(maker, "a", now); !errors.Is(err, ErrTooManyChallenges) {
+			t.Fatalf("got %v, want %v", err, ErrTooManyChallenges)
+		}
+		if _, err := s.Challenge(maker, "b", now); err != nil {
+			t.Fatalf("other client: %v", err)
+		}
+		// signing in does not give a challenge back, expiring does
+		if _, err := s.Challenge(maker, "a", now.Add(ChallengeTTL-time.Second)); !errors.Is(err, ErrTooManyChallenges) {
+			t.Fatalf("before expiry: got %v, want %v", err, ErrTooManyChallenges)
+		}
+		if _, err := s.Challenge(maker, "a", now.Add(ChallengeTTL)); err != nil {
+			t.Fatalf("after expiry: %v", err)
+		}
+	})
+
+	t.Run("total", func(t *testing.T) {
+		s := newSessions(t)
+		for i := range MaxChallenges {
+			if _, err := s.Challenge(maker, strconv.Itoa(i), now); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if _, err := s.Challenge(maker, "new", now); !errors.Is(err, ErrTooManyChallenges) {
+			t.Fatalf("got %v, want %v", err, ErrTooManyChallenges)
+		}
+		if _, err := s.Challenge(maker, "new", now.Add(ChallengeTTL)); err != nil {
+			t.Fatalf("after expiry: %v", err)
+		}
+		if len(s.challenges) != 1 || len(s.clients) != 1 {
+			t.Fatalf("kept %d challenges of %d clients after expiry, want 1 of 1", len(s.challenges), len(s.clients))
+		}
+	})
+
+	t.Run("no domain", func(t *testing.T) {
+		s, err := New("", nil, time.Hour)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.Challenge(maker, "a", now); !errors.Is(err, ErrNoDomain) {
+			t.Fatalf("got %v, want %v", err, ErrNoDomain)
+		}
+	})
+}
@@ -0,0 +1,29 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// EventCursor returns the transaction digest and event sequence number of the
+// last event the named subscription handled, or ErrNotFound.
+func (s *Store) EventCursor(ctx context.Context, name string) (string, string, error) {
+	var txDigest, eventSeq string
+	err := s.db.QueryRowContext(ctx, `SELECT tx_digest, event_seq FROM event_cursors WHERE name = ?`, name).
+		Scan(&txDigest, &eventSeq)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", "", ErrNotFound
+	}
+	return txDigest, eventSeq, err
+}
+
+// PutEventCursor stores the last event the named subscription handled.
+func (s *Store) PutEventCursor(ctx context.Context, name, txDigest, eventSeq string) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO event_cursors (name, tx_digest, event_seq) VALUES (?, ?, ?)
+		ON CONFLICT (name) DO UPDATE SET tx_digest = excluded.tx_digest, event_seq = excluded.event_seq`,
+		name, txDigest, eventSeq,
+	)
+	return err
+}
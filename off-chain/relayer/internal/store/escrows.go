@@ -0,0 +1,88 @@
+package store
+
+import (
+	"context"
+	"time"
+)
+
+// EscrowRecord is one escrow of a verified fill, with the immutables its
+// withdraw and cancel functions take.
+type EscrowRecord struct {
+	OrderHash  string
+	HashIdx    int
+	Side       string // src or dst
+	ChainID    string
+	Escrow     string
+	DeployTx   string
+	Immutables []byte // JSON, empty for Sui escrows
+	DeployedAt time.Time
+	Taker      string
+	Amount     string // base units, decimal
+	Hashlock   string
+	VerifiedAt time.Time
+	// withdrawing or cancelling tx, empty while the escrow is open
+	ClosedTx string
+}
+
+// PutEscrow records an escrow of a fill, replacing the one recorded for the
+// same fill and side.
+func (s *Store) PutEscrow(ctx context.Context, rec EscrowRecord) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO escrows (order_hash, hash_idx, side, chain_id, escrow, deploy_tx, immutables, deployed_at, taker, amount, hashlock, verified_at, closed_tx)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, '')
+		ON CONFLICT (order_hash, hash_idx, side) DO UPDATE SET
+			chain_id = excluded.chain_id,
+			escrow = excluded.escrow,
+			deploy_tx = excluded.deploy_tx,
+			immutables = excluded.immutables,
+			deployed_at = excluded.deployed_at,
+			taker = excluded.taker,
+			amount = excluded.amount,
+			hashlock = excluded.hashlock,
+			verified_at = excluded.verified_at,
+			closed_tx = ''`,
+		rec.OrderHash, rec.HashIdx, rec.Side, rec.ChainID, rec.Escrow, rec.DeployTx, string(rec.Immutables), rec.DeployedAt.UnixMilli(),
+		rec.Taker, rec.Amount, rec.Hashlock, rec.VerifiedAt.UnixMilli(),
+	)
+	return err
+}
+
+// CloseEscrow records the tx that withdrew from or cancelled an escrow of an
+// order. Escrows are matched regardless of case.
+func (s *Store) CloseEscrow(ctx context.Context, orderHash, escrow, txHash string) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE escrows SET closed_tx = ? WHERE order_hash = ? AND lower(escrow) = lower(?)`,
+		txHash, orderHash, escrow,
+	)
+	return err
+}
+
+// Escrows returns the recorded escrows of an order by fill index, src
+// before dst.
+func (s *Store) Escrows(ctx context.Context, orderHash string) ([]EscrowRecord, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT hash_idx, side, chain_id, escrow, deploy_tx, immutables, deployed_at, taker, amount, hashlock, verified_at, closed_tx
+		FROM escrows WHERE order_hash = ? ORDER BY hash_idx, side DESC`, orderHash)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var escrows []EscrowRecord
+	for rows.Next() {
+		rec := EscrowRecord{OrderHash: orderHash}
+		var immutables string
+		var deployedAt, verifiedAt int64
+		if err := rows.Scan(&rec.HashIdx, &rec.Side, &rec.ChainID, &rec.Escrow, &rec.DeployTx, &immutables, &deployedAt,
+			&rec.Taker, &rec.Amount, &rec.Hashlock, &verifiedAt, &rec.ClosedTx); err != nil {
+			return nil, err
+		}
+		rec.Immutables = []byte(immutables)
+		rec.DeployedAt = time.UnixMilli(deployedAt)
+		if verifiedAt > 0 {
+			rec.VerifiedAt = time.UnixMilli(verifiedAt)
+		}
+		escrows = append(escrows, rec)
+	}
+	return escrows, rows.Err()
+}
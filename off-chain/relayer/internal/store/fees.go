@@ -0,0 +1,60 @@
+package store
+
+import (
+	"context"
+	"time"
+)
+
+// Fee kinds of a FeeAccrualRecord.
+const (
+	ProtocolFee   = "protocol"
+	IntegratorFee = "integrator"
+)
+
+// FeeAccrualRecord is the share of an order's protocol or integrator fee
+// accrued when the source escrow of one of its fills was withdrawn.
+type FeeAccrualRecord struct {
+	OrderHash  string
+	HashIdx    int
+	Kind       string
+	Integrator string
+	ChainID    string
+	Token      string
+	Amount     string // base units, decimal
+	AccruedAt  time.Time
+}
+
+// PutFeeAccrual records an accrual, once per fill and kind.
+func (s *Store) PutFeeAccrual(ctx context.Context, rec FeeAccrualRecord) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO fee_accruals (order_hash, hash_idx, kind, integrator, chain_id, token, amount, accrued_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (order_hash, hash_idx, kind) DO NOTHING`,
+		rec.OrderHash, rec.HashIdx, rec.Kind, rec.Integrator, rec.ChainID, rec.Token, rec.Amount, rec.AccruedAt.UnixMilli(),
+	)
+	return err
+}
+
+// FeeAccruals returns every accrual, oldest first, those of orderHash only
+// when it is not empty.
+func (s *Store) FeeAccruals(ctx context.Context, orderHash string) ([]FeeAccrualRecord, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT order_hash, hash_idx, kind, integrator, chain_id, token, amount, accrued_at
+		FROM fee_accruals WHERE ? = '' OR order_hash = ? ORDER BY accrued_at`, orderHash, orderHash)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var accruals []FeeAccrualRecord
+	for rows.Next() {
+		var rec FeeAccrualRecord
+		var accruedAt int64
+		if err := rows.Scan(&rec.OrderHash, &rec.HashIdx, &rec.Kind, &rec.Integrator, &rec.ChainID, &rec.Token, &rec.Amount, &accruedAt); err != nil {
+			return nil, err
+		}
+		rec.AccruedAt = time.UnixMilli(accruedAt)
+		accruals = append(accruals, rec)
+	}
+	return accruals, rows.Err()
+}
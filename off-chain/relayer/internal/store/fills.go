@@ -0,0 +1,53 @@
+package store
+
+import "context"
+
+// FillSecretRecord tells how far the secret of a verified fill got.
+type FillSecretRecord struct {
+	HashIdx  int
+	Allowed  bool
+	Revealed bool
+}
+
+// MarkFillAllowed records that a fill may receive its secret.
+func (s *Store) MarkFillAllowed(ctx context.Context, orderHash string, hashIdx int) error {
+	return s.markFillSecret(ctx, orderHash, hashIdx, true, false)
+}
+
+// MarkFillRevealed records that the secret of a fill was shared.
+func (s *Store) MarkFillRevealed(ctx context.Context, orderHash string, hashIdx int) error {
+	return s.markFillSecret(ctx, orderHash, hashIdx, false, true)
+}
+
+// markFillSecret sets the given flags of a fill, leaving those set earlier.
+func (s *Store) markFillSecret(ctx context.Context, orderHash string, hashIdx int, allowed, revealed bool) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO fill_secrets (order_hash, hash_idx, allowed, revealed) VALUES (?, ?, ?, ?)
+		ON CONFLICT (order_hash, hash_idx) DO UPDATE SET
+			allowed = max(allowed, excluded.allowed),
+			revealed = max(revealed, excluded.revealed)`,
+		orderHash, hashIdx, allowed, revealed,
+	)
+	return err
+}
+
+// FillSecrets returns the recorded secret progress of an order's fills by
+// fill index.
+func (s *Store) FillSecrets(ctx context.Context, orderHash string) ([]FillSecretRecord, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT hash_idx, allowed, revealed FROM fill_secrets WHERE order_hash = ? ORDER BY hash_idx`, orderHash)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var fills []FillSecretRecord
+	for rows.Next() {
+		var rec FillSecretRecord
+		if err := rows.Scan(&rec.HashIdx, &rec.Allowed, &rec.Revealed); err != nil {
+			return nil, err
+		}
+		fills = append(fills, rec)
+	}
+	return fills, rows.Err()
+}
@@ -0,0 +1,85 @@
+package store
+
+import (
+	"context"
+	"time"
+)
+
+// LegRecord is an order of a multi-hop quote. The order and state of a leg
+// are kept while it is held back until the previous leg settles.
+type LegRecord struct {
+	ParentID  string
+	Index     int
+	Count     int
+	OrderHash string
+	Settled   bool
+	Order     []byte
+	State     []byte
+	CreatedAt time.Time
+}
+
+// HoldLeg stores a leg held back until the previous one settles.
+func (s *Store) HoldLeg(ctx context.Context, rec LegRecord) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO multihop_legs (parent_id, leg_idx, leg_count, order_hash, "order", state, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (parent_id, leg_idx) DO UPDATE SET
+			order_hash = excluded.order_hash,
+			"order" = excluded."order",
+			state = excluded.state`,
+		rec.ParentID, rec.Index, rec.Count, rec.OrderHash, string(rec.Order), string(rec.State), rec.CreatedAt.UnixMilli(),
+	)
+	return err
+}
+
+// BroadcastLeg records that a held leg was broadcast, dropping its order,
+// which the orders table has from then on.
+func (s *Store) BroadcastLeg(ctx context.Context, parentID string, idx int) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE multihop_legs SET "order" = '', state = '' WHERE parent_id = ? AND leg_idx = ?`, parentID, idx)
+	return err
+}
+
+// SettleLeg records that a leg settled, so the next one may be broadcast.
+func (s *Store) SettleLeg(ctx context.Context, rec LegRecord) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO multihop_legs (parent_id, leg_idx, leg_count, order_hash, settled, created_at)
+		VALUES (?, ?, ?, ?, 1, ?)
+		ON CONFLICT (parent_id, leg_idx) DO UPDATE SET settled = 1`,
+		rec.ParentID, rec.Index, rec.Count, rec.OrderHash, rec.CreatedAt.UnixMilli(),
+	)
+	return err
+}
+
+// Legs returns the legs recorded at or after since, by quote and index.
+func (s *Store) Legs(ctx context.Context, since time.Time) ([]LegRecord, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT parent_id, leg_idx, leg_count, order_hash, settled, "order", state, created_at
+		FROM multihop_legs WHERE created_at >= ? ORDER BY parent_id, leg_idx`, since.UnixMilli())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var legs []LegRecord
+	for rows.Next() {
+		var rec LegRecord
+		var order, state string
+		var createdAt int64
+		if err := rows.Scan(&rec.ParentID, &rec.Index, &rec.Count, &rec.OrderHash, &rec.Settled, &order, &state, &createdAt); err != nil {
+			return nil, err
+		}
+		if order != "" {
+			rec.Order, rec.State = []byte(order), []byte(state)
+		}
+		rec.CreatedAt = time.UnixMilli(createdAt)
+		legs = append(legs, rec)
+	}
+	return legs, rows.Err()
+}
+
+// DeleteLegs removes the legs recorded before before.
+func (s *Store) DeleteLegs(ctx context.Context, before time.Time) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM multihop_legs WHERE created_at < ?`, before.UnixMilli())
+	return err
+}
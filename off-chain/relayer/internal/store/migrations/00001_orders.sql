@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE orders (
+    order_hash   TEXT PRIMARY KEY,
+    src_chain_id TEXT NOT NULL,
+    maker        TEXT NOT NULL,
+    status       TEXT NOT NULL,
+    quote_id     TEXT NOT NULL,
+    "order"      TEXT NOT NULL, -- the submitted order, JSON
+    submitted_at INTEGER NOT NULL, -- unix seconds
+    updated_at   INTEGER NOT NULL
+);
+
+CREATE INDEX orders_status ON orders (status);
+CREATE INDEX orders_maker ON orders (maker);
+
+-- +goose Down
+DROP TABLE orders;
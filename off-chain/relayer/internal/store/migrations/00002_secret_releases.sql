@@ -0,0 +1,15 @@
+-- +goose Up
+ALTER TABLE orders ADD COLUMN state TEXT NOT NULL DEFAULT '{}'; -- relayer state needed to restore the order, JSON
+
+CREATE TABLE secret_releases (
+    order_hash  TEXT NOT NULL REFERENCES orders (order_hash) ON DELETE CASCADE,
+    hash_idx    INTEGER NOT NULL,
+    src_tx_hash TEXT NOT NULL,
+    dst_tx_hash TEXT NOT NULL,
+    release_at  INTEGER NOT NULL, -- unix milliseconds
+    PRIMARY KEY (order_hash, hash_idx)
+);
+
+-- +goose Down
+DROP TABLE secret_releases;
+ALTER TABLE orders DROP COLUMN state;
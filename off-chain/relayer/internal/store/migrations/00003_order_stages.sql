@@ -0,0 +1,12 @@
+-- +goose Up
+CREATE TABLE order_stages (
+    order_hash TEXT NOT NULL REFERENCES orders (order_hash) ON DELETE CASCADE,
+    stage      TEXT NOT NULL, -- quoted, submitted, escrows, secret, withdrawn
+    reached_at INTEGER NOT NULL, -- unix milliseconds
+    PRIMARY KEY (order_hash, stage)
+);
+
+CREATE INDEX order_stages_stage ON order_stages (stage, reached_at);
+
+-- +goose Down
+DROP TABLE order_stages;
@@ -0,0 +1,15 @@
+-- +goose Up
+CREATE TABLE escrows (
+    order_hash  TEXT NOT NULL REFERENCES orders (order_hash) ON DELETE CASCADE,
+    hash_idx    INTEGER NOT NULL,
+    side        TEXT NOT NULL, -- src, dst
+    chain_id    TEXT NOT NULL,
+    escrow      TEXT NOT NULL, -- EVM address or Sui object id
+    deploy_tx   TEXT NOT NULL,
+    immutables  TEXT NOT NULL, -- JSON, empty for Sui escrows
+    deployed_at INTEGER NOT NULL, -- unix milliseconds
+    PRIMARY KEY (order_hash, hash_idx, side)
+);
+
+-- +goose Down
+DROP TABLE escrows;
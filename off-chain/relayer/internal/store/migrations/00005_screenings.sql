@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE screenings (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    stage       TEXT NOT NULL, -- quote, submit
+    ref         TEXT NOT NULL, -- order hash at submit, empty at quote
+    chain_id    TEXT NOT NULL,
+    address     TEXT NOT NULL,
+    role        TEXT NOT NULL, -- maker, receiver
+    screener    TEXT NOT NULL,
+    allowed     INTEGER NOT NULL,
+    reason      TEXT NOT NULL, -- the screener's reason or error
+    screened_at INTEGER NOT NULL -- unix milliseconds
+);
+CREATE INDEX screenings_address ON screenings (address);
+
+-- +goose Down
+DROP TABLE screenings;
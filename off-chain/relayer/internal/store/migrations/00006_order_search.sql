@@ -0,0 +1,25 @@
+-- +goose Up
+ALTER TABLE orders ADD COLUMN dst_chain_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE orders ADD COLUMN maker_asset TEXT NOT NULL DEFAULT '';
+ALTER TABLE orders ADD COLUMN taker_asset TEXT NOT NULL DEFAULT '';
+ALTER TABLE orders ADD COLUMN making_amount TEXT NOT NULL DEFAULT ''; -- base units, decimal
+ALTER TABLE orders ADD COLUMN taking_amount TEXT NOT NULL DEFAULT '';
+
+UPDATE orders SET
+    dst_chain_id = COALESCE(json_extract(state, '$.quote.QuoteRequest.DstChain'), ''),
+    maker_asset = COALESCE(json_extract("order", '$.order.makerAsset'), ''),
+    taker_asset = COALESCE(json_extract("order", '$.order.takerAsset'), ''),
+    making_amount = COALESCE(json_extract("order", '$.order.makingAmount'), ''),
+    taking_amount = COALESCE(json_extract("order", '$.order.takingAmount'), '');
+
+CREATE INDEX orders_submitted ON orders (submitted_at);
+CREATE INDEX orders_chains ON orders (src_chain_id, dst_chain_id);
+
+-- +goose Down
+DROP INDEX orders_chains;
+DROP INDEX orders_submitted;
+ALTER TABLE orders DROP COLUMN taking_amount;
+ALTER TABLE orders DROP COLUMN making_amount;
+ALTER TABLE orders DROP COLUMN taker_asset;
+ALTER TABLE orders DROP COLUMN maker_asset;
+ALTER TABLE orders DROP COLUMN dst_chain_id;
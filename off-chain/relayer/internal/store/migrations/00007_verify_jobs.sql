@@ -0,0 +1,12 @@
+-- +goose Up
+CREATE TABLE verify_jobs (
+    order_hash  TEXT NOT NULL REFERENCES orders (order_hash) ON DELETE CASCADE,
+    src_tx_hash TEXT NOT NULL,
+    dst_tx_hash TEXT NOT NULL,
+    resolver_id TEXT NOT NULL, -- empty without a resolver registry
+    received_at INTEGER NOT NULL, -- unix milliseconds
+    PRIMARY KEY (order_hash, src_tx_hash, dst_tx_hash)
+);
+
+-- +goose Down
+DROP TABLE verify_jobs;
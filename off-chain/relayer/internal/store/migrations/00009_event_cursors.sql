@@ -0,0 +1,9 @@
+-- +goose Up
+CREATE TABLE event_cursors (
+    name      TEXT PRIMARY KEY, -- the subscription, e.g. sui:<package>::<module>
+    tx_digest TEXT NOT NULL,
+    event_seq TEXT NOT NULL
+);
+
+-- +goose Down
+DROP TABLE event_cursors;
@@ -0,0 +1,15 @@
+-- +goose Up
+CREATE TABLE secret_sets (
+    id            TEXT PRIMARY KEY,
+    algorithm     TEXT NOT NULL,
+    policy        TEXT NOT NULL,
+    hashlock      TEXT NOT NULL,
+    secret_hashes TEXT NOT NULL, -- JSON array of hex hashes
+    sealed        TEXT NOT NULL, -- JSON array of the AES-GCM sealed secrets, base64
+    export_token  BLOB NOT NULL, -- sha256 of the token handed to the maker
+    order_hash    TEXT NOT NULL, -- empty until an order is submitted with the set
+    created_at    INTEGER NOT NULL -- unix milliseconds
+);
+
+-- +goose Down
+DROP TABLE secret_sets;
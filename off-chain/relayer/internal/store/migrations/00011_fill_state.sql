@@ -0,0 +1,22 @@
+-- +goose Up
+ALTER TABLE escrows ADD COLUMN taker TEXT NOT NULL DEFAULT '';
+ALTER TABLE escrows ADD COLUMN amount TEXT NOT NULL DEFAULT ''; -- base units, decimal
+ALTER TABLE escrows ADD COLUMN hashlock TEXT NOT NULL DEFAULT '';
+ALTER TABLE escrows ADD COLUMN verified_at INTEGER NOT NULL DEFAULT 0; -- unix milliseconds
+ALTER TABLE escrows ADD COLUMN closed_tx TEXT NOT NULL DEFAULT ''; -- empty while open
+
+CREATE TABLE fill_secrets (
+    order_hash TEXT NOT NULL REFERENCES orders (order_hash) ON DELETE CASCADE,
+    hash_idx   INTEGER NOT NULL,
+    allowed    INTEGER NOT NULL, -- 1 once the fill may receive its secret
+    revealed   INTEGER NOT NULL, -- 1 once the secret was shared
+    PRIMARY KEY (order_hash, hash_idx)
+);
+
+-- +goose Down
+DROP TABLE fill_secrets;
+ALTER TABLE escrows DROP COLUMN closed_tx;
+ALTER TABLE escrows DROP COLUMN verified_at;
+ALTER TABLE escrows DROP COLUMN hashlock;
+ALTER TABLE escrows DROP COLUMN amount;
+ALTER TABLE escrows DROP COLUMN taker;
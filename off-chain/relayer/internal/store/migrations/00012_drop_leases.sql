@@ -0,0 +1,11 @@
+-- +goose Up
+-- leader election was removed, its lease table is left over in databases
+-- created while it existed
+DROP TABLE IF EXISTS leases;
+
+-- +goose Down
+CREATE TABLE leases (
+    name       TEXT PRIMARY KEY,
+    holder     TEXT NOT NULL, -- instance id of the replica holding it
+    expires_at INTEGER NOT NULL -- unix milliseconds
+);
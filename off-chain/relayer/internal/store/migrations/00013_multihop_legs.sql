@@ -0,0 +1,15 @@
+-- +goose Up
+CREATE TABLE multihop_legs (
+    parent_id  TEXT NOT NULL, -- id of the multi-hop quote
+    leg_idx    INTEGER NOT NULL,
+    leg_count  INTEGER NOT NULL,
+    order_hash TEXT NOT NULL,
+    settled    INTEGER NOT NULL DEFAULT 0, -- 1 once the leg's order was fully filled and withdrawn
+    "order"    TEXT NOT NULL DEFAULT '', -- the order of a held leg, JSON, empty once broadcast
+    state      TEXT NOT NULL DEFAULT '', -- relayer state of a held leg, JSON
+    created_at INTEGER NOT NULL, -- unix milliseconds
+    PRIMARY KEY (parent_id, leg_idx)
+);
+
+-- +goose Down
+DROP TABLE multihop_legs;
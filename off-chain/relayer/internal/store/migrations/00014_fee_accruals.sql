@@ -0,0 +1,15 @@
+-- +goose Up
+CREATE TABLE fee_accruals (
+    order_hash TEXT NOT NULL,
+    hash_idx   INTEGER NOT NULL,
+    kind       TEXT NOT NULL, -- protocol or integrator
+    integrator TEXT NOT NULL, -- empty for the protocol fee
+    chain_id   TEXT NOT NULL,
+    token      TEXT NOT NULL,
+    amount     TEXT NOT NULL, -- base units, decimal
+    accrued_at INTEGER NOT NULL, -- unix milliseconds
+    PRIMARY KEY (order_hash, hash_idx, kind)
+);
+
+-- +goose Down
+DROP TABLE fee_accruals;
@@ -0,0 +1,104 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// ErrNotFound is returned when a record does not exist.
+var ErrNotFound = errors.New("not found")
+
+// OrderRecord is the persisted state of an order.
+type OrderRecord struct {
+	OrderHash    string
+	SrcChainID   string
+	DstChainID   string
+	Maker        string
+	MakerAsset   string
+	TakerAsset   string
+	MakingAmount string
+	TakingAmount string
+	Status       string
+	QuoteID      string
+	Order        []byte // the submitted order, JSON
+	State        []byte // relayer state needed to restore the order, JSON
+	SubmittedAt  time.Time
+	UpdatedAt    time.Time // set by queries only
+}
+
+// PutOrder inserts an order, or replaces it when it is already stored.
+func (s *Store) PutOrder(ctx context.Context, rec OrderRecord) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO orders (order_hash, src_chain_id, dst_chain_id, maker, maker_asset, taker_asset, making_amount,
+			taking_amount, status, quote_id, "order", state, submitted_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (order_hash) DO UPDATE SET
+			status = excluded.status,
+			"order" = excluded."order",
+			state = excluded.state,
+			updated_at = excluded.updated_at`,
+		rec.OrderHash, rec.SrcChainID, rec.DstChainID, rec.Maker, rec.MakerAsset, rec.TakerAsset, canonicalAmount(rec.MakingAmount),
+		canonicalAmount(rec.TakingAmount), rec.Status, rec.QuoteID, string(rec.Order), string(rec.State),
+		rec.SubmittedAt.Unix(), time.Now().Unix(),
+	)
+	return err
+}
+
+// SetOrderStatus records a status change of a stored order.
+func (s *Store) SetOrderStatus(ctx context.Context, orderHash, status string) error {
+	_, err := s.db.ExecContext(ctx, `UPDATE orders SET status = ?, updated_at = ? WHERE order_hash = ?`,
+		status, time.Now().Unix(), orderHash)
+	return err
+}
+
+// GetOrder returns a stored order, or ErrNotFound.
+func (s *Store) GetOrder(ctx context.Context, orderHash string) (OrderRecord, error) {
+	rec := OrderRecord{OrderHash: orderHash}
+	var order, state string
+	var submittedAt int64
+	err := s.db.QueryRowContext(ctx, `
+		SELECT src_chain_id, maker, status, quote_id, "order", state, submitted_at
+		FROM orders WHERE order_hash = ?`, orderHash,
+	).Scan(&rec.SrcChainID, &rec.Maker, &rec.Status, &rec.QuoteID, &order, &state, &submittedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return OrderRecord{}, ErrNotFound
+	}
+	if err != nil {
+		return OrderRecord{}, err
+	}
+
+	rec.Order = []byte(order)
+	rec.State = []byte(state)
+	rec.SubmittedAt = time.Unix(submittedAt, 0)
+	return rec, nil
+}
+
+// OrdersUpdated returns the orders submitted or last changed in [from, to),
+// oldest change first.
+func (s *Store) OrdersUpdated(ctx context.Context, from, to time.Time) ([]OrderRecord, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT order_hash, src_chain_id, maker, status, quote_id, "order", state, submitted_at
+		FROM orders WHERE updated_at >= ? AND updated_at < ?
+		ORDER BY updated_at`, from.Unix(), to.Unix())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var recs []OrderRecord
+	for rows.Next() {
+		var rec OrderRecord
+		var order, state string
+		var submittedAt int64
+		if err := rows.Scan(&rec.OrderHash, &rec.SrcChainID, &rec.Maker, &rec.Status, &rec.QuoteID, &order, &state, &submittedAt); err != nil {
+			return nil, err
+		}
+		rec.Order = []byte(order)
+		rec.State = []byte(state)
+		rec.SubmittedAt = time.Unix(submittedAt, 0)
+		recs = append(recs, rec)
+	}
+	return recs, rows.Err()
+}
@@ -0,0 +1,59 @@
+package store
+
+import (
+	"context"
+	"time"
+)
+
+// ReleaseRecord is a verified fill waiting for its escrows to be final
+// before its secret may be released.
+type ReleaseRecord struct {
+	OrderHash string
+	HashIdx   int
+	SrcTxHash string
+	DstTxHash string
+	ReleaseAt time.Time
+}
+
+// PutRelease schedules a release, replacing an earlier schedule of the fill.
+func (s *Store) PutRelease(ctx context.Context, rec ReleaseRecord) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO secret_releases (order_hash, hash_idx, src_tx_hash, dst_tx_hash, release_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (order_hash, hash_idx) DO UPDATE SET
+			src_tx_hash = excluded.src_tx_hash,
+			dst_tx_hash = excluded.dst_tx_hash,
+			release_at = excluded.release_at`,
+		rec.OrderHash, rec.HashIdx, rec.SrcTxHash, rec.DstTxHash, rec.ReleaseAt.UnixMilli(),
+	)
+	return err
+}
+
+// DeleteRelease removes the schedule of a fill once its secret is released.
+func (s *Store) DeleteRelease(ctx context.Context, orderHash string, hashIdx int) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM secret_releases WHERE order_hash = ? AND hash_idx = ?`, orderHash, hashIdx)
+	return err
+}
+
+// PendingReleases returns every scheduled release, earliest first.
+func (s *Store) PendingReleases(ctx context.Context) ([]ReleaseRecord, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT order_hash, hash_idx, src_tx_hash, dst_tx_hash, release_at
+		FROM secret_releases ORDER BY release_at`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var releases []ReleaseRecord
+	for rows.Next() {
+		var rec ReleaseRecord
+		var releaseAt int64
+		if err := rows.Scan(&rec.OrderHash, &rec.HashIdx, &rec.SrcTxHash, &rec.DstTxHash, &releaseAt); err != nil {
+			return nil, err
+		}
+		rec.ReleaseAt = time.UnixMilli(releaseAt)
+		releases = append(releases, rec)
+	}
+	return releases, rows.Err()
+}
@@ -0,0 +1,29 @@
+package store
+
+import (
+	"context"
+	"time"
+)
+
+// ScreeningRecord is the audit record of one compliance screening decision.
+type ScreeningRecord struct {
+	Stage      string // quote or submit
+	Ref        string // order hash at submit, empty at quote
+	ChainID    string
+	Address    string
+	Role       string // maker or receiver
+	Screener   string
+	Allowed    bool
+	Reason     string // the screener's reason, or why it could not decide
+	ScreenedAt time.Time
+}
+
+// PutScreening appends a screening decision to the audit log.
+func (s *Store) PutScreening(ctx context.Context, rec ScreeningRecord) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO screenings (stage, ref, chain_id, address, role, screener, allowed, reason, screened_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		rec.Stage, rec.Ref, rec.ChainID, rec.Address, rec.Role, rec.Screener, rec.Allowed, rec.Reason, rec.ScreenedAt.UnixMilli(),
+	)
+	return err
+}
@@ -0,0 +1,206 @@
+package store
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrBadCursor is returned for a search cursor this store did not issue for
+// the query's sort order.
+var ErrBadCursor = errors.New("invalid cursor")
+
+// OrderSort is the order search results are sorted in, ties broken by order
+// hash.
+type OrderSort string
+
+const (
+	SortSubmitted    OrderSort = "submittedAt"
+	SortMakingAmount OrderSort = "makingAmount"
+)
+
+// OrderQuery filters and pages a search of the stored orders. Zero fields do
+// not filter.
+type OrderQuery struct {
+	Statuses   []string
+	SrcChainID string
+	DstChainID string
+	// matches the maker or the taker asset, case-insensitively like Maker
+	Token string
+	Maker string
+	// submitted in [From, To)
+	From time.Time
+	To   time.Time
+	// making amount in base units, both inclusive
+	MinAmount string
+	MaxAmount string
+	Sort      OrderSort
+	Desc      bool
+	// the next cursor of the previous page, empty for the first
+	Cursor string
+	Limit  int
+}
+
+// searchCursor is the sort key of the last order of a page, the next page
+// starting after it.
+type searchCursor struct {
+	Sort      OrderSort `json:"s"`
+	Key       string    `json:"k"`
+	OrderHash string    `json:"h"`
+}
+
+// SearchOrders returns a page of at most q.Limit orders matching q, and the
+// cursor of the next page, empty on the last one. Amounts are compared as
+// numbers: longer decimals are larger, equal lengths compare as text.
+func (s *Store) SearchOrders(ctx context.Context, q OrderQuery) ([]OrderRecord, string, error) {
+	var where []string
+	var args []any
+
+	if len(q.Statuses) > 0 {
+		where = append(where, "status IN (?"+strings.Repeat(", ?", len(q.Statuses)-1)+")")
+		for _, status := range q.Statuses {
+			args = append(args, status)
+		}
+	}
+	if q.SrcChainID != "" {
+		where = append(where, "src_chain_id = ?")
+		args = append(args, q.SrcChainID)
+	}
+	if q.DstChainID != "" {
+		where = append(where, "dst_chain_id = ?")
+		args = append(args, q.DstChainID)
+	}
+	if q.Token != "" {
+		where = append(where, "(maker_asset = ? COLLATE NOCASE OR taker_asset = ? COLLATE NOCASE)")
+		args = append(args, q.Token, q.Token)
+	}
+	if q.Maker != "" {
+		where = append(where, "maker = ? COLLATE NOCASE")
+		args = append(args, q.Maker)
+	}
+	if !q.From.IsZero() {
+		where = append(where, "submitted_at >= ?")
+		args = append(args, q.From.Unix())
+	}
+	if !q.To.IsZero() {
+		where = append(where, "submitted_at < ?")
+		args = append(args, q.To.Unix())
+	}
+	if q.MinAmount != "" {
+		min := canonicalAmount(q.MinAmount)
+		where = append(where, "(length(making_amount) > ? OR (length(making_amount) = ? AND making_amount >= ?))")
+		args = append(args, len(min), len(min), min)
+	}
+	if q.MaxAmount != "" {
+		max := canonicalAmount(q.MaxAmount)
+		where = append(where, "(length(making_amount) < ? OR (length(making_amount) = ? AND making_amount <= ?))")
+		args = append(args, len(max), len(max), max)
+	}
+
+	sort := q.Sort
+	if sort == "" {
+		sort = SortSubmitted
+	}
+	after, dir := ">", "ASC"
+	if q.Desc {
+		after, dir = "<", "DESC"
+	}
+
+	if q.Cursor != "" {
+		cursor, err := decodeCursor(q.Cursor)
+		if err != nil || cursor.Sort != sort {
+			return nil, "", ErrBadCursor
+		}
+		switch sort {
+		case SortMakingAmount:
+			where = append(where, "(length(making_amount) "+after+" ? OR (length(making_amount) = ? AND (making_amount "+after+
+				" ? OR (making_amount = ? AND order_hash "+after+" ?))))")
+			args = append(args, len(cursor.Key), len(cursor.Key), cursor.Key, cursor.Key, cursor.OrderHash)
+		default:
+			at, err := strconv.ParseInt(cursor.Key, 10, 64)
+			if err != nil {
+				return nil, "", ErrBadCursor
+			}
+			where = append(where, "(submitted_at "+after+" ? OR (submitted_at = ? AND order_hash "+after+" ?))")
+			args = append(args, at, at, cursor.OrderHash)
+		}
+	}
+
+	query := `
+		SELECT order_hash, src_chain_id, dst_chain_id, maker, maker_asset, taker_asset, making_amount, taking_amount,
+			status, quote_id, submitted_at, updated_at
+		FROM orders`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	if sort == SortMakingAmount {
+		query += " ORDER BY length(making_amount) " + dir + ", making_amount " + dir + ", order_hash " + dir
+	} else {
+		query += " ORDER BY submitted_at " + dir + ", order_hash " + dir
+	}
+	// one more than the page, to tell whether another one follows
+	query += " LIMIT ?"
+	args = append(args, q.Limit+1)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	var recs []OrderRecord
+	for rows.Next() {
+		var rec OrderRecord
+		var submittedAt, updatedAt int64
+		err := rows.Scan(&rec.OrderHash, &rec.SrcChainID, &rec.DstChainID, &rec.Maker, &rec.MakerAsset, &rec.TakerAsset,
+			&rec.MakingAmount, &rec.TakingAmount, &rec.Status, &rec.QuoteID, &submittedAt, &updatedAt)
+		if err != nil {
+			return nil, "", err
+		}
+		rec.SubmittedAt = time.Unix(submittedAt, 0)
+		rec.UpdatedAt = time.Unix(updatedAt, 0)
+		recs = append(recs, rec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	if len(recs) <= q.Limit {
+		return recs, "", nil
+	}
+	recs = recs[:q.Limit]
+	last := recs[len(recs)-1]
+	cursor := searchCursor{Sort: sort, Key: strconv.FormatInt(last.SubmittedAt.Unix(), 10), OrderHash: last.OrderHash}
+	if sort == SortMakingAmount {
+		cursor.Key = last.MakingAmount
+	}
+	return recs, encodeCursor(cursor), nil
+}
+
+func encodeCursor(c searchCursor) string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeCursor(s string) (searchCursor, error) {
+	var c searchCursor
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, err
+	}
+	err = json.Unmarshal(raw, &c)
+	return c, err
+}
+
+// canonicalAmount strips the leading zeros of a decimal amount, so amounts
+// sort by length first.
+func canonicalAmount(amount string) string {
+	if trimmed := strings.TrimLeft(amount, "0"); trimmed != "" {
+		return trimmed
+	}
+	return "0"
+}
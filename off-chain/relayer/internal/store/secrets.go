@@ -0,0 +1,78 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// SecretSetRecord is a relayer generated secret set, its secrets sealed under
+// the custody key, which the store never sees.
+type SecretSetRecord struct {
+	ID           string
+	Algorithm    string
+	Policy       string
+	Hashlock     string
+	SecretHashes []string
+	Sealed       [][]byte
+	ExportToken  []byte
+	OrderHash    string
+	CreatedAt    time.Time
+}
+
+// PutSecretSet stores a set, replacing an earlier version of it such as the
+// one stored before it was bound to an order.
+func (s *Store) PutSecretSet(ctx context.Context, rec SecretSetRecord) error {
+	hashes, err := json.Marshal(rec.SecretHashes)
+	if err != nil {
+		return err
+	}
+	sealed, err := json.Marshal(rec.Sealed)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO secret_sets (id, algorithm, policy, hashlock, secret_hashes, sealed, export_token, order_hash, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET order_hash = excluded.order_hash`,
+		rec.ID, rec.Algorithm, rec.Policy, rec.Hashlock, string(hashes), string(sealed), rec.ExportToken, rec.OrderHash, rec.CreatedAt.UnixMilli(),
+	)
+	return err
+}
+
+// SecretSets returns the sets created at or after since, oldest first.
+func (s *Store) SecretSets(ctx context.Context, since time.Time) ([]SecretSetRecord, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, algorithm, policy, hashlock, secret_hashes, sealed, export_token, order_hash, created_at
+		FROM secret_sets WHERE created_at >= ? ORDER BY created_at`, since.UnixMilli())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sets []SecretSetRecord
+	for rows.Next() {
+		var rec SecretSetRecord
+		var hashes, sealed string
+		var createdAt int64
+		if err := rows.Scan(&rec.ID, &rec.Algorithm, &rec.Policy, &rec.Hashlock, &hashes, &sealed, &rec.ExportToken, &rec.OrderHash, &createdAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(hashes), &rec.SecretHashes); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(sealed), &rec.Sealed); err != nil {
+			return nil, err
+		}
+		rec.CreatedAt = time.UnixMilli(createdAt)
+		sets = append(sets, rec)
+	}
+	return sets, rows.Err()
+}
+
+// DeleteSecretSets removes the sets created before before.
+func (s *Store) DeleteSecretSets(ctx context.Context, before time.Time) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM secret_sets WHERE created_at < ?`, before.UnixMilli())
+	return err
+}
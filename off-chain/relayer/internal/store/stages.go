@@ -0,0 +1,44 @@
+package store
+
+import (
+	"context"
+	"time"
+)
+
+// RecordStage records when an order first reached a lifecycle stage. Later
+// records of the same stage are ignored.
+func (s *Store) RecordStage(ctx context.Context, orderHash, stage string, at time.Time) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO order_stages (order_hash, stage, reached_at) VALUES (?, ?, ?)
+		ON CONFLICT (order_hash, stage) DO NOTHING`,
+		orderHash, stage, at.UnixMilli(),
+	)
+	return err
+}
+
+// StageDurations returns, for every order that reached both stages, the time
+// from reaching stage from to reaching stage to. Only orders that reached
+// from at or after since are included.
+func (s *Store) StageDurations(ctx context.Context, from, to string, since time.Time) ([]time.Duration, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT b.reached_at - a.reached_at
+		FROM order_stages a
+		JOIN order_stages b ON b.order_hash = a.order_hash AND b.stage = ?
+		WHERE a.stage = ? AND a.reached_at >= ? AND b.reached_at >= a.reached_at`,
+		to, from, since.UnixMilli(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var durations []time.Duration
+	for rows.Next() {
+		var ms int64
+		if err := rows.Scan(&ms); err != nil {
+			return nil, err
+		}
+		durations = append(durations, time.Duration(ms)*time.Millisecond)
+	}
+	return durations, rows.Err()
+}
@@ -0,0 +1,99 @@
+// Package store is the relayer's persistent store, a SQLite database whose
+// schema is versioned by the embedded migrations and brought up to date when
+// it is opened.
+package store
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/pressly/goose/v3"
+	_ "modernc.org/sqlite"
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// ErrIncompatibleSchema is returned when the database was migrated by a newer
+// relayer than this one.
+var ErrIncompatibleSchema = errors.New("incompatible database schema")
+
+// Store wraps the database connection.
+type Store struct {
+	db *sql.DB
+}
+
+// Open opens the database at path, creating it if needed, and migrates it to
+// SchemaVersion. A database at a later version is refused, since its
+// migrations may have changed tables this binary relies on.
+func Open(ctx context.Context, path string) (*Store, error) {
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_pragma=foreign_keys(1)")
+	if err != nil {
+		return nil, err
+	}
+	// SQLite allows one writer, serialize rather than fail with SQLITE_BUSY
+	db.SetMaxOpenConns(1)
+
+	if err := migrate(ctx, db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &Store{db: db}, nil
+}
+
+func migrate(ctx context.Context, db *sql.DB) error {
+	migrationsFS, err := fs.Sub(migrations, "migrations")
+	if err != nil {
+		return err
+	}
+
+	provider, err := goose.NewProvider(goose.DialectSQLite3, db, migrationsFS)
+	if err != nil {
+		return fmt.Errorf("loading migrations: %w", err)
+	}
+
+	current, err := provider.GetDBVersion(ctx)
+	if err != nil {
+		return fmt.Errorf("reading schema version: %w", err)
+	}
+	if latest := SchemaVersion(); current > latest {
+		return fmt.Errorf("%w: database is at version %d, this relayer supports up to %d", ErrIncompatibleSchema, current, latest)
+	}
+
+	if _, err := provider.Up(ctx); err != nil {
+		return fmt.Errorf("migrating: %w", err)
+	}
+	return nil
+}
+
+// SchemaVersion is the latest schema version known to this binary, the
+// version of its last embedded migration.
+func SchemaVersion() int64 {
+	entries, err := fs.Glob(migrations, "migrations/*.sql")
+	if err != nil || len(entries) == 0 {
+		return 0
+	}
+
+	version, err := goose.NumericComponent(entries[len(entries)-1])
+	if err != nil {
+		return 0
+	}
+	return version
+}
+
+// Version returns the schema version of the database.
+func (s *Store) Version(ctx context.Context) (int64, error) {
+	var version int64
+	err := s.db.QueryRowContext(ctx, "SELECT COALESCE(MAX(version_id), 0) FROM goose_db_version WHERE is_applied").Scan(&version)
+	return version, err
+}
+
+// Close closes the database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
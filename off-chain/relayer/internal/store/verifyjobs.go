@@ -0,0 +1,57 @@
+package store
+
+import (
+	"context"
+	"time"
+)
+
+// VerifyJobRecord is a TXHASH event a shutdown interrupted before its fill
+// was verified, resumed at the next startup.
+type VerifyJobRecord struct {
+	OrderHash  string
+	SrcTxHash  string
+	DstTxHash  string
+	ResolverID string
+	ReceivedAt time.Time
+}
+
+// PutVerifyJob stores an unfinished verification; storing it again is a no-op.
+func (s *Store) PutVerifyJob(ctx context.Context, rec VerifyJobRecord) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO verify_jobs (order_hash, src_tx_hash, dst_tx_hash, resolver_id, received_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (order_hash, src_tx_hash, dst_tx_hash) DO NOTHING`,
+		rec.OrderHash, rec.SrcTxHash, rec.DstTxHash, rec.ResolverID, rec.ReceivedAt.UnixMilli(),
+	)
+	return err
+}
+
+// DeleteVerifyJob removes a verification once it finished.
+func (s *Store) DeleteVerifyJob(ctx context.Context, orderHash, srcTxHash, dstTxHash string) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM verify_jobs WHERE order_hash = ? AND src_tx_hash = ? AND dst_tx_hash = ?`,
+		orderHash, srcTxHash, dstTxHash)
+	return err
+}
+
+// PendingVerifyJobs returns every unfinished verification, oldest first.
+func (s *Store) PendingVerifyJobs(ctx context.Context) ([]VerifyJobRecord, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT order_hash, src_tx_hash, dst_tx_hash, resolver_id, received_at
+		FROM verify_jobs ORDER BY received_at`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobs []VerifyJobRecord
+	for rows.Next() {
+		var rec VerifyJobRecord
+		var receivedAt int64
+		if err := rows.Scan(&rec.OrderHash, &rec.SrcTxHash, &rec.DstTxHash, &rec.ResolverID, &receivedAt); err != nil {
+			return nil, err
+		}
+		rec.ReceivedAt = time.UnixMilli(receivedAt)
+		jobs = append(jobs, rec)
+	}
+	return jobs, rows.Err()
+}
@@ -0,0 +1,125 @@
+package ws
+
+import (
+	"bytes"
+	"net"
+	"relayer/internal/config"
+	"relayer/internal/manager"
+	"relayer/internal/metrics"
+	"time"
+
+	"github.com/imkira/go-ttlmap"
+	"golang.org/x/time/rate"
+)
+
+// Events a client sends may make the relayer fetch transactions from chain.
+// Each client has a budget of such calls, and clients whose events keep
+// failing are banned for a while. Both are tracked per client rather than
+// per connection, so reconnecting resets neither.
+
+// offender is the budget and recent invalid events of one client.
+type offender struct {
+	budget  *rate.Limiter
+	strikes []time.Time
+}
+
+// clientKey identifies the client behind cn: its resolver id when the
+// registry authenticated it, its IP otherwise.
+func clientKey(cn *conn) string {
+	if cn.resolver != nil {
+		return "resolver:" + cn.resolver.ID
+	}
+	return remoteKey(cn.remote)
+}
+
+func remoteKey(remote string) string {
+	host, _, err := net.SplitHostPort(remote)
+	if err != nil {
+		host = remote
+	}
+	return "ip:" + host
+}
+
+// eventCost is the number of chain calls an inbound event may cause.
+func eventCost(msg []byte) int {
+	event, _, _ := bytes.Cut(msg, []byte(" "))
+	switch string(event) {
+	case manager.TXHASH_EVENT:
+		return 2
+	case manager.CANCEL_EVENT, manager.WITHDRAW_EVENT, manager.WITHDRAWN_EVENT:
+		return 1
+	}
+	return 0
+}
+
+// banned returns when the client's ban ends, if it is banned.
+func (ws *WSServer) banned(key string) (time.Time, bool) {
+	item, err := ws.bans.Get(key)
+	if err != nil || item.Expired() {
+		return time.Time{}, false
+	}
+	return item.Expiration(), true
+}
+
+// offender returns the client's record, refreshing its ttl. The caller
+// holds abuseMu.
+func (ws *WSServer) offender(key string, cfg *config.Config) *offender {
+	var o *offender
+	if item, err := ws.offenders.Get(key); err == nil {
+		o = item.Value().(*offender)
+	} else {
+		o = &offender{budget: rate.NewLimiter(rate.Limit(cfg.VerifyRate), cfg.VerifyBurst)}
+	}
+	// pick up limits changed by a config reload
+	if o.budget.Limit() != rate.Limit(cfg.VerifyRate) || o.budget.Burst() != cfg.VerifyBurst {
+		o.budget.SetLimit(rate.Limit(cfg.VerifyRate))
+		o.budget.SetBurst(cfg.VerifyBurst)
+	}
+
+	ttl := max(time.Duration(cfg.BanWindow), OffenderTTL)
+	ws.offenders.Set(key, ttlmap.NewItem(o, ttlmap.WithTTL(ttl)), nil)
+	return o
+}
+
+// spend takes cost chain calls from the client's budget, reporting whether
+// it had enough left.
+func (ws *WSServer) spend(key string, cost int) bool {
+	ws.abuseMu.Lock()
+	defer ws.abuseMu.Unlock()
+
+	if ws.offender(key, ws.manager.Config()).budget.AllowN(time.Now(), cost) {
+		return true
+	}
+	metrics.WSBudgetExceeded.Add(1)
+	return false
+}
+
+// strike records an invalid event of the client and bans it once it sent
+// banThreshold within banWindow, returning when the ban ends.
+func (ws *WSServer) strike(key string) (time.Time, bool) {
+	cfg := ws.manager.Config()
+	if cfg.BanThreshold == 0 {
+		return time.Time{}, false
+	}
+
+	ws.abuseMu.Lock()
+	defer ws.abuseMu.Unlock()
+
+	now := time.Now()
+	o := ws.offender(key, cfg)
+	recent := o.strikes[:0]
+	for _, at := range o.strikes {
+		if now.Sub(at) < time.Duration(cfg.BanWindow) {
+			recent = append(recent, at)
+		}
+	}
+	o.strikes = append(recent, now)
+	if len(o.strikes) < cfg.BanThreshold {
+		return time.Time{}, false
+	}
+
+	o.strikes = nil
+	ws.bans.Set(key, ttlmap.NewItem(struct{}{}, ttlmap.WithTTL(time.Duration(cfg.BanDuration))), nil)
+	metrics.WSBans.Add(1)
+	return now.Add(time.Duration(cfg.BanDuration)), true
+}
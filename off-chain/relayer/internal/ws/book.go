@@ -0,0 +1,55 @@
+package ws
+
+import (
+	"encoding/json"
+	"net/http"
+	"relayer/internal/manager"
+
+	"github.com/coder/websocket"
+)
+
+// BookHandler serves the public order book feed: no credentials, nothing
+// accepted from the client. A connection first gets a BOOK frame per open
+// order, then the BOOK updates as orders are added, cancelled or expire.
+func (ws *WSServer) BookHandler(w http.ResponseWriter, r *http.Request) {
+	if n := ws.bookConns.Add(1); n > MaxBookConns {
+		ws.bookConns.Add(-1)
+		http.Error(w, "too many order book connections", http.StatusServiceUnavailable)
+		return
+	}
+	defer ws.bookConns.Add(-1)
+
+	c, err := websocket.Accept(w, r, &websocket.AcceptOptions{})
+	if err != nil {
+		http.Error(w, "WebSocket connection failed", http.StatusInternalServerError)
+		return
+	}
+	defer c.CloseNow()
+
+	// read-only: any data frame closes the connection, a close ends ctx
+	ctx := c.CloseRead(r.Context())
+
+	cn := &conn{
+		c:       c,
+		remote:  r.RemoteAddr,
+		msgChan: make(chan manager.Message, SendBufferSize),
+	}
+	cn.id = ws.manager.RegisterReceiver(cn.msgChan)
+	defer ws.manager.UnregisterReceiver(cn.id)
+
+	// joined before the snapshot so no update falls in between
+	ws.manager.JoinRoom(cn.id, manager.BookRoom)
+
+	for _, order := range ws.manager.OrderBook() {
+		data, err := json.Marshal(order)
+		if err != nil {
+			continue
+		}
+		if err := ws.write(ctx, cn, append([]byte(manager.BOOK_EVENT+" "), data...)); err != nil {
+			ws.closeAfterWriteError(cn, err)
+			return
+		}
+	}
+
+	ws.writePump(ctx, cn)
+}
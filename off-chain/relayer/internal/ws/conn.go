@@ -0,0 +1,411 @@
+package ws
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"relayer/internal/common"
+	"relayer/internal/manager"
+	"relayer/internal/resolver"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/coder/websocket"
+	"golang.org/x/time/rate"
+)
+
+// conn is a single client connection served by a read pump, which hands
+// its messages to an inbound worker, and a write pump.
+type conn struct {
+	id        uint64 // broadcast receiver id
+	c         *websocket.Conn
+	remote    string
+	msgChan   chan manager.Message
+	protocol  int // negotiated protocol version, see manager.ProtocolV1
+	sequenced bool
+	lastSeq   uint64
+
+	resolverID string
+	resolver   *resolver.Resolver // set when the registry authenticated the connection
+	limiter    *rate.Limiter
+	// read messages waiting for the inbound worker
+	inbound chan []byte
+
+	// maker session token the connection signed in with, see signIn
+	makerToken string
+}
+
+// readPump reads client frames until the connection fails, then cancels ctx so
+// the write pump stops as well. Messages are handled one at a time by the
+// inbound worker, so the reads answering the write pump's pings go on while
+// a fill is verified.
+func (ws *WSServer) readPump(ctx context.Context, cancel context.CancelFunc, cn *conn) {
+	defer cancel()
+
+	cn.inbound = make(chan []byte, InboundQueueSize)
+	defer close(cn.inbound)
+	go ws.inboundWorker(ctx, cn)
+
+	for {
+		msgType, msg, err := cn.c.Read(ctx)
+		if err != nil {
+			ws.logClosed(cn, "read", err)
+			return
+		}
+
+		if msgType != websocket.MessageText {
+			ws.logger.Println("Received non-text message, ignoring")
+			continue
+		}
+
+		// pick up limits changed by a config reload
+		if cfg := ws.manager.Config(); cn.limiter.Limit() != rate.Limit(cfg.InboundRate) || cn.limiter.Burst() != cfg.InboundBurst {
+			cn.limiter.SetLimit(rate.Limit(cfg.InboundRate))
+			cn.limiter.SetBurst(cfg.InboundBurst)
+		}
+		if !cn.limiter.Allow() {
+			ws.reject(ctx, cn, "rate limit exceeded")
+			continue
+		}
+
+		select {
+		case cn.inbound <- msg:
+		default:
+			ws.reject(ctx, cn, "too many messages in flight")
+		}
+	}
+}
+
+// inboundWorker handles the messages of the read pump in order until it
+// closes the queue. Messages queued when the connection fails are still
+// handled: their fills are verified though the answers are lost.
+func (ws *WSServer) inboundWorker(ctx context.Context, cn *conn) {
+	for msg := range cn.inbound {
+		ws.handleRecovered(ctx, cn, msg)
+	}
+}
+
+// handleRecovered handles one client message, answering with an ERROR
+// rather than taking the relayer down when handling it panics.
+func (ws *WSServer) handleRecovered(ctx context.Context, cn *conn, msg []byte) {
+	defer func() {
+		if r := recover(); r != nil {
+			ws.logger.Printf("Panic handling message from %s: %v\n%s", cn.remote, r, debug.Stack())
+			ws.reject(ctx, cn, "internal error")
+		}
+	}()
+
+	ws.handleInbound(ctx, cn, msg)
+}
+
+// handleInbound dispatches one client message. JSON control messages are
+// handled here, protocol events go to the manager.
+func (ws *WSServer) handleInbound(ctx context.Context, cn *conn, msg []byte) {
+	msg = bytes.TrimSpace(msg)
+	if len(msg) == 0 {
+		return
+	}
+
+	if msg[0] == '{' {
+		var ctrl struct {
+			Type       string `json:"type"`
+			ResolverID any    `json:"resolverId"`
+			OrderHash  string `json:"orderHash"`
+			Maker      string `json:"maker"`
+			Session    string `json:"session"`
+		}
+		if err := json.Unmarshal(msg, &ctrl); err != nil {
+			ws.reject(ctx, cn, "invalid control message")
+			return
+		}
+
+		switch ctrl.Type {
+		case "register":
+			id := fmt.Sprint(ctrl.ResolverID)
+			if cn.resolver != nil && id != cn.resolver.ID {
+				ws.reject(ctx, cn, "resolverId does not match the authenticated resolver")
+				return
+			}
+			cn.resolverID = id
+			ws.logger.Printf("Resolver %s registered from %s", cn.resolverID, cn.remote)
+		case "subscribe":
+			if ctrl.Session != "" {
+				if err := ws.signIn(cn, ctrl.Session); err != nil {
+					ws.reject(ctx, cn, err.Error())
+					return
+				}
+			}
+			if err := ws.mayWatch(cn, ctrl.OrderHash, ctrl.Maker); err != nil {
+				ws.reject(ctx, cn, err.Error())
+				return
+			}
+			for _, room := range ctrlRooms(ctrl.OrderHash, ctrl.Maker) {
+				if err := ws.join(cn, room); err != nil {
+					ws.reject(ctx, cn, err.Error())
+					return
+				}
+			}
+		case "unsubscribe":
+			for _, room := range ctrlRooms(ctrl.OrderHash, ctrl.Maker) {
+				ws.manager.LeaveRoom(cn.id, room)
+			}
+		default:
+			ws.reject(ctx, cn, "unknown control message type: "+ctrl.Type)
+		}
+		return
+	}
+
+	// lookups are answered in-band to the asking connection only
+	if event, orderHash, _ := bytes.Cut(msg, []byte(" ")); string(event) == manager.GET_ORDER_EVENT {
+		ws.answerGetOrder(ctx, cn, string(bytes.TrimSpace(orderHash)))
+		return
+	}
+
+	ws.logger.Printf("Received message from %s: %s", cn.remote, msg)
+
+	key := clientKey(cn)
+	cost := eventCost(msg)
+	if cost > 0 {
+		if until, ok := ws.banned(key); ok {
+			ws.reject(ctx, cn, fmt.Sprintf("banned for invalid events until %d", until.Unix()))
+			return
+		}
+		if !ws.spend(key, cost) {
+			ws.reject(ctx, cn, "verification budget exceeded")
+			return
+		}
+	}
+
+	if err := ws.manager.HandleReceiveEvent(cn.resolver, msg); err != nil {
+		ws.reject(ctx, cn, err.Error())
+		// fills not verifiable yet are not the client's fault
+		if cost == 0 || errors.Is(err, manager.ErrVerifyRetry) {
+			return
+		}
+		if until, banned := ws.strike(key); banned {
+			ws.logger.Printf("Banned %s until %s for invalid events", key, until.Format(time.RFC3339))
+			cn.c.Close(websocket.StatusPolicyViolation, fmt.Sprintf("banned for invalid events until %d", until.Unix()))
+		}
+	}
+}
+
+// answerGetOrder replies to GET_ORDER <ORDER_HASH_HEX> with the order's
+// ORDER_DETAIL, or an ERROR naming the hash when the relayer does not know it.
+func (ws *WSServer) answerGetOrder(ctx context.Context, cn *conn, orderHash string) {
+	if orderHash == "" || strings.ContainsRune(orderHash, ' ') {
+		ws.reject(ctx, cn, "invalid get order event format, expected GET_ORDER <orderHash>")
+		return
+	}
+
+	if err := ws.mayGetOrder(cn, orderHash); err != nil {
+		ws.reject(ctx, cn, err.Error())
+		return
+	}
+
+	detail, err := ws.manager.OrderDetailEvent(orderHash)
+	if err != nil {
+		ws.reject(ctx, cn, "order not found: "+orderHash)
+		return
+	}
+	if err := ws.write(ctx, cn, detail); err != nil {
+		ws.logClosed(cn, "write", err)
+	}
+}
+
+// ctrlRooms are the rooms named by a subscribe or unsubscribe message.
+func ctrlRooms(orderHash, maker string) []string {
+	rooms := make([]string, 0, 2)
+	if orderHash != "" {
+		rooms = append(rooms, manager.OrderRoom(orderHash))
+	}
+	if maker != "" {
+		rooms = append(rooms, manager.MakerRoom(maker))
+	}
+	return rooms
+}
+
+// signIn authenticates the connection's maker with a session token from
+// POST /orders/v1.0/session.
+func (ws *WSServer) signIn(cn *conn, token string) error {
+	if _, err := ws.manager.Sessions().Authenticate(token, time.Now()); err != nil {
+		return fmt.Errorf("invalid maker session: %w", err)
+	}
+	cn.makerToken = token
+	return nil
+}
+
+// mayWatch checks that the connection may follow the updates of an order
+// and of a maker, either possibly empty. With PRIVATE_ORDER_STATUS only the
+// maker's session may; orders of other makers are reported as not found, as
+// by the API, so their hashes cannot be probed.
+func (ws *WSServer) mayWatch(cn *conn, orderHash, maker string) error {
+	if !ws.manager.PrivateStatus() || (orderHash == "" && maker == "") {
+		return nil
+	}
+
+	signedIn, err := ws.signedIn(cn)
+	if err != nil {
+		return err
+	}
+	if maker != "" && !common.SameAddress(maker, signedIn) {
+		return errors.New("maker does not match the session")
+	}
+	if orderHash != "" {
+		return ws.ownsOrder(signedIn, orderHash)
+	}
+	return nil
+}
+
+// mayGetOrder checks that the connection may look an order up with
+// GET_ORDER: authenticated resolvers may, otherwise only the order's maker.
+// Without a resolver registry every client counts as a resolver, unless
+// order status is private.
+func (ws *WSServer) mayGetOrder(cn *conn, orderHash string) error {
+	if cn.resolver != nil || (!ws.manager.Resolvers().Enabled() && !ws.manager.PrivateStatus()) {
+		return nil
+	}
+
+	signedIn, err := ws.signedIn(cn)
+	if err != nil {
+		return err
+	}
+	return ws.ownsOrder(signedIn, orderHash)
+}
+
+// signedIn returns the maker the connection signed in as. The session is
+// checked again each time, it may have expired since signing in.
+func (ws *WSServer) signedIn(cn *conn) (string, error) {
+	maker, err := ws.manager.Sessions().Authenticate(cn.makerToken, time.Now())
+	if err != nil {
+		return "", errors.New("a maker session is required")
+	}
+	return maker, nil
+}
+
+// ownsOrder checks that maker made the order, reporting orders of other
+// makers as not found.
+func (ws *WSServer) ownsOrder(maker, orderHash string) error {
+	orderMaker, err := ws.manager.OrderMaker(orderHash)
+	if err != nil || !common.SameAddress(orderMaker, maker) {
+		return errors.New("order not found: " + orderHash)
+	}
+	return nil
+}
+
+// join subscribes the connection to a room, up to MaxRoomsPerConn rooms.
+func (ws *WSServer) join(cn *conn, room string) error {
+	if n := ws.manager.JoinRoom(cn.id, room); n > MaxRoomsPerConn {
+		ws.manager.LeaveRoom(cn.id, room)
+		return fmt.Errorf("too many subscriptions, at most %d per connection", MaxRoomsPerConn)
+	}
+	return nil
+}
+
+// reject replies to the client with an ERROR event describing why its message was dropped.
+func (ws *WSServer) reject(ctx context.Context, cn *conn, reason string) {
+	ws.logger.Printf("Rejected message from %s: %s", cn.remote, reason)
+
+	if err := ws.write(ctx, cn, []byte(manager.ERROR_EVENT+" "+reason)); err != nil {
+		ws.logClosed(cn, "write", err)
+	}
+}
+
+// writePump delivers broadcast messages and keepalive pings until ctx is done
+// or the manager closes the receiver channel. Ping round trips of
+// authenticated resolvers are accounted in their liveness.
+func (ws *WSServer) writePump(ctx context.Context, cn *conn) {
+	ticker := time.NewTicker(PingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case m, ok := <-cn.msgChan:
+			if !ok {
+				// the manager is shutting down, let the client reconnect elsewhere
+				cn.c.Close(websocket.StatusGoingAway, "relayer shutting down")
+				return
+			}
+
+			if cn.sequenced {
+				// already delivered during replay
+				if m.Seq <= cn.lastSeq {
+					continue
+				}
+				cn.lastSeq = m.Seq
+			}
+
+			if err := ws.write(ctx, cn, frame(m, cn)); err != nil {
+				ws.closeAfterWriteError(cn, err)
+				return
+			}
+		case <-ticker.C:
+			pingCtx, cancel := context.WithTimeout(ctx, WriteTimeout)
+			sent := time.Now()
+			err := cn.c.Ping(pingCtx)
+			cancel()
+			if err != nil {
+				if cn.resolver != nil && ctx.Err() == nil {
+					ws.manager.Liveness().PingFailed(cn.resolverID)
+				}
+				ws.closeAfterWriteError(cn, err)
+				return
+			}
+			if cn.resolver != nil {
+				ws.manager.Liveness().Pinged(cn.resolverID, time.Since(sent), time.Now())
+			}
+		}
+	}
+}
+
+// write sends one text frame bounded by WriteTimeout.
+func (ws *WSServer) write(ctx context.Context, cn *conn, data []byte) error {
+	writeCtx, cancel := context.WithTimeout(ctx, WriteTimeout)
+	defer cancel()
+
+	return cn.c.Write(writeCtx, websocket.MessageText, data)
+}
+
+// closeAfterWriteError picks a close code the client can act on: slow or
+// unresponsive clients are told to retry later, anything else is logged.
+func (ws *WSServer) closeAfterWriteError(cn *conn, err error) {
+	if errors.Is(err, context.DeadlineExceeded) {
+		ws.logger.Printf("WebSocket write to %s timed out, closing", cn.remote)
+		cn.c.Close(websocket.StatusTryAgainLater, "write timeout")
+		return
+	}
+
+	ws.logClosed(cn, "write", err)
+}
+
+// logClosed classifies connection errors so that normal disconnects do not
+// show up as failures.
+func (ws *WSServer) logClosed(cn *conn, op string, err error) {
+	switch status := websocket.CloseStatus(err); {
+	case status == websocket.StatusNormalClosure || status == websocket.StatusGoingAway:
+		ws.logger.Printf("WebSocket client %s disconnected (%d)", cn.remote, status)
+	case errors.Is(err, context.Canceled):
+		// the other pump already ended the connection
+	case status != -1:
+		ws.logger.Printf("WebSocket client %s closed with status %d: %v", cn.remote, status, err)
+	default:
+		ws.logger.Printf("WebSocket %s error for %s: %v", op, cn.remote, err)
+	}
+}
+
+// frame renders a broadcast message for the wire in the connection's protocol
+// version, prefixing the sequence number for clients that opted into
+// sequenced delivery.
+func frame(m manager.Message, cn *conn) []byte {
+	data := m.Encode(cn.protocol)
+	if !cn.sequenced {
+		return data
+	}
+
+	prefix := fmt.Sprintf("%s %d ", manager.SEQ_PREFIX, m.Seq)
+	return append([]byte(prefix), data...)
+}
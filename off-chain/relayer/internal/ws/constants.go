@@ -0,0 +1,35 @@
+package ws
+
+import "time"
+
+const (
+	// WriteTimeout bounds a single frame write to a client
+	WriteTimeout = time.Second * 10
+	// PingInterval is how often idle connections are pinged to detect dead peers
+	PingInterval = time.Second * 30
+	// SendBufferSize is the number of outbound messages buffered per connection
+	SendBufferSize = 64
+	// InboundQueueSize is the number of client messages queued per connection
+	// behind the one being handled, which may be a TXHASH verification
+	InboundQueueSize = 16
+
+	// MaxMessageSize is the largest inbound frame accepted from a client, in bytes
+	MaxMessageSize = 4096
+	// MaxRoomsPerConn caps the order and maker rooms a connection may join
+	MaxRoomsPerConn = 32
+
+	// MaxBookConns caps the unauthenticated public order book connections
+	MaxBookConns = 256
+
+	// SessionGrace is how long after a disconnect a resolver may resume its
+	// session with the token it was issued
+	SessionGrace = time.Minute * 2
+
+	// OffenderTTL is how long the chain call budget and invalid events of an
+	// idle client are remembered, at least the config's banWindow
+	OffenderTTL = time.Minute * 10
+
+	// ProtocolsHeader lists the protocol subprotocols the relayer speaks on
+	// the handshake response
+	ProtocolsHeader = "X-Fission-Protocols"
+)
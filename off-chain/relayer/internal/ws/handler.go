@@ -1,17 +1,26 @@
 package ws
 
 import (
+	"context"
+	"fmt"
 	"net/http"
+	"relayer/internal/manager"
+	"relayer/internal/resolver"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/coder/websocket"
+	"golang.org/x/time/rate"
 )
 
 func (ws *WSServer) Serve() http.Handler {
 	ws.logger.Println("WebSocket server listening on port", ws.port)
 	mux := http.NewServeMux()
 
-	// main and only route for the WebSocket server
+	// resolvers and frontends on /, the public order book on /book
 	mux.HandleFunc("/", ws.MainHandler)
+	mux.HandleFunc("/book", ws.BookHandler)
 	ws.logger.Println("WebSocket server routes registered.")
 
 	// Wrap the mux with CORS middleware
@@ -39,48 +48,175 @@ func (ws *WSServer) corsMiddleware(next http.Handler) http.Handler {
 func (ws *WSServer) MainHandler(w http.ResponseWriter, r *http.Request) {
 	ws.logger.Println("WebSocket connection request received from", r.RemoteAddr)
 
+	// With a resolver registry configured, only registered resolvers may
+	// connect. Resolvers resuming a session with ?resume=<token> may skip the
+	// API key, which is then only used if the session expired.
+	query := r.URL.Query()
+	resume := query.Get("resume")
+	registry := ws.manager.Resolvers()
+	var res *resolver.Resolver
+	if apiKey, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); registry.Enabled() && (apiKey != "" || resume == "") {
+		var ok bool
+		if res, ok = registry.Authenticate(apiKey); !ok {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+	}
+
+	// banned clients may not reconnect until their ban ends, resolvers
+	// resuming a session without their API key included
+	key := remoteKey(r.RemoteAddr)
+	switch {
+	case res != nil:
+		key = "resolver:" + res.ID
+	case resume != "":
+		if id, ok := ws.sessionResolver(resume); ok && id != "" {
+			key = "resolver:" + id
+		}
+	}
+	if until, ok := ws.banned(key); ok {
+		http.Error(w, fmt.Sprintf("banned for invalid events until %d", until.Unix()), http.StatusForbidden)
+		return
+	}
+
+	// Clients offer the protocol versions they speak as fission.v<N>
+	// subprotocols, the newest both sides speak is used. Clients offering none
+	// get ProtocolV1; the header advertises what the relayer speaks.
+	w.Header().Set(ProtocolsHeader, strings.Join(manager.Subprotocols(), ", "))
+
 	// Upgrade the HTTP connection to a WebSocket connection
-	c, err := websocket.Accept(w, r, &websocket.AcceptOptions{})
+	c, err := websocket.Accept(w, r, &websocket.AcceptOptions{Subprotocols: manager.Subprotocols()})
 	if err != nil {
 		http.Error(w, "WebSocket connection failed", http.StatusInternalServerError)
 		return
 	}
 	defer c.CloseNow()
+	c.SetReadLimit(MaxMessageSize)
 
-	msgChan := make(chan []byte)
-	id := ws.manager.RegisterReceiver(msgChan)
-	defer ws.manager.UnregisterReceiver(id)
+	protocol, err := manager.ParseSubprotocol(c.Subprotocol())
+	if err != nil {
+		c.Close(websocket.StatusPolicyViolation, err.Error())
+		return
+	}
 
-	// Start a goroutine for reading messages from the client
-	go func() {
-		for {
-			msgType, msg, err := c.Read(r.Context())
-			if err != nil {
-				ws.logger.Printf("WebSocket read error: %v", err)
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	var sess *session
+	if resume != "" {
+		if sess, err = ws.resumeSession(resume, cancel); err != nil {
+			ws.logger.Printf("Session resume from %s failed: %v", r.RemoteAddr, err)
+			if registry.Enabled() && res == nil {
+				c.Close(websocket.StatusPolicyViolation, err.Error())
 				return
 			}
+		}
+	}
 
-			if msgType != websocket.MessageText {
-				ws.logger.Println("Received non-text message, ignoring")
-				continue
-			}
+	cfg := ws.manager.Config()
+	cn := &conn{
+		c:        c,
+		remote:   r.RemoteAddr,
+		msgChan:  make(chan manager.Message, SendBufferSize),
+		protocol: protocol,
+		resolver: res,
+		limiter:  rate.NewLimiter(rate.Limit(cfg.InboundRate), cfg.InboundBurst),
+	}
+	if res != nil {
+		cn.resolverID = res.ID
+	}
+	if sess != nil {
+		cn.resolver, cn.resolverID, cn.limiter = sess.resolver, sess.resolverID, sess.limiter
+		cn.sequenced, cn.lastSeq = sess.sequenced, sess.lastSeq
+		ws.logger.Printf("Resolver %s resumed its session from %s", cn.resolverID, cn.remote)
+	}
+
+	// Clients that pass ?since=<seq> get sequenced frames and a replay of the
+	// retained messages they missed
+	if since := query.Get("since"); since != "" {
+		cn.lastSeq, err = strconv.ParseUint(since, 10, 64)
+		if err != nil {
+			c.Close(websocket.StatusPolicyViolation, "invalid since parameter")
+			return
+		}
+		cn.sequenced = true
+	}
+
+	if c.Subprotocol() != "" {
+		hello := fmt.Sprintf("%s %d %s", manager.PROTOCOL_EVENT, protocol, strings.Join(manager.Subprotocols(), ","))
+		if err := ws.write(ctx, cn, []byte(hello)); err != nil {
+			ws.closeAfterWriteError(cn, err)
+			return
+		}
+	}
+
+	cn.id = ws.manager.RegisterReceiver(cn.msgChan)
+	defer ws.manager.UnregisterReceiver(cn.id)
 
-			ws.logger.Println("Received Text Message:", string(msg))
-			ws.manager.HandleReceiveEvent(msg)
+	// Authenticated resolvers get a session to resume after a disconnect
+	if sess == nil && cn.resolver != nil {
+		sess = ws.newSession(cn, cancel)
+	}
+	if sess != nil {
+		// runs before the receiver is unregistered, while its rooms are known
+		defer ws.detach(sess, cn)
+
+		for _, room := range sess.rooms {
+			ws.join(cn, room)
 		}
-	}()
+		if err := ws.write(ctx, cn, []byte(manager.SESSION_EVENT+" "+sess.token)); err != nil {
+			ws.closeAfterWriteError(cn, err)
+			return
+		}
+	}
+
+	// authenticated resolvers' connections count towards their liveness
+	if cn.resolver != nil {
+		live := ws.manager.Liveness()
+		live.Connected(cn.resolverID, time.Now())
+		defer func() { live.Disconnected(cn.resolverID, time.Now()) }()
+	}
 
-	// Main loop for writing messages to the client
-	for {
-		select {
-		case <-r.Context().Done():
-			// Client disconnected
+	// Frontends pass ?order=<hash> and/or ?maker=<address> to only receive the
+	// updates of their own orders, signed in with ?session=<token> when order
+	// status is private
+	if token := query.Get("session"); token != "" {
+		if err := ws.signIn(cn, token); err != nil {
+			c.Close(websocket.StatusPolicyViolation, err.Error())
 			return
-		case m := <-msgChan:
-			if err := c.Write(r.Context(), websocket.MessageText, m); err != nil {
-				ws.logger.Printf("Failed to write message: %v", err)
+		}
+	}
+	for _, hash := range query["order"] {
+		if err := ws.mayWatch(cn, hash, ""); err != nil {
+			c.Close(websocket.StatusPolicyViolation, err.Error())
+			return
+		}
+		if err := ws.join(cn, manager.OrderRoom(hash)); err != nil {
+			c.Close(websocket.StatusPolicyViolation, err.Error())
+			return
+		}
+	}
+	for _, maker := range query["maker"] {
+		if err := ws.mayWatch(cn, "", maker); err != nil {
+			c.Close(websocket.StatusPolicyViolation, err.Error())
+			return
+		}
+		if err := ws.join(cn, manager.MakerRoom(maker)); err != nil {
+			c.Close(websocket.StatusPolicyViolation, err.Error())
+			return
+		}
+	}
+
+	if cn.sequenced {
+		for _, m := range ws.manager.Replay(cn.id, cn.lastSeq) {
+			if err := ws.write(ctx, cn, frame(m, cn)); err != nil {
+				ws.closeAfterWriteError(cn, err)
 				return
 			}
+			cn.lastSeq = m.Seq
 		}
 	}
+
+	go ws.readPump(ctx, cancel, cn)
+	ws.writePump(ctx, cn)
 }
@@ -7,8 +7,11 @@ import (
 	"os"
 	"relayer/internal/manager"
 	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/imkira/go-ttlmap"
 	_ "github.com/joho/godotenv/autoload"
 )
 
@@ -16,6 +19,18 @@ type WSServer struct {
 	port    int
 	manager *manager.Manager
 	logger  *log.Logger
+
+	// resumable resolver sessions by token, see session.go
+	sessionsMu sync.Mutex
+	sessions   *ttlmap.Map
+
+	// open public order book connections, see book.go
+	bookConns atomic.Int64
+
+	// chain call budgets, invalid events and bans per client, see abuse.go
+	abuseMu   sync.Mutex
+	offenders *ttlmap.Map
+	bans      *ttlmap.Map
 }
 
 func NewWSServer(manager *manager.Manager, logger *log.Logger) *http.Server {
@@ -24,6 +39,10 @@ func NewWSServer(manager *manager.Manager, logger *log.Logger) *http.Server {
 		port:    port,
 		manager: manager,
 		logger:  logger,
+
+		sessions:  ttlmap.New(&ttlmap.Options{InitialCapacity: 32}),
+		offenders: ttlmap.New(&ttlmap.Options{InitialCapacity: 32}),
+		bans:      ttlmap.New(&ttlmap.Options{InitialCapacity: 8}),
 	}
 
 	// Declare Server config
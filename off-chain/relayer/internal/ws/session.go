@@ -0,0 +1,128 @@
+package ws
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"relayer/internal/bus"
+	"relayer/internal/resolver"
+	"time"
+
+	"github.com/imkira/go-ttlmap"
+	"golang.org/x/time/rate"
+)
+
+var errUnknownSession = errors.New("unknown or expired session")
+
+// session is the state an authenticated resolver connection carries over to
+// the next one: its identity, subscriptions, sequence cursor and rate limit
+// bucket. Fields are guarded by WSServer.sessionsMu.
+type session struct {
+	token      string
+	resolver   *resolver.Resolver
+	resolverID string
+	limiter    *rate.Limiter
+	rooms      []string
+	sequenced  bool
+	lastSeq    uint64
+
+	// set while a connection uses the session, closed once it saved its state
+	active   chan struct{}
+	stop     func()
+	resuming bool
+}
+
+// newSession issues a session to a connection that just authenticated.
+func (ws *WSServer) newSession(cn *conn, stop func()) *session {
+	s := &session{resolver: cn.resolver, resolverID: cn.resolverID, limiter: cn.limiter}
+
+	ws.sessionsMu.Lock()
+	defer ws.sessionsMu.Unlock()
+
+	ws.attach(s, stop)
+	return s
+}
+
+// sessionResolver returns the id of the resolver the session of token
+// belongs to, without taking the session over.
+func (ws *WSServer) sessionResolver(token string) (string, bool) {
+	ws.sessionsMu.Lock()
+	defer ws.sessionsMu.Unlock()
+
+	item, err := ws.sessions.Get(token)
+	if err != nil {
+		return "", false
+	}
+	return item.Value().(*session).resolverID, true
+}
+
+// resumeSession takes over the session of token with a new connection. A
+// connection still using it, e.g. one whose peer vanished without closing, is
+// stopped first. The token is rotated, every token resumes once.
+func (ws *WSServer) resumeSession(token string, stop func()) (*session, error) {
+	ws.sessionsMu.Lock()
+	defer ws.sessionsMu.Unlock()
+
+	item, err := ws.sessions.Get(token)
+	if err != nil {
+		return nil, errUnknownSession
+	}
+	s := item.Value().(*session)
+	if s.resuming {
+		return nil, errUnknownSession
+	}
+
+	if s.active != nil {
+		s.resuming = true
+		active := s.active
+		s.stop()
+
+		ws.sessionsMu.Unlock()
+		select {
+		case <-active:
+		case <-time.After(WriteTimeout):
+		}
+		ws.sessionsMu.Lock()
+
+		s.resuming = false
+		if s.active == active {
+			return nil, errors.New("previous connection of the session did not close")
+		}
+	}
+
+	ws.sessions.Delete(token)
+	ws.attach(s, stop)
+	return s, nil
+}
+
+// attach binds s to a connection under a fresh token that stays valid while
+// the connection lasts. Callers hold sessionsMu.
+func (ws *WSServer) attach(s *session, stop func()) {
+	s.token = newSessionToken()
+	s.active = make(chan struct{})
+	s.stop = stop
+	ws.sessions.Set(s.token, ttlmap.NewItem(s, nil), nil)
+}
+
+// detach saves the state of the connection ending and keeps the session
+// resumable for SessionGrace. The disconnect is published to the bus.
+func (ws *WSServer) detach(s *session, cn *conn) {
+	ws.manager.Events().Publish(bus.Event{Kind: bus.ResolverDisconnected, Subject: cn.resolverID})
+	rooms := ws.manager.Rooms(cn.id)
+
+	ws.sessionsMu.Lock()
+	defer ws.sessionsMu.Unlock()
+
+	s.rooms = rooms
+	s.sequenced = cn.sequenced
+	s.lastSeq = cn.lastSeq
+	close(s.active)
+	s.active, s.stop = nil, nil
+	ws.sessions.Set(s.token, ttlmap.NewItem(s, ttlmap.WithTTL(SessionGrace)), nil)
+}
+
+func newSessionToken() string {
+	b := make([]byte, 32)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
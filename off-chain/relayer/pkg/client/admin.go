@@ -0,0 +1,215 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Admin endpoints, authenticated with Client.AdminKey.
+
+// ListOrders returns a summary of every live order.
+func (c *Client) ListOrders(ctx context.Context) ([]AdminOrder, error) {
+	var resp struct {
+		Orders []AdminOrder `json:"orders"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/admin/v1.0/orders", nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Orders, nil
+}
+
+// OrderSearch filters an order search. Zero fields do not filter; Sort is
+// submittedAt, the default, or makingAmount.
+type OrderSearch struct {
+	Statuses  []string
+	SrcChain  string
+	DstChain  string
+	Token     string
+	Maker     string
+	From      time.Time
+	To        time.Time
+	MinAmount string
+	MaxAmount string
+	Sort      string
+	Ascending bool
+	Cursor    string
+	Limit     int
+}
+
+// SearchOrders returns a page of the stored orders matching search. Pass the
+// result's NextCursor as search.Cursor for the next page.
+func (c *Client) SearchOrders(ctx context.Context, search OrderSearch) (*OrderSearchResult, error) {
+	values := url.Values{}
+	if len(search.Statuses) > 0 {
+		values.Set("status", strings.Join(search.Statuses, ","))
+	}
+	for param, v := range map[string]string{
+		"srcChain":  search.SrcChain,
+		"dstChain":  search.DstChain,
+		"token":     search.Token,
+		"maker":     search.Maker,
+		"minAmount": search.MinAmount,
+		"maxAmount": search.MaxAmount,
+		"sort":      search.Sort,
+		"cursor":    search.Cursor,
+	} {
+		if v != "" {
+			values.Set(param, v)
+		}
+	}
+	if !search.From.IsZero() {
+		values.Set("from", search.From.Format(time.RFC3339))
+	}
+	if !search.To.IsZero() {
+		values.Set("to", search.To.Format(time.RFC3339))
+	}
+	if search.Ascending {
+		values.Set("order", "asc")
+	}
+	if search.Limit > 0 {
+		values.Set("limit", strconv.Itoa(search.Limit))
+	}
+
+	var result OrderSearchResult
+	if err := c.do(ctx, http.MethodGet, "/orders/v1.0/search?"+values.Encode(), nil, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// InspectOrder returns the relayer state of an order, including archived ones.
+func (c *Client) InspectOrder(ctx context.Context, orderHash string) (*AdminOrder, error) {
+	var order AdminOrder
+	if err := c.do(ctx, http.MethodGet, "/admin/v1.0/orders/"+orderHash, nil, &order); err != nil {
+		return nil, err
+	}
+
+	return &order, nil
+}
+
+// InspectQuote returns a cached quote with the request it was issued for.
+func (c *Client) InspectQuote(ctx context.Context, quoteID string) (*AdminQuote, error) {
+	var quote AdminQuote
+	if err := c.do(ctx, http.MethodGet, "/admin/v1.0/quotes/"+quoteID, nil, &quote); err != nil {
+		return nil, err
+	}
+
+	return &quote, nil
+}
+
+// Reverify makes the relayer verify a fill again, bypassing its cache.
+func (c *Client) Reverify(ctx context.Context, orderHash, srcTxHash, dstTxHash string) error {
+	body, err := json.Marshal(reverifyRequest{SrcTxHash: srcTxHash, DstTxHash: dstTxHash})
+	if err != nil {
+		return err
+	}
+
+	return c.do(ctx, http.MethodPost, "/admin/v1.0/orders/"+orderHash+"/reverify", body, nil)
+}
+
+// ReleaseFill marks a fill ready to accept its secret without verification.
+func (c *Client) ReleaseFill(ctx context.Context, orderHash string, fill ReadyToAcceptSecretFill) error {
+	body, err := json.Marshal(fill)
+	if err != nil {
+		return err
+	}
+
+	return c.do(ctx, http.MethodPost, "/admin/v1.0/orders/"+orderHash+"/release", body, nil)
+}
+
+// Quarantined lists the orders held back for a price off the oracle's.
+func (c *Client) Quarantined(ctx context.Context) ([]QuarantinedOrder, error) {
+	var resp struct {
+		Orders []QuarantinedOrder `json:"orders"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/admin/v1.0/quarantine", nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Orders, nil
+}
+
+// ApproveQuarantined broadcasts a quarantined order.
+func (c *Client) ApproveQuarantined(ctx context.Context, orderHash string) error {
+	return c.do(ctx, http.MethodPost, "/admin/v1.0/quarantine/"+orderHash+"/approve", nil, nil)
+}
+
+// RejectQuarantined drops a quarantined order.
+func (c *Client) RejectQuarantined(ctx context.Context, orderHash string) error {
+	return c.do(ctx, http.MethodPost, "/admin/v1.0/quarantine/"+orderHash+"/reject", nil, nil)
+}
+
+// ChainHealth reports connectivity of the relayer's chain RPC endpoints.
+func (c *Client) ChainHealth(ctx context.Context) ([]ChainHealth, error) {
+	var resp struct {
+		Chains []ChainHealth `json:"chains"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/admin/v1.0/chains", nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Chains, nil
+}
+
+// ResolverStats ranks the authenticated resolvers by their liveness score.
+func (c *Client) ResolverStats(ctx context.Context) ([]ResolverStats, error) {
+	var resp struct {
+		Resolvers []ResolverStats `json:"resolvers"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/admin/v1.0/resolvers", nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Resolvers, nil
+}
+
+// SafetyDeposits reports the configured chains' safety deposit parameters
+// and the deposits their quotes currently get.
+func (c *Client) SafetyDeposits(ctx context.Context) ([]SafetyDepositQuote, error) {
+	var resp struct {
+		SafetyDeposits []SafetyDepositQuote `json:"safetyDeposits"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/admin/v1.0/safety-deposits", nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.SafetyDeposits, nil
+}
+
+// UpstreamKeys reports the requests and throttling of every 1inch API key.
+func (c *Client) UpstreamKeys(ctx context.Context) ([]UpstreamKeyUsage, error) {
+	var resp struct {
+		Keys []UpstreamKeyUsage `json:"keys"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/admin/v1.0/upstream-keys", nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Keys, nil
+}
+
+// GetConfig returns the relayer's active runtime config.
+func (c *Client) GetConfig(ctx context.Context) (*Config, error) {
+	var cfg Config
+	if err := c.do(ctx, http.MethodGet, "/admin/v1.0/config", nil, &cfg); err != nil {
+		return nil, err
+	}
+
+	return &cfg, nil
+}
+
+// ReloadConfig makes the relayer re-read its config file and returns the result.
+func (c *Client) ReloadConfig(ctx context.Context) (*Config, error) {
+	var cfg Config
+	if err := c.do(ctx, http.MethodPost, "/admin/v1.0/config/reload", nil, &cfg); err != nil {
+		return nil, err
+	}
+
+	return &cfg, nil
+}
@@ -0,0 +1,251 @@
+// Package client is a Go SDK for resolvers and frontends talking to the relayer.
+// Client wraps the REST API and Stream wraps the WebSocket event protocol.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/schema"
+)
+
+// Client is a thin typed wrapper around the relayer REST API.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+
+	// AdminKey is sent as a bearer token, required by the admin endpoints.
+	AdminKey string
+	// SessionToken is sent as a bearer token without an AdminKey, required
+	// by the order status endpoints of relayers with PRIVATE_ORDER_STATUS.
+	SessionToken string
+}
+
+// New creates a client for the relayer API served at baseURL
+// (e.g. "http://localhost:8080"). A nil httpClient uses a client with a 30s timeout.
+func New(baseURL string, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	return &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: httpClient,
+	}
+}
+
+var encoder = schema.NewEncoder()
+
+// GetQuote requests a quote; the returned QuoteID must be used when submitting the order.
+func (c *Client) GetQuote(ctx context.Context, params QuoteRequestParams) (*Quote, error) {
+	values := url.Values{}
+	if err := encoder.Encode(params, values); err != nil {
+		return nil, fmt.Errorf("encoding quote params: %w", err)
+	}
+
+	var quote Quote
+	if err := c.do(ctx, http.MethodGet, "/quoter/v1.0/quote/receive?"+values.Encode(), nil, &quote); err != nil {
+		return nil, err
+	}
+
+	return &quote, nil
+}
+
+// SubmitOrder submits a signed order for broadcast to resolvers.
+func (c *Client) SubmitOrder(ctx context.Context, order Order) error {
+	body, err := encodeOrder(order)
+	if err != nil {
+		return err
+	}
+
+	return c.do(ctx, http.MethodPost, "/relayer/v1.0/submit", body, nil)
+}
+
+// SubmitSecret shares the maker's secret for an order once its escrows are ready.
+func (c *Client) SubmitSecret(ctx context.Context, orderHash, secret string) error {
+	body, err := json.Marshal(map[string]string{"orderHash": orderHash, "secret": secret})
+	if err != nil {
+		return err
+	}
+
+	return c.do(ctx, http.MethodPost, "/relayer/v1.0/submit/secret", body, nil)
+}
+
+// GetOrderStatus returns the current status of an order.
+func (c *Client) GetOrderStatus(ctx context.Context, orderHash string) (*OrderStatus, error) {
+	var status OrderStatus
+	if err := c.do(ctx, http.MethodGet, "/orders/v1.0/order/status/"+orderHash, nil, &status); err != nil {
+		return nil, err
+	}
+
+	return &status, nil
+}
+
+// GetReadyToAcceptSecretFills returns the fills whose escrows have been verified
+// since the last call.
+func (c *Client) GetReadyToAcceptSecretFills(ctx context.Context, orderHash string) (*ReadyToAcceptSecretFills, error) {
+	var fills ReadyToAcceptSecretFills
+	if err := c.do(ctx, http.MethodGet, "/orders/v1.0/order/ready-to-accept-secret-fills/"+orderHash, nil, &fills); err != nil {
+		return nil, err
+	}
+
+	return &fills, nil
+}
+
+// GenerateSecrets asks the relayer to generate and hold the secrets of an order
+// built from quoteID. Keep the returned ExportToken to export them later.
+func (c *Client) GenerateSecrets(ctx context.Context, req SecretsRequest) (*SecretSet, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var set SecretSet
+	if err := c.do(ctx, http.MethodPost, "/relayer/v1.0/secrets", body, &set); err != nil {
+		return nil, err
+	}
+
+	return &set, nil
+}
+
+// ExportSecrets retrieves the secrets the relayer holds for a maker.
+func (c *Client) ExportSecrets(ctx context.Context, secretsID, exportToken string) (*SecretSet, error) {
+	body, err := json.Marshal(map[string]string{"exportToken": exportToken})
+	if err != nil {
+		return nil, err
+	}
+
+	var set SecretSet
+	if err := c.do(ctx, http.MethodPost, "/relayer/v1.0/secrets/"+url.PathEscape(secretsID)+"/export", body, &set); err != nil {
+		return nil, err
+	}
+
+	return &set, nil
+}
+
+// SessionChallenge requests the message a maker signs to sign in.
+func (c *Client) SessionChallenge(ctx context.Context, address string) (*SessionChallenge, error) {
+	body, err := json.Marshal(map[string]string{"address": address})
+	if err != nil {
+		return nil, err
+	}
+
+	var challenge SessionChallenge
+	if err := c.do(ctx, http.MethodPost, "/orders/v1.0/session/challenge", body, &challenge); err != nil {
+		return nil, err
+	}
+
+	return &challenge, nil
+}
+
+// SignIn trades the maker's signature of a challenge message for a session,
+// whose token the client sends from then on.
+func (c *Client) SignIn(ctx context.Context, nonce, signature string) (*Session, error) {
+	body, err := json.Marshal(map[string]string{"nonce": nonce, "signature": signature})
+	if err != nil {
+		return nil, err
+	}
+
+	var session Session
+	if err := c.do(ctx, http.MethodPost, "/orders/v1.0/session", body, &session); err != nil {
+		return nil, err
+	}
+	c.SessionToken = session.Token
+
+	return &session, nil
+}
+
+// GetOrderEscrows returns the escrows of an order's verified fills with the
+// immutables withdrawing from or cancelling them takes.
+func (c *Client) GetOrderEscrows(ctx context.Context, orderHash string) (*OrderEscrows, error) {
+	var escrows OrderEscrows
+	if err := c.do(ctx, http.MethodGet, "/orders/v1.0/order/escrow/"+orderHash, nil, &escrows); err != nil {
+		return nil, err
+	}
+
+	return &escrows, nil
+}
+
+// Chains lists the chains the relayer serves with their escrow factories.
+func (c *Client) Chains(ctx context.Context) ([]ChainInfo, error) {
+	var resp struct {
+		Chains []ChainInfo `json:"chains"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/info/v1.0/chains", nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Chains, nil
+}
+
+// Tokens lists the tokens the relayer quotes; chains it quotes every token
+// on are absent.
+func (c *Client) Tokens(ctx context.Context) ([]TokenInfo, error) {
+	var resp struct {
+		Tokens []TokenInfo `json:"tokens"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/info/v1.0/tokens", nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Tokens, nil
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body []byte, out any) error {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.AdminKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.AdminKey)
+	} else if c.SessionToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.SessionToken)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var apiErr struct {
+			Error string `json:"error"`
+			Code  string `json:"code"`
+		}
+		raw, _ := io.ReadAll(resp.Body)
+		if json.Unmarshal(raw, &apiErr) != nil || apiErr.Error == "" {
+			apiErr.Error = strings.TrimSpace(string(raw))
+		}
+		retryAfter, _ := strconv.Atoi(resp.Header.Get("Retry-After"))
+		return &APIError{
+			StatusCode: resp.StatusCode,
+			Message:    apiErr.Error,
+			Code:       apiErr.Code,
+			RetryAfter: time.Duration(retryAfter) * time.Second,
+		}
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,456 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"relayer/pkg/reconnect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coder/websocket"
+)
+
+// Event names of the relayer WS protocol, see internal/manager/types.go.
+const (
+	orderEvent    = "BROADC"
+	secretEvent   = "SECRET"
+	txHashEvent   = "TXHASH"
+	cancelEvent   = "CANCEL"
+	expireEvent   = "EXPIRED"
+	statusEvent   = "STATUS"
+	intentEvent   = "FILL_INTENT"
+	reservedEvent = "FILL_RESERVED"
+	adviceEvent   = "CANCEL_ADVICE"
+
+	escrowsVerifiedEvent = "ESCROWS_VERIFIED"
+	finalityWaitEvent    = "FINALITY_WAIT"
+	fillReadyEvent       = "FILL_READY"
+	secretReleasedEvent  = "SECRET_RELEASED"
+	withdrawnEvent       = "WITHDRAWN"
+	errorEvent           = "ERROR"
+	sessionEvent         = "SESSION"
+	protocolEvent        = "PROTOCOL"
+	getOrderEvent        = "GET_ORDER"
+	orderDetailEvent     = "ORDER_DETAIL"
+	seqPrefix            = "SEQ"
+)
+
+// Protocol versions the stream speaks, newest first, as WebSocket subprotocols.
+// Relayers predating version negotiation speak version 1.
+var subprotocols = []string{"fission.v2", "fission.v1"}
+
+// ErrNotConnected is returned when sending on a Stream without a live connection.
+var ErrNotConnected = errors.New("relayer stream is not connected")
+
+// Handlers are the callbacks invoked by a Stream. Nil handlers are skipped.
+type Handlers struct {
+	// OnOrder is called for every broadcast order.
+	OnOrder func(order *Order)
+	// OnSecret is called when the relayer releases a secret for an order.
+	OnSecret func(orderHash, secret string)
+	// OnIndexedSecret is called after OnSecret with the index of the secret's
+	// hashlock, when the relayer speaks protocol version 2 and knows it.
+	OnIndexedSecret func(orderHash string, hashIdx int, secret string)
+	// OnExpired is called when the relayer expires an order nobody filled.
+	OnExpired func(orderHash string)
+	// OnFillReserved is called when a resolver reserves the segment of an
+	// order filled with the secret at hashIdx, until the given time.
+	OnFillReserved func(orderHash string, hashIdx int, resolverID string, until time.Time)
+	// OnCancelAdvice is called when two resolvers deployed dst escrows for the
+	// same secret: the escrow deployed later, dstEscrow, should be cancelled.
+	OnCancelAdvice func(orderHash string, hashIdx int, dstEscrow, canonicalEscrow string)
+	// OnStatus is called when an order the stream is subscribed to changes
+	// status; the relayer only sends it to order and maker subscriptions.
+	OnStatus func(orderHash, status string)
+	// OnEscrowsVerified is called when a fill's escrows pass verification.
+	OnEscrowsVerified func(orderHash string, hashIdx int, srcEscrow, dstEscrow string)
+	// OnFinalityWait is called with the time a verified fill's secret may be
+	// shared, once both escrows reach finality.
+	OnFinalityWait func(orderHash string, hashIdx int, until time.Time)
+	// OnFillReady is called when a verified fill may receive its secret, with
+	// the evidence the relayer verified it on.
+	OnFillReady func(orderHash string, report *VerificationReport)
+	// OnSecretReleased is called when the maker's secret is shared with resolvers.
+	OnSecretReleased func(orderHash string)
+	// OnWithdrawn is called when the src or dst escrow of an order pays out.
+	OnWithdrawn func(orderHash, side, txHash string)
+	// OnOrderDetail is called with the answer to GetOrder.
+	OnOrderDetail func(detail *OrderDetail)
+	// OnRejected is called when the relayer rejects a message sent on the stream.
+	OnRejected func(reason string)
+	// OnUnknown receives any frame the client does not understand.
+	OnUnknown func(raw string)
+	// OnError reports decode failures and connection drops; the stream keeps running.
+	OnError func(err error)
+}
+
+// Stream is a resolver connection to the relayer WebSocket server. It reconnects
+// with exponential backoff and resumes from the last sequence number it saw, so
+// broadcasts retained by the relayer are replayed instead of lost. Authenticated
+// streams also resume their relayer session, keeping subscriptions and rate
+// limits, when they reconnect within its grace window.
+type Stream struct {
+	url      string
+	handlers Handlers
+
+	reconnect.Backoff
+
+	// APIKey authenticates the resolver when the relayer has a resolver registry.
+	APIKey string
+
+	// Orders and Makers restrict the stream to the updates of these order
+	// hashes and maker addresses. Resolvers leave both empty to get everything.
+	Orders []string
+	Makers []string
+	// MakerSession is the maker's session token, required to follow orders
+	// by relayers with PRIVATE_ORDER_STATUS.
+	MakerSession string
+
+	mu       sync.Mutex
+	conn     *websocket.Conn
+	protocol int
+	lastSeq  uint64
+	session  string
+}
+
+// NewStream creates a stream for the relayer WS endpoint (e.g. "ws://localhost:8081/").
+func NewStream(wsURL string, handlers Handlers) *Stream {
+	return &Stream{
+		url:      wsURL,
+		handlers: handlers,
+		Backoff:  reconnect.DefaultBackoff(),
+	}
+}
+
+// Subscribe connects and dispatches events until ctx is cancelled, reconnecting
+// on any connection failure. It only returns ctx.Err().
+func (s *Stream) Subscribe(ctx context.Context) error {
+	return s.Backoff.Run(ctx, s.runOnce, s.reportError)
+}
+
+// LastSeq returns the sequence number of the last event received.
+func (s *Stream) LastSeq() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.lastSeq
+}
+
+// Protocol returns the protocol version negotiated with the relayer on the
+// current or last connection, 0 before the first.
+func (s *Stream) Protocol() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.protocol
+}
+
+// SubmitTxHashes reports the escrow deployment transactions of a fill so the
+// relayer can verify them and release the secret.
+func (s *Stream) SubmitTxHashes(ctx context.Context, orderHash, srcTxHash, dstTxHash string) error {
+	return s.send(ctx, strings.Join([]string{txHashEvent, orderHash, srcTxHash, dstTxHash}, " "))
+}
+
+// SubmitCancel reports a transaction that cancelled one of the order's escrows.
+func (s *Stream) SubmitCancel(ctx context.Context, orderHash, txHash string) error {
+	return s.send(ctx, strings.Join([]string{cancelEvent, orderHash, txHash}, " "))
+}
+
+// DeclareFillIntent reserves the segment of an order filled with the secret at
+// hashIdx before creating its escrows. The relayer answers with FILL_RESERVED,
+// or rejects the intent when another resolver holds the segment.
+func (s *Stream) DeclareFillIntent(ctx context.Context, orderHash string, hashIdx int) error {
+	return s.send(ctx, strings.Join([]string{intentEvent, orderHash, strconv.Itoa(hashIdx)}, " "))
+}
+
+// SubmitWithdraw proves a transaction that withdrew from the order's escrows,
+// revealing its secret, so the relayer can tell the maker and complete the
+// order once all its fills are settled.
+func (s *Stream) SubmitWithdraw(ctx context.Context, orderHash, txHash string) error {
+	return s.send(ctx, strings.Join([]string{withdrawnEvent, orderHash, txHash}, " "))
+}
+
+// GetOrder asks the relayer for an order whose broadcast the stream missed.
+// The answer arrives at OnOrderDetail, or at OnRejected when the relayer does
+// not know the order.
+func (s *Stream) GetOrder(ctx context.Context, orderHash string) error {
+	return s.send(ctx, getOrderEvent+" "+orderHash)
+}
+
+func (s *Stream) send(ctx context.Context, msg string) error {
+	s.mu.Lock()
+	conn := s.conn
+	s.mu.Unlock()
+
+	if conn == nil {
+		return ErrNotConnected
+	}
+
+	return conn.Write(ctx, websocket.MessageText, []byte(msg))
+}
+
+func (s *Stream) runOnce(ctx context.Context) (bool, error) {
+	query := url.Values{"order": s.Orders, "maker": s.Makers}
+	if s.MakerSession != "" {
+		query.Set("session", s.MakerSession)
+	}
+	s.mu.Lock()
+	query.Set("since", strconv.FormatUint(s.lastSeq, 10))
+	if s.session != "" {
+		query.Set("resume", s.session)
+		// a token resumes once, a failed dial gets a fresh session
+		s.session = ""
+	}
+	s.mu.Unlock()
+	streamURL := s.url + "?" + query.Encode()
+
+	opts := &websocket.DialOptions{Subprotocols: subprotocols}
+	if s.APIKey != "" {
+		opts.HTTPHeader = http.Header{"Authorization": {"Bearer " + s.APIKey}}
+	}
+
+	conn, _, err := websocket.Dial(ctx, streamURL, opts)
+	if err != nil {
+		return false, fmt.Errorf("dialing relayer: %w", err)
+	}
+	defer conn.CloseNow()
+
+	protocol := 1
+	if conn.Subprotocol() == subprotocols[0] {
+		protocol = 2
+	}
+
+	s.mu.Lock()
+	s.conn = conn
+	s.protocol = protocol
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		s.conn = nil
+		s.mu.Unlock()
+	}()
+
+	for {
+		msgType, data, err := conn.Read(ctx)
+		if err != nil {
+			return true, fmt.Errorf("reading from relayer: %w", err)
+		}
+		if msgType != websocket.MessageText {
+			continue
+		}
+
+		s.dispatch(protocol, string(data))
+	}
+}
+
+func (s *Stream) dispatch(protocol int, raw string) {
+	// strip the sequence header: SEQ <N> <EVENT>
+	if rest, ok := strings.CutPrefix(raw, seqPrefix+" "); ok {
+		seqStr, event, _ := strings.Cut(rest, " ")
+		seq, err := strconv.ParseUint(seqStr, 10, 64)
+		if err != nil {
+			s.reportError(fmt.Errorf("invalid sequence header %q", seqStr))
+			return
+		}
+
+		s.mu.Lock()
+		if seq <= s.lastSeq {
+			s.mu.Unlock()
+			return
+		}
+		s.lastSeq = seq
+		s.mu.Unlock()
+
+		raw = event
+	}
+
+	op, payload, _ := strings.Cut(raw, " ")
+	switch op {
+	case orderEvent:
+		if protocol >= 2 {
+			var envelope struct {
+				Order json.RawMessage `json:"order"`
+			}
+			if err := json.Unmarshal([]byte(payload), &envelope); err != nil {
+				s.reportError(fmt.Errorf("decoding broadcast order: %w", err))
+				return
+			}
+			payload = string(envelope.Order)
+		}
+		order, err := decodeOrder([]byte(payload))
+		if err != nil {
+			s.reportError(fmt.Errorf("decoding broadcast order: %w", err))
+			return
+		}
+		if s.handlers.OnOrder != nil {
+			s.handlers.OnOrder(order)
+		}
+	case secretEvent:
+		if protocol >= 2 {
+			var secret struct {
+				OrderHash string `json:"orderHash"`
+				HashIdx   *int   `json:"idx"`
+				Secret    string `json:"secret"`
+			}
+			if err := json.Unmarshal([]byte(payload), &secret); err != nil || secret.OrderHash == "" || secret.Secret == "" {
+				s.reportError(fmt.Errorf("invalid secret event: %q", payload))
+				return
+			}
+			if s.handlers.OnSecret != nil {
+				s.handlers.OnSecret(secret.OrderHash, secret.Secret)
+			}
+			if s.handlers.OnIndexedSecret != nil && secret.HashIdx != nil {
+				s.handlers.OnIndexedSecret(secret.OrderHash, *secret.HashIdx, secret.Secret)
+			}
+			return
+		}
+		parts := strings.Fields(payload)
+		if len(parts) != 2 {
+			s.reportError(fmt.Errorf("invalid secret event: %q", payload))
+			return
+		}
+		if s.handlers.OnSecret != nil {
+			s.handlers.OnSecret(parts[0], parts[1])
+		}
+	case expireEvent:
+		if s.handlers.OnExpired != nil {
+			s.handlers.OnExpired(strings.TrimSpace(payload))
+		}
+	case reservedEvent:
+		parts := strings.Fields(payload)
+		idx, err := fillIndex(parts, 4)
+		if err != nil {
+			s.reportError(fmt.Errorf("invalid fill reserved event %q: %w", payload, err))
+			return
+		}
+		until, err := strconv.ParseInt(parts[3], 10, 64)
+		if err != nil {
+			s.reportError(fmt.Errorf("invalid fill reserved event %q: %w", payload, err))
+			return
+		}
+		if s.handlers.OnFillReserved != nil {
+			s.handlers.OnFillReserved(parts[0], idx, parts[2], time.Unix(until, 0))
+		}
+	case adviceEvent:
+		parts := strings.Fields(payload)
+		idx, err := fillIndex(parts, 4)
+		if err != nil {
+			s.reportError(fmt.Errorf("invalid cancel advice event %q: %w", payload, err))
+			return
+		}
+		if s.handlers.OnCancelAdvice != nil {
+			s.handlers.OnCancelAdvice(parts[0], idx, parts[2], parts[3])
+		}
+	case statusEvent:
+		parts := strings.Fields(payload)
+		if len(parts) != 2 {
+			s.reportError(fmt.Errorf("invalid status event: %q", payload))
+			return
+		}
+		if s.handlers.OnStatus != nil {
+			s.handlers.OnStatus(parts[0], parts[1])
+		}
+	case escrowsVerifiedEvent:
+		parts := strings.Fields(payload)
+		idx, err := fillIndex(parts, 4)
+		if err != nil {
+			s.reportError(fmt.Errorf("invalid escrows verified event %q: %w", payload, err))
+			return
+		}
+		if s.handlers.OnEscrowsVerified != nil {
+			s.handlers.OnEscrowsVerified(parts[0], idx, parts[2], parts[3])
+		}
+	case finalityWaitEvent:
+		parts := strings.Fields(payload)
+		idx, err := fillIndex(parts, 3)
+		if err != nil {
+			s.reportError(fmt.Errorf("invalid finality wait event %q: %w", payload, err))
+			return
+		}
+		until, err := strconv.ParseInt(parts[2], 10, 64)
+		if err != nil {
+			s.reportError(fmt.Errorf("invalid finality wait event %q: %w", payload, err))
+			return
+		}
+		if s.handlers.OnFinalityWait != nil {
+			s.handlers.OnFinalityWait(parts[0], idx, time.Unix(until, 0))
+		}
+	case fillReadyEvent:
+		orderHash, raw, ok := strings.Cut(payload, " ")
+		if !ok {
+			s.reportError(fmt.Errorf("invalid fill ready event: %q", payload))
+			return
+		}
+		report := &VerificationReport{}
+		if err := json.Unmarshal([]byte(raw), report); err != nil {
+			s.reportError(fmt.Errorf("decoding verification report: %w", err))
+			return
+		}
+		if s.handlers.OnFillReady != nil {
+			s.handlers.OnFillReady(orderHash, report)
+		}
+	case secretReleasedEvent:
+		if s.handlers.OnSecretReleased != nil {
+			s.handlers.OnSecretReleased(strings.TrimSpace(payload))
+		}
+	case withdrawnEvent:
+		parts := strings.Fields(payload)
+		if len(parts) != 3 {
+			s.reportError(fmt.Errorf("invalid withdrawn event: %q", payload))
+			return
+		}
+		if s.handlers.OnWithdrawn != nil {
+			s.handlers.OnWithdrawn(parts[0], parts[1], parts[2])
+		}
+	case orderDetailEvent:
+		_, raw, ok := strings.Cut(payload, " ")
+		if !ok {
+			s.reportError(fmt.Errorf("invalid order detail event: %q", payload))
+			return
+		}
+		detail := &OrderDetail{}
+		if err := json.Unmarshal([]byte(raw), detail); err != nil {
+			s.reportError(fmt.Errorf("decoding order detail: %w", err))
+			return
+		}
+		if s.handlers.OnOrderDetail != nil {
+			s.handlers.OnOrderDetail(detail)
+		}
+	case protocolEvent:
+		// the negotiated version, already known from the handshake
+	case sessionEvent:
+		s.mu.Lock()
+		s.session = strings.TrimSpace(payload)
+		s.mu.Unlock()
+	case errorEvent:
+		if s.handlers.OnRejected != nil {
+			s.handlers.OnRejected(payload)
+		}
+	default:
+		if s.handlers.OnUnknown != nil {
+			s.handlers.OnUnknown(raw)
+		}
+	}
+}
+
+// fillIndex checks a fill event has n fields and parses its hash index, the
+// second field.
+func fillIndex(parts []string, n int) (int, error) {
+	if len(parts) != n {
+		return 0, fmt.Errorf("expected %d fields, got %d", n, len(parts))
+	}
+	return strconv.Atoi(parts[1])
+}
+
+func (s *Stream) reportError(err error) {
+	if s.handlers.OnError != nil {
+		s.handlers.OnError(err)
+	}
+}
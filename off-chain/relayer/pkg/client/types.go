@@ -0,0 +1,562 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// The wire types of the relayer API, as the SDK's own so resolver code
+// neither mirrors them nor depends on the relayer's internals.
+
+// ChainID is a chain's numeric id, Sui's being SuiChainID. It is encoded as
+// a JSON number and decoded from a number, a decimal string or a CAIP-2 id
+// such as "eip155:1" or "sui:mainnet".
+type ChainID uint64
+
+// SuiChainID is the id the relayer gives Sui.
+const SuiChainID ChainID = 101
+
+// String returns the decimal chain id.
+func (c ChainID) String() string {
+	return strconv.FormatUint(uint64(c), 10)
+}
+
+func (c ChainID) MarshalJSON() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+func (c *ChainID) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	if strings.HasPrefix(s, `"`) {
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+	}
+
+	namespace, reference, namespaced := strings.Cut(s, ":")
+	switch {
+	case namespaced && namespace == "sui":
+		*c = SuiChainID
+		return nil
+	case namespaced && namespace != "eip155":
+		return fmt.Errorf("invalid chain id %s: unknown namespace %q", data, namespace)
+	case namespaced:
+		s = reference
+	}
+	v, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid chain id %s: %w", data, err)
+	}
+	*c = ChainID(v)
+	return nil
+}
+
+// QuoteRequestParams asks for a quote. DstReceiver is the maker's address on
+// the destination chain when the order's receiver cannot hold it, e.g. a
+// Sui address for an EVM maker; IntegratorFee, in bps of the making amount,
+// is paid to FeeReceiver on the source chain.
+type QuoteRequestParams struct {
+	SrcChain        string `schema:"srcChain"`
+	DstChain        string `schema:"dstChain"`
+	SrcTokenAddress string `schema:"srcTokenAddress"`
+	DstTokenAddress string `schema:"dstTokenAddress"`
+	Amount          string `schema:"amount"`
+	WalletAddress   string `schema:"walletAddress"`
+	DstReceiver     string `schema:"dstReceiver,omitempty"`
+	IntegratorFee   uint64 `schema:"integratorFee,omitempty"`
+	FeeReceiver     string `schema:"feeReceiver,omitempty"`
+}
+
+// Quote is a quote to build an order against, amounts net of the protocol
+// and integrator fees. Orders against it are rejected after ExpiresAt, and
+// their secret hashes must use Hashlock, keccak256 or sha256.
+type Quote struct {
+	QuoteID           uuid.UUID                 `json:"quoteId"`
+	SrcTokenAmount    string                    `json:"srcTokenAmount"`
+	DstTokenAmount    string                    `json:"dstTokenAmount"`
+	Presets           map[PresetEnum]PresetData `json:"presets"`
+	SrcEscrowFactory  string                    `json:"srcEscrowFactory"`
+	DstEscrowFactory  string                    `json:"dstEscrowFactory"`
+	RecommendedPreset PresetEnum                `json:"recommendedPreset"`
+	Prices            Cost                      `json:"prices"`
+	Volume            Cost                      `json:"volume"`
+	Whitelist         []string                  `json:"whitelist"`
+	TakerAddresses    []string                  `json:"takerAddresses,omitempty"`
+	TimeLocks         TimeLocksRaw              `json:"timeLocks"`
+	SrcSafetyDeposit  string                    `json:"srcSafetyDeposit"`
+	DstSafetyDeposit  string                    `json:"dstSafetyDeposit"`
+	AutoK             float64                   `json:"autoK"`
+	ProtocolFeeBps    uint64                    `json:"protocolFeeBps,omitempty"`
+	IntegratorFeeBps  uint64                    `json:"integratorFeeBps,omitempty"`
+	FeeReceiver       string                    `json:"feeReceiver,omitempty"`
+	ExpiresAt         int64                     `json:"expiresAt,omitempty"`
+	Hashlock          string                    `json:"hashlock,omitempty"`
+}
+
+// PresetEnum names an auction preset of a quote.
+type PresetEnum string
+
+const (
+	PresetFast   PresetEnum = "fast"
+	PresetMedium PresetEnum = "medium"
+	PresetSlow   PresetEnum = "slow"
+	PresetCustom PresetEnum = "custom"
+)
+
+// PresetData is the auction of one preset. ExclusiveResolver is nil when any
+// resolver may fill.
+type PresetData struct {
+	AuctionDuration    int64          `json:"auctionDuration"`
+	StartAuctionIn     int64          `json:"startAuctionIn"`
+	InitialRateBump    float64        `json:"initialRateBump"`
+	AuctionStartAmount string         `json:"auctionStartAmount"`
+	StartAmount        string         `json:"startAmount"`
+	AuctionEndAmount   string         `json:"auctionEndAmount"`
+	CostInDstToken     string         `json:"costInDstToken"`
+	Points             []AuctionPoint `json:"points"`
+	AllowPartialFills  bool           `json:"allowPartialFills"`
+	AllowMultipleFills bool           `json:"allowMultipleFills"`
+	GasCost            struct {
+		GasBumpEstimate  float64 `json:"gasBumpEstimate"`
+		GasPriceEstimate string  `json:"gasPriceEstimate"`
+	} `json:"gasCost"`
+	ExclusiveResolver *string `json:"exclusiveResolver"`
+	SecretsCount      int     `json:"secretsCount"`
+}
+
+// AuctionPoint is a point of an auction's price curve.
+type AuctionPoint struct {
+	Delay       int64   `json:"delay"`
+	Coefficient float64 `json:"coefficient"`
+}
+
+// Cost is the USD price of a quote's tokens.
+type Cost struct {
+	USD struct {
+		SrcToken string `json:"srcToken"`
+		DstToken string `json:"dstToken"`
+	} `json:"usd"`
+}
+
+// TimeLocksRaw are the escrow timelocks of a quote, in seconds from the
+// escrows' deployment.
+type TimeLocksRaw struct {
+	SrcWithdrawal         int64 `json:"srcWithdrawal"`
+	SrcPublicWithdrawal   int64 `json:"srcPublicWithdrawal"`
+	SrcCancellation       int64 `json:"srcCancellation"`
+	SrcPublicCancellation int64 `json:"srcPublicCancellation"`
+	DstWithdrawal         int64 `json:"dstWithdrawal"`
+	DstPublicWithdrawal   int64 `json:"dstPublicWithdrawal"`
+	DstCancellation       int64 `json:"dstCancellation"`
+}
+
+// Order is a signed cross-chain order. The relayer sets SecretsID when it
+// generated the secrets, Permit2 when resolvers must fill through Permit2,
+// and MinFillAmount as the smallest making amount a partial fill but the
+// last may take.
+type Order struct {
+	SrcChainID       ChainID    `json:"srcChainId"`
+	LimitOrder       LimitOrder `json:"order"`
+	RelayerSignature string     `json:"relayerSignature,omitempty"`
+	Signature        string     `json:"signature"`
+	QuoteID          uuid.UUID  `json:"quoteId"`
+	Extension        string     `json:"extension"`
+	SecretHashes     []string   `json:"secretHashes,omitempty"`
+	MakerPubKey      string     `json:"makerPubKey,omitempty"`
+	DstReceiver      string     `json:"dstReceiver,omitempty"`
+	Hashlock         string     `json:"hashlock,omitempty"`
+	SecretsID        string     `json:"secretsId,omitempty"`
+	Permit2          bool       `json:"permit2,omitempty"`
+	MinFillAmount    string     `json:"minFillAmount,omitempty"`
+}
+
+// LimitOrder is the 1inch limit order of an Order, amounts decimal.
+type LimitOrder struct {
+	Salt         string `json:"salt"`
+	Maker        string `json:"maker"`
+	Receiver     string `json:"receiver"`
+	MakerAsset   string `json:"makerAsset"`
+	TakerAsset   string `json:"takerAsset"`
+	MakingAmount string `json:"makingAmount"`
+	TakingAmount string `json:"takingAmount"`
+	MakerTraits  string `json:"makerTraits"`
+}
+
+// OrderStatus is the status of an order and its fills.
+type OrderStatus struct {
+	Status              OrderStatusMode `json:"status"`
+	Order               *LimitOrder     `json:"order"`
+	Extension           string          `json:"extension"`
+	Points              []AuctionPoint  `json:"points"`
+	CancelTx            *string         `json:"cancelTx"`
+	Fills               []Fill          `json:"fills"`
+	CreatedAt           string          `json:"createdAt"`
+	AuctionStartDate    int64           `json:"auctionStartDate"`
+	AuctionDuration     int64           `json:"auctionDuration"`
+	InitialRateBump     float64         `json:"initialRateBump"`
+	IsNativeCurrency    bool            `json:"isNativeCurrency"`
+	FromTokenToUsdPrice string          `json:"fromTokenToUsdPrice"`
+	ToTokenToUsdPrice   string          `json:"toTokenToUsdPrice"`
+}
+
+// OrderStatusMode is where an order is in its life.
+type OrderStatusMode string
+
+const (
+	OrderStatusPending   OrderStatusMode = "pending"
+	OrderStatusExecuted  OrderStatusMode = "executed"
+	OrderStatusExpired   OrderStatusMode = "expired"
+	OrderStatusCancelled OrderStatusMode = "cancelled"
+	OrderStatusRefunding OrderStatusMode = "refunding"
+	OrderStatusRefunded  OrderStatusMode = "refunded"
+)
+
+// Fill is one fill of an order and the events of its escrows. Status is
+// pending, executed, refunding or refunded.
+type Fill struct {
+	Status                   string            `json:"status"`
+	TxHash                   string            `json:"txHash"`
+	FilledMakerAmount        string            `json:"filledMakerAmount"`
+	FilledAuctionTakerAmount string            `json:"filledAuctionTakerAmount"`
+	EscrowEvents             []EscrowEventData `json:"escrowEvents"`
+}
+
+// EscrowEventData is an event of a fill's escrow. Side is src or dst, and
+// Action src_escrow_created, dst_escrow_created, withdrawn, funds_rescued or
+// escrow_cancelled.
+type EscrowEventData struct {
+	TransactionHash string `json:"transactionHash"`
+	Escrow          string `json:"escrow"`
+	Side            string `json:"side"`
+	Action          string `json:"action"`
+	BlockTimestamp  int64  `json:"blockTimestamp"`
+}
+
+// ReadyToAcceptSecretFills are the fills of an order whose escrows were
+// verified, ready for the maker to reveal their secret.
+type ReadyToAcceptSecretFills struct {
+	Fills []ReadyToAcceptSecretFill `json:"fills"`
+}
+
+// ReadyToAcceptSecretFill is a verified fill and, when the relayer verified
+// it, the evidence it was verified on.
+type ReadyToAcceptSecretFill struct {
+	Idx                   int                 `json:"idx"`
+	SrcEscrowDeployTxHash string              `json:"srcEscrowDeployTxHash"`
+	DstEscrowDeployTxHash string              `json:"dstEscrowDeployTxHash"`
+	Verification          *VerificationReport `json:"verification,omitempty"`
+}
+
+// VerificationReport is the evidence a fill was verified on: the checks
+// performed, in order, and the tolerance each amount check applied.
+// VerifiedAt and ReadyAt are unix seconds.
+type VerificationReport struct {
+	HashIdx    int               `json:"idx"`
+	Hashlock   string            `json:"hashlock"`
+	Src        EscrowEvidence    `json:"src"`
+	Dst        EscrowEvidence    `json:"dst"`
+	Checks     []string          `json:"checks"`
+	Policies   map[string]string `json:"policies,omitempty"`
+	VerifiedAt int64             `json:"verifiedAt"`
+	ReadyAt    int64             `json:"readyAt"`
+}
+
+// EscrowEvidence is what the relayer read from chain about one escrow of a
+// fill, DeployedAt in unix milliseconds.
+type EscrowEvidence struct {
+	ChainID    string `json:"chainId"`
+	Escrow     string `json:"escrow"`
+	DeployTx   string `json:"deployTx"`
+	Taker      string `json:"taker"`
+	Amount     string `json:"amount"`
+	DeployedAt int64  `json:"deployedAt"`
+}
+
+// OrderDetail answers a stream's GetOrder with what filling the order takes.
+type OrderDetail struct {
+	OrderHash          string      `json:"orderHash"`
+	Order              *Order      `json:"order"`
+	DstChainID         string      `json:"dstChainId"`
+	Status             OrderStatus `json:"status"`
+	FilledMakingAmount string      `json:"filledMakingAmount"`
+	Archived           bool        `json:"archived"`
+}
+
+// OrderEscrows are the escrows of an order's verified fills.
+type OrderEscrows struct {
+	OrderHash string        `json:"orderHash"`
+	Fills     []FillEscrows `json:"fills"`
+}
+
+// FillEscrows are both escrows of a verified fill.
+type FillEscrows struct {
+	Idx int    `json:"idx"`
+	Src Escrow `json:"src"`
+	Dst Escrow `json:"dst"`
+}
+
+// Escrow is one escrow of a verified fill: an address on EVM chains, with
+// the immutables its withdraw and cancel functions take, and an object id on
+// Sui. DeployedAt is in unix milliseconds.
+type Escrow struct {
+	ChainID    string            `json:"chainId"`
+	Escrow     string            `json:"escrow"`
+	DeployTx   string            `json:"deployTx"`
+	Immutables *EscrowImmutables `json:"immutables,omitempty"`
+	DeployedAt int64             `json:"deployedAt"`
+}
+
+// EscrowImmutables are the immutables of an EVM escrow, addresses hex and
+// amounts decimal.
+type EscrowImmutables struct {
+	OrderHash     string `json:"orderHash"`
+	Hashlock      string `json:"hashlock"`
+	Maker         string `json:"maker"`
+	Taker         string `json:"taker"`
+	Token         string `json:"token"`
+	Amount        string `json:"amount"`
+	SafetyDeposit string `json:"safetyDeposit"`
+	Timelocks     string `json:"timelocks"`
+}
+
+// SecretsRequest asks the relayer to generate the secrets of an order built
+// from QuoteID. Policy is "finality", the default, or "manual".
+type SecretsRequest struct {
+	QuoteID uuid.UUID `json:"quoteId"`
+	Policy  string    `json:"policy,omitempty"`
+}
+
+// SecretSet describes generated secrets. ExportToken is only returned on
+// generation and Secrets only on export.
+type SecretSet struct {
+	SecretsID    string   `json:"secretsId"`
+	Algorithm    string   `json:"algorithm"`
+	Policy       string   `json:"policy"`
+	Hashlock     string   `json:"hashlock"`
+	SecretHashes []string `json:"secretHashes"`
+	OrderHash    string   `json:"orderHash,omitempty"`
+	ExportToken  string   `json:"exportToken,omitempty"`
+	Secrets      []string `json:"secrets,omitempty"`
+}
+
+// SessionChallenge is a message for a maker to sign in with, ExpiresAt in
+// unix seconds.
+type SessionChallenge struct {
+	Nonce     string `json:"nonce"`
+	Message   string `json:"message"`
+	ExpiresAt int64  `json:"expiresAt"`
+}
+
+// Session is a maker session token, sent as a bearer token.
+type Session struct {
+	Token     string `json:"token"`
+	ExpiresAt int64  `json:"expiresAt"`
+}
+
+// ChainInfo describes a chain the relayer serves. VM is "evm" or "move", and
+// Destinations the decimal ids of the chains its routes lead to.
+type ChainInfo struct {
+	ChainID         ChainID  `json:"chainId"`
+	CAIP2           string   `json:"caip2"`
+	VM              string   `json:"vm"`
+	EscrowFactories []string `json:"escrowFactories,omitempty"`
+	PackageIDs      []string `json:"packageIds,omitempty"`
+	Destinations    []string `json:"destinations"`
+}
+
+// TokenInfo is a token the relayer quotes on a chain.
+type TokenInfo struct {
+	Chain    string `json:"chain"`
+	Address  string `json:"address"`
+	Symbol   string `json:"symbol,omitempty"`
+	Decimals uint8  `json:"decimals"`
+}
+
+// AdminOrder is an operator view of an order tracked by the relayer.
+type AdminOrder struct {
+	OrderHash          string                    `json:"orderHash"`
+	SrcChainID         string                    `json:"srcChainId"`
+	OrderType          string                    `json:"orderType"`
+	Status             OrderStatusMode           `json:"status"`
+	QuoteID            string                    `json:"quoteId"`
+	SubmittedAt        time.Time                 `json:"submittedAt"`
+	FilledMakingAmount string                    `json:"filledMakingAmount"`
+	CancelTx           *string                   `json:"cancelTx,omitempty"`
+	Fills              []ReadyToAcceptSecretFill `json:"fills"`
+	Escrows            map[string]string         `json:"escrows,omitempty"`
+	DstReceiver        string                    `json:"dstReceiver,omitempty"`
+	Hashlock           string                    `json:"hashlock,omitempty"`
+	Archived           bool                      `json:"archived"`
+	LimitOrder         *LimitOrder               `json:"order,omitempty"`
+	Extension          string                    `json:"extension,omitempty"`
+	SecretHashes       []string                  `json:"secretHashes,omitempty"`
+	Upstream           *UpstreamSubmission       `json:"upstream,omitempty"`
+}
+
+// UpstreamSubmission is the answer of the official 1inch relayer to an order
+// forwarded to it. Status is 0 when it could not be reached.
+type UpstreamSubmission struct {
+	Status      int       `json:"status"`
+	Body        string    `json:"body,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	SubmittedAt time.Time `json:"submittedAt"`
+}
+
+// OrderSummary is a stored order as found by an order search.
+type OrderSummary struct {
+	OrderHash    string          `json:"orderHash"`
+	Status       OrderStatusMode `json:"status"`
+	SrcChainID   string          `json:"srcChainId"`
+	DstChainID   string          `json:"dstChainId"`
+	Maker        string          `json:"maker"`
+	MakerAsset   string          `json:"makerAsset"`
+	TakerAsset   string          `json:"takerAsset"`
+	MakingAmount string          `json:"makingAmount"`
+	TakingAmount string          `json:"takingAmount"`
+	QuoteID      string          `json:"quoteId"`
+	SubmittedAt  time.Time       `json:"submittedAt"`
+	UpdatedAt    time.Time       `json:"updatedAt"`
+}
+
+// OrderSearchResult is a page of an order search, NextCursor empty on the
+// last one.
+type OrderSearchResult struct {
+	Orders     []OrderSummary `json:"orders"`
+	NextCursor string         `json:"nextCursor,omitempty"`
+}
+
+// AdminQuote is an operator view of a cached quote.
+type AdminQuote struct {
+	QuoteID      string              `json:"quoteId"`
+	FeeBps       uint64              `json:"feeBps"`
+	QuoteRequest *QuoteRequestParams `json:"quoteRequest"`
+	Quote        *Quote              `json:"quote"`
+	ParentID     string              `json:"parentId,omitempty"`
+	LegIndex     int                 `json:"legIndex,omitempty"`
+	OrderHash    string              `json:"orderHash,omitempty"`
+}
+
+// ChainHealth is the connectivity of one chain endpoint. Head is the latest
+// block number for EVM chains and the clock in milliseconds for Sui.
+type ChainHealth struct {
+	Chain   string `json:"chain"`
+	OK      bool   `json:"ok"`
+	Head    uint64 `json:"head,omitempty"`
+	Lag     string `json:"lag,omitempty"`
+	Behind  bool   `json:"behind,omitempty"`
+	Latency string `json:"latency"`
+	Error   string `json:"error,omitempty"`
+}
+
+// QuarantinedOrder is an order held back from broadcast for an admin's
+// approval, its price deviating from the oracle's by DeviationBps.
+type QuarantinedOrder struct {
+	OrderHash     string    `json:"orderHash"`
+	SrcChainID    string    `json:"srcChainId"`
+	DstChainID    string    `json:"dstChainId"`
+	QuoteID       string    `json:"quoteId"`
+	Maker         string    `json:"maker"`
+	MakingAmount  string    `json:"makingAmount"`
+	TakingAmount  string    `json:"takingAmount"`
+	DeviationBps  int64     `json:"deviationBps"`
+	QuarantinedAt time.Time `json:"quarantinedAt"`
+}
+
+// ResolverStats is the liveness of an authenticated resolver, Score from 0
+// to 1.
+type ResolverStats struct {
+	ID             string     `json:"id"`
+	Connected      bool       `json:"connected"`
+	Connections    int        `json:"connections"`
+	ConnectedSince *time.Time `json:"connectedSince,omitempty"`
+	Uptime         float64    `json:"uptime"`
+	PingRTT        string     `json:"pingRtt,omitempty"`
+	Pings          int        `json:"pings"`
+	PingFailures   int        `json:"pingFailures"`
+	LastPing       *time.Time `json:"lastPing,omitempty"`
+	Score          float64    `json:"score"`
+}
+
+// SafetyDepositQuote is the safety deposit a chain's quotes currently get,
+// in the chain's native base units.
+type SafetyDepositQuote struct {
+	Chain         string `json:"chain"`
+	GasUnits      uint64 `json:"gasUnits"`
+	MultiplierBps uint64 `json:"multiplierBps"`
+	Floor         string `json:"floor,omitempty"`
+	GasPrice      string `json:"gasPrice,omitempty"`
+	Deposit       string `json:"deposit,omitempty"`
+	Error         string `json:"error,omitempty"`
+}
+
+// UpstreamKeyUsage is the use of one 1inch API key, by label, since the
+// relayer started; CoolingUntil is set while it is left out after a 429.
+type UpstreamKeyUsage struct {
+	Key          string     `json:"key"`
+	Requests     int64      `json:"requests"`
+	Throttled    int64      `json:"throttled"`
+	CoolingUntil *time.Time `json:"coolingUntil,omitempty"`
+}
+
+// Config is the relayer's runtime config as served, see CONFIG_FILE in the
+// relayer's README.
+type Config map[string]any
+
+// reverifyRequest asks the relayer to verify a fill again.
+type reverifyRequest struct {
+	SrcTxHash string `json:"srcTxHash"`
+	DstTxHash string `json:"dstTxHash"`
+}
+
+// APIError is returned for any non-2xx response from the relayer REST API.
+type APIError struct {
+	StatusCode int
+	Message    string
+	// Code is the machine-readable error code, when the relayer sets one
+	Code string
+	// RetryAfter is how long to back off when the relayer is overloaded (429)
+	RetryAfter time.Duration
+}
+
+// ErrCodeQuoteExpired is returned when an order references an expired quote.
+const ErrCodeQuoteExpired = "QUOTE_EXPIRED"
+
+// IsQuoteExpired reports whether err is the relayer rejecting an order because
+// its quote expired; the caller should request a new quote.
+func IsQuoteExpired(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.Code == ErrCodeQuoteExpired
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("relayer returned %d: %s", e.StatusCode, e.Message)
+}
+
+func decodeOrder(data []byte) (*Order, error) {
+	var order Order
+	if err := json.Unmarshal(data, &order); err != nil {
+		return nil, err
+	}
+	if order.SrcChainID == 0 {
+		return nil, errors.New("order has no srcChainId")
+	}
+
+	return &order, nil
+}
+
+func encodeOrder(order Order) ([]byte, error) {
+	if order.SrcChainID == 0 {
+		return nil, errors.New("order has no srcChainId")
+	}
+
+	return json.Marshal(order)
+}
@@ -0,0 +1,141 @@
+package client
+
+import (
+	"encoding/json"
+	"reflect"
+	"relayer/internal/common"
+	"strings"
+	"testing"
+)
+
+// TestWireTypes checks that the SDK's types encode to the same JSON as the
+// relayer's: the same fields, by name and kind, down to nested types.
+func TestWireTypes(t *testing.T) {
+	tests := []struct {
+		sdk, relayer any
+	}{
+		{QuoteRequestParams{}, common.QuoteRequestParams{}},
+		{Quote{}, common.Quote{}},
+		{Order{}, common.Order{}},
+		{OrderStatus{}, common.OrderStatus{}},
+		{ReadyToAcceptSecretFills{}, common.ReadyToAcceptSecretFills{}},
+		{OrderDetail{}, common.OrderDetail{}},
+		{OrderEscrows{}, common.OrderEscrows{}},
+		{SecretsRequest{}, common.SecretsRequest{}},
+		{SecretSet{}, common.SecretSet{}},
+		{Session{}, common.Session{}},
+		{ChainInfo{}, common.ChainInfo{}},
+		{TokenInfo{}, common.TokenInfo{}},
+		{AdminOrder{}, common.AdminOrder{}},
+		{OrderSearchResult{}, common.OrderSearchResult{}},
+		{AdminQuote{}, common.AdminQuote{}},
+		{ChainHealth{}, common.ChainHealth{}},
+		{QuarantinedOrder{}, common.QuarantinedOrder{}},
+		{ResolverStats{}, common.ResolverStats{}},
+		{SafetyDepositQuote{}, common.SafetyDepositQuote{}},
+		{UpstreamKeyUsage{}, common.UpstreamKeyUsage{}},
+		{reverifyRequest{}, common.ReverifyRequest{}},
+	}
+	for _, tt := range tests {
+		sdk, relayer := reflect.TypeOf(tt.sdk), reflect.TypeOf(tt.relayer)
+		t.Run(sdk.Name(), func(t *testing.T) {
+			compareWire(t, sdk.Name(), sdk, relayer)
+		})
+	}
+}
+
+func compareWire(t *testing.T, path string, sdk, relayer reflect.Type) {
+	t.Helper()
+	if sdk.Kind() != relayer.Kind() {
+		t.Errorf("%s: %s, relayer has %s", path, sdk.Kind(), relayer.Kind())
+		return
+	}
+	switch sdk.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Array:
+		compareWire(t, path+"[]", sdk.Elem(), relayer.Elem())
+	case reflect.Map:
+		compareWire(t, path+"{}", sdk.Key(), relayer.Key())
+		compareWire(t, path+"{}", sdk.Elem(), relayer.Elem())
+	case reflect.Struct:
+		sdkFields, relayerFields := wireFields(sdk), wireFields(relayer)
+		if len(sdkFields) == 0 && sdk != relayer {
+			t.Errorf("%s: %s, relayer has %s", path, sdk, relayer)
+		}
+		for name, field := range relayerFields {
+			other, ok := sdkFields[name]
+			if !ok {
+				t.Errorf("%s.%s: missing", path, name)
+				continue
+			}
+			compareWire(t, path+"."+name, other.Type, field.Type)
+		}
+		for name := range sdkFields {
+			if _, ok := relayerFields[name]; !ok {
+				t.Errorf("%s.%s: not sent by the relayer", path, name)
+			}
+		}
+	}
+}
+
+// wireFields are the exported fields of a struct by their JSON key, and by
+// their query parameter for QuoteRequestParams.
+func wireFields(typ reflect.Type) map[string]reflect.StructField {
+	fields := map[string]reflect.StructField{}
+	for _, field := range reflect.VisibleFields(typ) {
+		if !field.IsExported() || field.Anonymous {
+			continue
+		}
+		name := field.Name
+		if tag, ok := field.Tag.Lookup("json"); ok {
+			name, _, _ = strings.Cut(tag, ",")
+		}
+		if name == "-" {
+			continue
+		}
+		fields[name] = field
+	}
+	return fields
+}
+
+func TestChainIDUnmarshal(t *testing.T) {
+	tests := []struct {
+		json    string
+		want    ChainID
+		wantErr bool
+	}{
+		{json: `1`, want: 1},
+		{json: `"8453"`, want: 8453},
+		{json: `"eip155:42161"`, want: 42161},
+		{json: `"sui:mainnet"`, want: SuiChainID},
+		{json: `"solana:mainnet"`, wantErr: true},
+		{json: `"eth"`, wantErr: true},
+		{json: `-1`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.json, func(t *testing.T) {
+			var got ChainID
+			err := json.Unmarshal([]byte(tt.json), &got)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("got %d, want an error", got)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Fatalf("got %d, %v, want %d", got, err, tt.want)
+			}
+			if want := common.ChainID(tt.want); string(mustMarshal(t, got)) != string(mustMarshal(t, want)) {
+				t.Fatalf("encodes to %s, relayer to %s", mustMarshal(t, got), mustMarshal(t, want))
+			}
+		})
+	}
+}
+
+func mustMarshal(t *testing.T, v any) []byte {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
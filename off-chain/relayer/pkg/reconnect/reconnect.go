@@ -0,0 +1,52 @@
+// Package reconnect runs long-lived connections, such as websocket
+// subscriptions, again after every failure with exponential backoff.
+package reconnect
+
+import (
+	"context"
+	"time"
+)
+
+// Backoff bounds the delay between reconnection attempts: it starts at
+// MinBackoff, doubles after every attempt that failed before connecting up
+// to MaxBackoff, and starts over once an attempt connected.
+type Backoff struct {
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+// DefaultBackoff retries after half a second, backing off to 30 seconds.
+func DefaultBackoff() Backoff {
+	return Backoff{MinBackoff: 500 * time.Millisecond, MaxBackoff: 30 * time.Second}
+}
+
+// Run calls connect until ctx is cancelled, waiting out the backoff between
+// calls. connect reports whether it got as far as connecting, and the error
+// it ended with, which is passed to onError unless ctx was cancelled; onError
+// may be nil. It only returns ctx.Err().
+func (b Backoff) Run(ctx context.Context, connect func(ctx context.Context) (bool, error), onError func(err error)) error {
+	backoff := b.MinBackoff
+	for {
+		connected, err := connect(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil && onError != nil {
+			onError(err)
+		}
+		if connected {
+			backoff = b.MinBackoff
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > b.MaxBackoff {
+			backoff = b.MaxBackoff
+		}
+	}
+}
@@ -32,7 +32,12 @@ export class ResolverWebSocketClient {
   public connect(): void {
     try {
       console.log(`Attempting to connect to relayer at ${this.relayerUrl}`);
-      this.ws = new WebSocket(this.relayerUrl);
+      // relayers with a resolver registry require the resolver's API key
+      const apiKey = process.env.RELAYER_API_KEY;
+      this.ws = new WebSocket(
+        this.relayerUrl,
+        apiKey ? { headers: { Authorization: `Bearer ${apiKey}` } } : undefined
+      );
 
       this.ws.on("open", this.handleOpen.bind(this));
       this.ws.on("message", this.handleMessage.bind(this));